@@ -0,0 +1,321 @@
+// Command backup creates, checks, restores, and downloads backups of a controller's data directory.
+//
+// Usage:
+//
+//	backup create -data .data -out backup.tar.gz [-key-file key]
+//	backup verify -in backup.tar.gz [-key-file key]
+//	backup restore -data .data -in backup.tar.gz [-key-file key] [-keep backups]
+//	backup list -addr localhost:23557 [-insecure]
+//	backup download -addr localhost:23557 [-id backup-...tar.gz] -out backup.tar.gz [-insecure]
+//
+// Create and restore work on the data directory directly.
+// The controller must be stopped to restore a backup, and to create a backup that includes its bolt database.
+// To back up a running controller use its BackupApi, via download, which creates a new backup if no id is given.
+//
+// Restore keeps the existing data directory, renamed, and checks every database in the backup can be used by this
+// version of the tool before replacing anything.
+// Use the tool from the same release as the controller that will use the restored data.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/oauth"
+
+	"github.com/smart-core-os/sc-bos/internal/backup"
+	"github.com/smart-core-os/sc-bos/internal/secrets"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+
+	// register the schemas of databases that may be in a backup, so restore can check them
+	_ "github.com/smart-core-os/sc-bos/internal/account"
+	_ "github.com/smart-core-os/sc-bos/internal/audit/auditdb"
+	_ "github.com/smart-core-os/sc-bos/internal/auth/session"
+	_ "github.com/smart-core-os/sc-bos/internal/health/healthdb"
+	_ "github.com/smart-core-os/sc-bos/pkg/history/sqlitestore"
+	_ "github.com/smart-core-os/sc-bos/pkg/system/billing"
+	_ "github.com/smart-core-os/sc-bos/pkg/system/lighttest"
+)
+
+var commands = map[string]func(ctx context.Context, args []string) error{
+	"create":   create,
+	"verify":   verify,
+	"restore":  restore,
+	"list":     list,
+	"download": download,
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		_, _ = fmt.Fprintln(os.Stderr, "usage: backup create|verify|restore|list|download [flags]")
+		os.Exit(2)
+	}
+	if err := commands[os.Args[1]](ctx, os.Args[2:]); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func create(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	dataDir := fs.String("data", ".data", "data directory to back up")
+	out := fs.String("out", "", "file to write the backup to")
+	keyFile := fs.String("key-file", "", "file containing the base64 key to encrypt the backup with")
+	exclude := fs.String("exclude", "backups", "comma separated patterns of files to exclude")
+	_ = fs.Parse(args)
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+	opts, err := keyOpts(*keyFile)
+	if err != nil {
+		return err
+	}
+	if *exclude != "" {
+		opts = append(opts, backup.WithExclude(strings.Split(*exclude, ",")...))
+	}
+
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	m, err := backup.Create(ctx, f, *dataDir, opts...)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(*out)
+		return err
+	}
+	printManifest(m)
+	return nil
+}
+
+func verify(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	in := fs.String("in", "", "backup file to check")
+	keyFile := fs.String("key-file", "", "file containing the base64 key the backup was encrypted with")
+	_ = fs.Parse(args)
+	opts, err := keyOpts(*keyFile)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	res, err := backup.Verify(ctx, f, opts...)
+	printWarnings(res.Warnings)
+	if err != nil {
+		return err
+	}
+	printManifest(res.Manifest)
+	fmt.Println("backup is OK")
+	return nil
+}
+
+func restore(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	dataDir := fs.String("data", ".data", "data directory to replace")
+	in := fs.String("in", "", "backup file to restore")
+	keyFile := fs.String("key-file", "", "file containing the base64 key the backup was encrypted with")
+	keep := fs.String("keep", "backups", "comma separated paths to move from the existing data directory to the restored one")
+	_ = fs.Parse(args)
+	opts, err := keyOpts(*keyFile)
+	if err != nil {
+		return err
+	}
+	if *keep != "" {
+		opts = append(opts, backup.WithKeep(strings.Split(*keep, ",")...))
+	}
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	res, err := backup.Restore(ctx, f, *dataDir, opts...)
+	printWarnings(res.Warnings)
+	if err != nil {
+		return err
+	}
+	printManifest(res.Manifest)
+	fmt.Printf("restored to %s\n", *dataDir)
+	if res.PreviousDir != "" {
+		fmt.Printf("previous data directory moved to %s\n", res.PreviousDir)
+	}
+	return nil
+}
+
+func list(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	conn := connFlags(fs)
+	_ = fs.Parse(args)
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+	req := &gen.ListBackupsRequest{}
+	for {
+		res, err := client.ListBackups(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, b := range res.Backups {
+			fmt.Printf("%s\t%s\t%d bytes\tencrypted=%t\n", b.Id, b.CreateTime.AsTime().Local(), b.Size, b.Encrypted)
+		}
+		if res.NextPageToken == "" {
+			return nil
+		}
+		req.PageToken = res.NextPageToken
+	}
+}
+
+func download(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("download", flag.ExitOnError)
+	conn := connFlags(fs)
+	id := fs.String("id", "", "id of the backup to download, a new backup is created if empty")
+	out := fs.String("out", "", "file to write the backup to, defaults to the backup id")
+	_ = fs.Parse(args)
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+	if *id == "" {
+		b, err := client.CreateBackup(ctx, &gen.CreateBackupRequest{})
+		if err != nil {
+			return fmt.Errorf("create backup: %w", err)
+		}
+		*id = b.Id
+		fmt.Printf("created backup %s\n", b.Id)
+	}
+	if *out == "" {
+		*out = filepath.Base(*id)
+	}
+
+	stream, err := client.DownloadBackup(ctx, &gen.DownloadBackupRequest{Id: *id})
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		for {
+			res, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := f.Write(res.Data); err != nil {
+				return err
+			}
+		}
+	}()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(*out)
+		return err
+	}
+	fmt.Printf("downloaded %s to %s\n", *id, *out)
+	return nil
+}
+
+func keyOpts(keyFile string) ([]backup.Option, error) {
+	if keyFile == "" {
+		return nil, nil
+	}
+	text, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := secrets.ParseKey(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyFile, err)
+	}
+	return []backup.Option{backup.WithKey(key)}, nil
+}
+
+type conn struct {
+	addr         *string
+	insecure     *bool
+	caCert       *string
+	tokenURL     *string
+	clientID     *string
+	clientSecret *string
+}
+
+func connFlags(fs *flag.FlagSet) conn {
+	return conn{
+		addr:         fs.String("addr", "localhost:23557", "host:port of gRPC server to call"),
+		insecure:     fs.Bool("insecure", false, "don't verify TLS certificates"),
+		caCert:       fs.String("ca", "", "path to Root CA certificate(s), PEM format X.509"),
+		tokenURL:     fs.String("token-url", "https://localhost:443/oauth2/token", "URL of OAuth2 token endpoint"),
+		clientID:     fs.String("client-id", "", "OAuth2 client ID, no token is used if empty"),
+		clientSecret: fs.String("client-secret", "", "OAuth2 client secret"),
+	}
+}
+
+func (c conn) client(ctx context.Context) (gen.BackupApiClient, error) {
+	tlsConfig := &tls.Config{}
+	if *c.insecure {
+		tlsConfig.InsecureSkipVerify = true
+	} else if *c.caCert != "" {
+		pem, err := os.ReadFile(*c.caCert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(pem)
+		tlsConfig.RootCAs = pool
+	}
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	if *c.clientID != "" {
+		ccConfig := &clientcredentials.Config{
+			TokenURL:     *c.tokenURL,
+			ClientID:     *c.clientID,
+			ClientSecret: *c.clientSecret,
+		}
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(oauth.TokenSource{TokenSource: ccConfig.TokenSource(ctx)}))
+	}
+	cc, err := grpc.NewClient(*c.addr, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return gen.NewBackupApiClient(cc), nil
+}
+
+func printManifest(m backup.Manifest) {
+	fmt.Printf("backup of %q created %s by version %q, format %d\n", m.Node, m.CreateTime.Local(), m.Version, m.FormatVersion)
+	for _, f := range m.Files {
+		fmt.Printf("  %s\t%s\t%d bytes\n", f.Path, f.Kind, f.Size)
+	}
+}
+
+func printWarnings(warnings []string) {
+	for _, w := range warnings {
+		_, _ = fmt.Fprintf(os.Stderr, "WARNING: %s\n", w)
+	}
+}
@@ -7,6 +7,15 @@
 //
 // The tool finds arrays that look like sets (have some id/key field) and checks for duplicate items.
 // What the tool defines as keys can be configured using the `-key` flag, which can be used multiple times.
+//
+// App config:
+//
+// Files passed using the `-app` flag, which can be used multiple times, are loaded as app config, including any
+// includes, and templates are expanded.
+// Errors loading the config or expanding templates are reported, and the tool exits with a non-zero status.
+// Environment variables referenced by templates are read from the environment the tool runs in,
+// system config vars are read from the file passed using the `-sysconf` flag, without it ${sys.name} references are
+// reported as unset.
 package main
 
 import (
@@ -22,28 +31,40 @@ import (
 	"strings"
 
 	"github.com/qri-io/jsonpointer"
+	"go.uber.org/multierr"
 	"golang.org/x/exp/maps"
+
+	"github.com/smart-core-os/sc-bos/pkg/app/appconf"
+	"github.com/smart-core-os/sc-bos/pkg/app/sysconf"
 )
 
 var (
-	dir    = flag.String("dir", ".", "directory to scan")
-	suffix = flag.String("suffix", ".json", "file suffix to scan")
+	dir     = flag.String("dir", ".", "directory to scan")
+	suffix  = flag.String("suffix", ".json", "file suffix to scan")
+	sysConf = flag.String("sysconf", "", "system config file, whose vars are used when expanding app config templates")
 )
 
 var (
 	ignore stringList
 	keys   = stringList{"{name,kind}", "id", "name", "host", "test"}
+	app    stringList
 )
 
 func init() {
 	flag.Var(&ignore, "ignore", "ignore glob paths")
 	flag.Var(&keys, "key", "key fields to look for")
+	flag.Var(&app, "app", "app config file to load and expand templates of")
 }
 
 func main() {
 	flag.Parse()
+	sysVars, err := loadSysVars(*sysConf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	appOK := vetApp(app, sysVars)
 	// scan through all files in the directory
-	err := filepath.WalkDir(*dir, func(path string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(*dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -126,6 +147,40 @@ func main() {
 			}
 		}
 	}
+
+	if !appOK {
+		os.Exit(1)
+	}
+}
+
+// loadSysVars returns the vars of the system config file, or nil if file is empty.
+func loadSysVars(file string) (map[string]string, error) {
+	if file == "" {
+		return nil, nil
+	}
+	var conf sysconf.Config
+	if err := sysconf.LoadFromJSONFile(&conf, file); err != nil {
+		return nil, fmt.Errorf("load system config %q: %w", file, err)
+	}
+	return conf.Vars, nil
+}
+
+// vetApp loads each app config file, expanding templates, and prints any errors.
+// Returns false if there were errors.
+func vetApp(files []string, sysVars map[string]string) bool {
+	ok := true
+	for _, f := range files {
+		_, err := appconf.LoadLocalConfig(filepath.Dir(f), filepath.Base(f), sysVars)
+		if err == nil {
+			continue
+		}
+		ok = false
+		fmt.Printf("errors in app config %q:\n", f)
+		for _, err := range multierr.Errors(err) {
+			fmt.Printf("\t%v\n", err)
+		}
+	}
+	return ok
 }
 
 func inspect(file string, path jsonpointer.Pointer, data any) {
@@ -3,6 +3,7 @@ package account
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -34,10 +35,14 @@ func accountToProto(account queries.AccountDetail, clientSecret string) *gen.Acc
 	case gen.Account_USER_ACCOUNT:
 		converted.Details = &gen.Account_UserDetails{UserDetails: &gen.UserAccount{
 			HasPassword: account.PasswordHash != nil,
+			TotpEnabled: account.TotpEnabled.Valid && account.TotpEnabled.Bool,
 		}}
 		if account.Username.Valid {
 			converted.GetUserDetails().Username = account.Username.String
 		}
+		if account.LockoutExpireTime.Valid && account.LockoutExpireTime.Time.After(time.Now()) {
+			converted.GetUserDetails().LockoutExpireTime = timestamppb.New(account.LockoutExpireTime.Time)
+		}
 	case gen.Account_SERVICE_ACCOUNT:
 		converted.Details = &gen.Account_ServiceDetails{ServiceDetails: &gen.ServiceAccount{
 			ClientId:     id,
@@ -0,0 +1,207 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/internal/account/queries"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// LockoutPolicy describes when user accounts are locked after failed login attempts.
+type LockoutPolicy struct {
+	// MaxFailures is the number of consecutive failed login attempts that lock the account.
+	MaxFailures int
+	// Duration is how long the account is locked for the first time.
+	// Each consecutive lockout doubles the duration, up to MaxDuration.
+	Duration time.Duration
+	// MaxDuration is the longest the account will be locked for.
+	MaxDuration time.Duration
+	// FailureWindow is how long failures are remembered for.
+	// Once this has passed since the last failure or lockout, the account starts again with no failures.
+	FailureWindow time.Duration
+}
+
+// DefaultLockoutPolicy is used by RecordLoginFailure for any zero fields of the given policy.
+var DefaultLockoutPolicy = LockoutPolicy{
+	MaxFailures:   5,
+	Duration:      time.Minute,
+	MaxDuration:   time.Hour,
+	FailureWindow: 15 * time.Minute,
+}
+
+func (p LockoutPolicy) withDefaults() LockoutPolicy {
+	if p.MaxFailures <= 0 {
+		p.MaxFailures = DefaultLockoutPolicy.MaxFailures
+	}
+	if p.Duration <= 0 {
+		p.Duration = DefaultLockoutPolicy.Duration
+	}
+	if p.MaxDuration <= 0 {
+		p.MaxDuration = max(DefaultLockoutPolicy.MaxDuration, p.Duration)
+	}
+	if p.FailureWindow <= 0 {
+		p.FailureWindow = DefaultLockoutPolicy.FailureWindow
+	}
+	return p
+}
+
+// lockoutDuration returns how long the account is locked for the nth consecutive time, starting at 1.
+func (p LockoutPolicy) lockoutDuration(n int64) time.Duration {
+	d := p.Duration
+	for i := int64(1); i < n && d < p.MaxDuration; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxDuration)
+}
+
+// CheckLockout returns ErrAccountLocked if the account can't be logged in to at now because of failed attempts.
+func (tx *Tx) CheckLockout(ctx context.Context, accountID int64, now time.Time) error {
+	failures, err := tx.GetUserLoginFailures(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if failures.LockoutExpireTime.Valid && failures.LockoutExpireTime.Time.After(now) {
+		return ErrAccountLocked
+	}
+	return nil
+}
+
+// RecordLoginFailure records a failed login attempt for a user account at now.
+// If this attempt causes the account to be locked, the time the lockout expires is returned,
+// otherwise the zero time is returned.
+func (tx *Tx) RecordLoginFailure(ctx context.Context, accountID int64, now time.Time, policy LockoutPolicy) (time.Time, error) {
+	attempt, err := tx.recordLoginFailure(ctx, accountID, now, policy)
+	return attempt.LockedUntil, err
+}
+
+// LoginAttempt is a login attempt counted by BeginLoginAttempt.
+type LoginAttempt struct {
+	// LockedUntil is when the lockout caused by this attempt expires.
+	// Zero if the attempt didn't lock the account.
+	LockedUntil time.Time
+
+	accountID int64
+	prev      *queries.UserLoginFailure // nil if there were no failures before this attempt
+	counted   queries.UserLoginFailure
+}
+
+// BeginLoginAttempt counts a login attempt as failed before the credentials are checked,
+// so concurrent attempts can't all be checked before any of their failures are recorded.
+// Returns ErrAccountLocked if the account is already locked.
+//
+// A successful login should call ResetLoginFailures.
+// An attempt that was neither successful nor failed, for example one waiting for a second factor,
+// should call CancelLoginAttempt.
+func (tx *Tx) BeginLoginAttempt(ctx context.Context, accountID int64, now time.Time, policy LockoutPolicy) (LoginAttempt, error) {
+	if err := tx.CheckLockout(ctx, accountID, now); err != nil {
+		return LoginAttempt{}, err
+	}
+	return tx.recordLoginFailure(ctx, accountID, now, policy)
+}
+
+// CancelLoginAttempt removes the failure counted by BeginLoginAttempt, including any lockout it caused.
+// If other failures have been recorded since, the attempt stays counted.
+func (tx *Tx) CancelLoginAttempt(ctx context.Context, attempt LoginAttempt) error {
+	failures, err := tx.GetUserLoginFailures(ctx, attempt.accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // already reset
+	} else if err != nil {
+		return err
+	}
+	if !sameLoginFailures(failures, attempt.counted) {
+		return nil
+	}
+	if attempt.prev == nil {
+		_, err = tx.DeleteUserLoginFailures(ctx, attempt.accountID)
+		return err
+	}
+	return tx.upsertLoginFailures(ctx, *attempt.prev)
+}
+
+func (tx *Tx) recordLoginFailure(ctx context.Context, accountID int64, now time.Time, policy LockoutPolicy) (LoginAttempt, error) {
+	policy = policy.withDefaults()
+	now = now.UTC()
+
+	attempt := LoginAttempt{accountID: accountID}
+	failures, err := tx.GetUserLoginFailures(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		failures = queries.UserLoginFailure{AccountID: accountID}
+	} else if err != nil {
+		return LoginAttempt{}, err
+	} else {
+		prev := failures
+		attempt.prev = &prev
+	}
+
+	lastActivity := failures.LastFailureTime
+	if failures.LockoutExpireTime.Valid && failures.LockoutExpireTime.Time.After(lastActivity) {
+		lastActivity = failures.LockoutExpireTime.Time
+	}
+	if now.Sub(lastActivity) > policy.FailureWindow {
+		failures.FailedAttempts = 0
+		failures.LockoutCount = 0
+	}
+
+	failures.FailedAttempts++
+	failures.LastFailureTime = now
+	if failures.FailedAttempts >= int64(policy.MaxFailures) {
+		failures.FailedAttempts = 0
+		failures.LockoutCount++
+		attempt.LockedUntil = now.Add(policy.lockoutDuration(failures.LockoutCount))
+		failures.LockoutExpireTime = sql.NullTime{Valid: true, Time: attempt.LockedUntil}
+	}
+
+	if err := tx.upsertLoginFailures(ctx, failures); err != nil {
+		return LoginAttempt{}, err
+	}
+	// read back what was stored, times lose precision in the database
+	attempt.counted, err = tx.GetUserLoginFailures(ctx, accountID)
+	if err != nil {
+		return LoginAttempt{}, err
+	}
+	return attempt, nil
+}
+
+func (tx *Tx) upsertLoginFailures(ctx context.Context, failures queries.UserLoginFailure) error {
+	return tx.UpsertUserLoginFailures(ctx, queries.UpsertUserLoginFailuresParams{
+		AccountID:         failures.AccountID,
+		FailedAttempts:    failures.FailedAttempts,
+		LockoutCount:      failures.LockoutCount,
+		LastFailureTime:   failures.LastFailureTime,
+		LockoutExpireTime: failures.LockoutExpireTime,
+	})
+}
+
+// sameLoginFailures reports whether a and b record the same failures, comparing times as instants.
+func sameLoginFailures(a, b queries.UserLoginFailure) bool {
+	return a.FailedAttempts == b.FailedAttempts &&
+		a.LockoutCount == b.LockoutCount &&
+		a.LastFailureTime.Equal(b.LastFailureTime) &&
+		a.LockoutExpireTime.Valid == b.LockoutExpireTime.Valid &&
+		a.LockoutExpireTime.Time.Equal(b.LockoutExpireTime.Time)
+}
+
+// ResetLoginFailures forgets any failed login attempts for an account, typically after a successful login.
+func (tx *Tx) ResetLoginFailures(ctx context.Context, accountID int64) error {
+	_, err := tx.DeleteUserLoginFailures(ctx, accountID)
+	return err
+}
+
+// UnlockAccount removes any lockout from a user account, along with its record of failed login attempts.
+func (tx *Tx) UnlockAccount(ctx context.Context, accountID int64) error {
+	account, err := tx.GetAccount(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAccountNotFound
+	} else if err != nil {
+		return err
+	}
+	if account.Type != gen.Account_USER_ACCOUNT.String() {
+		return ErrInvalidAccountType
+	}
+	return tx.ResetLoginFailures(ctx, accountID)
+}
@@ -0,0 +1,219 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/internal/account/queries"
+	"github.com/smart-core-os/sc-bos/internal/util/totp"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const (
+	// recoveryCodeCount is the number of recovery codes generated for an account at a time.
+	recoveryCodeCount = 10
+	// recoveryCodeBytes is the number of random bytes in each recovery code, encoded as 16 base32 characters.
+	recoveryCodeBytes = 10
+	// totpSkew is the number of time steps either side of now that TOTP codes are accepted for.
+	totpSkew = 1
+	// totpIssuer is presented to users in their authenticator app.
+	totpIssuer = "Smart Core"
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// BeginTotpEnrollment generates and stores a new pending TOTP secret for a user account.
+// Any existing pending enrollment is replaced.
+// Returns the new secret and an otpauth URI describing it.
+func (tx *Tx) BeginTotpEnrollment(ctx context.Context, accountID int64) (secret []byte, uri string, err error) {
+	details, err := tx.GetAccountDetails(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrAccountNotFound
+	} else if err != nil {
+		return nil, "", err
+	}
+	if details.Type != gen.Account_USER_ACCOUNT.String() {
+		return nil, "", ErrUnexpectedMfa
+	}
+	if details.TotpEnabled.Valid && details.TotpEnabled.Bool {
+		return nil, "", ErrMfaAlreadyEnabled
+	}
+
+	secret, err = totp.GenerateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	err = tx.UpsertUserTotp(ctx, queries.UpsertUserTotpParams{
+		AccountID: accountID,
+		Secret:    secret,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return secret, totp.URI(totpIssuer, details.Username.String, secret), nil
+}
+
+// ConfirmTotpEnrollment enables TOTP for an account with a pending enrollment, if code is valid.
+// Returns new recovery codes for the account.
+func (tx *Tx) ConfirmTotpEnrollment(ctx context.Context, accountID int64, code string, now time.Time) ([]string, error) {
+	enrollment, err := tx.GetUserTotp(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMfaNotPending
+	} else if err != nil {
+		return nil, err
+	}
+	if enrollment.Confirmed {
+		return nil, ErrMfaAlreadyEnabled
+	}
+	step, ok := totp.Validate(enrollment.Secret, normaliseCode(code), now, totpSkew)
+	if !ok {
+		return nil, ErrIncorrectCode
+	}
+	err = tx.ConfirmUserTotp(ctx, queries.ConfirmUserTotpParams{
+		AccountID:    accountID,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tx.RegenerateRecoveryCodes(ctx, accountID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes for an account with TOTP enabled.
+func (tx *Tx) RegenerateRecoveryCodes(ctx context.Context, accountID int64) ([]string, error) {
+	enabled, err := tx.TotpEnabled(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrMfaNotEnabled
+	}
+
+	_, err = tx.ClearRecoveryCodes(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = genRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		err = tx.CreateRecoveryCode(ctx, queries.CreateRecoveryCodeParams{
+			AccountID: accountID,
+			CodeHash:  hashSecret(normaliseCode(codes[i])),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
+
+// TotpEnabled returns whether the account requires a TOTP code to log in.
+func (tx *Tx) TotpEnabled(ctx context.Context, accountID int64) (bool, error) {
+	enrollment, err := tx.GetUserTotp(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return enrollment.Confirmed, nil
+}
+
+// CheckSecondFactor checks that code is either a valid TOTP code or an unused recovery code for the account.
+// Accepted codes cannot be used again.
+// Returns ErrIncorrectCode if the code is not accepted.
+func (tx *Tx) CheckSecondFactor(ctx context.Context, accountID int64, code string, now time.Time) error {
+	code = normaliseCode(code)
+	if code == "" {
+		return ErrIncorrectCode
+	}
+	enrollment, err := tx.GetUserTotp(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrIncorrectCode
+	} else if err != nil {
+		return err
+	}
+	if !enrollment.Confirmed {
+		return ErrIncorrectCode
+	}
+
+	if step, ok := totp.Validate(enrollment.Secret, code, now, totpSkew); ok {
+		// only accept the code if no code for this, or a later, step has been used
+		n, err := tx.UpdateUserTotpLastUsedStep(ctx, queries.UpdateUserTotpLastUsedStepParams{
+			AccountID:    accountID,
+			LastUsedStep: step,
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrIncorrectCode
+		}
+		return nil
+	}
+
+	n, err := tx.UseRecoveryCode(ctx, queries.UseRecoveryCodeParams{
+		AccountID: accountID,
+		CodeHash:  hashSecret(code),
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrIncorrectCode
+	}
+	return nil
+}
+
+// ResetMfa removes any TOTP enrollment and recovery codes from an account.
+func (tx *Tx) ResetMfa(ctx context.Context, accountID int64) error {
+	_, err := tx.GetAccount(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAccountNotFound
+	} else if err != nil {
+		return err
+	}
+	if _, err := tx.DeleteUserTotp(ctx, accountID); err != nil {
+		return err
+	}
+	_, err = tx.ClearRecoveryCodes(ctx, accountID)
+	return err
+}
+
+// genRecoveryCode returns a new random recovery code formatted for display, like ABCD-EFGH-IJKL-MNOP.
+func genRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeBytes)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	encoded := recoveryCodeEncoding.EncodeToString(b)
+	var sb strings.Builder
+	for i, r := range encoded {
+		if i > 0 && i%4 == 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String(), nil
+}
+
+// normaliseCode removes formatting from user entered TOTP and recovery codes.
+func normaliseCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '-' || r == ' ':
+			return -1
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		}
+		return r
+	}, code)
+}
@@ -12,3 +12,7 @@ import (
 var migrationsFS embed.FS
 
 var schema = sqlite.MustLoadVersionedSchema(migrationsFS, "migrations")
+
+func init() {
+	sqlite.RegisterSchema("accounts", appID, schema)
+}
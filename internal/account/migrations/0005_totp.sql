@@ -0,0 +1,33 @@
+-- Optional TOTP second factor for user accounts.
+-- An enrollment is pending until confirmed with a valid code, only confirmed enrollments are enforced at login.
+CREATE TABLE user_totp (
+    account_id      INTEGER PRIMARY KEY,
+    secret          BLOB NOT NULL,
+    confirmed       BOOLEAN NOT NULL DEFAULT FALSE,
+    -- the most recent time step a code was accepted for, codes for this step or earlier are rejected to prevent replay
+    last_used_step  INTEGER NOT NULL DEFAULT 0,
+    create_time     DATETIME NOT NULL,
+
+    FOREIGN KEY (account_id) REFERENCES user_accounts (account_id) ON DELETE CASCADE,
+    CONSTRAINT create_time_format CHECK ( create_time IS datetime(create_time, 'subsec') )
+);
+
+-- Single use codes that can be used in place of a TOTP code, for when the user loses their authenticator.
+CREATE TABLE user_recovery_codes (
+    id          INTEGER PRIMARY KEY,
+    account_id  INTEGER NOT NULL,
+    code_hash   BLOB NOT NULL,
+
+    FOREIGN KEY (account_id) REFERENCES user_accounts (account_id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_recovery_codes_account_id ON user_recovery_codes (account_id);
+
+DROP VIEW account_details;
+CREATE VIEW account_details AS
+SELECT accounts.*, username, password_hash, primary_secret_hash, secondary_secret_hash, secondary_secret_expire_time,
+       user_totp.confirmed AS totp_enabled
+FROM accounts
+LEFT OUTER JOIN user_accounts ON accounts.id = user_accounts.account_id
+LEFT OUTER JOIN service_accounts ON accounts.id = service_accounts.account_id
+LEFT OUTER JOIN user_totp ON accounts.id = user_totp.account_id;
@@ -0,0 +1,37 @@
+-- Previous password hashes of user accounts, so that password policies can prevent their reuse.
+CREATE TABLE user_password_history (
+    id              INTEGER PRIMARY KEY,
+    account_id      INTEGER NOT NULL,
+    password_hash   BLOB NOT NULL,
+    create_time     DATETIME NOT NULL,
+
+    FOREIGN KEY (account_id) REFERENCES user_accounts (account_id) ON DELETE CASCADE,
+    CONSTRAINT create_time_format CHECK ( create_time IS datetime(create_time, 'subsec') )
+);
+
+CREATE INDEX user_password_history_account_id ON user_password_history (account_id, id);
+
+-- Failed login attempts for user accounts.
+-- Once enough attempts fail the account is locked until lockout_expire_time,
+-- lockout_count increases the duration of each consecutive lockout.
+CREATE TABLE user_login_failures (
+    account_id          INTEGER PRIMARY KEY,
+    failed_attempts     INTEGER NOT NULL DEFAULT 0,
+    lockout_count       INTEGER NOT NULL DEFAULT 0,
+    last_failure_time   DATETIME NOT NULL,
+    lockout_expire_time DATETIME,
+
+    FOREIGN KEY (account_id) REFERENCES user_accounts (account_id) ON DELETE CASCADE,
+    CONSTRAINT last_failure_time_format CHECK ( last_failure_time IS datetime(last_failure_time, 'subsec') ),
+    CONSTRAINT lockout_expire_time_format CHECK ( lockout_expire_time IS datetime(lockout_expire_time, 'subsec') )
+);
+
+DROP VIEW account_details;
+CREATE VIEW account_details AS
+SELECT accounts.*, username, password_hash, primary_secret_hash, secondary_secret_hash, secondary_secret_expire_time,
+       user_totp.confirmed AS totp_enabled, user_login_failures.lockout_expire_time
+FROM accounts
+LEFT OUTER JOIN user_accounts ON accounts.id = user_accounts.account_id
+LEFT OUTER JOIN service_accounts ON accounts.id = service_accounts.account_id
+LEFT OUTER JOIN user_totp ON accounts.id = user_totp.account_id
+LEFT OUTER JOIN user_login_failures ON accounts.id = user_login_failures.account_id;
@@ -0,0 +1,160 @@
+package account
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/internal/account/queries"
+	"github.com/smart-core-os/sc-bos/internal/util/pass"
+)
+
+// maxPasswordHistory is the number of previous password hashes kept for each account.
+const maxPasswordHistory = 24
+
+// PasswordPolicy describes the passwords user accounts are allowed to have.
+// The zero value only applies the fixed length limits.
+type PasswordPolicy struct {
+	// MinLength is the minimum password length, values below the fixed minimum of 10 are ignored.
+	MinLength int
+	// MinCharacterClasses is the number of different classes of character a password must contain.
+	// The classes are lower case letters, upper case letters, digits, and everything else.
+	MinCharacterClasses int
+	// Denylist contains lower case passwords that are known to be breached or easily guessed.
+	Denylist map[string]struct{}
+	// History is the number of most recent passwords, including the current one, that can't be reused.
+	// At most 25 passwords are remembered.
+	History int
+}
+
+func (p PasswordPolicy) minLength() int {
+	return max(p.MinLength, minPasswordLength)
+}
+
+// Check returns an error describing why password does not comply with the policy, or nil if it does.
+// Password history is checked separately, when the password is updated.
+func (p PasswordPolicy) Check(password string) error {
+	password = normalisePassword(password)
+	if len(password) < p.minLength() {
+		return policyError("must be at least %d characters", p.minLength())
+	}
+	if len(password) > maxPasswordLength {
+		return policyError("must be at most %d characters", maxPasswordLength)
+	}
+	if n := characterClasses(password); n < p.MinCharacterClasses {
+		return policyError("must contain at least %d of lower case letters, upper case letters, digits, and symbols", p.MinCharacterClasses)
+	}
+	if _, ok := p.Denylist[strings.ToLower(password)]; ok {
+		return policyError("is too common")
+	}
+	return nil
+}
+
+func policyError(format string, args ...any) error {
+	return status.Errorf(codes.InvalidArgument, "password does not comply with policy: password "+format, args...)
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+	var n int
+	for _, b := range []bool{lower, upper, digit, other} {
+		if b {
+			n++
+		}
+	}
+	return n
+}
+
+// LoadDenylist reads a file of denied passwords, one per line, for use as PasswordPolicy.Denylist.
+// Blank lines and lines starting with # are ignored.
+// Passwords that could never be allowed by the length limits are skipped to save memory.
+func LoadDenylist(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	denylist := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := normalisePassword(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if len(line) < minPasswordLength || len(line) > maxPasswordLength {
+			continue
+		}
+		denylist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return denylist, nil
+}
+
+// checkPasswordHistory returns ErrPasswordReused if password matches the current password of the account
+// or one of its previous passwords within the policy history.
+func (tx *Tx) checkPasswordHistory(ctx context.Context, current []byte, accountID int64, password string) error {
+	if tx.policy.History <= 0 {
+		return nil
+	}
+	var hashes [][]byte
+	if current != nil {
+		hashes = append(hashes, current)
+	}
+	if n := min(tx.policy.History-1, maxPasswordHistory); n > 0 {
+		previous, err := tx.ListPasswordHistory(ctx, queries.ListPasswordHistoryParams{
+			AccountID: accountID,
+			Limit:     int64(n),
+		})
+		if err != nil {
+			return err
+		}
+		hashes = append(hashes, previous...)
+	}
+	password = normalisePassword(password)
+	for _, hash := range hashes {
+		if pass.Compare(hash, []byte(password)) == nil {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// recordPasswordHistory remembers the replaced password hash of an account.
+func (tx *Tx) recordPasswordHistory(ctx context.Context, accountID int64, hash []byte) error {
+	if hash == nil {
+		return nil
+	}
+	err := tx.CreatePasswordHistory(ctx, queries.CreatePasswordHistoryParams{
+		AccountID:    accountID,
+		PasswordHash: hash,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tx.TrimPasswordHistory(ctx, queries.TrimPasswordHistoryParams{
+		AccountID: accountID,
+		Keep:      maxPasswordHistory,
+	})
+	return err
+}
@@ -28,6 +28,8 @@ type AccountDetail struct {
 	PrimarySecretHash         []byte
 	SecondarySecretHash       []byte
 	SecondarySecretExpireTime sql.NullTime
+	TotpEnabled               sql.NullBool
+	LockoutExpireTime         sql.NullTime
 }
 
 type Role struct {
@@ -63,3 +65,32 @@ type UserAccount struct {
 	Username     string
 	PasswordHash []byte
 }
+
+type UserLoginFailure struct {
+	AccountID         int64
+	FailedAttempts    int64
+	LockoutCount      int64
+	LastFailureTime   time.Time
+	LockoutExpireTime sql.NullTime
+}
+
+type UserPasswordHistory struct {
+	ID           int64
+	AccountID    int64
+	PasswordHash []byte
+	CreateTime   time.Time
+}
+
+type UserRecoveryCode struct {
+	ID        int64
+	AccountID int64
+	CodeHash  []byte
+}
+
+type UserTotp struct {
+	AccountID    int64
+	Secret       []byte
+	Confirmed    bool
+	LastUsedStep int64
+	CreateTime   time.Time
+}
@@ -215,4 +215,89 @@ RETURNING *;
 
 -- name: DeleteRoleAssignment :execrows
 DELETE FROM role_assignments
-WHERE id = :id;
\ No newline at end of file
+WHERE id = :id;
+-- name: GetUserTotp :one
+SELECT *
+FROM user_totp
+WHERE account_id = :account_id;
+
+-- name: UpsertUserTotp :exec
+INSERT INTO user_totp (account_id, secret, create_time)
+VALUES (:account_id, :secret, datetime('now', 'subsec'))
+ON CONFLICT (account_id) DO UPDATE
+SET secret         = excluded.secret,
+    confirmed      = FALSE,
+    last_used_step = 0,
+    create_time    = excluded.create_time;
+
+-- name: ConfirmUserTotp :exec
+UPDATE user_totp
+SET confirmed      = TRUE,
+    last_used_step = :last_used_step
+WHERE account_id = :account_id;
+
+-- name: UpdateUserTotpLastUsedStep :execrows
+UPDATE user_totp
+SET last_used_step = :last_used_step
+WHERE account_id = :account_id
+  AND last_used_step < :last_used_step;
+
+-- name: DeleteUserTotp :execrows
+DELETE FROM user_totp
+WHERE account_id = :account_id;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO user_recovery_codes (account_id, code_hash)
+VALUES (:account_id, :code_hash);
+
+-- name: UseRecoveryCode :execrows
+DELETE FROM user_recovery_codes
+WHERE account_id = :account_id
+  AND code_hash = :code_hash;
+
+-- name: ClearRecoveryCodes :execrows
+DELETE FROM user_recovery_codes
+WHERE account_id = :account_id;
+
+-- name: CountRecoveryCodes :one
+SELECT COUNT(*) AS count
+FROM user_recovery_codes
+WHERE account_id = :account_id;
+
+-- name: CreatePasswordHistory :exec
+INSERT INTO user_password_history (account_id, password_hash, create_time)
+VALUES (:account_id, :password_hash, datetime('now', 'subsec'));
+
+-- name: ListPasswordHistory :many
+SELECT password_hash
+FROM user_password_history
+WHERE account_id = :account_id
+ORDER BY id DESC
+LIMIT :limit;
+
+-- name: TrimPasswordHistory :execrows
+DELETE FROM user_password_history
+WHERE account_id = :account_id
+  AND id NOT IN (SELECT id
+                 FROM user_password_history
+                 WHERE account_id = :account_id
+                 ORDER BY id DESC
+                 LIMIT :keep);
+
+-- name: GetUserLoginFailures :one
+SELECT *
+FROM user_login_failures
+WHERE account_id = :account_id;
+
+-- name: UpsertUserLoginFailures :exec
+INSERT INTO user_login_failures (account_id, failed_attempts, lockout_count, last_failure_time, lockout_expire_time)
+VALUES (:account_id, :failed_attempts, :lockout_count, :last_failure_time, :lockout_expire_time)
+ON CONFLICT (account_id) DO UPDATE
+SET failed_attempts     = excluded.failed_attempts,
+    lockout_count       = excluded.lockout_count,
+    last_failure_time   = excluded.last_failure_time,
+    lockout_expire_time = excluded.lockout_expire_time;
+
+-- name: DeleteUserLoginFailures :execrows
+DELETE FROM user_login_failures
+WHERE account_id = :account_id;
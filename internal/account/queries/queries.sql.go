@@ -8,6 +8,7 @@ package queries
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const addRolePermission = `-- name: AddRolePermission :exec
@@ -26,6 +27,19 @@ func (q *Queries) AddRolePermission(ctx context.Context, arg AddRolePermissionPa
 	return err
 }
 
+const clearRecoveryCodes = `-- name: ClearRecoveryCodes :execrows
+DELETE FROM user_recovery_codes
+WHERE account_id = ?1
+`
+
+func (q *Queries) ClearRecoveryCodes(ctx context.Context, accountID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, clearRecoveryCodes, accountID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const clearRolePermissions = `-- name: ClearRolePermissions :execrows
 DELETE FROM role_permissions
 WHERE role_id = ?1
@@ -39,6 +53,23 @@ func (q *Queries) ClearRolePermissions(ctx context.Context, roleID int64) (int64
 	return result.RowsAffected()
 }
 
+const confirmUserTotp = `-- name: ConfirmUserTotp :exec
+UPDATE user_totp
+SET confirmed      = TRUE,
+    last_used_step = ?1
+WHERE account_id = ?2
+`
+
+type ConfirmUserTotpParams struct {
+	LastUsedStep int64
+	AccountID    int64
+}
+
+func (q *Queries) ConfirmUserTotp(ctx context.Context, arg ConfirmUserTotpParams) error {
+	_, err := q.db.ExecContext(ctx, confirmUserTotp, arg.LastUsedStep, arg.AccountID)
+	return err
+}
+
 const countAccounts = `-- name: CountAccounts :one
 SELECT COUNT(*) AS count
 FROM accounts
@@ -51,6 +82,19 @@ func (q *Queries) CountAccounts(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countRecoveryCodes = `-- name: CountRecoveryCodes :one
+SELECT COUNT(*) AS count
+FROM user_recovery_codes
+WHERE account_id = ?1
+`
+
+func (q *Queries) CountRecoveryCodes(ctx context.Context, accountID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRecoveryCodes, accountID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countRoleAssignments = `-- name: CountRoleAssignments :one
 SELECT COUNT(*)
 FROM role_assignments
@@ -126,6 +170,36 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 	return i, err
 }
 
+const createPasswordHistory = `-- name: CreatePasswordHistory :exec
+INSERT INTO user_password_history (account_id, password_hash, create_time)
+VALUES (?1, ?2, datetime('now', 'subsec'))
+`
+
+type CreatePasswordHistoryParams struct {
+	AccountID    int64
+	PasswordHash []byte
+}
+
+func (q *Queries) CreatePasswordHistory(ctx context.Context, arg CreatePasswordHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, createPasswordHistory, arg.AccountID, arg.PasswordHash)
+	return err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO user_recovery_codes (account_id, code_hash)
+VALUES (?1, ?2)
+`
+
+type CreateRecoveryCodeParams struct {
+	AccountID int64
+	CodeHash  []byte
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.db.ExecContext(ctx, createRecoveryCode, arg.AccountID, arg.CodeHash)
+	return err
+}
+
 const createRole = `-- name: CreateRole :one
 INSERT INTO roles (display_name, description)
 VALUES (?1, ?2)
@@ -281,6 +355,32 @@ func (q *Queries) DeleteRolePermission(ctx context.Context, arg DeleteRolePermis
 	return result.RowsAffected()
 }
 
+const deleteUserLoginFailures = `-- name: DeleteUserLoginFailures :execrows
+DELETE FROM user_login_failures
+WHERE account_id = ?1
+`
+
+func (q *Queries) DeleteUserLoginFailures(ctx context.Context, accountID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserLoginFailures, accountID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUserTotp = `-- name: DeleteUserTotp :execrows
+DELETE FROM user_totp
+WHERE account_id = ?1
+`
+
+func (q *Queries) DeleteUserTotp(ctx context.Context, accountID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserTotp, accountID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getAccount = `-- name: GetAccount :one
 SELECT id, display_name, description, type, create_time
 FROM accounts
@@ -313,7 +413,7 @@ func (q *Queries) GetAccountByUsername(ctx context.Context, username string) (Us
 }
 
 const getAccountDetails = `-- name: GetAccountDetails :one
-SELECT id, display_name, description, type, create_time, username, password_hash, primary_secret_hash, secondary_secret_hash, secondary_secret_expire_time FROM account_details
+SELECT id, display_name, description, type, create_time, username, password_hash, primary_secret_hash, secondary_secret_hash, secondary_secret_expire_time, totp_enabled, lockout_expire_time FROM account_details
 WHERE id = ?1
 `
 
@@ -331,6 +431,8 @@ func (q *Queries) GetAccountDetails(ctx context.Context, id int64) (AccountDetai
 		&i.PrimarySecretHash,
 		&i.SecondarySecretHash,
 		&i.SecondarySecretExpireTime,
+		&i.TotpEnabled,
+		&i.LockoutExpireTime,
 	)
 	return i, err
 }
@@ -373,8 +475,46 @@ func (q *Queries) GetRoleAssignment(ctx context.Context, id int64) (RoleAssignme
 	return i, err
 }
 
+const getUserLoginFailures = `-- name: GetUserLoginFailures :one
+SELECT account_id, failed_attempts, lockout_count, last_failure_time, lockout_expire_time
+FROM user_login_failures
+WHERE account_id = ?1
+`
+
+func (q *Queries) GetUserLoginFailures(ctx context.Context, accountID int64) (UserLoginFailure, error) {
+	row := q.db.QueryRowContext(ctx, getUserLoginFailures, accountID)
+	var i UserLoginFailure
+	err := row.Scan(
+		&i.AccountID,
+		&i.FailedAttempts,
+		&i.LockoutCount,
+		&i.LastFailureTime,
+		&i.LockoutExpireTime,
+	)
+	return i, err
+}
+
+const getUserTotp = `-- name: GetUserTotp :one
+SELECT account_id, secret, confirmed, last_used_step, create_time
+FROM user_totp
+WHERE account_id = ?1
+`
+
+func (q *Queries) GetUserTotp(ctx context.Context, accountID int64) (UserTotp, error) {
+	row := q.db.QueryRowContext(ctx, getUserTotp, accountID)
+	var i UserTotp
+	err := row.Scan(
+		&i.AccountID,
+		&i.Secret,
+		&i.Confirmed,
+		&i.LastUsedStep,
+		&i.CreateTime,
+	)
+	return i, err
+}
+
 const listAccountDetails = `-- name: ListAccountDetails :many
-SELECT id, display_name, description, type, create_time, username, password_hash, primary_secret_hash, secondary_secret_hash, secondary_secret_expire_time FROM account_details
+SELECT id, display_name, description, type, create_time, username, password_hash, primary_secret_hash, secondary_secret_hash, secondary_secret_expire_time, totp_enabled, lockout_expire_time FROM account_details
 WHERE id > ?1
 ORDER BY id
 LIMIT ?2
@@ -405,6 +545,8 @@ func (q *Queries) ListAccountDetails(ctx context.Context, arg ListAccountDetails
 			&i.PrimarySecretHash,
 			&i.SecondarySecretHash,
 			&i.SecondarySecretExpireTime,
+			&i.TotpEnabled,
+			&i.LockoutExpireTime,
 		); err != nil {
 			return nil, err
 		}
@@ -493,6 +635,42 @@ func (q *Queries) ListLegacyRolesForAccount(ctx context.Context, accountID int64
 	return items, nil
 }
 
+const listPasswordHistory = `-- name: ListPasswordHistory :many
+SELECT password_hash
+FROM user_password_history
+WHERE account_id = ?1
+ORDER BY id DESC
+LIMIT ?2
+`
+
+type ListPasswordHistoryParams struct {
+	AccountID int64
+	Limit     int64
+}
+
+func (q *Queries) ListPasswordHistory(ctx context.Context, arg ListPasswordHistoryParams) ([][]byte, error) {
+	rows, err := q.db.QueryContext(ctx, listPasswordHistory, arg.AccountID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items [][]byte
+	for rows.Next() {
+		var password_hash []byte
+		if err := rows.Scan(&password_hash); err != nil {
+			return nil, err
+		}
+		items = append(items, password_hash)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPermissionsForAccount = `-- name: ListPermissionsForAccount :many
 SELECT DISTINCT rp.permission, ra.scope_type, ra.scope_resource
 FROM role_assignments ra
@@ -842,6 +1020,29 @@ func (q *Queries) RotateServiceAccountSecret(ctx context.Context, arg RotateServ
 	return err
 }
 
+const trimPasswordHistory = `-- name: TrimPasswordHistory :execrows
+DELETE FROM user_password_history
+WHERE account_id = ?1
+  AND id NOT IN (SELECT id
+                 FROM user_password_history
+                 WHERE account_id = ?1
+                 ORDER BY id DESC
+                 LIMIT ?2)
+`
+
+type TrimPasswordHistoryParams struct {
+	AccountID int64
+	Keep      int64
+}
+
+func (q *Queries) TrimPasswordHistory(ctx context.Context, arg TrimPasswordHistoryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trimPasswordHistory, arg.AccountID, arg.Keep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateAccountDescription = `-- name: UpdateAccountDescription :exec
 UPDATE accounts
 SET description = ?1
@@ -945,3 +1146,91 @@ func (q *Queries) UpdateRoleDisplayName(ctx context.Context, arg UpdateRoleDispl
 	}
 	return result.RowsAffected()
 }
+
+const updateUserTotpLastUsedStep = `-- name: UpdateUserTotpLastUsedStep :execrows
+UPDATE user_totp
+SET last_used_step = ?1
+WHERE account_id = ?2
+  AND last_used_step < ?1
+`
+
+type UpdateUserTotpLastUsedStepParams struct {
+	LastUsedStep int64
+	AccountID    int64
+}
+
+func (q *Queries) UpdateUserTotpLastUsedStep(ctx context.Context, arg UpdateUserTotpLastUsedStepParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserTotpLastUsedStep, arg.LastUsedStep, arg.AccountID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertUserLoginFailures = `-- name: UpsertUserLoginFailures :exec
+INSERT INTO user_login_failures (account_id, failed_attempts, lockout_count, last_failure_time, lockout_expire_time)
+VALUES (?1, ?2, ?3, ?4, ?5)
+ON CONFLICT (account_id) DO UPDATE
+SET failed_attempts     = excluded.failed_attempts,
+    lockout_count       = excluded.lockout_count,
+    last_failure_time   = excluded.last_failure_time,
+    lockout_expire_time = excluded.lockout_expire_time
+`
+
+type UpsertUserLoginFailuresParams struct {
+	AccountID         int64
+	FailedAttempts    int64
+	LockoutCount      int64
+	LastFailureTime   time.Time
+	LockoutExpireTime sql.NullTime
+}
+
+func (q *Queries) UpsertUserLoginFailures(ctx context.Context, arg UpsertUserLoginFailuresParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserLoginFailures,
+		arg.AccountID,
+		arg.FailedAttempts,
+		arg.LockoutCount,
+		arg.LastFailureTime,
+		arg.LockoutExpireTime,
+	)
+	return err
+}
+
+const upsertUserTotp = `-- name: UpsertUserTotp :exec
+INSERT INTO user_totp (account_id, secret, create_time)
+VALUES (?1, ?2, datetime('now', 'subsec'))
+ON CONFLICT (account_id) DO UPDATE
+SET secret         = excluded.secret,
+    confirmed      = FALSE,
+    last_used_step = 0,
+    create_time    = excluded.create_time
+`
+
+type UpsertUserTotpParams struct {
+	AccountID int64
+	Secret    []byte
+}
+
+func (q *Queries) UpsertUserTotp(ctx context.Context, arg UpsertUserTotpParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserTotp, arg.AccountID, arg.Secret)
+	return err
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+DELETE FROM user_recovery_codes
+WHERE account_id = ?1
+  AND code_hash = ?2
+`
+
+type UseRecoveryCodeParams struct {
+	AccountID int64
+	CodeHash  []byte
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useRecoveryCode, arg.AccountID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"math"
 	"regexp"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
@@ -15,6 +16,7 @@ import (
 	"github.com/smart-core-os/sc-bos/internal/auth/permission"
 	"github.com/smart-core-os/sc-bos/internal/sqlite"
 	"github.com/smart-core-os/sc-bos/internal/util/pass"
+	"github.com/smart-core-os/sc-bos/internal/util/totp"
 	"github.com/smart-core-os/sc-bos/pkg/gen"
 )
 
@@ -51,6 +53,13 @@ var (
 	ErrRoleScopedAssignment     = status.Error(codes.FailedPrecondition, "role cannot be used in a scoped assignment")
 	ErrResourceMissing          = status.Error(codes.InvalidArgument, "resource to create/update not supplied")
 	ErrGenerateSecret           = status.Error(codes.Internal, "failed to generate secret")
+	ErrUnexpectedMfa            = status.Error(codes.FailedPrecondition, "only user account can have multi-factor authentication")
+	ErrMfaAlreadyEnabled        = status.Error(codes.FailedPrecondition, "multi-factor authentication is already enabled")
+	ErrMfaNotEnabled            = status.Error(codes.FailedPrecondition, "multi-factor authentication is not enabled")
+	ErrMfaNotPending            = status.Error(codes.FailedPrecondition, "no pending TOTP enrollment")
+	ErrIncorrectCode            = status.Error(codes.FailedPrecondition, "incorrect one-time password")
+	ErrPasswordReused           = status.Error(codes.InvalidArgument, "password does not comply with policy: password has been used recently")
+	ErrAccountLocked            = status.Error(codes.FailedPrecondition, "account is temporarily locked")
 )
 
 type Server struct {
@@ -179,6 +188,11 @@ func (s *Server) CreateAccount(ctx context.Context, req *gen.CreateAccountReques
 		if !validateUsername(username) {
 			return nil, ErrInvalidUsername
 		}
+		if req.Password != "" {
+			if err := s.store.PasswordPolicy().Check(req.Password); err != nil {
+				return nil, err
+			}
+		}
 	case gen.Account_SERVICE_ACCOUNT:
 		// allow not providing a details value for service accounts because there are no required fields
 		// but still check that no other type of details is provided
@@ -417,8 +431,8 @@ func (s *Server) UpdateAccountPassword(ctx context.Context, req *gen.UpdateAccou
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
-	if !permitPassword(req.NewPassword) {
-		return nil, ErrInvalidPassword
+	if err := s.store.PasswordPolicy().Check(req.NewPassword); err != nil {
+		return nil, err
 	}
 
 	id, ok := parseID(req.Id)
@@ -496,6 +510,99 @@ func (s *Server) RotateAccountClientSecret(ctx context.Context, req *gen.RotateA
 	return &gen.RotateAccountClientSecretResponse{ClientSecret: secret}, nil
 }
 
+func (s *Server) BeginTotpEnrollment(ctx context.Context, req *gen.BeginTotpEnrollmentRequest) (*gen.BeginTotpEnrollmentResponse, error) {
+	id, ok := parseID(req.Id)
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+
+	var secret []byte
+	var uri string
+	err := s.store.Write(ctx, func(tx *Tx) error {
+		var err error
+		secret, uri, err = tx.BeginTotpEnrollment(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, s.processError(err, zap.String("rpc", "BeginTotpEnrollment"), zap.String("id", req.Id))
+	}
+
+	return &gen.BeginTotpEnrollmentResponse{
+		Secret: totp.EncodeSecret(secret),
+		Uri:    uri,
+	}, nil
+}
+
+func (s *Server) ConfirmTotpEnrollment(ctx context.Context, req *gen.ConfirmTotpEnrollmentRequest) (*gen.ConfirmTotpEnrollmentResponse, error) {
+	id, ok := parseID(req.Id)
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+
+	var recoveryCodes []string
+	err := s.store.Write(ctx, func(tx *Tx) error {
+		var err error
+		recoveryCodes, err = tx.ConfirmTotpEnrollment(ctx, id, req.Code, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, s.processError(err, zap.String("rpc", "ConfirmTotpEnrollment"), zap.String("id", req.Id))
+	}
+
+	return &gen.ConfirmTotpEnrollmentResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *Server) RegenerateRecoveryCodes(ctx context.Context, req *gen.RegenerateRecoveryCodesRequest) (*gen.RegenerateRecoveryCodesResponse, error) {
+	id, ok := parseID(req.Id)
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+
+	var recoveryCodes []string
+	err := s.store.Write(ctx, func(tx *Tx) error {
+		var err error
+		recoveryCodes, err = tx.RegenerateRecoveryCodes(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, s.processError(err, zap.String("rpc", "RegenerateRecoveryCodes"), zap.String("id", req.Id))
+	}
+
+	return &gen.RegenerateRecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *Server) ResetAccountMfa(ctx context.Context, req *gen.ResetAccountMfaRequest) (*gen.ResetAccountMfaResponse, error) {
+	id, ok := parseID(req.Id)
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+
+	err := s.store.Write(ctx, func(tx *Tx) error {
+		return tx.ResetMfa(ctx, id)
+	})
+	if err != nil {
+		return nil, s.processError(err, zap.String("rpc", "ResetAccountMfa"), zap.String("id", req.Id))
+	}
+
+	return &gen.ResetAccountMfaResponse{}, nil
+}
+
+func (s *Server) UnlockAccount(ctx context.Context, req *gen.UnlockAccountRequest) (*gen.UnlockAccountResponse, error) {
+	id, ok := parseID(req.Id)
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+
+	err := s.store.Write(ctx, func(tx *Tx) error {
+		return tx.UnlockAccount(ctx, id)
+	})
+	if err != nil {
+		return nil, s.processError(err, zap.String("rpc", "UnlockAccount"), zap.String("id", req.Id))
+	}
+
+	return &gen.UnlockAccountResponse{}, nil
+}
+
 func (s *Server) GetRole(ctx context.Context, req *gen.GetRoleRequest) (*gen.Role, error) {
 	id, ok := parseID(req.Id)
 	if !ok {
@@ -1009,7 +1116,7 @@ func (s *Server) GetAccountLimits(ctx context.Context, req *gen.GetAccountLimits
 			MaxLength: maxUsernameLength,
 		},
 		Password: &gen.AccountLimits_Field{
-			MinLength: minPasswordLength,
+			MinLength: int32(s.store.PasswordPolicy().minLength()),
 			MaxLength: maxPasswordLength,
 		},
 		DisplayName: &gen.AccountLimits_Field{
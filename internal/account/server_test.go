@@ -2,6 +2,7 @@ package account
 
 import (
 	"context"
+	"encoding/base32"
 	"errors"
 	"fmt"
 	"math/rand/v2"
@@ -20,6 +21,7 @@ import (
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/smart-core-os/sc-bos/internal/util/totp"
 	"github.com/smart-core-os/sc-bos/pkg/gen"
 )
 
@@ -1427,6 +1429,271 @@ func TestServer_RotateAccountClientSecret(t *testing.T) {
 	check(secret4, true)
 }
 
+func TestServer_TotpEnrollment(t *testing.T) {
+	ctx := context.Background()
+	logger := testLogger(t)
+	store := NewMemoryStore(logger)
+	server := NewServer(store, logger)
+
+	account, err := server.CreateAccount(ctx, &gen.CreateAccountRequest{
+		Account: &gen.Account{
+			Type:        gen.Account_USER_ACCOUNT,
+			DisplayName: "User 1",
+			Details:     &gen.Account_UserDetails{UserDetails: &gen.UserAccount{Username: "user1"}},
+		},
+		Password: "thepassword1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	id, _ := parseID(account.Id)
+
+	checkCode := func(code string, wantOK bool) {
+		t.Helper()
+		err := store.Write(ctx, func(tx *Tx) error {
+			return tx.CheckSecondFactor(ctx, id, code, time.Now())
+		})
+		switch {
+		case wantOK && err != nil:
+			t.Errorf("expected code %q to be accepted, got %v", code, err)
+		case !wantOK && !errors.Is(err, ErrIncorrectCode):
+			t.Errorf("expected code %q to be rejected, got %v", code, err)
+		}
+	}
+	checkEnabled := func(want bool) {
+		t.Helper()
+		got, err := server.GetAccount(ctx, &gen.GetAccountRequest{Id: account.Id})
+		if err != nil {
+			t.Fatalf("failed to get account: %v", err)
+		}
+		if got.GetUserDetails().GetTotpEnabled() != want {
+			t.Errorf("expected totp_enabled=%v, got %v", want, got.GetUserDetails().GetTotpEnabled())
+		}
+	}
+
+	begin, err := server.BeginTotpEnrollment(ctx, &gen.BeginTotpEnrollmentRequest{Id: account.Id})
+	if err != nil {
+		t.Fatalf("failed to begin enrollment: %v", err)
+	}
+	if !strings.HasPrefix(begin.Uri, "otpauth://totp/") {
+		t.Errorf("unexpected enrollment uri %q", begin.Uri)
+	}
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(begin.Secret)
+	if err != nil {
+		t.Fatalf("failed to decode secret: %v", err)
+	}
+	// pending enrollments are not enforced
+	checkEnabled(false)
+
+	_, err = server.ConfirmTotpEnrollment(ctx, &gen.ConfirmTotpEnrollmentRequest{Id: account.Id, Code: "000000"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition for incorrect code, got %v", err)
+	}
+	step := totp.Step(time.Now())
+	confirm, err := server.ConfirmTotpEnrollment(ctx, &gen.ConfirmTotpEnrollmentRequest{Id: account.Id, Code: totp.Code(secret, step)})
+	if err != nil {
+		t.Fatalf("failed to confirm enrollment: %v", err)
+	}
+	if len(confirm.RecoveryCodes) != recoveryCodeCount {
+		t.Errorf("expected %d recovery codes, got %d", recoveryCodeCount, len(confirm.RecoveryCodes))
+	}
+	checkEnabled(true)
+
+	_, err = server.BeginTotpEnrollment(ctx, &gen.BeginTotpEnrollmentRequest{Id: account.Id})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition when already enrolled, got %v", err)
+	}
+
+	// the code used to confirm can't be reused
+	checkCode(totp.Code(secret, step), false)
+	checkCode(totp.Code(secret, step+1), true)
+	checkCode(totp.Code(secret, step+1), false)
+	checkCode("", false)
+
+	// recovery codes are single use, and formatting is ignored
+	recovery := confirm.RecoveryCodes[0]
+	checkCode(strings.ToLower(recovery), true)
+	checkCode(recovery, false)
+
+	regen, err := server.RegenerateRecoveryCodes(ctx, &gen.RegenerateRecoveryCodesRequest{Id: account.Id})
+	if err != nil {
+		t.Fatalf("failed to regenerate recovery codes: %v", err)
+	}
+	checkCode(confirm.RecoveryCodes[1], false)
+	checkCode(regen.RecoveryCodes[0], true)
+
+	_, err = server.ResetAccountMfa(ctx, &gen.ResetAccountMfaRequest{Id: account.Id})
+	if err != nil {
+		t.Fatalf("failed to reset mfa: %v", err)
+	}
+	checkEnabled(false)
+	checkCode(regen.RecoveryCodes[1], false)
+
+	_, err = server.ResetAccountMfa(ctx, &gen.ResetAccountMfaRequest{Id: "999"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound resetting missing account, got %v", err)
+	}
+
+	service, err := server.CreateAccount(ctx, &gen.CreateAccountRequest{
+		Account: &gen.Account{Type: gen.Account_SERVICE_ACCOUNT, DisplayName: "Service"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	_, err = server.BeginTotpEnrollment(ctx, &gen.BeginTotpEnrollmentRequest{Id: service.Id})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition for service account, got %v", err)
+	}
+}
+
+func TestServer_PasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+	logger := testLogger(t)
+	store := NewMemoryStore(logger)
+	store.SetPasswordPolicy(PasswordPolicy{
+		MinLength:           12,
+		MinCharacterClasses: 3,
+		Denylist:            map[string]struct{}{"password1234": {}},
+		History:             3,
+	})
+	server := NewServer(store, logger)
+
+	limits, err := server.GetAccountLimits(ctx, &gen.GetAccountLimitsRequest{})
+	if err != nil {
+		t.Fatalf("failed to get limits: %v", err)
+	}
+	if got := limits.Password.MinLength; got != 12 {
+		t.Errorf("expected min password length 12, got %d", got)
+	}
+
+	createUser := func(password string) (*gen.Account, error) {
+		return server.CreateAccount(ctx, &gen.CreateAccountRequest{
+			Account: &gen.Account{
+				Type:        gen.Account_USER_ACCOUNT,
+				DisplayName: "User 1",
+				Details:     &gen.Account_UserDetails{UserDetails: &gen.UserAccount{Username: "user1"}},
+			},
+			Password: password,
+		})
+	}
+	for _, password := range []string{"Short1pass", "alllowercaseletters", "PassWord1234"} {
+		if _, err := createUser(password); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("CreateAccount(%q) expected InvalidArgument, got %v", password, err)
+		}
+	}
+	account, err := createUser("Password-0001")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	updatePassword := func(password string) error {
+		_, err := server.UpdateAccountPassword(ctx, &gen.UpdateAccountPasswordRequest{Id: account.Id, NewPassword: password})
+		return err
+	}
+	if err := updatePassword("Password-0001"); !errors.Is(err, ErrPasswordReused) {
+		t.Errorf("expected current password to be rejected, got %v", err)
+	}
+	for _, password := range []string{"Password-0002", "Password-0003", "Password-0004"} {
+		if err := updatePassword(password); err != nil {
+			t.Fatalf("failed to update password to %q: %v", password, err)
+		}
+	}
+	// the last 3 passwords can't be used again
+	for _, password := range []string{"Password-0002", "Password-0003", "Password-0004"} {
+		if err := updatePassword(password); !errors.Is(err, ErrPasswordReused) {
+			t.Errorf("expected %q to be rejected, got %v", password, err)
+		}
+	}
+	if err := updatePassword("Password-0001"); err != nil {
+		t.Errorf("expected password outside history to be allowed, got %v", err)
+	}
+}
+
+func TestTx_RecordLoginFailure(t *testing.T) {
+	ctx := context.Background()
+	logger := testLogger(t)
+	store := NewMemoryStore(logger)
+	server := NewServer(store, logger)
+
+	account, err := server.CreateAccount(ctx, &gen.CreateAccountRequest{
+		Account: &gen.Account{
+			Type:        gen.Account_USER_ACCOUNT,
+			DisplayName: "User 1",
+			Details:     &gen.Account_UserDetails{UserDetails: &gen.UserAccount{Username: "user1"}},
+		},
+		Password: "thepassword1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	id, _ := parseID(account.Id)
+
+	policy := LockoutPolicy{MaxFailures: 3, Duration: time.Minute, MaxDuration: 3 * time.Minute, FailureWindow: 10 * time.Minute}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	fail := func() time.Time {
+		t.Helper()
+		var lockedUntil time.Time
+		err := store.Write(ctx, func(tx *Tx) error {
+			var err error
+			lockedUntil, err = tx.RecordLoginFailure(ctx, id, now, policy)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("failed to record failure: %v", err)
+		}
+		return lockedUntil
+	}
+	checkLocked := func(want bool) {
+		t.Helper()
+		err := store.Read(ctx, func(tx *Tx) error {
+			return tx.CheckLockout(ctx, id, now)
+		})
+		if got := errors.Is(err, ErrAccountLocked); got != want {
+			t.Errorf("expected locked=%v, got %v", want, err)
+		}
+	}
+
+	// each consecutive lockout is longer, up to the max
+	for _, wantDuration := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		fail()
+		fail()
+		checkLocked(false)
+		lockedUntil := fail()
+		if want := now.Add(wantDuration); !lockedUntil.Equal(want) {
+			t.Errorf("expected lockout until %v, got %v", want, lockedUntil)
+		}
+		checkLocked(true)
+		now = lockedUntil
+		checkLocked(false)
+	}
+
+	// failures are forgotten after the window
+	now = now.Add(policy.FailureWindow + time.Second)
+	fail()
+	fail()
+	if lockedUntil := fail(); !lockedUntil.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected lockout to start again after window, got %v", lockedUntil)
+	}
+
+	got, err := server.GetAccount(ctx, &gen.GetAccountRequest{Id: account.Id})
+	if err != nil {
+		t.Fatalf("failed to get account: %v", err)
+	}
+	if got := got.GetUserDetails().GetLockoutExpireTime(); !got.AsTime().Equal(now.Add(time.Minute)) {
+		t.Errorf("expected lockout_expire_time %v, got %v", now.Add(time.Minute), got.AsTime())
+	}
+
+	_, err = server.UnlockAccount(ctx, &gen.UnlockAccountRequest{Id: account.Id})
+	if err != nil {
+		t.Fatalf("failed to unlock account: %v", err)
+	}
+	checkLocked(false)
+	_, err = server.UnlockAccount(ctx, &gen.UnlockAccountRequest{Id: "999"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound unlocking missing account, got %v", err)
+	}
+}
+
 func TestServer_Role(t *testing.T) {
 	// tests a sequence of role operations, checking that role lifecycles are handled correctly
 
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"io"
 	"math"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -23,7 +24,8 @@ import (
 const appID = 0x5C0501
 
 type Store struct {
-	db *sqlite.Database
+	db     *sqlite.Database
+	policy atomic.Pointer[PasswordPolicy]
 }
 
 func OpenStore(ctx context.Context, path string, logger *zap.Logger) (*Store, error) {
@@ -64,31 +66,51 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// SetPasswordPolicy changes the policy applied to new passwords.
+// Existing passwords are not affected.
+func (s *Store) SetPasswordPolicy(policy PasswordPolicy) {
+	s.policy.Store(&policy)
+}
+
+// PasswordPolicy returns the policy applied to new passwords.
+func (s *Store) PasswordPolicy() PasswordPolicy {
+	if p := s.policy.Load(); p != nil {
+		return *p
+	}
+	return PasswordPolicy{}
+}
+
 func (s *Store) Read(ctx context.Context, f func(tx *Tx) error) error {
 	return s.db.ReadTx(ctx, func(tx *sql.Tx) error {
-		storeTx := &Tx{Queries: queries.New(tx)}
+		storeTx := &Tx{Queries: queries.New(tx), policy: s.PasswordPolicy()}
 		return f(storeTx)
 	})
 }
 
 func (s *Store) Write(ctx context.Context, f func(tx *Tx) error) error {
 	return s.db.WriteTx(ctx, func(tx *sql.Tx) error {
-		storeTx := &Tx{Queries: queries.New(tx)}
+		storeTx := &Tx{Queries: queries.New(tx), policy: s.PasswordPolicy()}
 		return f(storeTx)
 	})
 }
 
 type Tx struct {
 	*queries.Queries
+	policy PasswordPolicy
 }
 
+// UpdateAccountPassword sets the password of a user account.
+// The password must comply with the store's password policy, including not being a recently used password.
 func (tx *Tx) UpdateAccountPassword(ctx context.Context, accountID int64, password string) error {
+	if err := tx.policy.Check(password); err != nil {
+		return err
+	}
 	hash, err := hashPassword(password)
 	if err != nil {
 		return err
 	}
 
-	account, err := tx.GetAccount(ctx, accountID)
+	account, err := tx.GetAccountDetails(ctx, accountID)
 	if errors.Is(err, sql.ErrNoRows) {
 		return ErrAccountNotFound
 	} else if err != nil {
@@ -97,6 +119,12 @@ func (tx *Tx) UpdateAccountPassword(ctx context.Context, accountID int64, passwo
 	if account.Type != gen.Account_USER_ACCOUNT.String() {
 		return ErrUnexpectedPasswordUpdate
 	}
+	if err := tx.checkPasswordHistory(ctx, account.PasswordHash, accountID, password); err != nil {
+		return err
+	}
+	if err := tx.recordPasswordHistory(ctx, accountID, account.PasswordHash); err != nil {
+		return err
+	}
 
 	return tx.UpdateAccountPasswordHash(ctx, queries.UpdateAccountPasswordHashParams{
 		AccountID:    accountID,
@@ -0,0 +1,375 @@
+// Package auditdb provides a SQLite-based store for audit events.
+package auditdb
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/internal/sqlite"
+)
+
+const appID = 0x5C0504
+
+//go:embed schema/*.sql
+var schemaVersionsFS embed.FS
+var schema = sqlite.MustLoadVersionedSchema(schemaVersionsFS, "schema")
+
+func init() {
+	sqlite.RegisterSchema("audit", appID, schema)
+}
+
+// DB is a store for Records.
+type DB struct {
+	db     *sqlite.Database
+	logger *zap.Logger
+	now    func() time.Time
+
+	trimInterval  time.Duration
+	retentionAge  time.Duration
+	retentionSize int64
+	lastTrimMu    sync.Mutex
+	lastTrimTime  time.Time
+}
+
+// Open opens, or creates, the audit database at path.
+func Open(ctx context.Context, path string, options ...Option) (*DB, error) {
+	o := resolveOpts(options...)
+
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("mkdir: %w", err)
+	}
+	db, err := sqlite.Open(ctx, path,
+		sqlite.WithApplicationID(appID),
+		sqlite.WithLogger(o.logger),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return newDB(ctx, db, o)
+}
+
+// OpenMemory opens an in-memory audit database.
+// All records are lost when the DB is closed.
+func OpenMemory(ctx context.Context, options ...Option) (*DB, error) {
+	o := resolveOpts(options...)
+	db := sqlite.OpenMemory(
+		sqlite.WithApplicationID(appID),
+		sqlite.WithLogger(o.logger),
+	)
+	return newDB(ctx, db, o)
+}
+
+func newDB(ctx context.Context, db *sqlite.Database, o opts) (*DB, error) {
+	err := db.Migrate(ctx, schema)
+	if err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	return &DB{
+		db:            db,
+		logger:        o.logger,
+		now:           o.now,
+		trimInterval:  o.trimInterval,
+		retentionAge:  o.maxAge,
+		retentionSize: o.maxCount,
+	}, nil
+}
+
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Insert adds record to the database, returning the record with ID populated.
+// If record.RecordTime is zero the current time will be used.
+//
+// Records older than the retention period of the database may be removed as part of the insert.
+func (d *DB) Insert(ctx context.Context, record Record) (Record, error) {
+	if record.RecordTime.IsZero() {
+		record.RecordTime = d.now()
+	}
+	err := d.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO audit_events (record_time, actor, service, method, resource, code, payload) VALUES (?, ?, ?, ?, ?, ?, ?);",
+			record.RecordTime.UnixMilli(), record.Actor, record.Service, record.Method, record.Resource, record.Code, record.Payload)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		record.ID = RecordID(id)
+		return nil
+	})
+	if err != nil {
+		return Record{}, err
+	}
+	record.RecordTime = time.UnixMilli(record.RecordTime.UnixMilli()) // truncated and without time zone
+	d.trimAfterWrite(ctx)
+	return record, nil
+}
+
+// Read reads records matching q into the provided slice.
+// If after is non-zero, only records after (or before if desc is true) that record are returned.
+// Returns number of records read and any error encountered.
+// At most len(into) records will be read.
+func (d *DB) Read(ctx context.Context, q Query, after RecordID, desc bool, into []Record) (n int, err error) {
+	if len(into) == 0 {
+		return 0, nil
+	}
+	err = d.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		filters, args := q.filters()
+		order := "ASC"
+		if after != 0 {
+			if desc {
+				filters = append(filters, "id < ?")
+			} else {
+				filters = append(filters, "id > ?")
+			}
+			args = append(args, after)
+		}
+		if desc {
+			order = "DESC"
+		}
+		args = append(args, len(into))
+
+		query := fmt.Sprintf(`
+			SELECT id, record_time, actor, service, method, resource, code, payload
+			FROM audit_events
+			WHERE %s
+			ORDER BY id %s
+			LIMIT ?;
+			`, joinFilters(filters), order)
+
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		for rows.Next() {
+			var r Record
+			var recordTime int64
+			err = rows.Scan(&r.ID, &recordTime, &r.Actor, &r.Service, &r.Method, &r.Resource, &r.Code, &r.Payload)
+			if err != nil {
+				return err
+			}
+			r.RecordTime = time.UnixMilli(recordTime)
+			into[n] = r
+			n++
+		}
+		return rows.Err()
+	})
+	return n, err
+}
+
+// Count returns the number of records that match q.
+func (d *DB) Count(ctx context.Context, q Query) (int, error) {
+	var count int
+	err := d.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		filters, args := q.filters()
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM audit_events WHERE %s;`, joinFilters(filters))
+		return tx.QueryRowContext(ctx, query, args...).Scan(&count)
+	})
+	return count, err
+}
+
+// TrimOptions specifies criteria for trimming records from the database.
+type TrimOptions struct {
+	// If non-zero, ensures at most this many records are retained.
+	MaxCount int64
+	// When non-zero, records before this time are deleted.
+	Before time.Time
+}
+
+func (o TrimOptions) IsZero() bool {
+	return o.MaxCount == 0 && o.Before.IsZero()
+}
+
+// Trim removes records from the database according to the provided options.
+// Returns the number of records removed.
+func (d *DB) Trim(ctx context.Context, opts TrimOptions) (int64, error) {
+	if opts.IsZero() {
+		return 0, nil
+	}
+	var deleted int64
+	err := d.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		if !opts.Before.IsZero() {
+			res, err := tx.ExecContext(ctx, "DELETE FROM audit_events WHERE record_time < ?", opts.Before.UnixMilli())
+			if err != nil {
+				return err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			deleted += n
+		}
+		if opts.MaxCount > 0 {
+			res, err := tx.ExecContext(ctx, `
+				DELETE FROM audit_events
+				WHERE id <= (SELECT id FROM audit_events ORDER BY id DESC LIMIT 1 OFFSET ?)`, opts.MaxCount)
+			if err != nil {
+				return err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			deleted += n
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// trimAfterWrite applies the retention policy of the DB, at most once per trimInterval.
+func (d *DB) trimAfterWrite(ctx context.Context) {
+	if d.retentionAge == 0 && d.retentionSize == 0 {
+		return
+	}
+	now := d.now()
+	d.lastTrimMu.Lock()
+	if now.Sub(d.lastTrimTime) < d.trimInterval {
+		d.lastTrimMu.Unlock()
+		return
+	}
+	d.lastTrimTime = now
+	d.lastTrimMu.Unlock()
+
+	opts := TrimOptions{MaxCount: d.retentionSize}
+	if d.retentionAge > 0 {
+		opts.Before = now.Add(-d.retentionAge)
+	}
+	n, err := d.Trim(ctx, opts)
+	if err != nil {
+		d.logger.Warn("failed to trim audit events", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		d.logger.Debug("trimmed audit events", zap.Int64("count", n))
+	}
+}
+
+// Query filters the records returned by Read and Count.
+// Zero fields do not filter records.
+type Query struct {
+	From, To       time.Time // From <= RecordTime < To
+	Actor          string
+	Resource       string
+	ResourcePrefix string
+	Service        string
+	Method         string
+	Success        *bool
+}
+
+func (q Query) filters() ([]string, []any) {
+	var filters []string
+	var args []any
+	if !q.From.IsZero() {
+		filters = append(filters, "record_time >= ?")
+		args = append(args, q.From.UnixMilli())
+	}
+	if !q.To.IsZero() {
+		filters = append(filters, "record_time < ?")
+		args = append(args, q.To.UnixMilli())
+	}
+	if q.Actor != "" {
+		filters = append(filters, "actor = ?")
+		args = append(args, q.Actor)
+	}
+	if q.Resource != "" {
+		filters = append(filters, "resource = ?")
+		args = append(args, q.Resource)
+	}
+	if q.ResourcePrefix != "" {
+		// a range over the bytes of resource, which unlike LIKE is case-sensitive and can use the index
+		filters = append(filters, "resource >= ?")
+		args = append(args, q.ResourcePrefix)
+		if end, ok := prefixEnd(q.ResourcePrefix); ok {
+			filters = append(filters, "resource < ?")
+			args = append(args, end)
+		}
+	}
+	if q.Service != "" {
+		filters = append(filters, "service = ?")
+		args = append(args, q.Service)
+	}
+	if q.Method != "" {
+		filters = append(filters, "method = ?")
+		args = append(args, q.Method)
+	}
+	if q.Success != nil {
+		if *q.Success {
+			filters = append(filters, "code = 0")
+		} else {
+			filters = append(filters, "code != 0")
+		}
+	}
+	return filters, args
+}
+
+// prefixEnd returns the smallest string that sorts after every string starting with prefix.
+// Returns false if there is no such string, i.e. prefix consists only of 0xff bytes.
+func prefixEnd(prefix string) (string, bool) {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1]), true
+		}
+	}
+	return "", false
+}
+
+func joinFilters(filters []string) string {
+	if len(filters) == 0 {
+		return "1 = 1" // no filtering, return a dummy condition
+	}
+	return strings.Join(filters, " AND ")
+}
+
+// Record is a single audit event as stored in the database.
+// The indexed fields are duplicated from Payload to support filtering.
+type Record struct {
+	ID         RecordID
+	RecordTime time.Time
+	Actor      string
+	Service    string
+	Method     string
+	Resource   string
+	Code       int32
+	Payload    []byte // the full event, typically a binary gen.AuditEvent
+}
+
+// RecordID uniquely identifies a Record in the database.
+// Record IDs increase as records are inserted.
+type RecordID int64
+
+func ParseRecordID(s string) (RecordID, error) {
+	id, err := strconv.ParseInt(s, 16, 64)
+	if err != nil || id < 0 {
+		return 0, ErrInvalidRecordID
+	}
+	return RecordID(id), nil
+}
+
+func (id RecordID) String() string {
+	return fmt.Sprintf("%016X", int64(id))
+}
+
+var ErrInvalidRecordID = errors.New("invalid record ID format")
@@ -0,0 +1,163 @@
+package auditdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDB_Read(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+	db := openTestDB(t, WithNow(func() time.Time { return now }))
+
+	insert := func(actor, resource string, code int32) Record {
+		t.Helper()
+		now = now.Add(time.Second)
+		r, err := db.Insert(t.Context(), Record{
+			Actor:    actor,
+			Service:  "smartcore.traits.OnOffApi",
+			Method:   "UpdateOnOff",
+			Resource: resource,
+			Code:     code,
+			Payload:  []byte(actor + resource),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	all := []Record{
+		insert("alice", "floor1/light1", 0),
+		insert("bob", "floor1/light2", 0),
+		insert("alice", "floor2/light1", 7),
+		insert("bob", "floor2/light2", 0),
+		insert("carol", "café/light1", 0),
+	}
+
+	read := func(q Query, after RecordID, desc bool, size int) []Record {
+		t.Helper()
+		got := make([]Record, size)
+		n, err := db.Read(t.Context(), q, after, desc, got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got[:n]
+	}
+	yes, no := true, false
+
+	tests := []struct {
+		name  string
+		q     Query
+		after RecordID
+		desc  bool
+		size  int
+		want  []Record
+	}{
+		{name: "all", size: 10, want: all},
+		{name: "page", size: 2, want: all[:2]},
+		{name: "page after", after: all[1].ID, size: 2, want: all[2:4]},
+		{name: "desc", desc: true, size: 2, want: []Record{all[4], all[3]}},
+		{name: "desc after", after: all[2].ID, desc: true, size: 10, want: []Record{all[1], all[0]}},
+		{name: "actor", q: Query{Actor: "alice"}, size: 10, want: []Record{all[0], all[2]}},
+		{name: "resource", q: Query{Resource: "floor1/light2"}, size: 10, want: []Record{all[1]}},
+		{name: "resource prefix", q: Query{ResourcePrefix: "floor2/"}, size: 10, want: all[2:4]},
+		{name: "resource prefix multibyte", q: Query{ResourcePrefix: "café/"}, size: 10, want: []Record{all[4]}},
+		{name: "resource prefix case", q: Query{ResourcePrefix: "Floor2/"}, size: 10, want: []Record{}},
+		{name: "success", q: Query{Success: &yes}, size: 10, want: []Record{all[0], all[1], all[3], all[4]}},
+		{name: "failure", q: Query{Success: &no}, size: 10, want: []Record{all[2]}},
+		{name: "time", q: Query{From: all[1].RecordTime, To: all[3].RecordTime}, size: 10, want: all[1:3]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := read(tt.q, tt.after, tt.desc, tt.size)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Read (-want,+got)\n%s", diff)
+			}
+			count, err := db.Count(t.Context(), tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.after == 0 && tt.size >= len(all) && count != len(tt.want) {
+				t.Errorf("Count got %d, want %d", count, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestDB_Trim(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+	db := openTestDB(t, WithNow(func() time.Time { return now }))
+	var all []Record
+	for range 5 {
+		now = now.Add(time.Minute)
+		r, err := db.Insert(t.Context(), Record{Actor: "alice", Payload: []byte{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		all = append(all, r)
+	}
+
+	n, err := db.Trim(t.Context(), TrimOptions{Before: all[1].RecordTime})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("Trim(Before) removed %d, want 1", n)
+	}
+	n, err = db.Trim(t.Context(), TrimOptions{MaxCount: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("Trim(MaxCount) removed %d, want 2", n)
+	}
+	got := make([]Record, 10)
+	c, err := db.Read(t.Context(), Query{}, 0, false, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(all[3:], got[:c]); diff != "" {
+		t.Errorf("remaining records (-want,+got)\n%s", diff)
+	}
+}
+
+func TestDB_retention(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+	db := openTestDB(t,
+		WithNow(func() time.Time { return now }),
+		WithRetention(time.Hour, 0),
+	)
+	old, err := db.Insert(t.Context(), Record{Actor: "alice", Payload: []byte{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Hour)
+	recent, err := db.Insert(t.Context(), Record{Actor: "bob", Payload: []byte{2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]Record, 10)
+	n, err := db.Read(t.Context(), Query{}, 0, false, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]Record{recent}, got[:n]); diff != "" {
+		t.Errorf("expected %v to be trimmed (-want,+got)\n%s", old.ID, diff)
+	}
+}
+
+func openTestDB(t *testing.T, opts ...Option) *DB {
+	t.Helper()
+	db, err := OpenMemory(t.Context(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return db
+}
@@ -0,0 +1,57 @@
+package auditdb
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type opts struct {
+	logger       *zap.Logger
+	now          func() time.Time
+	maxAge       time.Duration
+	maxCount     int64
+	trimInterval time.Duration
+}
+
+func resolveOpts(options ...Option) opts {
+	o := opts{
+		trimInterval: time.Minute,
+	}
+	for _, option := range options {
+		option(&o)
+	}
+	if o.logger == nil {
+		o.logger = zap.NewNop()
+	}
+	if o.now == nil {
+		o.now = time.Now
+	}
+	return o
+}
+
+type Option func(*opts)
+
+// WithLogger is an option to set the logger used by the store.
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *opts) {
+		o.logger = logger
+	}
+}
+
+// WithRetention is an option to set how long records are kept for.
+// Records older than maxAge, or beyond the newest maxCount records, are removed after writing.
+// A zero value for either argument means no limit.
+func WithRetention(maxAge time.Duration, maxCount int64) Option {
+	return func(o *opts) {
+		o.maxAge = maxAge
+		o.maxCount = maxCount
+	}
+}
+
+// WithNow is an option to set the clock used by the store.
+func WithNow(now func() time.Time) Option {
+	return func(o *opts) {
+		o.now = now
+	}
+}
@@ -0,0 +1,18 @@
+CREATE TABLE audit_events
+(
+    id          INTEGER PRIMARY KEY,
+    -- unix milliseconds
+    record_time INTEGER NOT NULL,
+    actor       TEXT    NOT NULL,
+    service     TEXT    NOT NULL,
+    method      TEXT    NOT NULL,
+    resource    TEXT    NOT NULL,
+    -- gRPC status code of the result, 0 means success
+    code        INTEGER NOT NULL,
+    -- A binary proto message representing the full event.
+    payload     BLOB    NOT NULL
+);
+
+CREATE INDEX audit_events_record_time_idx ON audit_events (record_time);
+CREATE INDEX audit_events_actor_idx ON audit_events (actor, id);
+CREATE INDEX audit_events_resource_idx ON audit_events (resource, id);
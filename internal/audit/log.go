@@ -0,0 +1,108 @@
+// Package audit records and serves the audit log of a node.
+//
+// A Log persists audit events to an auditdb.DB and notifies any listeners of new events.
+// Use a Server to expose the Log via the AuditApi.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/internal/audit/auditdb"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// Log records audit events.
+type Log struct {
+	db *auditdb.DB
+
+	listenersMu sync.Mutex
+	listeners   map[chan *gen.AuditEvent]struct{}
+}
+
+func NewLog(db *auditdb.DB) *Log {
+	return &Log{db: db}
+}
+
+// Record saves event to the log.
+// The id of event is ignored, and record_time is set if absent.
+func (l *Log) Record(ctx context.Context, event *gen.AuditEvent) error {
+	event = proto.Clone(event).(*gen.AuditEvent)
+	event.Id = ""
+	record := auditdb.Record{
+		Actor:    event.GetActor().GetSubject(),
+		Service:  event.GetService(),
+		Method:   event.GetMethod(),
+		Resource: event.GetResource(),
+		Code:     event.GetResult().GetCode(),
+	}
+	if event.RecordTime != nil {
+		record.RecordTime = event.RecordTime.AsTime()
+	}
+	event.RecordTime = nil // recorded in the table
+	payload, err := proto.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	record.Payload = payload
+	record, err = l.db.Insert(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	event.Id = record.ID.String()
+	event.RecordTime = timestamppb.New(record.RecordTime)
+	l.notify(event)
+	return nil
+}
+
+// listenerBuffer is how many events can be waiting for each listener before new events are dropped.
+const listenerBuffer = 100
+
+// notify sends event to all listeners without blocking.
+// Slow listeners must not hold up the request being audited, so listeners that can't keep up miss events,
+// they can be recovered via List.
+func (l *Log) notify(event *gen.AuditEvent) {
+	l.listenersMu.Lock()
+	defer l.listenersMu.Unlock()
+	for ch := range l.listeners {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Listen returns a chan that emits newly recorded events until ctx is done.
+func (l *Log) Listen(ctx context.Context) <-chan *gen.AuditEvent {
+	ch := make(chan *gen.AuditEvent, listenerBuffer)
+	l.listenersMu.Lock()
+	if l.listeners == nil {
+		l.listeners = make(map[chan *gen.AuditEvent]struct{})
+	}
+	l.listeners[ch] = struct{}{}
+	l.listenersMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		l.listenersMu.Lock()
+		defer l.listenersMu.Unlock()
+		delete(l.listeners, ch)
+		close(ch)
+	}()
+	return ch
+}
+
+func decodeRecord(r auditdb.Record) (*gen.AuditEvent, error) {
+	event := &gen.AuditEvent{}
+	if err := proto.Unmarshal(r.Payload, event); err != nil {
+		return nil, err
+	}
+	event.Id = r.ID.String()
+	event.RecordTime = timestamppb.New(r.RecordTime)
+	return event, nil
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/internal/audit/auditdb"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+func TestLog_Record_slowListener(t *testing.T) {
+	ctx := context.Background()
+	db, err := auditdb.OpenMemory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	log := NewLog(db)
+
+	listenCtx, stopListening := context.WithCancel(ctx)
+	defer stopListening()
+	events := log.Listen(listenCtx) // never read until all events are recorded
+
+	const n = listenerBuffer + 10
+	start := time.Now()
+	for range n {
+		if err := log.Record(ctx, &gen.AuditEvent{Resource: "a", Service: "smartcore.traits.OnOffApi", Method: "UpdateOnOff"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// blocking on the listener for even a fraction of a second per event would take much longer than this
+	if d := time.Since(start); d > 30*time.Second {
+		t.Errorf("recording %d events took %v, slow listeners should not block Record", n, d)
+	}
+
+	stopListening()
+	var got int
+	for range events {
+		got++
+	}
+	if got != listenerBuffer {
+		t.Errorf("listener got %d events, want %d", got, listenerBuffer)
+	}
+}
@@ -0,0 +1,232 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/smart-core-os/sc-bos/internal/audit/auditdb"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/historypb"
+	"github.com/smart-core-os/sc-golang/pkg/masks"
+)
+
+// These are the same as those in the historypb package.
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// Server is a [gen.AuditApiServer] that serves events from a Log.
+type Server struct {
+	gen.UnimplementedAuditApiServer
+	log *Log
+}
+
+func NewServer(log *Log) *Server {
+	return &Server{log: log}
+}
+
+func (s *Server) ListAuditEvents(ctx context.Context, req *gen.ListAuditEventsRequest) (*gen.ListAuditEventsResponse, error) {
+	q := parseQuery(req.GetQuery())
+	desc, err := parseOrderBy(req.GetOrderBy())
+	if err != nil {
+		return nil, err
+	}
+	queryHash, err := hashQuery(req.GetQuery(), desc)
+	if err != nil {
+		return nil, err
+	}
+	after, pageSize, totalSize, err := parsePageInfo(req, queryHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// avoid counting if we already know the total size
+	if totalSize == 0 {
+		t, err := s.log.db.Count(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		totalSize = int32(t)
+	}
+
+	buf := make([]auditdb.Record, pageSize+1) // +1 to detect if there's a next page
+	n, err := s.log.db.Read(ctx, q, after, desc, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &gen.ListAuditEventsResponse{
+		TotalSize: totalSize,
+	}
+	if n == len(buf) {
+		n-- // don't include the extra record in the results
+		token, err := marshalToken(&historypb.PageToken{RecordId: buf[n-1].ID.String(), TotalSize: totalSize, QueryHash: queryHash})
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	filter := masks.NewResponseFilter(masks.WithFieldMask(req.GetReadMask()))
+	res.AuditEvents = make([]*gen.AuditEvent, n)
+	for i := range n {
+		event, err := decodeRecord(buf[i])
+		if err != nil {
+			return nil, err
+		}
+		res.AuditEvents[i] = filter.FilterClone(event).(*gen.AuditEvent)
+	}
+	return res, nil
+}
+
+func (s *Server) PullAuditEvents(req *gen.PullAuditEventsRequest, server gen.AuditApi_PullAuditEventsServer) error {
+	filter := masks.NewResponseFilter(masks.WithFieldMask(req.GetReadMask()))
+	q := req.GetQuery()
+	for event := range s.log.Listen(server.Context()) {
+		if !matchesQuery(q, event) {
+			continue
+		}
+		change := &gen.PullAuditEventsResponse_Change{
+			Name:       req.GetName(),
+			AuditEvent: filter.FilterClone(event).(*gen.AuditEvent),
+			ChangeTime: event.GetRecordTime(),
+		}
+		err := server.Send(&gen.PullAuditEventsResponse{Changes: []*gen.PullAuditEventsResponse_Change{change}})
+		if err != nil {
+			return err
+		}
+	}
+	return server.Context().Err()
+}
+
+func parseQuery(q *gen.AuditEvent_Query) auditdb.Query {
+	dst := auditdb.Query{
+		Actor:          q.GetActor(),
+		Resource:       q.GetResource(),
+		ResourcePrefix: q.GetResourcePrefix(),
+		Service:        q.GetService(),
+		Method:         q.GetMethod(),
+	}
+	if q != nil && q.Success != nil {
+		success := q.GetSuccess()
+		dst.Success = &success
+	}
+	if t := q.GetRecordNotBefore(); t != nil {
+		dst.From = t.AsTime()
+	}
+	if t := q.GetRecordNotAfter(); t != nil {
+		// To is exclusive, but record_not_after is inclusive
+		dst.To = t.AsTime().Add(time.Millisecond)
+	}
+	return dst
+}
+
+// matchesQuery returns whether event would be included in the results of a query using q.
+// The record time bounds of q are ignored.
+func matchesQuery(q *gen.AuditEvent_Query, event *gen.AuditEvent) bool {
+	if q == nil {
+		return true
+	}
+	if q.Actor != "" && q.Actor != event.GetActor().GetSubject() {
+		return false
+	}
+	if q.Resource != "" && q.Resource != event.GetResource() {
+		return false
+	}
+	if q.ResourcePrefix != "" && !strings.HasPrefix(event.GetResource(), q.ResourcePrefix) {
+		return false
+	}
+	if q.Service != "" && q.Service != event.GetService() {
+		return false
+	}
+	if q.Method != "" && q.Method != event.GetMethod() {
+		return false
+	}
+	if q.Success != nil && q.GetSuccess() != (event.GetResult().GetCode() == int32(codes.OK)) {
+		return false
+	}
+	return true
+}
+
+func parseOrderBy(s string) (desc bool, _ error) {
+	sn := strings.ToLower(s)
+	sn = strings.Join(strings.Fields(sn), " ") // normalize spaces
+	switch sn {
+	case "", "recordtime", "recordtime asc", "record_time", "record_time asc":
+		return false, nil
+	case "recordtime desc", "record_time desc":
+		return true, nil
+	default:
+		return false, status.Errorf(codes.InvalidArgument, "unsupported order_by %q", s)
+	}
+}
+
+// hashQuery returns a hash identifying the results of a query, used to check the query doesn't change between pages.
+func hashQuery(q *gen.AuditEvent_Query, desc bool) ([]byte, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(q)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	if desc {
+		h.Write([]byte{1})
+	} else {
+		h.Write([]byte{0})
+	}
+	h.Write(data)
+	return h.Sum(nil)[:8], nil
+}
+
+func parsePageInfo(req *gen.ListAuditEventsRequest, queryHash []byte) (after auditdb.RecordID, pageSize, totalSize int32, _ error) {
+	pageToken, err := unmarshalToken(req.GetPageToken())
+	if err != nil {
+		return 0, 0, 0, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+	if req.GetPageToken() != "" && !bytes.Equal(pageToken.GetQueryHash(), queryHash) {
+		return 0, 0, 0, status.Error(codes.InvalidArgument, "query or order_by changed since page_token was issued")
+	}
+	if id := pageToken.GetRecordId(); id != "" {
+		after, err = auditdb.ParseRecordID(id)
+		if err != nil {
+			return 0, 0, 0, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+	totalSize = pageToken.GetTotalSize()
+	pageSize = req.GetPageSize()
+	switch {
+	case pageSize <= 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+	return after, pageSize, totalSize, nil
+}
+
+func unmarshalToken(token string) (*historypb.PageToken, error) {
+	if token == "" {
+		return &historypb.PageToken{}, nil
+	}
+	data, err := base64.RawStdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	pb := &historypb.PageToken{}
+	err = proto.Unmarshal(data, pb)
+	return pb, err
+}
+
+func marshalToken(pb *historypb.PageToken) (string, error) {
+	data, err := proto.Marshal(pb)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/internal/audit/auditdb"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+func TestServer_ListAuditEvents_paging(t *testing.T) {
+	ctx := context.Background()
+	db, err := auditdb.OpenMemory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	log := NewLog(db)
+	for _, resource := range []string{"a", "b", "a", "b", "a"} {
+		err := log.Record(ctx, &gen.AuditEvent{Resource: resource, Service: "smartcore.traits.OnOffApi", Method: "UpdateOnOff"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := NewServer(log)
+
+	query := &gen.AuditEvent_Query{Resource: "a"}
+	var got []string
+	req := &gen.ListAuditEventsRequest{PageSize: 2, Query: query}
+	for {
+		res, err := server.ListAuditEvents(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.TotalSize != 3 {
+			t.Fatalf("TotalSize = %d, want 3", res.TotalSize)
+		}
+		for _, e := range res.AuditEvents {
+			got = append(got, e.Resource)
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d events %v, want 3", len(got), got)
+	}
+
+	first, err := server.ListAuditEvents(ctx, &gen.ListAuditEventsRequest{PageSize: 2, Query: query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		req  *gen.ListAuditEventsRequest
+	}{
+		{"query changed", &gen.ListAuditEventsRequest{PageToken: first.NextPageToken, Query: &gen.AuditEvent_Query{Resource: "b"}}},
+		{"query removed", &gen.ListAuditEventsRequest{PageToken: first.NextPageToken}},
+		{"order changed", &gen.ListAuditEventsRequest{PageToken: first.NextPageToken, Query: query, OrderBy: "record_time desc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.ListAuditEvents(ctx, tt.req)
+			if code := status.Code(err); code != codes.InvalidArgument {
+				t.Fatalf("error = %v, want code %v", err, codes.InvalidArgument)
+			}
+		})
+	}
+}
@@ -16,7 +16,10 @@ import (
 	"github.com/smart-core-os/sc-bos/pkg/gen"
 )
 
-var ErrUnsupportedTokenVersion = errors.New("unsupported token version")
+var (
+	ErrUnsupportedTokenVersion = errors.New("unsupported token version")
+	ErrSessionRevoked          = errors.New("session revoked")
+)
 
 const claimsVersion = 1
 
@@ -25,6 +28,7 @@ type claims struct {
 	Name        string                 `json:"name,omitempty"`
 	SystemRoles []string               `json:"roles,omitempty"` // Named roles in JSON for back-compat
 	Permissions []permissionAssignment `json:"perms,omitempty"`
+	SessionID   string                 `json:"sid,omitempty"` // the session the token was issued for, if any
 }
 
 // like token.PermissionAssignment but with a more compact JSON representation
@@ -60,21 +64,27 @@ type Source struct {
 	Issuer              string
 	Now                 func() time.Time
 	SignatureAlgorithms []string
+	// SessionRevoked, if not nil, is used to reject tokens issued for sessions that have since been revoked.
+	SessionRevoked func(sessionID string) bool
 }
 
 func (ts *Source) GenerateAccessToken(data SecretData, validity time.Duration) (token string, err error) {
+	return ts.generateAccessToken(data, "", validity)
+}
+
+// GenerateSessionAccessToken is like GenerateAccessToken but associates the token with a session.
+// The token will no longer validate if SessionRevoked reports the session as revoked.
+func (ts *Source) GenerateSessionAccessToken(data SecretData, sessionID string, validity time.Duration) (token string, err error) {
+	return ts.generateAccessToken(data, sessionID, validity)
+}
+
+func (ts *Source) generateAccessToken(data SecretData, sessionID string, validity time.Duration) (token string, err error) {
 	signer, err := jose.NewSigner(ts.Key, nil)
 	if err != nil {
 		return "", err
 	}
 
-	var now time.Time
-	if ts.Now != nil {
-		now = ts.Now()
-	} else {
-		now = time.Now()
-	}
-
+	now := ts.now()
 	expires := now.Add(validity)
 
 	jwtClaims := jwt.Claims{
@@ -94,6 +104,7 @@ func (ts *Source) GenerateAccessToken(data SecretData, validity time.Duration) (
 		Name:        data.Title,
 		Permissions: compressedPermissions,
 		SystemRoles: data.SystemRoles,
+		SessionID:   sessionID,
 	}
 	return jwt.Signed(signer).
 		Claims(jwtClaims).
@@ -102,36 +113,57 @@ func (ts *Source) GenerateAccessToken(data SecretData, validity time.Duration) (
 }
 
 func (ts *Source) ValidateAccessToken(_ context.Context, tokenStr string) (*token.Claims, error) {
-	tok, err := jwt.ParseSigned(tokenStr, jose_utils.ConvertToNativeJose(ts.SignatureAlgorithms))
+	jwtClaims, customClaims, err := ts.parseAccessToken(tokenStr)
 	if err != nil {
 		return nil, err
 	}
+	if customClaims.SessionID != "" && ts.SessionRevoked != nil && ts.SessionRevoked(customClaims.SessionID) {
+		return nil, ErrSessionRevoked
+	}
+	tokenPermissions := make([]token.PermissionAssignment, 0, len(customClaims.Permissions))
+	for _, pa := range customClaims.Permissions {
+		tokenPermissions = append(tokenPermissions, pa.ToTokenPermissionAssignment())
+	}
+	return &token.Claims{
+		Subject:     jwtClaims.Subject,
+		Name:        customClaims.Name,
+		SystemRoles: customClaims.SystemRoles,
+		IsService:   true,
+		Permissions: tokenPermissions,
+	}, nil
+}
+
+func (ts *Source) now() time.Time {
+	if ts.Now != nil {
+		return ts.Now()
+	}
+	return time.Now()
+}
+
+// parseAccessToken checks the signature and standard claims of tokenStr, returning its claims.
+func (ts *Source) parseAccessToken(tokenStr string) (jwt.Claims, claims, error) {
+	tok, err := jwt.ParseSigned(tokenStr, jose_utils.ConvertToNativeJose(ts.SignatureAlgorithms))
+	if err != nil {
+		return jwt.Claims{}, claims{}, err
+	}
 	var jwtClaims jwt.Claims
 	var customClaims claims
 	err = tok.Claims(ts.Key.Key, &jwtClaims, &customClaims)
 	if err != nil {
-		return nil, err
+		return jwt.Claims{}, claims{}, err
 	}
 	err = jwtClaims.Validate(jwt.Expected{
 		AnyAudience: jwt.Audience{ts.Issuer},
 		Issuer:      ts.Issuer,
 	})
 	if err != nil {
-		return nil, err
+		return jwt.Claims{}, claims{}, err
 	}
 	if customClaims.Version != claimsVersion {
 		// token issued using a schema we no longer support
-		return nil, ErrUnsupportedTokenVersion
+		return jwt.Claims{}, claims{}, ErrUnsupportedTokenVersion
 	}
-	tokenPermissions := make([]token.PermissionAssignment, 0, len(customClaims.Permissions))
-	for _, pa := range customClaims.Permissions {
-		tokenPermissions = append(tokenPermissions, pa.ToTokenPermissionAssignment())
-	}
-	return &token.Claims{
-		SystemRoles: customClaims.SystemRoles,
-		IsService:   true,
-		Permissions: tokenPermissions,
-	}, nil
+	return jwtClaims, customClaims, nil
 }
 
 func generateKey() (jose.SigningKey, error) {
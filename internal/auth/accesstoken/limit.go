@@ -0,0 +1,115 @@
+package accesstoken
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxAddressEntries is the number of addresses an AddressLimiter tracks before it forgets expired entries.
+const maxAddressEntries = 10_000
+
+// AddressLimiter blocks password logins from network addresses that have made too many failed attempts.
+// This limits password guessing spread across many accounts, which per-account lockouts don't prevent.
+//
+// Failures are counted per remote address.
+// Once MaxFailures consecutive failures are made the address is blocked for Duration,
+// doubling for each consecutive block up to MaxDuration.
+// Failures are forgotten once FailureWindow has passed since the last failure or block.
+type AddressLimiter struct {
+	MaxFailures   int
+	Duration      time.Duration
+	MaxDuration   time.Duration
+	FailureWindow time.Duration
+	// OnBlock, if not nil, is called when an address becomes blocked.
+	OnBlock func(addr string, until time.Time)
+
+	now func() time.Time
+
+	mu        sync.Mutex
+	addresses map[string]*addressFailures
+}
+
+type addressFailures struct {
+	failures     int
+	blocks       int
+	lastFailure  time.Time
+	blockedUntil time.Time
+}
+
+func (af *addressFailures) lastActivity() time.Time {
+	if af.blockedUntil.After(af.lastFailure) {
+		return af.blockedUntil
+	}
+	return af.lastFailure
+}
+
+// Blocked returns whether addr can't attempt to log in.
+func (l *AddressLimiter) Blocked(addr string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	af, ok := l.addresses[addr]
+	return ok && af.blockedUntil.After(l.timeNow())
+}
+
+// Fail records a failed login attempt from addr.
+func (l *AddressLimiter) Fail(addr string) {
+	l.mu.Lock()
+	now := l.timeNow()
+	if l.addresses == nil {
+		l.addresses = make(map[string]*addressFailures)
+	}
+	if len(l.addresses) >= maxAddressEntries {
+		l.forgetExpired(now)
+	}
+	af, ok := l.addresses[addr]
+	if !ok || now.Sub(af.lastActivity()) > l.FailureWindow {
+		af = &addressFailures{}
+		l.addresses[addr] = af
+	}
+	af.failures++
+	af.lastFailure = now
+	var until time.Time
+	if af.failures >= l.MaxFailures {
+		af.failures = 0
+		af.blocks++
+		d := l.Duration
+		for i := 1; i < af.blocks && d < l.MaxDuration; i++ {
+			d *= 2
+		}
+		until = now.Add(min(d, l.MaxDuration))
+		af.blockedUntil = until
+	}
+	l.mu.Unlock()
+
+	if !until.IsZero() && l.OnBlock != nil {
+		l.OnBlock(addr, until)
+	}
+}
+
+// forgetExpired removes addresses that are no longer blocked and whose failures are outside the window.
+// l.mu must be held.
+func (l *AddressLimiter) forgetExpired(now time.Time) {
+	for addr, af := range l.addresses {
+		if now.Sub(af.lastActivity()) > l.FailureWindow {
+			delete(l.addresses, addr)
+		}
+	}
+}
+
+func (l *AddressLimiter) timeNow() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+// remoteHost returns the host part of the request's remote address, without the port.
+func remoteHost(request *http.Request) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+	return host
+}
@@ -11,6 +11,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/smart-core-os/sc-bos/internal/auth/session"
 	"github.com/smart-core-os/sc-bos/internal/util/rpcutil"
 	"github.com/smart-core-os/sc-bos/pkg/auth/token"
 )
@@ -26,6 +27,18 @@ import (
 //   - Unsupported grant type: error code "unsupported_grant_type".
 //   - Malformed request: error code "invalid_request".
 //   - Authentication successful, but identity has no resource access: error code "unauthorized_client".
+//   - Password correct, but the account requires a one-time password: error code "mfa_required".
+//   - Too many failed password logins from the client's address: error code "invalid_grant", http status 429.
+//
+// Accounts protected by a second factor are verified in two steps: the client first requests a token using the
+// username and password, receiving an "mfa_required" error, then repeats the request including the one-time password
+// from the user in the "otp" parameter. Both parameters may be supplied in the first request if the client knows
+// the account requires it. See SecondFactorVerifier.
+//
+// When WithSessions is set, each successful password grant starts a session and the response includes a refresh token.
+// Requests with grant_type=refresh_token exchange the refresh token for a new access token and refresh token,
+// the old refresh token can't be used again. Sessions can be ended using the RFC 7009 revocation endpoint, see
+// RevocationHandler, after which access tokens issued for the session are no longer valid.
 type Server struct {
 	tokens *Source
 	logger *zap.Logger
@@ -35,6 +48,10 @@ type Server struct {
 
 	passwordVerifier Verifier
 	passwordValidity time.Duration
+	addressLimiter   *AddressLimiter
+
+	sessions        *session.Store
+	refreshValidity time.Duration
 }
 
 func NewServer(name string, opts ...ServerOption) (*Server, error) {
@@ -48,7 +65,7 @@ func NewServer(name string, opts ...ServerOption) (*Server, error) {
 		Now:    time.Now,
 	}
 
-	s := &Server{tokens: tokens}
+	s := &Server{tokens: tokens, logger: zap.NewNop()}
 	for _, opt := range opts {
 		opt(s)
 	}
@@ -78,6 +95,27 @@ func WithPasswordFlow(v Verifier, validity time.Duration) ServerOption {
 	}
 }
 
+// WithAddressLimiter blocks password logins from addresses that have made too many failed attempts.
+// Failed attempts are those where the credentials or one-time password are incorrect.
+func WithAddressLimiter(l *AddressLimiter) ServerOption {
+	return func(ts *Server) {
+		ts.addressLimiter = l
+	}
+}
+
+// WithSessions enables refresh tokens for the password flow, storing sessions in store.
+// A session expires if it isn't refreshed within validity.
+func WithSessions(store *session.Store, validity time.Duration) ServerOption {
+	return func(ts *Server) {
+		ts.sessions = store
+		ts.refreshValidity = validity
+		ts.tokens.SessionRevoked = func(sessionID string) bool {
+			id, err := session.ParseID(sessionID)
+			return err != nil || store.IsRevoked(id)
+		}
+	}
+}
+
 func WithPermittedSignatureAlgorithms(algs []string) ServerOption {
 	return func(ts *Server) {
 		ts.tokens.SignatureAlgorithms = algs
@@ -108,6 +146,8 @@ func (s *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		err = s.clientCredentialsFlow(ctx, writer, request)
 	case "password":
 		err = s.passwordFlow(ctx, writer, request)
+	case "refresh_token":
+		err = s.refreshTokenFlow(ctx, writer, request, logger)
 	default:
 		err = errUnsupportedGrantType
 	}
@@ -165,22 +205,11 @@ func (s *Server) clientCredentialsFlow(ctx context.Context, writer http.Response
 	}
 
 	// send response to the client
-	response := tokenSuccessResponse{
+	return writeTokenSuccess(writer, tokenSuccessResponse{
 		AccessToken: token,
 		TokenType:   "Bearer",
 		ExpiresIn:   int(s.clientCredentialValidity.Seconds()),
-	}
-	responseBytes, err := json.Marshal(response)
-	if err != nil {
-		return errors.New("failed to marshal response")
-	}
-
-	writer.Header().Set("Content-Type", "application/json")
-	_, err = writer.Write(responseBytes)
-	if err != nil {
-		return errors.New("failed to write response body")
-	}
-	return nil
+	})
 }
 
 func (s *Server) passwordFlow(ctx context.Context, writer http.ResponseWriter, request *http.Request) error {
@@ -191,36 +220,192 @@ func (s *Server) passwordFlow(ctx context.Context, writer http.ResponseWriter, r
 	if err != nil {
 		return err
 	}
+	addr := remoteHost(request)
+	if s.addressLimiter != nil && s.addressLimiter.Blocked(addr) {
+		return errTooManyAttempts
+	}
 
 	// lookup secret, and ensure it's for the matching client
-	secretData, err := s.passwordVerifier.Verify(ctx, username, password)
+	var secretData SecretData
+	if v, ok := s.passwordVerifier.(SecondFactorVerifier); ok {
+		secretData, err = v.VerifyWithSecondFactor(ctx, username, password, request.PostForm.Get("otp"))
+	} else {
+		secretData, err = s.passwordVerifier.Verify(ctx, username, password)
+	}
+	if s.addressLimiter != nil && (errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrInvalidSecondFactor)) {
+		s.addressLimiter.Fail(addr)
+	}
 	if tokenErr := (tokenError{}); errors.As(err, &tokenErr) {
 		return tokenErr
 	} else if err != nil {
 		return errInvalidClient
 	}
 
-	// generate an access token for the client
-	token, err := s.tokens.GenerateAccessToken(secretData, s.passwordValidity)
+	if s.sessions == nil {
+		// generate an access token for the client
+		token, err := s.tokens.GenerateAccessToken(secretData, s.passwordValidity)
+		if err != nil {
+			return errors.New("failed to generate token")
+		}
+
+		// send response to the client
+		return writeTokenSuccess(writer, tokenSuccessResponse{
+			AccessToken: token,
+			TokenType:   "Bearer",
+			ExpiresIn:   int(s.passwordValidity.Seconds()),
+		})
+	}
+
+	// start a session so the client can refresh the access token without the user's password
+	sessionData, err := json.Marshal(secretData)
 	if err != nil {
-		return errors.New("failed to generate token")
+		return errors.New("failed to marshal session data")
+	}
+	sess, refreshToken, err := s.sessions.Create(ctx, session.Session{
+		Subject:       secretData.TenantID,
+		DisplayName:   secretData.Title,
+		Data:          sessionData,
+		UserAgent:     request.UserAgent(),
+		RemoteAddress: request.RemoteAddr,
+		ExpireTime:    s.sessionExpireTime(),
+	})
+	if err != nil {
+		return errors.New("failed to create session")
 	}
+	return s.writeSessionTokens(writer, sess, secretData, refreshToken)
+}
 
-	// send response to the client
-	response := tokenSuccessResponse{
-		AccessToken: token,
-		TokenType:   "Bearer",
-		ExpiresIn:   int(s.passwordValidity.Seconds()),
+func (s *Server) refreshTokenFlow(ctx context.Context, writer http.ResponseWriter, request *http.Request, logger *zap.Logger) error {
+	if s.sessions == nil {
+		return errUnsupportedGrantType
 	}
-	responseBytes, err := json.Marshal(response)
+	refreshToken := request.PostForm.Get("refresh_token")
+	if refreshToken == "" {
+		return errInvalidRequest
+	}
+
+	// check the identity is still valid before rotating the refresh token,
+	// otherwise a failure here would leave the client without a usable token.
+	sess, err := s.sessions.Lookup(ctx, refreshToken)
 	if err != nil {
-		return errors.New("failed to marshal response")
+		return refreshError(err, logger)
+	}
+	var secretData SecretData
+	if err := json.Unmarshal(sess.Data, &secretData); err != nil {
+		return errors.New("failed to unmarshal session data")
+	}
+	if v, ok := s.passwordVerifier.(RefreshVerifier); ok {
+		secretData, err = v.VerifyRefresh(ctx, secretData)
+		if tokenErr := (tokenError{}); errors.As(err, &tokenErr) {
+			// the identity is no longer allowed to log in, end the session
+			if _, err := s.sessions.Revoke(ctx, sess.ID); err != nil {
+				logger.Warn("failed to revoke session", zap.Stringer("session", sess.ID), zap.Error(err))
+			}
+			return tokenErr
+		} else if err != nil {
+			return errors.New("failed to verify session")
+		}
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
-	_, err = writer.Write(responseBytes)
+	refreshed, newRefreshToken, err := s.sessions.Refresh(ctx, refreshToken, s.sessionExpireTime())
 	if err != nil {
-		return errors.New("failed to write response body")
+		return refreshError(err, logger)
+	}
+	return s.writeSessionTokens(writer, refreshed, secretData, newRefreshToken)
+}
+
+// refreshError converts errors from the session store into errors to return to the client.
+func refreshError(err error, logger *zap.Logger) error {
+	switch {
+	case errors.Is(err, session.ErrTokenReused):
+		logger.Warn("refresh token reused, session revoked")
+		return errInvalidRefreshToken
+	case errors.Is(err, session.ErrInvalidToken):
+		return errInvalidRefreshToken
+	default:
+		return errors.New("failed to refresh session")
+	}
+}
+
+func (s *Server) writeSessionTokens(writer http.ResponseWriter, sess session.Session, secretData SecretData, refreshToken string) error {
+	token, err := s.tokens.GenerateSessionAccessToken(secretData, sess.ID.String(), s.passwordValidity)
+	if err != nil {
+		return errors.New("failed to generate token")
+	}
+	return writeTokenSuccess(writer, tokenSuccessResponse{
+		AccessToken:  token,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.passwordValidity.Seconds()),
+		RefreshToken: refreshToken,
+	})
+}
+
+// sessionExpireTime returns when a session created or refreshed now should expire.
+// Sessions outlive the access tokens issued for them so that revoking the session always applies to its tokens.
+func (s *Server) sessionExpireTime() time.Time {
+	return s.tokens.now().Add(max(s.refreshValidity, s.passwordValidity))
+}
+
+// RevocationHandler returns a handler implementing the RFC 7009 token revocation endpoint.
+// Both refresh tokens and access tokens issued by this server are accepted, revoking either ends the session the
+// token belongs to. As per the spec, the handler responds with 200 OK for tokens that are invalid or unknown.
+// The token_type_hint parameter is ignored.
+func (s *Server) RevocationHandler() http.Handler {
+	return http.HandlerFunc(s.serveRevocation)
+}
+
+func (s *Server) serveRevocation(writer http.ResponseWriter, request *http.Request) {
+	ctx, cancel := context.WithTimeout(request.Context(), time.Minute)
+	defer cancel()
+	logger := rpcutil.HTTPLogger(request, s.logger)
+
+	writer.Header().Set("Cache-Control", "no-store")
+
+	if request.Method != http.MethodPost {
+		writeTokenError(writer, errInvalidRequest, logger)
+		return
+	}
+	if err := parsePostForm(request); err != nil {
+		writeTokenError(writer, err, logger)
+		return
+	}
+	if s.sessions == nil {
+		writeTokenError(writer, errUnsupportedTokenType, logger)
+		return
+	}
+	tokenStr := request.PostForm.Get("token")
+	if tokenStr == "" {
+		writeTokenError(writer, errInvalidRequest, logger)
+		return
+	}
+	if err := s.revokeToken(ctx, tokenStr); err != nil {
+		writeTokenError(writer, err, logger)
+		return
+	}
+	writer.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) revokeToken(ctx context.Context, tokenStr string) error {
+	_, err := s.sessions.RevokeToken(ctx, tokenStr)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, session.ErrInvalidToken) {
+		return errors.New("failed to revoke session")
+	}
+
+	// not a refresh token, try an access token
+	_, claims, err := s.tokens.parseAccessToken(tokenStr)
+	if err != nil || claims.SessionID == "" {
+		return nil // invalid tokens are ignored
+	}
+	id, err := session.ParseID(claims.SessionID)
+	if err != nil {
+		return nil
+	}
+	_, err = s.sessions.Revoke(ctx, id)
+	if err != nil && !errors.Is(err, session.ErrNotFound) {
+		return errors.New("failed to revoke session")
 	}
 	return nil
 }
@@ -247,6 +432,20 @@ func (s *Server) TokenValidator() token.Validator {
 	return s.tokens
 }
 
+func writeTokenSuccess(writer http.ResponseWriter, response tokenSuccessResponse) error {
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return errors.New("failed to marshal response")
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	_, err = writer.Write(responseBytes)
+	if err != nil {
+		return errors.New("failed to write response body")
+	}
+	return nil
+}
+
 func writeTokenError(writer http.ResponseWriter, err error, logger *zap.Logger) {
 	tokErr, ok := err.(tokenError)
 	if !ok {
@@ -297,4 +496,15 @@ var (
 	errInvalidRequest       = tokenError{Code: 400, ErrorName: "invalid_request"}
 	errInvalidClient        = tokenError{Code: 401, ErrorName: "invalid_client"}
 	errUnsupportedGrantType = tokenError{Code: 400, ErrorName: "unsupported_grant_type"}
+	errUnsupportedTokenType = tokenError{Code: 400, ErrorName: "unsupported_token_type"}
+	errTooManyAttempts      = tokenError{
+		Code:             http.StatusTooManyRequests,
+		ErrorName:        "invalid_grant",
+		ErrorDescription: "too many failed login attempts, try again later",
+	}
+	errInvalidRefreshToken = tokenError{
+		Code:             400,
+		ErrorName:        "invalid_grant",
+		ErrorDescription: "refresh token is invalid, expired, or revoked",
+	}
 )
@@ -2,6 +2,7 @@ package accesstoken
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -9,8 +10,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-jose/go-jose/v4"
 	"go.uber.org/zap"
 
+	"github.com/smart-core-os/sc-bos/internal/auth/session"
 	"github.com/smart-core-os/sc-bos/pkg/auth/token"
 )
 
@@ -173,6 +176,209 @@ func TestTokenServer(t *testing.T) {
 	}
 }
 
+func TestTokenServer_sessions(t *testing.T) {
+	var users testMemoryVerifier
+	users.add(t, SecretData{TenantID: "user1", SystemRoles: []string{"admin"}}, "password123")
+	sessions, err := session.OpenMemory(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sessions.Close() })
+
+	server, err := NewServer("test",
+		WithLogger(zap.NewNop()),
+		WithPasswordFlow(&users, 10*time.Minute),
+		WithSessions(sessions, time.Hour),
+		WithPermittedSignatureAlgorithms([]string{string(jose.HS256)}),
+	)
+	if err != nil {
+		t.Fatalf("NewServer %v", err)
+	}
+	tokenServer := httptest.NewServer(server)
+	t.Cleanup(tokenServer.Close)
+	revokeServer := httptest.NewServer(server.RevocationHandler())
+	t.Cleanup(revokeServer.Close)
+
+	post := func(u string, values url.Values) (int, tokenSuccessResponse, tokenError) {
+		t.Helper()
+		resp, err := tokenServer.Client().PostForm(u, values)
+		if err != nil {
+			t.Fatalf("PostForm %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("ReadAll %v", err)
+		}
+		var (
+			success tokenSuccessResponse
+			tokErr  tokenError
+		)
+		if len(body) > 0 {
+			if resp.StatusCode == http.StatusOK {
+				err = json.Unmarshal(body, &success)
+			} else {
+				err = json.Unmarshal(body, &tokErr)
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal %v", err)
+			}
+		}
+		return resp.StatusCode, success, tokErr
+	}
+	login := func() tokenSuccessResponse {
+		t.Helper()
+		code, res, tokErr := post(tokenServer.URL, url.Values{
+			"grant_type": {"password"},
+			"username":   {"user1"},
+			"password":   {"password123"},
+		})
+		if code != http.StatusOK {
+			t.Fatalf("login got %d %v", code, tokErr)
+		}
+		if res.RefreshToken == "" {
+			t.Fatalf("login didn't return a refresh token")
+		}
+		return res
+	}
+	refresh := func(refreshToken string) (int, tokenSuccessResponse, tokenError) {
+		t.Helper()
+		return post(tokenServer.URL, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {refreshToken},
+		})
+	}
+	validate := func(accessToken string) error {
+		_, err := server.TokenValidator().ValidateAccessToken(t.Context(), accessToken)
+		return err
+	}
+
+	t.Run("refresh", func(t *testing.T) {
+		first := login()
+		code, second, tokErr := refresh(first.RefreshToken)
+		if code != http.StatusOK {
+			t.Fatalf("refresh got %d %v", code, tokErr)
+		}
+		if second.RefreshToken == "" || second.RefreshToken == first.RefreshToken {
+			t.Fatalf("refresh didn't rotate the refresh token")
+		}
+		claims, err := server.TokenValidator().ValidateAccessToken(t.Context(), second.AccessToken)
+		if err != nil {
+			t.Fatalf("refreshed access token invalid: %v", err)
+		}
+		if claims.Subject != "user1" {
+			t.Errorf("refreshed access token subject got %q, want %q", claims.Subject, "user1")
+		}
+
+		// reusing the old refresh token ends the session
+		code, _, tokErr = refresh(first.RefreshToken)
+		if code != http.StatusBadRequest || tokErr.ErrorName != "invalid_grant" {
+			t.Errorf("reused refresh got %d %v, want invalid_grant", code, tokErr)
+		}
+		if code, _, _ := refresh(second.RefreshToken); code != http.StatusBadRequest {
+			t.Errorf("refresh after reuse got %d, want %d", code, http.StatusBadRequest)
+		}
+		if err := validate(second.AccessToken); !errors.Is(err, ErrSessionRevoked) {
+			t.Errorf("access token after reuse got %v, want %v", err, ErrSessionRevoked)
+		}
+	})
+
+	t.Run("revoke refresh token", func(t *testing.T) {
+		res := login()
+		code, _, tokErr := post(revokeServer.URL, url.Values{"token": {res.RefreshToken}})
+		if code != http.StatusOK {
+			t.Fatalf("revoke got %d %v", code, tokErr)
+		}
+		if err := validate(res.AccessToken); !errors.Is(err, ErrSessionRevoked) {
+			t.Errorf("access token after revoke got %v, want %v", err, ErrSessionRevoked)
+		}
+		if code, _, _ := refresh(res.RefreshToken); code != http.StatusBadRequest {
+			t.Errorf("refresh after revoke got %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("revoke access token", func(t *testing.T) {
+		res := login()
+		code, _, tokErr := post(revokeServer.URL, url.Values{"token": {res.AccessToken}, "token_type_hint": {"access_token"}})
+		if code != http.StatusOK {
+			t.Fatalf("revoke got %d %v", code, tokErr)
+		}
+		if code, _, _ := refresh(res.RefreshToken); code != http.StatusBadRequest {
+			t.Errorf("refresh after revoke got %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("revoke unknown token", func(t *testing.T) {
+		code, _, tokErr := post(revokeServer.URL, url.Values{"token": {"not-a-token"}})
+		if code != http.StatusOK {
+			t.Errorf("revoke got %d %v, want 200", code, tokErr)
+		}
+	})
+}
+
+func TestTokenServer_addressLimiter(t *testing.T) {
+	var users testMemoryVerifier
+	users.add(t, SecretData{TenantID: "user1", SystemRoles: []string{"admin"}}, "password123")
+	now := time.Unix(1_700_000_000, 0)
+	var blocked []time.Time
+	limiter := &AddressLimiter{
+		MaxFailures:   2,
+		Duration:      time.Minute,
+		MaxDuration:   time.Hour,
+		FailureWindow: 10 * time.Minute,
+		OnBlock:       func(_ string, until time.Time) { blocked = append(blocked, until) },
+		now:           func() time.Time { return now },
+	}
+	server, err := NewServer("test",
+		WithLogger(zap.NewNop()),
+		WithPasswordFlow(&users, 10*time.Minute),
+		WithAddressLimiter(limiter),
+	)
+	if err != nil {
+		t.Fatalf("NewServer %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	login := func(password string) int {
+		t.Helper()
+		resp, err := httpServer.Client().PostForm(httpServer.URL, url.Values{
+			"grant_type": {"password"},
+			"username":   {"user1"},
+			"password":   {password},
+		})
+		if err != nil {
+			t.Fatalf("PostForm %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	for range 2 {
+		if code := login("wrong"); code != http.StatusBadRequest {
+			t.Fatalf("wrong password got %d, want %d", code, http.StatusBadRequest)
+		}
+	}
+	// even the right password is refused while the address is blocked
+	if code := login("password123"); code != http.StatusTooManyRequests {
+		t.Fatalf("blocked login got %d, want %d", code, http.StatusTooManyRequests)
+	}
+	if len(blocked) != 1 || !blocked[0].Equal(now.Add(time.Minute)) {
+		t.Errorf("OnBlock got %v, want [%v]", blocked, now.Add(time.Minute))
+	}
+
+	now = now.Add(time.Minute)
+	if code := login("password123"); code != http.StatusOK {
+		t.Fatalf("login after block got %d, want %d", code, http.StatusOK)
+	}
+	// consecutive blocks are longer
+	login("wrong")
+	login("wrong")
+	if len(blocked) != 2 || !blocked[1].Equal(now.Add(2*time.Minute)) {
+		t.Errorf("OnBlock got %v, want second block until %v", blocked, now.Add(2*time.Minute))
+	}
+}
+
 type testMemoryVerifier struct {
 	MemoryVerifier
 }
@@ -12,10 +12,8 @@ import (
 
 	"go.uber.org/multierr"
 
-	"github.com/smart-core-os/sc-bos/internal/auth/permission"
 	"github.com/smart-core-os/sc-bos/internal/util/pass"
 	"github.com/smart-core-os/sc-bos/pkg/auth/token"
-	"github.com/smart-core-os/sc-bos/pkg/gen"
 )
 
 // Verifier verifies that an id is associated with a given secret.
@@ -28,6 +26,28 @@ type Verifier interface {
 	Verify(ctx context.Context, id, secret string) (SecretData, error)
 }
 
+// SecondFactorVerifier is a Verifier that can also check a second authentication factor, like a one-time password.
+// The token server will use VerifyWithSecondFactor in preference to Verify when a Verifier implements this interface.
+type SecondFactorVerifier interface {
+	Verifier
+	// VerifyWithSecondFactor is like Verify but also checks code, which may be empty if the client didn't supply one.
+	// Implementations should return ErrSecondFactorRequired if the id+secret are correct but a code is required and
+	// was not supplied, or ErrInvalidSecondFactor if the supplied code is incorrect.
+	VerifyWithSecondFactor(ctx context.Context, id, secret, code string) (SecretData, error)
+}
+
+// RefreshVerifier is a Verifier that can check an identity is still valid without its secret.
+// When refreshing a session the token server uses VerifyRefresh, if available, so that changes to the identity,
+// like assigned roles, are reflected in new access tokens.
+// Otherwise the SecretData from when the session was created is reused.
+type RefreshVerifier interface {
+	Verifier
+	// VerifyRefresh returns up-to-date SecretData for an identity previously returned by Verify.
+	// Implementations should return ErrInvalidCredentials if the identity is no longer valid, for example if the
+	// account has been deleted, which revokes the session.
+	VerifyRefresh(ctx context.Context, data SecretData) (SecretData, error)
+}
+
 var (
 	ErrInvalidCredentials = tokenError{
 		Code:             http.StatusBadRequest,
@@ -39,6 +59,21 @@ var (
 		ErrorName:        "unauthorized_client",
 		ErrorDescription: "no roles assigned that allow access to this resource",
 	}
+	ErrSecondFactorRequired = tokenError{
+		Code:             http.StatusUnauthorized,
+		ErrorName:        "mfa_required",
+		ErrorDescription: "a one-time password is required, supply it using the otp parameter",
+	}
+	ErrInvalidSecondFactor = tokenError{
+		Code:             http.StatusBadRequest,
+		ErrorName:        "invalid_grant",
+		ErrorDescription: "provided one-time password is incorrect",
+	}
+	ErrAccountLocked = tokenError{
+		Code:             http.StatusBadRequest,
+		ErrorName:        "invalid_grant",
+		ErrorDescription: "account is temporarily locked after too many failed login attempts",
+	}
 )
 
 // VerifierFunc adapts an ordinary func to implement Verifier.
@@ -366,12 +401,7 @@ type SecretData struct {
 // LegacyZonePermission returns a PermissionAssignment that grants write access to names beginning with the given zone prefix.
 // This does not use a ZONE resource type, in order to maintain compatibility.
 func LegacyZonePermission(zone string) token.PermissionAssignment {
-	return token.PermissionAssignment{
-		Permission:   permission.TraitWrite,
-		Scoped:       true,
-		ResourceType: token.ResourceType(gen.RoleAssignment_NAMED_RESOURCE_PATH_PREFIX),
-		Resource:     zone,
-	}
+	return token.ZonePermission(zone)
 }
 
 func genId() string {
@@ -18,6 +18,8 @@ import (
 // accessTokenPayload describes the claims present in a token issued by a Keycloak Authorization Server.
 type accessTokenPayload struct {
 	jwt.Claims
+	Name           string                    `json:"name"`
+	Username       string                    `json:"preferred_username"`
 	Roles          []string                  `json:"roles"`
 	Scopes         auth.JWTScopes            `json:"scope"`
 	ResourceAccess map[string]resourceAccess `json:"resource_access"`
@@ -112,7 +114,13 @@ func (v *tokenValidator) ValidateAccessToken(ctx context.Context, tokenStr strin
 		return nil, err
 	}
 
+	name := payload.Name
+	if name == "" {
+		name = payload.Username
+	}
 	return &token.Claims{
+		Subject:     payload.Subject,
+		Name:        name,
 		SystemRoles: payload.allRoles(),
 		IsService:   false,
 	}, nil
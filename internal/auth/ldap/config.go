@@ -0,0 +1,83 @@
+// Package ldap verifies user credentials against an LDAP directory, like Active Directory or OpenLDAP.
+package ldap
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/smart-core-os/sc-bos/pkg/auth/token"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	DefaultUserFilter    = "(uid={username})"
+	DefaultGroupFilter   = "(member={dn})"
+	DefaultNameAttribute = "displayName"
+)
+
+// Config configures how users are found and authenticated in a directory.
+//
+// Users log in by username, which is used to search for their directory entry under UserBaseDN.
+// Their password is checked by binding to the directory as that entry.
+// The groups the user is a member of are then converted into system roles via GroupMapping,
+// users that are not a member of any mapped group can't log in.
+type Config struct {
+	// URL of the directory server, for example ldaps://ad.example.com or ldap://ldap.example.com:389.
+	URL string `json:"url,omitempty"`
+	// StartTLS upgrades ldap:// connections to TLS before any credentials are sent.
+	StartTLS bool `json:"startTls,omitempty"`
+	// CACertFile is a PEM file of certificate authorities trusted to sign the server certificate.
+	// Defaults to the system roots.
+	CACertFile string `json:"caCertFile,omitempty"`
+	// InsecureSkipVerify disables server certificate verification, only use this for testing.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// Timeout for each request to the directory, defaults to 10 seconds.
+	Timeout *jsontypes.Duration `json:"timeout,omitempty"`
+
+	// BindDN is the distinguished name of a service account used to search for users and their groups.
+	// One of BindDN or UserBindTemplate must be set.
+	BindDN string `json:"bindDn,omitempty"`
+	// BindPassword is the password of the BindDN service account.
+	BindPassword jsontypes.Password `json:"bindPassword,omitempty"`
+	// UserBindTemplate, if set, is used to bind as the user directly instead of searching for their entry first.
+	// Searches are then made as the user.
+	// {username} is replaced with the escaped username,
+	// for example "uid={username},ou=people,dc=example,dc=com" or, for Active Directory, "{username}@example.com".
+	UserBindTemplate string `json:"userBindTemplate,omitempty"`
+
+	// UserBaseDN is the entry under which users are searched for.
+	UserBaseDN string `json:"userBaseDn,omitempty"`
+	// UserFilter finds the entry of the user logging in, {username} is replaced with the escaped username.
+	// Defaults to DefaultUserFilter, for Active Directory use "(sAMAccountName={username})".
+	UserFilter string `json:"userFilter,omitempty"`
+	// NameAttribute is the attribute of the user entry holding their display name, defaults to DefaultNameAttribute.
+	// The username is used if the attribute is absent.
+	NameAttribute string `json:"nameAttribute,omitempty"`
+
+	// GroupBaseDN is the entry under which groups are searched for.
+	// If absent, the memberOf attribute of the user entry lists the user's groups.
+	GroupBaseDN string `json:"groupBaseDn,omitempty"`
+	// GroupFilter finds the groups the user is a member of, defaults to DefaultGroupFilter.
+	// {dn} is replaced with the distinguished name of the user and {username} with the username, both escaped.
+	GroupFilter string `json:"groupFilter,omitempty"`
+
+	// GroupMapping converts directory groups into system roles and zone permissions.
+	// Groups can be referenced either by common name (cn) or full distinguished name.
+	token.GroupMapping
+}
+
+func (c Config) validate() error {
+	if c.URL == "" {
+		return errors.New("url is required")
+	}
+	if _, err := url.Parse(c.URL); err != nil {
+		return err
+	}
+	if c.UserBaseDN == "" {
+		return errors.New("userBaseDn is required")
+	}
+	if c.BindDN == "" && c.UserBindTemplate == "" {
+		return errors.New("one of bindDn or userBindTemplate is required")
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+
+	"github.com/smart-core-os/sc-bos/internal/auth/accesstoken"
+)
+
+// Verifier is an accesstoken.RefreshVerifier that checks usernames and passwords against an LDAP directory.
+// The subject of verified users is the distinguished name of their directory entry.
+type Verifier struct {
+	cfg          Config
+	bindPassword string
+	tlsConfig    *tls.Config
+	timeout      time.Duration
+}
+
+// NewVerifier returns a Verifier using the directory described by cfg.
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	v := &Verifier{
+		cfg:     cfg,
+		timeout: cfg.Timeout.Or(10 * time.Second),
+	}
+	if cfg.UserFilter == "" {
+		v.cfg.UserFilter = DefaultUserFilter
+	}
+	if cfg.GroupFilter == "" {
+		v.cfg.GroupFilter = DefaultGroupFilter
+	}
+	if cfg.NameAttribute == "" {
+		v.cfg.NameAttribute = DefaultNameAttribute
+	}
+	if cfg.BindDN != "" {
+		pw, err := cfg.BindPassword.Read()
+		if err != nil {
+			return nil, fmt.Errorf("bind password: %w", err)
+		}
+		v.bindPassword = pw
+	}
+
+	u, _ := url.Parse(cfg.URL) // validated above
+	v.tlsConfig = &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if cfg.CACertFile != "" {
+		pem, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("ca cert: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca cert: no certificates found in %q", cfg.CACertFile)
+		}
+		v.tlsConfig.RootCAs = pool
+	}
+	return v, nil
+}
+
+func (v *Verifier) Verify(ctx context.Context, username, password string) (accesstoken.SecretData, error) {
+	// an empty password is an unauthenticated bind, which many directories allow for any dn
+	if username == "" || password == "" {
+		return accesstoken.SecretData{}, accesstoken.ErrInvalidCredentials
+	}
+	conn, closeConn, err := v.dial(ctx)
+	if err != nil {
+		return accesstoken.SecretData{}, err
+	}
+	defer closeConn()
+
+	var user *goldap.Entry
+	if v.cfg.BindDN != "" {
+		// find the user as the service account, then check their password
+		if err := conn.Bind(v.cfg.BindDN, v.bindPassword); err != nil {
+			return accesstoken.SecretData{}, fmt.Errorf("service account bind: %w", err)
+		}
+		user, err = v.findUser(conn, username)
+		if err != nil {
+			return accesstoken.SecretData{}, err
+		}
+		groups, err := v.findGroups(conn, username, user)
+		if err != nil {
+			return accesstoken.SecretData{}, err
+		}
+		if err := v.bindUser(conn, user.DN, password); err != nil {
+			return accesstoken.SecretData{}, err
+		}
+		return v.secretData(username, user, groups)
+	}
+
+	bindDN := strings.ReplaceAll(v.cfg.UserBindTemplate, "{username}", goldap.EscapeDN(username))
+	if err := v.bindUser(conn, bindDN, password); err != nil {
+		return accesstoken.SecretData{}, err
+	}
+	user, err = v.findUser(conn, username)
+	if err != nil {
+		return accesstoken.SecretData{}, err
+	}
+	groups, err := v.findGroups(conn, username, user)
+	if err != nil {
+		return accesstoken.SecretData{}, err
+	}
+	return v.secretData(username, user, groups)
+}
+
+// VerifyRefresh looks up the user's directory entry again so that changes to their group membership are reflected
+// in refreshed access tokens.
+// Without a service account there are no credentials to search with, so the existing data is returned unchanged.
+func (v *Verifier) VerifyRefresh(ctx context.Context, data accesstoken.SecretData) (accesstoken.SecretData, error) {
+	if _, err := goldap.ParseDN(data.TenantID); err != nil {
+		// not one of ours
+		return accesstoken.SecretData{}, accesstoken.ErrInvalidCredentials
+	}
+	if v.cfg.BindDN == "" {
+		return data, nil
+	}
+	conn, closeConn, err := v.dial(ctx)
+	if err != nil {
+		return accesstoken.SecretData{}, err
+	}
+	defer closeConn()
+	if err := conn.Bind(v.cfg.BindDN, v.bindPassword); err != nil {
+		return accesstoken.SecretData{}, fmt.Errorf("service account bind: %w", err)
+	}
+
+	res, err := conn.Search(goldap.NewSearchRequest(data.TenantID, goldap.ScopeBaseObject, goldap.NeverDerefAliases,
+		1, 0, false, "(objectClass=*)", v.userAttributes(), nil))
+	switch {
+	case goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject):
+		return accesstoken.SecretData{}, accesstoken.ErrInvalidCredentials
+	case err != nil:
+		return accesstoken.SecretData{}, fmt.Errorf("user search: %w", err)
+	case len(res.Entries) != 1:
+		return accesstoken.SecretData{}, accesstoken.ErrInvalidCredentials
+	}
+	user := res.Entries[0]
+	// the username isn't stored in the session, only the dn is, which is enough for the default group filter
+	groups, err := v.findGroups(conn, "", user)
+	if err != nil {
+		return accesstoken.SecretData{}, err
+	}
+	return v.secretData(data.Title, user, groups)
+}
+
+// dial connects to the directory, the returned func closes the connection.
+func (v *Verifier) dial(ctx context.Context) (*goldap.Conn, func(), error) {
+	conn, err := goldap.DialURL(v.cfg.URL,
+		goldap.DialWithDialer(&net.Dialer{Timeout: v.timeout}),
+		goldap.DialWithTLSConfig(v.tlsConfig))
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect: %w", err)
+	}
+	conn.SetTimeout(v.timeout)
+	// go-ldap doesn't support contexts, closing the connection aborts any outstanding requests
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	closeConn := func() {
+		stop()
+		_ = conn.Close()
+	}
+	if v.cfg.StartTLS {
+		if err := conn.StartTLS(v.tlsConfig); err != nil {
+			closeConn()
+			return nil, nil, fmt.Errorf("start tls: %w", err)
+		}
+	}
+	return conn, closeConn, nil
+}
+
+func (v *Verifier) bindUser(conn *goldap.Conn, dn, password string) error {
+	err := conn.Bind(dn, password)
+	if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+		return accesstoken.ErrInvalidCredentials
+	}
+	if err != nil {
+		return fmt.Errorf("user bind: %w", err)
+	}
+	return nil
+}
+
+func (v *Verifier) findUser(conn *goldap.Conn, username string) (*goldap.Entry, error) {
+	filter := strings.ReplaceAll(v.cfg.UserFilter, "{username}", goldap.EscapeFilter(username))
+	res, err := conn.Search(goldap.NewSearchRequest(v.cfg.UserBaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		2, 0, false, filter, v.userAttributes(), nil))
+	if err != nil && !goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject) && !goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("user search: %w", err)
+	}
+	if err != nil || len(res.Entries) != 1 {
+		// either unknown or ambiguous, neither of which we can log in as
+		return nil, accesstoken.ErrInvalidCredentials
+	}
+	return res.Entries[0], nil
+}
+
+func (v *Verifier) userAttributes() []string {
+	attrs := []string{v.cfg.NameAttribute}
+	if v.cfg.GroupBaseDN == "" {
+		attrs = append(attrs, "memberOf")
+	}
+	return attrs
+}
+
+// findGroups returns the names and distinguished names of the groups user is a member of.
+func (v *Verifier) findGroups(conn *goldap.Conn, username string, user *goldap.Entry) ([]string, error) {
+	var groups []string
+	addGroup := func(dn, cn string) {
+		groups = append(groups, dn)
+		if cn == "" {
+			if parsed, err := goldap.ParseDN(dn); err == nil && len(parsed.RDNs) > 0 && len(parsed.RDNs[0].Attributes) > 0 {
+				cn = parsed.RDNs[0].Attributes[0].Value
+			}
+		}
+		if cn != "" {
+			groups = append(groups, cn)
+		}
+	}
+
+	if v.cfg.GroupBaseDN == "" {
+		for _, dn := range user.GetAttributeValues("memberOf") {
+			addGroup(dn, "")
+		}
+		return groups, nil
+	}
+
+	filter := strings.NewReplacer(
+		"{dn}", goldap.EscapeFilter(user.DN),
+		"{username}", goldap.EscapeFilter(username),
+	).Replace(v.cfg.GroupFilter)
+	res, err := conn.Search(goldap.NewSearchRequest(v.cfg.GroupBaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		0, 0, false, filter, []string{"cn"}, nil))
+	if goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("group search: %w", err)
+	}
+	for _, e := range res.Entries {
+		addGroup(e.DN, e.GetAttributeValue("cn"))
+	}
+	return groups, nil
+}
+
+func (v *Verifier) secretData(username string, user *goldap.Entry, groups []string) (accesstoken.SecretData, error) {
+	roles, permissions := v.cfg.Apply(groups)
+	if len(roles) == 0 && len(permissions) == 0 {
+		return accesstoken.SecretData{}, accesstoken.ErrNoRolesAssigned
+	}
+	name := user.GetAttributeValue(v.cfg.NameAttribute)
+	if name == "" {
+		name = username
+	}
+	return accesstoken.SecretData{
+		Title:       name,
+		TenantID:    user.DN,
+		SystemRoles: roles,
+		Permissions: permissions,
+	}, nil
+}
@@ -0,0 +1,173 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jimlambrt/gldap"
+	"github.com/jimlambrt/gldap/testdirectory"
+
+	"github.com/smart-core-os/sc-bos/internal/auth/accesstoken"
+	"github.com/smart-core-os/sc-bos/pkg/auth/token"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	peopleDN = "ou=people,dc=example,dc=org"
+	groupsDN = "ou=groups,dc=example,dc=org"
+)
+
+func TestVerifier_groupSearch(t *testing.T) {
+	users := testdirectory.NewUsers(t, []string{"svc", "alice", "bob", "carol"})
+	d := startDirectory(t, users, []*gldap.Entry{
+		testdirectory.NewGroup(t, "admins", []string{"alice"}),
+		testdirectory.NewGroup(t, "operators", []string{"alice", "bob"}),
+	})
+
+	v, err := NewVerifier(Config{
+		URL:           fmt.Sprintf("ldap://%s:%d", d.Host(), d.Port()),
+		BindDN:        "cn=svc," + peopleDN,
+		BindPassword:  jsontypes.Password{Password: "password"},
+		UserBaseDN:    peopleDN,
+		UserFilter:    "(cn={username})",
+		NameAttribute: "email",
+		GroupBaseDN:   groupsDN,
+		GroupMapping: token.GroupMapping{
+			Roles: map[string][]string{"Admins": {"admin"}},
+			Zones: map[string][]string{"cn=operators," + groupsDN: {"building/floor-1"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := v.Verify(t.Context(), "alice", "password")
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	want := accesstoken.SecretData{
+		Title:       "alice@example.com",
+		TenantID:    "cn=alice," + peopleDN,
+		SystemRoles: []string{"admin"},
+		Permissions: []token.PermissionAssignment{token.ZonePermission("building/floor-1")},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Verify (-want,+got)\n%s", diff)
+	}
+
+	errTests := []struct {
+		username, password string
+		want               error
+	}{
+		{"alice", "wrong", accesstoken.ErrInvalidCredentials},
+		{"alice", "", accesstoken.ErrInvalidCredentials},
+		{"dave", "password", accesstoken.ErrInvalidCredentials},
+		{"carol", "password", accesstoken.ErrNoRolesAssigned},
+	}
+	for _, tt := range errTests {
+		if _, err := v.Verify(t.Context(), tt.username, tt.password); !errors.Is(err, tt.want) {
+			t.Errorf("Verify(%q, %q) got %v, want %v", tt.username, tt.password, err, tt.want)
+		}
+	}
+
+	// group changes are picked up on refresh
+	d.SetGroups(testdirectory.NewGroup(t, "operators", []string{"alice", "bob"}))
+	refreshed, err := v.VerifyRefresh(t.Context(), got)
+	if err != nil {
+		t.Fatalf("VerifyRefresh: %v", err)
+	}
+	want.SystemRoles = nil
+	if diff := cmp.Diff(want, refreshed); diff != "" {
+		t.Errorf("VerifyRefresh (-want,+got)\n%s", diff)
+	}
+
+	d.SetGroups()
+	if _, err := v.VerifyRefresh(t.Context(), got); !errors.Is(err, accesstoken.ErrNoRolesAssigned) {
+		t.Errorf("VerifyRefresh without groups got %v, want %v", err, accesstoken.ErrNoRolesAssigned)
+	}
+	if _, err := v.VerifyRefresh(t.Context(), accesstoken.SecretData{TenantID: "not a dn"}); !errors.Is(err, accesstoken.ErrInvalidCredentials) {
+		t.Errorf("VerifyRefresh invalid dn got %v, want %v", err, accesstoken.ErrInvalidCredentials)
+	}
+}
+
+func TestVerifier_memberOf(t *testing.T) {
+	users := slices.Concat(
+		testdirectory.NewUsers(t, []string{"alice"}, testdirectory.WithMembersOf(t, testdirectory.NewMemberOf(t, []string{"bms-admins"})...)),
+		testdirectory.NewUsers(t, []string{"bob"}, testdirectory.WithMembersOf(t, testdirectory.NewMemberOf(t, []string{"staff"})...)),
+	)
+	d := startDirectory(t, users, nil)
+
+	v, err := NewVerifier(Config{
+		URL:              fmt.Sprintf("ldap://%s:%d", d.Host(), d.Port()),
+		UserBindTemplate: "cn={username}," + peopleDN,
+		UserBaseDN:       peopleDN,
+		UserFilter:       "(cn={username})",
+		GroupMapping: token.GroupMapping{
+			Roles: map[string][]string{"bms-admins": {"admin", "operator"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := v.Verify(t.Context(), "alice", "password")
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	want := accesstoken.SecretData{
+		Title:       "alice",
+		TenantID:    "cn=alice," + peopleDN,
+		SystemRoles: []string{"admin", "operator"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Verify (-want,+got)\n%s", diff)
+	}
+	if _, err := v.Verify(t.Context(), "alice", "wrong"); !errors.Is(err, accesstoken.ErrInvalidCredentials) {
+		t.Errorf("Verify wrong password got %v, want %v", err, accesstoken.ErrInvalidCredentials)
+	}
+	if _, err := v.Verify(t.Context(), "bob", "password"); !errors.Is(err, accesstoken.ErrNoRolesAssigned) {
+		t.Errorf("Verify unmapped groups got %v, want %v", err, accesstoken.ErrNoRolesAssigned)
+	}
+
+	// without a service account the session data is kept as is
+	refreshed, err := v.VerifyRefresh(t.Context(), got)
+	if err != nil {
+		t.Fatalf("VerifyRefresh: %v", err)
+	}
+	if diff := cmp.Diff(got, refreshed); diff != "" {
+		t.Errorf("VerifyRefresh (-want,+got)\n%s", diff)
+	}
+}
+
+func TestNewVerifier_invalid(t *testing.T) {
+	tests := map[string]Config{
+		"no url":         {UserBaseDN: peopleDN, UserBindTemplate: "{username}"},
+		"no user base":   {URL: "ldap://localhost", UserBindTemplate: "{username}"},
+		"no credentials": {URL: "ldap://localhost", UserBaseDN: peopleDN},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewVerifier(cfg); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func startDirectory(t *testing.T, users, groups []*gldap.Entry) *testdirectory.Directory {
+	t.Helper()
+	return testdirectory.Start(t,
+		testdirectory.WithNoTLS(t),
+		testdirectory.WithDefaults(t, &testdirectory.Defaults{
+			UserAttr:  "cn",
+			GroupAttr: "cn",
+			UserDN:    peopleDN,
+			GroupDN:   groupsDN,
+			Users:     users,
+			Groups:    groups,
+		}),
+	)
+}
@@ -0,0 +1,45 @@
+package session
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type opts struct {
+	logger       *zap.Logger
+	now          func() time.Time
+	trimInterval time.Duration
+}
+
+func resolveOpts(options ...Option) opts {
+	o := opts{
+		trimInterval: time.Minute,
+	}
+	for _, option := range options {
+		option(&o)
+	}
+	if o.logger == nil {
+		o.logger = zap.NewNop()
+	}
+	if o.now == nil {
+		o.now = time.Now
+	}
+	return o
+}
+
+type Option func(*opts)
+
+// WithLogger is an option to set the logger used by the store.
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *opts) {
+		o.logger = logger
+	}
+}
+
+// WithNow is an option to set the clock used by the store.
+func WithNow(now func() time.Time) Option {
+	return func(o *opts) {
+		o.now = now
+	}
+}
@@ -0,0 +1,23 @@
+CREATE TABLE sessions
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT, -- ids are never reused, they are embedded in access tokens
+    -- the subject of tokens issued for the session, typically an account id
+    subject         TEXT    NOT NULL,
+    display_name    TEXT    NOT NULL DEFAULT '',
+    -- opaque data used by the token server when issuing tokens for the session
+    data            BLOB,
+    user_agent      TEXT    NOT NULL DEFAULT '',
+    remote_address  TEXT    NOT NULL DEFAULT '',
+    -- sha256 hash of the current refresh token secret
+    token_hash      BLOB    NOT NULL,
+    -- sha256 hash of the refresh token secret that was last exchanged, used to detect token reuse
+    prev_token_hash BLOB,
+    -- unix milliseconds
+    create_time     INTEGER NOT NULL,
+    refresh_time    INTEGER NOT NULL,
+    expire_time     INTEGER NOT NULL,
+    revoke_time     INTEGER
+);
+
+CREATE INDEX sessions_subject_idx ON sessions (subject, id);
+CREATE INDEX sessions_expire_time_idx ON sessions (expire_time);
@@ -0,0 +1,151 @@
+package session
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/historypb"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// Server is a [gen.SessionApiServer] that manages the sessions in a Store.
+type Server struct {
+	gen.UnimplementedSessionApiServer
+	store *Store
+}
+
+func NewServer(store *Store) *Server {
+	return &Server{store: store}
+}
+
+func (s *Server) ListSessions(ctx context.Context, req *gen.ListSessionsRequest) (*gen.ListSessionsResponse, error) {
+	q := Query{Subject: req.GetAccountId(), IncludeInactive: req.GetIncludeRevoked()}
+	after, pageSize, totalSize, err := parsePageInfo(req)
+	if err != nil {
+		return nil, err
+	}
+	// avoid counting if we already know the total size
+	if totalSize == 0 {
+		t, err := s.store.Count(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		totalSize = int32(t)
+	}
+
+	buf := make([]Session, pageSize+1) // +1 to detect if there's a next page
+	n, err := s.store.List(ctx, q, after, buf)
+	if err != nil {
+		return nil, err
+	}
+	res := &gen.ListSessionsResponse{TotalSize: totalSize}
+	if n == len(buf) {
+		n-- // don't include the extra session in the results
+		token, err := marshalToken(&historypb.PageToken{RecordId: buf[n-1].ID.String(), TotalSize: totalSize})
+		if err != nil {
+			return nil, err
+		}
+		res.NextPageToken = token
+	}
+	res.Sessions = make([]*gen.Session, n)
+	for i := range n {
+		res.Sessions[i] = sessionToProto(buf[i])
+	}
+	return res, nil
+}
+
+func (s *Server) RevokeSession(ctx context.Context, req *gen.RevokeSessionRequest) (*gen.RevokeSessionResponse, error) {
+	id, err := ParseID(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+	sess, err := s.store.Revoke(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "session not found")
+	} else if err != nil {
+		return nil, err
+	}
+	return &gen.RevokeSessionResponse{Session: sessionToProto(sess)}, nil
+}
+
+func (s *Server) RevokeAccountSessions(ctx context.Context, req *gen.RevokeAccountSessionsRequest) (*gen.RevokeAccountSessionsResponse, error) {
+	if req.GetAccountId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "account_id is required")
+	}
+	n, err := s.store.RevokeSubject(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, err
+	}
+	return &gen.RevokeAccountSessionsResponse{RevokedCount: int32(n)}, nil
+}
+
+func sessionToProto(sess Session) *gen.Session {
+	pb := &gen.Session{
+		Id:            sess.ID.String(),
+		AccountId:     sess.Subject,
+		DisplayName:   sess.DisplayName,
+		CreateTime:    timestamppb.New(sess.CreateTime),
+		RefreshTime:   timestamppb.New(sess.RefreshTime),
+		ExpireTime:    timestamppb.New(sess.ExpireTime),
+		UserAgent:     sess.UserAgent,
+		RemoteAddress: sess.RemoteAddress,
+	}
+	if !sess.RevokeTime.IsZero() {
+		pb.RevokeTime = timestamppb.New(sess.RevokeTime)
+	}
+	return pb
+}
+
+func parsePageInfo(req *gen.ListSessionsRequest) (after ID, pageSize, totalSize int32, _ error) {
+	pageToken, err := unmarshalToken(req.GetPageToken())
+	if err != nil {
+		return 0, 0, 0, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+	if id := pageToken.GetRecordId(); id != "" {
+		after, err = ParseID(id)
+		if err != nil {
+			return 0, 0, 0, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+	totalSize = pageToken.GetTotalSize()
+	pageSize = req.GetPageSize()
+	switch {
+	case pageSize <= 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+	return after, pageSize, totalSize, nil
+}
+
+func unmarshalToken(token string) (*historypb.PageToken, error) {
+	if token == "" {
+		return &historypb.PageToken{}, nil
+	}
+	data, err := base64.RawStdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	pb := &historypb.PageToken{}
+	err = proto.Unmarshal(data, pb)
+	return pb, err
+}
+
+func marshalToken(pb *historypb.PageToken) (string, error) {
+	data, err := proto.Marshal(pb)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,639 @@
+// Package session persists the login sessions of a token server.
+//
+// A session is created when a user logs in and is identified by a refresh token.
+// Each time the refresh token is used it is replaced by a new one, a process known as rotation.
+// Presenting a refresh token that has already been replaced is a sign that the token has been stolen,
+// so the session is revoked.
+//
+// Use a Server to allow administrators to see and revoke sessions via the SessionApi.
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/internal/sqlite"
+)
+
+const appID = 0x5C0505
+
+//go:embed schema/*.sql
+var schemaVersionsFS embed.FS
+var schema = sqlite.MustLoadVersionedSchema(schemaVersionsFS, "schema")
+
+func init() {
+	sqlite.RegisterSchema("sessions", appID, schema)
+}
+
+var (
+	ErrNotFound = errors.New("session not found")
+	// ErrInvalidToken is returned when a refresh token is malformed, unknown, expired, or belongs to a revoked session.
+	ErrInvalidToken = errors.New("invalid refresh token")
+	// ErrTokenReused is returned when a refresh token that has already been exchanged is used again.
+	// The session the token belongs to is revoked.
+	ErrTokenReused = errors.New("refresh token reused")
+)
+
+// Store is a persistent store of Sessions.
+type Store struct {
+	db     *sqlite.Database
+	logger *zap.Logger
+	now    func() time.Time
+
+	// revoked caches the ids of revoked sessions that have not expired, to their expiry time.
+	// IsRevoked is called for every request so shouldn't hit the database.
+	revokedMu sync.RWMutex
+	revoked   map[ID]time.Time
+
+	trimInterval time.Duration
+	lastTrimMu   sync.Mutex
+	lastTrimTime time.Time
+}
+
+// Open opens, or creates, the session database at path.
+func Open(ctx context.Context, path string, options ...Option) (*Store, error) {
+	o := resolveOpts(options...)
+
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("mkdir: %w", err)
+	}
+	db, err := sqlite.Open(ctx, path,
+		sqlite.WithApplicationID(appID),
+		sqlite.WithLogger(o.logger),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return newStore(ctx, db, o)
+}
+
+// OpenMemory opens an in-memory session database.
+// All sessions are lost when the Store is closed.
+func OpenMemory(ctx context.Context, options ...Option) (*Store, error) {
+	o := resolveOpts(options...)
+	db := sqlite.OpenMemory(
+		sqlite.WithApplicationID(appID),
+		sqlite.WithLogger(o.logger),
+	)
+	return newStore(ctx, db, o)
+}
+
+func newStore(ctx context.Context, db *sqlite.Database, o opts) (*Store, error) {
+	err := db.Migrate(ctx, schema)
+	if err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	s := &Store{
+		db:           db,
+		logger:       o.logger,
+		now:          o.now,
+		revoked:      make(map[ID]time.Time),
+		trimInterval: o.trimInterval,
+	}
+	err = db.ReadTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx,
+			"SELECT id, expire_time FROM sessions WHERE revoke_time IS NOT NULL AND expire_time > ?;",
+			s.now().UnixMilli())
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+		for rows.Next() {
+			var id ID
+			var expireTime int64
+			if err := rows.Scan(&id, &expireTime); err != nil {
+				return err
+			}
+			s.revoked[id] = time.UnixMilli(expireTime)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	return s, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Create starts a new session, returning the session with ID and times populated, and a refresh token for it.
+// The ExpireTime of sess must be set.
+func (s *Store) Create(ctx context.Context, sess Session) (Session, string, error) {
+	if sess.ExpireTime.IsZero() {
+		return Session{}, "", errors.New("expire time must be set")
+	}
+	secret, hash, err := newSecret()
+	if err != nil {
+		return Session{}, "", err
+	}
+	now := truncate(s.now())
+	sess.CreateTime = now
+	sess.RefreshTime = now
+	sess.ExpireTime = truncate(sess.ExpireTime)
+	sess.RevokeTime = time.Time{}
+	err = s.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO sessions (subject, display_name, data, user_agent, remote_address, token_hash, create_time, refresh_time, expire_time)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`,
+			sess.Subject, sess.DisplayName, sess.Data, sess.UserAgent, sess.RemoteAddress, hash,
+			sess.CreateTime.UnixMilli(), sess.RefreshTime.UnixMilli(), sess.ExpireTime.UnixMilli())
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		sess.ID = ID(id)
+		return nil
+	})
+	if err != nil {
+		return Session{}, "", err
+	}
+	s.trimAfterWrite(ctx)
+	return sess, formatToken(sess.ID, secret), nil
+}
+
+// Lookup returns the active session refreshToken belongs to, without exchanging the token.
+// Returns errors in the same way as Refresh, including revoking the session if the token has already been exchanged.
+func (s *Store) Lookup(ctx context.Context, refreshToken string) (Session, error) {
+	id, secret, ok := parseToken(refreshToken)
+	if !ok {
+		return Session{}, ErrInvalidToken
+	}
+	now := s.now()
+	hash := hashSecret(secret)
+	var (
+		sess   Session
+		reused bool
+	)
+	err := s.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		var tokenHash, prevTokenHash []byte
+		var err error
+		sess, tokenHash, prevTokenHash, err = getSession(ctx, tx, id)
+		if errors.Is(err, ErrNotFound) {
+			return ErrInvalidToken
+		} else if err != nil {
+			return err
+		}
+		if !sess.Active(now) {
+			return ErrInvalidToken
+		}
+		switch {
+		case subtle.ConstantTimeCompare(hash, tokenHash) == 1:
+			return nil
+		case len(prevTokenHash) > 0 && subtle.ConstantTimeCompare(hash, prevTokenHash) == 1:
+			reused = true
+			return nil
+		default:
+			return ErrInvalidToken
+		}
+	})
+	if err != nil {
+		return Session{}, err
+	}
+	if reused {
+		if _, err := s.Revoke(ctx, id); err != nil {
+			return Session{}, err
+		}
+		return Session{}, ErrTokenReused
+	}
+	return sess, nil
+}
+
+// Refresh exchanges refreshToken for a new refresh token, extending the session until expireTime.
+// The exchanged token can no longer be used.
+//
+// Returns ErrInvalidToken if the token isn't valid for any active session.
+// Returns ErrTokenReused, and revokes the session, if the token is valid but has already been exchanged.
+func (s *Store) Refresh(ctx context.Context, refreshToken string, expireTime time.Time) (Session, string, error) {
+	id, secret, ok := parseToken(refreshToken)
+	if !ok {
+		return Session{}, "", ErrInvalidToken
+	}
+	newSecret, newHash, err := newSecret()
+	if err != nil {
+		return Session{}, "", err
+	}
+	now := truncate(s.now())
+	hash := hashSecret(secret)
+	var (
+		sess   Session
+		reused bool
+	)
+	err = s.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		var tokenHash, prevTokenHash []byte
+		sess, tokenHash, prevTokenHash, err = getSession(ctx, tx, id)
+		if errors.Is(err, ErrNotFound) {
+			return ErrInvalidToken
+		} else if err != nil {
+			return err
+		}
+		if !sess.Active(now) {
+			return ErrInvalidToken
+		}
+
+		switch {
+		case subtle.ConstantTimeCompare(hash, tokenHash) == 1:
+			sess.RefreshTime = now
+			sess.ExpireTime = truncate(expireTime)
+			_, err := tx.ExecContext(ctx,
+				"UPDATE sessions SET token_hash = ?, prev_token_hash = ?, refresh_time = ?, expire_time = ? WHERE id = ?;",
+				newHash, tokenHash, sess.RefreshTime.UnixMilli(), sess.ExpireTime.UnixMilli(), id)
+			return err
+		case len(prevTokenHash) > 0 && subtle.ConstantTimeCompare(hash, prevTokenHash) == 1:
+			// Only the previous token triggers revocation.
+			// Session ids are not secret, revoking for any incorrect token would let anyone end any session.
+			reused = true
+			sess.RevokeTime = now
+			return revokeSession(ctx, tx, id, now)
+		default:
+			return ErrInvalidToken
+		}
+	})
+	if err != nil {
+		return Session{}, "", err
+	}
+	if reused {
+		s.markRevoked(sess)
+		return Session{}, "", ErrTokenReused
+	}
+	return sess, formatToken(id, newSecret), nil
+}
+
+// RevokeToken revokes the session refreshToken belongs to.
+// Returns ErrInvalidToken if the token isn't the current refresh token of any active session.
+func (s *Store) RevokeToken(ctx context.Context, refreshToken string) (Session, error) {
+	id, secret, ok := parseToken(refreshToken)
+	if !ok {
+		return Session{}, ErrInvalidToken
+	}
+	now := truncate(s.now())
+	hash := hashSecret(secret)
+	var sess Session
+	err := s.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		var tokenHash []byte
+		var err error
+		sess, tokenHash, _, err = getSession(ctx, tx, id)
+		if errors.Is(err, ErrNotFound) {
+			return ErrInvalidToken
+		} else if err != nil {
+			return err
+		}
+		if !sess.Active(now) || subtle.ConstantTimeCompare(hash, tokenHash) != 1 {
+			return ErrInvalidToken
+		}
+		sess.RevokeTime = now
+		return revokeSession(ctx, tx, id, now)
+	})
+	if err != nil {
+		return Session{}, err
+	}
+	s.markRevoked(sess)
+	return sess, nil
+}
+
+// Revoke revokes the session with the given id.
+// Revoking a session that is already revoked has no effect, the existing revoke time is kept.
+func (s *Store) Revoke(ctx context.Context, id ID) (Session, error) {
+	now := truncate(s.now())
+	var sess Session
+	err := s.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		sess, _, _, err = getSession(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !sess.RevokeTime.IsZero() {
+			return nil
+		}
+		sess.RevokeTime = now
+		return revokeSession(ctx, tx, id, now)
+	})
+	if err != nil {
+		return Session{}, err
+	}
+	s.markRevoked(sess)
+	return sess, nil
+}
+
+// RevokeSubject revokes all active sessions belonging to subject.
+// Returns the number of sessions revoked.
+func (s *Store) RevokeSubject(ctx context.Context, subject string) (int, error) {
+	now := truncate(s.now())
+	var revoked []Session
+	err := s.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `
+			UPDATE sessions SET revoke_time = ?
+			WHERE subject = ? AND revoke_time IS NULL AND expire_time > ?
+			RETURNING id, expire_time;`,
+			now.UnixMilli(), subject, now.UnixMilli())
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+		for rows.Next() {
+			var id ID
+			var expireTime int64
+			if err := rows.Scan(&id, &expireTime); err != nil {
+				return err
+			}
+			revoked = append(revoked, Session{ID: id, ExpireTime: time.UnixMilli(expireTime), RevokeTime: now})
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, sess := range revoked {
+		s.markRevoked(sess)
+	}
+	return len(revoked), nil
+}
+
+// IsRevoked returns whether the session with the given id has been revoked.
+// Unknown sessions are not considered revoked.
+func (s *Store) IsRevoked(id ID) bool {
+	s.revokedMu.RLock()
+	defer s.revokedMu.RUnlock()
+	_, ok := s.revoked[id]
+	return ok
+}
+
+func (s *Store) markRevoked(sess Session) {
+	s.revokedMu.Lock()
+	defer s.revokedMu.Unlock()
+	s.revoked[sess.ID] = sess.ExpireTime
+}
+
+// Get returns the session with the given id.
+func (s *Store) Get(ctx context.Context, id ID) (Session, error) {
+	var sess Session
+	err := s.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		sess, _, _, err = getSession(ctx, tx, id)
+		return err
+	})
+	return sess, err
+}
+
+// List reads sessions matching q into the provided slice, ordered by ID.
+// If after is non-zero, only sessions after that session are returned.
+// Returns the number of sessions read.
+func (s *Store) List(ctx context.Context, q Query, after ID, into []Session) (n int, err error) {
+	if len(into) == 0 {
+		return 0, nil
+	}
+	err = s.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		filters, args := q.filters(s.now())
+		if after != 0 {
+			filters = append(filters, "id > ?")
+			args = append(args, after)
+		}
+		args = append(args, len(into))
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM sessions
+			WHERE %s
+			ORDER BY id
+			LIMIT ?;`, sessionColumns, joinFilters(filters))
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+		for rows.Next() {
+			sess, _, _, err := scanSession(rows)
+			if err != nil {
+				return err
+			}
+			into[n] = sess
+			n++
+		}
+		return rows.Err()
+	})
+	return n, err
+}
+
+// Count returns the number of sessions matching q.
+func (s *Store) Count(ctx context.Context, q Query) (int, error) {
+	var count int
+	err := s.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		filters, args := q.filters(s.now())
+		query := fmt.Sprintf("SELECT COUNT(*) FROM sessions WHERE %s;", joinFilters(filters))
+		return tx.QueryRowContext(ctx, query, args...).Scan(&count)
+	})
+	return count, err
+}
+
+// Trim removes sessions that expired before the given time.
+// Returns the number of sessions removed.
+func (s *Store) Trim(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := s.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "DELETE FROM sessions WHERE expire_time <= ?;", before.UnixMilli())
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.revokedMu.Lock()
+	for id, expireTime := range s.revoked {
+		if !expireTime.After(before) {
+			delete(s.revoked, id)
+		}
+	}
+	s.revokedMu.Unlock()
+	return deleted, nil
+}
+
+// trimAfterWrite removes expired sessions, at most once per trimInterval.
+func (s *Store) trimAfterWrite(ctx context.Context) {
+	now := s.now()
+	s.lastTrimMu.Lock()
+	if now.Sub(s.lastTrimTime) < s.trimInterval {
+		s.lastTrimMu.Unlock()
+		return
+	}
+	s.lastTrimTime = now
+	s.lastTrimMu.Unlock()
+
+	n, err := s.Trim(ctx, now)
+	if err != nil {
+		s.logger.Warn("failed to trim expired sessions", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		s.logger.Debug("trimmed expired sessions", zap.Int64("count", n))
+	}
+}
+
+// Query filters the sessions returned by List and Count.
+// Zero fields do not filter sessions.
+type Query struct {
+	Subject string
+	// By default only active sessions are returned.
+	// Set IncludeInactive to also return revoked sessions.
+	// Expired sessions are removed from the store and are never returned.
+	IncludeInactive bool
+}
+
+func (q Query) filters(now time.Time) ([]string, []any) {
+	filters := []string{"expire_time > ?"}
+	args := []any{now.UnixMilli()}
+	if q.Subject != "" {
+		filters = append(filters, "subject = ?")
+		args = append(args, q.Subject)
+	}
+	if !q.IncludeInactive {
+		filters = append(filters, "revoke_time IS NULL")
+	}
+	return filters, args
+}
+
+func joinFilters(filters []string) string {
+	return strings.Join(filters, " AND ")
+}
+
+const sessionColumns = "id, subject, display_name, data, user_agent, remote_address, token_hash, prev_token_hash, create_time, refresh_time, expire_time, revoke_time"
+
+func getSession(ctx context.Context, tx *sql.Tx, id ID) (Session, []byte, []byte, error) {
+	row := tx.QueryRowContext(ctx, fmt.Sprintf("SELECT %s FROM sessions WHERE id = ?;", sessionColumns), id)
+	sess, tokenHash, prevTokenHash, err := scanSession(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, nil, nil, ErrNotFound
+	}
+	return sess, tokenHash, prevTokenHash, err
+}
+
+func scanSession(row interface{ Scan(...any) error }) (sess Session, tokenHash, prevTokenHash []byte, err error) {
+	var (
+		createTime, refreshTime, expireTime int64
+		revokeTime                          sql.NullInt64
+	)
+	err = row.Scan(&sess.ID, &sess.Subject, &sess.DisplayName, &sess.Data, &sess.UserAgent, &sess.RemoteAddress,
+		&tokenHash, &prevTokenHash, &createTime, &refreshTime, &expireTime, &revokeTime)
+	if err != nil {
+		return Session{}, nil, nil, err
+	}
+	sess.CreateTime = time.UnixMilli(createTime)
+	sess.RefreshTime = time.UnixMilli(refreshTime)
+	sess.ExpireTime = time.UnixMilli(expireTime)
+	if revokeTime.Valid {
+		sess.RevokeTime = time.UnixMilli(revokeTime.Int64)
+	}
+	return sess, tokenHash, prevTokenHash, nil
+}
+
+func revokeSession(ctx context.Context, tx *sql.Tx, id ID, now time.Time) error {
+	_, err := tx.ExecContext(ctx, "UPDATE sessions SET revoke_time = ? WHERE id = ?;", now.UnixMilli(), id)
+	return err
+}
+
+// Session is a single login session.
+type Session struct {
+	ID            ID
+	Subject       string
+	DisplayName   string
+	Data          []byte // opaque data associated with the session by the token server
+	UserAgent     string
+	RemoteAddress string
+	CreateTime    time.Time
+	RefreshTime   time.Time // the last time the refresh token was exchanged, or CreateTime
+	ExpireTime    time.Time // the session ends if it isn't refreshed before this time
+	RevokeTime    time.Time // zero if the session hasn't been revoked
+}
+
+// Active returns whether the session can be refreshed at time now.
+func (s Session) Active(now time.Time) bool {
+	return s.RevokeTime.IsZero() && now.Before(s.ExpireTime)
+}
+
+// ID uniquely identifies a Session in a Store.
+// IDs are not secret, they are included in access tokens issued for the session.
+type ID int64
+
+func ParseID(s string) (ID, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidID
+	}
+	return ID(id), nil
+}
+
+func (id ID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+var ErrInvalidID = errors.New("invalid session ID format")
+
+// secretBytes is the number of random bytes in a refresh token.
+const secretBytes = 32
+
+// newSecret returns a new random refresh token secret and its hash.
+func newSecret() (secret string, hash []byte, err error) {
+	b := make([]byte, secretBytes)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", nil, err
+	}
+	secret = base64.RawURLEncoding.EncodeToString(b)
+	return secret, hashSecret(secret), nil
+}
+
+func hashSecret(secret string) []byte {
+	h := sha256.Sum256([]byte(secret))
+	return h[:]
+}
+
+// formatToken returns a refresh token for the session id with the given secret.
+// The id allows the session to be found without scanning all hashes.
+func formatToken(id ID, secret string) string {
+	return id.String() + "." + secret
+}
+
+func parseToken(token string) (ID, string, bool) {
+	idStr, secret, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return 0, "", false
+	}
+	id, err := ParseID(idStr)
+	if err != nil {
+		return 0, "", false
+	}
+	return id, secret, true
+}
+
+// truncate returns t with the precision and location it would have after being read from the database.
+func truncate(t time.Time) time.Time {
+	return time.UnixMilli(t.UnixMilli())
+}
@@ -0,0 +1,184 @@
+package session
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStore_Refresh(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+	store := openTestStore(t, WithNow(func() time.Time { return now }))
+
+	sess, token1, err := store.Create(t.Context(), Session{Subject: "1", ExpireTime: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Minute)
+	refreshed, token2, err := store.Refresh(t.Context(), token1, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if refreshed.ID != sess.ID {
+		t.Errorf("Refresh returned session %v, want %v", refreshed.ID, sess.ID)
+	}
+	if !refreshed.RefreshTime.Equal(now) || !refreshed.ExpireTime.Equal(now.Add(time.Hour)) {
+		t.Errorf("Refresh times not updated: %v, %v", refreshed.RefreshTime, refreshed.ExpireTime)
+	}
+	if token2 == token1 {
+		t.Fatalf("Refresh didn't rotate the token")
+	}
+
+	// an incorrect secret doesn't affect the session
+	if _, _, err := store.Refresh(t.Context(), sess.ID.String()+".wrong", now.Add(time.Hour)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Refresh(wrong) got %v, want %v", err, ErrInvalidToken)
+	}
+	if store.IsRevoked(sess.ID) {
+		t.Fatalf("session revoked by incorrect token")
+	}
+
+	// reusing the first token revokes the session
+	if _, _, err := store.Refresh(t.Context(), token1, now.Add(time.Hour)); !errors.Is(err, ErrTokenReused) {
+		t.Errorf("Refresh(reused) got %v, want %v", err, ErrTokenReused)
+	}
+	if !store.IsRevoked(sess.ID) {
+		t.Errorf("session not revoked after token reuse")
+	}
+	if _, _, err := store.Refresh(t.Context(), token2, now.Add(time.Hour)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Refresh(revoked) got %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestStore_Refresh_expired(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+	store := openTestStore(t, WithNow(func() time.Time { return now }))
+
+	_, token, err := store.Create(t.Context(), Session{Subject: "1", ExpireTime: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if _, _, err := store.Refresh(t.Context(), token, now.Add(time.Hour)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Refresh got %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestStore_Revoke(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+	store := openTestStore(t, WithNow(func() time.Time { return now }))
+
+	create := func(subject string) (Session, string) {
+		t.Helper()
+		sess, token, err := store.Create(t.Context(), Session{Subject: subject, ExpireTime: now.Add(time.Hour)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sess, token
+	}
+	a1, a1Token := create("alice")
+	a2, _ := create("alice")
+	b1, b1Token := create("bob")
+	b2, _ := create("bob")
+
+	now = now.Add(time.Minute)
+	if _, err := store.RevokeToken(t.Context(), a1Token); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if _, err := store.RevokeToken(t.Context(), a1Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("RevokeToken(revoked) got %v, want %v", err, ErrInvalidToken)
+	}
+	revoked, err := store.Revoke(t.Context(), b2.ID)
+	if err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if !revoked.RevokeTime.Equal(now) {
+		t.Errorf("Revoke time got %v, want %v", revoked.RevokeTime, now)
+	}
+	if _, err := store.Revoke(t.Context(), 100); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Revoke(unknown) got %v, want %v", err, ErrNotFound)
+	}
+
+	list := func(q Query) []ID {
+		t.Helper()
+		buf := make([]Session, 10)
+		n, err := store.List(t.Context(), q, 0, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []ID
+		for _, s := range buf[:n] {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+	if diff := cmp.Diff([]ID{a2.ID, b1.ID}, list(Query{})); diff != "" {
+		t.Errorf("List active (-want,+got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]ID{b1.ID, b2.ID}, list(Query{Subject: "bob", IncludeInactive: true})); diff != "" {
+		t.Errorf("List bob (-want,+got)\n%s", diff)
+	}
+
+	n, err := store.RevokeSubject(t.Context(), "bob")
+	if err != nil {
+		t.Fatalf("RevokeSubject: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("RevokeSubject revoked %d, want 1", n)
+	}
+	for _, id := range []ID{a1.ID, b1.ID, b2.ID} {
+		if !store.IsRevoked(id) {
+			t.Errorf("session %v should be revoked", id)
+		}
+	}
+	if store.IsRevoked(a2.ID) {
+		t.Errorf("session %v should not be revoked", a2.ID)
+	}
+	if _, _, err := store.Refresh(t.Context(), b1Token, now.Add(time.Hour)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Refresh(revoked) got %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestStore_revokedAfterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.sqlite3")
+	store, err := Open(t.Context(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, _, err := store.Create(t.Context(), Session{Subject: "1", ExpireTime: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Revoke(t.Context(), sess.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = Open(t.Context(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if !store.IsRevoked(sess.ID) {
+		t.Errorf("session not revoked after reopening the store")
+	}
+}
+
+func openTestStore(t *testing.T, opts ...Option) *Store {
+	t.Helper()
+	store, err := OpenMemory(t.Context(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return store
+}
@@ -0,0 +1,350 @@
+// Package backup creates and restores backups of a controller's data directory.
+//
+// A backup is a gzipped tar archive containing a manifest followed by a consistent snapshot of each file in the data
+// directory.
+// SQLite databases are copied using VACUUM INTO and bolt databases within a read transaction, so databases can be
+// backed up while they are in use.
+// Archives can optionally be encrypted, see WithKey.
+//
+// Restoring a backup checks the archive is complete, and that every database it contains can be used by this build,
+// before replacing the data directory.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/internal/sqlite"
+)
+
+// FormatVersion is the version of the archive format written by Create.
+// Restore refuses archives with a newer format version.
+const FormatVersion = 1
+
+const (
+	manifestName = "manifest.json"
+	dataPrefix   = "data/"
+)
+
+// Manifest describes the contents of a backup.
+type Manifest struct {
+	FormatVersion int       `json:"formatVersion"`
+	CreateTime    time.Time `json:"createTime"`
+	Node          string    `json:"node,omitempty"`    // the name of the node the backup is of
+	Version       string    `json:"version,omitempty"` // the version of the software that created the backup
+	Files         []File    `json:"files"`
+}
+
+// Kind is how a file in the data directory was copied.
+type Kind string
+
+const (
+	KindFile   Kind = "file"
+	KindSQLite Kind = "sqlite"
+	KindBolt   Kind = "bolt"
+)
+
+// File describes a file in a backup.
+type File struct {
+	Path   string      `json:"path"` // slash separated, relative to the data dir
+	Kind   Kind        `json:"kind"`
+	Mode   fs.FileMode `json:"mode"`
+	Size   int64       `json:"size"`
+	SHA256 string      `json:"sha256"`
+	// For sqlite databases, the application id and schema version of the database.
+	AppID         uint32 `json:"appId,omitempty"`
+	SchemaVersion uint32 `json:"schemaVersion,omitempty"`
+}
+
+// Create writes a backup of dataDir to w.
+func Create(ctx context.Context, w io.Writer, dataDir string, opts ...Option) (Manifest, error) {
+	o := resolveOpts(opts...)
+	staging, err := os.MkdirTemp("", "backup-")
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer os.RemoveAll(staging)
+
+	manifest := Manifest{
+		FormatVersion: FormatVersion,
+		CreateTime:    o.now().UTC(),
+		Node:          o.node,
+		Version:       o.version,
+	}
+	err = filepath.WalkDir(dataDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dataDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+		if excluded(o.exclude, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil // directories are implied by their files, other types aren't supported
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		f, err := snapshotFile(ctx, o, p, rel, filepath.Join(staging, filepath.FromSlash(rel)))
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		manifest.Files = append(manifest.Files, f)
+		return nil
+	})
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	if err := writeArchive(w, o.key, manifest, staging); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+func excluded(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotFile copies the file at src to dst, in a way that is consistent for its kind.
+func snapshotFile(ctx context.Context, o opts, src, rel, dst string) (File, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return File{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return File{}, err
+	}
+	f := File{Path: rel, Mode: info.Mode().Perm()}
+	kind, err := detectKind(src)
+	if err != nil {
+		return File{}, err
+	}
+	if _, ok := o.bolt[rel]; ok {
+		kind = KindBolt
+	}
+	f.Kind = kind
+
+	switch kind {
+	case KindSQLite:
+		db, err := sqlite.Open(ctx, src, sqlite.WithLogger(o.logger))
+		if err != nil {
+			return File{}, err
+		}
+		err = db.BackupTo(ctx, dst)
+		if closeErr := db.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return File{}, err
+		}
+		appID, version, err := sqliteVersion(ctx, dst)
+		if err != nil {
+			return File{}, err
+		}
+		f.AppID, f.SchemaVersion = uint32(appID), version
+	case KindBolt:
+		db, ok := o.bolt[rel]
+		if !ok {
+			// not in use by us, we can open it ourselves if nobody else has it open
+			db, err = bbolt.Open(src, 0, &bbolt.Options{ReadOnly: true, Timeout: time.Second})
+			if err != nil {
+				return File{}, fmt.Errorf("open bolt database, is it in use? %w", err)
+			}
+			defer db.Close()
+		}
+		err := db.View(func(tx *bbolt.Tx) error {
+			return tx.CopyFile(dst, 0600)
+		})
+		if err != nil {
+			return File{}, err
+		}
+	default:
+		if err := copyFile(src, dst); err != nil {
+			return File{}, err
+		}
+	}
+
+	f.Size, f.SHA256, err = hashFile(dst)
+	return f, err
+}
+
+var (
+	sqliteHeader = []byte("SQLite format 3\x00")
+	boltMagic    = []byte{0xED, 0xDA, 0x0C, 0xED} // bbolt meta page magic, little endian, after the 16 byte page header
+)
+
+func detectKind(p string) (Kind, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	head := make([]byte, 20)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	head = head[:n]
+	switch {
+	case bytes.HasPrefix(head, sqliteHeader):
+		return KindSQLite, nil
+	case len(head) == 20 && bytes.Equal(head[16:20], boltMagic):
+		return KindBolt, nil
+	}
+	return KindFile, nil
+}
+
+func sqliteVersion(ctx context.Context, p string) (sqlite.ApplicationID, uint32, error) {
+	db, err := sqlite.Open(ctx, p)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer db.Close()
+	return db.Version(ctx)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func hashFile(p string) (int64, string, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, file)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeArchive writes the manifest, then each file in the manifest from dir, as a gzipped tar to w.
+func writeArchive(w io.Writer, key []byte, manifest Manifest, dir string) (err error) {
+	if key != nil {
+		enc, err := newEncryptWriter(w, key)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err == nil {
+				err = enc.Close()
+			}
+		}()
+		w = enc
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Name:     manifestName,
+		Mode:     0600,
+		Size:     int64(len(manifestJSON)),
+		ModTime:  manifest.CreateTime,
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestJSON); err != nil {
+		return err
+	}
+
+	files := slices.Clone(manifest.Files)
+	slices.SortFunc(files, func(a, b File) int { return strings.Compare(a.Path, b.Path) })
+	for _, f := range files {
+		if err := writeArchiveFile(tw, dir, f, manifest.CreateTime); err != nil {
+			return fmt.Errorf("%s: %w", f.Path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeArchiveFile(tw *tar.Writer, dir string, f File, modTime time.Time) error {
+	file, err := os.Open(filepath.Join(dir, filepath.FromSlash(f.Path)))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	err = tw.WriteHeader(&tar.Header{
+		Name:     dataPrefix + f.Path,
+		Mode:     int64(f.Mode),
+		Size:     f.Size,
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// logManifest logs a summary of m.
+func logManifest(logger *zap.Logger, msg string, m Manifest) {
+	var size int64
+	for _, f := range m.Files {
+		size += f.Size
+	}
+	logger.Info(msg, zap.Time("createTime", m.CreateTime), zap.String("node", m.Node),
+		zap.Int("files", len(m.Files)), zap.Int64("size", size))
+}
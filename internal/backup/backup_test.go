@@ -0,0 +1,365 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.etcd.io/bbolt"
+
+	"github.com/smart-core-os/sc-bos/internal/sqlite"
+)
+
+const testAppID sqlite.ApplicationID = 0x7e570001
+
+var testMigrations = []sqlite.Migration{
+	{Version: 1, SQL: "CREATE TABLE things (name TEXT PRIMARY KEY);"},
+	{Version: 2, SQL: "ALTER TABLE things ADD COLUMN value TEXT;"},
+	{Version: 3, SQL: "CREATE INDEX things_value ON things (value);"},
+}
+
+func init() {
+	// this build only knows about the first two migrations
+	sqlite.RegisterSchema("test", testAppID, mustSchema(testMigrations[:2]))
+}
+
+func TestCreateRestore(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		t.Run(map[bool]string{false: "plain", true: "encrypted"}[encrypt], func(t *testing.T) {
+			ctx := context.Background()
+			dataDir := filepath.Join(t.TempDir(), "data")
+			writeTestDB(t, filepath.Join(dataDir, "things.db"), testMigrations[:2])
+			writeFile(t, filepath.Join(dataDir, "config", "app.conf.json"), `{"drivers":[]}`)
+			writeFile(t, filepath.Join(dataDir, "scratch.tmp"), "temporary")
+			writeFile(t, filepath.Join(dataDir, "backups", "old.tar.gz"), "an old backup")
+			boltDB := openTestBolt(t, filepath.Join(dataDir, "db.bolt"))
+
+			var opts []Option
+			if encrypt {
+				opts = append(opts, WithKey(testKey(1)))
+			}
+			var archive bytes.Buffer
+			m, err := Create(ctx, &archive, dataDir, append(opts,
+				WithBolt("db.bolt", boltDB),
+				WithExclude("backups"),
+				WithSource("test-node", "v1.2.3"),
+			)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(map[string]Kind{
+				"config/app.conf.json": KindFile,
+				"db.bolt":              KindBolt,
+				"things.db":            KindSQLite,
+			}, fileKinds(m)); diff != "" {
+				t.Fatalf("manifest files (-want,+got)\n%s", diff)
+			}
+			if _, err := ReadManifest(bytes.NewReader(archive.Bytes()), nil); errors.Is(err, ErrEncrypted) != encrypt {
+				t.Fatalf("ReadManifest without key error = %v, want encrypted %t", err, encrypt)
+			}
+
+			// change the data after the backup was taken
+			writeFile(t, filepath.Join(dataDir, "config", "app.conf.json"), `{"changed":true}`)
+			writeFile(t, filepath.Join(dataDir, "new.txt"), "created after the backup")
+
+			if _, err := Restore(ctx, bytes.NewReader(archive.Bytes()), dataDir, opts...); !errors.Is(err, ErrInUse) {
+				t.Fatalf("Restore while in use error = %v, want %v", err, ErrInUse)
+			}
+			if err := boltDB.Close(); err != nil {
+				t.Fatal(err)
+			}
+			res, err := Restore(ctx, bytes.NewReader(archive.Bytes()), dataDir, append(opts, WithKeep("backups"))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Warnings) > 0 {
+				t.Errorf("unexpected warnings: %v", res.Warnings)
+			}
+			if res.Manifest.Node != "test-node" || res.Manifest.Version != "v1.2.3" {
+				t.Errorf("manifest source = %q %q, want %q %q", res.Manifest.Node, res.Manifest.Version, "test-node", "v1.2.3")
+			}
+
+			assertFile(t, filepath.Join(dataDir, "config", "app.conf.json"), `{"drivers":[]}`)
+			assertFile(t, filepath.Join(dataDir, "backups", "old.tar.gz"), "an old backup")
+			assertFile(t, filepath.Join(res.PreviousDir, "new.txt"), "created after the backup")
+			for _, name := range []string{"new.txt", "scratch.tmp"} {
+				if _, err := os.Stat(filepath.Join(dataDir, name)); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("%s exists after restore, err = %v", name, err)
+				}
+			}
+			if got := readTestDB(t, filepath.Join(dataDir, "things.db")); got != "value1" {
+				t.Errorf("sqlite value = %q, want %q", got, "value1")
+			}
+			if got := readTestBolt(t, filepath.Join(dataDir, "db.bolt")); got != "value1" {
+				t.Errorf("bolt value = %q, want %q", got, "value1")
+			}
+		})
+	}
+}
+
+func TestRestore_invalid(t *testing.T) {
+	ctx := context.Background()
+	dataDir := filepath.Join(t.TempDir(), "data")
+	writeTestDB(t, filepath.Join(dataDir, "things.db"), testMigrations[:2])
+	writeFile(t, filepath.Join(dataDir, "notes.txt"), strings.Repeat("some notes\n", 1000))
+
+	var plain, encrypted bytes.Buffer
+	if _, err := Create(ctx, &plain, dataDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Create(ctx, &encrypted, dataDir, WithKey(testKey(1))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(ctx, bytes.NewReader(plain.Bytes())); err != nil {
+		t.Fatalf("Verify valid backup: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		archive []byte
+		opts    []Option
+		wantErr error
+	}{
+		{name: "truncated", archive: plain.Bytes()[:plain.Len()/2]},
+		{name: "corrupt", archive: flipByte(plain.Bytes(), plain.Len()/2)},
+		{name: "no key", archive: encrypted.Bytes(), wantErr: ErrEncrypted},
+		{name: "wrong key", archive: encrypted.Bytes(), opts: []Option{WithKey(testKey(2))}},
+		{name: "encrypted truncated", archive: encrypted.Bytes()[:encrypted.Len()-10], opts: []Option{WithKey(testKey(1))}},
+		{name: "encrypted corrupt", archive: flipByte(encrypted.Bytes(), encrypted.Len()/2), opts: []Option{WithKey(testKey(1))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Restore(ctx, bytes.NewReader(tt.archive), dataDir, tt.opts...)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			assertFile(t, filepath.Join(dataDir, "notes.txt"), strings.Repeat("some notes\n", 1000))
+		})
+	}
+}
+
+func TestRestore_newerSchema(t *testing.T) {
+	ctx := context.Background()
+	srcDir := t.TempDir()
+	writeTestDB(t, filepath.Join(srcDir, "things.db"), testMigrations)
+	var archive bytes.Buffer
+	if _, err := Create(ctx, &archive, srcDir); err != nil {
+		t.Fatal(err)
+	}
+
+	dataDir := filepath.Join(t.TempDir(), "data")
+	writeFile(t, filepath.Join(dataDir, "notes.txt"), "existing")
+	_, err := Restore(ctx, bytes.NewReader(archive.Bytes()), dataDir)
+	if err == nil || !strings.Contains(err.Error(), "newer than this build supports") {
+		t.Fatalf("Restore error = %v, want newer schema error", err)
+	}
+	assertFile(t, filepath.Join(dataDir, "notes.txt"), "existing")
+}
+
+func TestDir(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	writeFile(t, filepath.Join(dataDir, "notes.txt"), "notes")
+	dir := NewDir(filepath.Join(t.TempDir(), "backups"), nil)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		now := start.Add(time.Duration(i) * time.Hour)
+		if _, err := dir.Create(ctx, dataDir, WithNow(func() time.Time { return now })); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := listIDs(t, dir); len(got) != 4 {
+		t.Fatalf("List returned %d backups, want 4: %v", len(got), got)
+	}
+
+	if err := dir.Prune(3, 0, start); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"backup-20240101T010000.000Z.tar.gz",
+		"backup-20240101T020000.000Z.tar.gz",
+		"backup-20240101T030000.000Z.tar.gz",
+	}
+	if diff := cmp.Diff(want, listIDs(t, dir)); diff != "" {
+		t.Fatalf("after prune by count (-want,+got)\n%s", diff)
+	}
+
+	// the newest backup is kept, even when it's too old
+	if err := dir.Prune(0, time.Hour, start.Add(24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want[2:], listIDs(t, dir)); diff != "" {
+		t.Fatalf("after prune by age (-want,+got)\n%s", diff)
+	}
+
+	f, info, err := dir.Open(want[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	m, err := ReadManifest(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.CreateTime.Equal(info.CreateTime) {
+		t.Fatalf("manifest create time %v, want %v", m.CreateTime, info.CreateTime)
+	}
+
+	if err := dir.Delete("../notes.txt"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete outside dir error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func mustSchema(migrations []sqlite.Migration) sqlite.Schema {
+	s, err := sqlite.NewVersionedSchema(migrations)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func writeTestDB(t *testing.T, path string, migrations []sqlite.Migration) {
+	t.Helper()
+	ctx := context.Background()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.Open(ctx, path, sqlite.WithApplicationID(testAppID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Migrate(ctx, mustSchema(migrations)); err != nil {
+		t.Fatal(err)
+	}
+	err = db.WriteTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO things (name, value) VALUES ('key1', 'value1')")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestDB(t *testing.T, path string) string {
+	t.Helper()
+	ctx := context.Background()
+	db, err := sqlite.Open(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var value string
+	err = db.ReadTx(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, "SELECT value FROM things WHERE name = 'key1'").Scan(&value)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func openTestBolt(t *testing.T, path string) *bbolt.DB {
+	t.Helper()
+	db, err := bbolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	err = db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte("things"))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("key1"), []byte("value1"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func readTestBolt(t *testing.T, path string) string {
+	t.Helper()
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var value string
+	err = db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte("things"))
+		if b == nil {
+			return errors.New("bucket missing")
+		}
+		value = string(b.Get([]byte("key1")))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Fatalf("%s = %q, want %q", path, got, want)
+	}
+}
+
+func fileKinds(m Manifest) map[string]Kind {
+	kinds := make(map[string]Kind)
+	for _, f := range m.Files {
+		kinds[f.Path] = f.Kind
+	}
+	return kinds
+}
+
+func listIDs(t *testing.T, dir *Dir) []string {
+	t.Helper()
+	infos, err := dir.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, info := range infos {
+		ids = append(ids, info.ID)
+	}
+	return ids
+}
+
+func flipByte(data []byte, i int) []byte {
+	data = bytes.Clone(data)
+	data[i] ^= 0xff
+	return data
+}
+
+func testKey(seed byte) []byte {
+	return bytes.Repeat([]byte{seed}, KeySize)
+}
@@ -0,0 +1,192 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// KeySize is the size in bytes of keys used to encrypt backups.
+const KeySize = 32
+
+// ErrEncrypted is returned when reading an encrypted backup without a key.
+var ErrEncrypted = errors.New("backup is encrypted, a key is needed")
+
+// Encrypted archives start with encMagic, followed by a random nonce prefix.
+// The rest of the archive is a sequence of chunks, each encrypted using AES-256-GCM:
+//
+//	flag (1 byte) | length (4 bytes, big endian) | ciphertext (length bytes)
+//
+// The nonce of each chunk is the prefix followed by the chunk index.
+// The header and flag are authenticated with each chunk, the flag marks the last chunk, so truncated archives are
+// detected.
+const (
+	encMagic       = "SCBOSBK1"
+	encPrefixSize  = 8
+	encChunkSize   = 64 * 1024
+	encFlagMore    = 0
+	encFlagLast    = 1
+	encMaxChunkLen = encChunkSize + 16 // plus the GCM tag
+)
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key is %d bytes, want %d", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptWriter encrypts everything written to it, writing the result to w.
+// Close must be called to write the last chunk, it does not close w.
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	buf    []byte
+	index  uint32
+}
+
+func newEncryptWriter(w io.Writer, key []byte) (*encryptWriter, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(encMagic)+encPrefixSize)
+	copy(header, encMagic)
+	if _, err := rand.Read(header[len(encMagic):]); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{w: w, aead: aead, header: header, buf: make([]byte, 0, encChunkSize)}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if len(e.buf) == encChunkSize {
+			// only write a full chunk once we know there's more to come, the last chunk is written by Close
+			if err := e.writeChunk(encFlagMore); err != nil {
+				return n, err
+			}
+		}
+		c := copy(e.buf[len(e.buf):encChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.writeChunk(encFlagLast)
+}
+
+func (e *encryptWriter) writeChunk(flag byte) error {
+	ciphertext := e.aead.Seal(nil, chunkNonce(e.header, e.index), e.buf, chunkAD(e.header, flag))
+	var prefix [5]byte
+	prefix[0] = flag
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(ciphertext)))
+	if _, err := e.w.Write(prefix[:]); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(ciphertext); err != nil {
+		return err
+	}
+	e.buf = e.buf[:0]
+	e.index++
+	return nil
+}
+
+// decryptReader decrypts an archive written by encryptWriter.
+type decryptReader struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	header []byte
+	buf    []byte
+	index  uint32
+	last   bool
+}
+
+func newDecryptReader(r io.Reader, key []byte) (*decryptReader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(encMagic)+encPrefixSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if string(header[:len(encMagic)]) != encMagic {
+		return nil, errors.New("not an encrypted backup")
+	}
+	return &decryptReader{r: r, aead: aead, header: header}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.last {
+			return 0, io.EOF
+		}
+		if err := d.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *decryptReader) readChunk() error {
+	var prefix [5]byte
+	if _, err := io.ReadFull(d.r, prefix[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF // we haven't seen the last chunk
+		}
+		return fmt.Errorf("read chunk: %w", err)
+	}
+	flag, n := prefix[0], binary.BigEndian.Uint32(prefix[1:])
+	if flag != encFlagMore && flag != encFlagLast || n > encMaxChunkLen {
+		return errors.New("corrupt chunk")
+	}
+	ciphertext := make([]byte, n)
+	if _, err := io.ReadFull(d.r, ciphertext); err != nil {
+		return fmt.Errorf("read chunk: %w", err)
+	}
+	plaintext, err := d.aead.Open(ciphertext[:0], chunkNonce(d.header, d.index), ciphertext, chunkAD(d.header, flag))
+	if err != nil {
+		return fmt.Errorf("decrypt chunk %d, is the key correct? %w", d.index, err)
+	}
+	d.buf = plaintext
+	d.index++
+	d.last = flag == encFlagLast
+	return nil
+}
+
+func chunkNonce(header []byte, index uint32) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, header[len(encMagic):])
+	binary.BigEndian.PutUint32(nonce[encPrefixSize:], index)
+	return nonce
+}
+
+func chunkAD(header []byte, flag byte) []byte {
+	return append(bytes.Clone(header), flag)
+}
+
+// isEncrypted reports whether the archive read by r is encrypted, without consuming any of it.
+func isEncrypted(r *bufio.Reader) bool {
+	magic, _ := r.Peek(len(encMagic))
+	return string(magic) == encMagic
+}
@@ -0,0 +1,191 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrNotFound is returned when a backup doesn't exist in a Dir.
+var ErrNotFound = errors.New("backup not found")
+
+const (
+	archiveExt   = ".tar.gz"
+	encryptedExt = ".enc"
+	idTimeFormat = "20060102T150405.000Z"
+)
+
+var idRegexp = regexp.MustCompile(`^backup-(\d{8}T\d{6}\.\d{3}Z)\.tar\.gz(\.enc)?$`)
+
+// Info describes a backup stored in a Dir.
+type Info struct {
+	ID         string // the file name of the archive
+	CreateTime time.Time
+	Size       int64
+	Encrypted  bool
+}
+
+// Dir is a directory of backups, like those created on a schedule.
+type Dir struct {
+	path   string
+	logger *zap.Logger
+}
+
+func NewDir(path string, logger *zap.Logger) *Dir {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Dir{path: path, logger: logger}
+}
+
+// Create writes a new backup of dataDir to d.
+// The backup is only visible in d once it is complete.
+func (d *Dir) Create(ctx context.Context, dataDir string, opts ...Option) (Info, error) {
+	o := resolveOpts(opts...)
+	now := o.now().UTC()
+	opts = append(opts, WithNow(func() time.Time { return now }))
+	id := "backup-" + now.Format(idTimeFormat) + archiveExt
+	if o.key != nil {
+		id += encryptedExt
+	}
+
+	if err := os.MkdirAll(d.path, 0700); err != nil {
+		return Info{}, err
+	}
+	tmp, err := os.CreateTemp(d.path, id+".*.tmp")
+	if err != nil {
+		return Info{}, err
+	}
+	defer os.Remove(tmp.Name()) // no-op if the rename succeeded
+	m, err := Create(ctx, tmp, dataDir, opts...)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	dst := filepath.Join(d.path, id)
+	if _, err := os.Stat(dst); err == nil {
+		return Info{}, fmt.Errorf("backup %s already exists", id)
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return Info{}, err
+	}
+	logManifest(d.logger, "created backup", m)
+	return d.info(id)
+}
+
+// List returns the backups in d, oldest first.
+func (d *Dir) List() ([]Info, error) {
+	entries, err := os.ReadDir(d.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var infos []Info
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !idRegexp.MatchString(e.Name()) {
+			continue
+		}
+		info, err := d.info(e.Name())
+		if errors.Is(err, ErrNotFound) {
+			continue // deleted since we read the dir
+		}
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].CreateTime.Before(infos[j].CreateTime)
+	})
+	return infos, nil
+}
+
+// Open returns the archive of the backup with the given id.
+func (d *Dir) Open(id string) (io.ReadCloser, Info, error) {
+	info, err := d.info(id)
+	if err != nil {
+		return nil, Info{}, err
+	}
+	f, err := os.Open(filepath.Join(d.path, id))
+	if errors.Is(err, os.ErrNotExist) {
+		err = fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return f, info, err
+}
+
+// Delete removes the backup with the given id.
+func (d *Dir) Delete(id string) error {
+	if _, err := d.info(id); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(d.path, id))
+	if errors.Is(err, os.ErrNotExist) {
+		err = fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return err
+}
+
+// Prune removes backups older than maxAge, and all but the newest maxCount backups.
+// A zero value for either argument means no limit.
+// The newest backup is always kept.
+func (d *Dir) Prune(maxCount int, maxAge time.Duration, now time.Time) error {
+	infos, err := d.List()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i, info := range infos {
+		remaining := len(infos) - i
+		if remaining <= 1 {
+			break
+		}
+		tooMany := maxCount > 0 && remaining > maxCount
+		tooOld := maxAge > 0 && now.Sub(info.CreateTime) > maxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := d.Delete(info.ID); err != nil && !errors.Is(err, ErrNotFound) {
+			errs = append(errs, err)
+			continue
+		}
+		d.logger.Debug("removed old backup", zap.String("id", info.ID))
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Dir) info(id string) (Info, error) {
+	m := idRegexp.FindStringSubmatch(id)
+	if m == nil {
+		return Info{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	t, err := time.Parse(idTimeFormat, m[1])
+	if err != nil {
+		return Info{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	stat, err := os.Stat(filepath.Join(d.path, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return Info{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{
+		ID:         id,
+		CreateTime: t,
+		Size:       stat.Size(),
+		Encrypted:  strings.HasSuffix(id, encryptedExt),
+	}, nil
+}
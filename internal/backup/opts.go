@@ -0,0 +1,109 @@
+package backup
+
+import (
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+type opts struct {
+	logger  *zap.Logger
+	now     func() time.Time
+	key     []byte
+	node    string
+	version string
+	bolt    map[string]*bbolt.DB
+	exclude []string
+	keep    []string
+	dryRun  bool
+}
+
+func resolveOpts(options ...Option) opts {
+	o := opts{
+		exclude: defaultExclude,
+	}
+	for _, option := range options {
+		option(&o)
+	}
+	if o.logger == nil {
+		o.logger = zap.NewNop()
+	}
+	if o.now == nil {
+		o.now = time.Now
+	}
+	return o
+}
+
+// defaultExclude are files that are never part of a backup.
+// Temporary files are transient, and sqlite journals are included in the snapshot of their database.
+var defaultExclude = []string{"*.tmp", "*-wal", "*-shm", "*-journal"}
+
+type Option func(*opts)
+
+// WithLogger is an option to set the logger.
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *opts) {
+		o.logger = logger
+	}
+}
+
+// WithNow is an option to set the clock used to timestamp backups.
+func WithNow(now func() time.Time) Option {
+	return func(o *opts) {
+		o.now = now
+	}
+}
+
+// WithKey is an option to encrypt new backups, or decrypt existing backups, using key.
+// The key must be KeySize bytes.
+func WithKey(key []byte) Option {
+	return func(o *opts) {
+		o.key = key
+	}
+}
+
+// WithSource is an option to record the name and version of the node that created the backup.
+func WithSource(node, version string) Option {
+	return func(o *opts) {
+		o.node = node
+		o.version = version
+	}
+}
+
+// WithBolt is an option to snapshot the bolt database at path, relative to the data dir, using db.
+// A bolt database can only be opened by one process, databases in use must be given using this option to be backed
+// up.
+func WithBolt(path string, db *bbolt.DB) Option {
+	return func(o *opts) {
+		if o.bolt == nil {
+			o.bolt = make(map[string]*bbolt.DB)
+		}
+		o.bolt[path] = db
+	}
+}
+
+// WithExclude is an option to exclude files or directories from backups.
+// Patterns are matched using [path.Match] against the slash separated path relative to the data dir, and against the
+// base name of each file.
+func WithExclude(patterns ...string) Option {
+	return func(o *opts) {
+		o.exclude = append(o.exclude[:len(o.exclude):len(o.exclude)], patterns...)
+	}
+}
+
+// WithKeep is an option for Restore to move the given paths, relative to the data dir, from the existing data dir
+// into the restored one.
+// This is typically used for directories excluded from backups, like the directory backups are stored in.
+func WithKeep(paths ...string) Option {
+	return func(o *opts) {
+		o.keep = append(o.keep, paths...)
+	}
+}
+
+// WithDryRun is an option for Restore to check the backup can be restored, without changing the data dir.
+func WithDryRun() Option {
+	return func(o *opts) {
+		o.dryRun = true
+	}
+}
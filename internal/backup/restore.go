@@ -0,0 +1,311 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/internal/sqlite"
+)
+
+// ErrInUse is returned by Restore when the data dir is being used by a running controller.
+var ErrInUse = errors.New("data dir is in use, stop the controller before restoring")
+
+// Result describes the outcome of Restore.
+type Result struct {
+	Manifest Manifest
+	// Warnings about the backup that didn't prevent it being restored,
+	// for example databases this build doesn't know about.
+	Warnings []string
+	// Where the data dir was moved to before the backup was restored in its place.
+	// Empty if there was no data dir, or for a dry run.
+	PreviousDir string
+}
+
+// Restore replaces dataDir with the backup read from r.
+//
+// The backup is extracted next to dataDir and checked before anything is replaced: each file must match the
+// manifest, and each database must be usable by this build.
+// Databases from a newer build, with schema migrations this build doesn't know about, can't be restored.
+// The existing data dir is kept, renamed, see Result.PreviousDir.
+//
+// The data dir must not be in use, Restore returns ErrInUse if it can tell the controller is running.
+func Restore(ctx context.Context, r io.Reader, dataDir string, opts ...Option) (Result, error) {
+	o := resolveOpts(opts...)
+	dataDir = filepath.Clean(dataDir)
+	suffix := o.now().UTC().Format("20060102T150405Z")
+	staging := dataDir + ".restore-" + suffix
+	if o.dryRun {
+		var err error
+		staging, err = os.MkdirTemp("", "restore-")
+		if err != nil {
+			return Result{}, err
+		}
+	} else if err := os.Mkdir(staging, 0750); err != nil {
+		return Result{}, err
+	}
+	swapped := false
+	defer func() {
+		if !swapped {
+			os.RemoveAll(staging)
+		}
+	}()
+
+	manifest, err := extract(r, o.key, staging)
+	if err != nil {
+		return Result{}, err
+	}
+	res := Result{Manifest: manifest}
+	res.Warnings, err = check(ctx, manifest, staging)
+	if err != nil {
+		return res, err
+	}
+	if o.dryRun {
+		return res, nil
+	}
+
+	if err := checkNotInUse(dataDir, manifest); err != nil {
+		return res, err
+	}
+	if _, err := os.Stat(dataDir); err == nil {
+		res.PreviousDir = dataDir + ".before-restore-" + suffix
+		if err := os.Rename(dataDir, res.PreviousDir); err != nil {
+			return res, err
+		}
+	}
+	if err := os.Rename(staging, dataDir); err != nil {
+		if res.PreviousDir != "" {
+			if undoErr := os.Rename(res.PreviousDir, dataDir); undoErr != nil {
+				err = errors.Join(err, fmt.Errorf("data dir left at %s: %w", res.PreviousDir, undoErr))
+			}
+		}
+		return res, err
+	}
+	swapped = true
+	if res.PreviousDir != "" {
+		for _, keep := range o.keep {
+			if err := moveKept(res.PreviousDir, dataDir, keep); err != nil {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("keep %s: %v", keep, err))
+			}
+		}
+	}
+	logManifest(o.logger, "restored backup", manifest)
+	if res.PreviousDir != "" {
+		o.logger.Info("previous data dir kept", zap.String("path", res.PreviousDir))
+	}
+	return res, nil
+}
+
+// moveKept moves rel from the old data dir to the new one, if it exists in old and not in new.
+func moveKept(oldDir, newDir, rel string) error {
+	src := filepath.Join(oldDir, filepath.FromSlash(rel))
+	if _, err := os.Stat(src); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	dst := filepath.Join(newDir, filepath.FromSlash(rel))
+	if _, err := os.Stat(dst); err == nil {
+		return errors.New("also in the backup, not moved")
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return err
+	}
+	return os.Rename(src, dst)
+}
+
+// Verify checks that the backup read from r is complete and could be restored by this build.
+func Verify(ctx context.Context, r io.Reader, opts ...Option) (Result, error) {
+	return Restore(ctx, r, "", append(opts, WithDryRun())...)
+}
+
+// ReadManifest returns the manifest of the backup read from r, without reading the rest of the backup.
+func ReadManifest(r io.Reader, key []byte) (Manifest, error) {
+	tr, err := openArchive(r, key)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return readManifest(tr)
+}
+
+func openArchive(r io.Reader, key []byte) (*tar.Reader, error) {
+	br := bufio.NewReader(r)
+	r = br
+	if isEncrypted(br) {
+		if key == nil {
+			return nil, ErrEncrypted
+		}
+		dr, err := newDecryptReader(br, key)
+		if err != nil {
+			return nil, err
+		}
+		r = dr
+	}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup: %w", err)
+	}
+	return tar.NewReader(gz), nil
+}
+
+func readManifest(tr *tar.Reader) (Manifest, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return Manifest{}, fmt.Errorf("read manifest: %w", err)
+	}
+	if hdr.Name != manifestName {
+		return Manifest{}, fmt.Errorf("not a backup: first entry is %q, want %q", hdr.Name, manifestName)
+	}
+	var m Manifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return Manifest{}, fmt.Errorf("read manifest: %w", err)
+	}
+	if m.FormatVersion > FormatVersion {
+		return m, fmt.Errorf("backup format version %d is newer than supported version %d", m.FormatVersion, FormatVersion)
+	}
+	return m, nil
+}
+
+// extract writes the files in the backup read from r to dir, checking they match the manifest.
+func extract(r io.Reader, key []byte, dir string) (Manifest, error) {
+	tr, err := openArchive(r, key)
+	if err != nil {
+		return Manifest{}, err
+	}
+	m, err := readManifest(tr)
+	if err != nil {
+		return m, err
+	}
+	want := make(map[string]File, len(m.Files))
+	for _, f := range m.Files {
+		want[f.Path] = f
+	}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return m, err
+		}
+		rel, ok := strings.CutPrefix(hdr.Name, dataPrefix)
+		if !ok || hdr.Typeflag != tar.TypeReg || !filepath.IsLocal(filepath.FromSlash(rel)) || path.Clean(rel) != rel {
+			return m, fmt.Errorf("unexpected entry %q", hdr.Name)
+		}
+		f, ok := want[rel]
+		if !ok {
+			return m, fmt.Errorf("%s: not in manifest", rel)
+		}
+		delete(want, rel)
+		dst := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := extractFile(tr, dst, f); err != nil {
+			return m, fmt.Errorf("%s: %w", rel, err)
+		}
+	}
+	for rel := range want {
+		return m, fmt.Errorf("%s: missing from backup", rel)
+	}
+	return m, nil
+}
+
+func extractFile(r io.Reader, dst string, f File) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, f.Mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	size, sum, err := hashFile(dst)
+	if err != nil {
+		return err
+	}
+	if size != f.Size || sum != f.SHA256 {
+		return errors.New("contents don't match manifest")
+	}
+	return nil
+}
+
+// check returns an error if any database in dir can't be used by this build.
+func check(ctx context.Context, m Manifest, dir string) (warnings []string, err error) {
+	for _, f := range m.Files {
+		p := filepath.Join(dir, filepath.FromSlash(f.Path))
+		switch f.Kind {
+		case KindSQLite:
+			appID, version, err := sqliteVersion(ctx, p)
+			if err != nil {
+				return warnings, fmt.Errorf("%s: %w", f.Path, err)
+			}
+			known, ok := sqlite.LookupSchema(appID)
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("%s: unknown database application id %#x, it may not be used", f.Path, uint32(appID)))
+				continue
+			}
+			if latest := known.Schema.LatestVersion(); version > latest {
+				return warnings, fmt.Errorf("%s: %s database schema version %d is newer than this build supports (%d), upgrade before restoring",
+					f.Path, known.Name, version, latest)
+			}
+		case KindBolt:
+			if err := checkBolt(p); err != nil {
+				return warnings, fmt.Errorf("%s: %w", f.Path, err)
+			}
+		}
+	}
+	return warnings, nil
+}
+
+func checkBolt(p string) error {
+	db, err := bbolt.Open(p, 0, &bbolt.Options{ReadOnly: true, Timeout: time.Second})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.View(func(tx *bbolt.Tx) error {
+		var errs []error
+		for err := range tx.Check() {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// checkNotInUse returns ErrInUse if any bolt database in dataDir that would be replaced is open.
+// Bolt databases are locked while open, unlike other files, so are a good indication of a running controller.
+func checkNotInUse(dataDir string, m Manifest) error {
+	for _, f := range m.Files {
+		if f.Kind != KindBolt {
+			continue
+		}
+		p := filepath.Join(dataDir, filepath.FromSlash(f.Path))
+		if _, err := os.Stat(p); err != nil {
+			continue
+		}
+		db, err := bbolt.Open(p, 0, &bbolt.Options{ReadOnly: true, Timeout: time.Second})
+		if errors.Is(err, bbolt.ErrTimeout) {
+			return fmt.Errorf("%w: %s is locked", ErrInUse, f.Path)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Path, err)
+		}
+		db.Close()
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/page"
+	"github.com/smart-core-os/sc-golang/pkg/masks"
+)
+
+// downloadChunkSize is the size of each chunk sent by DownloadBackup.
+const downloadChunkSize = 64 * 1024
+
+// Server is a [gen.BackupApiServer] for the backups in a Dir.
+type Server struct {
+	gen.UnimplementedBackupApiServer
+	dir    *Dir
+	create func(ctx context.Context) (Info, error)
+}
+
+// NewServer returns a Server for the backups in dir.
+// New backups are created by calling create, which should store the backup in dir.
+func NewServer(dir *Dir, create func(ctx context.Context) (Info, error)) *Server {
+	return &Server{dir: dir, create: create}
+}
+
+func (s *Server) ListBackups(_ context.Context, req *gen.ListBackupsRequest) (*gen.ListBackupsResponse, error) {
+	var listErr error
+	backups, totalSize, nextPageToken, err := page.List(req, func(i Info) string { return i.ID }, func() []Info {
+		infos, err := s.dir.List()
+		listErr = err
+		return infos
+	})
+	if listErr != nil {
+		return nil, listErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	filter := masks.NewResponseFilter(masks.WithFieldMask(req.GetReadMask()))
+	res := &gen.ListBackupsResponse{
+		TotalSize:     int32(totalSize),
+		NextPageToken: nextPageToken,
+	}
+	for _, info := range backups {
+		res.Backups = append(res.Backups, filter.FilterClone(infoToProto(info)).(*gen.Backup))
+	}
+	return res, nil
+}
+
+func (s *Server) CreateBackup(ctx context.Context, _ *gen.CreateBackupRequest) (*gen.Backup, error) {
+	info, err := s.create(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return infoToProto(info), nil
+}
+
+func (s *Server) DownloadBackup(req *gen.DownloadBackupRequest, stream grpc.ServerStreamingServer[gen.DownloadBackupResponse]) error {
+	f, _, err := s.dir.Open(req.GetId())
+	if err != nil {
+		return dirErr(err)
+	}
+	defer f.Close()
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&gen.DownloadBackupResponse{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) DeleteBackup(_ context.Context, req *gen.DeleteBackupRequest) (*gen.DeleteBackupResponse, error) {
+	err := s.dir.Delete(req.GetId())
+	if errors.Is(err, ErrNotFound) && req.GetAllowMissing() {
+		err = nil
+	}
+	if err != nil {
+		return nil, dirErr(err)
+	}
+	return &gen.DeleteBackupResponse{}, nil
+}
+
+func dirErr(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return err
+}
+
+func infoToProto(info Info) *gen.Backup {
+	return &gen.Backup{
+		Id:         info.ID,
+		CreateTime: timestamppb.New(info.CreateTime),
+		Size:       info.Size,
+		Encrypted:  info.Encrypted,
+	}
+}
@@ -24,6 +24,10 @@ const appID = 0x5C0503
 var schemaVersionsFS embed.FS
 var schema = sqlite.MustLoadVersionedSchema(schemaVersionsFS, "schema")
 
+func init() {
+	sqlite.RegisterSchema("health", appID, schema)
+}
+
 // DB is a store for Records
 type DB struct {
 	db *sqlite.Database
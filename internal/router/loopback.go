@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -25,11 +27,13 @@ func NewLoopback(mr MethodResolver) *Loopback {
 	return &Loopback{mr: mr}
 }
 
-func (l *Loopback) Invoke(ctx context.Context, fullMethodName string, args any, reply any, opts ...grpc.CallOption) error {
+func (l *Loopback) Invoke(ctx context.Context, fullMethodName string, args any, reply any, opts ...grpc.CallOption) (err error) {
 	argsProto, ok := args.(proto.Message)
 	if !ok {
 		return ErrNonProtoMessage
 	}
+	ctx, span := tracer.Start(ctx, "router.Loopback/Invoke", trace.WithAttributes(attribute.String("rpc.method", fullMethodName)))
+	defer func() { endSpan(span, err) }()
 
 	method, err := l.mr.ResolveMethod(fullMethodName)
 	if err != nil {
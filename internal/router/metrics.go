@@ -0,0 +1,35 @@
+package router
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	methodResolves = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "scbos",
+		Subsystem: "router",
+		Name:      "method_resolves_total",
+		Help:      "Number of calls to ResolveMethod, by result.",
+	}, []string{"result"})
+	connResolves = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "scbos",
+		Subsystem: "router",
+		Name:      "conn_resolves_total",
+		Help:      "Number of requests routed, by service and the kind of route that matched, or none.",
+	}, []string{"service", "route"})
+)
+
+// routeLabel returns the metrics label for the kind of route id is.
+func routeLabel(id routeID) string {
+	switch {
+	case id.Service != "" && id.Key != "":
+		return "service_key"
+	case id.Key != "":
+		return "key"
+	case id.Service != "":
+		return "service"
+	default:
+		return "default"
+	}
+}
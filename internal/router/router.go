@@ -221,17 +221,21 @@ func (r *Router) ResolveMethod(fullName string) (Method, error) {
 
 	serviceName, methodName, ok := parseMethod(fullName)
 	if !ok {
+		methodResolves.WithLabelValues("missing_method").Inc()
 		return Method{}, ErrMissingMethod
 	}
 	// if the service is not registered then we certainly can't resolve the method
 	service, exists := r.services[serviceName]
 	if !exists {
+		methodResolves.WithLabelValues("unknown_service").Inc()
 		return Method{}, ErrUnknownService
 	}
 	methodDesc := service.descriptor.Methods().ByName(protoreflect.Name(methodName))
 	if methodDesc == nil {
+		methodResolves.WithLabelValues("unknown_method").Inc()
 		return Method{}, ErrUnknownMethod
 	}
+	methodResolves.WithLabelValues("ok").Inc()
 
 	connResolver := ConnResolverFunc(func(mr MsgRecver) (grpc.ClientConnInterface, error) {
 		var candidates []routeID // what routes should we try to match?
@@ -240,11 +244,13 @@ func (r *Router) ResolveMethod(fullName string) (Method, error) {
 			// we can route by key
 			key, err := keyFunc(mr)
 			if err != nil {
+				connResolves.WithLabelValues(serviceName, "error").Inc()
 				return nil, err
 			}
 			if r.keyInterceptor != nil {
 				key, err = r.keyInterceptor(key)
 				if err != nil {
+					connResolves.WithLabelValues(serviceName, "error").Inc()
 					return nil, err
 				}
 			}
@@ -266,10 +272,12 @@ func (r *Router) ResolveMethod(fullName string) (Method, error) {
 		defer r.m.RUnlock()
 		for _, candidate := range candidates {
 			if conn, exists := r.routes[candidate]; exists {
+				connResolves.WithLabelValues(serviceName, routeLabel(candidate)).Inc()
 				return conn, nil
 			}
 		}
 
+		connResolves.WithLabelValues(serviceName, "none").Inc()
 		return nil, status.Error(codes.NotFound, "no route found")
 	})
 
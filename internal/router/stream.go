@@ -6,6 +6,9 @@ import (
 	"io"
 	"sync"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -21,17 +24,23 @@ type MethodResolver interface {
 
 // StreamHandler returns a grpc.StreamHandler that routes incoming message to the appropriate downstream target.
 func StreamHandler(r MethodResolver) grpc.StreamHandler {
-	return func(_ any, serverStream grpc.ServerStream) error {
+	return func(_ any, serverStream grpc.ServerStream) (err error) {
 		method, ok := grpc.Method(serverStream.Context())
 		if !ok {
 			return ErrMissingMethod
 		}
+		spanCtx, span := tracer.Start(serverStream.Context(), "router.StreamHandler", trace.WithAttributes(attribute.String("rpc.method", method)))
+		defer func() { endSpan(span, err) }()
+
 		target, err := r.ResolveMethod(method)
 		if err != nil {
 			return err
 		}
 
-		md, _ := metadata.FromIncomingContext(serverStream.Context())
+		md, ok := metadata.FromIncomingContext(serverStream.Context())
+		if !ok {
+			md = metadata.MD{}
+		}
 		// The Connection header (i.e. Connection: keep-alive), if present in the request we send to the downstream server,
 		// will cause the gRPC internals to abort the connection returning an error like:
 		// > stream terminated by RST_STREAM with error code: PROTOCOL_ERROR
@@ -39,7 +48,9 @@ func StreamHandler(r MethodResolver) grpc.StreamHandler {
 		//
 		// See https://github.com/grpc/grpc-go/blob/c04b085930ce33ee83cc3f92dbe7632031e127a9/internal/transport/http2_server.go#L444
 		delete(md, "connection")
-		ctx := metadata.NewOutgoingContext(serverStream.Context(), md)
+		// replace any trace context from the caller with ours, so the downstream server is a child of this span
+		otel.GetTextMapPropagator().Inject(spanCtx, mdCarrier(md))
+		ctx := metadata.NewOutgoingContext(spanCtx, md)
 
 		clientCtx, stopClient := context.WithCancel(ctx)
 		defer stopClient()
@@ -51,6 +62,7 @@ func StreamHandler(r MethodResolver) grpc.StreamHandler {
 			if err != nil {
 				return nil, err
 			}
+			span.AddEvent("resolved conn")
 			return cc.NewStream(clientCtx, descriptorToStreamDesc(target.Desc), method)
 		}
 
@@ -283,13 +283,13 @@ func nodeServer(name string) *grpc.Server {
 	return grpc.NewServer(grpc.UnknownServiceHandler(StreamHandler(r)))
 }
 
-func bufConn(buf *bufconn.Listener) (*grpc.ClientConn, error) {
-	return grpc.NewClient("localhost:0",
+func bufConn(buf *bufconn.Listener, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return grpc.NewClient("localhost:0", append([]grpc.DialOption{
 		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
 			return buf.Dial()
 		}),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	}, opts...)...)
 }
 
 func serviceDescriptor(name string) protoreflect.ServiceDescriptor {
@@ -0,0 +1,94 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+)
+
+func TestStreamHandler_Tracing(t *testing.T) {
+	recorder := installSpanRecorder()
+
+	// a downstream node that records the traceparent it was called with
+	var gotParent []string
+	downstreamLis := bufconn.Listen(1024 * 1024)
+	downstream := grpc.NewServer(grpc.UnknownServiceHandler(func(_ any, ss grpc.ServerStream) error {
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		gotParent = md.Get("traceparent")
+		return status.Error(codes.Unimplemented, "not implemented")
+	}))
+	t.Cleanup(downstream.Stop)
+	go func() { _ = downstream.Serve(downstreamLis) }()
+	downstreamConn, err := bufConn(downstreamLis)
+	if err != nil {
+		t.Fatalf("bufConn(downstream) = %v", err)
+	}
+	t.Cleanup(func() { downstreamConn.Close() })
+
+	reg := New()
+	registerService(reg, traits.OnOffApi_ServiceDesc.ServiceName, downstreamConn)
+	proxy := grpc.NewServer(grpc.UnknownServiceHandler(StreamHandler(reg)), grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	proxyLis := bufconn.Listen(1024 * 1024)
+	t.Cleanup(proxy.Stop)
+	go func() { _ = proxy.Serve(proxyLis) }()
+	proxyConn, err := bufConn(proxyLis, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	if err != nil {
+		t.Fatalf("bufConn(proxy) = %v", err)
+	}
+	t.Cleanup(func() { proxyConn.Close() })
+
+	ctx, root := otel.Tracer("test").Start(context.Background(), "test")
+	// the downstream server doesn't implement OnOffApi, we only care that the call made it there
+	_, _ = traits.NewOnOffApiClient(proxyConn).GetOnOff(ctx, &traits.GetOnOffRequest{Name: "n1"})
+	root.End()
+
+	// the handler ends its span after the response has been sent
+	var span sdktrace.ReadOnlySpan
+	for deadline := time.Now().Add(time.Second); span == nil && time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		for _, s := range recorder.Ended() {
+			if s.Name() == "router.StreamHandler" && s.SpanContext().TraceID() == root.SpanContext().TraceID() {
+				span = s
+			}
+		}
+	}
+	if span == nil {
+		t.Fatalf("no router.StreamHandler span in trace %s", root.SpanContext().TraceID())
+	}
+	if len(gotParent) != 1 {
+		t.Fatalf("downstream traceparent = %v, want 1 value", gotParent)
+	}
+	wantParent := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"
+	if gotParent[0] != wantParent {
+		t.Errorf("downstream traceparent = %q, want %q", gotParent[0], wantParent)
+	}
+}
+
+var (
+	spanRecorderOnce sync.Once
+	spanRecorder     *tracetest.SpanRecorder
+)
+
+// installSpanRecorder sets the global TracerProvider to one that records spans.
+// Global tracers only delegate to the first provider set, so this is only done once and shared by all tests.
+func installSpanRecorder() *tracetest.SpanRecorder {
+	spanRecorderOnce.Do(func() {
+		spanRecorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+	})
+	return spanRecorder
+}
@@ -0,0 +1,51 @@
+package secrets
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type opts struct {
+	logger  *zap.Logger
+	now     func() time.Time
+	oldKeys [][]byte
+}
+
+func resolveOpts(options ...Option) opts {
+	o := opts{}
+	for _, option := range options {
+		option(&o)
+	}
+	if o.logger == nil {
+		o.logger = zap.NewNop()
+	}
+	if o.now == nil {
+		o.now = time.Now
+	}
+	return o
+}
+
+type Option func(*opts)
+
+// WithLogger is an option to set the logger used by the store.
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *opts) {
+		o.logger = logger
+	}
+}
+
+// WithOldKeys is an option to set keys previously used to encrypt the store.
+// Secrets encrypted using an old key are re-encrypted using the current key when the store is opened.
+func WithOldKeys(keys ...[]byte) Option {
+	return func(o *opts) {
+		o.oldKeys = append(o.oldKeys, keys...)
+	}
+}
+
+// WithNow is an option to set the clock used by the store.
+func WithNow(now func() time.Time) Option {
+	return func(o *opts) {
+		o.now = now
+	}
+}
@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/page"
+	"github.com/smart-core-os/sc-golang/pkg/masks"
+)
+
+// Server is a [gen.SecretStoreApiServer] backed by a Store.
+// Secret values are never returned.
+type Server struct {
+	gen.UnimplementedSecretStoreApiServer
+	store *Store
+}
+
+func NewServer(store *Store) *Server {
+	return &Server{store: store}
+}
+
+func (s *Server) ListStoredSecrets(_ context.Context, req *gen.ListStoredSecretsRequest) (*gen.ListStoredSecretsResponse, error) {
+	secrets, totalSize, nextPageToken, err := page.List(req, func(m Metadata) string { return m.Name }, s.store.List)
+	if err != nil {
+		return nil, err
+	}
+	filter := masks.NewResponseFilter(masks.WithFieldMask(req.GetReadMask()))
+	res := &gen.ListStoredSecretsResponse{
+		TotalSize:     int32(totalSize),
+		NextPageToken: nextPageToken,
+	}
+	for _, m := range secrets {
+		res.StoredSecrets = append(res.StoredSecrets, filter.FilterClone(metadataToProto(m)).(*gen.StoredSecret))
+	}
+	return res, nil
+}
+
+func (s *Server) SetStoredSecret(_ context.Context, req *gen.SetStoredSecretRequest) (*gen.StoredSecret, error) {
+	m, err := s.store.Set(req.GetSecretName(), req.GetSecretValue())
+	if err != nil {
+		return nil, storeErr(err)
+	}
+	return metadataToProto(m), nil
+}
+
+func (s *Server) RotateStoredSecret(_ context.Context, req *gen.RotateStoredSecretRequest) (*gen.StoredSecret, error) {
+	m, err := s.store.Rotate(req.GetSecretName(), req.GetSecretValue())
+	if err != nil {
+		return nil, storeErr(err)
+	}
+	return metadataToProto(m), nil
+}
+
+func (s *Server) DeleteStoredSecret(_ context.Context, req *gen.DeleteStoredSecretRequest) (*gen.DeleteStoredSecretResponse, error) {
+	err := s.store.Delete(req.GetSecretName())
+	if errors.Is(err, ErrNotFound) && req.GetAllowMissing() {
+		err = nil
+	}
+	if err != nil {
+		return nil, storeErr(err)
+	}
+	return &gen.DeleteStoredSecretResponse{}, nil
+}
+
+func storeErr(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrInvalidName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
+
+func metadataToProto(m Metadata) *gen.StoredSecret {
+	return &gen.StoredSecret{
+		Name:       m.Name,
+		Version:    m.Version,
+		CreateTime: timestamppb.New(m.CreateTime),
+		UpdateTime: timestamppb.New(m.UpdateTime),
+	}
+}
@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	auditlog "github.com/smart-core-os/sc-bos/internal/audit"
+	"github.com/smart-core-os/sc-bos/internal/audit/auditdb"
+	"github.com/smart-core-os/sc-bos/pkg/auth/audit"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+func TestServer_auditRedactsValues(t *testing.T) {
+	ctx := context.Background()
+	db, err := auditdb.OpenMemory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	log := auditlog.NewLog(db)
+
+	store, err := Open(filepath.Join(t.TempDir(), "secrets.json"), testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(audit.NewInterceptor(log).GRPCUnaryInterceptor()))
+	gen.RegisterSecretStoreApiServer(server, NewServer(store))
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			t.Logf("server stopped with error: %v", err)
+		}
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("localhost:0",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := gen.NewSecretStoreApiClient(conn)
+
+	if _, err := client.SetStoredSecret(ctx, &gen.SetStoredSecretRequest{SecretName: "mqtt/broker", SecretValue: "hunter2-set"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RotateStoredSecret(ctx, &gen.RotateStoredSecretRequest{SecretName: "mqtt/broker", SecretValue: "hunter2-rotate"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := auditlog.NewServer(log).ListAuditEvents(ctx, &gen.ListAuditEventsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.AuditEvents) != 2 {
+		t.Fatalf("got %d audit events, want 2", len(res.AuditEvents))
+	}
+	for _, e := range res.AuditEvents {
+		if strings.Contains(e.Request, "hunter2") {
+			t.Errorf("%s audit event contains the secret value: %s", e.Method, e.Request)
+		}
+	}
+}
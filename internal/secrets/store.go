@@ -0,0 +1,324 @@
+// Package secrets stores named secrets, like passwords and API keys, encrypted at rest.
+//
+// Secrets are kept in a single JSON file, each value encrypted using AES-256-GCM with a key provided by the caller,
+// typically read from a file or environment variable using ParseKey.
+// The name of each secret is authenticated along with its value, so encrypted values can't be swapped between names.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// KeySize is the size in bytes of keys used to encrypt secrets.
+const KeySize = 32
+
+var (
+	ErrNotFound    = errors.New("secret not found")
+	ErrInvalidName = errors.New("invalid secret name")
+)
+
+// Metadata describes a secret, without its value.
+type Metadata struct {
+	Name       string
+	Version    int64
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// Store holds secrets encrypted at rest in a file.
+type Store struct {
+	path   string
+	key    key
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu      sync.RWMutex
+	secrets map[string]record
+}
+
+// Open opens the store at path, creating it when the first secret is set if it doesn't exist.
+// key must be KeySize bytes.
+func Open(path string, k []byte, opts ...Option) (*Store, error) {
+	o := resolveOpts(opts...)
+	current, err := newKey(k)
+	if err != nil {
+		return nil, err
+	}
+	keys := map[string]key{current.id: current}
+	for _, k := range o.oldKeys {
+		old, err := newKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("old key: %w", err)
+		}
+		if _, ok := keys[old.id]; !ok {
+			keys[old.id] = old
+		}
+	}
+
+	s := &Store{
+		path:    path,
+		key:     current,
+		logger:  o.logger,
+		now:     o.now,
+		secrets: make(map[string]record),
+	}
+	var f file
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return s, nil
+	case err != nil:
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// check we can decrypt everything now, rather than when a secret is needed,
+	// re-encrypting secrets using the current key as we go
+	var reEncrypted []string
+	for name, r := range f.Secrets {
+		k, ok := keys[r.KeyID]
+		if !ok {
+			return nil, fmt.Errorf("secret %q: encrypted using unknown key %s", name, r.KeyID)
+		}
+		value, err := k.open(name, r)
+		if err != nil {
+			return nil, fmt.Errorf("secret %q: %w", name, err)
+		}
+		if k.id != current.id {
+			updated, err := current.seal(name, value)
+			if err != nil {
+				return nil, err
+			}
+			r.KeyID, r.Nonce, r.Ciphertext = updated.KeyID, updated.Nonce, updated.Ciphertext
+			reEncrypted = append(reEncrypted, name)
+		}
+		s.secrets[name] = r
+	}
+	if len(reEncrypted) > 0 {
+		if err := s.write(); err != nil {
+			return nil, fmt.Errorf("re-encrypt: %w", err)
+		}
+		sort.Strings(reEncrypted)
+		s.logger.Info("re-encrypted secrets using the current key", zap.Strings("secrets", reEncrypted))
+	}
+	return s, nil
+}
+
+// ParseKey decodes a key from text, as found in a key file or environment variable.
+// The key must be KeySize bytes, base64 encoded, for example the output of `openssl rand -base64 32`.
+func ParseKey(text []byte) ([]byte, error) {
+	k, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(text)))
+	if err != nil {
+		return nil, fmt.Errorf("key is not base64: %w", err)
+	}
+	if len(k) != KeySize {
+		return nil, fmt.Errorf("key is %d bytes, want %d", len(k), KeySize)
+	}
+	return k, nil
+}
+
+// Get returns the value of the named secret.
+func (s *Store) Get(name string) (string, error) {
+	s.mu.RLock()
+	r, ok := s.secrets[name]
+	s.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	return s.key.open(name, r)
+}
+
+// List returns all secrets sorted by name.
+func (s *Store) List() []Metadata {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Metadata, 0, len(s.secrets))
+	for name, r := range s.secrets {
+		list = append(list, r.metadata(name))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Set sets the value of the named secret, creating it if it doesn't exist.
+func (s *Store) Set(name, value string) (Metadata, error) {
+	return s.update(name, value, true)
+}
+
+// Rotate replaces the value of an existing secret.
+// Returns ErrNotFound if the secret doesn't exist.
+func (s *Store) Rotate(name, value string) (Metadata, error) {
+	return s.update(name, value, false)
+}
+
+func (s *Store) update(name, value string, create bool) (Metadata, error) {
+	if err := checkName(name); err != nil {
+		return Metadata{}, err
+	}
+	sealed, err := s.key.seal(name, value)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, exists := s.secrets[name]
+	if !exists && !create {
+		return Metadata{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	now := s.now()
+	r := sealed
+	r.Version = old.Version + 1
+	r.CreateTime = old.CreateTime
+	if !exists {
+		r.CreateTime = now
+	}
+	r.UpdateTime = now
+
+	s.secrets[name] = r
+	if err := s.write(); err != nil {
+		// keep memory consistent with what's on disk
+		if exists {
+			s.secrets[name] = old
+		} else {
+			delete(s.secrets, name)
+		}
+		return Metadata{}, err
+	}
+	return r.metadata(name), nil
+}
+
+// Delete removes the named secret.
+// Returns ErrNotFound if the secret doesn't exist.
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.secrets[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	delete(s.secrets, name)
+	if err := s.write(); err != nil {
+		s.secrets[name] = old
+		return err
+	}
+	return nil
+}
+
+// write saves the secrets to the store file, replacing the file atomically.
+// Must be called with s.mu held.
+func (s *Store) write() error {
+	data, err := json.MarshalIndent(file{Secrets: s.secrets}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op if the rename succeeded
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is empty", ErrInvalidName)
+	}
+	if strings.TrimSpace(name) != name {
+		return fmt.Errorf("%w: %q has leading or trailing space", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// file is the format of the store file.
+type file struct {
+	Secrets map[string]record `json:"secrets"`
+}
+
+type record struct {
+	KeyID      string    `json:"keyId"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+	Version    int64     `json:"version"`
+	CreateTime time.Time `json:"createTime"`
+	UpdateTime time.Time `json:"updateTime"`
+}
+
+func (r record) metadata(name string) Metadata {
+	return Metadata{Name: name, Version: r.Version, CreateTime: r.CreateTime, UpdateTime: r.UpdateTime}
+}
+
+type key struct {
+	id   string // identifies the key without revealing it
+	aead cipher.AEAD
+}
+
+func newKey(k []byte) (key, error) {
+	if len(k) != KeySize {
+		return key{}, fmt.Errorf("key is %d bytes, want %d", len(k), KeySize)
+	}
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return key{}, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return key{}, err
+	}
+	sum := sha256.Sum256(k)
+	return key{id: hex.EncodeToString(sum[:8]), aead: aead}, nil
+}
+
+// seal encrypts value, returning a record with only the encryption fields set.
+func (k key) seal(name, value string) (record, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return record{}, err
+	}
+	return record{
+		KeyID:      k.id,
+		Nonce:      nonce,
+		Ciphertext: k.aead.Seal(nil, nonce, []byte(value), []byte(name)),
+	}, nil
+}
+
+func (k key) open(name string, r record) (string, error) {
+	if len(r.Nonce) != k.aead.NonceSize() {
+		return "", errors.New("invalid nonce")
+	}
+	value, err := k.aead.Open(nil, r.Nonce, r.Ciphertext, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("decrypt: %w", err)
+	}
+	return string(value), nil
+}
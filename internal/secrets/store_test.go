@@ -0,0 +1,132 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets", "secrets.json")
+	key := testKey(1)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := WithNow(func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	})
+
+	store, err := Open(path, key, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Rotate("mqtt/broker", "pass1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Rotate missing secret error = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := store.Set("mqtt/broker", "pass1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Set("api", "key1"); err != nil {
+		t.Fatal(err)
+	}
+	m, err := store.Rotate("mqtt/broker", "pass2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Metadata{
+		Name:       "mqtt/broker",
+		Version:    2,
+		CreateTime: time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC),
+		UpdateTime: time.Date(2024, 1, 1, 0, 3, 0, 0, time.UTC),
+	}
+	if diff := cmp.Diff(want, m); diff != "" {
+		t.Fatalf("Rotate (-want,+got)\n%s", diff)
+	}
+
+	// values are encrypted at rest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("pass2")) || bytes.Contains(data, []byte("key1")) {
+		t.Fatalf("secret values stored in plain text:\n%s", data)
+	}
+
+	// reopen and read back
+	store, err = Open(path, key, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Get("mqtt/broker"); err != nil || got != "pass2" {
+		t.Fatalf("Get = %q, %v; want %q", got, err, "pass2")
+	}
+	var names []string
+	for _, m := range store.List() {
+		names = append(names, m.Name)
+	}
+	if diff := cmp.Diff([]string{"api", "mqtt/broker"}, names); diff != "" {
+		t.Fatalf("List (-want,+got)\n%s", diff)
+	}
+
+	if err := store.Delete("api"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("api"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get deleted secret error = %v, want %v", err, ErrNotFound)
+	}
+
+	// the wrong key can't open the store
+	if _, err := Open(path, testKey(2)); err == nil {
+		t.Fatal("Open with wrong key succeeded")
+	}
+}
+
+func TestStore_keyRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	oldKey, newKey := testKey(1), testKey(2)
+	store, err := Open(path, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Set("s", "value"); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = Open(path, newKey, WithOldKeys(oldKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Get("s"); err != nil || got != "value" {
+		t.Fatalf("Get = %q, %v; want %q", got, err, "value")
+	}
+
+	// the old key is no longer needed
+	store, err = Open(path, newKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Get("s"); err != nil || got != "value" {
+		t.Fatalf("Get after re-encrypt = %q, %v; want %q", got, err, "value")
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	k, err := ParseKey([]byte("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(k) != KeySize || k[31] != 31 {
+		t.Fatalf("ParseKey = %v", k)
+	}
+	if _, err := ParseKey([]byte("c2hvcnQ=")); err == nil {
+		t.Fatal("ParseKey short key succeeded")
+	}
+}
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// BackupTo writes a consistent copy of the database to a new file at path.
+// Other connections, including those from other processes, can continue to use the database during the backup.
+func (db *Database) BackupTo(ctx context.Context, path string) error {
+	_, err := db.reader.ExecContext(ctx, "VACUUM INTO ?", path)
+	return err
+}
+
+// Version returns the application ID and schema version of the database.
+func (db *Database) Version(ctx context.Context) (appID ApplicationID, version uint32, err error) {
+	err = db.ReadTx(ctx, func(tx *sql.Tx) error {
+		appID, err = getApplicationID(ctx, tx)
+		if err != nil {
+			return err
+		}
+		version, err = getUserVersion(ctx, tx)
+		return err
+	})
+	return appID, version, err
+}
+
+// LatestVersion returns the schema version a database has after all migrations in s are applied.
+func (s Schema) LatestVersion() uint32 {
+	if len(s.migrations) == 0 {
+		return 0
+	}
+	return s.migrations[len(s.migrations)-1].Version
+}
+
+// KnownSchema describes a database schema registered using RegisterSchema.
+type KnownSchema struct {
+	Name   string
+	AppID  ApplicationID
+	Schema Schema
+}
+
+var (
+	knownSchemasMu sync.RWMutex
+	knownSchemas   = map[ApplicationID]KnownSchema{}
+)
+
+// RegisterSchema records the schema used by databases with the given application ID.
+// Packages that own a database register its schema so databases from elsewhere, like backups, can be checked before
+// use.
+// Typically called from an init function.
+func RegisterSchema(name string, appID ApplicationID, schema Schema) {
+	knownSchemasMu.Lock()
+	defer knownSchemasMu.Unlock()
+	knownSchemas[appID] = KnownSchema{Name: name, AppID: appID, Schema: schema}
+}
+
+// LookupSchema returns the schema registered for appID.
+func LookupSchema(appID ApplicationID) (KnownSchema, bool) {
+	knownSchemasMu.RLock()
+	defer knownSchemasMu.RUnlock()
+	s, ok := knownSchemas[appID]
+	return s, ok
+}
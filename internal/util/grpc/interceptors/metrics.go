@@ -0,0 +1,97 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+var (
+	grpcHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "scbos",
+		Subsystem: "grpc_server",
+		Name:      "handled_total",
+		Help:      "Number of RPCs completed by the server, by method and status code.",
+	}, []string{"method", "type", "code"})
+	grpcHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "scbos",
+		Subsystem: "grpc_server",
+		Name:      "handling_seconds",
+		Help:      "Time taken for the server to complete RPCs, for streams this is the lifetime of the stream.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "type"})
+	grpcStreamsActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "scbos",
+		Subsystem: "grpc_server",
+		Name:      "streams_active",
+		Help:      "Number of streaming RPCs currently open.",
+	}, []string{"method", "type"})
+)
+
+// MetricsUnary returns an interceptor that records the count and duration of unary RPCs.
+func MetricsUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeRPC(methodLabel(info.FullMethod), "unary", start, err)
+		return resp, err
+	}
+}
+
+// MetricsStream returns an interceptor that records the count and duration of streaming RPCs.
+// The stream type is taken from info, so this should come after CorrectStreamInfo in the chain.
+func MetricsStream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		method, typ := methodLabel(info.FullMethod), streamType(info)
+		active := grpcStreamsActive.WithLabelValues(method, typ)
+		active.Inc()
+		defer active.Dec()
+		start := time.Now()
+		err := handler(srv, ss)
+		observeRPC(method, typ, start, err)
+		return err
+	}
+}
+
+func observeRPC(method, typ string, start time.Time, err error) {
+	grpcHandlingSeconds.WithLabelValues(method, typ).Observe(time.Since(start).Seconds())
+	grpcHandled.WithLabelValues(method, typ, status.Code(err).String()).Inc()
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	case info.IsServerStream:
+		return "server_stream"
+	default:
+		return "unary"
+	}
+}
+
+// methodLabel returns fullMethod if it names a method known to the proto registry, or "unknown" otherwise.
+// Method names come from the client, this stops requests for made up methods creating new series.
+func methodLabel(fullMethod string) string {
+	serviceName, methodName, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown"
+	}
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return "unknown"
+	}
+	serviceDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok || serviceDesc.Methods().ByName(protoreflect.Name(methodName)) == nil {
+		return "unknown"
+	}
+	return fullMethod
+}
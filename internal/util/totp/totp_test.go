@@ -0,0 +1,69 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Test vectors from RFC 6238 Appendix B, truncated to 6 digits.
+func TestCode(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		time int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got := Code(secret, Step(time.Unix(tt.time, 0)))
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.time, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111109, 0)
+	step := Step(now)
+	tests := []struct {
+		name   string
+		code   string
+		skew   int
+		wantOK bool
+	}{
+		{"current", Code(secret, step), 0, true},
+		{"previous no skew", Code(secret, step-1), 0, false},
+		{"previous", Code(secret, step-1), 1, true},
+		{"next", Code(secret, step+1), 1, true},
+		{"too old", Code(secret, step-2), 1, false},
+		{"short", "12345", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := Validate(secret, tt.code, now, tt.skew)
+			if ok != tt.wantOK {
+				t.Errorf("Validate() = %v, want %v", ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestURI(t *testing.T) {
+	u, err := url.Parse(URI("Smart Core", "alice", []byte("12345678901234567890")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		t.Errorf("unexpected URI %s", u)
+	}
+	if got := u.Query().Get("secret"); got != "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" {
+		t.Errorf("secret = %s", got)
+	}
+}
@@ -20,8 +20,9 @@ import (
 
 // replaceable for testing
 var (
-	readFile = os.ReadFile
-	glob     = filepath.Glob
+	readFile  = os.ReadFile
+	glob      = filepath.Glob
+	lookupEnv = os.LookupEnv
 )
 
 type Config struct {
@@ -39,6 +40,17 @@ type Config struct {
 	Automation []auto.RawConfig   `json:"automation,omitempty"`
 	Zones      []zone.RawConfig   `json:"zones,omitempty"`
 
+	// Templates and Instances add more Drivers, Automation, and Zones to the config, see ExpandTemplates.
+	// Like Drivers, templates are merged using the Name, all Instances are kept.
+	Templates []Template `json:"templates,omitempty"`
+	Instances []Instance `json:"instances,omitempty"`
+	// Vars are values available to Templates and Instances as ${name}.
+	// Vars are merged by name in a first-come, first-served nature.
+	Vars map[string]any `json:"vars,omitempty"`
+	// VarsFiles are JSON files containing more Vars, Vars in this config take precedence.
+	// Paths are resolved relative to the directory the config file is in.
+	VarsFiles []string `json:"varsFiles,omitempty"`
+
 	// the path to the file this config was loaded from
 	FilePath string `json:"-"`
 }
@@ -75,6 +87,26 @@ func (c *Config) mergeWith(other *Config) {
 			c.Zones = append(c.Zones, z)
 		}
 	}
+	templateNames := make(map[string]bool, len(c.Templates))
+	for _, t := range c.Templates {
+		templateNames[t.Name] = true
+	}
+	for _, t := range other.Templates {
+		if !templateNames[t.Name] {
+			c.Templates = append(c.Templates, t)
+		}
+	}
+	c.Instances = append(c.Instances, other.Instances...)
+	for k, v := range other.Vars {
+		if c.Vars == nil {
+			c.Vars = make(map[string]any)
+		}
+		if _, found := c.Vars[k]; !found {
+			c.Vars[k] = v
+		}
+	}
+	c.VarsFiles = append(c.VarsFiles, other.VarsFiles...)
+
 	// Includes are merged in a special way, we use the FilePath relative to c as the include.
 	relInc, err := filepath.Rel(filepath.Dir(c.FilePath), other.FilePath)
 	if err != nil {
@@ -121,8 +153,9 @@ func (c *Config) clone() Config {
 	}
 }
 
-// LoadLocalConfig will load Config from a local file, as well as any included files
-func LoadLocalConfig(dir, file string) (*Config, error) {
+// LoadLocalConfig will load Config from a local file, as well as any included files.
+// Templates in the loaded config are expanded, see ExpandTemplates, with sysVars available as ${sys.name}.
+func LoadLocalConfig(dir, file string, sysVars map[string]string) (*Config, error) {
 	path := files.Path(dir, file)
 	conf, err := configFromFile(path)
 	if err != nil {
@@ -132,6 +165,7 @@ func LoadLocalConfig(dir, file string) (*Config, error) {
 	includes := conf.Includes
 	conf.Includes = nil // includes are added back into the config during merge. This gets rid of globs and files we couldn't find
 	_, err = loadIncludes(dir, conf, includes, nil)
+	err = multierr.Append(err, ExpandTemplates(conf, sysVars))
 	return conf, err // return the config we have, and any errors
 }
 
@@ -189,5 +223,8 @@ func configFromFile(path string) (*Config, error) {
 		return nil, fmt.Errorf("config JSON unmarshal %s: %w", path, err)
 	}
 	conf.FilePath = path
+	for i, f := range conf.VarsFiles {
+		conf.VarsFiles[i] = files.Path(filepath.Dir(path), f)
+	}
 	return &conf, nil
 }
@@ -316,7 +316,7 @@ func TestLoadLocalConfig(t *testing.T) {
 			glob = func(pattern string) (matches []string, err error) {
 				return fs.Glob(tt.fs, pattern)
 			}
-			conf, err := LoadLocalConfig(tt.dir, tt.file)
+			conf, err := LoadLocalConfig(tt.dir, tt.file, nil)
 			if tt.config != nil && conf == nil && err != nil {
 				t.Errorf("expected config, got error: %s", err)
 			}
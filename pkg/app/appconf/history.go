@@ -0,0 +1,255 @@
+package appconf
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/renameio/v2/maybe"
+
+	"github.com/smart-core-os/sc-bos/pkg/block"
+)
+
+const historyDirName = "history"
+
+// ErrRevisionNotFound is returned when a revision does not exist, or has been pruned.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Revision is a version of the active config.
+// A new revision is recorded each time the active config changes.
+type Revision struct {
+	ID          int64     `json:"id"`
+	CreateTime  time.Time `json:"createTime"`
+	Author      Author    `json:"author,omitzero"`
+	Description string    `json:"description,omitempty"`
+	// The active config as of this revision, encoded as JSON.
+	// Not populated by Store.Revisions.
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// Author identifies who made a change to the config.
+// The zero value means the change wasn't made by an authenticated request.
+type Author struct {
+	Subject     string `json:"subject,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// history stores revisions as files in a directory, one file per revision.
+// An index of the revisions, without their config, is kept in memory.
+type history struct {
+	dir      string
+	maxAge   time.Duration
+	maxCount int
+
+	index []Revision // oldest first, Config is nil
+}
+
+func loadHistory(dir string, maxAge time.Duration, maxCount int) (*history, error) {
+	h := &history{dir: dir, maxAge: maxAge, maxCount: maxCount}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		id, err := strconv.ParseInt(strings.TrimSuffix(e.Name(), ".json"), 10, 64)
+		if err != nil {
+			continue // not one of ours
+		}
+		r, err := h.read(id)
+		if err != nil {
+			return nil, err
+		}
+		r.Config = nil
+		h.index = append(h.index, r)
+	}
+	slices.SortFunc(h.index, func(a, b Revision) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return h, nil
+}
+
+// latest returns the newest revision, if there is one.
+func (h *history) latest() (Revision, bool) {
+	if len(h.index) == 0 {
+		return Revision{}, false
+	}
+	return h.index[len(h.index)-1], true
+}
+
+// add records r as a new revision, assigning its ID, then prunes old revisions.
+func (h *history) add(r Revision) (Revision, error) {
+	if latest, ok := h.latest(); ok {
+		r.ID = latest.ID + 1
+	} else {
+		r.ID = 1
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return Revision{}, err
+	}
+	if err := os.MkdirAll(h.dir, 0755); err != nil {
+		return Revision{}, err
+	}
+	if err := maybe.WriteFile(h.filename(r.ID), data, 0644); err != nil {
+		return Revision{}, err
+	}
+	indexed := r
+	indexed.Config = nil
+	h.index = append(h.index, indexed)
+	return r, h.prune(r.CreateTime)
+}
+
+// prune removes revisions beyond the configured count or age.
+// The latest revision is always kept.
+func (h *history) prune(now time.Time) error {
+	n := 0
+	for n < len(h.index)-1 {
+		r := h.index[n]
+		tooMany := h.maxCount > 0 && len(h.index)-n > h.maxCount
+		tooOld := h.maxAge > 0 && now.Sub(r.CreateTime) > h.maxAge
+		if !tooMany && !tooOld {
+			break
+		}
+		n++
+	}
+	var errs []error
+	for _, r := range h.index[:n] {
+		if err := os.Remove(h.filename(r.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	h.index = slices.Delete(h.index, 0, n)
+	return errors.Join(errs...)
+}
+
+// get returns the revision with the given id, including its config.
+// An id of 0 returns the latest revision.
+func (h *history) get(id int64) (Revision, error) {
+	if id == 0 {
+		latest, ok := h.latest()
+		if !ok {
+			return Revision{}, ErrRevisionNotFound
+		}
+		id = latest.ID
+	}
+	if _, found := slices.BinarySearchFunc(h.index, id, func(r Revision, id int64) int {
+		return cmp.Compare(r.ID, id)
+	}); !found {
+		return Revision{}, ErrRevisionNotFound
+	}
+	return h.read(id)
+}
+
+func (h *history) read(id int64) (Revision, error) {
+	data, err := os.ReadFile(h.filename(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return Revision{}, ErrRevisionNotFound
+	}
+	if err != nil {
+		return Revision{}, err
+	}
+	var r Revision
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Revision{}, fmt.Errorf("revision %d: %w", id, err)
+	}
+	return r, nil
+}
+
+func (h *history) filename(id int64) string {
+	return filepath.Join(h.dir, fmt.Sprintf("%010d.json", id))
+}
+
+// Revisions returns the recorded revisions of the active config, oldest first.
+// The Config of each revision is not populated, use Revision for that.
+func (s *Store) Revisions() []Revision {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return slices.Clone(s.history.index)
+}
+
+// Revision returns the revision with the given id, including its config.
+// An id of 0 returns the latest revision.
+func (s *Store) Revision(id int64) (Revision, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.history.get(id)
+}
+
+// DiffRevisions returns the patches that transform the config of revision from into the config of revision to.
+// An id of 0 refers to the latest revision.
+// Patches are split using the same blocks as when merging external config, and are sorted by path.
+func (s *Store) DiffRevisions(from, to int64) ([]block.Patch, error) {
+	s.m.Lock()
+	fromRev, err := s.history.get(from)
+	if err != nil {
+		s.m.Unlock()
+		return nil, err
+	}
+	toRev, err := s.history.get(to)
+	s.m.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var a, b any
+	if err := json.Unmarshal(fromRev.Config, &a); err != nil {
+		return nil, fmt.Errorf("revision %d: %w", fromRev.ID, err)
+	}
+	if err := json.Unmarshal(toRev.Config, &b); err != nil {
+		return nil, fmt.Errorf("revision %d: %w", toRev.ID, err)
+	}
+	patches, err := block.Diff(a, b, s.schema.Blocks())
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(patches, func(a, b block.Patch) int {
+		return block.ComparePaths(a.Path, b.Path)
+	})
+	return patches, nil
+}
+
+// Rollback makes the config of revision id the active config, recording a new revision for the change.
+// Returns the config that was active before the rollback, and the config that is now active.
+// The caller is responsible for applying any differences between the two to running services.
+func (s *Store) Rollback(ctx context.Context, id int64) (old, updated Config, _ Revision, _ error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	r, err := s.history.get(id)
+	if err != nil {
+		return Config{}, Config{}, Revision{}, err
+	}
+	if err := json.Unmarshal(r.Config, &updated); err != nil {
+		return Config{}, Config{}, Revision{}, fmt.Errorf("revision %d: %w", r.ID, err)
+	}
+	old = s.active.clone()
+	updated.FilePath = old.FilePath
+	newRev, err := s.save(ctx, updated, fmt.Sprintf("rollback to revision %d", r.ID))
+	if err != nil {
+		return Config{}, Config{}, Revision{}, err
+	}
+	return old, updated.clone(), newRev, nil
+}
+
+// jsonEqual returns whether a and b are the same JSON, ignoring insignificant whitespace.
+func jsonEqual(a, b []byte) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
@@ -0,0 +1,174 @@
+package appconf
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+type authorKey struct{}
+
+func TestStore_history(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	opts := []StoreOption{
+		WithNow(func() time.Time {
+			now = now.Add(time.Minute)
+			return now
+		}),
+		WithAuthor(func(ctx context.Context) Author {
+			a, _ := ctx.Value(authorKey{}).(Author)
+			return a
+		}),
+	}
+	external := Config{
+		Drivers: []driver.RawConfig{
+			{BaseConfig: driver.BaseConfig{Name: "d1", Type: "t"}, Raw: []byte(`{"name":"d1","type":"t","v":1}`)},
+		},
+	}
+	store, err := LoadStore(external, Schema{}, dir, zap.NewNop(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice := Author{Subject: "alice", DisplayName: "Alice"}
+	ctx := context.WithValue(context.Background(), authorKey{}, alice)
+	err = store.Drivers().SaveConfig(ctx, "d1", "", []byte(`{"name":"d1","type":"t","v":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Drivers().SaveConfig(ctx, "d2", "t", []byte(`{"name":"d2","type":"t"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type info struct {
+		ID          int64
+		Author      Author
+		Description string
+	}
+	revisionInfo := func(revisions []Revision) []info {
+		var infos []info
+		for _, r := range revisions {
+			infos = append(infos, info{r.ID, r.Author, r.Description})
+		}
+		return infos
+	}
+	want := []info{
+		{1, Author{}, "initial config"},
+		{2, alice, "configure driver d1"},
+		{3, alice, "configure driver d2"},
+	}
+	if diff := cmp.Diff(want, revisionInfo(store.Revisions())); diff != "" {
+		t.Fatalf("revisions (-want,+got)\n%s", diff)
+	}
+
+	server := NewHistoryServer(store, nil)
+	diffRes, err := server.DiffConfigRevisions(context.Background(), &gen.DiffConfigRevisionsRequest{FromId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDiff := &gen.DiffConfigRevisionsResponse{Patches: []*gen.ConfigPatch{
+		{Path: `/drivers[name="d1"]`, ValueRaw: `{"name":"d1","type":"t","v":2}`},
+		{Path: `/drivers[name="d2"]`, ValueRaw: `{"name":"d2","type":"t"}`},
+	}}
+	if diff := cmp.Diff(wantDiff, diffRes, protocmp.Transform()); diff != "" {
+		t.Fatalf("diff (-want,+got)\n%s", diff)
+	}
+
+	old, updated, r, err := store.Rollback(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != 4 || r.Description != "rollback to revision 1" {
+		t.Fatalf("rollback revision = %d %q, want 4 %q", r.ID, r.Description, "rollback to revision 1")
+	}
+	if len(old.Drivers) != 2 || len(updated.Drivers) != 1 {
+		t.Fatalf("rollback old/updated drivers = %d/%d, want 2/1", len(old.Drivers), len(updated.Drivers))
+	}
+	if got := store.Active().Drivers[0].Raw; !jsonEqual(got, []byte(`{"name":"d1","type":"t","v":1}`)) {
+		t.Fatalf("active d1 config after rollback = %s", got)
+	}
+
+	// reloading doesn't record a new revision when nothing has changed
+	store, err = LoadStore(external, Schema{}, dir, zap.NewNop(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(store.Revisions()); got != 4 {
+		t.Fatalf("revisions after reload = %d, want 4", got)
+	}
+}
+
+func TestStore_historyRetention(t *testing.T) {
+	dir := t.TempDir()
+	store, err := LoadStore(Config{}, Schema{}, dir, zap.NewNop(), WithHistoryRetention(0, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 5 {
+		if err := store.Zones().SaveConfig(context.Background(), "z", "t", []byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var ids []int64
+	for _, r := range store.Revisions() {
+		ids = append(ids, r.ID)
+	}
+	if diff := cmp.Diff([]int64{4, 5, 6}, ids); diff != "" {
+		t.Fatalf("revision ids (-want,+got)\n%s", diff)
+	}
+	if _, err := store.Revision(1); !errors.Is(err, ErrRevisionNotFound) {
+		t.Fatalf("Revision(1) error = %v, want %v", err, ErrRevisionNotFound)
+	}
+
+	// pruned files are gone, so reloading finds the same revisions
+	store, err = LoadStore(Config{}, Schema{}, dir, zap.NewNop(), WithHistoryRetention(0, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(store.Revisions()); got != 3 {
+		t.Fatalf("revisions after reload = %d, want 3", got)
+	}
+}
+
+func TestHistoryServer_RollbackConfig_unrecorded(t *testing.T) {
+	dir := t.TempDir()
+	external := Config{
+		Drivers: []driver.RawConfig{
+			{BaseConfig: driver.BaseConfig{Name: "d1", Type: "t"}, Raw: []byte(`{"name":"d1","type":"t","v":1}`)},
+		},
+	}
+	store, err := LoadStore(external, Schema{}, dir, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Drivers().SaveConfig(context.Background(), "d1", "", []byte(`{"name":"d1","type":"t","v":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a directory where the next revision should be written stops it being recorded
+	if err := os.Mkdir(store.history.filename(3), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewHistoryServer(store, nil)
+	_, err = server.RollbackConfig(context.Background(), &gen.RollbackConfigRequest{Id: 1})
+	if code := status.Code(err); code != codes.Internal {
+		t.Fatalf("RollbackConfig error = %v, want code %v", err, codes.Internal)
+	}
+	if got := store.Active().Drivers[0].Raw; !jsonEqual(got, []byte(`{"name":"d1","type":"t","v":1}`)) {
+		t.Fatalf("active d1 config after rollback = %s", got)
+	}
+}
@@ -0,0 +1,147 @@
+package appconf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/block"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/page"
+	"github.com/smart-core-os/sc-golang/pkg/masks"
+)
+
+// ApplyFunc updates running services to match a change in config from old to updated.
+type ApplyFunc func(ctx context.Context, old, updated Config) error
+
+// HistoryServer is a [gen.ConfigHistoryApiServer] backed by the revisions recorded in a Store.
+type HistoryServer struct {
+	gen.UnimplementedConfigHistoryApiServer
+	store *Store
+	apply ApplyFunc
+}
+
+// NewHistoryServer returns a HistoryServer for store.
+// apply is called after a rollback to update running services, it may be nil.
+func NewHistoryServer(store *Store, apply ApplyFunc) *HistoryServer {
+	return &HistoryServer{store: store, apply: apply}
+}
+
+func (s *HistoryServer) ListConfigRevisions(_ context.Context, req *gen.ListConfigRevisionsRequest) (*gen.ListConfigRevisionsResponse, error) {
+	revisions, totalSize, nextPageToken, err := page.List(req, revisionPageID, s.store.Revisions)
+	if err != nil {
+		return nil, err
+	}
+	filter := masks.NewResponseFilter(masks.WithFieldMask(req.GetReadMask()))
+	res := &gen.ListConfigRevisionsResponse{
+		TotalSize:     int32(totalSize),
+		NextPageToken: nextPageToken,
+	}
+	for _, r := range revisions {
+		res.ConfigRevisions = append(res.ConfigRevisions, filter.FilterClone(revisionToProto(r)).(*gen.ConfigRevision))
+	}
+	return res, nil
+}
+
+// revisionPageID returns an id for r that sorts newer revisions first.
+func revisionPageID(r Revision) string {
+	return fmt.Sprintf("%019d", math.MaxInt64-r.ID)
+}
+
+func (s *HistoryServer) GetConfigRevision(_ context.Context, req *gen.GetConfigRevisionRequest) (*gen.ConfigRevision, error) {
+	r, err := s.store.Revision(req.GetId())
+	if err != nil {
+		return nil, revisionErr(err)
+	}
+	filter := masks.NewResponseFilter(masks.WithFieldMask(req.GetReadMask()))
+	return filter.FilterClone(revisionToProto(r)).(*gen.ConfigRevision), nil
+}
+
+func (s *HistoryServer) DiffConfigRevisions(_ context.Context, req *gen.DiffConfigRevisionsRequest) (*gen.DiffConfigRevisionsResponse, error) {
+	if req.GetFromId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "from_id is required")
+	}
+	patches, err := s.store.DiffRevisions(req.GetFromId(), req.GetToId())
+	if err != nil {
+		return nil, revisionErr(err)
+	}
+	res := &gen.DiffConfigRevisionsResponse{}
+	for _, p := range patches {
+		dst := &gen.ConfigPatch{Path: p.Path.String(), Deleted: p.Deleted}
+		if !p.Deleted {
+			raw, err := json.Marshal(withoutIgnored(p.Value))
+			if err != nil {
+				return nil, err
+			}
+			dst.ValueRaw = string(raw)
+		}
+		res.Patches = append(res.Patches, dst)
+	}
+	return res, nil
+}
+
+func (s *HistoryServer) RollbackConfig(ctx context.Context, req *gen.RollbackConfigRequest) (*gen.ConfigRevision, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	old, updated, r, err := s.store.Rollback(ctx, req.GetId())
+	if err != nil {
+		return nil, revisionErr(err)
+	}
+	if s.apply != nil {
+		if err := s.apply(ctx, old, updated); err != nil {
+			return nil, status.Errorf(codes.Internal, "config rolled back to revision %d, but applying it to services failed: %v", req.GetId(), err)
+		}
+	}
+	if r.ID == 0 {
+		return nil, status.Errorf(codes.Internal, "config rolled back to revision %d, but recording the new revision failed", req.GetId())
+	}
+	r.Config = nil // not needed by the caller, they know what they asked for
+	return revisionToProto(r), nil
+}
+
+// withoutIgnored returns v without any block.Ignore values.
+// Ignore marks sections that are patched separately, and is only meaningful when applying a patch.
+func withoutIgnored(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	dst := make(map[string]any, len(m))
+	for k, v := range m {
+		if _, ok := v.(block.Ignore); ok {
+			continue
+		}
+		dst[k] = withoutIgnored(v)
+	}
+	return dst
+}
+
+func revisionErr(err error) error {
+	if errors.Is(err, ErrRevisionNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return err
+}
+
+func revisionToProto(r Revision) *gen.ConfigRevision {
+	dst := &gen.ConfigRevision{
+		Id:          r.ID,
+		CreateTime:  timestamppb.New(r.CreateTime),
+		Description: r.Description,
+		ConfigRaw:   string(r.Config),
+	}
+	if r.Author != (Author{}) {
+		dst.Author = &gen.ConfigRevision_Author{
+			Subject:     r.Author.Subject,
+			DisplayName: r.Author.DisplayName,
+		}
+	}
+	return dst
+}
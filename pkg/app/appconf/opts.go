@@ -0,0 +1,59 @@
+package appconf
+
+import (
+	"context"
+	"time"
+)
+
+type storeOpts struct {
+	author         func(ctx context.Context) Author
+	now            func() time.Time
+	maxRevisions   int
+	maxRevisionAge time.Duration
+}
+
+func resolveStoreOpts(options ...StoreOption) storeOpts {
+	o := storeOpts{
+		maxRevisions: DefaultMaxRevisions,
+	}
+	for _, option := range options {
+		option(&o)
+	}
+	if o.author == nil {
+		o.author = func(context.Context) Author { return Author{} }
+	}
+	if o.now == nil {
+		o.now = time.Now
+	}
+	return o
+}
+
+// DefaultMaxRevisions is the number of config revisions kept if WithHistoryRetention is not used.
+const DefaultMaxRevisions = 100
+
+type StoreOption func(*storeOpts)
+
+// WithAuthor is an option to set how the author of a config change is found from the context passed to SaveConfig.
+func WithAuthor(author func(ctx context.Context) Author) StoreOption {
+	return func(o *storeOpts) {
+		o.author = author
+	}
+}
+
+// WithHistoryRetention is an option to set how many config revisions are kept.
+// Revisions older than maxAge, or beyond the newest maxCount revisions, are removed when a new revision is recorded.
+// The latest revision is always kept.
+// A zero value for either argument means no limit.
+func WithHistoryRetention(maxAge time.Duration, maxCount int) StoreOption {
+	return func(o *storeOpts) {
+		o.maxRevisionAge = maxAge
+		o.maxRevisions = maxCount
+	}
+}
+
+// WithNow is an option to set the clock used to timestamp revisions.
+func WithNow(now func() time.Time) StoreOption {
+	return func(o *storeOpts) {
+		o.now = now
+	}
+}
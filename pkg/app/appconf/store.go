@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
@@ -24,24 +25,66 @@ import (
 type Store struct {
 	logger  *zap.Logger
 	backing confmerge.Store
+	schema  Schema
+	author  func(ctx context.Context) Author
+	now     func() time.Time
 
-	m      sync.Mutex
-	active Config
+	m       sync.Mutex
+	active  Config
+	history *history
 }
 
-func LoadStore(external Config, schema Schema, storeDir string, logger *zap.Logger) (*Store, error) {
+func LoadStore(external Config, schema Schema, storeDir string, logger *zap.Logger, opts ...StoreOption) (*Store, error) {
+	o := resolveStoreOpts(opts...)
 	store := confmerge.NewDirStore(storeDir)
 	active, patches, err := confmerge.Merge(external, store, schema.Blocks())
 	err = multierr.Append(err, saveConfigPatches(patches, filepath.Join(storeDir, patchDirName), logger))
 	if err != nil {
 		return nil, err
 	}
+	hist, err := loadHistory(filepath.Join(storeDir, historyDirName), o.maxRevisionAge, o.maxRevisions)
+	if err != nil {
+		return nil, fmt.Errorf("config history: %w", err)
+	}
 
-	return &Store{
+	s := &Store{
 		logger:  logger,
 		backing: store,
+		schema:  schema,
+		author:  o.author,
+		now:     o.now,
 		active:  active,
-	}, nil
+		history: hist,
+	}
+	// record a revision for changes made while we weren't running, or the first time history is enabled
+	if err := s.recordLoaded(len(patches) > 0); err != nil {
+		return nil, fmt.Errorf("config history: %w", err)
+	}
+	return s, nil
+}
+
+// recordLoaded records the active config as a new revision if it differs from the latest revision.
+func (s *Store) recordLoaded(merged bool) error {
+	encoded, err := json.MarshalIndent(s.active, "", "  ")
+	if err != nil {
+		return err
+	}
+	description := "initial config"
+	if latest, ok := s.history.latest(); ok {
+		latest, err = s.history.get(latest.ID)
+		if err != nil {
+			return err
+		}
+		if jsonEqual(latest.Config, encoded) {
+			return nil
+		}
+		description = "config changed while stopped"
+		if merged {
+			description = "merge external config"
+		}
+	}
+	_, err = s.history.add(Revision{CreateTime: s.now(), Description: description, Config: encoded})
+	return err
 }
 
 func (s *Store) Active() Config {
@@ -62,17 +105,32 @@ func (s *Store) Zones() *ZoneStore {
 	return &ZoneStore{store: s}
 }
 
-func (s *Store) save(updated Config) error {
+// save replaces the active config with updated, recording a new revision described by description.
+// Failing to record the revision is logged rather than returned, as the config has still changed,
+// in which case the zero Revision is returned.
+// s.m must be held.
+func (s *Store) save(ctx context.Context, updated Config, description string) (Revision, error) {
 	encoded, err := json.MarshalIndent(updated, "", "  ")
 	if err != nil {
-		return err
+		return Revision{}, err
 	}
 	err = s.backing.SetActiveConfig(encoded)
 	if err != nil {
-		return err
+		return Revision{}, err
 	}
 	s.active = updated
-	return nil
+
+	r, err := s.history.add(Revision{
+		CreateTime:  s.now(),
+		Author:      s.author(ctx),
+		Description: description,
+		Config:      encoded,
+	})
+	if err != nil {
+		// the change has been made, failing to record it shouldn't undo that
+		s.logger.Warn("failed to record config revision", zap.String("description", description), zap.Error(err))
+	}
+	return r, nil
 }
 
 type serviceConfigOps[T any] struct {
@@ -125,7 +183,7 @@ type DriverStore struct {
 	store *Store
 }
 
-func (ds *DriverStore) SaveConfig(_ context.Context, name string, typ string, data []byte) error {
+func (ds *DriverStore) SaveConfig(ctx context.Context, name string, typ string, data []byte) error {
 	ds.store.m.Lock()
 	defer ds.store.m.Unlock()
 
@@ -137,6 +195,7 @@ func (ds *DriverStore) SaveConfig(_ context.Context, name string, typ string, da
 			return d.Name, d.Type
 		},
 		update: func(cfg driver.RawConfig, typ string, data []byte) driver.RawConfig {
+			cfg.Name = name // unset for new services
 			cfg.Type = typ
 			cfg.Raw = data
 			return cfg
@@ -146,14 +205,15 @@ func (ds *DriverStore) SaveConfig(_ context.Context, name string, typ string, da
 		return err
 	}
 
-	return ds.store.save(updated)
+	_, err = ds.store.save(ctx, updated, "configure driver "+name)
+	return err
 }
 
 type AutomationStore struct {
 	store *Store
 }
 
-func (as *AutomationStore) SaveConfig(_ context.Context, name string, typ string, data []byte) error {
+func (as *AutomationStore) SaveConfig(ctx context.Context, name string, typ string, data []byte) error {
 	as.store.m.Lock()
 	defer as.store.m.Unlock()
 
@@ -165,6 +225,7 @@ func (as *AutomationStore) SaveConfig(_ context.Context, name string, typ string
 			return a.Name, a.Type
 		},
 		update: func(cfg auto.RawConfig, typ string, data []byte) auto.RawConfig {
+			cfg.Name = name // unset for new services
 			cfg.Type = typ
 			cfg.Raw = data
 			return cfg
@@ -174,14 +235,15 @@ func (as *AutomationStore) SaveConfig(_ context.Context, name string, typ string
 		return err
 	}
 
-	return as.store.save(updated)
+	_, err = as.store.save(ctx, updated, "configure automation "+name)
+	return err
 }
 
 type ZoneStore struct {
 	store *Store
 }
 
-func (zs *ZoneStore) SaveConfig(_ context.Context, name string, typ string, data []byte) error {
+func (zs *ZoneStore) SaveConfig(ctx context.Context, name string, typ string, data []byte) error {
 	zs.store.m.Lock()
 	defer zs.store.m.Unlock()
 
@@ -193,6 +255,7 @@ func (zs *ZoneStore) SaveConfig(_ context.Context, name string, typ string, data
 			return z.Name, z.Type
 		},
 		update: func(cfg zone.RawConfig, typ string, data []byte) zone.RawConfig {
+			cfg.Name = name // unset for new services
 			cfg.Type = typ
 			cfg.Raw = data
 			return cfg
@@ -202,7 +265,8 @@ func (zs *ZoneStore) SaveConfig(_ context.Context, name string, typ string, data
 		return err
 	}
 
-	return zs.store.save(updated)
+	_, err = zs.store.save(ctx, updated, "configure zone "+name)
+	return err
 }
 
 const patchDirName = "patches"
@@ -119,6 +119,38 @@ func TestZoneStore_SaveConfig(t *testing.T) {
 	}
 }
 
+func TestStore_SaveConfig_new(t *testing.T) {
+	store := setupStoreServiceTest(t)
+	// saving twice should update the service added by the first save, not add another
+	for _, property := range []string{"new", "updated"} {
+		raw := []byte(`{"property": "` + property + `"}`)
+		if err := store.Drivers().SaveConfig(context.TODO(), "newdriver", "bar", raw); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Automations().SaveConfig(context.TODO(), "newauto", "bar", raw); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Zones().SaveConfig(context.TODO(), "newzone", "bar", raw); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	raw := []byte(`{"property": "updated"}`)
+	active := store.Active()
+	wantDriver := driver.RawConfig{BaseConfig: driver.BaseConfig{Name: "newdriver", Type: "bar"}, Raw: raw}
+	if diff := cmp.Diff([]driver.RawConfig{wantDriver}, active.Drivers[1:]); diff != "" {
+		t.Errorf("new drivers (-want +got)\n%s", diff)
+	}
+	wantAuto := auto.RawConfig{Config: auto.Config{Name: "newauto", Type: "bar"}, Raw: raw}
+	if diff := cmp.Diff([]auto.RawConfig{wantAuto}, active.Automation[1:]); diff != "" {
+		t.Errorf("new automations (-want +got)\n%s", diff)
+	}
+	wantZone := zone.RawConfig{Config: zone.Config{Name: "newzone", Type: "bar"}, Raw: raw}
+	if diff := cmp.Diff([]zone.RawConfig{wantZone}, active.Zones[1:]); diff != "" {
+		t.Errorf("new zones (-want +got)\n%s", diff)
+	}
+}
+
 func setupStoreServiceTest(t *testing.T) *Store {
 	t.Helper()
 	external := Config{
@@ -0,0 +1,507 @@
+package appconf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/multierr"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto"
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/zone"
+)
+
+// Template is reusable config for drivers, automations, and zones.
+// Strings in the config can refer to parameters and variables using ${name}, which are substituted when an Instance
+// of the template is expanded.
+// A string that is only a reference, like "${devices}", is replaced by the value of the variable, which need not be
+// a string.
+// Use $${ for a literal ${.
+//
+// As well as parameters and Config.Vars, ${sys.name} refers to the system config vars, and ${env.NAME} refers to
+// environment variables.
+type Template struct {
+	Name string `json:"name"`
+	// Params lists the parameters an Instance must provide, either in Args or ForEach.
+	// If empty, parameters are not checked.
+	Params     []string          `json:"params,omitempty"`
+	Drivers    []json.RawMessage `json:"drivers,omitempty"`
+	Automation []json.RawMessage `json:"automation,omitempty"`
+	Zones      []json.RawMessage `json:"zones,omitempty"`
+}
+
+// Instance adds the drivers, automations, and zones of a Template to the config.
+type Instance struct {
+	// Template is the name of the template to expand.
+	Template string `json:"template"`
+	// Args are the parameters for the template.
+	// Args can refer to variables, and to ForEach parameters.
+	Args map[string]any `json:"args,omitempty"`
+	// ForEach expands the template once for each value of the loop, with the loop value as a parameter.
+	// For example {"floor": {"from": 1, "to": 12}} expands the template 12 times with ${floor} set to 1 to 12.
+	// With more than one loop, the template is expanded for each combination of values, ordered by parameter name.
+	ForEach map[string]Loop `json:"forEach,omitempty"`
+}
+
+// Loop is the set of values for a parameter of an Instance.
+// In JSON a loop is either an array of values, an object with inclusive integer "from" and "to" bounds,
+// or a reference to a variable that is an array, like "${floors}".
+type Loop struct {
+	Values   []any
+	From, To int
+	Var      string
+}
+
+func (l Loop) MarshalJSON() ([]byte, error) {
+	switch {
+	case l.Var != "":
+		return json.Marshal("${" + l.Var + "}")
+	case l.Values != nil:
+		return json.Marshal(l.Values)
+	default:
+		return json.Marshal(map[string]int{"from": l.From, "to": l.To})
+	}
+}
+
+func (l *Loop) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("empty loop")
+	}
+	*l = Loop{}
+	switch data[0] {
+	case '[':
+		return json.Unmarshal(data, &l.Values)
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		name, ok := wholeRef(s)
+		if !ok {
+			return fmt.Errorf("loop %q must be a reference to a variable, like ${name}", s)
+		}
+		l.Var = name
+		return nil
+	default:
+		var r struct {
+			From *int `json:"from"`
+			To   *int `json:"to"`
+		}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		if r.From == nil || r.To == nil {
+			return errors.New("loop range needs both from and to")
+		}
+		l.From, l.To = *r.From, *r.To
+		return nil
+	}
+}
+
+// values returns the values of the loop, resolving Var using s.
+func (l Loop) values(s scope) ([]any, error) {
+	switch {
+	case l.Var != "":
+		v, err := s.lookup(l.Var)
+		if err != nil {
+			return nil, err
+		}
+		vs, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("${%s} is not an array", l.Var)
+		}
+		return vs, nil
+	case l.Values != nil:
+		return l.Values, nil
+	default:
+		if l.To < l.From {
+			return nil, fmt.Errorf("loop range from %d is after to %d", l.From, l.To)
+		}
+		vs := make([]any, 0, l.To-l.From+1)
+		for i := l.From; i <= l.To; i++ {
+			vs = append(vs, float64(i)) // same as a JSON number
+		}
+		return vs, nil
+	}
+}
+
+// ExpandTemplates adds the drivers, automations, and zones created by c.Instances to c.
+// Instances are expanded in order, so the result is the same each time for the same config.
+// Once expanded, c.Templates, c.Instances, c.Vars, and c.VarsFiles are cleared.
+// sysVars are available to templates as ${sys.name}.
+//
+// All errors are returned, the config is left unchanged if there are any.
+func ExpandTemplates(c *Config, sysVars map[string]string) error {
+	if len(c.Instances) == 0 {
+		c.clearTemplates()
+		return nil
+	}
+
+	vars, err := c.loadVars()
+	if err != nil {
+		return err
+	}
+	global := scope{vars: vars, sys: sysVars}
+
+	templates := make(map[string]Template, len(c.Templates))
+	var errs error
+	for _, t := range c.Templates {
+		if _, ok := templates[t.Name]; ok {
+			errs = multierr.Append(errs, fmt.Errorf("template %q defined more than once", t.Name))
+			continue
+		}
+		templates[t.Name] = t
+	}
+
+	expanded := c.clone()
+	names := map[string]map[string]bool{
+		"driver":     expanded.driverNamesMap(),
+		"automation": expanded.autoNamesMap(),
+		"zone":       expanded.zoneNamesMap(),
+	}
+	for i, inst := range c.Instances {
+		t, ok := templates[inst.Template]
+		if !ok {
+			errs = multierr.Append(errs, fmt.Errorf("instances[%d]: unknown template %q", i, inst.Template))
+			continue
+		}
+		err := inst.expand(global, t, func(kind string, raw []byte) error {
+			return addExpanded(&expanded, names, kind, raw)
+		})
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("instances[%d] (template %q): %w", i, inst.Template, err))
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+
+	*c = expanded
+	c.clearTemplates()
+	return nil
+}
+
+func (c *Config) clearTemplates() {
+	c.Templates = nil
+	c.Instances = nil
+	c.Vars = nil
+	c.VarsFiles = nil
+}
+
+// loadVars returns c.Vars combined with the vars in c.VarsFiles.
+// Vars in c take precedence, followed by the files in order.
+func (c *Config) loadVars() (map[string]any, error) {
+	vars := make(map[string]any, len(c.Vars))
+	for k, v := range c.Vars {
+		vars[k] = v
+	}
+	for _, path := range c.VarsFiles {
+		data, err := readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("vars file: %w", err)
+		}
+		var fileVars map[string]any
+		if err := json.Unmarshal(data, &fileVars); err != nil {
+			return nil, fmt.Errorf("vars file %s: %w", path, err)
+		}
+		for k, v := range fileVars {
+			if _, ok := vars[k]; !ok {
+				vars[k] = v
+			}
+		}
+	}
+	return vars, nil
+}
+
+// expand calls add for each driver, automation, and zone created by expanding t for each combination of i's loops.
+func (i Instance) expand(global scope, t Template, add func(kind string, raw []byte) error) error {
+	loopNames := make([]string, 0, len(i.ForEach))
+	for name := range i.ForEach {
+		loopNames = append(loopNames, name)
+	}
+	sort.Strings(loopNames)
+	if err := checkParams(t, i, loopNames); err != nil {
+		return err
+	}
+	loopValues := make([][]any, len(loopNames))
+	for n, name := range loopNames {
+		vs, err := i.ForEach[name].values(global)
+		if err != nil {
+			return fmt.Errorf("forEach %s: %w", name, err)
+		}
+		if len(vs) == 0 {
+			return nil // nothing to expand
+		}
+		loopValues[n] = vs
+	}
+
+	// step through every combination of loop values, like an odometer
+	idx := make([]int, len(loopNames))
+	var errs error
+	for {
+		params := make(map[string]any, len(i.Args)+len(loopNames))
+		var desc []string
+		for n, name := range loopNames {
+			v := loopValues[n][idx[n]]
+			params[name] = v
+			desc = append(desc, fmt.Sprintf("%s=%v", name, v))
+		}
+		err := i.expandOnce(global, t, params, add)
+		if err != nil {
+			if len(desc) > 0 {
+				err = fmt.Errorf("%s: %w", strings.Join(desc, ","), err)
+			}
+			errs = multierr.Append(errs, err)
+		}
+
+		n := len(idx) - 1
+		for ; n >= 0; n-- {
+			idx[n]++
+			if idx[n] < len(loopValues[n]) {
+				break
+			}
+			idx[n] = 0
+		}
+		if n < 0 {
+			return errs
+		}
+	}
+}
+
+// expandOnce expands t using loop params, and i.Args substituted using those params.
+func (i Instance) expandOnce(global scope, t Template, params map[string]any, add func(kind string, raw []byte) error) error {
+	argScope := global.with(params)
+	for name, arg := range i.Args {
+		v, err := argScope.substitute(arg)
+		if err != nil {
+			return fmt.Errorf("args %s: %w", name, err)
+		}
+		params[name] = v
+	}
+	s := global.with(params)
+
+	var errs error
+	for _, group := range []struct {
+		kind  string
+		items []json.RawMessage
+	}{
+		{"driver", t.Drivers},
+		{"automation", t.Automation},
+		{"zone", t.Zones},
+	} {
+		for n, item := range group.items {
+			var v any
+			if err := json.Unmarshal(item, &v); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("%s %d: %w", group.kind, n, err))
+				continue
+			}
+			v, err := s.substitute(v)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("%s %d: %w", group.kind, n, err))
+				continue
+			}
+			raw, err := json.Marshal(v)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("%s %d: %w", group.kind, n, err))
+				continue
+			}
+			errs = multierr.Append(errs, add(group.kind, raw))
+		}
+	}
+	return errs
+}
+
+func checkParams(t Template, i Instance, loopNames []string) error {
+	if len(t.Params) == 0 {
+		return nil
+	}
+	provided := make(map[string]bool, len(i.Args)+len(loopNames))
+	for name := range i.Args {
+		provided[name] = true
+	}
+	for _, name := range loopNames {
+		provided[name] = true
+	}
+	var errs error
+	for _, p := range t.Params {
+		if !provided[p] {
+			errs = multierr.Append(errs, fmt.Errorf("missing param %q", p))
+		}
+		delete(provided, p)
+	}
+	unknown := make([]string, 0, len(provided))
+	for name := range provided {
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		errs = multierr.Append(errs, fmt.Errorf("unknown param %q", name))
+	}
+	return errs
+}
+
+// addExpanded adds the expanded raw config of the given kind to c.
+// names tracks the names already used by each kind, which must be unique.
+func addExpanded(c *Config, names map[string]map[string]bool, kind string, raw []byte) error {
+	var name string
+	switch kind {
+	case "driver":
+		var cfg driver.RawConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return err
+		}
+		name = cfg.Name
+		c.Drivers = append(c.Drivers, cfg)
+	case "automation":
+		var cfg auto.RawConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return err
+		}
+		name = cfg.Name
+		c.Automation = append(c.Automation, cfg)
+	case "zone":
+		var cfg zone.RawConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return err
+		}
+		name = cfg.Name
+		c.Zones = append(c.Zones, cfg)
+	}
+	if name == "" {
+		return fmt.Errorf("%s has no name", kind)
+	}
+	if names[kind][name] {
+		return fmt.Errorf("%s %q already exists", kind, name)
+	}
+	names[kind][name] = true
+	return nil
+}
+
+// scope resolves ${name} references.
+type scope struct {
+	vars map[string]any
+	sys  map[string]string
+}
+
+// with returns a scope where vars shadow those in s.
+func (s scope) with(vars map[string]any) scope {
+	merged := make(map[string]any, len(s.vars)+len(vars))
+	for k, v := range s.vars {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[k] = v
+	}
+	return scope{vars: merged, sys: s.sys}
+}
+
+func (s scope) lookup(name string) (any, error) {
+	switch {
+	case strings.HasPrefix(name, "env."):
+		v, ok := lookupEnv(strings.TrimPrefix(name, "env."))
+		if !ok {
+			return nil, fmt.Errorf("environment variable %q is not set", strings.TrimPrefix(name, "env."))
+		}
+		return v, nil
+	case strings.HasPrefix(name, "sys."):
+		v, ok := s.sys[strings.TrimPrefix(name, "sys.")]
+		if !ok {
+			return nil, fmt.Errorf("system config var %q is not set", strings.TrimPrefix(name, "sys."))
+		}
+		return v, nil
+	default:
+		v, ok := s.vars[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q", name)
+		}
+		return v, nil
+	}
+}
+
+// substitute replaces references in all strings in v, which is a value decoded from JSON.
+func (s scope) substitute(v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return s.substituteString(v)
+	case []any:
+		dst := make([]any, len(v))
+		for i, item := range v {
+			sub, err := s.substitute(item)
+			if err != nil {
+				return nil, err
+			}
+			dst[i] = sub
+		}
+		return dst, nil
+	case map[string]any:
+		dst := make(map[string]any, len(v))
+		for k, item := range v {
+			sub, err := s.substitute(item)
+			if err != nil {
+				return nil, err
+			}
+			dst[k] = sub
+		}
+		return dst, nil
+	default:
+		return v, nil
+	}
+}
+
+func (s scope) substituteString(str string) (any, error) {
+	if name, ok := wholeRef(str); ok {
+		return s.lookup(name)
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(str, "${")
+		if i < 0 {
+			b.WriteString(str)
+			return b.String(), nil
+		}
+		if i > 0 && str[i-1] == '$' {
+			// escaped, $${ is a literal ${
+			b.WriteString(str[:i-1])
+			b.WriteString("${")
+			str = str[i+2:]
+			continue
+		}
+		end := strings.IndexByte(str[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated reference in %q", str)
+		}
+		v, err := s.lookup(str[i+2 : i+end])
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(str[:i])
+		switch v := v.(type) {
+		case string:
+			b.WriteString(v)
+		default:
+			enc, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			b.Write(enc)
+		}
+		str = str[i+end+1:]
+	}
+}
+
+// wholeRef returns the name referenced by s if s is only a reference, like "${name}".
+func wholeRef(s string) (string, bool) {
+	if !strings.HasPrefix(s, "${") || !strings.HasSuffix(s, "}") {
+		return "", false
+	}
+	name := s[2 : len(s)-1]
+	if name == "" || strings.ContainsAny(name, "${}") {
+		return "", false
+	}
+	return name, true
+}
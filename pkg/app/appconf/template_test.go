@@ -0,0 +1,204 @@
+package appconf
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExpandTemplates(t *testing.T) {
+	lookupEnv = func(key string) (string, bool) {
+		if key == "SITE" {
+			return "hq", true
+		}
+		return "", false
+	}
+	t.Cleanup(func() { lookupEnv = os.LookupEnv })
+
+	tests := []struct {
+		name    string
+		config  string
+		sys     map[string]string
+		drivers []string // raw driver config, compared as JSON
+		zones   []string
+		err     string // substring of the error, if expected
+	}{
+		{
+			name: "no templates",
+			config: `{
+				"drivers": [{"name": "d1", "type": "t"}]
+			}`,
+			drivers: []string{`{"name": "d1", "type": "t"}`},
+		},
+		{
+			name: "args",
+			config: `{
+				"templates": [{"name": "meter", "params": ["id"], "drivers": [{"name": "meter-${id}", "type": "t", "addr": "10.0.0.${id}"}]}],
+				"instances": [{"template": "meter", "args": {"id": "5"}}]
+			}`,
+			drivers: []string{`{"name": "meter-5", "type": "t", "addr": "10.0.0.5"}`},
+		},
+		{
+			name: "range loop",
+			config: `{
+				"templates": [{"name": "floor", "params": ["floor"], "zones": [{"name": "floor-${floor}", "type": "area", "floor": "${floor}"}]}],
+				"instances": [{"template": "floor", "forEach": {"floor": {"from": 1, "to": 3}}}]
+			}`,
+			zones: []string{
+				`{"name": "floor-1", "type": "area", "floor": 1}`,
+				`{"name": "floor-2", "type": "area", "floor": 2}`,
+				`{"name": "floor-3", "type": "area", "floor": 3}`,
+			},
+		},
+		{
+			name: "nested loops",
+			config: `{
+				"vars": {"wings": ["east", "west"]},
+				"templates": [{"name": "fcu", "drivers": [{"name": "${wing}/${floor}", "type": "t"}]}],
+				"instances": [{"template": "fcu", "forEach": {"wing": "${wings}", "floor": [1, 2]}}]
+			}`,
+			drivers: []string{
+				`{"name": "east/1", "type": "t"}`,
+				`{"name": "west/1", "type": "t"}`,
+				`{"name": "east/2", "type": "t"}`,
+				`{"name": "west/2", "type": "t"}`,
+			},
+		},
+		{
+			name: "vars env and sys",
+			config: `{
+				"vars": {"devices": ["a", "b"], "port": 47808},
+				"templates": [{"name": "gw", "drivers": [{
+					"name": "${env.SITE}-${sys.building}-${name}",
+					"type": "t",
+					"devices": "${devices}",
+					"port": "${port}",
+					"literal": "$${name}"
+				}]}],
+				"instances": [{"template": "gw", "args": {"name": "gw-${env.SITE}"}}]
+			}`,
+			sys: map[string]string{"building": "b1"},
+			drivers: []string{
+				`{"name": "hq-b1-gw-hq", "type": "t", "devices": ["a", "b"], "port": 47808, "literal": "${name}"}`,
+			},
+		},
+		{
+			name: "unknown template",
+			config: `{
+				"instances": [{"template": "missing"}]
+			}`,
+			err: `unknown template "missing"`,
+		},
+		{
+			name: "missing param",
+			config: `{
+				"templates": [{"name": "meter", "params": ["id"], "drivers": [{"name": "meter-${id}", "type": "t"}]}],
+				"instances": [{"template": "meter"}]
+			}`,
+			err: `missing param "id"`,
+		},
+		{
+			name: "unknown variable",
+			config: `{
+				"templates": [{"name": "meter", "drivers": [{"name": "meter-${id}", "type": "t"}]}],
+				"instances": [{"template": "meter"}]
+			}`,
+			err: `unknown variable "id"`,
+		},
+		{
+			name: "duplicate name",
+			config: `{
+				"drivers": [{"name": "meter-1", "type": "t"}],
+				"templates": [{"name": "meter", "drivers": [{"name": "meter-${id}", "type": "t"}]}],
+				"instances": [{"template": "meter", "forEach": {"id": [1, 2]}}]
+			}`,
+			err: `driver "meter-1" already exists`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Config
+			if err := json.Unmarshal([]byte(tt.config), &c); err != nil {
+				t.Fatal(err)
+			}
+			before := c.clone()
+			err := ExpandTemplates(&c, tt.sys)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want containing %q", err, tt.err)
+				}
+				if diff := cmp.Diff(before.Drivers, c.Drivers); diff != "" {
+					t.Fatalf("config changed on error (-want,+got)\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Templates != nil || c.Instances != nil || c.Vars != nil {
+				t.Errorf("templates not cleared after expanding")
+			}
+
+			var drivers, zones []string
+			for _, d := range c.Drivers {
+				drivers = append(drivers, string(d.Raw))
+			}
+			for _, z := range c.Zones {
+				zones = append(zones, string(z.Raw))
+			}
+			if diff := cmp.Diff(tt.drivers, drivers, jsonStrings); diff != "" {
+				t.Errorf("drivers (-want,+got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.zones, zones, jsonStrings); diff != "" {
+				t.Errorf("zones (-want,+got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadLocalConfig_templates(t *testing.T) {
+	fsys := fstest.MapFS{
+		filepath.Join("data", "base.json"): {Data: []byte(`{
+			"includes": ["site/floors.json"],
+			"vars": {"type": "override"}
+		}`)},
+		filepath.Join("data", "site", "floors.json"): {Data: []byte(`{
+			"varsFiles": ["vars.json"],
+			"templates": [{"name": "floor", "drivers": [{"name": "${sys.site}-floor-${floor}", "type": "${type}"}]}],
+			"instances": [{"template": "floor", "forEach": {"floor": "${floors}"}}]
+		}`)},
+		filepath.Join("data", "site", "vars.json"): {Data: []byte(`{"floors": ["G", "1"], "type": "t"}`)},
+	}
+	readFile = func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}
+	t.Cleanup(func() { readFile = os.ReadFile })
+
+	c, err := LoadLocalConfig("data", "base.json", map[string]string{"site": "hq"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range c.Drivers {
+		got = append(got, d.Name+":"+d.Type)
+	}
+	want := []string{"hq-floor-G:override", "hq-floor-1:override"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("drivers (-want,+got)\n%s", diff)
+	}
+}
+
+// jsonStrings compares strings as JSON values, ignoring formatting and the order of object keys.
+var jsonStrings = cmp.Comparer(func(a, b string) bool {
+	var va, vb any
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	return cmp.Equal(va, vb)
+})
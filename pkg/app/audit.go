@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	auditlog "github.com/smart-core-os/sc-bos/internal/audit"
+	"github.com/smart-core-os/sc-bos/internal/audit/auditdb"
+	"github.com/smart-core-os/sc-bos/pkg/app/files"
+	"github.com/smart-core-os/sc-bos/pkg/app/sysconf"
+	"github.com/smart-core-os/sc-bos/pkg/auth/audit"
+	"github.com/smart-core-os/sc-bos/pkg/auth/token"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+// setupAuditLog opens the audit log store and announces the AuditApi on the rootNode.
+// The returned interceptor should be installed on the grpc server to record requests,
+// the returned log can be used to record and read other events, like account lockouts.
+// Returns a nil interceptor and log if the audit log is disabled.
+func setupAuditLog(ctx context.Context, config sysconf.Config, rootNode *node.Node, tokenValidator token.Validator, logger *zap.Logger) (_ *audit.Interceptor, _ *auditlog.Log, close func() error, _ error) {
+	if config.Audit == nil {
+		return nil, nil, func() error { return nil }, nil
+	}
+	var maxAge time.Duration
+	var maxCount int64
+	if v := config.Audit.TTL.MaxAge; v != nil {
+		maxAge = v.Duration
+	}
+	if v := config.Audit.TTL.MaxCount; v != nil {
+		maxCount = int64(*v)
+	}
+	db, err := auditdb.Open(ctx, files.Path(config.DataDir, sysconf.AuditDBPath),
+		auditdb.WithLogger(logger.Named("db")),
+		auditdb.WithRetention(maxAge, maxCount),
+	)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("audit store: %w", err)
+	}
+	log := auditlog.NewLog(db)
+	rootNode.Announce(rootNode.Name(),
+		node.HasServer[gen.AuditApiServer](gen.RegisterAuditApiServer, auditlog.NewServer(log)),
+	)
+	interceptor := audit.NewInterceptor(log,
+		audit.WithLogger(logger),
+		audit.WithTokenVerifier(tokenValidator),
+	)
+	return interceptor, log, db.Close, nil
+}
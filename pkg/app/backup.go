@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/timshannon/bolthold"
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/internal/backup"
+	"github.com/smart-core-os/sc-bos/internal/secrets"
+	"github.com/smart-core-os/sc-bos/pkg/app/files"
+	"github.com/smart-core-os/sc-bos/pkg/app/sysconf"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+// setupBackups announces the BackupApi on the rootNode and starts creating backups on the configured schedule.
+// The returned close func stops scheduled backups.
+func setupBackups(config sysconf.Config, rootNode *node.Node, db *bolthold.Store, logger *zap.Logger) (func() error, error) {
+	if config.Backup == nil {
+		return func() error { return nil }, nil
+	}
+	dirPath := config.Backup.Dir
+	if dirPath == "" {
+		dirPath = sysconf.DefaultBackupDir
+	}
+	dirPath = files.Path(config.DataDir, dirPath)
+	maxCount := 7
+	if v := config.Backup.TTL.MaxCount; v != nil {
+		maxCount = *v
+	}
+	var maxAge time.Duration
+	if v := config.Backup.TTL.MaxAge; v != nil {
+		maxAge = v.Duration
+	}
+
+	var version string
+	if Version.BuildInfo != nil {
+		version = Version.Main.Version
+	}
+	createOpts := []backup.Option{
+		backup.WithLogger(logger),
+		backup.WithSource(config.Name, version),
+	}
+	if db != nil {
+		createOpts = append(createOpts, backup.WithBolt("db.bolt", db.Bolt()))
+	}
+	if rel, err := filepath.Rel(config.DataDir, dirPath); err == nil && filepath.IsLocal(rel) {
+		createOpts = append(createOpts, backup.WithExclude(filepath.ToSlash(rel)))
+	}
+	if config.Backup.KeyFile != "" {
+		text, err := os.ReadFile(config.Backup.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("backup key: %w", err)
+		}
+		key, err := secrets.ParseKey(text)
+		if err != nil {
+			return nil, fmt.Errorf("backup key: %w", err)
+		}
+		createOpts = append(createOpts, backup.WithKey(key))
+	}
+
+	dir := backup.NewDir(dirPath, logger)
+	var createMu sync.Mutex // one backup at a time
+	create := func(ctx context.Context) (backup.Info, error) {
+		createMu.Lock()
+		defer createMu.Unlock()
+		info, err := dir.Create(ctx, config.DataDir, createOpts...)
+		if err != nil {
+			return info, err
+		}
+		if err := dir.Prune(maxCount, maxAge, time.Now()); err != nil {
+			logger.Warn("failed to remove old backups", zap.Error(err))
+		}
+		return info, nil
+	}
+	rootNode.Announce(rootNode.Name(),
+		node.HasServer[gen.BackupApiServer](gen.RegisterBackupApiServer, backup.NewServer(dir, create)),
+	)
+
+	schedule := config.Backup.Schedule
+	if schedule == nil {
+		return func() error { return nil }, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Until(schedule.Next(time.Now()))):
+			}
+			if _, err := create(ctx); err != nil && ctx.Err() == nil {
+				logger.Warn("scheduled backup failed", zap.Error(err))
+			}
+		}
+	}()
+	return func() error {
+		cancel()
+		<-done
+		return nil
+	}, nil
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"go.uber.org/multierr"
+
+	"github.com/smart-core-os/sc-bos/pkg/app/appconf"
+	"github.com/smart-core-os/sc-bos/pkg/app/sysconf"
+	"github.com/smart-core-os/sc-bos/pkg/auth/token"
+	"github.com/smart-core-os/sc-bos/pkg/auto"
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+	"github.com/smart-core-os/sc-bos/pkg/zone"
+)
+
+// configHistoryOpts returns options for the app config store that record revisions as configured.
+// The author of each revision is found from the access token of the request that made the change.
+func configHistoryOpts(config sysconf.Config, tokenValidator token.Validator) []appconf.StoreOption {
+	maxCount := appconf.DefaultMaxRevisions
+	if v := config.ConfigHistory.MaxCount; v != nil {
+		maxCount = *v
+	}
+	var maxAge time.Duration
+	if v := config.ConfigHistory.MaxAge; v != nil {
+		maxAge = v.Duration
+	}
+	return []appconf.StoreOption{
+		appconf.WithAuthor(requestAuthor(tokenValidator)),
+		appconf.WithHistoryRetention(maxAge, maxCount),
+	}
+}
+
+func requestAuthor(tokenValidator token.Validator) func(ctx context.Context) appconf.Author {
+	return func(ctx context.Context) appconf.Author {
+		tkn, err := grpc_auth.AuthFromMD(ctx, "Bearer")
+		if err != nil || tkn == "" {
+			return appconf.Author{}
+		}
+		claims, err := tokenValidator.ValidateAccessToken(ctx, tkn)
+		if err != nil {
+			return appconf.Author{}
+		}
+		return appconf.Author{Subject: claims.Subject, DisplayName: claims.Name}
+	}
+}
+
+// applyConfigFunc returns an appconf.ApplyFunc that updates the given services to match changes in app config.
+func applyConfigFunc(drivers, automations, zones *service.Map) appconf.ApplyFunc {
+	return func(_ context.Context, old, updated appconf.Config) error {
+		return multierr.Combine(
+			applyServiceConfigs(drivers, old.Drivers, updated.Drivers, func(c driver.RawConfig) serviceConfig {
+				return serviceConfig{c.Name, c.Type, c.Disabled, c.Raw}
+			}),
+			applyServiceConfigs(automations, old.Automation, updated.Automation, func(c auto.RawConfig) serviceConfig {
+				return serviceConfig{c.Name, c.Type, c.Disabled, c.Raw}
+			}),
+			applyServiceConfigs(zones, old.Zones, updated.Zones, func(c zone.RawConfig) serviceConfig {
+				return serviceConfig{c.Name, c.Type, c.Disabled, c.Raw}
+			}),
+		)
+	}
+}
+
+// serviceConfig is the config common to drivers, automations, and zones.
+type serviceConfig struct {
+	name, kind string
+	disabled   bool
+	raw        []byte
+}
+
+// applyServiceConfigs updates the services in m whose config differs between old and updated.
+// Services only in updated are created, and services only in old are deleted.
+func applyServiceConfigs[T any](m *service.Map, old, updated []T, get func(T) serviceConfig) error {
+	oldByName := make(map[string]serviceConfig, len(old))
+	for _, c := range old {
+		sc := get(c)
+		oldByName[sc.name] = sc
+	}
+
+	var errs error
+	for _, c := range updated {
+		sc := get(c)
+		prev, existed := oldByName[sc.name]
+		delete(oldByName, sc.name)
+		if existed && prev.kind == sc.kind && prev.disabled == sc.disabled && bytes.Equal(prev.raw, sc.raw) {
+			continue
+		}
+		errs = multierr.Append(errs, applyServiceConfig(m, sc))
+	}
+	for name := range oldByName {
+		if _, err := m.Delete(name); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("delete %s: %w", name, err))
+		}
+	}
+	return errs
+}
+
+func applyServiceConfig(m *service.Map, sc serviceConfig) error {
+	r := m.Get(sc.name)
+	if r != nil && r.Kind != sc.kind {
+		// the type of a service can't be changed, replace it instead
+		if _, err := m.Delete(sc.name); err != nil {
+			return fmt.Errorf("replace %s: %w", sc.name, err)
+		}
+		r = nil
+	}
+	if r == nil {
+		if _, _, err := m.Create(sc.name, sc.kind, service.State{Active: !sc.disabled, Config: sc.raw}); err != nil {
+			return fmt.Errorf("create %s: %w", sc.name, err)
+		}
+		return nil
+	}
+
+	if _, err := r.Service.Configure(sc.raw); err != nil {
+		return fmt.Errorf("configure %s: %w", sc.name, err)
+	}
+	var err error
+	if sc.disabled {
+		_, err = r.Service.Stop()
+		if errors.Is(err, service.ErrAlreadyStopped) {
+			err = nil
+		}
+	} else {
+		_, err = r.Service.Start()
+		if errors.Is(err, service.ErrAlreadyStarted) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("start/stop %s: %w", sc.name, err)
+	}
+	return nil
+}
@@ -15,8 +15,10 @@ import (
 
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"github.com/open-policy-agent/opa/rego"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 	"github.com/timshannon/bolthold"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -24,6 +26,7 @@ import (
 	"google.golang.org/grpc/credentials"
 
 	"github.com/smart-core-os/sc-bos/internal/account"
+	auditlog "github.com/smart-core-os/sc-bos/internal/audit"
 	"github.com/smart-core-os/sc-bos/internal/manage/devices"
 	"github.com/smart-core-os/sc-bos/internal/node/nodeopts"
 	"github.com/smart-core-os/sc-bos/internal/util/grpc/interceptors"
@@ -76,11 +79,20 @@ func Bootstrap(ctx context.Context, config sysconf.Config) (*Controller, error)
 		// successfully loaded the config
 		logger.Debug("loaded external config", zap.Strings("paths", config.AppConfig), zap.Strings("includes", externalConf.Includes), zap.Strings("filesLoaded", filesLoaded))
 	}
+	// expand templates before any drivers, automations, or zones are created from the config
+	if err := appconf.ExpandTemplates(&externalConf, config.Vars); err != nil {
+		return nil, fmt.Errorf("app config templates: %w", err)
+	}
+	// tokenValidator validates access tokens as part of the authorisation of requests to our APIs.
+	// Claims associated with the token are presented along with other information when processing policy files.
+	// Systems contribute validators to this set supporting different sources of token.
+	tokenValidator := &token.ValidatorSet{}
+
 	confStore, err := appconf.LoadStore(externalConf, appconf.Schema{
 		Drivers:     config.DriverConfigBlocks(),
 		Automations: config.AutoConfigBlocks(),
 		Zones:       config.ZoneConfigBlocks(),
-	}, files.Path(config.DataDir, configDirName), logger)
+	}, files.Path(config.DataDir, configDirName), logger, configHistoryOpts(config, tokenValidator)...)
 	if err != nil {
 		return nil, err
 	}
@@ -236,16 +248,50 @@ func Bootstrap(ctx context.Context, config sysconf.Config) (*Controller, error)
 	manager := node.DialChan(ctx, enrollServer.ManagerAddress(ctx),
 		grpc.WithTransportCredentials(credentials.NewTLS(tlsGRPCClientConfig)))
 
+	// spans for requests to the grpc server are exported if tracing is configured,
+	// trace context is always passed along to other nodes.
+	closeTracing, err := setupTracing(ctx, config, cName, logger.Named("tracing"))
+	if err != nil {
+		return nil, err
+	}
+
 	var grpcOpts []grpc.ServerOption
 	grpcOpts = append(grpcOpts,
 		grpc.Creds(credentials.NewTLS(tlsGRPCServerConfig)),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.ChainStreamInterceptor(interceptors.CorrectStreamInfo(rootNode)),
 	)
+	if !config.Metrics.Disabled {
+		// before auth so denied requests are also counted
+		grpcOpts = append(grpcOpts,
+			grpc.ChainUnaryInterceptor(interceptors.MetricsUnary()),
+			grpc.ChainStreamInterceptor(interceptors.MetricsStream()),
+		)
+	}
 
-	// tokenValidator validates access tokens as part of the authorisation of requests to our APIs.
-	// Claims associated with the token are presented along with other information when processing policy files.
-	// Systems contribute validators to this set supporting different sources of token.
-	tokenValidator := &token.ValidatorSet{}
+	// AuditApi, recording of requests that might change the system.
+	// This comes before the policy interceptor so denied requests are also recorded.
+	auditInterceptor, auditLog, closeAuditStore, err := setupAuditLog(ctx, config, rootNode, tokenValidator, logger.Named("audit"))
+	if err != nil {
+		return nil, err
+	}
+	if auditInterceptor != nil {
+		grpcOpts = append(grpcOpts,
+			grpc.ChainUnaryInterceptor(auditInterceptor.GRPCUnaryInterceptor()),
+			grpc.ChainStreamInterceptor(auditInterceptor.GRPCStreamingInterceptor()),
+		)
+	}
+
+	// SecretStoreApi, and resolution of secrets referenced by app config.
+	if err := setupSecretStore(config, rootNode, logger.Named("secrets")); err != nil {
+		return nil, err
+	}
+
+	// BackupApi, and scheduled backups of the data dir.
+	closeBackups, err := setupBackups(config, rootNode, db, logger.Named("backup"))
+	if err != nil {
+		return nil, err
+	}
 
 	// configure request authorisation, here we setup grpc interceptors that decide if a request is denied or not.
 	logPolicyMode(config.PolicyMode, logger)
@@ -331,6 +377,15 @@ func Bootstrap(ctx context.Context, config sysconf.Config) (*Controller, error)
 		mux.Handle("GET /__/debug/pprof/", httpAuth(http.StripPrefix("/__", pprofMux)))
 	}
 
+	if !config.Metrics.Disabled {
+		metricsAuth := httpAuth
+		if config.Metrics.Public {
+			metricsAuth = func(next http.Handler) http.Handler { return next }
+		}
+		// Prometheus metrics for drivers, automations, gRPC requests, and more
+		mux.Handle("GET /metrics", metricsAuth(promhttp.Handler()))
+	}
+
 	// configure CORS setup
 	co := cors.New(cors.Options{
 		AllowedOrigins:   config.Cors.CorsOrigins,
@@ -366,6 +421,7 @@ func Bootstrap(ctx context.Context, config sysconf.Config) (*Controller, error)
 		Database:         db,
 		Stores:           store,
 		Accounts:         accountStore,
+		AuditLog:         auditLog,
 		TokenValidators:  tokenValidator,
 		GRPCCerts:        systemSource,
 		ReflectionServer: reflectionServer,
@@ -379,6 +435,9 @@ func Bootstrap(ctx context.Context, config sysconf.Config) (*Controller, error)
 	c.Defer(manager.Close)
 	c.Defer(store.Close)
 	c.Defer(closeHealthStore)
+	c.Defer(closeAuditStore)
+	c.Defer(closeTracing)
+	c.Defer(closeBackups)
 	return c, nil
 }
 
@@ -444,6 +503,7 @@ type Controller struct {
 	GRPCCerts       *pki.SourceSet
 	Stores          *stores.Stores
 	Accounts        *account.Store
+	AuditLog        *auditlog.Log // nil if the audit log is disabled
 	CheckRegistry   *healthpb.Registry
 
 	ReflectionServer *reflectionapi.Server
@@ -524,6 +584,11 @@ func (c *Controller) Run(ctx context.Context) (err error) {
 	announceServices(c, "zones", zoneServices, c.SystemConfig.ZoneFactories, c.ControllerConfig.Zones())
 	go logServiceMapChanges(ctx, c.Logger.Named("zone"), zoneServices)
 
+	// ConfigHistoryApi, rolling back config applies the changes to the services we just started
+	c.Node.Announce(c.Node.Name(), node.HasServer[gen.ConfigHistoryApiServer](gen.RegisterConfigHistoryApiServer,
+		appconf.NewHistoryServer(c.ControllerConfig, applyConfigFunc(driverServices, autoServices, zoneServices)),
+	))
+
 	err = multierr.Append(err, group.Wait())
 	return
 }
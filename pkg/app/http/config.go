@@ -14,3 +14,10 @@ type CorsConfig struct {
 	// CorsOrigins specifies a list of allowed domains for passing to the CORS handler
 	CorsOrigins []string `json:"corsOrigins,omitempty"`
 }
+
+type MetricsConfig struct {
+	// Disabled turns off the /metrics endpoint and the collection of gRPC request metrics
+	Disabled bool `json:"disabled,omitempty"`
+	// Public serves /metrics without checking the request against the policy, for scrapers that can't present a token
+	Public bool `json:"public,omitempty"`
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/internal/secrets"
+	"github.com/smart-core-os/sc-bos/pkg/app/files"
+	"github.com/smart-core-os/sc-bos/pkg/app/sysconf"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+// setupSecretStore opens the secret store and makes its secrets available to app config via jsontypes.
+func setupSecretStore(config sysconf.Config, rootNode *node.Node, logger *zap.Logger) error {
+	if config.Secrets == nil {
+		return nil
+	}
+	key, err := readSecretsKey(config.Secrets.KeyFile, config.Secrets.KeyEnv)
+	if err != nil {
+		return fmt.Errorf("secret store key: %w", err)
+	}
+	var oldKeys [][]byte
+	for _, f := range config.Secrets.OldKeyFiles {
+		k, err := readSecretsKey(f, "")
+		if err != nil {
+			return fmt.Errorf("secret store old key: %w", err)
+		}
+		oldKeys = append(oldKeys, k)
+	}
+	store, err := secrets.Open(files.Path(config.DataDir, sysconf.SecretsPath), key,
+		secrets.WithLogger(logger),
+		secrets.WithOldKeys(oldKeys...),
+	)
+	if err != nil {
+		return fmt.Errorf("secret store: %w", err)
+	}
+	jsontypes.SetSecretResolver(store.Get)
+	rootNode.Announce(rootNode.Name(),
+		node.HasServer[gen.SecretStoreApiServer](gen.RegisterSecretStoreApiServer, secrets.NewServer(store)),
+	)
+	return nil
+}
+
+func readSecretsKey(file, env string) ([]byte, error) {
+	if file != "" {
+		text, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return secrets.ParseKey(text)
+	}
+	if env == "" {
+		env = sysconf.DefaultSecretsKeyEnv
+	}
+	text, ok := os.LookupEnv(env)
+	if !ok {
+		return nil, errors.New("no key file and $" + env + " is not set")
+	}
+	return secrets.ParseKey([]byte(text))
+}
@@ -46,7 +46,7 @@ func (c *Controller) startDrivers(configs []driver.RawConfig) (*service.Map, err
 			return nil, fmt.Errorf("unsupported driver type %v", kind)
 		}
 		return f.New(driverServices), nil
-	}, service.IdIsRequired)
+	}, service.IdIsRequired, service.WithMetricsGroup("drivers"))
 
 	var allErrs error
 	for _, cfg := range configs {
@@ -79,7 +79,7 @@ func (c *Controller) startAutomations(configs []auto.RawConfig) (*service.Map, e
 			return nil, fmt.Errorf("unsupported automation type %v", kind)
 		}
 		return f.New(autoServices), nil
-	}, service.IdIsRequired)
+	}, service.IdIsRequired, service.WithMetricsGroup("automations"))
 
 	var allErrs error
 	for _, cfg := range configs {
@@ -94,6 +94,10 @@ func (c *Controller) startSystems() (*service.Map, error) {
 	if err != nil {
 		return nil, err
 	}
+	httpEndpoint, err := c.SystemConfig.ExternalHTTPEndpoint()
+	if err != nil {
+		c.Logger.Warn("failed to determine external http endpoint", zap.Error(err))
+	}
 	ctxServices := system.Services{
 		ConfigDirs:       c.SystemConfig.ConfigDirs,
 		DataDir:          c.SystemConfig.DataDir,
@@ -101,9 +105,11 @@ func (c *Controller) startSystems() (*service.Map, error) {
 		Node:             c.Node,
 		HealthChecks:     devicesToHealthCheckCollection(c.DeviceStore),
 		GRPCEndpoint:     grpcEndpoint,
+		HTTPEndpoint:     httpEndpoint,
 		Database:         c.Database,
 		Stores:           c.Stores,
 		Accounts:         c.Accounts,
+		AuditLog:         c.AuditLog,
 		HTTPMux:          c.Mux,
 		TokenValidators:  c.TokenValidators,
 		ReflectionServer: c.ReflectionServer,
@@ -118,7 +124,7 @@ func (c *Controller) startSystems() (*service.Map, error) {
 			return nil, fmt.Errorf("unsupported system type %v", kind)
 		}
 		return f.New(ctxServices), nil
-	}, service.IdIsKind)
+	}, service.IdIsKind, service.WithMetricsGroup("systems"))
 
 	var allErrs error
 	for kind, cfg := range c.SystemConfig.Systems {
@@ -148,7 +154,7 @@ func (c *Controller) startZones(configs []zone.RawConfig) (*service.Map, error)
 			return nil, fmt.Errorf("unsupported zone type %v", kind)
 		}
 		return f.New(zoneServices), nil
-	}, service.IdIsRequired)
+	}, service.IdIsRequired, service.WithMetricsGroup("zones"))
 
 	var allErrs error
 	for _, cfg := range configs {
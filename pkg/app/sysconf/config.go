@@ -74,9 +74,21 @@ type Config struct {
 	CertConfig    *Certs                     `json:"certs,omitempty"`
 	Cors          http.CorsConfig            `json:"cors,omitempty"`
 
-	DisablePprof bool `json:"disablePprof"` // don't register net/http/pprof handlers
+	DisablePprof bool               `json:"disablePprof"`      // don't register net/http/pprof handlers
+	Metrics      http.MetricsConfig `json:"metrics,omitempty"` // Prometheus metrics served on /metrics
 
 	Health *Health `json:"health,omitempty"`
+	Audit  *Audit  `json:"audit,omitempty"` // record non-read requests, nil disables the audit log
+
+	Tracing *Tracing `json:"tracing,omitempty"` // export OpenTelemetry traces, nil disables exporting
+
+	ConfigHistory ConfigHistory `json:"configHistory,omitempty"` // how many revisions of the app config to keep
+	// Vars are available to app config templates as ${sys.name}.
+	Vars map[string]string `json:"vars,omitempty"`
+
+	Secrets *Secrets `json:"secrets,omitempty"` // encrypted store of secrets referenced by app config, nil disables the store
+
+	Backup *Backup `json:"backup,omitempty"` // backups of DataDir, nil disables the BackupApi and scheduled backups
 
 	Systems map[string]system.RawConfig `json:"systems,omitempty"`
 
@@ -171,6 +183,75 @@ type HealthTTL struct {
 	MaxAge   *jsontypes.Duration `json:"maxAge,omitempty"`   // defaults to 1 week
 }
 
+// Audit configures the audit log of requests made to this node.
+type Audit struct {
+	TTL AuditTTL `json:"ttl,omitempty"` // how long to keep audit events
+}
+
+// AuditDBPath is the location of the SQLite database file used to store audit events.
+// Relative paths are relative to DataDir.
+const AuditDBPath = "audit/events.sqlite3"
+
+type AuditTTL struct {
+	MaxCount *int                `json:"maxCount,omitempty"` // defaults to no max count
+	MaxAge   *jsontypes.Duration `json:"maxAge,omitempty"`   // defaults to 90 days
+}
+
+// Secrets configures the store of secrets, like passwords, that app config can refer to using {"$secret": "name"}.
+// Secrets are encrypted using a key read from KeyFile, or from the environment variable KeyEnv.
+// The key is 32 bytes, base64 encoded, for example the output of `openssl rand -base64 32`.
+type Secrets struct {
+	KeyFile string `json:"keyFile,omitempty"`
+	KeyEnv  string `json:"keyEnv,omitempty"` // defaults to SCBOS_SECRETS_KEY if KeyFile is not set
+	// Files containing keys previously used to encrypt the store.
+	// Secrets encrypted using these keys are re-encrypted using the current key on startup,
+	// after which the old keys are no longer needed.
+	OldKeyFiles []string `json:"oldKeyFiles,omitempty"`
+}
+
+// DefaultSecretsKeyEnv is the environment variable the secrets key is read from if no other source is configured.
+const DefaultSecretsKeyEnv = "SCBOS_SECRETS_KEY"
+
+// SecretsPath is the location of the file used to store secrets.
+// Relative paths are relative to DataDir.
+const SecretsPath = "secrets/secrets.json"
+
+// Backup configures backups of the data directory.
+// Backups are stored in Dir, which is not itself included in backups.
+type Backup struct {
+	Dir      string              `json:"dir,omitempty"`      // defaults to DefaultBackupDir, relative paths are relative to DataDir
+	Schedule *jsontypes.Schedule `json:"schedule,omitempty"` // when to create backups, nil means only when requested via the BackupApi
+	// Encrypt backups using the key in this file.
+	// The key is 32 bytes, base64 encoded, and is needed to restore the backups.
+	KeyFile string    `json:"keyFile,omitempty"`
+	TTL     BackupTTL `json:"ttl,omitempty"` // how long to keep backups
+}
+
+// DefaultBackupDir is the directory backups are stored in if Backup.Dir is not set.
+const DefaultBackupDir = "backups"
+
+type BackupTTL struct {
+	MaxCount *int                `json:"maxCount,omitempty"` // defaults to 7, 0 means no max count
+	MaxAge   *jsontypes.Duration `json:"maxAge,omitempty"`   // defaults to no max age
+}
+
+// ConfigHistory configures how many previous revisions of the app config are kept.
+type ConfigHistory struct {
+	MaxCount *int                `json:"maxCount,omitempty"` // defaults to 100, 0 means no max count
+	MaxAge   *jsontypes.Duration `json:"maxAge,omitempty"`   // defaults to no max age
+}
+
+// Tracing configures the export of OpenTelemetry traces using OTLP over gRPC.
+// Trace context is propagated between nodes whether spans are exported or not.
+type Tracing struct {
+	Endpoint string            `json:"endpoint,omitempty"` // host:port of the OTLP collector
+	Insecure bool              `json:"insecure,omitempty"` // connect to Endpoint without TLS
+	Headers  map[string]string `json:"headers,omitempty"`  // sent with each export, for example for authentication
+	// The fraction of new traces that are recorded, from 0 to 1. Defaults to 1.
+	// Requests that are part of an existing trace are recorded if the caller recorded them.
+	SampleRatio *float64 `json:"sampleRatio,omitempty"`
+}
+
 func Default() Config {
 	logConf := zap.NewDevelopmentConfig()
 	one := 1
@@ -198,6 +279,11 @@ func Default() Config {
 				MaxAge:   &jsontypes.Duration{Duration: 7 * 24 * time.Hour},
 			},
 		},
+		Audit: &Audit{
+			TTL: AuditTTL{
+				MaxAge: &jsontypes.Duration{Duration: 90 * 24 * time.Hour},
+			},
+		},
 
 		CertConfig: &Certs{
 			KeyFile:      "grpc.key.pem",
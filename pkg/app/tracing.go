@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/app/sysconf"
+)
+
+// setupTracing configures the global OpenTelemetry propagator, and if config.Tracing is set a TracerProvider that
+// exports spans to the configured OTLP endpoint.
+// The propagator is always configured so nodes pass trace context along even if they don't export their own spans.
+// The returned func flushes any pending spans and stops exporting.
+func setupTracing(ctx context.Context, config sysconf.Config, name string, logger *zap.Logger) (close func() error, _ error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	cfg := config.Tracing
+	if cfg == nil || cfg.Endpoint == "" {
+		return func() error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("otlp exporter: %w", err)
+	}
+
+	attrs := []resource.Option{
+		resource.WithAttributes(semconv.ServiceName("sc-bos"), semconv.ServiceInstanceID(name)),
+	}
+	if Version.BuildInfo != nil {
+		attrs = append(attrs, resource.WithAttributes(semconv.ServiceVersion(Version.Main.Version)))
+	}
+	res, err := resource.New(ctx, append(attrs, resource.WithHost(), resource.WithTelemetrySDK())...)
+	if err != nil {
+		return nil, fmt.Errorf("resource: %w", err)
+	}
+
+	ratio := 1.0
+	if cfg.SampleRatio != nil {
+		ratio = *cfg.SampleRatio
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("tracing error", zap.Error(err))
+	}))
+	logger.Info("exporting traces", zap.String("endpoint", cfg.Endpoint), zap.Float64("sampleRatio", ratio))
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return tp.Shutdown(ctx)
+	}, nil
+}
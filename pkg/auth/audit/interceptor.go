@@ -0,0 +1,296 @@
+// Package audit provides gRPC interceptors that record requests that may modify the state of the system.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/internal/util/rpcutil"
+	"github.com/smart-core-os/sc-bos/pkg/auth/token"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// Recorder saves audit events.
+type Recorder interface {
+	Record(ctx context.Context, event *gen.AuditEvent) error
+}
+
+// RecorderFunc adapts a func to implement Recorder.
+type RecorderFunc func(ctx context.Context, event *gen.AuditEvent) error
+
+func (f RecorderFunc) Record(ctx context.Context, event *gen.AuditEvent) error {
+	return f(ctx, event)
+}
+
+// Interceptor records an audit event for each request that isn't a read request.
+//
+// Read requests are those whose method starts with one of the read verbs: Get, List, Pull, or Describe.
+// Requests to the grpc.* services, like reflection, are never recorded.
+//
+// The interceptor should be installed before any authorization interceptors so that denied requests are also recorded.
+type Interceptor struct {
+	recorder Recorder
+	logger   *zap.Logger
+	verifier token.Validator
+	now      func() time.Time
+}
+
+func NewInterceptor(recorder Recorder, opts ...InterceptorOption) *Interceptor {
+	interceptor := &Interceptor{
+		recorder: recorder,
+		logger:   zap.NewNop(),
+		now:      time.Now,
+	}
+	for _, o := range opts {
+		o(interceptor)
+	}
+	return interceptor
+}
+
+type InterceptorOption func(interceptor *Interceptor)
+
+func WithLogger(logger *zap.Logger) InterceptorOption {
+	return func(interceptor *Interceptor) {
+		interceptor.logger = logger
+	}
+}
+
+// WithTokenVerifier configures the validator used to extract the identity of the caller from the request.
+// Without a verifier the actor of each event will only include network and certificate information.
+func WithTokenVerifier(tv token.Validator) InterceptorOption {
+	return func(interceptor *Interceptor) {
+		interceptor.verifier = tv
+	}
+}
+
+func (i *Interceptor) GRPCUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !ShouldAudit(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		start := i.now()
+		resp, err := handler(ctx, req)
+		i.record(ctx, info.FullMethod, start, req, err)
+		return resp, err
+	}
+}
+
+func (i *Interceptor) GRPCStreamingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !ShouldAudit(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		// The first message received is the request for unary and server streaming calls,
+		// which includes calls made via the UnknownServiceHandler.
+		// For client streams we record the first message as a representative of the stream.
+		wrapped := &firstMsgServerStream{ServerStream: ss}
+		start := i.now()
+		err := handler(srv, wrapped)
+		i.record(ss.Context(), info.FullMethod, start, wrapped.first, err)
+		return err
+	}
+}
+
+func (i *Interceptor) record(ctx context.Context, fullMethod string, start time.Time, req any, err error) {
+	service, method, _ := rpcutil.SplitMethodPath(fullMethod)
+	st := status.Convert(err)
+	event := &gen.AuditEvent{
+		RecordTime: timestamppb.New(start),
+		Actor:      i.actor(ctx),
+		Service:    service,
+		Method:     method,
+		Result: &gen.AuditEvent_Result{
+			Code:     int32(st.Code()),
+			Message:  st.Message(),
+			Duration: durationpb.New(i.now().Sub(start)),
+		},
+	}
+	if msg, ok := req.(proto.Message); ok {
+		event.Resource = resourceName(msg)
+		event.Request, event.RequestTruncated = summariseRequest(msg)
+	}
+
+	// the request may have been cancelled, but we still want a record of it
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+	if err := i.recorder.Record(ctx, event); err != nil {
+		i.logger.Error("failed to record audit event", zap.String("method", fullMethod), zap.Error(err))
+	}
+}
+
+const recordTimeout = 10 * time.Second
+
+func (i *Interceptor) actor(ctx context.Context) *gen.AuditEvent_Actor {
+	actor := &gen.AuditEvent_Actor{}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		actor.RemoteAddress = p.Addr.String()
+	}
+	if cert, valid := rpcutil.CertFromServerContext(ctx); cert != nil && valid {
+		actor.CertificateSubject = cert.Subject.CommonName
+	}
+	tkn, err := grpc_auth.AuthFromMD(ctx, "Bearer")
+	if err != nil || tkn == "" || i.verifier == nil {
+		return actor
+	}
+	claims, err := i.verifier.ValidateAccessToken(ctx, tkn)
+	if err != nil {
+		// the request will be denied by the policy interceptor, but we still audit it as anonymous
+		return actor
+	}
+	actor.Subject = claims.Subject
+	actor.DisplayName = claims.Name
+	actor.IsService = claims.IsService
+	actor.Roles = claims.SystemRoles
+	return actor
+}
+
+// ShouldAudit returns whether requests to the given full method name should be recorded in the audit log.
+func ShouldAudit(fullMethod string) bool {
+	service, method, ok := rpcutil.SplitMethodPath(fullMethod)
+	if !ok {
+		return true
+	}
+	if strings.HasPrefix(service, "grpc.") {
+		return false
+	}
+	return !isReadMethod(method)
+}
+
+var readVerbs = []string{"Get", "List", "Pull", "Describe"}
+
+// isReadMethod returns true if method looks like a read request.
+// See also the read_verbs in the default policy.
+func isReadMethod(method string) bool {
+	for _, verb := range readVerbs {
+		rest, ok := strings.CutPrefix(method, verb)
+		if !ok {
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(rest)
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceName returns the value of the name field of msg, if it has one.
+func resourceName(msg proto.Message) string {
+	m := msg.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("name")
+	if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+		return ""
+	}
+	return m.Get(fd).String()
+}
+
+// maxRequestSize is the maximum length of the request summary in an audit event.
+const maxRequestSize = 4096
+
+// summariseRequest returns a JSON representation of msg, redacting sensitive fields.
+// Summaries longer than maxRequestSize are cut at a character boundary and reported as truncated.
+func summariseRequest(msg proto.Message) (summary string, truncated bool) {
+	msg = proto.Clone(msg)
+	redact(msg.ProtoReflect())
+	b, err := protojson.MarshalOptions{AllowPartial: true}.Marshal(msg)
+	if err != nil {
+		return "", false
+	}
+	// protojson output is deliberately unstable, compact it so the summary is consistent
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err == nil {
+		b = buf.Bytes()
+	}
+	if len(b) > maxRequestSize {
+		n := maxRequestSize
+		for n > 0 && !utf8.RuneStart(b[n]) {
+			n--
+		}
+		return string(b[:n]), true
+	}
+	return string(b), false
+}
+
+const redacted = "REDACTED"
+
+// sensitiveFieldWords are parts of field names that indicate the field is sensitive.
+var sensitiveFieldWords = []string{"password", "secret", "token", "key"}
+
+// redact replaces the value of any sensitive fields in m.
+// Sensitive string fields are replaced with a placeholder, other sensitive fields are cleared.
+func redact(m protoreflect.Message) {
+	var sensitive []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case isSensitiveField(fd):
+			sensitive = append(sensitive, fd)
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					redact(v.Message())
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				l := v.List()
+				for i := 0; i < l.Len(); i++ {
+					redact(l.Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			redact(v.Message())
+		}
+		return true
+	})
+	for _, fd := range sensitive {
+		if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			m.Set(fd, protoreflect.ValueOfString(redacted))
+		} else {
+			m.Clear(fd)
+		}
+	}
+}
+
+func isSensitiveField(fd protoreflect.FieldDescriptor) bool {
+	name := strings.ToLower(string(fd.Name()))
+	if name == "page_token" {
+		return false
+	}
+	for _, word := range sensitiveFieldWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstMsgServerStream records the first message received via RecvMsg.
+type firstMsgServerStream struct {
+	grpc.ServerStream
+	first any
+}
+
+func (ss *firstMsgServerStream) RecvMsg(m any) error {
+	err := ss.ServerStream.RecvMsg(m)
+	if err == nil && ss.first == nil {
+		ss.first = m
+	}
+	return err
+}
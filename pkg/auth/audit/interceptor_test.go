@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-golang/pkg/trait/onoffpb"
+)
+
+func TestInterceptor_GRPC(t *testing.T) {
+	var eventsMu sync.Mutex
+	var events []*gen.AuditEvent
+	interceptor := NewInterceptor(RecorderFunc(func(ctx context.Context, event *gen.AuditEvent) error {
+		eventsMu.Lock()
+		defer eventsMu.Unlock()
+		events = append(events, event)
+		return nil
+	}))
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptor.GRPCUnaryInterceptor()),
+		grpc.ChainStreamInterceptor(interceptor.GRPCStreamingInterceptor()),
+	)
+	traits.RegisterOnOffApiServer(server, onoffpb.NewModelServer(onoffpb.NewModel()))
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			t.Logf("server stopped with error: %v", err)
+		}
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("localhost:0",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := traits.NewOnOffApiClient(conn)
+
+	// reads are not audited
+	if _, err := client.GetOnOff(t.Context(), &traits.GetOnOffRequest{Name: "light1"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.UpdateOnOff(t.Context(), &traits.UpdateOnOffRequest{Name: "light1", OnOff: &traits.OnOff{State: traits.OnOff_ON}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	want := []*gen.AuditEvent{
+		{
+			Service:  "smartcore.traits.OnOffApi",
+			Method:   "UpdateOnOff",
+			Resource: "light1",
+			Request:  `{"name":"light1","onOff":{"state":"ON"}}`,
+			Result:   &gen.AuditEvent_Result{Code: int32(codes.OK)},
+		},
+	}
+	diff := cmp.Diff(want, events, protocmp.Transform(),
+		protocmp.IgnoreFields(&gen.AuditEvent{}, "record_time", "actor"),
+		protocmp.IgnoreFields(&gen.AuditEvent_Result{}, "duration"),
+	)
+	if diff != "" {
+		t.Errorf("events (-want,+got)\n%s", diff)
+	}
+}
+
+func TestShouldAudit(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"/smartcore.traits.OnOffApi/UpdateOnOff", true},
+		{"/smartcore.traits.OnOffApi/GetOnOff", false},
+		{"/smartcore.traits.OnOffApi/PullOnOff", false},
+		{"/smartcore.bos.AccountApi/ListAccounts", false},
+		{"/smartcore.bos.AccountApi/DeleteAccount", true},
+		{"/smartcore.bos.ServicesApi/Pullout", true}, // not a read verb
+		{"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := ShouldAudit(tt.method); got != tt.want {
+				t.Errorf("ShouldAudit(%q) = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_summariseRequest(t *testing.T) {
+	req := &gen.UpdateAccountPasswordRequest{Id: "1", NewPassword: "hunter2hunter2", OldPassword: "password1234"}
+	got, truncated := summariseRequest(req)
+	want := `{"id":"1","newPassword":"REDACTED","oldPassword":"REDACTED"}`
+	if got != want || truncated {
+		t.Errorf("summariseRequest() = %s, %v, want %s, false", got, truncated, want)
+	}
+	if req.NewPassword != "hunter2hunter2" {
+		t.Errorf("summariseRequest modified the request")
+	}
+}
+
+func Test_summariseRequest_truncated(t *testing.T) {
+	// each é is 2 bytes, offset by the JSON prefix so maxRequestSize falls in the middle of one
+	req := &gen.UpdateAccountPasswordRequest{Id: strings.Repeat("é", maxRequestSize)}
+	got, truncated := summariseRequest(req)
+	if !truncated {
+		t.Errorf("summariseRequest() truncated = false, want true")
+	}
+	if len(got) > maxRequestSize {
+		t.Errorf("summariseRequest() length = %d, want <= %d", len(got), maxRequestSize)
+	}
+	if !utf8.ValidString(got) {
+		t.Errorf("summariseRequest() is not valid UTF-8: ...%q", got[len(got)-8:])
+	}
+}
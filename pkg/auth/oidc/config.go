@@ -4,6 +4,7 @@ package oidc
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/smart-core-os/sc-bos/internal/util/fetch"
 )
@@ -11,6 +12,7 @@ import (
 // Config represents an OpenID Connect configuration document.
 // It only contains the properties that we need to function, for now.
 type Config struct {
+	Issuer                string `json:"issuer"`
 	JWKSURI               string `json:"jwks_uri"`
 	TokenEndpoint         string `json:"token_endpoint"`
 	AuthorizationEndpoint string `json:"authorization_endpoint"`
@@ -18,7 +20,7 @@ type Config struct {
 
 // FetchConfig retrieves OIDC Config from the given issuer URL prefix using the `.well-known/openid-configuration` suffix.
 func FetchConfig(ctx context.Context, issuer string) (Config, error) {
-	url := fmt.Sprintf("%s/.well-known/openid-configuration", issuer)
+	url := fmt.Sprintf("%s/.well-known/openid-configuration", strings.TrimSuffix(issuer, "/"))
 
 	var config Config
 	err := fetch.JSON(ctx, url, &config)
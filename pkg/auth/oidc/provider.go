@@ -0,0 +1,222 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	jose_utils "github.com/smart-core-os/sc-bos/internal/util/jose"
+	"github.com/smart-core-os/sc-bos/pkg/auth/jwks"
+	"github.com/smart-core-os/sc-bos/pkg/auth/token"
+)
+
+// DefaultSignatureAlgorithms are the signature algorithms accepted by a ProviderValidator if none are configured.
+var DefaultSignatureAlgorithms = []string{
+	string(jose.RS256),
+	string(jose.RS384),
+	string(jose.RS512),
+	string(jose.ES256),
+	string(jose.ES384),
+	string(jose.ES512),
+	string(jose.PS256),
+	string(jose.PS384),
+	string(jose.PS512),
+}
+
+// discoveryRetryInterval is how long a ProviderValidator waits before retrying failed discovery.
+const discoveryRetryInterval = 30 * time.Second
+
+// ProviderConfig configures validation of access tokens issued by an OpenID Connect provider,
+// for example Entra ID (Azure AD), Okta, or Keycloak.
+type ProviderConfig struct {
+	// Issuer is the issuer URL of the provider, it must match the iss claim of tokens exactly.
+	// Provider metadata, including signing keys, is discovered via {Issuer}/.well-known/openid-configuration.
+	Issuer string `json:"issuer,omitempty"`
+	// Audience must be one of the aud claims of the token, so tokens the provider issued to other applications are rejected.
+	// This is typically the client id, or application id uri, of sc-bos as registered with the provider.
+	Audience string `json:"audience,omitempty"`
+	// SignatureAlgorithms token signatures may use, defaults to DefaultSignatureAlgorithms.
+	SignatureAlgorithms []string `json:"signatureAlgorithms,omitempty"`
+	// Claims configures how token claims are converted into the user's identity and roles.
+	Claims ClaimMapping `json:"claims,omitempty"`
+}
+
+// ClaimMapping describes how claims in tokens issued by a provider map to token.Claims.
+type ClaimMapping struct {
+	// NameClaim is the claim holding the user's display name.
+	// Defaults to "name", falling back to "preferred_username" then "email".
+	NameClaim string `json:"nameClaim,omitempty"`
+	// GroupsClaim is the claim listing the groups or roles the user belongs to, defaults to "groups".
+	// Nested claims are separated by '.', for example Keycloak realm roles are found at "realm_access.roles".
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// GroupsAsRoles uses the user's groups as system roles directly, in addition to any mapped by Roles.
+	// Useful when the provider is configured to issue sc-bos role names, like Entra ID app roles.
+	GroupsAsRoles bool `json:"groupsAsRoles,omitempty"`
+	// GroupMapping converts the user's groups into system roles and zone permissions.
+	token.GroupMapping
+}
+
+// ProviderValidator is a token.Validator for access tokens issued by an OpenID Connect provider.
+// Provider metadata is discovered when the first token is validated.
+type ProviderValidator struct {
+	cfg  ProviderConfig
+	algs []jose.SignatureAlgorithm
+	now  func() time.Time
+
+	mu          sync.Mutex
+	keySet      jwks.KeySet
+	lastErr     error
+	nextAttempt time.Time
+}
+
+// NewProviderValidator returns a token.Validator that validates tokens issued by the provider described by cfg.
+func NewProviderValidator(cfg ProviderConfig) *ProviderValidator {
+	algs := cfg.SignatureAlgorithms
+	if len(algs) == 0 {
+		algs = DefaultSignatureAlgorithms
+	}
+	return &ProviderValidator{
+		cfg:  cfg,
+		algs: jose_utils.ConvertToNativeJose(algs),
+		now:  time.Now,
+	}
+}
+
+func (v *ProviderValidator) ValidateAccessToken(ctx context.Context, tokenStr string) (*token.Claims, error) {
+	if v.cfg.Audience == "" {
+		return nil, errors.New("no audience configured")
+	}
+	keySet, err := v.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payloadBytes, err := keySet.VerifySignature(ctx, tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var registered jwt.Claims
+	if err := json.Unmarshal(payloadBytes, &registered); err != nil {
+		return nil, err
+	}
+	expected := jwt.Expected{Issuer: v.cfg.Issuer, AnyAudience: jwt.Audience{v.cfg.Audience}, Time: v.now()}
+	if err := registered.Validate(expected); err != nil {
+		return nil, err
+	}
+	if registered.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return nil, err
+	}
+	return v.cfg.Claims.claims(registered.Subject, payload), nil
+}
+
+// discover returns the key set of the provider, fetching the provider metadata if needed.
+// Failed attempts are remembered for a while so that an unavailable provider doesn't slow down every request.
+func (v *ProviderValidator) discover(ctx context.Context) (jwks.KeySet, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.keySet != nil {
+		return v.keySet, nil
+	}
+	if now := v.now(); now.Before(v.nextAttempt) {
+		return nil, v.lastErr
+	}
+
+	keySet, err := v.fetchKeySet(ctx)
+	if err != nil {
+		v.lastErr = err
+		v.nextAttempt = v.now().Add(discoveryRetryInterval)
+		return nil, err
+	}
+	v.keySet = keySet
+	v.lastErr = nil
+	return keySet, nil
+}
+
+func (v *ProviderValidator) fetchKeySet(ctx context.Context) (jwks.KeySet, error) {
+	cfg, err := FetchConfig(ctx, v.cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc fetch: %w", err)
+	}
+	// OpenID Connect Discovery 1.0 section 4.3
+	if cfg.Issuer != v.cfg.Issuer {
+		return nil, fmt.Errorf("oidc fetch: issuer %q does not match configured issuer %q", cfg.Issuer, v.cfg.Issuer)
+	}
+	if cfg.JWKSURI == "" {
+		return nil, errors.New("oidc fetch: provider has no jwks_uri")
+	}
+	// the key set outlives the request that triggered discovery
+	return jwks.NewRemoteKeySet(context.Background(), cfg.JWKSURI, v.algs), nil
+}
+
+func (m ClaimMapping) claims(subject string, payload map[string]any) *token.Claims {
+	groupsClaim := m.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+	groups := stringsClaim(payload, groupsClaim)
+	roles, permissions := m.Apply(groups)
+	if m.GroupsAsRoles {
+		roles = append(roles, groups...)
+	}
+
+	var name string
+	if m.NameClaim != "" {
+		name, _ = claimValue(payload, m.NameClaim).(string)
+	} else {
+		for _, c := range []string{"name", "preferred_username", "email"} {
+			if name, _ = payload[c].(string); name != "" {
+				break
+			}
+		}
+	}
+
+	return &token.Claims{
+		Subject:     subject,
+		Name:        name,
+		SystemRoles: roles,
+		Permissions: permissions,
+	}
+}
+
+// claimValue returns the value of the claim at path, where nested claims are separated by '.'.
+func claimValue(payload map[string]any, path string) any {
+	var v any = payload
+	for _, seg := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = obj[seg]
+	}
+	return v
+}
+
+// stringsClaim returns the claim at path as a list of strings.
+// A single string value is treated as a list of one, non-string list items are ignored.
+func stringsClaim(payload map[string]any, path string) []string {
+	switch v := claimValue(payload, path).(type) {
+	case string:
+		return []string{v}
+	case []any:
+		res := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/smart-core-os/sc-bos/pkg/auth/token"
+)
+
+func TestProviderValidator(t *testing.T) {
+	provider := newMockProvider(t)
+	now := time.Unix(1_700_000_000, 0)
+
+	cfg := ProviderConfig{
+		Issuer:   provider.issuer,
+		Audience: "sc-bos",
+		Claims: ClaimMapping{
+			GroupsClaim: "realm_access.roles",
+			GroupMapping: token.GroupMapping{
+				Roles: map[string][]string{"BMS-Admins": {"admin"}, "bms-operators": {"operator"}},
+				Zones: map[string][]string{"bms-operators": {"building/floor-1"}},
+			},
+		},
+	}
+	v := NewProviderValidator(cfg)
+	v.now = func() time.Time { return now }
+
+	claims := map[string]any{
+		"iss":                provider.issuer,
+		"sub":                "user-1",
+		"aud":                []string{"other", "sc-bos"},
+		"exp":                now.Add(time.Hour).Unix(),
+		"iat":                now.Unix(),
+		"preferred_username": "alice",
+		"realm_access":       map[string]any{"roles": []string{"bms-admins", "BMS-Operators", "unmapped"}},
+	}
+	got, err := v.ValidateAccessToken(t.Context(), provider.sign(t, claims))
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	want := &token.Claims{
+		Subject:     "user-1",
+		Name:        "alice",
+		SystemRoles: []string{"admin", "operator"},
+		Permissions: []token.PermissionAssignment{token.ZonePermission("building/floor-1")},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("claims (-want,+got)\n%s", diff)
+	}
+
+	invalid := map[string]map[string]any{
+		"wrong issuer":   {"iss": "https://other.example.com"},
+		"wrong audience": {"aud": "other"},
+		"no audience":    {"aud": nil},
+		"expired":        {"exp": now.Add(-time.Hour).Unix()},
+		"no subject":     {"sub": ""},
+	}
+	for name, changes := range invalid {
+		t.Run(name, func(t *testing.T) {
+			c := make(map[string]any)
+			for k, v := range claims {
+				c[k] = v
+			}
+			for k, v := range changes {
+				c[k] = v
+			}
+			if _, err := v.ValidateAccessToken(t.Context(), provider.sign(t, c)); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+
+	t.Run("no audience configured", func(t *testing.T) {
+		cfg := cfg
+		cfg.Audience = ""
+		v := NewProviderValidator(cfg)
+		v.now = func() time.Time { return now }
+		if _, err := v.ValidateAccessToken(t.Context(), provider.sign(t, claims)); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		other := newMockProvider(t)
+		if _, err := v.ValidateAccessToken(t.Context(), other.sign(t, claims)); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
+
+func TestProviderValidator_discoveryFailure(t *testing.T) {
+	provider := newMockProvider(t)
+	provider.fail.Store(true)
+	now := time.Unix(1_700_000_000, 0)
+	v := NewProviderValidator(ProviderConfig{Issuer: provider.issuer, Audience: "sc-bos", Claims: ClaimMapping{GroupsAsRoles: true}})
+	v.now = func() time.Time { return now }
+
+	tokenStr := provider.sign(t, map[string]any{
+		"iss":    provider.issuer,
+		"sub":    "user-1",
+		"aud":    "sc-bos",
+		"exp":    now.Add(time.Hour).Unix(),
+		"groups": "admin",
+	})
+	if _, err := v.ValidateAccessToken(t.Context(), tokenStr); err == nil {
+		t.Fatalf("expected error")
+	}
+	if got := provider.discoveryCount.Load(); got != 1 {
+		t.Fatalf("discovery requests got %d, want 1", got)
+	}
+
+	// failures are cached for a while
+	provider.fail.Store(false)
+	if _, err := v.ValidateAccessToken(t.Context(), tokenStr); err == nil {
+		t.Fatalf("expected cached error")
+	}
+	if got := provider.discoveryCount.Load(); got != 1 {
+		t.Fatalf("discovery requests got %d, want 1", got)
+	}
+
+	now = now.Add(discoveryRetryInterval)
+	got, err := v.ValidateAccessToken(t.Context(), tokenStr)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	if diff := cmp.Diff([]string{"admin"}, got.SystemRoles, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("roles (-want,+got)\n%s", diff)
+	}
+}
+
+// mockProvider is a minimal OpenID Connect provider serving discovery and signing keys.
+type mockProvider struct {
+	issuer         string
+	key            jose.JSONWebKey
+	fail           atomic.Bool
+	discoveryCount atomic.Int32
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &mockProvider{
+		key: jose.JSONWebKey{Key: rsaKey, KeyID: "test-key", Algorithm: string(jose.RS256), Use: "sig"},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		p.discoveryCount.Add(1)
+		if p.fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Config{Issuer: p.issuer, JWKSURI: p.issuer + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{p.key.Public()}})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	p.issuer = server.URL
+	return p
+}
+
+func (p *mockProvider) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: p.key}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenStr, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokenStr
+}
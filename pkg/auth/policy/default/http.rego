@@ -17,8 +17,16 @@ allow {
   startswith(input.path, "/__/debug/pprof/")
 }
 
+allow {
+  metrics_permission
+  input.path == "/metrics"
+}
+
 log_level_permission { token_has_role("admin") }
 log_level_permission { token_has_role("super-admin") }
 
 pprof_permission { token_has_role("admin") }
 pprof_permission { token_has_role("super-admin") }
+
+metrics_permission { token_has_role("admin") }
+metrics_permission { token_has_role("super-admin") }
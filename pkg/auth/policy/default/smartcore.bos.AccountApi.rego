@@ -0,0 +1,15 @@
+package smartcore.bos.AccountApi
+
+import future.keywords.in
+
+import data.scutil.token.valid_claims
+
+# Users can manage the second factor of their own account.
+# Resetting MFA is left to administrators, otherwise a stolen session could remove the second factor.
+allow {
+  input.method in {"BeginTotpEnrollment", "ConfirmTotpEnrollment", "RegenerateRecoveryCodes"}
+  claims := valid_claims
+  not claims.is_service
+  claims.subject != ""
+  input.request.id == claims.subject
+}
@@ -0,0 +1,10 @@
+package smartcore.bos.AuditApi
+
+import data.scutil.token.token_has_role
+
+default allow := false # the audit log records who did what, restrict who can see it
+
+allow {token_has_role("admin")}
+allow {token_has_role("super-admin")}
+# certificate based access is unrestricted, this may change in future
+allow {input.certificate_valid}
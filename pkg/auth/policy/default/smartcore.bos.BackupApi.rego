@@ -0,0 +1,11 @@
+package smartcore.bos.BackupApi
+
+import data.scutil.token.token_has_role
+
+default allow := false # backups contain all the node's data
+
+# admin based access is unrestricted
+allow {token_has_role("admin")}
+allow {token_has_role("super-admin")}
+# certificate based access is unrestricted, this may change in future
+allow {input.certificate_valid}
@@ -0,0 +1,25 @@
+package smartcore.bos.ConfigHistoryApi
+
+import data.scutil.token.token_has_role
+import data.scutil.rpc.verb_match
+
+default allow := false # rolling back config can change any service, restrict it like the ServicesApi
+
+# admin based access is unrestricted
+allow {token_has_role("admin")}
+allow {token_has_role("super-admin")}
+# certificate based access is unrestricted, this may change in future
+allow {input.certificate_valid}
+
+# Commissioners can do anything with services, including rolling back their config
+allow {token_has_role("commissioner")}
+
+# Operators and viewers can see what changed, but not roll it back
+allow {
+  token_has_role("operator")
+  verb_match({"Get", "List", "Diff"})
+}
+allow {
+  token_has_role("viewer")
+  verb_match({"Get", "List", "Diff"})
+}
@@ -0,0 +1,14 @@
+package smartcore.bos.SecretStoreApi
+
+import data.scutil.token.token_has_role
+
+default allow := false # secrets are sensitive, even though their values can't be read
+
+# admin based access is unrestricted
+allow {token_has_role("admin")}
+allow {token_has_role("super-admin")}
+# certificate based access is unrestricted, this may change in future
+allow {input.certificate_valid}
+
+# Commissioners configure drivers and automations, which need secrets
+allow {token_has_role("commissioner")}
@@ -0,0 +1,21 @@
+package smartcore.bos.SessionApi
+
+import data.scutil.token.token_has_role
+import data.scutil.token.valid_claims
+
+default allow := false # sessions reveal who is logged in and from where, restrict who can see them
+
+allow {token_has_role("admin")}
+allow {token_has_role("super-admin")}
+# certificate based access is unrestricted, this may change in future
+allow {input.certificate_valid}
+
+# Users can see their own sessions.
+# Users end their own sessions using the token revocation endpoint.
+allow {
+  input.method == "ListSessions"
+  claims := valid_claims
+  not claims.is_service
+  claims.subject != ""
+  input.request.account_id == claims.subject
+}
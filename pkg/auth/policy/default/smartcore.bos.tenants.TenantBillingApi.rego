@@ -0,0 +1,14 @@
+package smartcore.bos.tenants.TenantBillingApi
+
+import future.keywords.in
+
+import data.scutil.token.valid_claims
+
+# Tenants can read their own statements, using a token issued for their tenant secret.
+allow {
+  input.method in {"ListStatements", "GetStatement"}
+  claims := valid_claims
+  claims.is_service
+  claims.subject != ""
+  input.request.tenant_id == claims.subject
+}
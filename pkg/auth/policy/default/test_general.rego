@@ -102,3 +102,94 @@ test_zone_mismatch {
   not data.smartcore.allow with input as tenant_request("smartcore.traits.LightApi", "GetBrightness", {"name": "zone/2"}, ["zone/1"])
   not data.grpc_default.allow with input as tenant_request("smartcore.traits.LightApi", "GetBrightness", {"name": "zone/2"}, ["zone/1"])
 }
+
+account_request(method, request, subject) := input {
+  input := {
+    "service": "smartcore.bos.AccountApi",
+    "method": method,
+    "stream": {"is_server_stream": false, "is_client_stream": false, "open": false},
+    "request": request,
+    "certificate_present": false,
+    "certificate_valid": false,
+    "certificate": null,
+    "token_present": true,
+    "token_valid": true,
+    "token_claims": {
+      "subject": subject,
+      "system_roles": [],
+      "is_service": false
+    }
+  }
+}
+
+test_own_account_mfa {
+  data.smartcore.bos.AccountApi.allow with input as account_request("BeginTotpEnrollment", {"id": "1"}, "1")
+  data.smartcore.bos.AccountApi.allow with input as account_request("ConfirmTotpEnrollment", {"id": "1"}, "1")
+  not data.smartcore.bos.AccountApi.allow with input as account_request("ResetAccountMfa", {"id": "1"}, "1")
+  not data.smartcore.bos.AccountApi.allow with input as account_request("UnlockAccount", {"id": "1"}, "1")
+}
+test_other_account_mfa {
+  not data.smartcore.bos.AccountApi.allow with input as account_request("BeginTotpEnrollment", {"id": "2"}, "1")
+  not data.smartcore.bos.AccountApi.allow with input as account_request("RegenerateRecoveryCodes", {"id": "2"}, "1")
+}
+
+session_request(method, request, subject, roles) := input {
+  input := {
+    "service": "smartcore.bos.SessionApi",
+    "method": method,
+    "stream": {"is_server_stream": false, "is_client_stream": false, "open": false},
+    "request": request,
+    "certificate_present": false,
+    "certificate_valid": false,
+    "certificate": null,
+    "token_present": true,
+    "token_valid": true,
+    "token_claims": {
+      "subject": subject,
+      "system_roles": roles,
+      "is_service": false
+    }
+  }
+}
+
+test_own_sessions {
+  data.smartcore.bos.SessionApi.allow with input as session_request("ListSessions", {"account_id": "1"}, "1", [])
+  not data.smartcore.bos.SessionApi.allow with input as session_request("ListSessions", {}, "1", [])
+  not data.smartcore.bos.SessionApi.allow with input as session_request("ListSessions", {"account_id": "2"}, "1", [])
+  not data.smartcore.bos.SessionApi.allow with input as session_request("RevokeAccountSessions", {"account_id": "1"}, "1", [])
+}
+test_admin_sessions {
+  data.smartcore.bos.SessionApi.allow with input as session_request("ListSessions", {}, "1", ["admin"])
+  data.smartcore.bos.SessionApi.allow with input as session_request("RevokeSession", {"id": "5"}, "1", ["admin"])
+  not data.smartcore.bos.SessionApi.allow with input as session_request("RevokeSession", {"id": "5"}, "1", ["operator"])
+}
+
+billing_request(method, request, subject) := input {
+  input := {
+    "service": "smartcore.bos.tenants.TenantBillingApi",
+    "method": method,
+    "stream": {"is_server_stream": false, "is_client_stream": false, "open": false},
+    "request": request,
+    "certificate_present": false,
+    "certificate_valid": false,
+    "certificate": null,
+    "token_present": true,
+    "token_valid": true,
+    "token_claims": {
+      "subject": subject,
+      "system_roles": null,
+      "is_service": true,
+      "permissions": []
+    }
+  }
+}
+
+test_tenant_own_statements {
+  data.smartcore.bos.tenants.TenantBillingApi.allow with input as billing_request("ListStatements", {"tenant_id": "t1"}, "t1")
+  data.smartcore.bos.tenants.TenantBillingApi.allow with input as billing_request("GetStatement", {"tenant_id": "t1", "id": "2024-02-t1"}, "t1")
+}
+test_tenant_other_statements {
+  not data.smartcore.bos.tenants.TenantBillingApi.allow with input as billing_request("ListStatements", {"tenant_id": "t2"}, "t1")
+  not data.smartcore.bos.tenants.TenantBillingApi.allow with input as billing_request("ListStatements", {}, "t1")
+  not data.smartcore.allow with input as billing_request("ListStatements", {}, "t1")
+}
@@ -9,8 +9,10 @@ import (
 )
 
 type Claims struct {
-	SystemRoles []string               `json:"system_roles"` // The built-in system roles that this token is authorized for
-	IsService   bool                   `json:"is_service"`   // True if the subject is an application acting on its own behalf, false if it's a user
+	Subject     string                 `json:"subject,omitempty"` // The identity the token was issued to, typically an account or client id
+	Name        string                 `json:"name,omitempty"`    // A human readable name for the subject, if known
+	SystemRoles []string               `json:"system_roles"`      // The built-in system roles that this token is authorized for
+	IsService   bool                   `json:"is_service"`        // True if the subject is an application acting on its own behalf, false if it's a user
 	Permissions []PermissionAssignment `json:"permissions"`
 }
 
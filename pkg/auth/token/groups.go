@@ -0,0 +1,59 @@
+package token
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/smart-core-os/sc-bos/internal/auth/permission"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// GroupMapping converts the groups a user belongs to in an external identity provider into system roles and permissions.
+// Group names are compared case-insensitively.
+type GroupMapping struct {
+	// Roles maps group names to the system roles given to members of that group.
+	Roles map[string][]string `json:"roles,omitempty"`
+	// Zones maps group names to zones that members of that group can control.
+	// Zone access grants write access to all names beginning with the zone name.
+	Zones map[string][]string `json:"zones,omitempty"`
+}
+
+// Apply returns the system roles and permissions for a member of groups.
+// Groups that have no mapping are ignored.
+func (m GroupMapping) Apply(groups []string) (roles []string, permissions []PermissionAssignment) {
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	member := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		member[strings.ToLower(g)] = true
+	}
+	for g, rs := range m.Roles {
+		if member[strings.ToLower(g)] {
+			roles = append(roles, rs...)
+		}
+	}
+	var zones []string
+	for g, zs := range m.Zones {
+		if member[strings.ToLower(g)] {
+			zones = append(zones, zs...)
+		}
+	}
+	slices.Sort(roles)
+	roles = slices.Compact(roles)
+	slices.Sort(zones)
+	for _, z := range slices.Compact(zones) {
+		permissions = append(permissions, ZonePermission(z))
+	}
+	return roles, permissions
+}
+
+// ZonePermission returns a PermissionAssignment that grants write access to names beginning with the given zone prefix.
+func ZonePermission(zone string) PermissionAssignment {
+	return PermissionAssignment{
+		Permission:   permission.TraitWrite,
+		Scoped:       true,
+		ResourceType: ResourceType(gen.RoleAssignment_NAMED_RESOURCE_PATH_PREFIX),
+		Resource:     zone,
+	}
+}
@@ -2,8 +2,10 @@ package allautos
 
 import (
 	"github.com/smart-core-os/sc-bos/pkg/auto"
+	"github.com/smart-core-os/sc-bos/pkg/auto/anomaly"
 	"github.com/smart-core-os/sc-bos/pkg/auto/azureiot"
 	"github.com/smart-core-os/sc-bos/pkg/auto/bms"
+	"github.com/smart-core-os/sc-bos/pkg/auto/demandresponse"
 	"github.com/smart-core-os/sc-bos/pkg/auto/export"
 	"github.com/smart-core-os/sc-bos/pkg/auto/exporthttp"
 	"github.com/smart-core-os/sc-bos/pkg/auto/healthbounds"
@@ -15,6 +17,8 @@ import (
 	"github.com/smart-core-os/sc-bos/pkg/auto/resetbrightness"
 	"github.com/smart-core-os/sc-bos/pkg/auto/resetenterleave"
 	"github.com/smart-core-os/sc-bos/pkg/auto/sccexporter"
+	"github.com/smart-core-os/sc-bos/pkg/auto/sensorfault"
+	"github.com/smart-core-os/sc-bos/pkg/auto/servicetickets"
 	"github.com/smart-core-os/sc-bos/pkg/auto/statusalerts"
 	"github.com/smart-core-os/sc-bos/pkg/auto/statusemail"
 	"github.com/smart-core-os/sc-bos/pkg/auto/udmi"
@@ -23,8 +27,10 @@ import (
 // Factories returns a new map containing all known auto factories.
 func Factories() map[string]auto.Factory {
 	return map[string]auto.Factory{
+		anomaly.AutoName:            anomaly.Factory,
 		azureiot.FactoryName:        azureiot.Factory,
 		bms.AutoType:                bms.Factory,
+		demandresponse.AutoName:     demandresponse.Factory,
 		"export-mqtt":               export.MQTTFactory,
 		healthbounds.AutoName:       healthbounds.Factory,
 		"history":                   history.Factory,
@@ -35,6 +41,8 @@ func Factories() map[string]auto.Factory {
 		resetbrightness.AutoName:    resetbrightness.Factory,
 		resetenterleave.AutoName:    resetenterleave.Factory,
 		sccexporter.AutoName:        sccexporter.Factory,
+		sensorfault.AutoName:        sensorfault.Factory,
+		servicetickets.AutoName:     servicetickets.Factory,
 		statusalerts.AutoName:       statusalerts.Factory,
 		statusemail.AutoName:        statusemail.Factory,
 		udmi.AutoType:               udmi.Factory,
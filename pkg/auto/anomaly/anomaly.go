@@ -0,0 +1,104 @@
+// Package anomaly provides an automation that detects unusual values of devices, like energy use out of hours or a
+// fridge probe drifting. A baseline, the median and median absolute deviation of each hour of the week, is learnt for
+// each device from its history and saved so it survives restarts. A health check for each device compares live
+// values to the baseline for the current hour of the week, becoming abnormal when they deviate too far.
+package anomaly
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto"
+	"github.com/smart-core-os/sc-bos/pkg/auto/anomaly/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/task"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+)
+
+const AutoName = "anomaly"
+
+var Factory auto.Factory = factory{}
+
+type factory struct{}
+
+func (f factory) New(services auto.Services) service.Lifecycle {
+	a := &autoImpl{Services: services}
+	a.Service = service.New(service.MonoApply(a.applyConfig), service.WithParser(config.ReadBytes))
+	a.Logger = a.Logger.Named(AutoName)
+	return a
+}
+
+type autoImpl struct {
+	*service.Service[config.Root]
+	auto.Services
+}
+
+func (a *autoImpl) applyConfig(ctx context.Context, cfg config.Root) error {
+	devicesMask, err := fieldmaskpb.New(&gen.Device{}, "name")
+	if err != nil {
+		return err
+	}
+	now := a.Now
+	if now == nil {
+		now = time.Now
+	}
+	st := &store{scope: cfg.Name, db: a.Database}
+	logger := a.Logger.With(zap.String("metric", cfg.Metric))
+
+	go func() {
+		running := make(map[string]context.CancelFunc)
+		defer func() {
+			for _, stop := range running {
+				stop()
+			}
+		}()
+		// the task is configured to retry forever (until ctx is done) so the error is ignored.
+		_ = task.Run(ctx, func(ctx context.Context) (task.Next, error) {
+			stream, err := a.Devices.PullDevices(ctx, &gen.PullDevicesRequest{
+				ReadMask: devicesMask,
+				Query:    &gen.Device_Query{Conditions: cfg.DevicesPb()},
+			})
+			if err != nil {
+				return task.Normal, err
+			}
+			for {
+				res, err := stream.Recv()
+				if err != nil {
+					return task.ResetBackoff, err
+				}
+				for _, change := range res.GetChanges() {
+					ov, nv := change.GetOldValue(), change.GetNewValue()
+					switch {
+					case ov == nil && nv != nil:
+						if _, ok := running[change.GetName()]; ok {
+							continue
+						}
+						dctx, stop := context.WithCancel(ctx)
+						running[change.GetName()] = stop
+						d := &detector{
+							cfg:    cfg,
+							metric: metrics[cfg.Metric],
+							device: change.GetName(),
+							conn:   a.Node.ClientConn(),
+							checks: a.Health,
+							store:  st,
+							logger: logger.With(zap.String("device", change.GetName())),
+							now:    now,
+							loc:    time.Local,
+						}
+						go d.run(dctx)
+					case ov != nil && nv == nil:
+						if stop, ok := running[change.GetName()]; ok {
+							stop()
+							delete(running, change.GetName())
+						}
+					}
+				}
+			}
+		}, task.WithRetry(task.RetryUnlimited), task.WithBackoff(100*time.Millisecond, time.Minute))
+	}()
+	return nil
+}
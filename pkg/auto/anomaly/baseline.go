@@ -0,0 +1,130 @@
+package anomaly
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/util/timeutil"
+)
+
+// madScale scales the median absolute deviation so it estimates the standard deviation of normally distributed values.
+const madScale = 1.4826
+
+// point is a value at a time.
+type point struct {
+	at    time.Time
+	value float64
+}
+
+// stats summarise a set of values.
+type stats struct {
+	Median  float64
+	MAD     float64 // median absolute deviation from Median
+	Samples int
+}
+
+func newStats(values []float64) stats {
+	if len(values) == 0 {
+		return stats{}
+	}
+	m := median(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - m)
+	}
+	return stats{Median: m, MAD: median(deviations), Samples: len(values)}
+}
+
+// median returns the median of values, reordering them.
+func median(values []float64) float64 {
+	slices.Sort(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// baseline is the normal behaviour of a device, learnt from its history.
+// It is saved so it survives restarts.
+type baseline struct {
+	Scope  string
+	Device string
+	Metric string
+	// TrainedAt is when the baseline was learnt.
+	TrainedAt time.Time
+	// HistoryStart is the time of the oldest value the baseline was learnt from.
+	HistoryStart time.Time
+	// Hours are the stats of each hour of the week, Monday 00:00 first, in the controller time zone.
+	Hours [7 * 24]stats
+	// All are the stats of all hours, used for hours with too few samples.
+	All stats
+}
+
+// train learns a baseline from points, which must be in order.
+func train(points []point, loc *time.Location) baseline {
+	var b baseline
+	if len(points) == 0 {
+		return b
+	}
+	b.HistoryStart = points[0].at
+	var buckets [7 * 24][]float64
+	all := make([]float64, len(points))
+	for i, p := range points {
+		h := timeutil.HourOfWeek(p.at.In(loc))
+		buckets[h] = append(buckets[h], p.value)
+		all[i] = p.value
+	}
+	for i, values := range buckets {
+		b.Hours[i] = newStats(values)
+	}
+	b.All = newStats(all)
+	return b
+}
+
+// ready returns whether the baseline was learnt from at least minHistory of history.
+func (b *baseline) ready(minHistory time.Duration) bool {
+	return b.All.Samples > 0 && b.TrainedAt.Sub(b.HistoryStart) >= minHistory
+}
+
+// normalRange returns the range of values that are normal at t.
+// Values further than sensitivity scaled median absolute deviations, or minDeviation if larger, from the median are
+// abnormal. Hours with fewer than minSamples samples use the stats of all hours.
+func (b *baseline) normalRange(t time.Time, loc *time.Location, sensitivity, minDeviation float64, minSamples int) (low, high float64) {
+	s := b.Hours[timeutil.HourOfWeek(t.In(loc))]
+	if s.Samples < minSamples {
+		s = b.All
+	}
+	d := math.Max(sensitivity*madScale*s.MAD, minDeviation)
+	return s.Median - d, s.Median + d
+}
+
+// hourlyIncreases returns how much counter readings increased in each whole hour between start and end.
+// Each point is at the start of its hour. The reading at a time is the last at or before it, hours without a reading
+// at both ends, or where the counter went down, are skipped.
+func hourlyIncreases(readings []point, start, end time.Time) []point {
+	var res []point
+	i := 0
+	var last point
+	valueAt := func(t time.Time) (float64, bool) {
+		for i < len(readings) && !readings[i].at.After(t) {
+			last = readings[i]
+			i++
+		}
+		return last.value, !last.at.IsZero()
+	}
+	from := start.Truncate(time.Hour)
+	if from.Before(start) {
+		from = from.Add(time.Hour)
+	}
+	prev, ok := valueAt(from)
+	for t := from.Add(time.Hour); !t.After(end); t = t.Add(time.Hour) {
+		v, vok := valueAt(t)
+		if ok && vok && v >= prev {
+			res = append(res, point{at: t.Add(-time.Hour), value: v - prev})
+		}
+		prev, ok = v, vok
+	}
+	return res
+}
@@ -0,0 +1,100 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewStats(t *testing.T) {
+	got := newStats([]float64{1, 2, 3, 4, 100})
+	want := stats{Median: 3, MAD: 1, Samples: 5}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	got = newStats([]float64{4, 1, 3, 2})
+	want = stats{Median: 2.5, MAD: 1, Samples: 4}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestBaseline_normalRange(t *testing.T) {
+	// Monday 1st January 2024
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var points []point
+	for week := 0; week < 4; week++ {
+		for day := 0; day < 7; day++ {
+			for hour := 0; hour < 24; hour++ {
+				v := 1.0 // out of hours
+				if hour >= 8 && hour < 18 {
+					v = 10 + float64(week) // working hours, varying a little
+				}
+				points = append(points, point{at: monday.AddDate(0, 0, week*7+day).Add(time.Duration(hour) * time.Hour), value: v})
+			}
+		}
+	}
+	// Sunday at 3am only has one unusual sample
+	sunday3am := 6*24 + 3
+	points[sunday3am].value = 50
+	for week := 3; week > 0; week-- {
+		i := week*7*24 + sunday3am
+		points = append(points[:i], points[i+1:]...)
+	}
+	b := train(points, time.UTC)
+	b.TrainedAt = monday.AddDate(0, 0, 28)
+
+	if !b.ready(21 * 24 * time.Hour) {
+		t.Error("expected baseline to be ready with 21 days of history")
+	}
+	if b.ready(29 * 24 * time.Hour) {
+		t.Error("expected baseline not to be ready with 29 days of history")
+	}
+
+	tests := []struct {
+		name            string
+		at              time.Time
+		wantLow, wantHi float64
+	}{
+		// median 11.5, mad 1
+		{name: "working hours", at: monday.Add(9 * time.Hour), wantLow: 11.5 - 3*madScale, wantHi: 11.5 + 3*madScale},
+		// no deviation out of hours, so minDeviation applies
+		{name: "out of hours", at: monday.Add(20 * time.Hour), wantLow: 0.5, wantHi: 1.5},
+		// too few samples, all hours are used: mostly out of hours values so median 1, mad 0
+		{name: "sparse hour", at: monday.AddDate(0, 0, 6).Add(3 * time.Hour), wantLow: 0.5, wantHi: 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high := b.normalRange(tt.at, time.UTC, 3, 0.5, 2)
+			if diff := cmp.Diff([]float64{tt.wantLow, tt.wantHi}, []float64{low, high}); diff != "" {
+				t.Errorf("normalRange (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHourlyIncreases(t *testing.T) {
+	at := func(h, m int) time.Time {
+		return time.Date(2024, 1, 1, h, m, 0, 0, time.UTC)
+	}
+	readings := []point{
+		{at(0, 50), 100},
+		{at(1, 30), 110},
+		{at(2, 0), 115},
+		// no readings between 2:00 and 4:00, the counter didn't change
+		{at(4, 10), 130},
+		{at(5, 0), 5}, // reset, so 4:00 to 5:00 is skipped
+		{at(6, 0), 8},
+	}
+	got := hourlyIncreases(readings, at(0, 30), at(6, 0))
+	want := []point{
+		{at(1, 0), 15},
+		{at(2, 0), 0},
+		{at(3, 0), 0},
+		{at(5, 0), 3},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(point{})); diff != "" {
+		t.Errorf("hourlyIncreases (-want +got):\n%s", diff)
+	}
+}
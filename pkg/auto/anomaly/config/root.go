@@ -0,0 +1,167 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	DefaultSensitivity     = 3.5
+	DefaultTrainingWindow  = 28 * 24 * time.Hour
+	DefaultMinHistory      = 7 * 24 * time.Hour
+	DefaultMinSamples      = 3
+	DefaultRetrainInterval = 24 * time.Hour
+)
+
+// Metrics lists the names of metrics that can be analysed.
+var Metrics = []string{
+	"meter.usage",
+	"electric.realPower",
+	"airTemperature.ambientTemperature",
+	"airQuality.carbonDioxideLevel",
+}
+
+type Root struct {
+	auto.Config
+	// Devices selects the devices to analyse, the query matches those in the DevicesApi.
+	Devices []*Condition `json:"devices"`
+	// Metric is the value analysed for each device, one of Metrics.
+	// Devices must have history recorded for the metric, for example by the history auto.
+	Metric string `json:"metric"`
+	// Check configures the metadata of the health check created for each device, like displayName.
+	Check *HealthCheck `json:"check,omitempty"`
+
+	// Sensitivity is how many scaled median absolute deviations from the median a value can be before it is abnormal.
+	// Lower values are more sensitive. Defaults to 3.5.
+	Sensitivity float64 `json:"sensitivity,omitempty"`
+	// MinDeviation is the smallest difference from the median, in the unit of the metric, that is abnormal.
+	// Use this to avoid alerting on tiny deviations when values barely vary.
+	MinDeviation float64 `json:"minDeviation,omitempty"`
+	// Deadband is how far, in the unit of the metric, a value must return within the normal range to be normal again.
+	Deadband float64 `json:"deadband,omitempty"`
+
+	// TrainingWindow is how much history baselines are learnt from, defaults to 28 days.
+	TrainingWindow *jsontypes.Duration `json:"trainingWindow,omitempty"`
+	// MinHistory is how much history a device needs before it is analysed, defaults to 7 days.
+	MinHistory *jsontypes.Duration `json:"minHistory,omitempty"`
+	// MinSamples is how many samples an hour of the week needs for its own baseline, defaults to 3.
+	// Hours with fewer samples use a baseline learnt from all hours.
+	MinSamples int `json:"minSamples,omitempty"`
+	// RetrainInterval is how often baselines are learnt again, defaults to 24 hours.
+	RetrainInterval *jsontypes.Duration `json:"retrainInterval,omitempty"`
+}
+
+func ReadBytes(data []byte) (cfg Root, err error) {
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return
+	}
+	if cfg.Sensitivity == 0 {
+		cfg.Sensitivity = DefaultSensitivity
+	}
+	if cfg.MinSamples == 0 {
+		cfg.MinSamples = DefaultMinSamples
+	}
+	return cfg, cfg.validate()
+}
+
+func (r Root) validate() error {
+	if r.Metric == "" {
+		return errors.New("metric is required")
+	}
+	if !slices.Contains(Metrics, r.Metric) {
+		return fmt.Errorf("metric %q is not supported, use one of %v", r.Metric, Metrics)
+	}
+	if r.Sensitivity <= 0 {
+		return errors.New("sensitivity must be positive")
+	}
+	if r.MinDeviation < 0 {
+		return errors.New("minDeviation must not be negative")
+	}
+	if r.Deadband < 0 {
+		return errors.New("deadband must not be negative")
+	}
+	if r.MinSamples < 1 {
+		return errors.New("minSamples must be positive")
+	}
+	if r.MinHistoryOrDefault() > r.TrainingWindowOrDefault() {
+		return errors.New("minHistory must not be longer than trainingWindow")
+	}
+	if r.RetrainIntervalOrDefault() < time.Hour {
+		return errors.New("retrainInterval must be at least 1h")
+	}
+	return nil
+}
+
+func (r Root) TrainingWindowOrDefault() time.Duration {
+	return r.TrainingWindow.Or(DefaultTrainingWindow)
+}
+
+func (r Root) MinHistoryOrDefault() time.Duration {
+	return r.MinHistory.Or(DefaultMinHistory)
+}
+
+func (r Root) RetrainIntervalOrDefault() time.Duration {
+	return r.RetrainInterval.Or(DefaultRetrainInterval)
+}
+
+func (r Root) DevicesPb() []*gen.Device_Query_Condition {
+	conds := make([]*gen.Device_Query_Condition, len(r.Devices))
+	for i, c := range r.Devices {
+		conds[i] = c.pb
+	}
+	return conds
+}
+
+// CheckPb returns the configured health check, or an empty check if none is configured.
+func (r Root) CheckPb() *gen.HealthCheck {
+	if r.Check == nil {
+		return &gen.HealthCheck{}
+	}
+	return r.Check.pb
+}
+
+type Condition struct {
+	pb *gen.Device_Query_Condition
+}
+
+func (c *Condition) UnmarshalJSON(bytes []byte) error {
+	cond := &gen.Device_Query_Condition{}
+	err := protojson.Unmarshal(bytes, cond)
+	if err != nil {
+		return fmt.Errorf("condition: %w", err)
+	}
+	*c = Condition{cond}
+	return nil
+}
+
+func (c *Condition) MarshalJSON() ([]byte, error) {
+	return protojson.Marshal(c.pb)
+}
+
+type HealthCheck struct {
+	pb *gen.HealthCheck
+}
+
+func (h *HealthCheck) UnmarshalJSON(bytes []byte) error {
+	hc := &gen.HealthCheck{}
+	err := protojson.Unmarshal(bytes, hc)
+	if err != nil {
+		return fmt.Errorf("health check: %w", err)
+	}
+	*h = HealthCheck{hc}
+	return nil
+}
+
+func (h *HealthCheck) MarshalJSON() ([]byte, error) {
+	return protojson.Marshal(h.pb)
+}
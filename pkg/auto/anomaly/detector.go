@@ -0,0 +1,243 @@
+package anomaly
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto/anomaly/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/healthpb"
+	"github.com/smart-core-os/sc-bos/pkg/util/pull"
+)
+
+const (
+	// counterDelay is how long after the end of each hour counters are checked, so the last readings are recorded.
+	counterDelay = time.Minute
+	// counterLookback is how far before each hour counter readings are read, to find the reading at the start of it.
+	counterLookback = 24 * time.Hour
+	// retryDelay is how long to wait before learning a baseline again after failing.
+	retryDelay = 10 * time.Minute
+	// unitTimeout limits how long finding the display unit of a device can take.
+	unitTimeout = 10 * time.Second
+)
+
+var errNoReadings = errors.New("no readings recorded during the hour")
+
+// detector learns the baseline of a device and keeps a health check of how its values compare to it.
+type detector struct {
+	cfg    config.Root
+	metric metric
+	device string
+	conn   grpc.ClientConnInterface
+	checks *healthpb.Checks
+	store  *store
+	logger *zap.Logger
+	now    func() time.Time
+	loc    *time.Location
+
+	base  *baseline
+	check *healthpb.BoundsCheck // nil until the baseline is ready
+	unit  string
+}
+
+func (d *detector) run(ctx context.Context) {
+	defer func() {
+		if d.check != nil {
+			d.check.Dispose()
+		}
+	}()
+
+	base, err := d.store.load(d.device, d.cfg.Metric)
+	if err != nil {
+		d.logger.Warn("failed to load baseline", zap.Error(err))
+	}
+	retrain := time.NewTimer(0)
+	defer retrain.Stop()
+	if base != nil {
+		d.base = base
+		d.ensureCheck(ctx)
+		if wait := base.TrainedAt.Add(d.cfg.RetrainIntervalOrDefault()).Sub(d.now()); wait > 0 {
+			retrain.Reset(wait)
+		}
+	}
+
+	var samples chan sample
+	if d.metric.live != nil {
+		samples = make(chan sample)
+		go func() {
+			// only returns when ctx is done
+			_ = pull.Changes(ctx, d.metric.live(d.conn, d.device), samples, pull.WithLogger(d.logger))
+		}()
+	}
+
+	hourly := time.NewTimer(d.untilNextHour())
+	defer hourly.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-retrain.C:
+			if err := d.train(ctx); err != nil {
+				d.logger.Warn("failed to learn baseline", zap.Error(err))
+				retrain.Reset(retryDelay)
+				continue
+			}
+			retrain.Reset(d.cfg.RetrainIntervalOrDefault())
+		case s := <-samples:
+			if d.check == nil {
+				continue
+			}
+			if s.err != nil {
+				d.check.UpdateReliability(ctx, healthpb.ReliabilityFromErr(s.err))
+				continue
+			}
+			d.check.UpdateValue(ctx, healthpb.FloatValue(s.value))
+		case <-hourly.C:
+			hourly.Reset(d.untilNextHour())
+			if d.check == nil {
+				continue
+			}
+			if d.metric.counter {
+				d.checkLastHour(ctx)
+			} else {
+				d.updateBounds(ctx, d.now())
+			}
+		}
+	}
+}
+
+// train learns the baseline of the device from its history.
+func (d *detector) train(ctx context.Context) error {
+	now := d.now()
+	start := now.Add(-d.cfg.TrainingWindowOrDefault())
+	points, err := d.readHistory(ctx, start, now)
+	if err != nil {
+		return err
+	}
+	base := train(points, d.loc)
+	base.Device = d.device
+	base.Metric = d.cfg.Metric
+	base.TrainedAt = now
+	if err := d.store.save(&base); err != nil {
+		d.logger.Warn("failed to save baseline", zap.Error(err))
+	}
+	d.base = &base
+	if !base.ready(d.cfg.MinHistoryOrDefault()) {
+		d.logger.Debug("not enough history to learn a baseline", zap.Int("samples", base.All.Samples),
+			zap.Time("historyStart", base.HistoryStart))
+		return nil
+	}
+	d.logger.Debug("learnt baseline", zap.Int("samples", base.All.Samples),
+		zap.Float64("median", base.All.Median), zap.Float64("mad", base.All.MAD))
+	if d.check == nil {
+		d.ensureCheck(ctx)
+	} else {
+		d.updateBounds(ctx, d.boundsTime(now))
+	}
+	return nil
+}
+
+// readHistory returns the values of the device between start and end, for counters their hourly increase.
+func (d *detector) readHistory(ctx context.Context, start, end time.Time) ([]point, error) {
+	var points []point
+	from := start
+	if d.metric.counter {
+		from = start.Add(-counterLookback)
+	}
+	err := d.metric.history(ctx, d.conn, d.device, from, end, func(at time.Time, v float64) {
+		points = append(points, point{at: at, value: v})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if d.metric.counter {
+		points = hourlyIncreases(points, start, end)
+	}
+	return points, nil
+}
+
+// ensureCheck creates the health check for the device if the baseline is ready.
+func (d *detector) ensureCheck(ctx context.Context) {
+	if d.check != nil || !d.base.ready(d.cfg.MinHistoryOrDefault()) {
+		return
+	}
+	c := proto.Clone(d.cfg.CheckPb()).(*gen.HealthCheck)
+	c.DisplayName = cmp.Or(c.DisplayName, "Unusual "+d.cfg.Metric)
+	c.Description = cmp.Or(c.Description, "Compares "+d.cfg.Metric+" to its normal value for the time of week, learnt from history")
+	unitCtx, cancel := context.WithTimeout(ctx, unitTimeout)
+	d.unit = d.metric.unit(unitCtx, d.conn, d.device)
+	cancel()
+	c.Check = &gen.HealthCheck_Bounds_{Bounds: &gen.HealthCheck_Bounds{
+		Expected:    d.expected(d.boundsTime(d.now())),
+		DisplayUnit: d.unit,
+	}}
+	check, err := d.checks.NewBoundsCheck(d.device, c)
+	if err != nil {
+		d.logger.Error("failed to create health check", zap.Error(err))
+		return
+	}
+	d.check = check
+	if d.metric.counter {
+		d.checkLastHour(ctx)
+	}
+}
+
+// checkLastHour compares the increase of a counter during the last whole hour to its baseline.
+func (d *detector) checkLastHour(ctx context.Context) {
+	end := d.now().Truncate(time.Hour)
+	start := end.Add(-time.Hour)
+	points, err := d.readHistory(ctx, start, end)
+	if err == nil && len(points) == 0 {
+		err = errNoReadings
+	}
+	if err != nil {
+		d.check.UpdateReliability(ctx, healthpb.ReliabilityFromErr(err))
+		return
+	}
+	d.updateBounds(ctx, start)
+	d.check.UpdateValue(ctx, healthpb.FloatValue(points[0].value))
+}
+
+// updateBounds sets the normal range of the check to the baseline at t.
+func (d *detector) updateBounds(ctx context.Context, t time.Time) {
+	b := &gen.HealthCheck_Bounds{Expected: d.expected(t), DisplayUnit: d.unit}
+	if err := d.check.UpdateBounds(ctx, b); err != nil {
+		d.logger.Warn("failed to update health check bounds", zap.Error(err))
+	}
+}
+
+func (d *detector) expected(t time.Time) *gen.HealthCheck_Bounds_NormalRange {
+	low, high := d.base.normalRange(t, d.loc, d.cfg.Sensitivity, d.cfg.MinDeviation, d.cfg.MinSamples)
+	r := &gen.HealthCheck_ValueRange{
+		Low:  healthpb.FloatValue(low),
+		High: healthpb.FloatValue(high),
+	}
+	if d.cfg.Deadband > 0 {
+		r.Deadband = healthpb.FloatValue(d.cfg.Deadband)
+	}
+	return &gen.HealthCheck_Bounds_NormalRange{NormalRange: r}
+}
+
+// boundsTime returns the time whose baseline values are currently compared to.
+// Counters are compared an hour at a time, after the hour has finished.
+func (d *detector) boundsTime(now time.Time) time.Time {
+	if d.metric.counter {
+		return now.Truncate(time.Hour).Add(-time.Hour)
+	}
+	return now
+}
+
+func (d *detector) untilNextHour() time.Duration {
+	now := d.now()
+	next := now.Truncate(time.Hour).Add(time.Hour)
+	if d.metric.counter {
+		next = next.Add(counterDelay)
+	}
+	return next.Sub(now)
+}
@@ -0,0 +1,271 @@
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread"
+	"github.com/smart-core-os/sc-bos/pkg/util/pull"
+)
+
+var errNoValue = errors.New("value not present")
+
+// metric describes how to read a value of a device, both from history and live.
+type metric struct {
+	// counter metrics only ever increase, like meter readings, their hourly increase is analysed.
+	// Other metrics are analysed as they are.
+	counter bool
+	// history calls fn for each recorded value of the device between start and end, oldest first.
+	history func(ctx context.Context, conn grpc.ClientConnInterface, name string, start, end time.Time, fn func(at time.Time, v float64)) error
+	// live fetches the current value of the device, nil for counter metrics.
+	live func(conn grpc.ClientConnInterface, name string) pull.Fetcher[sample]
+	// unit returns the display unit of values of the device.
+	unit func(ctx context.Context, conn grpc.ClientConnInterface, name string) string
+}
+
+// sample is a live value of a device, or the error reading it.
+type sample struct {
+	value float64
+	err   error
+}
+
+// metrics are the metrics that can be analysed, keyed by the names in config.Metrics.
+var metrics = map[string]metric{
+	"meter.usage": {
+		counter: true,
+		history: func(ctx context.Context, conn grpc.ClientConnInterface, name string, start, end time.Time, fn func(at time.Time, v float64)) error {
+			client := gen.NewMeterHistoryClient(conn)
+			req := &gen.ListMeterReadingHistoryRequest{Name: name, Period: period(start, end), PageSize: historyread.PageSize}
+			for {
+				res, err := client.ListMeterReadingHistory(ctx, req)
+				if err != nil {
+					return err
+				}
+				for _, r := range res.MeterReadingRecords {
+					fn(r.GetRecordTime().AsTime(), float64(r.GetMeterReading().GetUsage()))
+				}
+				if res.NextPageToken == "" {
+					return nil
+				}
+				req.PageToken = res.NextPageToken
+			}
+		},
+		unit: func(ctx context.Context, conn grpc.ClientConnInterface, name string) string {
+			info, err := gen.NewMeterInfoClient(conn).DescribeMeterReading(ctx, &gen.DescribeMeterReadingRequest{Name: name})
+			if err != nil {
+				return ""
+			}
+			return info.GetUsageUnit()
+		},
+	},
+	"electric.realPower": {
+		history: func(ctx context.Context, conn grpc.ClientConnInterface, name string, start, end time.Time, fn func(at time.Time, v float64)) error {
+			client := gen.NewElectricHistoryClient(conn)
+			req := &gen.ListElectricDemandHistoryRequest{Name: name, Period: period(start, end), PageSize: historyread.PageSize}
+			for {
+				res, err := client.ListElectricDemandHistory(ctx, req)
+				if err != nil {
+					return err
+				}
+				for _, r := range res.ElectricDemandRecords {
+					if p := r.GetElectricDemand().RealPower; p != nil {
+						fn(r.GetRecordTime().AsTime(), float64(*p))
+					}
+				}
+				if res.NextPageToken == "" {
+					return nil
+				}
+				req.PageToken = res.NextPageToken
+			}
+		},
+		live: func(conn grpc.ClientConnInterface, name string) pull.Fetcher[sample] {
+			client := traits.NewElectricApiClient(conn)
+			toSample := func(d *traits.ElectricDemand) sample {
+				if d.RealPower == nil {
+					return sample{err: errNoValue}
+				}
+				return sample{value: float64(*d.RealPower)}
+			}
+			return liveFetcher(
+				func(ctx context.Context, changes chan<- sample) error {
+					stream, err := client.PullDemand(ctx, &traits.PullDemandRequest{Name: name})
+					if err != nil {
+						return err
+					}
+					for {
+						res, err := stream.Recv()
+						if err != nil {
+							return err
+						}
+						for _, change := range res.Changes {
+							send(ctx, changes, toSample(change.Demand))
+						}
+					}
+				},
+				func(ctx context.Context) (sample, error) {
+					res, err := client.GetDemand(ctx, &traits.GetDemandRequest{Name: name})
+					if err != nil {
+						return sample{}, err
+					}
+					return toSample(res), nil
+				},
+			)
+		},
+		unit: staticUnit("W"),
+	},
+	"airTemperature.ambientTemperature": {
+		history: func(ctx context.Context, conn grpc.ClientConnInterface, name string, start, end time.Time, fn func(at time.Time, v float64)) error {
+			client := gen.NewAirTemperatureHistoryClient(conn)
+			req := &gen.ListAirTemperatureHistoryRequest{Name: name, Period: period(start, end), PageSize: historyread.PageSize}
+			for {
+				res, err := client.ListAirTemperatureHistory(ctx, req)
+				if err != nil {
+					return err
+				}
+				for _, r := range res.AirTemperatureRecords {
+					if t := r.GetAirTemperature().GetAmbientTemperature(); t != nil {
+						fn(r.GetRecordTime().AsTime(), t.ValueCelsius)
+					}
+				}
+				if res.NextPageToken == "" {
+					return nil
+				}
+				req.PageToken = res.NextPageToken
+			}
+		},
+		live: func(conn grpc.ClientConnInterface, name string) pull.Fetcher[sample] {
+			client := traits.NewAirTemperatureApiClient(conn)
+			toSample := func(t *traits.AirTemperature) sample {
+				if t.AmbientTemperature == nil {
+					return sample{err: errNoValue}
+				}
+				return sample{value: t.AmbientTemperature.ValueCelsius}
+			}
+			return liveFetcher(
+				func(ctx context.Context, changes chan<- sample) error {
+					stream, err := client.PullAirTemperature(ctx, &traits.PullAirTemperatureRequest{Name: name})
+					if err != nil {
+						return err
+					}
+					for {
+						res, err := stream.Recv()
+						if err != nil {
+							return err
+						}
+						for _, change := range res.Changes {
+							send(ctx, changes, toSample(change.AirTemperature))
+						}
+					}
+				},
+				func(ctx context.Context) (sample, error) {
+					res, err := client.GetAirTemperature(ctx, &traits.GetAirTemperatureRequest{Name: name})
+					if err != nil {
+						return sample{}, err
+					}
+					return toSample(res), nil
+				},
+			)
+		},
+		unit: staticUnit("°C"),
+	},
+	"airQuality.carbonDioxideLevel": {
+		history: func(ctx context.Context, conn grpc.ClientConnInterface, name string, start, end time.Time, fn func(at time.Time, v float64)) error {
+			client := gen.NewAirQualitySensorHistoryClient(conn)
+			req := &gen.ListAirQualityHistoryRequest{Name: name, Period: period(start, end), PageSize: historyread.PageSize}
+			for {
+				res, err := client.ListAirQualityHistory(ctx, req)
+				if err != nil {
+					return err
+				}
+				for _, r := range res.AirQualityRecords {
+					if co2 := r.GetAirQuality().CarbonDioxideLevel; co2 != nil {
+						fn(r.GetRecordTime().AsTime(), float64(*co2))
+					}
+				}
+				if res.NextPageToken == "" {
+					return nil
+				}
+				req.PageToken = res.NextPageToken
+			}
+		},
+		live: func(conn grpc.ClientConnInterface, name string) pull.Fetcher[sample] {
+			client := traits.NewAirQualitySensorApiClient(conn)
+			toSample := func(q *traits.AirQuality) sample {
+				if q.CarbonDioxideLevel == nil {
+					return sample{err: errNoValue}
+				}
+				return sample{value: float64(*q.CarbonDioxideLevel)}
+			}
+			return liveFetcher(
+				func(ctx context.Context, changes chan<- sample) error {
+					stream, err := client.PullAirQuality(ctx, &traits.PullAirQualityRequest{Name: name})
+					if err != nil {
+						return err
+					}
+					for {
+						res, err := stream.Recv()
+						if err != nil {
+							return err
+						}
+						for _, change := range res.Changes {
+							send(ctx, changes, toSample(change.AirQuality))
+						}
+					}
+				},
+				func(ctx context.Context) (sample, error) {
+					res, err := client.GetAirQuality(ctx, &traits.GetAirQualityRequest{Name: name})
+					if err != nil {
+						return sample{}, err
+					}
+					return toSample(res), nil
+				},
+			)
+		},
+		unit: staticUnit("ppm"),
+	},
+}
+
+// liveFetcher returns a fetcher using pullFn and getFn, which also sends errors so they can be reported.
+func liveFetcher(pullFn func(ctx context.Context, changes chan<- sample) error, getFn func(ctx context.Context) (sample, error)) pull.Fetcher[sample] {
+	return pull.NewFetcher(
+		func(ctx context.Context, changes chan<- sample) error {
+			err := pullFn(ctx, changes)
+			if err != nil {
+				send(ctx, changes, sample{err: err})
+			}
+			return err
+		},
+		func(ctx context.Context, changes chan<- sample) error {
+			s, err := getFn(ctx)
+			if err != nil {
+				s = sample{err: err}
+			}
+			send(ctx, changes, s)
+			return err
+		},
+	)
+}
+
+func send(ctx context.Context, changes chan<- sample, s sample) {
+	select {
+	case <-ctx.Done():
+	case changes <- s:
+	}
+}
+
+func staticUnit(unit string) func(context.Context, grpc.ClientConnInterface, string) string {
+	return func(context.Context, grpc.ClientConnInterface, string) string {
+		return unit
+	}
+}
+
+func period(start, end time.Time) *timepb.Period {
+	return &timepb.Period{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)}
+}
@@ -0,0 +1,45 @@
+package anomaly
+
+import (
+	"errors"
+
+	"github.com/timshannon/bolthold"
+)
+
+// store saves baselines so they survive restarts.
+type store struct {
+	scope string
+	db    *bolthold.Store // may be nil, baselines are then learnt again each time the automation starts
+}
+
+// load returns the saved baseline of metric for device, or nil if there isn't one.
+func (s *store) load(device, metric string) (*baseline, error) {
+	if s.db == nil {
+		return nil, nil
+	}
+	b := &baseline{}
+	err := s.db.Get(s.key(device), b)
+	if errors.Is(err, bolthold.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b.Metric != metric {
+		// the config changed, the baseline is for a different metric
+		return nil, nil
+	}
+	return b, nil
+}
+
+func (s *store) save(b *baseline) error {
+	if s.db == nil {
+		return nil
+	}
+	b.Scope = s.scope
+	return s.db.Upsert(s.key(b.Device), b)
+}
+
+func (s *store) key(device string) string {
+	return s.scope + "/baseline/" + device
+}
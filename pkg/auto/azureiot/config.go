@@ -17,10 +17,11 @@ const (
 )
 
 type Config struct {
-	// Azure IoT Hub dps credentials, used for devices where DeviceConfig.ConnectionString == ""
-	GroupKey     string `json:"groupKey"`
-	GroupKeyFile string `json:"groupKeyFile"`
-	IDScope      string `json:"idScope"`
+	// Azure IoT Hub dps credentials, used for devices where DeviceConfig.ConnectionString == "".
+	// GroupKey is given directly, or as a reference to a secret like {"$secret": "azureiot/group"}.
+	GroupKey     jsontypes.Secret `json:"groupKey"`
+	GroupKeyFile string           `json:"groupKeyFile"`
+	IDScope      string           `json:"idScope"`
 
 	Devices []DeviceConfig `json:"devices"`
 
@@ -39,9 +40,9 @@ type DeviceConfig struct {
 	// todo: DiscoverDevices bool // if true, discover devices from Smart Core and use them as the Children list
 
 	// Azure IoT Hub connection details, one of these must be provided
-	RegistrationID       string `json:"registrationID,omitempty"`       // Device Provisioning Service registration ID - usually becomes the device name in the cloud
-	ConnectionString     string `json:"connectionString,omitempty"`     // Use a connection string to directly connect to IoT Hub, bypassing Device Provisioning Service
-	ConnectionStringFile string `json:"connectionStringFile,omitempty"` // Filesystem path to a file containing the connection string
+	RegistrationID       string           `json:"registrationID,omitempty"`       // Device Provisioning Service registration ID - usually becomes the device name in the cloud
+	ConnectionString     jsontypes.Secret `json:"connectionString,omitempty"`     // Use a connection string to directly connect to IoT Hub, bypassing Device Provisioning Service, can be a reference to a secret
+	ConnectionStringFile string           `json:"connectionStringFile,omitempty"` // Filesystem path to a file containing the connection string
 }
 
 // UsesConnectionString returns if the device will connect directly using a Connection String, bypassing DPS.
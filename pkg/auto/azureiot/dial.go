@@ -17,7 +17,10 @@ type dialler interface {
 
 func diallerFromConfig(devCfg DeviceConfig, idScope string, grpKey auth.SASKey) (dialler, error) {
 	if devCfg.UsesConnectionString() {
-		connectionString := devCfg.ConnectionString
+		connectionString, err := devCfg.ConnectionString.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read connection string for device %q: %w", devCfg.Name, err)
+		}
 		if devCfg.ConnectionStringFile != "" {
 			contents, err := os.ReadFile(devCfg.ConnectionStringFile)
 			if err != nil {
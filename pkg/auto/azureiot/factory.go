@@ -161,7 +161,11 @@ func (a *Auto) sendOutputMessages(ctx context.Context, dialler dialler, stream <
 
 func loadGroupKey(cfg Config) (auth.SASKey, error) {
 	if cfg.GroupKey != "" {
-		return auth.ParseSASKey(cfg.GroupKey)
+		key, err := cfg.GroupKey.Read()
+		if err != nil {
+			return nil, err
+		}
+		return auth.ParseSASKey(key)
 	}
 
 	raw, err := os.ReadFile(cfg.GroupKeyFile)
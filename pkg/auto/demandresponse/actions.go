@@ -0,0 +1,252 @@
+package demandresponse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/timshannon/bolthold"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-api/go/types"
+	"github.com/smart-core-os/sc-bos/pkg/auto/demandresponse/config"
+)
+
+// savedState is the state of a device before it was shed, so it can be restored.
+type savedState struct {
+	Scope  string
+	Device string
+	Type   config.ActionType
+	State  []byte // a proto message, depending on Type
+}
+
+// shedder changes devices to reduce demand, remembering their original state so they can be restored.
+type shedder struct {
+	conn  grpc.ClientConnInterface
+	scope string
+	db    *bolthold.Store // may be nil, saved states are then only kept in memory
+
+	mu    sync.Mutex
+	saved map[string]*savedState
+}
+
+func newShedder(conn grpc.ClientConnInterface, scope string, db *bolthold.Store) *shedder {
+	return &shedder{conn: conn, scope: scope, db: db, saved: make(map[string]*savedState)}
+}
+
+// load returns states saved by a previous run, devices that were shed when the automation stopped.
+func (s *shedder) load() ([]*savedState, error) {
+	if s.db == nil {
+		return nil, nil
+	}
+	var states []*savedState
+	if err := s.db.Find(&states, bolthold.Where("Scope").Eq(s.scope)); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range states {
+		s.saved[s.key(st.Device, st.Type)] = st
+	}
+	return states, nil
+}
+
+// shed applies a to its device, returning a description of the change.
+func (s *shedder) shed(ctx context.Context, a config.Action) (string, error) {
+	if _, ok := s.savedState(a.Name, a.Type); ok {
+		return "already shed", nil
+	}
+	var desc string
+	var err error
+	switch a.Type {
+	case config.ActionDim:
+		desc, err = s.dim(ctx, a)
+	case config.ActionWidenDeadband:
+		desc, err = s.widenDeadband(ctx, a)
+	case config.ActionOff:
+		desc, err = s.off(ctx, a)
+	default:
+		return "", fmt.Errorf("unsupported action %q", a.Type)
+	}
+	if err != nil {
+		// the device wasn't changed, so there's nothing to restore and the next shed should try again
+		_ = s.forget(a.Name, a.Type)
+	}
+	return desc, err
+}
+
+// restore returns the device changed by a shed action of type t to its original state.
+func (s *shedder) restore(ctx context.Context, device string, t config.ActionType) (string, error) {
+	st, ok := s.savedState(device, t)
+	if !ok {
+		return "not shed", nil
+	}
+	var desc string
+	var err error
+	switch t {
+	case config.ActionDim:
+		state := &traits.Brightness{}
+		if err := proto.Unmarshal(st.State, state); err != nil {
+			return "", err
+		}
+		_, err = traits.NewLightApiClient(s.conn).UpdateBrightness(ctx, &traits.UpdateBrightnessRequest{
+			Name:       device,
+			Brightness: &traits.Brightness{LevelPercent: state.LevelPercent},
+		})
+		desc = fmt.Sprintf("brightness -> %.0f%%", state.LevelPercent)
+	case config.ActionWidenDeadband:
+		state := &traits.AirTemperature{}
+		if err := proto.Unmarshal(st.State, state); err != nil {
+			return "", err
+		}
+		_, err = traits.NewAirTemperatureApiClient(s.conn).UpdateAirTemperature(ctx, &traits.UpdateAirTemperatureRequest{
+			Name:       device,
+			State:      state,
+			UpdateMask: goalMask(state),
+		})
+		desc = "set points -> " + describeGoal(state)
+	case config.ActionOff:
+		state := &traits.OnOff{}
+		if err := proto.Unmarshal(st.State, state); err != nil {
+			return "", err
+		}
+		_, err = traits.NewOnOffApiClient(s.conn).UpdateOnOff(ctx, &traits.UpdateOnOffRequest{Name: device, OnOff: state})
+		desc = "-> " + state.State.String()
+	default:
+		return "", fmt.Errorf("unsupported action %q", t)
+	}
+	if err != nil {
+		return desc, err
+	}
+	return desc, s.forget(device, t)
+}
+
+func (s *shedder) dim(ctx context.Context, a config.Action) (string, error) {
+	client := traits.NewLightApiClient(s.conn)
+	current, err := client.GetBrightness(ctx, &traits.GetBrightnessRequest{Name: a.Name})
+	if err != nil {
+		return "", err
+	}
+	level := current.LevelPercent * float32(1-a.Percent/100)
+	if err := s.save(a.Name, a.Type, &traits.Brightness{LevelPercent: current.LevelPercent}); err != nil {
+		return "", err
+	}
+	desc := fmt.Sprintf("brightness %.0f%% -> %.0f%%", current.LevelPercent, level)
+	_, err = client.UpdateBrightness(ctx, &traits.UpdateBrightnessRequest{Name: a.Name, Brightness: &traits.Brightness{LevelPercent: level}})
+	return desc, err
+}
+
+func (s *shedder) widenDeadband(ctx context.Context, a config.Action) (string, error) {
+	client := traits.NewAirTemperatureApiClient(s.conn)
+	current, err := client.GetAirTemperature(ctx, &traits.GetAirTemperatureRequest{Name: a.Name})
+	if err != nil {
+		return "", err
+	}
+	orig := &traits.AirTemperature{TemperatureGoal: current.TemperatureGoal}
+	shed := &traits.AirTemperature{}
+	switch goal := current.TemperatureGoal.(type) {
+	case *traits.AirTemperature_TemperatureRange:
+		r := proto.Clone(goal.TemperatureRange).(*traits.TemperatureRange)
+		r.Low = &types.Temperature{ValueCelsius: r.GetLow().GetValueCelsius() - a.Widen}
+		r.High = &types.Temperature{ValueCelsius: r.GetHigh().GetValueCelsius() + a.Widen}
+		shed.TemperatureGoal = &traits.AirTemperature_TemperatureRange{TemperatureRange: r}
+	case *traits.AirTemperature_TemperatureSetPoint:
+		v := goal.TemperatureSetPoint.GetValueCelsius()
+		switch current.Mode {
+		case traits.AirTemperature_COOL:
+			v += a.Widen
+		case traits.AirTemperature_HEAT:
+			v -= a.Widen
+		default:
+			return "", fmt.Errorf("can't widen the deadband of a single set point in mode %s", current.Mode)
+		}
+		shed.TemperatureGoal = &traits.AirTemperature_TemperatureSetPoint{TemperatureSetPoint: &types.Temperature{ValueCelsius: v}}
+	default:
+		return "", errors.New("device has no set point or range")
+	}
+	if err := s.save(a.Name, a.Type, orig); err != nil {
+		return "", err
+	}
+	desc := fmt.Sprintf("set points %s -> %s", describeGoal(orig), describeGoal(shed))
+	_, err = client.UpdateAirTemperature(ctx, &traits.UpdateAirTemperatureRequest{Name: a.Name, State: shed, UpdateMask: goalMask(shed)})
+	return desc, err
+}
+
+func (s *shedder) off(ctx context.Context, a config.Action) (string, error) {
+	client := traits.NewOnOffApiClient(s.conn)
+	current, err := client.GetOnOff(ctx, &traits.GetOnOffRequest{Name: a.Name})
+	if err != nil {
+		return "", err
+	}
+	if err := s.save(a.Name, a.Type, &traits.OnOff{State: current.State}); err != nil {
+		return "", err
+	}
+	desc := fmt.Sprintf("%s -> %s", current.State, traits.OnOff_OFF)
+	_, err = client.UpdateOnOff(ctx, &traits.UpdateOnOffRequest{Name: a.Name, OnOff: &traits.OnOff{State: traits.OnOff_OFF}})
+	return desc, err
+}
+
+func (s *shedder) savedState(device string, t config.ActionType) (*savedState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.saved[s.key(device, t)]
+	return st, ok
+}
+
+func (s *shedder) save(device string, t config.ActionType, state proto.Message) error {
+	data, err := proto.Marshal(state)
+	if err != nil {
+		return err
+	}
+	st := &savedState{Scope: s.scope, Device: device, Type: t, State: data}
+	key := s.key(device, t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved[key] = st
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Upsert(key, st)
+}
+
+func (s *shedder) forget(device string, t config.ActionType) error {
+	key := s.key(device, t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.saved, key)
+	if s.db == nil {
+		return nil
+	}
+	err := s.db.Delete(key, savedState{})
+	if errors.Is(err, bolthold.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *shedder) key(device string, t config.ActionType) string {
+	return s.scope + "/shed/" + string(t) + "/" + device
+}
+
+func goalMask(state *traits.AirTemperature) *fieldmaskpb.FieldMask {
+	switch state.TemperatureGoal.(type) {
+	case *traits.AirTemperature_TemperatureRange:
+		return &fieldmaskpb.FieldMask{Paths: []string{"temperature_range"}}
+	default:
+		return &fieldmaskpb.FieldMask{Paths: []string{"temperature_set_point"}}
+	}
+}
+
+func describeGoal(state *traits.AirTemperature) string {
+	switch goal := state.TemperatureGoal.(type) {
+	case *traits.AirTemperature_TemperatureRange:
+		return fmt.Sprintf("%.1f-%.1f°C", goal.TemperatureRange.GetLow().GetValueCelsius(), goal.TemperatureRange.GetHigh().GetValueCelsius())
+	case *traits.AirTemperature_TemperatureSetPoint:
+		return fmt.Sprintf("%.1f°C", goal.TemperatureSetPoint.GetValueCelsius())
+	}
+	return "none"
+}
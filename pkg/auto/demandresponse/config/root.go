@@ -0,0 +1,153 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	DefaultHysteresis   = 0.05
+	DefaultShedDelay    = time.Minute
+	DefaultRestoreDelay = 5 * time.Minute
+	DefaultDimPercent   = 20
+	DefaultWiden        = 1
+)
+
+type ActionType string
+
+const (
+	// ActionDim reduces the brightness of a light by a percentage of its current level.
+	ActionDim ActionType = "dim"
+	// ActionWidenDeadband moves the set points of an AirTemperature device away from each other,
+	// or a single set point away from the direction the device is conditioning air.
+	ActionWidenDeadband ActionType = "widenDeadband"
+	// ActionOff turns off an OnOff device.
+	ActionOff ActionType = "off"
+)
+
+func ReadBytes(data []byte) (cfg Root, err error) {
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return
+	}
+	if cfg.DeviceName == "" {
+		cfg.DeviceName = cfg.Name
+	}
+	for i := range cfg.Stages {
+		for j := range cfg.Stages[i].Actions {
+			a := &cfg.Stages[i].Actions[j]
+			if a.Type == ActionDim && a.Percent == 0 {
+				a.Percent = DefaultDimPercent
+			}
+			if a.Type == ActionWidenDeadband && a.Widen == 0 {
+				a.Widen = DefaultWiden
+			}
+		}
+	}
+	return cfg, cfg.validate()
+}
+
+type Root struct {
+	auto.Config
+	// DeviceName is the Smart Core name the DemandResponseApi is announced as, defaults to the automation name.
+	DeviceName string `json:"deviceName,omitempty"`
+	// Meter is the name of an Electric device measuring site demand.
+	// The real power of the device is compared to the demand limit.
+	Meter string `json:"meter,omitempty"`
+	// DemandLimit is the site demand limit, in watts.
+	DemandLimit float64 `json:"demandLimit,omitempty"`
+	// Hysteresis is the fraction a stage threshold is reduced by before the stage is restored, defaults to 0.05.
+	// For example with a threshold of 100 kW and hysteresis of 0.05, demand must fall below 95 kW to restore.
+	Hysteresis *float64 `json:"hysteresis,omitempty"`
+	// ShedDelay is the minimum time between shedding stages, so each stage has a chance to reduce demand.
+	// Defaults to 1 minute.
+	ShedDelay *jsontypes.Duration `json:"shedDelay,omitempty"`
+	// RestoreDelay is how long demand must be below the restore threshold before a stage is restored.
+	// Defaults to 5 minutes.
+	RestoreDelay *jsontypes.Duration `json:"restoreDelay,omitempty"`
+	// Stages are shed in order, and restored in reverse order.
+	Stages []Stage `json:"stages,omitempty"`
+}
+
+type Stage struct {
+	Name string `json:"name,omitempty"`
+	// Threshold is the fraction of the demand limit above which the stage is shed, like 0.9 for 90%.
+	// Each stage threshold must be at least that of the previous stage.
+	Threshold float64  `json:"threshold,omitempty"`
+	Actions   []Action `json:"actions,omitempty"`
+}
+
+type Action struct {
+	// Name of the device to change.
+	Name string     `json:"name,omitempty"`
+	Type ActionType `json:"type,omitempty"`
+	// Percent is how much to dim by, as a percentage of the current brightness, defaults to 20.
+	Percent float64 `json:"percent,omitempty"`
+	// Widen is the number of degrees Celsius to move each set point, defaults to 1.
+	Widen float64 `json:"widen,omitempty"`
+}
+
+func (r Root) HysteresisOrDefault() float64 {
+	if r.Hysteresis == nil {
+		return DefaultHysteresis
+	}
+	return *r.Hysteresis
+}
+
+func (r Root) ShedDelayOrDefault() time.Duration {
+	return r.ShedDelay.Or(DefaultShedDelay)
+}
+
+func (r Root) RestoreDelayOrDefault() time.Duration {
+	return r.RestoreDelay.Or(DefaultRestoreDelay)
+}
+
+func (r Root) validate() error {
+	if r.Meter == "" {
+		return errors.New("meter is required")
+	}
+	if r.DemandLimit <= 0 {
+		return errors.New("demandLimit must be positive")
+	}
+	if h := r.HysteresisOrDefault(); h < 0 || h >= 1 {
+		return errors.New("hysteresis must be at least 0 and less than 1")
+	}
+	if len(r.Stages) == 0 {
+		return errors.New("stages is required")
+	}
+	for i, s := range r.Stages {
+		if s.Threshold <= 0 {
+			return fmt.Errorf("stages[%d].threshold must be positive", i)
+		}
+		if i > 0 && s.Threshold < r.Stages[i-1].Threshold {
+			return fmt.Errorf("stages[%d].threshold must be at least the threshold of the previous stage", i)
+		}
+		if len(s.Actions) == 0 {
+			return fmt.Errorf("stages[%d].actions is required", i)
+		}
+		for j, a := range s.Actions {
+			if a.Name == "" {
+				return fmt.Errorf("stages[%d].actions[%d].name is required", i, j)
+			}
+			switch a.Type {
+			case ActionDim:
+				if a.Percent <= 0 || a.Percent > 100 {
+					return fmt.Errorf("stages[%d].actions[%d].percent must be between 0 and 100", i, j)
+				}
+			case ActionWidenDeadband:
+				if a.Widen <= 0 {
+					return fmt.Errorf("stages[%d].actions[%d].widen must be positive", i, j)
+				}
+			case ActionOff:
+			default:
+				return fmt.Errorf("stages[%d].actions[%d].type %q is not supported", i, j, a.Type)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package demandresponse
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto/demandresponse/config"
+)
+
+// controller decides how many stages should be shed.
+// Stages are shed one at a time while demand is above the threshold of the next stage,
+// and restored one at a time once demand has been below the threshold of the current stage, less hysteresis,
+// for the restore delay.
+type controller struct {
+	stages       []config.Stage
+	hysteresis   float64
+	shedDelay    time.Duration
+	restoreDelay time.Duration
+
+	stage      int       // the number of stages currently shed
+	lastShed   time.Time // when a stage was last shed
+	belowSince time.Time // when demand fell below the restore threshold, zero if it isn't
+}
+
+func newController(cfg config.Root) *controller {
+	return &controller{
+		stages:       cfg.Stages,
+		hysteresis:   cfg.HysteresisOrDefault(),
+		shedDelay:    cfg.ShedDelayOrDefault(),
+		restoreDelay: cfg.RestoreDelayOrDefault(),
+	}
+}
+
+// reading is the input to the controller at a point in time.
+type reading struct {
+	now       time.Time
+	demand    float64 // in watts, ignored if !hasDemand
+	hasDemand bool
+	limit     float64 // in watts
+	minStage  int     // required by active events
+}
+
+// step returns the number of stages that should be shed, and why, and records it as the current stage.
+// The reason is empty if the stage doesn't change.
+func (c *controller) step(r reading) (int, string) {
+	minStage := min(r.minStage, len(c.stages))
+	target, reason := c.stage, ""
+	switch {
+	case !r.hasDemand:
+		// hold the current stage until we know the demand again
+		c.belowSince = time.Time{}
+	case c.stage < len(c.stages) && r.demand > c.stages[c.stage].Threshold*r.limit:
+		c.belowSince = time.Time{}
+		if r.now.Sub(c.lastShed) >= c.shedDelay {
+			target = c.stage + 1
+			reason = fmt.Sprintf("demand %s above %s", kW(r.demand), kW(c.stages[c.stage].Threshold*r.limit))
+		}
+	case c.stage > 0 && r.demand < c.restoreThreshold(c.stage, r.limit):
+		if c.belowSince.IsZero() {
+			c.belowSince = r.now
+		}
+		if c.stage > minStage && r.now.Sub(c.belowSince) >= c.restoreDelay {
+			target = c.stage - 1
+			reason = fmt.Sprintf("demand %s below %s for %s", kW(r.demand), kW(c.restoreThreshold(c.stage, r.limit)), c.restoreDelay)
+			// the next stage must wait for the full delay again
+			c.belowSince = r.now
+		}
+	default:
+		c.belowSince = time.Time{}
+	}
+	if target < minStage {
+		target = minStage
+		reason = fmt.Sprintf("demand response event requires stage %d", minStage)
+	}
+	if target > c.stage {
+		c.lastShed = r.now
+	}
+	c.stage = target
+	return target, reason
+}
+
+// restoreThreshold returns the demand below which stage can be restored.
+func (c *controller) restoreThreshold(stage int, limit float64) float64 {
+	return c.stages[stage-1].Threshold * limit * (1 - c.hysteresis)
+}
+
+// kW formats watts as kilowatts for humans.
+func kW(w float64) string {
+	return fmt.Sprintf("%.1f kW", w/1000)
+}
@@ -0,0 +1,142 @@
+package demandresponse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-api/go/types"
+	"github.com/smart-core-os/sc-bos/pkg/auto/demandresponse/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/resource"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+	"github.com/smart-core-os/sc-golang/pkg/trait/airtemperaturepb"
+	"github.com/smart-core-os/sc-golang/pkg/trait/lightpb"
+	"github.com/smart-core-os/sc-golang/pkg/trait/onoffpb"
+)
+
+func TestController_step(t *testing.T) {
+	cfg := config.Root{
+		Stages: []config.Stage{
+			{Name: "lights", Threshold: 0.9},
+			{Name: "hvac", Threshold: 1},
+		},
+	}
+	c := newController(cfg)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		after    time.Duration
+		demand   float64
+		minStage int
+		want     int
+	}{
+		{name: "below thresholds", after: 0, demand: 80_000, want: 0},
+		{name: "above stage 1", after: time.Second, demand: 95_000, want: 1},
+		{name: "above stage 2 too soon", after: 30 * time.Second, demand: 105_000, want: 1},
+		{name: "above stage 2", after: 2 * time.Minute, demand: 105_000, want: 2},
+		{name: "within hysteresis", after: 3 * time.Minute, demand: 97_000, want: 2},
+		{name: "below restore threshold", after: 4 * time.Minute, demand: 90_000, want: 2},
+		{name: "still below, not long enough", after: 8 * time.Minute, demand: 90_000, want: 2},
+		{name: "restore stage 2", after: 9 * time.Minute, demand: 90_000, want: 1},
+		{name: "restore waits again", after: 10 * time.Minute, demand: 80_000, want: 1},
+		{name: "restore stage 1", after: 14 * time.Minute, demand: 80_000, want: 0},
+		{name: "event requires stage 2", after: 15 * time.Minute, demand: 10_000, minStage: 2, want: 2},
+		{name: "event holds stage", after: 30 * time.Minute, demand: 10_000, minStage: 2, want: 2},
+		{name: "event ended", after: 31 * time.Minute, demand: 10_000, want: 2},
+		{name: "restore after event", after: 36 * time.Minute, demand: 10_000, want: 1},
+	}
+	for _, tt := range tests {
+		got, reason := c.step(reading{now: start.Add(tt.after), demand: tt.demand, hasDemand: true, limit: 100_000, minStage: tt.minStage})
+		if got != tt.want {
+			t.Fatalf("%s: got stage %d, want %d (%s)", tt.name, got, tt.want, reason)
+		}
+	}
+
+	// without demand the stage is held
+	got, _ := c.step(reading{now: start.Add(time.Hour), limit: 100_000})
+	if got != 1 {
+		t.Fatalf("no demand: got stage %d, want 1", got)
+	}
+}
+
+func TestRunner_transition(t *testing.T) {
+	n := node.New("test")
+	light := lightpb.NewModel(resource.WithInitialValue(&traits.Brightness{LevelPercent: 80}))
+	n.Announce("light", node.HasTrait(trait.Light, node.WithClients(lightpb.WrapApi(lightpb.NewModelServer(light)))))
+	fan := onoffpb.NewModel(resource.WithInitialValue(&traits.OnOff{State: traits.OnOff_ON}))
+	n.Announce("fan", node.HasTrait(trait.OnOff, node.WithClients(onoffpb.WrapApi(onoffpb.NewModelServer(fan)))))
+	ahu := airtemperaturepb.NewModel(airtemperaturepb.WithInitialAirTemperature(&traits.AirTemperature{
+		TemperatureGoal: &traits.AirTemperature_TemperatureRange{TemperatureRange: &traits.TemperatureRange{
+			Low:  &types.Temperature{ValueCelsius: 20},
+			High: &types.Temperature{ValueCelsius: 24},
+		}},
+	}))
+	n.Announce("ahu", node.HasTrait(trait.AirTemperature, node.WithClients(airtemperaturepb.WrapApi(airtemperaturepb.NewModelServer(ahu)))))
+
+	cfg := config.Root{
+		DemandLimit: 100_000,
+		Stages: []config.Stage{
+			{Name: "lights", Threshold: 0.9, Actions: []config.Action{{Name: "light", Type: config.ActionDim, Percent: 25}}},
+			{Name: "plant", Threshold: 1, Actions: []config.Action{
+				{Name: "ahu", Type: config.ActionWidenDeadband, Widen: 1},
+				{Name: "fan", Type: config.ActionOff},
+			}},
+		},
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := newRunner(cfg, n.ClientConn(), newStore("dr", nil), newShedder(n.ClientConn(), "dr", nil), zap.NewNop(), func() time.Time { return now })
+	ctx := context.Background()
+
+	r.transition(ctx, 2, "test")
+	if b, _ := light.GetBrightness(); b.LevelPercent != 60 {
+		t.Errorf("shed brightness got %v, want 60", b.LevelPercent)
+	}
+	if o, _ := fan.GetOnOff(); o.State != traits.OnOff_OFF {
+		t.Errorf("shed fan got %v, want OFF", o.State)
+	}
+	if a, _ := ahu.GetAirTemperature(); a.GetTemperatureRange().GetLow().GetValueCelsius() != 19 || a.GetTemperatureRange().GetHigh().GetValueCelsius() != 25 {
+		t.Errorf("shed ahu got %v, want 19-25", a.GetTemperatureRange())
+	}
+	if st := r.getStatus(); st.Stage != 2 || st.StageName != "plant" {
+		t.Errorf("status got stage %d %q, want 2 plant", st.Stage, st.StageName)
+	}
+
+	r.transition(ctx, 0, "test")
+	if b, _ := light.GetBrightness(); b.LevelPercent != 80 {
+		t.Errorf("restored brightness got %v, want 80", b.LevelPercent)
+	}
+	if o, _ := fan.GetOnOff(); o.State != traits.OnOff_ON {
+		t.Errorf("restored fan got %v, want ON", o.State)
+	}
+	if a, _ := ahu.GetAirTemperature(); a.GetTemperatureRange().GetLow().GetValueCelsius() != 20 || a.GetTemperatureRange().GetHigh().GetValueCelsius() != 24 {
+		t.Errorf("restored ahu got %v, want 20-24", a.GetTemperatureRange())
+	}
+
+	actions, total := r.store.listActions(0, 10)
+	if total != 6 {
+		t.Fatalf("got %d actions, want 6", total)
+	}
+	// newest first, restores happen in reverse order
+	wantOrder := []struct {
+		kind   gen.DemandResponseAction_Kind
+		device string
+	}{
+		{gen.DemandResponseAction_RESTORE, "light"},
+		{gen.DemandResponseAction_RESTORE, "ahu"},
+		{gen.DemandResponseAction_RESTORE, "fan"},
+		{gen.DemandResponseAction_SHED, "fan"},
+		{gen.DemandResponseAction_SHED, "ahu"},
+		{gen.DemandResponseAction_SHED, "light"},
+	}
+	for i, w := range wantOrder {
+		a := actions[i]
+		if a.Kind != w.kind || a.DeviceName != w.device || a.Error != "" {
+			t.Errorf("actions[%d] got %v %s %q, want %v %s", i, a.Kind, a.DeviceName, a.Error, w.kind, w.device)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Package demandresponse provides an automation that sheds load when site demand approaches a limit.
+// Site demand is read from an Electric device. Stages of actions, like dimming lights or turning off non-essential
+// loads, are shed in order as demand crosses each stage threshold, and restored in reverse order once demand falls.
+// Demand response events created via the DemandResponseApi can lower the limit or require a minimum stage.
+// Every action is logged and can be listed via the DemandResponseApi.
+package demandresponse
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto"
+	"github.com/smart-core-os/sc-bos/pkg/auto/demandresponse/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/demandresponsepb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+)
+
+const AutoName = "demandresponse"
+
+var Factory auto.Factory = factory{}
+
+type factory struct{}
+
+func (f factory) New(services auto.Services) service.Lifecycle {
+	a := &autoImpl{Services: services, announcer: node.NewReplaceAnnouncer(services.Node)}
+	a.Service = service.New(service.MonoApply(a.applyConfig), service.WithParser(config.ReadBytes))
+	a.Logger = a.Logger.Named(AutoName)
+	return a
+}
+
+type autoImpl struct {
+	*service.Service[config.Root]
+	auto.Services
+	announcer *node.ReplaceAnnouncer
+}
+
+func (a *autoImpl) applyConfig(ctx context.Context, cfg config.Root) error {
+	now := a.Now
+	if now == nil {
+		now = time.Now
+	}
+	logger := a.Logger.With(zap.String("meter", cfg.Meter))
+	conn := a.Node.ClientConn()
+
+	st := newStore(cfg.Name, a.Database)
+	if err := st.load(now()); err != nil {
+		return err
+	}
+	sh := newShedder(conn, cfg.Name, a.Database)
+	saved, err := sh.load()
+	if err != nil {
+		return err
+	}
+
+	r := newRunner(cfg, conn, st, sh, logger, now)
+	announcer := a.announcer.Replace(ctx)
+	srv := &server{r: r}
+	announcer.Announce(cfg.DeviceName, node.HasTrait(demandresponsepb.TraitName, node.WithClients(gen.WrapDemandResponseApi(srv))))
+	go r.run(ctx, saved)
+	return nil
+}
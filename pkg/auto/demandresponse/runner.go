@@ -0,0 +1,266 @@
+package demandresponse
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/auto/demandresponse/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/pull"
+)
+
+const (
+	// evaluateInterval is how often the stage is checked when demand doesn't change,
+	// so delays elapse and events start and end on time.
+	evaluateInterval = 10 * time.Second
+	// actionTimeout limits how long each device action can take.
+	actionTimeout = 10 * time.Second
+	// restoreTimeout limits how long restoring all devices can take when the automation stops.
+	restoreTimeout = time.Minute
+)
+
+// runner reads site demand and sheds or restores stages as the controller decides.
+type runner struct {
+	cfg     config.Root
+	conn    grpc.ClientConnInterface
+	ctrl    *controller
+	store   *store
+	shedder *shedder
+	logger  *zap.Logger
+	now     func() time.Time
+
+	wake chan struct{} // nudged when events change so they take effect straight away
+
+	mu     sync.Mutex
+	status *gen.DemandResponseStatus
+}
+
+func newRunner(cfg config.Root, conn grpc.ClientConnInterface, st *store, sh *shedder, logger *zap.Logger, now func() time.Time) *runner {
+	return &runner{
+		cfg:     cfg,
+		conn:    conn,
+		ctrl:    newController(cfg),
+		store:   st,
+		shedder: sh,
+		logger:  logger,
+		now:     now,
+		wake:    make(chan struct{}, 1),
+		status:  &gen.DemandResponseStatus{DemandLimit: float32(cfg.DemandLimit)},
+	}
+}
+
+// demandSample is a reading of site demand, or the error reading it.
+type demandSample struct {
+	demand float64
+	err    error
+}
+
+// run sheds and restores stages until ctx is done, then restores everything that is shed.
+// Devices in saved were shed by a previous run and are restored first.
+func (r *runner) run(ctx context.Context, saved []*savedState) {
+	for _, s := range saved {
+		r.restoreDevice(ctx, 0, "", s.Device, s.Type, "automation restarted")
+	}
+
+	samples := make(chan demandSample)
+	go func() {
+		// only returns when ctx is done
+		_ = pull.Changes(ctx, demandFetcher(traits.NewElectricApiClient(r.conn), r.cfg.Meter), samples, pull.WithLogger(r.logger))
+	}()
+
+	ticker := time.NewTicker(evaluateInterval)
+	defer ticker.Stop()
+	var last demandSample
+	hasDemand := false
+	for {
+		select {
+		case <-ctx.Done():
+			// leave the site as we found it
+			restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), restoreTimeout)
+			r.transition(restoreCtx, 0, "automation stopped")
+			cancel()
+			return
+		case s := <-samples:
+			last = s
+			if s.err == nil {
+				hasDemand = true
+				r.setStatus(func(st *gen.DemandResponseStatus) {
+					st.Demand = float32(s.demand)
+					st.DemandTime = timestamppb.New(r.now())
+				})
+			} else {
+				hasDemand = false
+				r.logger.Debug("can't read demand", zap.Error(s.err))
+			}
+		case <-ticker.C:
+		case <-r.wake:
+		}
+		r.evaluate(ctx, last.demand, hasDemand)
+	}
+}
+
+// evaluate works out which stage should be shed and sheds or restores stages to get there.
+func (r *runner) evaluate(ctx context.Context, demand float64, hasDemand bool) {
+	now := r.now()
+	limit := r.cfg.DemandLimit
+	var minStage int
+	var activeIDs []string
+	for _, e := range r.store.activeEvents(now) {
+		activeIDs = append(activeIDs, e.ID)
+		if e.DemandLimit != nil {
+			limit = math.Min(limit, float64(*e.DemandLimit))
+		}
+		minStage = max(minStage, int(e.MinStage))
+	}
+	r.setStatus(func(st *gen.DemandResponseStatus) {
+		st.DemandLimit = float32(limit)
+		st.ActiveEventIds = activeIDs
+	})
+
+	from := r.ctrl.stage
+	to, reason := r.ctrl.step(reading{now: now, demand: demand, hasDemand: hasDemand, limit: limit, minStage: minStage})
+	if to == from {
+		return
+	}
+	r.logger.Info("changing demand response stage", zap.Int("from", from), zap.Int("to", to), zap.String("reason", reason))
+	r.transition(ctx, to, reason)
+}
+
+// transition sheds or restores stages until to stages are shed.
+func (r *runner) transition(ctx context.Context, to int, reason string) {
+	r.mu.Lock()
+	from := int(r.status.Stage)
+	r.mu.Unlock()
+	for i := from; i < to; i++ {
+		stage := r.cfg.Stages[i]
+		for _, a := range stage.Actions {
+			actx, cancel := context.WithTimeout(ctx, actionTimeout)
+			desc, err := r.shedder.shed(actx, a)
+			cancel()
+			r.record(gen.DemandResponseAction_SHED, i+1, stage.Name, a.Name, desc, reason, err)
+		}
+		r.setStage(i + 1)
+	}
+	for i := from; i > to; i-- {
+		stage := r.cfg.Stages[i-1]
+		// restore in the reverse order devices were shed
+		for j := len(stage.Actions) - 1; j >= 0; j-- {
+			a := stage.Actions[j]
+			r.restoreDevice(ctx, i, stage.Name, a.Name, a.Type, reason)
+		}
+		r.setStage(i - 1)
+	}
+}
+
+func (r *runner) restoreDevice(ctx context.Context, stage int, stageName, device string, t config.ActionType, reason string) {
+	actx, cancel := context.WithTimeout(ctx, actionTimeout)
+	defer cancel()
+	desc, err := r.shedder.restore(actx, device, t)
+	r.record(gen.DemandResponseAction_RESTORE, stage, stageName, device, desc, reason, err)
+}
+
+// record logs and saves an action.
+func (r *runner) record(kind gen.DemandResponseAction_Kind, stage int, stageName, device, desc, reason string, err error) {
+	a := &actionRecord{
+		Time:        r.now(),
+		Kind:        kind,
+		Stage:       int32(stage),
+		StageName:   stageName,
+		Device:      device,
+		Description: desc,
+		Reason:      reason,
+	}
+	logger := r.logger.With(zap.Stringer("kind", kind), zap.Int("stage", stage), zap.String("device", device),
+		zap.String("description", desc), zap.String("reason", reason))
+	if err != nil {
+		a.Error = err.Error()
+		logger.Warn("demand response action failed", zap.Error(err))
+	} else {
+		logger.Info("demand response action")
+	}
+	if err := r.store.addAction(a); err != nil {
+		r.logger.Warn("failed to save demand response action", zap.Error(err))
+	}
+}
+
+func (r *runner) setStage(stage int) {
+	r.setStatus(func(st *gen.DemandResponseStatus) {
+		st.Stage = int32(stage)
+		st.StageName = ""
+		if stage > 0 {
+			st.StageName = r.cfg.Stages[stage-1].Name
+		}
+	})
+}
+
+func (r *runner) setStatus(fn func(st *gen.DemandResponseStatus)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(r.status)
+}
+
+func (r *runner) getStatus() *gen.DemandResponseStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return proto.Clone(r.status).(*gen.DemandResponseStatus)
+}
+
+// eventsChanged causes the stage to be evaluated straight away.
+func (r *runner) eventsChanged() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func demandFetcher(client traits.ElectricApiClient, name string) pull.Fetcher[demandSample] {
+	toSample := func(d *traits.ElectricDemand) demandSample {
+		if d.RealPower == nil {
+			return demandSample{err: errors.New("meter has no real power")}
+		}
+		return demandSample{demand: float64(*d.RealPower)}
+	}
+	send := func(ctx context.Context, changes chan<- demandSample, s demandSample) {
+		select {
+		case <-ctx.Done():
+		case changes <- s:
+		}
+	}
+	return pull.NewFetcher(
+		func(ctx context.Context, changes chan<- demandSample) error {
+			stream, err := client.PullDemand(ctx, &traits.PullDemandRequest{Name: name})
+			if err != nil {
+				send(ctx, changes, demandSample{err: err})
+				return err
+			}
+			for {
+				res, err := stream.Recv()
+				if err != nil {
+					send(ctx, changes, demandSample{err: err})
+					return err
+				}
+				for _, change := range res.Changes {
+					send(ctx, changes, toSample(change.Demand))
+				}
+			}
+		},
+		func(ctx context.Context, changes chan<- demandSample) error {
+			res, err := client.GetDemand(ctx, &traits.GetDemandRequest{Name: name})
+			if err != nil {
+				send(ctx, changes, demandSample{err: err})
+				return err
+			}
+			send(ctx, changes, toSample(res))
+			return nil
+		},
+	)
+}
@@ -0,0 +1,155 @@
+package demandresponse
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-golang/pkg/masks"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// server implements gen.DemandResponseApiServer for a runner.
+type server struct {
+	gen.UnimplementedDemandResponseApiServer
+	r *runner
+}
+
+func (s *server) GetDemandResponseStatus(_ context.Context, request *gen.GetDemandResponseStatusRequest) (*gen.DemandResponseStatus, error) {
+	filter := masks.NewResponseFilter(masks.WithFieldMask(request.ReadMask))
+	return filter.FilterClone(s.r.getStatus()).(*gen.DemandResponseStatus), nil
+}
+
+func (s *server) ListDemandResponseEvents(_ context.Context, request *gen.ListDemandResponseEventsRequest) (*gen.ListDemandResponseEventsResponse, error) {
+	filter := masks.NewResponseFilter(masks.WithFieldMask(request.ReadMask))
+	events := s.r.store.listEvents(s.r.now(), request.IncludeEnded)
+	for i, e := range events {
+		events[i] = filter.FilterClone(e).(*gen.DemandResponseEvent)
+	}
+	return &gen.ListDemandResponseEventsResponse{Events: events}, nil
+}
+
+func (s *server) CreateDemandResponseEvent(_ context.Context, request *gen.CreateDemandResponseEventRequest) (*gen.DemandResponseEvent, error) {
+	in := request.GetEvent()
+	if in == nil {
+		return nil, status.Error(codes.InvalidArgument, "event is required")
+	}
+	now := s.r.now()
+	e := &eventRecord{
+		Source:      in.Source,
+		Start:       now,
+		DemandLimit: in.DemandLimit,
+		MinStage:    in.MinStage,
+		CreateTime:  now,
+	}
+	if in.StartTime != nil {
+		e.Start = in.StartTime.AsTime()
+	}
+	e.End = endTime(in)
+	if err := s.validateEvent(e); err != nil {
+		return nil, err
+	}
+	res, err := s.r.store.createEvent(e)
+	if err != nil {
+		return nil, err
+	}
+	s.r.eventsChanged()
+	return res, nil
+}
+
+func (s *server) UpdateDemandResponseEvent(_ context.Context, request *gen.UpdateDemandResponseEventRequest) (*gen.DemandResponseEvent, error) {
+	in := request.GetEvent()
+	if in.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "event.id is required")
+	}
+	paths := []string{"end_time", "demand_limit", "min_stage"}
+	if len(request.GetUpdateMask().GetPaths()) > 0 {
+		paths = request.UpdateMask.Paths
+	}
+	res, err := s.r.store.updateEvent(in.Id, func(e *eventRecord) error {
+		for _, p := range paths {
+			switch p {
+			case "end_time":
+				e.End = endTime(in)
+			case "demand_limit":
+				e.DemandLimit = in.DemandLimit
+			case "min_stage":
+				e.MinStage = in.MinStage
+			default:
+				return status.Errorf(codes.InvalidArgument, "update_mask path %q can't be updated", p)
+			}
+		}
+		return s.validateEvent(e)
+	})
+	if errors.Is(err, errEventNotFound) {
+		return nil, status.Errorf(codes.NotFound, "event %q", in.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.r.eventsChanged()
+	return res, nil
+}
+
+func (s *server) ListDemandResponseActions(_ context.Context, request *gen.ListDemandResponseActionsRequest) (*gen.ListDemandResponseActionsResponse, error) {
+	var offset int
+	if request.PageToken != "" {
+		var err error
+		offset, err = strconv.Atoi(request.PageToken)
+		if err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+	}
+	pageSize := int(request.PageSize)
+	switch {
+	case pageSize <= 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	filter := masks.NewResponseFilter(masks.WithFieldMask(request.ReadMask))
+	actions, total := s.r.store.listActions(offset, pageSize)
+	for i, a := range actions {
+		actions[i] = filter.FilterClone(a).(*gen.DemandResponseAction)
+	}
+	res := &gen.ListDemandResponseActionsResponse{Actions: actions, TotalSize: int32(total)}
+	if next := offset + len(actions); next < total {
+		res.NextPageToken = strconv.Itoa(next)
+	}
+	return res, nil
+}
+
+func (s *server) validateEvent(e *eventRecord) error {
+	// an event ending when it starts has been cancelled
+	if !e.End.IsZero() && e.End.Before(e.Start) {
+		return status.Error(codes.InvalidArgument, "end_time must not be before start_time")
+	}
+	if e.DemandLimit != nil && *e.DemandLimit <= 0 {
+		return status.Error(codes.InvalidArgument, "demand_limit must be positive")
+	}
+	if e.MinStage < 0 || int(e.MinStage) > len(s.r.cfg.Stages) {
+		return status.Errorf(codes.InvalidArgument, "min_stage must be between 0 and %d", len(s.r.cfg.Stages))
+	}
+	if e.DemandLimit == nil && e.MinStage == 0 {
+		return status.Error(codes.InvalidArgument, "demand_limit or min_stage is required")
+	}
+	return nil
+}
+
+// endTime returns the end time of e, or the zero time if it has none.
+func endTime(e *gen.DemandResponseEvent) time.Time {
+	if e.EndTime == nil {
+		return time.Time{}
+	}
+	return e.EndTime.AsTime()
+}
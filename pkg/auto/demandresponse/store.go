@@ -0,0 +1,243 @@
+package demandresponse
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/timshannon/bolthold"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const (
+	// retention is how long actions, and events that have ended, are kept for.
+	retention = 90 * 24 * time.Hour
+	// maxActions is the number of most recent actions that can be listed.
+	maxActions = 1000
+)
+
+var errEventNotFound = errors.New("event not found")
+
+type eventRecord struct {
+	Scope       string
+	ID          string
+	Source      string
+	Start       time.Time
+	End         time.Time // zero if the event doesn't end
+	DemandLimit *float32
+	MinStage    int32
+	CreateTime  time.Time
+}
+
+func (r *eventRecord) active(t time.Time) bool {
+	return !t.Before(r.Start) && (r.End.IsZero() || t.Before(r.End))
+}
+
+func (r *eventRecord) ended(t time.Time) bool {
+	return !r.End.IsZero() && !t.Before(r.End)
+}
+
+func (r *eventRecord) toProto() *gen.DemandResponseEvent {
+	e := &gen.DemandResponseEvent{
+		Id:          r.ID,
+		Source:      r.Source,
+		StartTime:   timestamppb.New(r.Start),
+		DemandLimit: r.DemandLimit,
+		MinStage:    r.MinStage,
+		CreateTime:  timestamppb.New(r.CreateTime),
+	}
+	if !r.End.IsZero() {
+		e.EndTime = timestamppb.New(r.End)
+	}
+	return e
+}
+
+type actionRecord struct {
+	Scope       string
+	ID          string
+	Time        time.Time
+	Kind        gen.DemandResponseAction_Kind
+	Stage       int32
+	StageName   string
+	Device      string
+	Description string
+	Reason      string
+	Error       string
+}
+
+func (r *actionRecord) toProto() *gen.DemandResponseAction {
+	return &gen.DemandResponseAction{
+		Id:          r.ID,
+		ActionTime:  timestamppb.New(r.Time),
+		Kind:        r.Kind,
+		Stage:       r.Stage,
+		StageName:   r.StageName,
+		DeviceName:  r.Device,
+		Description: r.Description,
+		Reason:      r.Reason,
+		Error:       r.Error,
+	}
+}
+
+// store keeps demand response events and the action log, persisting them to db.
+type store struct {
+	scope string
+	db    *bolthold.Store // may be nil, records are then only kept in memory
+
+	mu      sync.Mutex
+	events  map[string]*eventRecord
+	actions []*actionRecord // newest last
+	seq     int             // to make ids unique
+}
+
+func newStore(scope string, db *bolthold.Store) *store {
+	return &store{scope: scope, db: db, events: make(map[string]*eventRecord)}
+}
+
+// load reads records saved by a previous run, removing any older than the retention period.
+func (s *store) load(now time.Time) error {
+	if s.db == nil {
+		return nil
+	}
+	cutoff := now.Add(-retention)
+	err := s.db.DeleteMatching(&actionRecord{}, bolthold.Where("Scope").Eq(s.scope).And("Time").Lt(cutoff))
+	if err != nil {
+		return err
+	}
+	var events []*eventRecord
+	if err := s.db.Find(&events, bolthold.Where("Scope").Eq(s.scope)); err != nil {
+		return err
+	}
+	var actions []*actionRecord
+	q := bolthold.Where("Scope").Eq(s.scope).SortBy("Time").Reverse().Limit(maxActions)
+	if err := s.db.Find(&actions, q); err != nil {
+		return err
+	}
+	slices.Reverse(actions)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range events {
+		if e.ended(cutoff) {
+			if err := s.db.Delete(s.key("event", e.ID), eventRecord{}); err != nil && !errors.Is(err, bolthold.ErrNotFound) {
+				return err
+			}
+			continue
+		}
+		s.events[e.ID] = e
+	}
+	s.actions = actions
+	return nil
+}
+
+func (s *store) newID(t time.Time) string {
+	s.seq++
+	return fmt.Sprintf("%d-%d", t.UnixMilli(), s.seq)
+}
+
+// activeEvents returns events active at t.
+func (s *store) activeEvents(t time.Time) []*eventRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []*eventRecord
+	for _, e := range s.events {
+		if e.active(t) {
+			res = append(res, e)
+		}
+	}
+	slices.SortFunc(res, compareEvents)
+	return res
+}
+
+// listEvents returns events that haven't ended at t, or all events if includeEnded.
+func (s *store) listEvents(t time.Time, includeEnded bool) []*gen.DemandResponseEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []*eventRecord
+	for _, e := range s.events {
+		if includeEnded || !e.ended(t) {
+			records = append(records, e)
+		}
+	}
+	slices.SortFunc(records, compareEvents)
+	res := make([]*gen.DemandResponseEvent, len(records))
+	for i, r := range records {
+		res[i] = r.toProto()
+	}
+	return res
+}
+
+func (s *store) createEvent(e *eventRecord) (*gen.DemandResponseEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.Scope = s.scope
+	e.ID = s.newID(e.CreateTime)
+	if err := s.save("event", e.ID, e); err != nil {
+		return nil, err
+	}
+	s.events[e.ID] = e
+	return e.toProto(), nil
+}
+
+// updateEvent calls fn with a copy of the event with id, saving the result if fn returns no error.
+func (s *store) updateEvent(id string, fn func(e *eventRecord) error) (*gen.DemandResponseEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.events[id]
+	if !ok {
+		return nil, errEventNotFound
+	}
+	e := *old
+	if err := fn(&e); err != nil {
+		return nil, err
+	}
+	if err := s.save("event", id, &e); err != nil {
+		return nil, err
+	}
+	s.events[id] = &e
+	return e.toProto(), nil
+}
+
+func (s *store) addAction(a *actionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a.Scope = s.scope
+	a.ID = s.newID(a.Time)
+	s.actions = append(s.actions, a)
+	if len(s.actions) > maxActions {
+		s.actions = slices.Delete(s.actions, 0, len(s.actions)-maxActions)
+	}
+	return s.save("action", a.ID, a)
+}
+
+// listActions returns a page of actions, newest first, and the total number of actions.
+func (s *store) listActions(offset, limit int) ([]*gen.DemandResponseAction, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := len(s.actions)
+	var res []*gen.DemandResponseAction
+	for i := total - 1 - offset; i >= 0 && len(res) < limit; i-- {
+		res = append(res, s.actions[i].toProto())
+	}
+	return res, total
+}
+
+func (s *store) save(kind, id string, v any) error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Upsert(s.key(kind, id), v)
+}
+
+func (s *store) key(kind, id string) string {
+	return s.scope + "/" + kind + "/" + id
+}
+
+func compareEvents(a, b *eventRecord) int {
+	return cmp.Or(a.Start.Compare(b.Start), cmp.Compare(a.ID, b.ID))
+}
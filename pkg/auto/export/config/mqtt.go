@@ -1,18 +1,30 @@
 package config
 
-import mqtt "github.com/eclipse/paho.mqtt.golang"
+import (
+	"fmt"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
 
 type MQTTBroker struct {
-	Host         string `json:"host,omitempty"`
-	Username     string `json:"username,omitempty"`
-	Password     string `json:"password,omitempty"`
-	PasswordFile string `json:"passwordFile,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Username string `json:"username,omitempty"`
+	jsontypes.Password
 }
 
 func (b MQTTBroker) ClientOptions() (*mqtt.ClientOptions, error) {
 	opts := mqtt.NewClientOptions()
 	opts.AddBroker(b.Host)
 	opts.SetUsername(b.Username)
+	if b.Password != (jsontypes.Password{}) {
+		password, err := b.Password.Read()
+		if err != nil {
+			return nil, fmt.Errorf("broker password: %w", err)
+		}
+		opts.SetPassword(password)
+	}
 	opts.SetOrderMatters(false)
 	return opts, nil
 }
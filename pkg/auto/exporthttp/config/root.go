@@ -52,6 +52,8 @@ type Temperature struct {
 type Energy struct {
 	Source
 	Meters []string `json:"meters"`
+	// IncludeConversions adds the carbon and cost of the consumption, as converted by the MeterConversionApi.
+	IncludeConversions bool `json:"includeConversions,omitempty"`
 }
 
 type AirQuality struct {
@@ -3,12 +3,15 @@ package job
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math"
 	"strings"
 	"time"
 
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
 	"github.com/smart-core-os/sc-bos/pkg/auto/exporthttp/types"
 	"github.com/smart-core-os/sc-bos/pkg/gen"
 )
@@ -18,7 +21,9 @@ type EnergyJob struct {
 	BaseJob
 	client     gen.MeterHistoryClient
 	infoClient gen.MeterInfoClient
-	Meters     []string
+	// conversionClient is used to include the carbon and cost of the consumption, nil if they aren't included.
+	conversionClient gen.MeterConversionApiClient
+	Meters           []string
 }
 
 func (e *EnergyJob) Do(ctx context.Context, sendFn sender) error {
@@ -48,6 +53,11 @@ func (e *EnergyJob) Do(ctx context.Context, sendFn sender) error {
 		consumption += processMeterRecords(multiplier, earliest, latest)
 	}
 
+	conv, err := e.getConversions(ctx, now)
+	if err != nil {
+		e.Logger.Error("getting conversions, they won't be included", zap.Error(err))
+	}
+
 	roundedConsumption := float32(math.Floor(float64(consumption)))
 
 	if roundedConsumption <= 0 {
@@ -65,6 +75,10 @@ func (e *EnergyJob) Do(ctx context.Context, sendFn sender) error {
 			Units: "kWh",
 		},
 	}
+	if conv != nil {
+		body.TodaysCarbon = &types.Float64Measure{Value: math.Round(conv.carbon*100) / 100, Units: "kgCO2e"}
+		body.TodaysCost = &types.Float64Measure{Value: math.Round(conv.cost*100) / 100, Units: conv.currency}
+	}
 
 	bytes, err := json.Marshal(body)
 
@@ -98,3 +112,38 @@ func (e *EnergyJob) getUnitMultiplier(ctx context.Context, meter string) (float3
 
 	return multiplier, nil
 }
+
+// conversions is the total carbon and cost of consumption across all meters.
+type conversions struct {
+	carbon   float64
+	cost     float64
+	currency string
+}
+
+// getConversions returns the carbon and cost of all meters since the previous execution.
+// Returns nil if conversions aren't included.
+// Conversions are all or nothing, an error with any meter means none are returned.
+func (e *EnergyJob) getConversions(ctx context.Context, now time.Time) (*conversions, error) {
+	if e.conversionClient == nil {
+		return nil, nil
+	}
+	res := &conversions{}
+	for _, meter := range e.Meters {
+		cctx, cancel := context.WithTimeout(ctx, e.Timeout.Or(defaultTimeout))
+		resp, err := e.conversionClient.ListConvertedMeterReadings(cctx, &gen.ListConvertedMeterReadingsRequest{
+			MeterName: meter,
+			Period:    &timepb.Period{StartTime: timestamppb.New(e.PreviousExecution), EndTime: timestamppb.New(now)},
+		})
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", meter, err)
+		}
+		if res.currency != "" && resp.Currency != res.currency {
+			return nil, fmt.Errorf("%s: currency %s doesn't match %s", meter, resp.Currency, res.currency)
+		}
+		res.currency = resp.Currency
+		res.carbon += resp.GetTotal().GetCarbon()
+		res.cost += resp.GetTotal().GetCost()
+	}
+	return res, nil
+}
@@ -187,6 +187,9 @@ func FromConfig(cfg config.Root, db *bolthold.Store, autoName, scName string, no
 			client:     gen.NewMeterHistoryClient(node.ClientConn()),
 			infoClient: gen.NewMeterInfoClient(node.ClientConn()),
 		}
+		if cfg.Sources.Energy.IncludeConversions {
+			energy.conversionClient = gen.NewMeterConversionApiClient(node.ClientConn())
+		}
 
 		energy.PreviousExecution = energy.getPreviousExecution()
 		jobs = append(jobs, energy)
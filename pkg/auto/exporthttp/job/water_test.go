@@ -0,0 +1,76 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto/exporthttp/types"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread/historyreadtest"
+)
+
+func TestWaterJob_getUnitMultiplier(t *testing.T) {
+	tests := []struct {
+		unit string
+		want float32
+	}{
+		{unit: "cm3", want: 0.001},
+		{unit: "m3", want: 1000},
+		{unit: "litres", want: 1},
+		{unit: "", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			w := &WaterJob{infoClient: fakeMeterInfo{"meter": tt.unit}}
+			got, err := w.getUnitMultiplier(context.Background(), "meter")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWaterJob_Do(t *testing.T) {
+	previous := time.Now().Add(-24 * time.Hour)
+	w := &WaterJob{
+		BaseJob: BaseJob{
+			Url:               "https://example.com/water",
+			PreviousExecution: previous,
+			Site:              "site",
+			Logger:            zap.NewNop(),
+		},
+		client: historyreadtest.MeterHistory{
+			"litres": {
+				historyreadtest.Reading(previous.Add(-time.Hour), 100),
+				historyreadtest.Reading(previous.Add(time.Hour), 150),
+			},
+			"cubic-metres": {
+				historyreadtest.Reading(previous.Add(-time.Hour), 10),
+				historyreadtest.Reading(previous.Add(time.Hour), 12.5),
+			},
+		},
+		infoClient: fakeMeterInfo{"litres": "litres", "cubic-metres": "m3"},
+		Meters:     []string{"litres", "cubic-metres"},
+	}
+
+	var got types.WaterConsumption
+	err := w.Do(context.Background(), func(_ context.Context, url string, body []byte) error {
+		assert.Equal(t, w.Url, url)
+		return json.Unmarshal(body, &got)
+	})
+	assert.NoError(t, err)
+	// 50 litres, plus 2.5 m3 reported in litres
+	assert.Equal(t, types.IntMeasure{Value: 2550, Units: "litres"}, got.TodaysWaterConsumption)
+}
+
+// fakeMeterInfo returns the usage unit of meters by name.
+type fakeMeterInfo map[string]string
+
+func (f fakeMeterInfo) DescribeMeterReading(_ context.Context, in *gen.DescribeMeterReadingRequest, _ ...grpc.CallOption) (*gen.MeterReadingSupport, error) {
+	return &gen.MeterReadingSupport{UsageUnit: f[in.Name]}, nil
+}
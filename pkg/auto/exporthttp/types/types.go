@@ -38,6 +38,9 @@ type AverageTemperature struct {
 type EnergyConsumption struct {
 	Meta
 	TodaysEnergyConsumption Float32Measure `json:"todaysEnergyConsumption"`
+	// TodaysCarbon and TodaysCost are only present if conversions are included.
+	TodaysCarbon *Float64Measure `json:"todaysCarbon,omitempty"`
+	TodaysCost   *Float64Measure `json:"todaysCost,omitempty"`
 }
 
 type AverageCo2 struct {
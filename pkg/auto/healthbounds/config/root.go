@@ -82,7 +82,7 @@ func (h *HealthCheck) MarshalJSON() ([]byte, error) {
 // Source configures which property of a device is checked by a health check.
 type Source struct {
 	// Trait is the fully qualified name of a trait implemented by monitored devices.
-	// TODO: Only some traits are supported, see internal/anytrait/registry.go for the list of supported traits.
+	// TODO: Only some traits are supported, see pkg/auto/internal/anytrait/registry.go for the list of supported traits.
 	Trait    trait.Name `json:"trait"`
 	Resource Resource   `json:"resource,omitempty"`
 	Value    Value      `json:"value,omitempty"`
@@ -19,7 +19,7 @@ import (
 	"github.com/smart-core-os/sc-bos/internal/protobuf/protopath2"
 	"github.com/smart-core-os/sc-bos/pkg/auto"
 	"github.com/smart-core-os/sc-bos/pkg/auto/healthbounds/config"
-	"github.com/smart-core-os/sc-bos/pkg/auto/healthbounds/internal/anytrait"
+	"github.com/smart-core-os/sc-bos/pkg/auto/internal/anytrait"
 	"github.com/smart-core-os/sc-bos/pkg/gen"
 	"github.com/smart-core-os/sc-bos/pkg/gentrait/healthpb"
 	"github.com/smart-core-os/sc-bos/pkg/task"
@@ -168,6 +168,7 @@ func (a *automation) applyConfig(ctx context.Context, cfg config.Root) error {
 	default:
 		return fmt.Errorf("unsupported storage type %s", cfg.Storage.Type)
 	}
+	store = history.WithMetrics(store, cfg.Storage.Type)
 
 	// work out where we're getting the records from
 	var serverClient wrap.ServiceUnwrapper
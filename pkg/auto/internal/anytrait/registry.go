@@ -50,6 +50,18 @@ var (
 			get:  getter(traits.NewAirTemperatureApiClient, traits.AirTemperatureApiClient.GetAirTemperature),
 			pull: puller(traits.NewAirTemperatureApiClient, traits.AirTemperatureApiClient.PullAirTemperature, (*traits.PullAirTemperatureResponse_Change).GetAirTemperature),
 		})
+		knownTraits.add(trait.AirQualitySensor, Resource{
+			name: "AirQuality",
+			desc: (&traits.AirQuality{}).ProtoReflect().Descriptor(),
+			get:  getter(traits.NewAirQualitySensorApiClient, traits.AirQualitySensorApiClient.GetAirQuality),
+			pull: puller(traits.NewAirQualitySensorApiClient, traits.AirQualitySensorApiClient.PullAirQuality, (*traits.PullAirQualityResponse_Change).GetAirQuality),
+		})
+		knownTraits.add(trait.Electric, Resource{
+			name: "Demand",
+			desc: (&traits.ElectricDemand{}).ProtoReflect().Descriptor(),
+			get:  getter(traits.NewElectricApiClient, traits.ElectricApiClient.GetDemand),
+			pull: puller(traits.NewElectricApiClient, traits.ElectricApiClient.PullDemand, (*traits.PullDemandResponse_Change).GetDemand),
+		})
 		knownTraits.add(emergencylightpb.TraitName, Resource{
 			name: "TestResultSet",
 			desc: (&gen.TestResultSet{}).ProtoReflect().Descriptor(),
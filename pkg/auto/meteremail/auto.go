@@ -9,11 +9,14 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/smart-core-os/sc-api/go/traits"
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
 	"github.com/smart-core-os/sc-bos/pkg/auto"
 	"github.com/smart-core-os/sc-bos/pkg/auto/meteremail/config"
 	"github.com/smart-core-os/sc-bos/pkg/block"
@@ -84,6 +87,26 @@ func (a *autoImpl) getMeterReadingAndSource(ctx context.Context, meterName strin
 	return source, meterReading, nil
 }
 
+// getConversion gets the consumption, carbon, and cost of the given electric meter between start and end.
+func (a *autoImpl) getConversion(ctx context.Context, meterName string, client gen.MeterConversionApiClient, start, end time.Time, timing *config.Timing) (*Conversion, error) {
+	res, err := retryT(ctx, timing, func(ctx context.Context) (*gen.ListConvertedMeterReadingsResponse, error) {
+		withTimeoutCtx, cancel := context.WithTimeout(ctx, timing.Timeout.Duration)
+		defer cancel()
+		return client.ListConvertedMeterReadings(withTimeoutCtx, &gen.ListConvertedMeterReadingsRequest{
+			MeterName: meterName,
+			Period:    &timepb.Period{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(res.UsageUnit, "kWh") {
+		return nil, fmt.Errorf("converted usage is in %s, not kWh", res.UsageUnit)
+	}
+	total := res.GetTotal()
+	return &Conversion{Usage: total.GetUsage(), Carbon: total.GetCarbon(), Cost: total.GetCost(), Currency: res.Currency}, nil
+}
+
 // generateSummaryReports calculates the total energy per zone and appends the totals to attrs.EnergySummaryReports & attrs.WaterSummaryReports
 func generateSummaryReports(attrs *Attrs) {
 
@@ -99,16 +122,23 @@ func generateSummaryReports(attrs *Attrs) {
 		for _, zoneName := range zoneKeys {
 			zoneTotalEnergy := float32(0.0)
 			zoneTotalWater := float32(0.0)
+			energyReport := SummaryReport{Floor: floorName, Zone: zoneName}
 			meters := zones[zoneName]
 			for _, meter := range meters {
 				if meter.MeterReading.MeterType == MeterTypeElectric {
 					zoneTotalEnergy += meter.MeterReading.Reading
+					if c := meter.Conversion; c != nil {
+						energyReport.Usage += c.Usage
+						energyReport.Carbon += c.Carbon
+						energyReport.Cost += c.Cost
+					}
 				}
 				if meter.MeterReading.MeterType == MeterTypeWater {
 					zoneTotalWater += meter.MeterReading.Reading
 				}
 			}
-			attrs.EnergySummaryReports = append(attrs.EnergySummaryReports, SummaryReport{Floor: floorName, Zone: zoneName, TotalReading: zoneTotalEnergy})
+			energyReport.TotalReading = zoneTotalEnergy
+			attrs.EnergySummaryReports = append(attrs.EnergySummaryReports, energyReport)
 			attrs.WaterSummaryReports = append(attrs.WaterSummaryReports, SummaryReport{Floor: floorName, Zone: zoneName, TotalReading: zoneTotalWater})
 		}
 	}
@@ -133,12 +163,23 @@ func (a *autoImpl) createMeterReadingsFile(attrs *Attrs) []byte {
 
 		for _, zoneName := range zoneKeys {
 			fmt.Fprintf(buf, "%s - %s\n", floorName, zoneName)
-			fmt.Fprintf(buf, "Name                          ,Floor	,Zone	,Reading (kWh)	\n")
+			if attrs.IncludeConversions {
+				fmt.Fprintf(buf, "Name                          ,Floor	,Zone	,Reading (kWh)	,Consumption (kWh)	,Carbon (kgCO2e)	,Cost (%s)	\n", attrs.Currency)
+			} else {
+				fmt.Fprintf(buf, "Name                          ,Floor	,Zone	,Reading (kWh)	\n")
+			}
 			meters := zones[zoneName]
 			for _, meter := range meters {
-				if meter.MeterReading.MeterType == MeterTypeElectric {
-					fmt.Fprintf(buf, "%s,%s,%s,%f\n", meter.Source.Name, floorName, zoneName, meter.MeterReading.Reading)
+				if meter.MeterReading.MeterType != MeterTypeElectric {
+					continue
+				}
+				fmt.Fprintf(buf, "%s,%s,%s,%f", meter.Source.Name, floorName, zoneName, meter.MeterReading.Reading)
+				if c := meter.Conversion; attrs.IncludeConversions && c != nil {
+					fmt.Fprintf(buf, ",%f,%f,%.2f", c.Usage, c.Carbon, c.Cost)
+				} else if attrs.IncludeConversions {
+					fmt.Fprintf(buf, ",,,")
 				}
+				fmt.Fprintf(buf, "\n")
 			}
 		}
 	}
@@ -210,6 +251,7 @@ func (a *autoImpl) applyConfig(ctx context.Context, cfg config.Root) error {
 
 	meterClient := gen.NewMeterApiClient(a.Node.ClientConn())
 	metadataClient := traits.NewMetadataApiClient(a.Node.ClientConn())
+	conversionClient := gen.NewMeterConversionApiClient(a.Node.ClientConn())
 
 	sendTime := cfg.Destination.SendTime
 	now := cfg.Now
@@ -233,16 +275,28 @@ func (a *autoImpl) applyConfig(ctx context.Context, cfg config.Root) error {
 			}
 
 			attrs := Attrs{
-				Now:          t,
-				Stats:        []Stats{},
-				TemplateArgs: cfg.TemplateArgs,
+				Now:                t,
+				Stats:              []Stats{},
+				TemplateArgs:       cfg.TemplateArgs,
+				IncludeConversions: cfg.IncludeConversions,
+				// conversions cover the time since the previous email
+				PeriodStart: t.Add(-sendTime.Next(t).Sub(t)),
 			}
 
 			logger.Debug("Meter email is being generated...", zap.Duration("timeout", cfg.Timing.Timeout.Duration))
 			for _, meterName := range cfg.ElectricMeters {
 				source, reading, err := a.getMeterReadingAndSource(ctx, meterName, MeterTypeElectric, meterClient, metadataClient, &cfg.Timing)
 				if err == nil {
-					attrs.Stats = append(attrs.Stats, Stats{Source: *source, MeterReading: *reading})
+					stats := Stats{Source: *source, MeterReading: *reading}
+					if cfg.IncludeConversions {
+						stats.Conversion, err = a.getConversion(ctx, meterName, conversionClient, attrs.PeriodStart, t, &cfg.Timing)
+						if err != nil {
+							logger.Warn("failed to convert consumption for electric meter", zap.String("meterName", meterName), zap.Error(err))
+						} else if attrs.Currency == "" {
+							attrs.Currency = stats.Conversion.Currency
+						}
+					}
+					attrs.Stats = append(attrs.Stats, stats)
 				} else {
 					logger.Error("Error getting info for electric meter ", zap.String("meterName", meterName), zap.Error(err))
 				}
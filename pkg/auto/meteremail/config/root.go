@@ -72,6 +72,9 @@ type Root struct {
 	WaterMeters    []string         `json:"waterMeters,omitempty"`
 	Timing         Timing           `json:"timing,omitempty"`
 	TemplateArgs   TemplateArgs     `json:"templateArgs,omitempty"`
+	// IncludeConversions adds the consumption, carbon, and cost of each electric meter since the previous email,
+	// as converted by the MeterConversionApi.
+	IncludeConversions bool `json:"includeConversions,omitempty"`
 }
 
 type AttachmentCfg struct {
@@ -228,12 +231,22 @@ const DefaultEmailBody = `<html lang="en">
 	<th>Floor</th>
 	<th>Zone</th>
 	<th>Total Reading (kWh)</th>
+{{- if .IncludeConversions}}
+	<th>Consumption since {{printTime .PeriodStart}} (kWh)</th>
+	<th>Carbon (kgCO2e)</th>
+	<th>Cost ({{.Currency}})</th>
+{{- end}}
 </tr>
 {{range .EnergySummaryReports}}
 <tr>	
 	<td>{{.Floor}}</td>	
 	<td>{{.Zone}}</td>
 	<td>{{.TotalReading}}</td>
+{{- if $.IncludeConversions}}
+	<td>{{printf "%.2f" .Usage}}</td>
+	<td>{{printf "%.2f" .Carbon}}</td>
+	<td>{{printf "%.2f" .Cost}}</td>
+{{- end}}
 </tr>
 {{end}}
 </tbody>
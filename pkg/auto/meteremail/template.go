@@ -19,6 +19,11 @@ type Attrs struct {
 	EnergySummaryReports []SummaryReport
 	WaterSummaryReports  []SummaryReport
 	TemplateArgs         config.TemplateArgs
+
+	// IncludeConversions is true if electric meters have conversions for the consumption since PeriodStart.
+	IncludeConversions bool
+	PeriodStart        time.Time
+	Currency           string
 }
 
 // grab the floors and sort them so we can iterate over consistently
@@ -35,6 +40,15 @@ func (a *Attrs) getFloorKeys() []string {
 type Stats struct {
 	Source       config.Source
 	MeterReading MeterReading
+	Conversion   *Conversion // nil if conversions aren't included or aren't available for the meter
+}
+
+// Conversion is the consumption of a meter over a period, and its carbon and cost.
+type Conversion struct {
+	Usage    float64 // in kWh
+	Carbon   float64 // in kgCO2e
+	Cost     float64
+	Currency string
 }
 
 type MeterReading struct {
@@ -47,4 +61,8 @@ type SummaryReport struct {
 	Floor        string
 	Zone         string
 	TotalReading float32
+	// Usage, Carbon, and Cost are the totals of meter conversions, if included.
+	Usage  float64
+	Carbon float64
+	Cost   float64
 }
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto"
+	healthbounds "github.com/smart-core-os/sc-bos/pkg/auto/healthbounds/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	DefaultMinChangingPeers = 1
+	DefaultSpikePer         = time.Minute
+	DefaultSpikeHold        = 15 * time.Minute
+)
+
+type Root struct {
+	auto.Config
+	// Devices selects the sensors to check, the query matches those in the DevicesApi.
+	// Sensors matched by the same automation are compared to each other to detect stuck values,
+	// so each automation should select comparable sensors, like all the temperature sensors in a building.
+	Devices []*Condition `json:"devices"`
+	// Source is the numeric value checked, like {"trait": "smartcore.traits.AirTemperature", "value": "ambientTemperature.valueCelsius"}.
+	Source healthbounds.Source `json:"source"`
+	// Check configures the metadata of the health check created for each device, like displayName.
+	Check *HealthCheck `json:"check,omitempty"`
+
+	// Stuck detects values that stop changing, when absent stuck values are not detected.
+	Stuck *Stuck `json:"stuck,omitempty"`
+	// Spike detects values that change faster than is physically possible, when absent spikes are not detected.
+	Spike *Spike `json:"spike,omitempty"`
+	// Range detects values that are physically impossible, when absent all values are possible.
+	Range *Range `json:"range,omitempty"`
+}
+
+// Stuck configures detection of sensors reporting a constant value.
+type Stuck struct {
+	// After is how long a value must not change before it is stuck, required.
+	After jsontypes.Duration `json:"after"`
+	// Epsilon is how much a value can change and still be considered unchanged.
+	Epsilon float64 `json:"epsilon,omitempty"`
+	// MinChangingPeers is how many other sensors must have changed while a sensor was unchanged for it to be stuck.
+	// This avoids flagging sensors whose value really is constant, like temperatures in an empty building.
+	// Defaults to 1, set to -1 to flag unchanged values whatever other sensors do.
+	MinChangingPeers int `json:"minChangingPeers,omitempty"`
+}
+
+// Spike configures detection of sudden changes in value.
+type Spike struct {
+	// MaxChange is the largest change in value that is possible during Per, required.
+	MaxChange float64 `json:"maxChange"`
+	// Per is the time MaxChange applies to, defaults to 1m.
+	// Changes between values reported less than Per apart are compared to MaxChange directly,
+	// changes between values further apart are scaled to the change per Per.
+	Per *jsontypes.Duration `json:"per,omitempty"`
+	// Hold is how long a spike is reported for after it happened, defaults to 15m.
+	Hold *jsontypes.Duration `json:"hold,omitempty"`
+}
+
+// Range configures the values a sensor can physically report.
+// Either or both of Min and Max must be set.
+type Range struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+func ReadBytes(data []byte) (cfg Root, err error) {
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return
+	}
+	if s := cfg.Stuck; s != nil && s.MinChangingPeers == 0 {
+		s.MinChangingPeers = DefaultMinChangingPeers
+	}
+	return cfg, cfg.validate()
+}
+
+func (r Root) validate() error {
+	if r.Source.Trait == "" {
+		return errors.New("source.trait is required")
+	}
+	if r.Stuck == nil && r.Spike == nil && r.Range == nil {
+		return errors.New("at least one of stuck, spike or range is required")
+	}
+	if s := r.Stuck; s != nil {
+		if s.After.Duration <= 0 {
+			return errors.New("stuck.after must be positive")
+		}
+		if s.Epsilon < 0 {
+			return errors.New("stuck.epsilon must not be negative")
+		}
+		if s.MinChangingPeers < -1 {
+			return errors.New("stuck.minChangingPeers must be -1 or more")
+		}
+	}
+	if s := r.Spike; s != nil {
+		if s.MaxChange <= 0 {
+			return errors.New("spike.maxChange must be positive")
+		}
+		if s.PerOrDefault() <= 0 {
+			return errors.New("spike.per must be positive")
+		}
+		if s.HoldOrDefault() <= 0 {
+			return errors.New("spike.hold must be positive")
+		}
+	}
+	if rng := r.Range; rng != nil {
+		if rng.Min == nil && rng.Max == nil {
+			return errors.New("range needs min or max")
+		}
+		if rng.Min != nil && rng.Max != nil && *rng.Min > *rng.Max {
+			return fmt.Errorf("range.min %v is more than range.max %v", *rng.Min, *rng.Max)
+		}
+	}
+	return nil
+}
+
+func (s *Spike) PerOrDefault() time.Duration {
+	return s.Per.Or(DefaultSpikePer)
+}
+
+func (s *Spike) HoldOrDefault() time.Duration {
+	return s.Hold.Or(DefaultSpikeHold)
+}
+
+func (r Root) DevicesPb() []*gen.Device_Query_Condition {
+	conds := make([]*gen.Device_Query_Condition, len(r.Devices))
+	for i, c := range r.Devices {
+		conds[i] = c.pb
+	}
+	return conds
+}
+
+// CheckPb returns the configured health check, or an empty check if none is configured.
+func (r Root) CheckPb() *gen.HealthCheck {
+	if r.Check == nil {
+		return &gen.HealthCheck{}
+	}
+	return r.Check.pb
+}
+
+type Condition struct {
+	pb *gen.Device_Query_Condition
+}
+
+func (c *Condition) UnmarshalJSON(bytes []byte) error {
+	cond := &gen.Device_Query_Condition{}
+	err := protojson.Unmarshal(bytes, cond)
+	if err != nil {
+		return fmt.Errorf("condition: %w", err)
+	}
+	*c = Condition{cond}
+	return nil
+}
+
+func (c *Condition) MarshalJSON() ([]byte, error) {
+	return protojson.Marshal(c.pb)
+}
+
+type HealthCheck struct {
+	pb *gen.HealthCheck
+}
+
+func (h *HealthCheck) UnmarshalJSON(bytes []byte) error {
+	hc := &gen.HealthCheck{}
+	err := protojson.Unmarshal(bytes, hc)
+	if err != nil {
+		return fmt.Errorf("health check: %w", err)
+	}
+	*h = HealthCheck{hc}
+	return nil
+}
+
+func (h *HealthCheck) MarshalJSON() ([]byte, error) {
+	return protojson.Marshal(h.pb)
+}
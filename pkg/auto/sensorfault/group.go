@@ -0,0 +1,265 @@
+package sensorfault
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto/sensorfault/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/healthpb"
+)
+
+// SystemName is the system of the fault codes reported by this automation.
+const SystemName = "sensorfault"
+
+// Fault codes reported by this automation.
+const (
+	CodeStuck      = "STUCK"
+	CodeSpike      = "SPIKE"
+	CodeOutOfRange = "OUT_OF_RANGE"
+)
+
+var faultCodes = []string{CodeStuck, CodeSpike, CodeOutOfRange}
+
+// faultCheck is the part of healthpb.FaultCheck used by a group.
+type faultCheck interface {
+	AddOrUpdateFault(err *gen.HealthCheck_Error)
+	RemoveFault(err *gen.HealthCheck_Error)
+	UpdateReliability(ctx context.Context, r *gen.HealthCheck_Reliability)
+}
+
+// group tracks the values of comparable sensors, reporting faults to the health check of each sensor.
+// Sensors in a group are peers, a sensor is only stuck if its peers are changing.
+type group struct {
+	cfg config.Root
+
+	mu      sync.Mutex
+	sensors map[string]*sensor
+}
+
+// sensor is the state of a single sensor in a group.
+type sensor struct {
+	check faultCheck
+
+	// the last value reported by the sensor, valid if hasValue
+	hasValue bool
+	value    float64
+	at       time.Time
+
+	// ref is the value the sensor has been within stuck.epsilon of since refAt.
+	ref   float64
+	refAt time.Time
+	// changedAt is when the value last moved further than stuck.epsilon from ref, zero if it hasn't since connecting.
+	changedAt time.Time
+
+	spike *spike
+
+	// faults are the details of each fault code currently reported.
+	faults map[string]*gen.HealthCheck_Error
+}
+
+// spike records a sudden change in value.
+type spike struct {
+	from, to     float64
+	fromAt, toAt time.Time
+}
+
+func newGroup(cfg config.Root) *group {
+	return &group{cfg: cfg, sensors: make(map[string]*sensor)}
+}
+
+func (g *group) add(name string, check faultCheck) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.sensors[name] = &sensor{check: check, faults: make(map[string]*gen.HealthCheck_Error)}
+}
+
+func (g *group) remove(name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.sensors, name)
+}
+
+// record updates the value of a sensor, reported at time at.
+func (g *group) record(ctx context.Context, name string, v float64, at time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s, ok := g.sensors[name]
+	if !ok {
+		return
+	}
+	if !s.hasValue {
+		// the first value, or the first since an error
+		s.check.UpdateReliability(ctx, healthpb.ReliabilityFromErr(nil))
+	}
+	if s.hasValue {
+		if sp := g.cfg.Spike; sp != nil && isSpike(sp, s.value, v, at.Sub(s.at)) {
+			s.spike = &spike{from: s.value, to: v, fromAt: s.at, toAt: at}
+		}
+	}
+	if !s.hasValue || math.Abs(v-s.ref) > g.stuckEpsilon() {
+		if s.hasValue {
+			s.changedAt = at
+		}
+		s.ref, s.refAt = v, at
+	}
+	s.hasValue, s.value, s.at = true, v, at
+	g.updateFaults(s, at)
+}
+
+// recordErr records that the value of a sensor could not be read.
+// Values before and after the error are not compared to each other.
+func (g *group) recordErr(ctx context.Context, name string, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s, ok := g.sensors[name]
+	if !ok {
+		return
+	}
+	s.hasValue = false
+	s.changedAt = time.Time{}
+	s.check.UpdateReliability(ctx, healthpb.ReliabilityFromErr(err))
+}
+
+// evaluate updates the faults of all sensors at now, for faults that depend on the passage of time.
+func (g *group) evaluate(now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, s := range g.sensors {
+		g.updateFaults(s, now)
+	}
+}
+
+func (g *group) updateFaults(s *sensor, now time.Time) {
+	if !s.hasValue {
+		// keep existing faults until we know more
+		return
+	}
+	want := make(map[string]*gen.HealthCheck_Error)
+	if e := g.stuckFault(s, now); e != nil {
+		want[CodeStuck] = e
+	}
+	if e := g.spikeFault(s, now); e != nil {
+		want[CodeSpike] = e
+	}
+	if e := g.rangeFault(s); e != nil {
+		want[CodeOutOfRange] = e
+	}
+	for _, code := range faultCodes {
+		old, had := s.faults[code]
+		e, has := want[code]
+		switch {
+		case has && (!had || old.GetDetailsText() != e.GetDetailsText()):
+			s.check.AddOrUpdateFault(e)
+			s.faults[code] = e
+		case !has && had:
+			s.check.RemoveFault(&gen.HealthCheck_Error{Code: faultCode(code)})
+			delete(s.faults, code)
+		}
+	}
+}
+
+func (g *group) stuckFault(s *sensor, now time.Time) *gen.HealthCheck_Error {
+	cfg := g.cfg.Stuck
+	if cfg == nil {
+		return nil
+	}
+	if now.Sub(s.refAt) < cfg.After.Duration {
+		return nil
+	}
+	var peers, changing int
+	for _, p := range g.sensors {
+		if p == s || !p.hasValue {
+			continue
+		}
+		peers++
+		if !p.changedAt.Before(s.refAt) {
+			changing++
+		}
+	}
+	if cfg.MinChangingPeers >= 0 && changing < cfg.MinChangingPeers {
+		return nil
+	}
+	details := fmt.Sprintf("Value %s has not changed by more than %s since %s.",
+		formatValue(s.ref), formatValue(g.stuckEpsilon()), formatTime(s.refAt))
+	if peers > 0 {
+		details += fmt.Sprintf(" %d of %d comparable sensors changed in that time.", changing, peers)
+	}
+	return &gen.HealthCheck_Error{
+		SummaryText: "Value stuck",
+		DetailsText: details,
+		Code:        faultCode(CodeStuck),
+	}
+}
+
+func (g *group) spikeFault(s *sensor, now time.Time) *gen.HealthCheck_Error {
+	cfg := g.cfg.Spike
+	if cfg == nil || s.spike == nil {
+		return nil
+	}
+	if now.Sub(s.spike.toAt) >= cfg.HoldOrDefault() {
+		s.spike = nil
+		return nil
+	}
+	return &gen.HealthCheck_Error{
+		SummaryText: "Value spiked",
+		DetailsText: fmt.Sprintf("Value changed from %s at %s to %s at %s, more than the possible %s per %s.",
+			formatValue(s.spike.from), formatTime(s.spike.fromAt), formatValue(s.spike.to), formatTime(s.spike.toAt),
+			formatValue(cfg.MaxChange), cfg.PerOrDefault()),
+		Code: faultCode(CodeSpike),
+	}
+}
+
+func (g *group) rangeFault(s *sensor) *gen.HealthCheck_Error {
+	cfg := g.cfg.Range
+	if cfg == nil {
+		return nil
+	}
+	var details string
+	switch {
+	case cfg.Min != nil && s.value < *cfg.Min:
+		details = fmt.Sprintf("Value %s at %s is less than the minimum possible %s.", formatValue(s.value), formatTime(s.at), formatValue(*cfg.Min))
+	case cfg.Max != nil && s.value > *cfg.Max:
+		details = fmt.Sprintf("Value %s at %s is more than the maximum possible %s.", formatValue(s.value), formatTime(s.at), formatValue(*cfg.Max))
+	default:
+		return nil
+	}
+	return &gen.HealthCheck_Error{
+		SummaryText: "Value out of range",
+		DetailsText: details,
+		Code:        faultCode(CodeOutOfRange),
+	}
+}
+
+func (g *group) stuckEpsilon() float64 {
+	if g.cfg.Stuck == nil {
+		return 0
+	}
+	return g.cfg.Stuck.Epsilon
+}
+
+// isSpike returns whether changing from old to v over dt is faster than cfg allows.
+// Changes over less than cfg.Per are compared to cfg.MaxChange directly, so noise between frequent reports is not a spike.
+func isSpike(cfg *config.Spike, old, v float64, dt time.Duration) bool {
+	per := cfg.PerOrDefault()
+	change := math.Abs(v - old)
+	if dt > per {
+		change = change * float64(per) / float64(dt)
+	}
+	return change > cfg.MaxChange
+}
+
+func faultCode(code string) *gen.HealthCheck_Error_Code {
+	return &gen.HealthCheck_Error_Code{Code: code, System: SystemName}
+}
+
+func formatValue(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,162 @@
+package sensorfault
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto/sensorfault/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+func TestGroup_stuck(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	g := newGroup(config.Root{Stuck: &config.Stuck{
+		After:            jsontypes.Duration{Duration: time.Hour},
+		Epsilon:          0.1,
+		MinChangingPeers: 1,
+	}})
+	a, b := &testCheck{}, &testCheck{}
+	g.add("a", a)
+	g.add("b", b)
+
+	g.record(ctx, "a", 20, start)
+	g.record(ctx, "b", 20, start)
+	for i := 1; i <= 12; i++ {
+		at := start.Add(time.Duration(i) * 10 * time.Minute)
+		g.record(ctx, "a", 20+float64(i%2)*0.05, at) // noise within epsilon
+		g.record(ctx, "b", 20+float64(i), at)
+	}
+	g.evaluate(start.Add(2 * time.Hour))
+	a.assertFaults(t, CodeStuck)
+	b.assertFaults(t)
+	if want := "Value 20 has not changed by more than 0.1 since 2024-01-01T09:00:00Z. 1 of 1 comparable sensors changed in that time."; a.faults[0].GetDetailsText() != want {
+		t.Errorf("details got %q, want %q", a.faults[0].GetDetailsText(), want)
+	}
+
+	g.record(ctx, "a", 21, start.Add(2*time.Hour))
+	a.assertFaults(t)
+
+	// errors mean we can't tell whether the value changed
+	g.recordErr(ctx, "a", errors.New("boom"))
+	if a.reliability.GetState() == gen.HealthCheck_Reliability_RELIABLE {
+		t.Errorf("want unreliable after error")
+	}
+	g.record(ctx, "a", 21, start.Add(4*time.Hour))
+	if a.reliability.GetState() != gen.HealthCheck_Reliability_RELIABLE {
+		t.Errorf("want reliable after value, got %v", a.reliability.GetState())
+	}
+	g.evaluate(start.Add(4*time.Hour + 30*time.Minute))
+	a.assertFaults(t)
+}
+
+func TestGroup_stuck_peers(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	cfg := config.Root{Stuck: &config.Stuck{After: jsontypes.Duration{Duration: time.Hour}, MinChangingPeers: 1}}
+
+	// all sensors unchanged, like in an empty building
+	g := newGroup(cfg)
+	a, b := &testCheck{}, &testCheck{}
+	g.add("a", a)
+	g.add("b", b)
+	g.record(ctx, "a", 20, start)
+	g.record(ctx, "b", 18, start)
+	g.evaluate(start.Add(2 * time.Hour))
+	a.assertFaults(t)
+	b.assertFaults(t)
+
+	// peers not needed
+	cfg.Stuck.MinChangingPeers = -1
+	g = newGroup(cfg)
+	a = &testCheck{}
+	g.add("a", a)
+	g.record(ctx, "a", 20, start)
+	g.evaluate(start.Add(59 * time.Minute))
+	a.assertFaults(t)
+	g.evaluate(start.Add(time.Hour))
+	a.assertFaults(t, CodeStuck)
+}
+
+func TestGroup_spike(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	g := newGroup(config.Root{Spike: &config.Spike{
+		MaxChange: 2,
+		Per:       &jsontypes.Duration{Duration: time.Minute},
+		Hold:      &jsontypes.Duration{Duration: 10 * time.Minute},
+	}})
+	a := &testCheck{}
+	g.add("a", a)
+
+	g.record(ctx, "a", 20, start)
+	g.record(ctx, "a", 21.5, start.Add(10*time.Second)) // within max change
+	a.assertFaults(t)
+	g.record(ctx, "a", 30, start.Add(time.Hour)) // large but slow change
+	a.assertFaults(t)
+	g.record(ctx, "a", 50, start.Add(time.Hour+5*time.Minute)) // 4 per minute
+	a.assertFaults(t, CodeSpike)
+	g.record(ctx, "a", 50, start.Add(time.Hour+10*time.Minute))
+	a.assertFaults(t, CodeSpike)
+	g.evaluate(start.Add(time.Hour + 15*time.Minute))
+	a.assertFaults(t)
+}
+
+func TestGroup_range(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	minV, maxV := -40.0, 85.0
+	g := newGroup(config.Root{Range: &config.Range{Min: &minV, Max: &maxV}})
+	a := &testCheck{}
+	g.add("a", a)
+
+	g.record(ctx, "a", 20, start)
+	a.assertFaults(t)
+	g.record(ctx, "a", 127.5, start.Add(time.Minute))
+	a.assertFaults(t, CodeOutOfRange)
+	g.record(ctx, "a", -50, start.Add(2*time.Minute))
+	a.assertFaults(t, CodeOutOfRange)
+	if want := "Value -50 at 2024-01-01T09:02:00Z is less than the minimum possible -40."; a.faults[0].GetDetailsText() != want {
+		t.Errorf("details got %q, want %q", a.faults[0].GetDetailsText(), want)
+	}
+	g.record(ctx, "a", 21, start.Add(3*time.Minute))
+	a.assertFaults(t)
+}
+
+// testCheck records the faults and reliability reported to it.
+type testCheck struct {
+	faults      []*gen.HealthCheck_Error
+	reliability *gen.HealthCheck_Reliability
+}
+
+func (c *testCheck) AddOrUpdateFault(err *gen.HealthCheck_Error) {
+	c.RemoveFault(err)
+	c.faults = append(c.faults, err)
+}
+
+func (c *testCheck) RemoveFault(err *gen.HealthCheck_Error) {
+	c.faults = slices.DeleteFunc(c.faults, func(e *gen.HealthCheck_Error) bool {
+		return e.GetCode().GetCode() == err.GetCode().GetCode()
+	})
+}
+
+func (c *testCheck) UpdateReliability(_ context.Context, r *gen.HealthCheck_Reliability) {
+	c.reliability = r
+}
+
+func (c *testCheck) assertFaults(t *testing.T, codes ...string) {
+	t.Helper()
+	var got []string
+	for _, f := range c.faults {
+		got = append(got, f.GetCode().GetCode())
+	}
+	slices.Sort(got)
+	slices.Sort(codes)
+	if !slices.Equal(got, codes) {
+		t.Errorf("faults got %v, want %v", got, codes)
+	}
+}
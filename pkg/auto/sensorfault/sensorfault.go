@@ -0,0 +1,153 @@
+// Package sensorfault provides an automation that detects faulty sensors, reporting them as faults on a health check
+// for each sensor. Sensors are faulty if their value is stuck, unchanged while comparable sensors change, spikes
+// faster than is physically possible, or is outside the range of possible values.
+package sensorfault
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto"
+	"github.com/smart-core-os/sc-bos/pkg/auto/sensorfault/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/task"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+	"github.com/smart-core-os/sc-bos/pkg/util/pull"
+)
+
+const AutoName = "sensorfault"
+
+// evaluateInterval is how often faults that depend on the passage of time, like stuck values, are checked.
+const evaluateInterval = time.Minute
+
+var Factory auto.Factory = factory{}
+
+type factory struct{}
+
+func (f factory) New(services auto.Services) service.Lifecycle {
+	a := &autoImpl{Services: services}
+	a.Service = service.New(service.MonoApply(a.applyConfig), service.WithParser(config.ReadBytes))
+	a.Logger = a.Logger.Named(AutoName)
+	return a
+}
+
+type autoImpl struct {
+	*service.Service[config.Root]
+	auto.Services
+}
+
+func (a *autoImpl) applyConfig(ctx context.Context, cfg config.Root) error {
+	devicesMask, err := fieldmaskpb.New(&gen.Device{}, "name")
+	if err != nil {
+		return err
+	}
+	src, err := newSource(cfg.Source)
+	if err != nil {
+		return err
+	}
+	now := a.Now
+	if now == nil {
+		now = time.Now
+	}
+	g := newGroup(cfg)
+
+	go func() {
+		ticker := time.NewTicker(evaluateInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				g.evaluate(now())
+			}
+		}
+	}()
+
+	go func() {
+		running := make(map[string]context.CancelFunc)
+		defer func() {
+			for _, stop := range running {
+				stop()
+			}
+		}()
+		// the task is configured to retry forever (until ctx is done) so the error is ignored.
+		_ = task.Run(ctx, func(ctx context.Context) (task.Next, error) {
+			stream, err := a.Devices.PullDevices(ctx, &gen.PullDevicesRequest{
+				ReadMask: devicesMask,
+				Query:    &gen.Device_Query{Conditions: cfg.DevicesPb()},
+			})
+			if err != nil {
+				return task.Normal, err
+			}
+			for {
+				res, err := stream.Recv()
+				if err != nil {
+					return task.ResetBackoff, err
+				}
+				for _, change := range res.GetChanges() {
+					ov, nv := change.GetOldValue(), change.GetNewValue()
+					name := change.GetName()
+					switch {
+					case ov == nil && nv != nil:
+						if _, ok := running[name]; ok {
+							continue
+						}
+						check, err := a.Health.NewFaultCheck(name, proto.Clone(cfg.CheckPb()).(*gen.HealthCheck))
+						if err != nil {
+							a.Logger.Error("failed to create health check", zap.String("device", name), zap.Error(err))
+							continue
+						}
+						g.add(name, check)
+						dctx, stop := context.WithCancel(ctx)
+						running[name] = func() {
+							stop()
+							g.remove(name)
+							check.Dispose()
+						}
+						go a.watch(dctx, g, src, name, now)
+					case ov != nil && nv == nil:
+						if stop, ok := running[name]; ok {
+							stop()
+							delete(running, name)
+						}
+					}
+				}
+			}
+		}, task.WithRetry(task.RetryUnlimited), task.WithBackoff(100*time.Millisecond, time.Minute))
+	}()
+	return nil
+}
+
+// watch records the values of the named sensor in g until ctx is done.
+func (a *autoImpl) watch(ctx context.Context, g *group, src source, name string, now func() time.Time) {
+	logger := a.Logger.With(zap.String("device", name))
+	changes := make(chan reading)
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		defer close(changes)
+		return pull.Changes(ctx, src.fetcher(a.Node.ClientConn(), name), changes, pull.WithLogger(logger))
+	})
+	eg.Go(func() error {
+		for change := range changes {
+			err := change.err
+			if err == nil {
+				var v float64
+				if v, err = src.value(change.value); err == nil {
+					g.record(ctx, name, v, now())
+					continue
+				}
+			}
+			logger.Debug("failed to read value", zap.Error(err))
+			g.recordErr(ctx, name, fmt.Errorf("failed to read %s from %q: %w", src, name, err))
+		}
+		return nil
+	})
+	_ = eg.Wait()
+}
@@ -0,0 +1,127 @@
+package sensorfault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/smart-core-os/sc-bos/internal/protobuf/protopath2"
+	healthbounds "github.com/smart-core-os/sc-bos/pkg/auto/healthbounds/config"
+	"github.com/smart-core-os/sc-bos/pkg/auto/internal/anytrait"
+	"github.com/smart-core-os/sc-bos/pkg/util/pull"
+)
+
+var errNoValue = errors.New("no value")
+
+// source reads a numeric value from a trait resource of devices.
+type source struct {
+	cfg       healthbounds.Source
+	resource  anytrait.Resource
+	path      protopath.Path
+	fieldMask *fieldmaskpb.FieldMask
+}
+
+func newSource(cfg healthbounds.Source) (source, error) {
+	t, err := anytrait.FindByName(cfg.Trait)
+	if err != nil {
+		return source{}, fmt.Errorf("%s: %w", cfg.Trait, err)
+	}
+	var r anytrait.Resource
+	for _, res := range t.Resources() {
+		if cfg.Resource == "" || string(cfg.Resource) == res.Name() {
+			r = res
+			break
+		}
+	}
+	if r.Name() == "" {
+		return source{}, fmt.Errorf("trait %q has no resource %q", cfg.Trait, cfg.Resource)
+	}
+	p, fm, err := cfg.Value.Parse(r.Message())
+	if err != nil {
+		return source{}, fmt.Errorf("source value path %q not found in %s[%s]: %w", cfg.Value, cfg.Trait, r.Name(), err)
+	}
+	return source{cfg: cfg, resource: r, path: p, fieldMask: fm}, nil
+}
+
+func (s source) String() string {
+	return fmt.Sprintf("%s.%s[%q]", s.cfg.Trait, s.resource.Name(), s.cfg.Value)
+}
+
+// reading is a value read from a device, or the error reading it.
+type reading struct {
+	value anytrait.Value
+	err   error
+}
+
+// fetcher returns a fetcher of the resource of the named device, using pull or get depending on device support.
+// Errors are sent as readings too, so the sensor isn't thought to be stuck while it can't be read.
+func (s source) fetcher(conn grpc.ClientConnInterface, name string) pull.Fetcher[reading] {
+	req := anytrait.ReadRequest{Name: name, ReadMask: s.fieldMask}
+	return pull.NewFetcher(
+		func(ctx context.Context, changes chan<- reading) error {
+			err := s.pull(ctx, conn, req, changes)
+			if err != nil {
+				send(ctx, changes, reading{err: err})
+			}
+			return err
+		},
+		func(ctx context.Context, changes chan<- reading) error {
+			value, err := s.resource.Get(ctx, conn, anytrait.GetRequest{ReadRequest: req})
+			send(ctx, changes, reading{value: value, err: err})
+			return err
+		},
+	)
+}
+
+func (s source) pull(ctx context.Context, conn grpc.ClientConnInterface, req anytrait.ReadRequest, changes chan<- reading) error {
+	stream, err := s.resource.Pull(ctx, conn, anytrait.PullRequest{ReadRequest: req})
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		for _, change := range res.Changes {
+			send(ctx, changes, reading{value: change.Value})
+		}
+	}
+}
+
+func send(ctx context.Context, changes chan<- reading, r reading) {
+	select {
+	case <-ctx.Done():
+	case changes <- r:
+	}
+}
+
+// value extracts the numeric value from v.
+func (s source) value(v anytrait.Value) (float64, error) {
+	values, err := protopath2.PathValues(s.path, v.Proto())
+	if err != nil {
+		return 0, err
+	}
+	switch goValue := values.Index(-1).Value.Interface().(type) {
+	case nil:
+		return 0, errNoValue
+	case int32:
+		return float64(goValue), nil
+	case int64:
+		return float64(goValue), nil
+	case uint32:
+		return float64(goValue), nil
+	case uint64:
+		return float64(goValue), nil
+	case float32:
+		return float64(goValue), nil
+	case float64:
+		return goValue, nil
+	default:
+		return 0, fmt.Errorf("value is not a number: %T", goValue)
+	}
+}
@@ -0,0 +1,141 @@
+package servicetickets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-api/go/types"
+	"github.com/smart-core-os/sc-bos/pkg/auto/servicetickets/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const alertKeyPrefix = "alert:"
+
+// watchAlerts raises tickets for unresolved alerts and resolves them when the alerts are resolved.
+// Returns when ctx is done or the alert stream fails.
+func (a *autoImpl) watchAlerts(ctx context.Context, client gen.AlertApiClient, cfg config.Root, t *tracker) error {
+	minSeverity, _ := config.ParseSeverity(cfg.Alerts.MinSeverity) // validated when parsed
+	source := cfg.Alerts.Source
+	if source == "" {
+		source = a.Node.Name()
+	}
+	resolved := false
+	query := &gen.Alert_Query{
+		SeverityNotBelow: int32(minSeverity),
+		Floor:            cfg.Alerts.Floor,
+		Zone:             cfg.Alerts.Zone,
+		Subsystem:        cfg.Alerts.Subsystem,
+		Resolved:         &resolved,
+	}
+
+	// start the stream before listing so we don't miss any changes in between
+	stream, err := client.PullAlerts(ctx, &gen.PullAlertsRequest{Name: source, Query: query})
+	if err != nil {
+		return err
+	}
+
+	open := make(map[string]*gen.Alert)
+	req := &gen.ListAlertsRequest{Name: source, Query: query, PageSize: 1000}
+	for {
+		res, err := client.ListAlerts(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, alert := range res.Alerts {
+			open[alert.Id] = alert
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
+	}
+	// tickets raised for alerts that were resolved while we weren't watching
+	for key, refs := range t.keys(alertKeyPrefix) {
+		for _, ref := range refs {
+			if _, ok := open[ref]; ok {
+				continue
+			}
+			if err := t.resolve(ctx, key, ref); err != nil {
+				a.Logger.Warn("failed to resolve ticket", zap.String("key", key), zap.Error(err))
+			}
+		}
+	}
+	for _, alert := range open {
+		a.raiseAlert(ctx, cfg, t, alert)
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		for _, change := range res.Changes {
+			switch change.Type {
+			case types.ChangeType_ADD, types.ChangeType_UPDATE, types.ChangeType_REPLACE:
+				if change.NewValue.GetResolveTime() != nil {
+					a.resolveAlert(ctx, t, change.NewValue)
+				} else {
+					a.raiseAlert(ctx, cfg, t, change.NewValue)
+				}
+			case types.ChangeType_REMOVE:
+				a.resolveAlert(ctx, t, change.OldValue)
+			}
+		}
+	}
+}
+
+func (a *autoImpl) raiseAlert(ctx context.Context, cfg config.Root, t *tracker, alert *gen.Alert) {
+	iss := alertIssue(cfg, alert)
+	if err := t.raise(ctx, iss); err != nil {
+		a.Logger.Warn("failed to raise ticket for alert", zap.String("alertId", alert.Id), zap.Error(err))
+	}
+}
+
+func (a *autoImpl) resolveAlert(ctx context.Context, t *tracker, alert *gen.Alert) {
+	if alert == nil {
+		return
+	}
+	if err := t.resolve(ctx, alertKey(alert), alert.Id); err != nil {
+		a.Logger.Warn("failed to resolve ticket for alert", zap.String("alertId", alert.Id), zap.Error(err))
+	}
+}
+
+// alertKey de-duplicates alerts, the same problem reported by the same source shares a ticket.
+func alertKey(alert *gen.Alert) string {
+	return alertKeyPrefix + alert.Source + ":" + alert.Description
+}
+
+func alertIssue(cfg config.Root, alert *gen.Alert) issue {
+	summary := alert.Description
+	if alert.Source != "" {
+		summary = fmt.Sprintf("%s: %s", alert.Source, alert.Description)
+	}
+	var desc strings.Builder
+	fmt.Fprintf(&desc, "%s\n\nSeverity: %s", alert.Description, alert.Severity)
+	if alert.Source != "" {
+		fmt.Fprintf(&desc, "\nSource: %s", alert.Source)
+	}
+	if alert.Floor != "" {
+		fmt.Fprintf(&desc, "\nFloor: %s", alert.Floor)
+	}
+	if alert.Zone != "" {
+		fmt.Fprintf(&desc, "\nZone: %s", alert.Zone)
+	}
+	if alert.Subsystem != "" {
+		fmt.Fprintf(&desc, "\nSubsystem: %s", alert.Subsystem)
+	}
+	if ct := alert.GetCreateTime(); ct != nil {
+		fmt.Fprintf(&desc, "\nRaised: %s", ct.AsTime().Format("2006-01-02 15:04:05Z07:00"))
+	}
+	return issue{
+		key:            alertKey(alert),
+		ref:            alert.Id,
+		summary:        summary,
+		description:    desc.String(),
+		classification: cfg.Classifications.Classification(alert.Subsystem),
+		severity:       cfg.Severity(alert.Severity),
+	}
+}
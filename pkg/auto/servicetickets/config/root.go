@@ -0,0 +1,140 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const DefaultReporterName = "Smart Core"
+
+func ReadBytes(data []byte) (cfg Root, err error) {
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return
+	}
+	if cfg.ReporterName == "" {
+		cfg.ReporterName = DefaultReporterName
+	}
+	if cfg.Alerts != nil && cfg.Alerts.MinSeverity == "" {
+		cfg.Alerts.MinSeverity = gen.Alert_WARNING.String()
+	}
+	if cfg.HealthChecks != nil && cfg.HealthChecks.Severity == "" {
+		cfg.HealthChecks.Severity = gen.Alert_WARNING.String()
+	}
+	return cfg, cfg.validate()
+}
+
+type Root struct {
+	auto.Config
+	// Name of the device tickets are raised against.
+	// Must implement ServiceTicketApi.
+	Destination string `json:"destination,omitempty"`
+	// Recorded as the reporter of each ticket.
+	// Defaults to DefaultReporterName.
+	ReporterName string `json:"reporterName,omitempty"`
+
+	// Raise tickets for unresolved alerts.
+	// Nil disables alert tickets.
+	Alerts *Alerts `json:"alerts,omitempty"`
+	// Raise tickets for abnormal health checks.
+	// Nil disables health check tickets.
+	HealthChecks *HealthChecks `json:"healthChecks,omitempty"`
+
+	// Severities maps alert severity names, like "WARNING" or "SEVERE", to the ticket severity id to use.
+	// Alerts that fall between configured severities use the highest configured severity below the alert severity.
+	// If empty, or no configured severity is at or below the alert severity, the ticket has no severity.
+	Severities map[string]string `json:"severities,omitempty"`
+	// Classifications selects the ticket classification id.
+	Classifications Classifications `json:"classifications,omitempty"`
+}
+
+type Alerts struct {
+	// Name of the device implementing AlertApi.
+	// Defaults to the name of this node.
+	Source string `json:"source,omitempty"`
+	// Only alerts at or above this severity raise tickets.
+	// Defaults to WARNING.
+	MinSeverity string `json:"minSeverity,omitempty"`
+	// Only alerts matching these properties raise tickets, if set.
+	Floor     string `json:"floor,omitempty"`
+	Zone      string `json:"zone,omitempty"`
+	Subsystem string `json:"subsystem,omitempty"`
+}
+
+type HealthChecks struct {
+	// The alert severity name to use for abnormal health checks when mapping to a ticket severity.
+	// Defaults to WARNING.
+	Severity string `json:"severity,omitempty"`
+	// Device name prefixes to ignore.
+	IgnorePrefixes []string `json:"ignorePrefixes,omitempty"`
+}
+
+type Classifications struct {
+	// Classification id used when no other rule matches.
+	Default string `json:"default,omitempty"`
+	// Classification id by subsystem, of the alert or the devices membership.
+	Subsystems map[string]string `json:"subsystems,omitempty"`
+}
+
+// Classification returns the classification id for an issue in the given subsystem.
+func (c Classifications) Classification(subsystem string) string {
+	if id, ok := c.Subsystems[subsystem]; ok && subsystem != "" {
+		return id
+	}
+	return c.Default
+}
+
+// Severity returns the ticket severity id for an alert of severity s.
+func (c Root) Severity(s gen.Alert_Severity) string {
+	type entry struct {
+		severity int32
+		id       string
+	}
+	var entries []entry
+	for name, id := range c.Severities {
+		entries = append(entries, entry{gen.Alert_Severity_value[name], id})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].severity > entries[j].severity })
+	for _, e := range entries {
+		if e.severity <= int32(s) {
+			return e.id
+		}
+	}
+	return ""
+}
+
+// ParseSeverity returns the alert severity with the given name.
+func ParseSeverity(name string) (gen.Alert_Severity, error) {
+	v, ok := gen.Alert_Severity_value[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown alert severity %q", name)
+	}
+	return gen.Alert_Severity(v), nil
+}
+
+func (c Root) validate() error {
+	if c.Destination == "" {
+		return errors.New("destination is required")
+	}
+	for name := range c.Severities {
+		if _, err := ParseSeverity(name); err != nil {
+			return fmt.Errorf("severities: %w", err)
+		}
+	}
+	if c.Alerts != nil {
+		if _, err := ParseSeverity(c.Alerts.MinSeverity); err != nil {
+			return fmt.Errorf("alerts.minSeverity: %w", err)
+		}
+	}
+	if c.HealthChecks != nil {
+		if _, err := ParseSeverity(c.HealthChecks.Severity); err != nil {
+			return fmt.Errorf("healthChecks.severity: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package servicetickets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto/servicetickets/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const healthKeyPrefix = "health:"
+
+// watchHealth raises tickets for abnormal health checks and resolves them when the checks return to normal.
+// Returns when ctx is done or the device stream fails.
+func (a *autoImpl) watchHealth(ctx context.Context, cfg config.Root, t *tracker) error {
+	severity, _ := config.ParseSeverity(cfg.HealthChecks.Severity) // validated when parsed
+	readMask, err := fieldmaskpb.New(&gen.Device{}, "name", "metadata.location", "metadata.membership", "health_checks")
+	if err != nil {
+		return err
+	}
+	ignored := func(name string) bool {
+		for _, prefix := range cfg.HealthChecks.IgnorePrefixes {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// start the stream before listing so we don't miss any changes in between
+	stream, err := a.Devices.PullDevices(ctx, &gen.PullDevicesRequest{ReadMask: readMask, UpdatesOnly: true})
+	if err != nil {
+		return err
+	}
+
+	abnormal := make(map[string]issue)
+	req := &gen.ListDevicesRequest{ReadMask: readMask, PageSize: 1000}
+	for {
+		res, err := a.Devices.ListDevices(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, device := range res.Devices {
+			if ignored(device.Name) {
+				continue
+			}
+			for _, iss := range healthIssues(cfg, severity, device) {
+				abnormal[iss.key] = iss
+			}
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
+	}
+	// tickets raised for checks that returned to normal while we weren't watching
+	for key := range t.keys(healthKeyPrefix) {
+		if _, ok := abnormal[key]; ok {
+			continue
+		}
+		if err := t.resolve(ctx, key, key); err != nil {
+			a.Logger.Warn("failed to resolve ticket", zap.String("key", key), zap.Error(err))
+		}
+	}
+	for _, iss := range abnormal {
+		a.raiseHealth(ctx, t, iss)
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		for _, change := range res.Changes {
+			if ignored(change.Name) {
+				continue
+			}
+			was := make(map[string]bool)
+			for _, iss := range healthIssues(cfg, severity, change.OldValue) {
+				was[iss.key] = true
+			}
+			for _, iss := range healthIssues(cfg, severity, change.NewValue) {
+				delete(was, iss.key)
+				a.raiseHealth(ctx, t, iss)
+			}
+			for key := range was {
+				if err := t.resolve(ctx, key, key); err != nil {
+					a.Logger.Warn("failed to resolve ticket", zap.String("key", key), zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+func (a *autoImpl) raiseHealth(ctx context.Context, t *tracker, iss issue) {
+	if err := t.raise(ctx, iss); err != nil {
+		a.Logger.Warn("failed to raise ticket for health check", zap.String("key", iss.key), zap.Error(err))
+	}
+}
+
+// healthIssues returns an issue for each abnormal health check of device.
+func healthIssues(cfg config.Root, severity gen.Alert_Severity, device *gen.Device) []issue {
+	var res []issue
+	for _, check := range device.GetHealthChecks() {
+		if check.GetNormality() <= gen.HealthCheck_NORMAL {
+			continue
+		}
+		key := healthKeyPrefix + device.Name + ":" + check.Id
+		title := check.DisplayName
+		if title == "" {
+			title = check.Id
+		}
+		var desc strings.Builder
+		if check.Description != "" {
+			fmt.Fprintf(&desc, "%s\n\n", check.Description)
+		}
+		fmt.Fprintf(&desc, "Device: %s\nNormality: %s", device.Name, check.Normality)
+		if loc := device.GetMetadata().GetLocation(); loc != nil {
+			if loc.Floor != "" {
+				fmt.Fprintf(&desc, "\nFloor: %s", loc.Floor)
+			}
+			if loc.Zone != "" {
+				fmt.Fprintf(&desc, "\nZone: %s", loc.Zone)
+			}
+		}
+		if check.OccupantImpact > gen.HealthCheck_NO_OCCUPANT_IMPACT {
+			fmt.Fprintf(&desc, "\nOccupant impact: %s", check.OccupantImpact)
+		}
+		if check.EquipmentImpact > gen.HealthCheck_NO_EQUIPMENT_IMPACT {
+			fmt.Fprintf(&desc, "\nEquipment impact: %s", check.EquipmentImpact)
+		}
+		if at := check.GetAbnormalTime(); at != nil {
+			fmt.Fprintf(&desc, "\nAbnormal since: %s", at.AsTime().Format("2006-01-02 15:04:05Z07:00"))
+		}
+		res = append(res, issue{
+			key:            key,
+			ref:            key,
+			summary:        fmt.Sprintf("%s: %s", device.Name, title),
+			description:    desc.String(),
+			classification: cfg.Classifications.Classification(device.GetMetadata().GetMembership().GetSubsystem()),
+			severity:       cfg.Severity(severity),
+		})
+	}
+	return res
+}
@@ -0,0 +1,76 @@
+// Package servicetickets provides an automation that raises service tickets for unresolved alerts and abnormal health checks.
+// Tickets are created via a ServiceTicketApi device, typically a CAFM driver, and updated when the problem is resolved.
+package servicetickets
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto"
+	"github.com/smart-core-os/sc-bos/pkg/auto/servicetickets/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/task"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+)
+
+const AutoName = "servicetickets"
+
+var Factory auto.Factory = factory{}
+
+type factory struct{}
+
+func (f factory) New(services auto.Services) service.Lifecycle {
+	a := &autoImpl{Services: services}
+	a.Service = service.New(service.MonoApply(a.applyConfig), service.WithParser(config.ReadBytes))
+	a.Logger = a.Logger.Named(AutoName)
+	return a
+}
+
+type autoImpl struct {
+	*service.Service[config.Root]
+	auto.Services
+}
+
+func (a *autoImpl) applyConfig(ctx context.Context, cfg config.Root) error {
+	logger := a.Logger.With(zap.String("destination", cfg.Destination))
+	now := a.Now
+	if now == nil {
+		now = time.Now
+	}
+	conn := a.Node.ClientConn()
+	t := newTracker(gen.NewServiceTicketApiClient(conn), cfg.Destination, cfg.ReporterName, cfg.Name, a.Database, logger, now)
+	if err := t.load(); err != nil {
+		return err
+	}
+
+	go func() {
+		// titles are nice to have, don't hold up raising tickets waiting for them
+		support, err := gen.NewServiceTicketInfoClient(conn).DescribeTicket(ctx, &gen.DescribeTicketRequest{Name: cfg.Destination})
+		if err != nil {
+			logger.Debug("ticket support unavailable", zap.Error(err))
+			return
+		}
+		t.setSupport(support)
+	}()
+
+	if cfg.Alerts != nil {
+		client := gen.NewAlertApiClient(conn)
+		go func() {
+			// retries forever, until ctx is done
+			_ = task.Run(ctx, func(ctx context.Context) (task.Next, error) {
+				return task.ResetBackoff, a.watchAlerts(ctx, client, cfg, t)
+			}, task.WithRetry(task.RetryUnlimited), task.WithBackoff(time.Second, time.Minute), task.WithErrorLogger(logger.Named("alerts")))
+		}()
+	}
+	if cfg.HealthChecks != nil {
+		go func() {
+			// retries forever, until ctx is done
+			_ = task.Run(ctx, func(ctx context.Context) (task.Next, error) {
+				return task.ResetBackoff, a.watchHealth(ctx, cfg, t)
+			}, task.WithRetry(task.RetryUnlimited), task.WithBackoff(time.Second, time.Minute), task.WithErrorLogger(logger.Named("health")))
+		}()
+	}
+	return nil
+}
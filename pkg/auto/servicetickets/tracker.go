@@ -0,0 +1,213 @@
+package servicetickets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/timshannon/bolthold"
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// issue is a problem that should have a ticket raised for it.
+type issue struct {
+	// key identifies the problem, issues with the same key share a ticket.
+	key string
+	// ref identifies the specific occurrence of the problem, an alert id for example.
+	// The ticket is resolved once all refs have been resolved.
+	ref            string
+	summary        string
+	description    string
+	classification string // ticket classification id
+	severity       string // ticket severity id
+}
+
+// ticketRecord is what we remember about a ticket we raised, persisted so restarts don't raise duplicate tickets.
+type ticketRecord struct {
+	Scope          string // the name of the automation that raised the ticket
+	Key            string
+	TicketID       string
+	Summary        string
+	Description    string
+	Classification string
+	Severity       string
+	Refs           []string
+	CreateTime     time.Time
+}
+
+// tracker raises and resolves tickets, making sure at most one ticket is open for each issue key.
+type tracker struct {
+	client   gen.ServiceTicketApiClient
+	name     string // name of the ServiceTicketApi device
+	reporter string
+	scope    string
+	db       *bolthold.Store // may be nil, records are then only kept in memory
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex // serialises ticket changes, guards all fields below
+	open    map[string]*ticketRecord
+	support *gen.TicketSupport
+}
+
+func newTracker(client gen.ServiceTicketApiClient, name, reporter, scope string, db *bolthold.Store, logger *zap.Logger, now func() time.Time) *tracker {
+	return &tracker{
+		client:   client,
+		name:     name,
+		reporter: reporter,
+		scope:    scope,
+		db:       db,
+		logger:   logger,
+		now:      now,
+		open:     make(map[string]*ticketRecord),
+	}
+}
+
+// load reads any records persisted by a previous run.
+func (t *tracker) load() error {
+	if t.db == nil {
+		return nil
+	}
+	var records []*ticketRecord
+	if err := t.db.Find(&records, bolthold.Where("Scope").Eq(t.scope)); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range records {
+		t.open[r.Key] = r
+	}
+	return nil
+}
+
+// setSupport records the classifications and severities supported by the destination,
+// used to populate the titles of the ticket classification and severity.
+func (t *tracker) setSupport(s *gen.TicketSupport) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.support = s
+}
+
+// keys returns the keys of all open tickets that start with prefix.
+func (t *tracker) keys(prefix string) map[string][]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := make(map[string][]string)
+	for k, r := range t.open {
+		if len(k) >= len(prefix) && k[:len(prefix)] == prefix {
+			res[k] = slices.Clone(r.Refs)
+		}
+	}
+	return res
+}
+
+// raise creates a ticket for iss, unless one is already open for the issue key.
+func (t *tracker) raise(ctx context.Context, iss issue) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r, ok := t.open[iss.key]; ok {
+		if slices.Contains(r.Refs, iss.ref) {
+			return nil
+		}
+		r.Refs = append(r.Refs, iss.ref)
+		return t.save(r)
+	}
+
+	r := &ticketRecord{
+		Scope:          t.scope,
+		Key:            iss.key,
+		Summary:        iss.summary,
+		Description:    iss.description,
+		Classification: iss.classification,
+		Severity:       iss.severity,
+		Refs:           []string{iss.ref},
+		CreateTime:     t.now(),
+	}
+	created, err := t.client.CreateTicket(ctx, &gen.CreateTicketRequest{Name: t.name, Ticket: t.ticket(r)})
+	if err != nil {
+		return fmt.Errorf("create ticket: %w", err)
+	}
+	r.TicketID = created.GetId()
+	t.open[iss.key] = r
+	t.logger.Info("raised ticket", zap.String("key", iss.key), zap.String("ticketId", r.TicketID))
+	return t.save(r)
+}
+
+// resolve marks ref of the issue key as resolved.
+// Once all refs are resolved the ticket is updated to say so and forgotten.
+func (t *tracker) resolve(ctx context.Context, key, ref string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r, ok := t.open[key]
+	if !ok {
+		return nil
+	}
+	i := slices.Index(r.Refs, ref)
+	if i < 0 {
+		return nil
+	}
+	r.Refs = slices.Delete(r.Refs, i, i+1)
+	if len(r.Refs) > 0 {
+		return t.save(r)
+	}
+
+	ticket := t.ticket(r)
+	ticket.Summary = "Resolved: " + r.Summary
+	ticket.Description = fmt.Sprintf("%s\n\nResolved at %s.", r.Description, t.now().Format(time.RFC3339))
+	if _, err := t.client.UpdateTicket(ctx, &gen.UpdateTicketRequest{Name: t.name, Ticket: ticket}); err != nil {
+		r.Refs = append(r.Refs, ref) // so we try again next time
+		return fmt.Errorf("update ticket %s: %w", r.TicketID, err)
+	}
+	delete(t.open, key)
+	t.logger.Info("resolved ticket", zap.String("key", key), zap.String("ticketId", r.TicketID))
+	if t.db == nil {
+		return nil
+	}
+	err := t.db.Delete(t.dbKey(key), ticketRecord{})
+	if errors.Is(err, bolthold.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (t *tracker) ticket(r *ticketRecord) *gen.Ticket {
+	ticket := &gen.Ticket{
+		Id:           r.TicketID,
+		Summary:      r.Summary,
+		Description:  r.Description,
+		ReporterName: t.reporter,
+	}
+	if r.Classification != "" {
+		ticket.Classification = &gen.Ticket_Classification{Id: r.Classification}
+		for _, c := range t.support.GetClassifications() {
+			if c.Id == r.Classification {
+				ticket.Classification.Title = c.Title
+			}
+		}
+	}
+	if r.Severity != "" {
+		ticket.Severity = &gen.Ticket_Severity{Id: r.Severity}
+		for _, s := range t.support.GetSeverities() {
+			if s.Id == r.Severity {
+				ticket.Severity.Title = s.Title
+			}
+		}
+	}
+	return ticket
+}
+
+func (t *tracker) save(r *ticketRecord) error {
+	if t.db == nil {
+		return nil
+	}
+	return t.db.Upsert(t.dbKey(r.Key), r)
+}
+
+func (t *tracker) dbKey(key string) string {
+	return t.scope + "/" + key
+}
@@ -0,0 +1,111 @@
+package servicetickets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto/servicetickets/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+func TestTracker(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeTickets{}
+	cfg := config.Root{
+		Severities:      map[string]string{"WARNING": "p3", "SEVERE": "p1"},
+		Classifications: config.Classifications{Default: "general", Subsystems: map[string]string{"lighting": "electrical"}},
+	}
+	tr := newTracker(client, "cafm", "Smart Core", "test", nil, zap.NewNop(), func() time.Time { return time.Unix(0, 0) })
+	tr.setSupport(&gen.TicketSupport{Severities: []*gen.Ticket_Severity{{Id: "p1", Title: "Urgent"}}})
+
+	alert := func(id string, severity gen.Alert_Severity) *gen.Alert {
+		return &gen.Alert{Id: id, Description: "lamp failure", Source: "floor1/light1", Subsystem: "lighting", Severity: severity}
+	}
+	// the same problem raised twice shares a ticket
+	for _, a := range []*gen.Alert{alert("1", gen.Alert_SEVERE), alert("2", gen.Alert_SEVERE)} {
+		if err := tr.raise(ctx, alertIssue(cfg, a)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(client.created) != 1 {
+		t.Fatalf("created %d tickets, want 1", len(client.created))
+	}
+	created := client.created[0]
+	if created.Summary != "floor1/light1: lamp failure" {
+		t.Errorf("summary got %q", created.Summary)
+	}
+	if created.GetClassification().GetId() != "electrical" {
+		t.Errorf("classification got %v, want electrical", created.Classification)
+	}
+	if created.GetSeverity().GetId() != "p1" || created.GetSeverity().GetTitle() != "Urgent" {
+		t.Errorf("severity got %v, want p1 Urgent", created.Severity)
+	}
+
+	// the ticket is only resolved when all occurrences are
+	key := alertKey(alert("1", gen.Alert_SEVERE))
+	if err := tr.resolve(ctx, key, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.updated) != 0 {
+		t.Fatalf("updated %d tickets, want 0", len(client.updated))
+	}
+	if err := tr.resolve(ctx, key, "2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.updated) != 1 {
+		t.Fatalf("updated %d tickets, want 1", len(client.updated))
+	}
+	if got := client.updated[0]; got.Id != "T1" || got.Summary != "Resolved: floor1/light1: lamp failure" {
+		t.Errorf("updated ticket got %v", got)
+	}
+
+	// raising again after resolution creates a new ticket
+	if err := tr.raise(ctx, alertIssue(cfg, alert("3", gen.Alert_WARNING))); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.created) != 2 {
+		t.Fatalf("created %d tickets, want 2", len(client.created))
+	}
+	if got := client.created[1].GetSeverity().GetId(); got != "p3" {
+		t.Errorf("severity got %q, want p3", got)
+	}
+}
+
+func TestRoot_Severity(t *testing.T) {
+	cfg := config.Root{Severities: map[string]string{"WARNING": "p3", "SEVERE": "p1"}}
+	tests := []struct {
+		in   gen.Alert_Severity
+		want string
+	}{
+		{gen.Alert_INFO, ""},
+		{gen.Alert_WARNING, "p3"},
+		{gen.Alert_WARNING + 1, "p3"},
+		{gen.Alert_SEVERE, "p1"},
+		{gen.Alert_LIFE_SAFETY, "p1"},
+	}
+	for _, tt := range tests {
+		if got := cfg.Severity(tt.in); got != tt.want {
+			t.Errorf("Severity(%v) got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+type fakeTickets struct {
+	created, updated []*gen.Ticket
+}
+
+func (f *fakeTickets) CreateTicket(_ context.Context, in *gen.CreateTicketRequest, _ ...grpc.CallOption) (*gen.Ticket, error) {
+	f.created = append(f.created, in.Ticket)
+	in.Ticket.Id = fmt.Sprintf("T%d", len(f.created))
+	return in.Ticket, nil
+}
+
+func (f *fakeTickets) UpdateTicket(_ context.Context, in *gen.UpdateTicketRequest, _ ...grpc.CallOption) (*gen.Ticket, error) {
+	f.updated = append(f.updated, in.Ticket)
+	return in.Ticket, nil
+}
@@ -61,10 +61,11 @@ type Auth struct {
 	Scopes   []string `json:"scopes,omitempty"`   // default: ["read:device"]
 }
 
-// ClientSecret allows specifying a client secret either directly or via a file.
+// ClientSecret allows specifying a client secret either directly, via a file, or as a reference to a secret.
+// For example {"clientSecret": {"$secret": "airthings/client"}}.
 type ClientSecret struct {
-	ClientSecret     string `json:"clientSecret,omitempty"`
-	ClientSecretFile string `json:"clientSecretFile,omitempty"`
+	ClientSecret     jsontypes.Secret `json:"clientSecret,omitempty"`
+	ClientSecretFile string           `json:"clientSecretFile,omitempty"`
 }
 
 // URL returns the full API URL for the given path.
@@ -118,7 +119,7 @@ func (a Auth) ClientCredentialsConfig() (clientcredentials.Config, error) {
 // Read returns the password, either from ClientSecret or ClientSecretFile.
 func (c ClientSecret) Read() (string, error) {
 	if c.ClientSecret != "" {
-		return c.ClientSecret, nil
+		return c.ClientSecret.Read()
 	}
 	bs, err := os.ReadFile(c.ClientSecretFile)
 	if err != nil {
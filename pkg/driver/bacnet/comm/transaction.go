@@ -0,0 +1,74 @@
+package comm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	bactypes "github.com/smart-core-os/gobacnet/types"
+)
+
+var (
+	transactions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "scbos",
+		Subsystem: "bacnet",
+		Name:      "transactions_total",
+		Help:      "Number of BACnet confirmed requests sent, by service and result.",
+	}, []string{"service", "result"})
+	transactionSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "scbos",
+		Subsystem: "bacnet",
+		Name:      "transaction_seconds",
+		Help:      "Time taken for BACnet confirmed requests to complete.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service"})
+)
+
+var tracer = otel.Tracer("github.com/smart-core-os/sc-bos/pkg/driver/bacnet/comm")
+
+// transaction records metrics and a span for a single BACnet request.
+type transaction struct {
+	service string
+	start   time.Time
+	span    trace.Span
+}
+
+// startTransaction starts recording a request for service to device.
+// The returned context should be used for the request, call transaction.end when it completes.
+func startTransaction(ctx context.Context, service string, device bactypes.Device) (context.Context, transaction) {
+	ctx, span := tracer.Start(ctx, "bacnet."+service,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int64("bacnet.device", int64(device.ID.Instance))),
+	)
+	return ctx, transaction{service: service, start: time.Now(), span: span}
+}
+
+// end records the outcome of the request.
+// err should already have been passed through ctxerr.Cause.
+func (t transaction) end(err error) {
+	transactionSeconds.WithLabelValues(t.service).Observe(time.Since(t.start).Seconds())
+	result := "ok"
+	switch {
+	case err == nil:
+	case errors.Is(err, context.Canceled):
+		result = "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		result = "timeout"
+	default:
+		result = "error"
+	}
+	transactions.WithLabelValues(t.service, result).Inc()
+
+	if err != nil {
+		t.span.RecordError(err)
+		t.span.SetStatus(codes.Error, result)
+	}
+	t.span.End()
+}
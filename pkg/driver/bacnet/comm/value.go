@@ -33,9 +33,14 @@ func ReadProperty(ctx context.Context, client *gobacnet.Client, known known.Cont
 			},
 		},
 	}
-	res, err := client.ReadProperty(ctx, device, req)
+	txCtx, tx := startTransaction(ctx, "readProperty", device)
+	res, err := client.ReadProperty(txCtx, device, req)
 	if err != nil {
-		return nil, ctxerr.Cause(ctx, err)
+		err = ctxerr.Cause(ctx, err)
+	}
+	tx.end(err)
+	if err != nil {
+		return nil, err
 	}
 	if len(res.Object.Properties) == 0 {
 		// Shouldn't happen, but has on occasion. I guess it depends how the device responds to our request
@@ -129,10 +134,14 @@ func ReadProperties(ctx context.Context, client *gobacnet.Client, known known.Co
 }
 
 func readMultiProperties(ctx context.Context, client *gobacnet.Client, device bactypes.Device, req bactypes.ReadMultipleProperty, resIndexes map[key][]int, res []any) {
-	multiRes, err := client.ReadMultiProperty(ctx, device, req)
+	txCtx, tx := startTransaction(ctx, "readPropertyMultiple", device)
+	multiRes, err := client.ReadMultiProperty(txCtx, device, req)
 	if err != nil {
-		// todo: be more conservative about which errors we try individual property reads for
 		err = ctxerr.Cause(ctx, err)
+	}
+	tx.end(err)
+	if err != nil {
+		// todo: be more conservative about which errors we try individual property reads for
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 			// stop early as ctx is done anyway
 			for _, object := range req.Objects {
@@ -163,16 +172,21 @@ func readMultiProperties(ctx context.Context, client *gobacnet.Client, device ba
 			// read the properties one at a time as the multi read failed
 			for _, object := range req.Objects {
 				for _, prop := range object.Properties {
-					oneRes, err := client.ReadProperty(ctx, device, bactypes.ReadPropertyData{
+					txCtx, tx := startTransaction(ctx, "readProperty", device)
+					oneRes, err := client.ReadProperty(txCtx, device, bactypes.ReadPropertyData{
 						Object: bactypes.Object{
 							ID:         object.ID,
 							Properties: []bactypes.Property{prop},
 						},
 					})
+					if err != nil {
+						err = ctxerr.Cause(ctx, err)
+					}
+					tx.end(err)
 					if err != nil {
 						k := key{device.ID.Instance, object.ID, prop.ID}
 						for _, i := range resIndexes[k] {
-							res[i] = ctxerr.Cause(ctx, err)
+							res[i] = err
 						}
 						continue
 					}
@@ -335,8 +349,11 @@ func WriteProperty(ctx context.Context, client *gobacnet.Client, known known.Con
 	if priority > 0 {
 		writePriority = priority // allow overriding the device default priority with one given if non-zero
 	}
-	err = client.WriteProperty(ctx, device, req, writePriority)
-	return ctxerr.Cause(ctx, err)
+	txCtx, tx := startTransaction(ctx, "writeProperty", device)
+	err = client.WriteProperty(txCtx, device, req, writePriority)
+	err = ctxerr.Cause(ctx, err)
+	tx.end(err)
+	return err
 }
 
 // massageValueForWrite converts value to a more correct type for the given BACnet object and property.
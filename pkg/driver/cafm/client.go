@@ -0,0 +1,140 @@
+package cafm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/cafm/config"
+)
+
+// Client makes requests against a CAFM REST API.
+type Client struct {
+	BaseURL *url.URL
+	HTTP    *http.Client
+	// Authorize adds credentials to each request, may be nil.
+	Authorize func(r *http.Request)
+}
+
+func newClient(cfg config.Root) (*Client, error) {
+	base, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		BaseURL: base,
+		HTTP:    &http.Client{Timeout: cfg.TimeoutOrDefault(), Transport: otelhttp.NewTransport(http.DefaultTransport)},
+	}
+	if cfg.Auth != nil {
+		secret, err := cfg.Auth.Read()
+		if err != nil {
+			return nil, fmt.Errorf("auth: %w", err)
+		}
+		switch cfg.Auth.Type {
+		case config.AuthBasic:
+			c.Authorize = func(r *http.Request) { r.SetBasicAuth(cfg.Auth.Username, secret) }
+		case config.AuthBearer:
+			c.Authorize = func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+secret) }
+		case config.AuthHeader:
+			c.Authorize = func(r *http.Request) { r.Header.Set(cfg.Auth.Header, secret) }
+		}
+	}
+	return c, nil
+}
+
+// Do sends body as JSON to the endpoint, decoding any JSON response into a generic value.
+// The {id} placeholder in the endpoint path is replaced with id.
+func (c *Client) Do(ctx context.Context, ep config.Endpoint, id string, body any) (any, error) {
+	path := strings.ReplaceAll(ep.Path, "{id}", url.PathEscape(id))
+	u := c.BaseURL.JoinPath(path)
+	bs, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, ep.Method, u.String(), bytes.NewReader(bs))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.Authorize != nil {
+		c.Authorize(req)
+	}
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, status.Errorf(httpCode(res.StatusCode), "%s %s: %s %s", ep.Method, path, res.Status, bytes.TrimSpace(resBody))
+	}
+	if len(bytes.TrimSpace(resBody)) == 0 {
+		return nil, nil
+	}
+	var out any
+	if err := json.Unmarshal(resBody, &out); err != nil {
+		return nil, status.Errorf(codes.Internal, "decode response: %v", err)
+	}
+	return out, nil
+}
+
+// httpCode converts an unsuccessful HTTP status code into the closest grpc code.
+func httpCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	}
+	if httpStatus >= 500 {
+		return codes.Unavailable
+	}
+	return codes.Unknown
+}
+
+// setPath sets the value at the dot separated path in m, creating intermediate objects as needed.
+func setPath(m map[string]any, path string, value any) {
+	parts := strings.Split(path, ".")
+	for _, p := range parts[:len(parts)-1] {
+		child, ok := m[p].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			m[p] = child
+		}
+		m = child
+	}
+	m[parts[len(parts)-1]] = value
+}
+
+// getPath returns the value at the dot separated path in v, or nil if there isn't one.
+func getPath(v any, path string) any {
+	for _, p := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[p]
+	}
+	return v
+}
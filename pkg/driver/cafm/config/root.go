@@ -0,0 +1,156 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+	AuthHeader = "header"
+)
+
+// Ticket fields that can be mapped into request bodies.
+const (
+	FieldSummary             = "summary"
+	FieldDescription         = "description"
+	FieldReporterName        = "reporterName"
+	FieldClassificationID    = "classification.id"
+	FieldClassificationTitle = "classification.title"
+	FieldSeverityID          = "severity.id"
+	FieldSeverityTitle       = "severity.title"
+)
+
+const DefaultTimeout = 30 * time.Second
+
+func DefaultFields() map[string]string {
+	return map[string]string{
+		FieldSummary:          "summary",
+		FieldDescription:      "description",
+		FieldReporterName:     "reporter",
+		FieldClassificationID: "category",
+		FieldSeverityID:       "priority",
+	}
+}
+
+func ReadBytes(data []byte) (cfg Root, err error) {
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return
+	}
+	if cfg.Create.Method == "" {
+		cfg.Create.Method = "POST"
+	}
+	if cfg.Create.Path == "" {
+		cfg.Create.Path = "tickets"
+	}
+	if cfg.Update.Method == "" {
+		cfg.Update.Method = "PUT"
+	}
+	if cfg.Update.Path == "" {
+		cfg.Update.Path = "tickets/{id}"
+	}
+	if cfg.Fields == nil {
+		cfg.Fields = DefaultFields()
+	}
+	if cfg.ResponseID == "" {
+		cfg.ResponseID = "id"
+	}
+	if cfg.Auth != nil && cfg.Auth.Type == AuthHeader && cfg.Auth.Header == "" {
+		cfg.Auth.Header = "X-API-Key"
+	}
+	return cfg, cfg.validate()
+}
+
+type Root struct {
+	driver.BaseConfig
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+
+	// BaseURL of the CAFM REST API, endpoint paths are relative to this.
+	BaseURL string `json:"baseUrl,omitempty"`
+	Auth    *Auth  `json:"auth,omitempty"`
+	// Timeout for each request, defaults to DefaultTimeout.
+	Timeout *jsontypes.Duration `json:"timeout,omitempty"`
+
+	// Create is called to create a ticket, defaults to POST tickets.
+	Create Endpoint `json:"create,omitempty"`
+	// Update is called to update a ticket, defaults to PUT tickets/{id}.
+	Update Endpoint `json:"update,omitempty"`
+
+	// Fields maps ticket fields to the dot separated path of the property in the request body.
+	// See the Field* constants for the ticket fields, DefaultFields is used if absent.
+	// Ticket fields that are not mapped, or are empty, are not sent.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Static properties included in every request body, for example a site id.
+	// Ticket fields are written on top of these.
+	Static map[string]any `json:"static,omitempty"`
+	// ResponseID is the dot separated path of the ticket id in the create response body.
+	ResponseID string `json:"responseId,omitempty"`
+	// ExternalURL is a link to view the ticket, {id} is replaced with the ticket id.
+	ExternalURL string `json:"externalUrl,omitempty"`
+
+	// Classifications and Severities the CAFM accepts, returned by DescribeTicket.
+	Classifications []Option `json:"classifications,omitempty"`
+	Severities      []Option `json:"severities,omitempty"`
+}
+
+type Endpoint struct {
+	Method string `json:"method,omitempty"`
+	// Path relative to the base url, {id} is replaced with the ticket id.
+	Path string `json:"path,omitempty"`
+}
+
+type Auth struct {
+	// Type is one of AuthBasic, AuthBearer, or AuthHeader.
+	Type     string `json:"type,omitempty"`
+	Username string `json:"username,omitempty"` // for AuthBasic
+	Header   string `json:"header,omitempty"`   // for AuthHeader, defaults to X-API-Key
+	// The password, token, or header value depending on Type.
+	jsontypes.Password
+}
+
+type Option struct {
+	ID          string `json:"id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func (c Root) TimeoutOrDefault() time.Duration {
+	if c.Timeout == nil {
+		return DefaultTimeout
+	}
+	return c.Timeout.Duration
+}
+
+func (c Root) validate() error {
+	if c.BaseURL == "" {
+		return errors.New("baseUrl is required")
+	}
+	if _, err := url.Parse(c.BaseURL); err != nil {
+		return fmt.Errorf("baseUrl: %w", err)
+	}
+	for field := range c.Fields {
+		switch field {
+		case FieldSummary, FieldDescription, FieldReporterName,
+			FieldClassificationID, FieldClassificationTitle, FieldSeverityID, FieldSeverityTitle:
+		default:
+			return fmt.Errorf("fields: unknown ticket field %q", field)
+		}
+	}
+	if c.Auth != nil {
+		switch c.Auth.Type {
+		case AuthBasic, AuthBearer, AuthHeader:
+		default:
+			return fmt.Errorf("auth: unknown type %q", c.Auth.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package cafm provides a driver that raises service tickets in a Computer Aided Facilities Management system via a
+// generic REST API.
+// Endpoints, authentication, and how ticket fields map to request bodies are all configurable.
+package cafm
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/driver/cafm/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/serviceticketpb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+)
+
+const DriverName = "cafm"
+
+var Factory driver.Factory = factory{}
+
+type factory struct{}
+
+func (f factory) New(services driver.Services) service.Lifecycle {
+	d := &Driver{
+		announcer: node.NewReplaceAnnouncer(services.Node),
+	}
+	d.Service = service.New(service.MonoApply(d.applyConfig), service.WithParser(config.ReadBytes))
+	d.logger = services.Logger.Named(DriverName)
+	return d
+}
+
+type Driver struct {
+	*service.Service[config.Root]
+	logger    *zap.Logger
+	announcer *node.ReplaceAnnouncer
+}
+
+func (d *Driver) applyConfig(ctx context.Context, cfg config.Root) error {
+	announcer := d.announcer.Replace(ctx)
+	client, err := newClient(cfg)
+	if err != nil {
+		return err
+	}
+	tickets := NewTickets(client, cfg)
+
+	if cfg.Metadata != nil {
+		announcer.Announce(cfg.Name, node.HasMetadata(cfg.Metadata))
+	}
+	announcer.Announce(cfg.Name, node.HasTrait(serviceticketpb.TraitName,
+		node.WithClients(gen.WrapServiceTicketApi(tickets), gen.WrapServiceTicketInfo(tickets))))
+	d.logger.Debug("announced", zap.String("name", cfg.Name), zap.String("baseUrl", cfg.BaseURL))
+	return nil
+}
@@ -0,0 +1,128 @@
+package cafm
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/cafm/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// Tickets implements the ServiceTicketApi and ServiceTicketInfo by calling a CAFM REST API.
+type Tickets struct {
+	gen.UnimplementedServiceTicketApiServer
+	gen.UnimplementedServiceTicketInfoServer
+
+	client  *Client
+	cfg     config.Root
+	support *gen.TicketSupport
+}
+
+func NewTickets(client *Client, cfg config.Root) *Tickets {
+	support := &gen.TicketSupport{}
+	for _, o := range cfg.Classifications {
+		support.Classifications = append(support.Classifications, &gen.Ticket_Classification{Id: o.ID, Title: o.Title, Description: o.Description})
+	}
+	for _, o := range cfg.Severities {
+		support.Severities = append(support.Severities, &gen.Ticket_Severity{Id: o.ID, Title: o.Title, Description: o.Description})
+	}
+	return &Tickets{client: client, cfg: cfg, support: support}
+}
+
+func (t *Tickets) CreateTicket(ctx context.Context, request *gen.CreateTicketRequest) (*gen.Ticket, error) {
+	ticket := request.GetTicket()
+	if ticket == nil {
+		return nil, status.Error(codes.InvalidArgument, "ticket is required")
+	}
+	res, err := t.client.Do(ctx, t.cfg.Create, "", t.body(ticket))
+	if err != nil {
+		return nil, err
+	}
+	id := getPath(res, t.cfg.ResponseID)
+	if id == nil {
+		return nil, status.Errorf(codes.Internal, "create response has no %q", t.cfg.ResponseID)
+	}
+	out := proto.Clone(ticket).(*gen.Ticket)
+	out.Id = idString(id)
+	out.ExternalUrl = t.externalURL(out.Id)
+	return out, nil
+}
+
+func (t *Tickets) UpdateTicket(ctx context.Context, request *gen.UpdateTicketRequest) (*gen.Ticket, error) {
+	ticket := request.GetTicket()
+	if ticket.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "ticket.id is required")
+	}
+	if _, err := t.client.Do(ctx, t.cfg.Update, ticket.Id, t.body(ticket)); err != nil {
+		return nil, err
+	}
+	out := proto.Clone(ticket).(*gen.Ticket)
+	out.ExternalUrl = t.externalURL(out.Id)
+	return out, nil
+}
+
+func (t *Tickets) DescribeTicket(context.Context, *gen.DescribeTicketRequest) (*gen.TicketSupport, error) {
+	return t.support, nil
+}
+
+// body returns the request body for ticket, using the configured field mapping.
+func (t *Tickets) body(ticket *gen.Ticket) map[string]any {
+	body := make(map[string]any)
+	for k, v := range t.cfg.Static {
+		body[k] = cloneJSON(v)
+	}
+	values := map[string]string{
+		config.FieldSummary:             ticket.Summary,
+		config.FieldDescription:         ticket.Description,
+		config.FieldReporterName:        ticket.ReporterName,
+		config.FieldClassificationID:    ticket.GetClassification().GetId(),
+		config.FieldClassificationTitle: ticket.GetClassification().GetTitle(),
+		config.FieldSeverityID:          ticket.GetSeverity().GetId(),
+		config.FieldSeverityTitle:       ticket.GetSeverity().GetTitle(),
+	}
+	for field, path := range t.cfg.Fields {
+		if v := values[field]; v != "" && path != "" {
+			setPath(body, path, v)
+		}
+	}
+	return body
+}
+
+func (t *Tickets) externalURL(id string) string {
+	if t.cfg.ExternalURL == "" || id == "" {
+		return ""
+	}
+	return strings.ReplaceAll(t.cfg.ExternalURL, "{id}", id)
+}
+
+// idString formats an id decoded from JSON, which may be a string or number.
+func idString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// cloneJSON deep copies objects so setPath doesn't modify the configured static values.
+func cloneJSON(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	m = maps.Clone(m)
+	for k, child := range m {
+		m[k] = cloneJSON(child)
+	}
+	return m
+}
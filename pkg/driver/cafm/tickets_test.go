@@ -0,0 +1,130 @@
+package cafm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/cafm/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+func TestTickets(t *testing.T) {
+	type call struct {
+		Method, Path, APIKey string
+		Body                 map[string]any
+	}
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := call{Method: r.Method, Path: r.URL.Path, APIKey: r.Header.Get("X-Api-Token")}
+		if err := json.NewDecoder(r.Body).Decode(&c.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		calls = append(calls, c)
+		if c.APIKey != "secret" {
+			http.Error(w, "bad key", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/jobs":
+			_, _ = w.Write([]byte(`{"data":{"jobId":1234567}}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v2/jobs/1234567":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg, err := config.ReadBytes([]byte(`{
+		"name": "cafm",
+		"baseUrl": "` + srv.URL + `/api/v2",
+		"auth": {"type": "header", "header": "X-Api-Token", "password": "secret"},
+		"create": {"path": "jobs"},
+		"update": {"method": "PATCH", "path": "jobs/{id}"},
+		"fields": {"summary": "title", "description": "details.text", "severity.id": "details.priority"},
+		"static": {"siteId": "S1", "details": {"source": "smart-core"}},
+		"responseId": "data.jobId",
+		"externalUrl": "https://cafm.example.com/jobs/{id}",
+		"severities": [{"id": "P1", "title": "Urgent"}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := newClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tickets := NewTickets(client, cfg)
+	ctx := context.Background()
+
+	created, err := tickets.CreateTicket(ctx, &gen.CreateTicketRequest{Ticket: &gen.Ticket{
+		Summary:     "Lamp failure",
+		Description: "floor1/light1 lamp failure",
+		Severity:    &gen.Ticket_Severity{Id: "P1"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Id != "1234567" || created.ExternalUrl != "https://cafm.example.com/jobs/1234567" {
+		t.Fatalf("created got id %q url %q", created.Id, created.ExternalUrl)
+	}
+
+	created.Summary = "Resolved: Lamp failure"
+	if _, err := tickets.UpdateTicket(ctx, &gen.UpdateTicketRequest{Ticket: created}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []call{
+		{Method: "POST", Path: "/api/v2/jobs", APIKey: "secret", Body: map[string]any{
+			"siteId": "S1",
+			"title":  "Lamp failure",
+			"details": map[string]any{
+				"source":   "smart-core",
+				"text":     "floor1/light1 lamp failure",
+				"priority": "P1",
+			},
+		}},
+		{Method: "PATCH", Path: "/api/v2/jobs/1234567", APIKey: "secret", Body: map[string]any{
+			"siteId": "S1",
+			"title":  "Resolved: Lamp failure",
+			"details": map[string]any{
+				"source":   "smart-core",
+				"text":     "floor1/light1 lamp failure",
+				"priority": "P1",
+			},
+		}},
+	}
+	if diff := cmp.Diff(want, calls); diff != "" {
+		t.Fatalf("requests (-want,+got)\n%s", diff)
+	}
+	if got := cfg.Static["details"].(map[string]any); len(got) != 1 {
+		t.Fatalf("static config was modified: %v", got)
+	}
+
+	support, err := tickets.DescribeTicket(ctx, &gen.DescribeTicketRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(support.Severities) != 1 || support.Severities[0].Title != "Urgent" {
+		t.Fatalf("support severities got %v", support.Severities)
+	}
+
+	// errors from the CAFM are converted to grpc errors
+	_, err = tickets.UpdateTicket(ctx, &gen.UpdateTicketRequest{Ticket: &gen.Ticket{Id: "missing"}})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Fatalf("update missing ticket: want NotFound, got %v", err)
+	}
+	client.Authorize = nil
+	_, err = tickets.CreateTicket(ctx, &gen.CreateTicketRequest{Ticket: &gen.Ticket{Summary: "x"}})
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Fatalf("create without auth: want Unauthenticated, got %v", err)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type Client struct {
@@ -29,12 +31,12 @@ func newHttpClient(baseURL string, apiKey string, caPath string, certPath string
 	return &Client{
 		BaseURL: baseURL,
 		HTTPClient: &http.Client{
-			Transport: &http.Transport{
+			Transport: otelhttp.NewTransport(&http.Transport{
 				TLSClientConfig: &tls.Config{
 					RootCAs:      caCertPool,
 					Certificates: []tls.Certificate{clientCert},
 				},
-			},
+			}),
 		},
 		ApiKey: apiKey,
 	}, nil
@@ -20,15 +20,19 @@ type Client struct {
 	HTTPClient *http.Client
 }
 
-func NewClient(conf *config.API) *Client {
+func NewClient(conf *config.API) (*Client, error) {
+	secret, err := conf.ReadSecret()
+	if err != nil {
+		return nil, fmt.Errorf("read secret: %w", err)
+	}
 	return &Client{
 		address: conf.Address,
 		appKey:  conf.AppKey,
-		secret:  conf.Secret,
+		secret:  secret,
 		HTTPClient: &http.Client{
 			Timeout: conf.Timeout.Duration,
 		},
-	}
+	}, nil
 }
 
 func (c *Client) ListCameraInfo(req *CamerasRequest) (*CamerasResponse, error) {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -23,9 +24,10 @@ type Root struct {
 }
 
 type API struct {
-	Address    string              `json:"address,omitempty"`
-	AppKey     string              `json:"appKey,omitempty"`
-	Secret     string              `json:"secret,omitempty"`
+	Address string `json:"address,omitempty"`
+	AppKey  string `json:"appKey,omitempty"`
+	// Secret is the APP secret, either given directly or as a reference to a secret like {"$secret": "hikcentral/app"}.
+	Secret     jsontypes.Secret    `json:"secret,omitempty"`
 	SecretFile string              `json:"secretFile,omitempty"`
 	Timeout    *jsontypes.Duration `json:"timeout,omitempty"`
 }
@@ -52,12 +54,6 @@ func ReadBytes(raw []byte) (dst Root, err error) {
 	if err != nil {
 		return dst, err
 	}
-	if dst.API.SecretFile != "" {
-		dst.API.Secret, err = readSecret(dst.API.SecretFile)
-		if err != nil {
-			return dst, err
-		}
-	}
 	if dst.API.Timeout == nil {
 		dst.API.Timeout = &jsontypes.Duration{Duration: 5 * time.Second}
 	}
@@ -73,12 +69,14 @@ func ReadBytes(raw []byte) (dst Root, err error) {
 	return
 }
 
-func readSecret(path string) (string, error) {
-	raw, err := os.ReadFile(path)
+// ReadSecret returns the APP secret, either from Secret or SecretFile.
+func (a API) ReadSecret() (string, error) {
+	if a.Secret != "" {
+		return a.Secret.Read()
+	}
+	raw, err := os.ReadFile(a.SecretFile)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("%w: %q", err, a.SecretFile)
 	}
-	str := string(raw)
-	str = strings.TrimSpace(str)
-	return str, nil
+	return strings.TrimSpace(string(raw)), nil
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
@@ -51,16 +52,19 @@ type Driver struct {
 }
 
 func (d *Driver) applyConfig(ctx context.Context, cfg config.Root) error {
+	client, err := api.NewClient(cfg.API)
+	if err != nil {
+		return err
+	}
 	// AnnounceContext only makes sense if using MonoApply, which we are in New
 	announcer, undo := node.AnnounceScope(d.announcer)
 	logger := d.logger.With(zap.String("host", cfg.API.Address))
 
-	client := api.NewClient(cfg.API)
-	client.HTTPClient.Transport = &http.Transport{
+	client.HTTPClient.Transport = otelhttp.NewTransport(&http.Transport{
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: true,
 		},
-	}
+	})
 
 	grp, ctx := errgroup.WithContext(ctx)
 	var cameras []*Camera
@@ -0,0 +1,317 @@
+// Package comm implements the M-Bus (EN 13757) link layer and variable data structure, for reading meters over a
+// serial port or a TCP gateway.
+package comm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/util/transport"
+)
+
+var (
+	// ErrNoResponse is returned when no meter responds to a request before the timeout.
+	ErrNoResponse = errors.New("no response")
+	// ErrCollision is returned when more than one meter responds to a request.
+	ErrCollision = errors.New("collision")
+)
+
+// collisionWindow is how long to wait after an acknowledgement for responses from other meters.
+const collisionWindow = 50 * time.Millisecond
+
+// Client makes requests to meters on an M-Bus, one at a time.
+// Run must be called for the client to receive responses.
+type Client struct {
+	name    string
+	t       transport.Transport
+	timeout time.Duration
+	logger  *zap.Logger
+
+	rx chan []byte
+	// mu makes sure requests are made one at a time, and guards the fields below.
+	mu  chan struct{}
+	buf []byte
+	// fcb is the next frame count bit to send to each meter, keyed by address.
+	fcb map[string]bool
+}
+
+// Option configures a Client.
+type Option func(c *Client)
+
+// WithTimeout sets how long to wait for a meter to respond to a request. Defaults to 1s.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewClient returns a Client that makes requests over t, which might be a serial port or a TCP connection to a gateway.
+func NewClient(name string, t transport.Transport, opts ...Option) *Client {
+	c := &Client{
+		name:    name,
+		t:       t,
+		timeout: time.Second,
+		logger:  zap.NewNop(),
+		rx:      make(chan []byte, 16),
+		mu:      make(chan struct{}, 1),
+		fcb:     make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Name returns the name the client was created with.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Run connects the transport and receives data from it until ctx is done, then closes the transport.
+func (c *Client) Run(ctx context.Context) {
+	connected := make(chan struct{})
+	go func() {
+		defer close(connected)
+		_ = c.t.Connect(ctx)
+	}()
+	c.receive(ctx)
+	// closing while the transport might still connect would leave the new connection open
+	<-connected
+	if err := c.t.Close(); err != nil {
+		c.logger.Debug("failed to close", zap.String("conn", c.name), zap.Error(err))
+	}
+}
+
+func (c *Client) receive(ctx context.Context) {
+	buf := make([]byte, 512)
+	for {
+		if !transport.WaitConnected(ctx, c.t) {
+			return
+		}
+		n, err := c.t.Read(buf)
+		if n > 0 {
+			select {
+			case c.rx <- append([]byte(nil), buf[:n]...):
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.logger.Debug("read failed", zap.String("conn", c.name), zap.Error(err))
+		}
+	}
+}
+
+// Reset sends SND_NKE to the meter at the primary address, which resets its frame count bit.
+// Sending it to AddressNetwork deselects the selected meter.
+func (c *Client) Reset(ctx context.Context, address byte) error {
+	if err := c.lock(ctx); err != nil {
+		return err
+	}
+	defer c.unlock()
+	return c.reset(ctx, address)
+}
+
+func (c *Client) reset(ctx context.Context, address byte) error {
+	c.fcb[primaryKey(address)] = true
+	return c.request(ctx, shortFrame(cSndNke, address), c.readAck)
+}
+
+// ReadPrimary requests data from the meter at the primary address.
+func (c *Client) ReadPrimary(ctx context.Context, address byte) (*Telegram, error) {
+	if err := c.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return c.readData(ctx, address, primaryKey(address))
+}
+
+// ReadSecondary selects the meter with the secondary address id, then requests data from it.
+func (c *Client) ReadSecondary(ctx context.Context, id string) (*Telegram, error) {
+	if err := ValidateID(id); err != nil {
+		return nil, err
+	}
+	if err := c.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	if err := c.selectID(ctx, id); err != nil {
+		return nil, fmt.Errorf("select: %w", err)
+	}
+	return c.readData(ctx, AddressNetwork, "s:"+id)
+}
+
+// Select selects the meter whose secondary address matches id, where F digits match any digit.
+// ErrNoResponse is returned if no meters match, and ErrCollision if more than one might.
+func (c *Client) Select(ctx context.Context, id string) error {
+	if err := c.lock(ctx); err != nil {
+		return err
+	}
+	defer c.unlock()
+	return c.selectID(ctx, id)
+}
+
+func (c *Client) selectID(ctx context.Context, id string) error {
+	b, err := encodeID(id)
+	if err != nil {
+		return err
+	}
+	// any manufacturer, version, and medium
+	data := append(b[:], 0xFF, 0xFF, 0xFF, 0xFF)
+	return c.request(ctx, frame{c: cSndUd, a: AddressNetwork, ci: ciSelect, data: data}.bytes(), c.readAck)
+}
+
+func (c *Client) readData(ctx context.Context, address byte, key string) (*Telegram, error) {
+	cf := byte(cReqUd2)
+	if c.fcb[key] {
+		cf |= cFCB
+	}
+	var res frame
+	err := c.request(ctx, shortFrame(cf, address), func(ctx context.Context) (err error) {
+		res, err = c.readLong(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.fcb[key] = !c.fcb[key]
+	if res.c&0xCF != cRspUd {
+		return nil, fmt.Errorf("%w: C field %02X isn't RSP_UD", ErrInvalidFrame, res.c)
+	}
+	return decodeTelegram(res)
+}
+
+// request writes req then reads the response using read, which has until the timeout to complete.
+func (c *Client) request(ctx context.Context, req []byte, read func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	c.discard()
+	if _, err := c.t.Write(req); err != nil {
+		return fmt.Errorf("write to %s: %w", c.name, err)
+	}
+	err := read(ctx)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+		return ErrNoResponse
+	}
+	return err
+}
+
+// readAck reads a single character acknowledgement.
+func (c *Client) readAck(ctx context.Context) error {
+	b, err := c.readN(ctx, 1)
+	if err != nil {
+		return err
+	}
+	// other meters might answer too, their responses overlapping ours
+	more := c.settle(ctx)
+	if b[0] != ack || more {
+		return fmt.Errorf("%w: got % X", ErrCollision, append(b, c.takeAll()...))
+	}
+	return nil
+}
+
+// readLong reads a long frame.
+func (c *Client) readLong(ctx context.Context) (frame, error) {
+	head, err := c.readN(ctx, 4)
+	if err != nil {
+		return frame{}, err
+	}
+	if head[0] != longStart || head[3] != longStart || head[1] != head[2] {
+		if head[0] == ack {
+			return frame{}, fmt.Errorf("%w: got an acknowledgement, not data", ErrInvalidFrame)
+		}
+		return frame{}, fmt.Errorf("%w: bad start % X", ErrInvalidFrame, head)
+	}
+	rest, err := c.readN(ctx, int(head[1])+2)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return frame{}, fmt.Errorf("%w: incomplete frame", ErrInvalidFrame)
+		}
+		return frame{}, err
+	}
+	return decodeLong(append(head, rest...))
+}
+
+// readN returns the next n bytes received.
+func (c *Client) readN(ctx context.Context, n int) ([]byte, error) {
+	for len(c.buf) < n {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case b := <-c.rx:
+			c.buf = append(c.buf, b...)
+		}
+	}
+	b := append([]byte(nil), c.buf[:n]...)
+	c.buf = c.buf[n:]
+	return b, nil
+}
+
+// settle waits for the collision window, returning whether anything else was received.
+func (c *Client) settle(ctx context.Context) bool {
+	if len(c.buf) > 0 {
+		return true
+	}
+	t := time.NewTimer(collisionWindow)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return false
+	case b := <-c.rx:
+		c.buf = append(c.buf, b...)
+		return true
+	}
+}
+
+// takeAll returns everything received but not yet read.
+func (c *Client) takeAll() []byte {
+	b := c.buf
+	c.buf = nil
+	for {
+		select {
+		case more := <-c.rx:
+			b = append(b, more...)
+		default:
+			return b
+		}
+	}
+}
+
+// discard drops anything received that wasn't a response to a request, like late responses.
+func (c *Client) discard() {
+	if b := c.takeAll(); len(b) > 0 {
+		c.logger.Debug("discarding unexpected data", zap.String("conn", c.name), zap.Binary("data", b))
+	}
+}
+
+func (c *Client) lock(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case c.mu <- struct{}{}:
+		return nil
+	}
+}
+
+func (c *Client) unlock() {
+	<-c.mu
+}
+
+func primaryKey(address byte) string {
+	return fmt.Sprintf("p:%d", address)
+}
@@ -0,0 +1,88 @@
+package comm
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	ack        = 0xE5
+	shortStart = 0x10
+	longStart  = 0x68
+	stop       = 0x16
+)
+
+// C fields of the requests we make, and the response we expect.
+const (
+	cSndNke = 0x40
+	cSndUd  = 0x53
+	cReqUd2 = 0x5B
+	cFCB    = 0x20
+	cRspUd  = 0x08
+)
+
+// CI fields of the requests we make.
+const (
+	ciSelect = 0x52
+)
+
+const (
+	// MaxPrimaryAddress is the highest primary address a meter can have.
+	MaxPrimaryAddress = 250
+	// AddressNetwork is the address of the meter selected using its secondary address.
+	AddressNetwork = 0xFD
+	// AddressBroadcast is the address all meters respond to, only useful when there is a single meter.
+	AddressBroadcast = 0xFE
+)
+
+var (
+	// ErrInvalidFrame is returned when a response can't be decoded.
+	ErrInvalidFrame = errors.New("invalid frame")
+	// ErrUnsupported is returned when a response uses features of M-Bus we don't support, like encryption.
+	ErrUnsupported = errors.New("unsupported")
+)
+
+// frame is a long or control frame.
+type frame struct {
+	c, a, ci byte
+	data     []byte
+}
+
+func shortFrame(c, a byte) []byte {
+	return []byte{shortStart, c, a, c + a, stop}
+}
+
+func (f frame) bytes() []byte {
+	l := byte(3 + len(f.data))
+	b := make([]byte, 0, len(f.data)+9)
+	b = append(b, longStart, l, l, longStart, f.c, f.a, f.ci)
+	b = append(b, f.data...)
+	return append(b, checksum(b[4:]), stop)
+}
+
+// decodeLong decodes the long frame b, whose first 4 bytes are the start, length, length, and start bytes.
+func decodeLong(b []byte) (frame, error) {
+	if len(b) < 9 || b[0] != longStart || b[3] != longStart || b[1] != b[2] {
+		return frame{}, fmt.Errorf("%w: bad long frame start % X", ErrInvalidFrame, b[:min(len(b), 4)])
+	}
+	l := int(b[1])
+	if l < 3 || len(b) != l+6 {
+		return frame{}, fmt.Errorf("%w: length %d doesn't match %d bytes", ErrInvalidFrame, l, len(b))
+	}
+	if b[len(b)-1] != stop {
+		return frame{}, fmt.Errorf("%w: missing stop byte", ErrInvalidFrame)
+	}
+	body := b[4 : 4+l]
+	if want, got := checksum(body), b[len(b)-2]; want != got {
+		return frame{}, fmt.Errorf("%w: checksum %02X, want %02X", ErrInvalidFrame, got, want)
+	}
+	return frame{c: body[0], a: body[1], ci: body[2], data: body[3:]}, nil
+}
+
+func checksum(b []byte) byte {
+	var sum byte
+	for _, c := range b {
+		sum += c
+	}
+	return sum
+}
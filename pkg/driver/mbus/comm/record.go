@@ -0,0 +1,433 @@
+package comm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Record is a single value reported by a meter, a data record of the variable data structure.
+type Record struct {
+	Function Function
+	// Storage is 0 for the current value, other numbers are historic values like the reading at the end of the last
+	// billing period, what each means is specific to the meter.
+	Storage int
+	Tariff  int
+	Subunit int
+
+	Quantity Quantity
+	// Numeric is true when Value holds a number, in the unit of the quantity.
+	Numeric bool
+	Value   float64
+	// Text is set for string values and records with a plain text unit.
+	Text string
+}
+
+// RecordKey identifies a record within a telegram.
+type RecordKey struct {
+	Quantity Quantity
+	Function Function
+	Storage  int
+	Tariff   int
+	Subunit  int
+}
+
+func (r Record) Key() RecordKey {
+	return RecordKey{Quantity: r.Quantity, Function: r.Function, Storage: r.Storage, Tariff: r.Tariff, Subunit: r.Subunit}
+}
+
+// Function is the kind of value of a record.
+type Function byte
+
+const (
+	FunctionInstantaneous Function = iota
+	FunctionMaximum
+	FunctionMinimum
+	FunctionError
+)
+
+func (f Function) String() string {
+	switch f {
+	case FunctionInstantaneous:
+		return "instantaneous"
+	case FunctionMaximum:
+		return "maximum"
+	case FunctionMinimum:
+		return "minimum"
+	case FunctionError:
+		return "error"
+	}
+	return fmt.Sprintf("function %d", byte(f))
+}
+
+// Quantity is what a record measures, decoded from its value information field.
+type Quantity int
+
+const (
+	QuantityUnknown Quantity = iota
+	QuantityEnergy
+	QuantityVolume
+	QuantityMass
+	QuantityPower
+	QuantityVolumeFlow
+	QuantityMassFlow
+	QuantityFlowTemperature
+	QuantityReturnTemperature
+	QuantityTemperatureDifference
+	QuantityExternalTemperature
+	QuantityPressure
+	QuantityOnTime
+	QuantityOperatingTime
+	QuantityVoltage
+	QuantityCurrent
+	QuantityFabricationNumber
+)
+
+var quantities = []struct {
+	name, unit string
+}{
+	QuantityUnknown:               {"unknown", ""},
+	QuantityEnergy:                {"energy", "kWh"},
+	QuantityVolume:                {"volume", "m3"},
+	QuantityMass:                  {"mass", "kg"},
+	QuantityPower:                 {"power", "kW"},
+	QuantityVolumeFlow:            {"volumeFlow", "m3/h"},
+	QuantityMassFlow:              {"massFlow", "kg/h"},
+	QuantityFlowTemperature:       {"flowTemperature", "°C"},
+	QuantityReturnTemperature:     {"returnTemperature", "°C"},
+	QuantityTemperatureDifference: {"temperatureDifference", "K"},
+	QuantityExternalTemperature:   {"externalTemperature", "°C"},
+	QuantityPressure:              {"pressure", "bar"},
+	QuantityOnTime:                {"onTime", "s"},
+	QuantityOperatingTime:         {"operatingTime", "s"},
+	QuantityVoltage:               {"voltage", "V"},
+	QuantityCurrent:               {"current", "A"},
+	QuantityFabricationNumber:     {"fabricationNumber", ""},
+}
+
+func (q Quantity) String() string {
+	if q < 0 || int(q) >= len(quantities) {
+		return fmt.Sprintf("quantity %d", int(q))
+	}
+	return quantities[q].name
+}
+
+// Unit returns the unit values of the quantity are converted to.
+// Energy is always kWh and volume m3, whatever the meter reports them in.
+func (q Quantity) Unit() string {
+	if q < 0 || int(q) >= len(quantities) {
+		return ""
+	}
+	return quantities[q].unit
+}
+
+// ParseQuantity returns the quantity with the given name, as returned by Quantity.String.
+func ParseQuantity(name string) (Quantity, error) {
+	for q, d := range quantities {
+		if d.name == name {
+			return Quantity(q), nil
+		}
+	}
+	return QuantityUnknown, fmt.Errorf("unknown quantity %q", name)
+}
+
+// vifInfo is what we know about a record from its value information block.
+type vifInfo struct {
+	quantity Quantity
+	// scale converts the raw value to the unit of quantity.
+	scale float64
+	text  string
+}
+
+// joules per kWh
+const jPerKWh = 3.6e6
+
+// timeUnitSeconds is the number of seconds in each of the units of on and operating time.
+var timeUnitSeconds = [4]float64{1, 60, 3600, 86400}
+
+// primaryVIF decodes the primary VIF table, ignoring the extension bit.
+func primaryVIF(code byte) vifInfo {
+	n := int(code & 0x07)
+	nn := int(code & 0x03)
+	switch {
+	case code <= 0x07: // Wh
+		return vifInfo{QuantityEnergy, math.Pow10(n - 6), ""}
+	case code <= 0x0F: // J
+		return vifInfo{QuantityEnergy, math.Pow10(n) / jPerKWh, ""}
+	case code <= 0x17: // m3
+		return vifInfo{QuantityVolume, math.Pow10(n - 6), ""}
+	case code <= 0x1F: // kg
+		return vifInfo{QuantityMass, math.Pow10(n - 3), ""}
+	case code <= 0x23:
+		return vifInfo{QuantityOnTime, timeUnitSeconds[nn], ""}
+	case code <= 0x27:
+		return vifInfo{QuantityOperatingTime, timeUnitSeconds[nn], ""}
+	case code <= 0x2F: // W
+		return vifInfo{QuantityPower, math.Pow10(n - 6), ""}
+	case code <= 0x37: // J/h
+		return vifInfo{QuantityPower, math.Pow10(n) / jPerKWh, ""}
+	case code <= 0x3F: // m3/h
+		return vifInfo{QuantityVolumeFlow, math.Pow10(n - 6), ""}
+	case code <= 0x47: // m3/min
+		return vifInfo{QuantityVolumeFlow, math.Pow10(n-7) * 60, ""}
+	case code <= 0x4F: // m3/s
+		return vifInfo{QuantityVolumeFlow, math.Pow10(n-9) * 3600, ""}
+	case code <= 0x57: // kg/h
+		return vifInfo{QuantityMassFlow, math.Pow10(n - 3), ""}
+	case code <= 0x5B:
+		return vifInfo{QuantityFlowTemperature, math.Pow10(nn - 3), ""}
+	case code <= 0x5F:
+		return vifInfo{QuantityReturnTemperature, math.Pow10(nn - 3), ""}
+	case code <= 0x63:
+		return vifInfo{QuantityTemperatureDifference, math.Pow10(nn - 3), ""}
+	case code <= 0x67:
+		return vifInfo{QuantityExternalTemperature, math.Pow10(nn - 3), ""}
+	case code <= 0x6B:
+		return vifInfo{QuantityPressure, math.Pow10(nn - 3), ""}
+	case code == 0x78:
+		return vifInfo{QuantityFabricationNumber, 1, ""}
+	}
+	// dates, averaging and actuality durations, addresses, and units for heat cost allocators
+	return vifInfo{QuantityUnknown, 1, ""}
+}
+
+// extendedVIF decodes the first extension table, following VIF FB.
+func extendedVIF(code byte) vifInfo {
+	n := int(code & 0x01)
+	switch code &^ 0x01 {
+	case 0x00: // MWh
+		return vifInfo{QuantityEnergy, math.Pow10(n + 2), ""}
+	case 0x08: // GJ
+		return vifInfo{QuantityEnergy, math.Pow10(n+8) / jPerKWh, ""}
+	case 0x10: // m3
+		return vifInfo{QuantityVolume, math.Pow10(n + 2), ""}
+	case 0x18: // t
+		return vifInfo{QuantityMass, math.Pow10(n + 5), ""}
+	case 0x28: // MW
+		return vifInfo{QuantityPower, math.Pow10(n + 2), ""}
+	case 0x30: // GJ/h
+		return vifInfo{QuantityPower, math.Pow10(n+8) / jPerKWh, ""}
+	}
+	return vifInfo{QuantityUnknown, 1, ""}
+}
+
+// secondExtendedVIF decodes the second extension table, following VIF FD.
+func secondExtendedVIF(code byte) vifInfo {
+	n := int(code & 0x0F)
+	switch code & 0x70 {
+	case 0x40:
+		return vifInfo{QuantityVoltage, math.Pow10(n - 9), ""}
+	case 0x50:
+		return vifInfo{QuantityCurrent, math.Pow10(n - 12), ""}
+	}
+	return vifInfo{QuantityUnknown, 1, ""}
+}
+
+// decodeRecords decodes the data records that follow the header of a telegram.
+func decodeRecords(data []byte) (records []Record, mfr []byte, more bool, err error) {
+	d := decoder{data: data}
+	for !d.done() {
+		dif := d.next()
+		switch {
+		case dif == 0x2F: // idle filler
+			continue
+		case dif == 0x0F || dif == 0x1F:
+			return records, d.rest(), dif == 0x1F, d.err
+		case dif&0x0F == 0x0F:
+			return records, nil, false, fmt.Errorf("%w: special function DIF %02X", ErrUnsupported, dif)
+		}
+		r := Record{
+			Function: Function((dif >> 4) & 0x03),
+			Storage:  int((dif >> 6) & 0x01),
+		}
+		for i, ext := 0, dif&0x80 != 0; ext; i++ {
+			if i == 10 {
+				return records, nil, false, fmt.Errorf("%w: too many DIFEs", ErrInvalidFrame)
+			}
+			dife := d.next()
+			r.Storage |= int(dife&0x0F) << (1 + 4*i)
+			r.Tariff |= int((dife>>4)&0x03) << (2 * i)
+			r.Subunit |= int((dife>>6)&0x01) << i
+			ext = dife&0x80 != 0
+		}
+		info := d.vib()
+		r.Quantity = info.quantity
+		r.Text = info.text
+		d.value(dif&0x0F, info.scale, &r)
+		if d.err != nil {
+			return records, nil, false, d.err
+		}
+		records = append(records, r)
+	}
+	return records, nil, false, d.err
+}
+
+// decoder reads data records, recording the first error.
+type decoder struct {
+	data []byte
+	i    int
+	err  error
+}
+
+func (d *decoder) done() bool {
+	return d.err != nil || d.i >= len(d.data)
+}
+
+func (d *decoder) next() byte {
+	b := d.take(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if d.i+n > len(d.data) {
+		d.err = fmt.Errorf("%w: record truncated", ErrInvalidFrame)
+		return nil
+	}
+	b := d.data[d.i : d.i+n]
+	d.i += n
+	return b
+}
+
+func (d *decoder) rest() []byte {
+	b := d.data[d.i:]
+	d.i = len(d.data)
+	return b
+}
+
+// vib decodes a value information block.
+func (d *decoder) vib() vifInfo {
+	vif := d.next()
+	plainText := vif&0x7F == 0x7C
+	var info vifInfo
+	switch code := vif & 0x7F; {
+	case vif == 0xFB:
+		vife := d.next()
+		info = extendedVIF(vife & 0x7F)
+		vif = vife
+	case vif == 0xFD:
+		vife := d.next()
+		info = secondExtendedVIF(vife & 0x7F)
+		vif = vife
+	case code == 0x7E || code == 0x7F: // any VIF, or manufacturer specific
+		info = vifInfo{QuantityUnknown, 1, ""}
+	default:
+		info = primaryVIF(code)
+	}
+	for ext := vif&0x80 != 0; ext && d.err == nil; {
+		vife := d.next()
+		switch code := vife & 0x7F; {
+		case code >= 0x70 && code <= 0x77:
+			info.scale *= math.Pow10(int(code&0x07) - 6)
+		case code == 0x7D:
+			info.scale *= 1000
+		}
+		ext = vife&0x80 != 0
+	}
+	if plainText {
+		// the unit follows any VIFEs
+		n := int(d.next())
+		info.quantity, info.text = QuantityUnknown, reverseString(d.take(n))
+	}
+	return info
+}
+
+// value decodes the data of a record, whose type is given by the data field of its DIF.
+func (d *decoder) value(field byte, scale float64, r *Record) {
+	setNumber := func(v float64) {
+		r.Numeric, r.Value = true, v*scale
+	}
+	switch field {
+	case 0x00, 0x08: // no data, or selection for readout
+	case 0x01, 0x02, 0x03, 0x04, 0x06, 0x07:
+		n := [...]int{1: 1, 2: 2, 3: 3, 4: 4, 6: 6, 7: 8}[field]
+		if b := d.take(n); b != nil {
+			setNumber(float64(decodeInt(b)))
+		}
+	case 0x05:
+		if b := d.take(4); b != nil {
+			setNumber(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+		}
+	case 0x09, 0x0A, 0x0B, 0x0C, 0x0E:
+		n := [...]int{9: 1, 10: 2, 11: 3, 12: 4, 14: 6}[field]
+		if b := d.take(n); b != nil {
+			if v, ok := decodeBCD(b); ok {
+				setNumber(float64(v))
+			}
+		}
+	case 0x0D:
+		d.variable(scale, r)
+	}
+}
+
+// variable decodes variable length data, whose first byte is the LVAR that gives its type and length.
+func (d *decoder) variable(scale float64, r *Record) {
+	lvar := d.next()
+	switch {
+	case lvar <= 0xBF:
+		r.Text = reverseString(d.take(int(lvar)))
+	case lvar <= 0xDF:
+		if b := d.take(int(lvar & 0x0F)); b != nil {
+			if v, ok := decodeBCD(b); ok {
+				if lvar >= 0xD0 {
+					v = -v
+				}
+				r.Numeric, r.Value = true, float64(v)*scale
+			}
+		}
+	case lvar <= 0xEF:
+		if b := d.take(int(lvar & 0x0F)); b != nil && len(b) <= 8 {
+			r.Numeric, r.Value = true, float64(decodeInt(b))*scale
+		}
+	default:
+		d.err = fmt.Errorf("%w: LVAR %02X", ErrUnsupported, lvar)
+	}
+}
+
+// decodeInt decodes a little endian two's complement integer of up to 8 bytes.
+func decodeInt(b []byte) int64 {
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	// sign extend
+	shift := 64 - 8*uint(len(b))
+	return int64(v<<shift) >> shift
+}
+
+// decodeBCD decodes a little endian BCD number, an F in the most significant digit means it is negative.
+// Values with other non-decimal digits, which meters use to report errors, aren't valid.
+func decodeBCD(b []byte) (int64, bool) {
+	var v int64
+	neg := false
+	for i := len(b) - 1; i >= 0; i-- {
+		for _, digit := range [2]byte{b[i] >> 4, b[i] & 0x0F} {
+			if digit > 9 {
+				if i == len(b)-1 && digit == 0xF && v == 0 && !neg {
+					neg = true
+					continue
+				}
+				return 0, false
+			}
+			v = v*10 + int64(digit)
+		}
+	}
+	if neg {
+		v = -v
+	}
+	return v, true
+}
+
+// reverseString returns the characters of b in reverse, strings are sent least significant character first.
+func reverseString(b []byte) string {
+	r := make([]byte, len(b))
+	for i, c := range b {
+		r[len(b)-1-i] = c
+	}
+	return string(r)
+}
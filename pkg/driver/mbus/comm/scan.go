@@ -0,0 +1,74 @@
+package comm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ScanPrimary requests data from every primary address, returning the telegrams of the meters that respond.
+// Addresses more than one meter responds to are skipped, those meters can be found using ScanSecondary.
+func (c *Client) ScanPrimary(ctx context.Context) ([]*Telegram, error) {
+	var found []*Telegram
+	for a := 0; a <= MaxPrimaryAddress; a++ {
+		t, err := c.ReadPrimary(ctx, byte(a))
+		switch {
+		case err == nil:
+			found = append(found, t)
+		case ctx.Err() != nil:
+			return found, ctx.Err()
+		case errors.Is(err, ErrNoResponse):
+		default:
+			c.logger.Debug("skipping primary address", zap.String("conn", c.name), zap.Int("address", a), zap.Error(err))
+		}
+	}
+	return found, nil
+}
+
+// ScanSecondary finds meters by their secondary address, returning their telegrams.
+// Addresses are selected using wildcards, which are narrowed down one digit at a time while more than one meter
+// responds, so it takes a few requests per meter regardless of how many meters share a primary address.
+func (c *Client) ScanSecondary(ctx context.Context) ([]*Telegram, error) {
+	var found []*Telegram
+	var search func(mask string, pos int) error
+	search = func(mask string, pos int) error {
+		t, err := c.probe(ctx, mask)
+		switch {
+		case err == nil:
+			found = append(found, t)
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, ErrNoResponse):
+		case errors.Is(err, ErrCollision) && pos < idDigits:
+			for d := byte('0'); d <= '9'; d++ {
+				if err := search(mask[:pos]+string(d)+mask[pos+1:], pos+1); err != nil {
+					return err
+				}
+			}
+		default:
+			c.logger.Debug("skipping secondary address", zap.String("conn", c.name), zap.String("mask", mask), zap.Error(err))
+		}
+		return nil
+	}
+	err := search(strings.Repeat("F", idDigits), 0)
+	return found, err
+}
+
+// probe selects the meters matching mask, reading data from the meter if only one matches.
+func (c *Client) probe(ctx context.Context, mask string) (*Telegram, error) {
+	if err := c.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	if err := c.selectID(ctx, mask); err != nil {
+		return nil, err
+	}
+	t, err := c.readData(ctx, AddressNetwork, "s:"+mask)
+	if err != nil {
+		return nil, fmt.Errorf("read selected meter: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,240 @@
+package comm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CI fields of the responses we decode.
+const (
+	ciLongHeader  = 0x72
+	ciNoHeader    = 0x78
+	ciShortHeader = 0x7A
+)
+
+const idDigits = 8
+
+// Telegram is the data a meter responds with, a header and a list of records.
+type Telegram struct {
+	// Address is the primary address of the meter that responded.
+	Address byte
+	Header  Header
+	Records []Record
+	// ManufacturerData is data following the records in a format specific to the manufacturer.
+	ManufacturerData []byte
+	// MoreRecords is true when the meter has more records than fit in this telegram.
+	MoreRecords bool
+}
+
+// Header identifies the meter and reports its status.
+// Telegrams without a header have an empty ID, those with a short header only have the access number, status,
+// and signature.
+type Header struct {
+	// ID is the 8 digit identification number, which with the manufacturer, version, and medium is the secondary
+	// address of the meter.
+	ID           string
+	Manufacturer string
+	Version      byte
+	Medium       Medium
+	AccessNumber byte
+	Status       byte
+	Signature    uint16
+}
+
+// Find returns the first record with a value whose key is k.
+func (t *Telegram) Find(k RecordKey) (Record, bool) {
+	for _, r := range t.Records {
+		if r.Numeric && r.Key() == k {
+			return r, true
+		}
+	}
+	return Record{}, false
+}
+
+func decodeTelegram(f frame) (*Telegram, error) {
+	t := &Telegram{Address: f.a}
+	data := f.data
+	switch f.ci {
+	case ciLongHeader:
+		if len(data) < 12 {
+			return nil, fmt.Errorf("%w: header is %d bytes, want 12", ErrInvalidFrame, len(data))
+		}
+		t.Header = Header{
+			ID:           decodeID(data[0:4]),
+			Manufacturer: decodeManufacturer(binary.LittleEndian.Uint16(data[4:6])),
+			Version:      data[6],
+			Medium:       Medium(data[7]),
+			AccessNumber: data[8],
+			Status:       data[9],
+			Signature:    binary.LittleEndian.Uint16(data[10:12]),
+		}
+		data = data[12:]
+	case ciShortHeader:
+		if len(data) < 4 {
+			return nil, fmt.Errorf("%w: header is %d bytes, want 4", ErrInvalidFrame, len(data))
+		}
+		t.Header = Header{
+			AccessNumber: data[0],
+			Status:       data[1],
+			Signature:    binary.LittleEndian.Uint16(data[2:4]),
+		}
+		data = data[4:]
+	case ciNoHeader:
+	default:
+		return nil, fmt.Errorf("%w: unsupported CI field %02X", ErrInvalidFrame, f.ci)
+	}
+	if t.Header.Signature != 0 {
+		return nil, fmt.Errorf("%w: records are encrypted", ErrUnsupported)
+	}
+	var err error
+	t.Records, t.ManufacturerData, t.MoreRecords, err = decodeRecords(data)
+	return t, err
+}
+
+// StatusErrors returns descriptions of the errors reported by the status byte of a header.
+func StatusErrors(status byte) []string {
+	var errs []string
+	switch status & 0x03 {
+	case 0x01:
+		errs = append(errs, "application busy")
+	case 0x02:
+		errs = append(errs, "application error")
+	case 0x03:
+		errs = append(errs, "abnormal condition")
+	}
+	if status&0x04 != 0 {
+		errs = append(errs, "power low")
+	}
+	if status&0x08 != 0 {
+		errs = append(errs, "permanent error")
+	}
+	if status&0x10 != 0 {
+		errs = append(errs, "temporary error")
+	}
+	return errs
+}
+
+// ValidateID returns an error if id isn't an 8 digit secondary address.
+func ValidateID(id string) error {
+	if len(id) != idDigits {
+		return fmt.Errorf("secondary address %q must be %d digits", id, idDigits)
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("secondary address %q must be %d digits", id, idDigits)
+		}
+	}
+	return nil
+}
+
+// encodeID encodes the 8 digit id as BCD, F digits are wildcards.
+func encodeID(id string) ([4]byte, error) {
+	var b [4]byte
+	if len(id) != idDigits {
+		return b, fmt.Errorf("secondary address %q must be %d digits", id, idDigits)
+	}
+	for i := 0; i < idDigits; i++ {
+		r := id[i]
+		var n byte
+		switch {
+		case r >= '0' && r <= '9':
+			n = r - '0'
+		case r == 'F' || r == 'f':
+			n = 0xF
+		default:
+			return b, errors.New("secondary address digits must be 0-9 or F")
+		}
+		// the least significant digits come first
+		j := 3 - i/2
+		if i%2 == 0 {
+			b[j] |= n << 4
+		} else {
+			b[j] |= n
+		}
+	}
+	return b, nil
+}
+
+func decodeID(b []byte) string {
+	return fmt.Sprintf("%02X%02X%02X%02X", b[3], b[2], b[1], b[0])
+}
+
+// decodeManufacturer decodes the 3 letter FLAG code, packed 5 bits per letter.
+func decodeManufacturer(m uint16) string {
+	var sb strings.Builder
+	for _, shift := range []int{10, 5, 0} {
+		sb.WriteByte(byte((m>>shift)&0x1F) + 64)
+	}
+	return sb.String()
+}
+
+// Medium is what a meter measures.
+type Medium byte
+
+const (
+	MediumOther               Medium = 0x00
+	MediumOil                 Medium = 0x01
+	MediumElectricity         Medium = 0x02
+	MediumGas                 Medium = 0x03
+	MediumHeat                Medium = 0x04
+	MediumSteam               Medium = 0x05
+	MediumWarmWater           Medium = 0x06
+	MediumWater               Medium = 0x07
+	MediumHeatCostAllocator   Medium = 0x08
+	MediumCompressedAir       Medium = 0x09
+	MediumCoolingOutlet       Medium = 0x0A
+	MediumCoolingInlet        Medium = 0x0B
+	MediumHeatInlet           Medium = 0x0C
+	MediumHeatCooling         Medium = 0x0D
+	MediumBus                 Medium = 0x0E
+	MediumUnknown             Medium = 0x0F
+	MediumHotWater            Medium = 0x15
+	MediumColdWater           Medium = 0x16
+	MediumDualRegisterWater   Medium = 0x17
+	MediumPressure            Medium = 0x18
+	MediumAnalogDigitalSensor Medium = 0x19
+)
+
+var mediumNames = map[Medium]string{
+	MediumOther:               "other",
+	MediumOil:                 "oil",
+	MediumElectricity:         "electricity",
+	MediumGas:                 "gas",
+	MediumHeat:                "heat",
+	MediumSteam:               "steam",
+	MediumWarmWater:           "warm water",
+	MediumWater:               "water",
+	MediumHeatCostAllocator:   "heat cost allocator",
+	MediumCompressedAir:       "compressed air",
+	MediumCoolingOutlet:       "cooling",
+	MediumCoolingInlet:        "cooling (inlet)",
+	MediumHeatInlet:           "heat (inlet)",
+	MediumHeatCooling:         "heat / cooling",
+	MediumBus:                 "bus / system",
+	MediumUnknown:             "unknown",
+	MediumHotWater:            "hot water",
+	MediumColdWater:           "cold water",
+	MediumDualRegisterWater:   "dual register water",
+	MediumPressure:            "pressure",
+	MediumAnalogDigitalSensor: "A/D converter",
+}
+
+func (m Medium) String() string {
+	if n, ok := mediumNames[m]; ok {
+		return n
+	}
+	return fmt.Sprintf("medium %02X", byte(m))
+}
+
+// Quantity returns the quantity a meter of this medium usually bills, or QuantityUnknown.
+func (m Medium) Quantity() Quantity {
+	switch m {
+	case MediumElectricity, MediumHeat, MediumSteam, MediumCoolingOutlet, MediumCoolingInlet, MediumHeatInlet, MediumHeatCooling:
+		return QuantityEnergy
+	case MediumOil, MediumGas, MediumWarmWater, MediumWater, MediumHotWater, MediumColdWater, MediumDualRegisterWater, MediumCompressedAir:
+		return QuantityVolume
+	}
+	return QuantityUnknown
+}
@@ -0,0 +1,110 @@
+package comm
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_decodeTelegram(t *testing.T) {
+	data := []byte{
+		0x78, 0x56, 0x34, 0x12, // ID
+		0x2D, 0x2C, // KAM
+		0x01, 0x04, 0x2A, 0x00, 0x00, 0x00, // version, heat, access number, status, signature
+		0x0C, 0x06, 0x27, 0x04, 0x85, 0x02, // energy, 8 digit BCD, kWh
+		0x0C, 0x14, 0x27, 0x04, 0x85, 0x02, // volume, 8 digit BCD, 0.01 m3
+		0x0B, 0x2D, 0x99, 0x09, 0x00, // power, 6 digit BCD, 100 W
+		0x0A, 0x5A, 0x12, 0x03, // flow temperature, 4 digit BCD, 0.1 °C
+		0x0A, 0x5E, 0x63, 0x01, // return temperature
+		0x4C, 0x06, 0x11, 0x11, 0x00, 0x00, // energy, storage 1
+		0x84, 0x10, 0x06, 0x01, 0x00, 0x00, 0x00, // energy, 32 bit integer, tariff 1
+		0x02, 0xFD, 0x49, 0xE6, 0x00, // voltage, 16 bit integer, V
+		0x0F, 0x01, 0x02, // manufacturer specific
+	}
+	f, err := decodeLong(frame{c: 0x08, a: 5, ci: ciLongHeader, data: data}.bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decodeTelegram(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Telegram{
+		Address: 5,
+		Header: Header{
+			ID:           "12345678",
+			Manufacturer: "KAM",
+			Version:      1,
+			Medium:       MediumHeat,
+			AccessNumber: 0x2A,
+		},
+		Records: []Record{
+			{Quantity: QuantityEnergy, Numeric: true, Value: 2850427},
+			{Quantity: QuantityVolume, Numeric: true, Value: 28504.27},
+			{Quantity: QuantityPower, Numeric: true, Value: 99.9},
+			{Quantity: QuantityFlowTemperature, Numeric: true, Value: 31.2},
+			{Quantity: QuantityReturnTemperature, Numeric: true, Value: 16.3},
+			{Quantity: QuantityEnergy, Storage: 1, Numeric: true, Value: 1111},
+			{Quantity: QuantityEnergy, Tariff: 1, Numeric: true, Value: 1},
+			{Quantity: QuantityVoltage, Numeric: true, Value: 230},
+		},
+		ManufacturerData: []byte{0x01, 0x02},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("decodeTelegram() (-want,+got)\n%s", diff)
+	}
+
+	r, ok := got.Find(RecordKey{Quantity: QuantityEnergy, Storage: 1})
+	if !ok || r.Value != 1111 {
+		t.Errorf("Find(energy storage 1) = %v, %v, want 1111", r.Value, ok)
+	}
+}
+
+func Test_decodeRecords_units(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []byte
+		want    Record
+	}{
+		{"Wh", []byte{0x04, 0x03, 0xE8, 0x03, 0x00, 0x00}, Record{Quantity: QuantityEnergy, Numeric: true, Value: 1}},
+		{"MWh", []byte{0x04, 0xFB, 0x00, 0x0A, 0x00, 0x00, 0x00}, Record{Quantity: QuantityEnergy, Numeric: true, Value: 1000}},
+		{"GJ", []byte{0x04, 0xFB, 0x09, 0x24, 0x00, 0x00, 0x00}, Record{Quantity: QuantityEnergy, Numeric: true, Value: 10000}},
+		{"litres", []byte{0x04, 0x13, 0xD2, 0x04, 0x00, 0x00}, Record{Quantity: QuantityVolume, Numeric: true, Value: 1.234}},
+		{"real", []byte{0x05, 0x16, 0x00, 0x00, 0x20, 0x41}, Record{Quantity: QuantityVolume, Numeric: true, Value: 10}},
+		{"negative int", []byte{0x02, 0x5B, 0xFE, 0xFF}, Record{Quantity: QuantityFlowTemperature, Numeric: true, Value: -2}},
+		{"negative BCD", []byte{0x0A, 0x5B, 0x12, 0xF0}, Record{Quantity: QuantityFlowTemperature, Numeric: true, Value: -12}},
+		{"BCD error", []byte{0x0A, 0x5B, 0xEE, 0xEE}, Record{Quantity: QuantityFlowTemperature}},
+		{"correction", []byte{0x04, 0x83, 0x7D, 0x01, 0x00, 0x00, 0x00}, Record{Quantity: QuantityEnergy, Numeric: true, Value: 1}},
+		{"hours", []byte{0x02, 0x22, 0x02, 0x00}, Record{Quantity: QuantityOnTime, Numeric: true, Value: 7200}},
+		{"plain text", []byte{0x01, 0xFC, 0x74, 0x03, 0x73, 0x67, 0x6B, 0x05}, Record{Text: "kgs", Numeric: true, Value: 0.05}},
+		{"string", []byte{0x0D, 0x78, 0x03, 0x43, 0x42, 0x41}, Record{Quantity: QuantityFabricationNumber, Text: "ABC"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, _, err := decodeRecords(tt.records)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]Record{tt.want}, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("decodeRecords() (-want,+got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_encodeID(t *testing.T) {
+	got, err := encodeID("1234567F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [4]byte{0x7F, 0x56, 0x34, 0x12}; got != want {
+		t.Errorf("encodeID() = % X, want % X", got, want)
+	}
+	if _, err := encodeID("1234567X"); err == nil {
+		t.Error("encodeID(1234567X) expected error")
+	}
+	if got := decodeID([]byte{0x78, 0x56, 0x34, 0x12}); got != "12345678" {
+		t.Errorf("decodeID() = %q, want 12345678", got)
+	}
+}
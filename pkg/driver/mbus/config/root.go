@@ -0,0 +1,284 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mbus/comm"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	DefaultBaudRate   = 2400
+	DefaultTimeout    = 2 * time.Second
+	DefaultPollPeriod = 15 * time.Minute
+	DefaultScanPeriod = 24 * time.Hour
+)
+
+// Root is the configuration of the mbus driver.
+type Root struct {
+	driver.BaseConfig
+
+	// Metadata is applied to the driver name.
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+	// Conn is the bus used by meters that don't have their own, and the bus that is scanned.
+	Conn   *Conn  `json:"conn,omitempty"`
+	Timing Timing `json:"timing,omitempty"`
+	// Scan finds meters on Conn that aren't in Devices.
+	Scan    *Scan    `json:"scan,omitempty"`
+	Devices []Device `json:"devices,omitempty"`
+}
+
+// Conn configures how to reach an M-Bus, either TCP or Serial must be set.
+// Meters that share a Conn share a connection, requests to them are made one at a time.
+type Conn struct {
+	// TCP is the host:port of an M-Bus gateway, which passes bytes to and from the bus unchanged.
+	TCP string `json:"tcp,omitempty"`
+	// Serial is a serial port connected to an M-Bus level converter.
+	Serial *Serial `json:"serial,omitempty"`
+}
+
+// Key uniquely identifies the connection.
+func (c Conn) Key() string {
+	if c.Serial != nil {
+		return "serial:" + c.Serial.Port
+	}
+	return "tcp:" + c.TCP
+}
+
+// Serial configures a serial port.
+type Serial struct {
+	// Port is the path to the serial port, like /dev/ttyUSB0.
+	Port string `json:"port,omitempty"`
+	// BaudRate defaults to 2400.
+	BaudRate int `json:"baudRate,omitempty"`
+	// DataBits defaults to 8.
+	DataBits int `json:"dataBits,omitempty"`
+	// StopBits defaults to 1.
+	StopBits int `json:"stopBits,omitempty"`
+	// Parity is N, E, or O, defaults to E.
+	Parity string `json:"parity,omitempty"`
+}
+
+type Timing struct {
+	// Timeout is how long to wait for a meter to respond, defaults to 2s.
+	Timeout *jsontypes.Duration `json:"timeout,omitempty"`
+	// PollPeriod is how often meters are read unless they have their own, defaults to 15m.
+	PollPeriod *jsontypes.Duration `json:"pollPeriod,omitempty"`
+}
+
+// Scan configures finding meters on the bus.
+// Each meter found is announced as NamePrefix/ID, where ID is its 8 digit secondary address, and read like a Device
+// with no Usage or Produced.
+type Scan struct {
+	// Primary requests data from every primary address.
+	Primary bool `json:"primary,omitempty"`
+	// Secondary searches for secondary addresses, which finds meters that share a primary address.
+	Secondary bool `json:"secondary,omitempty"`
+	// Period is how often to scan again for new meters, defaults to 24h.
+	Period *jsontypes.Duration `json:"period,omitempty"`
+	// NamePrefix defaults to the name of the driver.
+	NamePrefix string `json:"namePrefix,omitempty"`
+}
+
+// Device is a meter, identified by either its primary or secondary address.
+type Device struct {
+	// Name is the Smart Core name of the meter.
+	Name string `json:"name,omitempty"`
+	// Metadata is announced for Name.
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+	// Conn is how to reach the meter, defaults to Root.Conn.
+	Conn *Conn `json:"conn,omitempty"`
+	// PrimaryAddress is the address of the meter on the bus, 0 to 250.
+	PrimaryAddress *int `json:"primaryAddress,omitempty"`
+	// SecondaryAddress is the 8 digit identification number of the meter, used when meters share a primary address.
+	SecondaryAddress string `json:"secondaryAddress,omitempty"`
+	// PollPeriod is how often the meter is read, defaults to Timing.PollPeriod.
+	PollPeriod *jsontypes.Duration `json:"pollPeriod,omitempty"`
+
+	// Usage is the record holding the total consumed, defaults to the current record of the quantity the medium of the
+	// meter is usually billed by, energy or volume.
+	Usage *Record `json:"usage,omitempty"`
+	// Produced is the record holding the total produced, like energy exported by an electricity meter.
+	Produced *Record `json:"produced,omitempty"`
+}
+
+// Record selects a record of the data read from a meter.
+// The first instantaneous record matching all fields is used.
+type Record struct {
+	// Quantity is what the record measures, like energy or volume.
+	// Energy is reported in kWh and volume in m3, whatever units the meter uses.
+	// Defaults to energy or volume, depending on the medium of the meter.
+	Quantity string `json:"quantity,omitempty"`
+	// Storage is 0 for the current value, other numbers are historic values specific to the meter.
+	Storage int `json:"storage,omitempty"`
+	Tariff  int `json:"tariff,omitempty"`
+	Subunit int `json:"subunit,omitempty"`
+}
+
+// Key returns the key of records matching r.
+// The quantity of the key is unknown if r doesn't have one, it depends on the meter.
+func (r Record) Key() (comm.RecordKey, error) {
+	q := comm.QuantityUnknown
+	if r.Quantity != "" {
+		var err error
+		if q, err = comm.ParseQuantity(r.Quantity); err != nil {
+			return comm.RecordKey{}, err
+		}
+	}
+	return comm.RecordKey{Quantity: q, Function: comm.FunctionInstantaneous, Storage: r.Storage, Tariff: r.Tariff, Subunit: r.Subunit}, nil
+}
+
+// ReadBytes decodes and validates data, filling in defaults.
+func ReadBytes(data []byte) (cfg Root, err error) {
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.Timing.Timeout == nil {
+		cfg.Timing.Timeout = &jsontypes.Duration{Duration: DefaultTimeout}
+	}
+	if cfg.Timing.PollPeriod == nil {
+		cfg.Timing.PollPeriod = &jsontypes.Duration{Duration: DefaultPollPeriod}
+	}
+	if cfg.Scan != nil {
+		if cfg.Scan.Period == nil {
+			cfg.Scan.Period = &jsontypes.Duration{Duration: DefaultScanPeriod}
+		}
+		if cfg.Scan.NamePrefix == "" {
+			cfg.Scan.NamePrefix = cfg.Name
+		}
+	}
+	if cfg.Conn != nil {
+		cfg.Conn.defaults()
+	}
+	for i := range cfg.Devices {
+		d := &cfg.Devices[i]
+		if d.Conn == nil {
+			d.Conn = cfg.Conn
+		} else {
+			d.Conn.defaults()
+		}
+		if d.PollPeriod == nil {
+			d.PollPeriod = cfg.Timing.PollPeriod
+		}
+	}
+	return cfg, cfg.validate()
+}
+
+func (c *Conn) defaults() {
+	if c.Serial == nil {
+		return
+	}
+	if c.Serial.BaudRate == 0 {
+		c.Serial.BaudRate = DefaultBaudRate
+	}
+	if c.Serial.DataBits == 0 {
+		c.Serial.DataBits = 8
+	}
+	if c.Serial.StopBits == 0 {
+		c.Serial.StopBits = 1
+	}
+	if c.Serial.Parity == "" {
+		c.Serial.Parity = "E"
+	}
+}
+
+func (r Root) validate() error {
+	var errs []error
+	if r.Conn != nil {
+		if err := r.Conn.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("conn: %w", err))
+		}
+	}
+	if r.Scan != nil {
+		if r.Conn == nil {
+			errs = append(errs, errors.New("scan: conn is required"))
+		}
+		if !r.Scan.Primary && !r.Scan.Secondary {
+			errs = append(errs, errors.New("scan: one of primary and secondary is required"))
+		}
+	}
+	names := make(map[string]bool)
+	for _, d := range r.Devices {
+		if d.Name == "" {
+			errs = append(errs, errors.New("device name is required"))
+			continue
+		}
+		if names[d.Name] {
+			errs = append(errs, fmt.Errorf("device %q: name is used more than once", d.Name))
+		}
+		names[d.Name] = true
+		if err := d.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("device %q: %w", d.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c Conn) validate() error {
+	switch {
+	case c.TCP == "" && c.Serial == nil:
+		return errors.New("tcp or serial is required")
+	case c.TCP != "" && c.Serial != nil:
+		return errors.New("only one of tcp and serial can be set")
+	case c.Serial != nil && c.Serial.Port == "":
+		return errors.New("serial.port is required")
+	case c.TCP != "":
+		if _, _, err := c.TCPHostPort(); err != nil {
+			return fmt.Errorf("tcp: %w", err)
+		}
+	}
+	return nil
+}
+
+// TCPHostPort splits TCP into its host and port.
+func (c Conn) TCPHostPort() (string, int, error) {
+	host, portStr, err := net.SplitHostPort(c.TCP)
+	if err != nil {
+		return "", 0, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("port %q: %w", portStr, err)
+	}
+	return host, port, nil
+}
+
+func (d Device) validate() error {
+	var errs []error
+	if d.Conn == nil {
+		errs = append(errs, errors.New("conn is required"))
+	} else if err := d.Conn.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("conn: %w", err))
+	}
+	switch {
+	case d.PrimaryAddress == nil && d.SecondaryAddress == "":
+		errs = append(errs, errors.New("primaryAddress or secondaryAddress is required"))
+	case d.PrimaryAddress != nil && d.SecondaryAddress != "":
+		errs = append(errs, errors.New("only one of primaryAddress and secondaryAddress can be set"))
+	case d.PrimaryAddress != nil && (*d.PrimaryAddress < 0 || *d.PrimaryAddress > comm.MaxPrimaryAddress):
+		errs = append(errs, fmt.Errorf("primaryAddress %d must be 0 to %d", *d.PrimaryAddress, comm.MaxPrimaryAddress))
+	case d.SecondaryAddress != "":
+		if err := comm.ValidateID(d.SecondaryAddress); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if d.Usage != nil {
+		if _, err := d.Usage.Key(); err != nil {
+			errs = append(errs, fmt.Errorf("usage: %w", err))
+		}
+	}
+	if d.Produced != nil {
+		if _, err := d.Produced.Key(); err != nil {
+			errs = append(errs, fmt.Errorf("produced: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,191 @@
+package mbus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/mbus/comm"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/meter"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/statuspb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+// device reads a meter on a schedule, exposing its usage using the Meter trait.
+type device struct {
+	name       string
+	read       func(ctx context.Context) (*comm.Telegram, error)
+	pollPeriod time.Duration
+	usage      comm.RecordKey
+	produced   *comm.RecordKey
+	statuses   *statuspb.Map
+	logger     *zap.Logger
+
+	model *meter.Model
+	// announced is set once the meter trait has been announced, which waits until we know the units of the meter.
+	// It is only used by run.
+	announced bool
+}
+
+func newDevice(cfg config.Device, client *comm.Client, statuses *statuspb.Map, logger *zap.Logger) (*device, error) {
+	var read func(ctx context.Context) (*comm.Telegram, error)
+	if cfg.PrimaryAddress != nil {
+		read = readPrimary(client, byte(*cfg.PrimaryAddress))
+	} else {
+		read = readSecondary(client, cfg.SecondaryAddress)
+	}
+	d := newDefaultDevice(cfg.Name, read, cfg.PollPeriod.Duration, statuses, logger)
+	var err error
+	if cfg.Usage != nil {
+		if d.usage, err = cfg.Usage.Key(); err != nil {
+			return nil, fmt.Errorf("usage: %w", err)
+		}
+	}
+	if cfg.Produced != nil {
+		produced, err := cfg.Produced.Key()
+		if err != nil {
+			return nil, fmt.Errorf("produced: %w", err)
+		}
+		d.produced = &produced
+	}
+	return d, nil
+}
+
+// newDefaultDevice returns a device that reports the usual usage record for the medium of the meter.
+func newDefaultDevice(name string, read func(ctx context.Context) (*comm.Telegram, error), pollPeriod time.Duration, statuses *statuspb.Map, logger *zap.Logger) *device {
+	return &device{
+		name:       name,
+		read:       read,
+		pollPeriod: pollPeriod,
+		usage:      comm.RecordKey{Function: comm.FunctionInstantaneous},
+		statuses:   statuses,
+		logger:     logger.With(zap.String("device", name)),
+		model:      meter.NewModel(),
+	}
+}
+
+func readPrimary(client *comm.Client, address byte) func(ctx context.Context) (*comm.Telegram, error) {
+	return func(ctx context.Context) (*comm.Telegram, error) {
+		return client.ReadPrimary(ctx, address)
+	}
+}
+
+func readSecondary(client *comm.Client, id string) func(ctx context.Context) (*comm.Telegram, error) {
+	return func(ctx context.Context) (*comm.Telegram, error) {
+		return client.ReadSecondary(ctx, id)
+	}
+}
+
+// run reads the meter every poll period until ctx is done, announcing its traits using a.
+// If first is not nil it is used as the first reading, otherwise the meter is read straight away.
+func (d *device) run(ctx context.Context, a node.Announcer, first *comm.Telegram) {
+	if first != nil {
+		d.handle(a, first)
+	} else {
+		d.poll(ctx, a)
+	}
+	ticker := time.NewTicker(d.pollPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.poll(ctx, a)
+		}
+	}
+}
+
+func (d *device) poll(ctx context.Context, a node.Announcer) {
+	t, err := d.read(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		d.logger.Debug("failed to read meter", zap.Error(err))
+		d.updateProblem("read", gen.StatusLog_NON_FUNCTIONAL, fmt.Sprintf("read failed, %v", err))
+		return
+	}
+	d.handle(a, t)
+}
+
+// handle updates the meter reading and status using the data read from the meter.
+func (d *device) handle(a node.Announcer, t *comm.Telegram) {
+	if errs := comm.StatusErrors(t.Header.Status); len(errs) > 0 {
+		d.updateProblem("status", gen.StatusLog_REDUCED_FUNCTION, "meter reports "+strings.Join(errs, ", "))
+	} else {
+		d.updateProblem("status", gen.StatusLog_NOMINAL, "meter status nominal")
+	}
+
+	usage := withMediumQuantity(d.usage, t.Header.Medium)
+	if usage.Quantity == comm.QuantityUnknown {
+		d.updateProblem("read", gen.StatusLog_NON_FUNCTIONAL,
+			fmt.Sprintf("%s meters have no default usage quantity, configure usage.quantity", t.Header.Medium))
+		return
+	}
+	usageRecord, ok := t.Find(usage)
+	if !ok {
+		d.updateProblem("read", gen.StatusLog_NON_FUNCTIONAL, fmt.Sprintf("no %s record", describeKey(usage)))
+		return
+	}
+	reading := &gen.MeterReading{Usage: float32(usageRecord.Value), EndTime: timestamppb.Now()}
+	var produced comm.RecordKey
+	if d.produced != nil {
+		produced = withMediumQuantity(*d.produced, t.Header.Medium)
+		if r, ok := t.Find(produced); ok {
+			reading.Produced = float32(r.Value)
+			d.updateProblem("read", gen.StatusLog_NOMINAL, "read nominal")
+		} else {
+			d.updateProblem("read", gen.StatusLog_REDUCED_FUNCTION, fmt.Sprintf("no %s record", describeKey(produced)))
+		}
+	} else {
+		d.updateProblem("read", gen.StatusLog_NOMINAL, "read nominal")
+	}
+
+	if !d.announced {
+		d.announced = true
+		info := &meter.InfoServer{MeterReading: &gen.MeterReadingSupport{
+			UsageUnit:    usage.Quantity.Unit(),
+			ProducedUnit: produced.Quantity.Unit(),
+		}}
+		a.Announce(d.name, node.HasTrait(meter.TraitName, node.WithClients(gen.WrapMeterApi(meter.NewModelServer(d.model)), gen.WrapMeterInfo(info))))
+	}
+	_, _ = d.model.UpdateMeterReading(reading)
+}
+
+func (d *device) updateProblem(suffix string, level gen.StatusLog_Level, description string) {
+	d.statuses.UpdateProblem(d.name, &gen.StatusLog_Problem{
+		Name:        d.name + ":" + suffix,
+		Level:       level,
+		Description: description,
+		RecordTime:  timestamppb.Now(),
+	})
+}
+
+// withMediumQuantity returns k with the quantity the medium is usually billed by, if k doesn't have one.
+func withMediumQuantity(k comm.RecordKey, m comm.Medium) comm.RecordKey {
+	if k.Quantity == comm.QuantityUnknown {
+		k.Quantity = m.Quantity()
+	}
+	return k
+}
+
+func describeKey(k comm.RecordKey) string {
+	s := k.Quantity.String()
+	if k.Storage != 0 {
+		s += fmt.Sprintf(" storage %d", k.Storage)
+	}
+	if k.Tariff != 0 {
+		s += fmt.Sprintf(" tariff %d", k.Tariff)
+	}
+	if k.Subunit != 0 {
+		s += fmt.Sprintf(" subunit %d", k.Subunit)
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+package mbus
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mbus/comm"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/statuspb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+	"github.com/smart-core-os/sc-bos/pkg/util/transport"
+)
+
+const DriverName = "mbus"
+
+var Factory driver.Factory = factory{}
+
+type factory struct{}
+
+func (f factory) New(services driver.Services) service.Lifecycle {
+	d := &Driver{
+		announcer: node.NewReplaceAnnouncer(services.Node),
+		logger:    services.Logger.Named(DriverName),
+	}
+	d.Service = service.New(
+		service.MonoApply(d.applyConfig),
+		service.WithParser(config.ReadBytes),
+	)
+	return d
+}
+
+// Driver reads M-Bus meters over serial ports or TCP gateways, exposing them using the Meter trait.
+type Driver struct {
+	*service.Service[config.Root]
+	logger    *zap.Logger
+	announcer *node.ReplaceAnnouncer
+}
+
+func (d *Driver) applyConfig(ctx context.Context, cfg config.Root) error {
+	a := d.announcer.Replace(ctx)
+	statuses := statuspb.NewMap(a)
+
+	if cfg.Metadata != nil {
+		a.Announce(cfg.Name, node.HasMetadata(cfg.Metadata))
+	}
+
+	// meters on the same bus share a client, so their requests don't interleave
+	clients := make(map[string]*comm.Client)
+	clientFor := func(c *config.Conn) *comm.Client {
+		key := c.Key()
+		if client, ok := clients[key]; ok {
+			return client
+		}
+		client := comm.NewClient(key, d.newTransport(c, cfg.Timing.Timeout),
+			comm.WithTimeout(cfg.Timing.Timeout.Duration),
+			comm.WithLogger(d.logger),
+		)
+		clients[key] = client
+		return client
+	}
+
+	var devices []*device
+	for _, devCfg := range cfg.Devices {
+		dev, err := newDevice(devCfg, clientFor(devCfg.Conn), statuses, d.logger)
+		if err != nil {
+			// config is validated when read, so this shouldn't happen
+			return fmt.Errorf("device %q: %w", devCfg.Name, err)
+		}
+		if devCfg.Metadata != nil {
+			a.Announce(devCfg.Name, node.HasMetadata(devCfg.Metadata))
+		}
+		devices = append(devices, dev)
+	}
+
+	var s *scanner
+	if cfg.Scan != nil {
+		s = newScanner(*cfg.Scan, clientFor(cfg.Conn), cfg.Devices, cfg.Timing.PollPeriod.Duration, a, statuses, d.logger)
+	}
+
+	for _, client := range clients {
+		go client.Run(ctx)
+	}
+	for _, dev := range devices {
+		go dev.run(ctx, a, nil)
+	}
+	if s != nil {
+		go s.run(ctx)
+	}
+	return nil
+}
+
+// newTransport returns a transport for the bus c.
+func (d *Driver) newTransport(c *config.Conn, timeout *jsontypes.Duration) transport.Transport {
+	connCfg := transport.ConnectionConfig{Timeout: *timeout, WriteTimeout: *timeout}
+	if c.Serial != nil {
+		return transport.NewSerial(transport.SerialConfig{
+			ConnectionConfig: connCfg,
+			Port:             c.Serial.Port,
+			BaudRate:         c.Serial.BaudRate,
+			DataBits:         c.Serial.DataBits,
+			StopBits:         c.Serial.StopBits,
+			Parity:           c.Serial.Parity,
+		}, d.logger)
+	}
+	host, port, _ := c.TCPHostPort() // validated when the config was read
+	return transport.NewTcp(transport.TcpConfig{ConnectionConfig: connCfg, Ip: host, Port: port}, d.logger)
+}
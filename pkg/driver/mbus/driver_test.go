@@ -0,0 +1,124 @@
+package mbus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mbus/internal/gateway"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+func TestDriver(t *testing.T) {
+	heat := &gateway.Meter{Address: 5, ID: "11111111", Manufacturer: "KAM", Medium: 0x04}
+	heat.SetRecords(0x0C, 0x06, 0x34, 0x12, 0x00, 0x00) // 1234 kWh
+	water := &gateway.Meter{ID: "22222222", Manufacturer: "SEN", Medium: 0x07}
+	water.SetRecords(0x0C, 0x13, 0x78, 0x56, 0x34, 0x12) // 12345678 litres
+	gas := &gateway.Meter{ID: "33333333", Manufacturer: "ELS", Medium: 0x03}
+	gas.SetRecords(0x0C, 0x14, 0x00, 0x10, 0x00, 0x00) // 10 m3
+	electricity := &gateway.Meter{ID: "34444444", Manufacturer: "ABB", Medium: 0x02}
+	electricity.SetRecords(0x04, 0x05, 0x40, 0xE2, 0x01, 0x00) // 12345.6 kWh, 32 bit integer
+	gw, err := gateway.Start(heat, water, gas, electricity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = gw.Close() })
+
+	n := node.New("test")
+	d := Factory.New(driver.Services{Logger: zaptest.NewLogger(t), Node: n})
+	if _, err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = d.Stop() })
+	_, err = d.Configure([]byte(fmt.Sprintf(`{
+		"name": "mbus", "type": "mbus",
+		"conn": {"tcp": %q},
+		"timing": {"timeout": "100ms", "pollPeriod": "100ms"},
+		"scan": {"secondary": true},
+		"devices": [
+			{"name": "flat1/heat", "primaryAddress": 5},
+			{"name": "flat1/water", "secondaryAddress": "22222222"}
+		]
+	}`, gw.Addr().String())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	t.Cleanup(cancel)
+
+	var meterClient gen.MeterApiClient
+	var infoClient gen.MeterInfoClient
+	for _, c := range []any{&meterClient, &infoClient} {
+		if err := n.Client(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	usage := func(name string) float32 {
+		r, err := meterClient.GetMeterReading(ctx, &gen.GetMeterReadingRequest{Name: name})
+		if err != nil {
+			return -1
+		}
+		return r.GetUsage()
+	}
+	unit := func(name string) string {
+		info, err := infoClient.DescribeMeterReading(ctx, &gen.DescribeMeterReadingRequest{Name: name})
+		if err != nil {
+			return ""
+		}
+		return info.GetUsageUnit()
+	}
+
+	t.Run("primary", func(t *testing.T) {
+		eventually(t, ctx, func() bool { return usage("flat1/heat") == 1234 })
+		if got := unit("flat1/heat"); got != "kWh" {
+			t.Errorf("usage unit = %q, want kWh", got)
+		}
+		heat.SetRecords(0x0C, 0x06, 0x00, 0x13, 0x00, 0x00)
+		eventually(t, ctx, func() bool { return usage("flat1/heat") == 1300 })
+	})
+
+	t.Run("secondary", func(t *testing.T) {
+		eventually(t, ctx, func() bool { return approx(usage("flat1/water"), 12345.678) })
+		if got := unit("flat1/water"); got != "m3" {
+			t.Errorf("usage unit = %q, want m3", got)
+		}
+	})
+
+	t.Run("scan", func(t *testing.T) {
+		eventually(t, ctx, func() bool { return usage("mbus/33333333") == 10 })
+		if got := unit("mbus/33333333"); got != "m3" {
+			t.Errorf("usage unit = %q, want m3", got)
+		}
+		eventually(t, ctx, func() bool { return approx(usage("mbus/34444444"), 12345.6) })
+		if got := unit("mbus/34444444"); got != "kWh" {
+			t.Errorf("usage unit = %q, want kWh", got)
+		}
+		// configured meters aren't announced again
+		for _, name := range []string{"mbus/11111111", "mbus/22222222"} {
+			if _, err := meterClient.GetMeterReading(ctx, &gen.GetMeterReadingRequest{Name: name}); err == nil {
+				t.Errorf("%s was announced", name)
+			}
+		}
+	})
+}
+
+func approx(got, want float32) bool {
+	return math.Abs(float64(got-want)) < 0.01
+}
+
+func eventually(t *testing.T, ctx context.Context, f func() bool) {
+	t.Helper()
+	for !f() {
+		select {
+		case <-ctx.Done():
+			t.Fatal("condition not met before timeout")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,251 @@
+// Package gateway is a minimal in-process M-Bus TCP gateway with simulated meters, for testing M-Bus clients.
+//
+// Meters answer SND_NKE, REQ_UD2, and selection by secondary address, including wildcards.
+// When more than one meter answers, their responses collide and are corrupted like they would be on a real bus.
+package gateway
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+)
+
+// Meter is a simulated meter.
+type Meter struct {
+	Address      byte
+	ID           string
+	Manufacturer string
+	Version      byte
+	Medium       byte
+	Status       byte
+
+	mu      sync.Mutex
+	records []byte
+}
+
+// SetRecords sets the data records the meter responds with, encoded as described by EN 13757-3.
+func (m *Meter) SetRecords(records ...byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = records
+}
+
+func (m *Meter) response(access byte) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := encodeID(m.ID)
+	data := append(id[:], 0, 0, m.Version, m.Medium, access, m.Status, 0, 0)
+	binary.LittleEndian.PutUint16(data[4:6], encodeManufacturer(m.Manufacturer))
+	data = append(data, m.records...)
+	return longFrame(0x08, m.Address, 0x72, data)
+}
+
+func (m *Meter) matches(mask []byte) bool {
+	id := encodeID(m.ID)
+	for i, b := range mask[:4] {
+		for _, shift := range []int{0, 4} {
+			want := (b >> shift) & 0x0F
+			if want != 0x0F && want != (id[i]>>shift)&0x0F {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type Gateway struct {
+	l      net.Listener
+	meters []*Meter
+
+	mu       sync.Mutex
+	selected []*Meter
+	access   byte
+	requests int
+	conns    map[net.Conn]struct{}
+}
+
+// Start starts a gateway listening on a random local port, connected to meters.
+func Start(meters ...*Meter) (*Gateway, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	g := &Gateway{l: l, meters: meters, conns: make(map[net.Conn]struct{})}
+	go g.serve()
+	return g, nil
+}
+
+// Addr returns the address the gateway is listening on.
+func (g *Gateway) Addr() *net.TCPAddr {
+	return g.l.Addr().(*net.TCPAddr)
+}
+
+// Requests returns how many requests the gateway has received.
+func (g *Gateway) Requests() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.requests
+}
+
+// Close stops the gateway, closing any connections.
+func (g *Gateway) Close() error {
+	err := g.l.Close()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for c := range g.conns {
+		_ = c.Close()
+	}
+	return err
+}
+
+func (g *Gateway) serve() {
+	for {
+		conn, err := g.l.Accept()
+		if err != nil {
+			return
+		}
+		g.mu.Lock()
+		g.conns[conn] = struct{}{}
+		g.mu.Unlock()
+		go g.handle(conn)
+	}
+}
+
+func (g *Gateway) handle(conn net.Conn) {
+	defer func() {
+		g.mu.Lock()
+		delete(g.conns, conn)
+		g.mu.Unlock()
+		_ = conn.Close()
+	}()
+	r := bufio.NewReader(conn)
+	for {
+		c, a, ci, data, err := readRequest(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		if res := g.respond(c, a, ci, data); len(res) > 0 {
+			if _, err := conn.Write(res); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (g *Gateway) respond(c, a, ci byte, data []byte) []byte {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.requests++
+	switch {
+	case c == 0x40 && a == 0xFD: // SND_NKE deselects
+		g.selected = nil
+		return nil
+	case c == 0x40:
+		return collide(g.addressed(a), func(*Meter) []byte { return []byte{0xE5} })
+	case c&^0x20 == 0x5B: // REQ_UD2
+		g.access++
+		return collide(g.addressed(a), func(m *Meter) []byte { return m.response(g.access) })
+	case c&^0x20 == 0x53 && a == 0xFD && ci == 0x52 && len(data) == 8: // select
+		g.selected = nil
+		for _, m := range g.meters {
+			if m.matches(data) {
+				g.selected = append(g.selected, m)
+			}
+		}
+		return collide(g.selected, func(*Meter) []byte { return []byte{0xE5} })
+	}
+	return nil
+}
+
+func (g *Gateway) addressed(a byte) []*Meter {
+	if a == 0xFD {
+		return g.selected
+	}
+	var ms []*Meter
+	for _, m := range g.meters {
+		if m.Address == a || a == 0xFE {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// collide returns the response of each meter, corrupted if there is more than one.
+func collide(meters []*Meter, res func(m *Meter) []byte) []byte {
+	var out []byte
+	for _, m := range meters {
+		b := res(m)
+		if len(meters) > 1 {
+			// overlapping signals on the bus are read as garbage
+			for j := range b {
+				b[j] ^= 0x5A
+			}
+		}
+		out = append(out, b...)
+	}
+	return out
+}
+
+func readRequest(r *bufio.Reader) (c, a, ci byte, data []byte, err error) {
+	start, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	switch start {
+	case 0x10:
+		b := make([]byte, 4)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return 0, 0, 0, nil, err
+		}
+		return b[0], b[1], 0, nil, nil
+	case 0x68:
+		head := make([]byte, 3)
+		if _, err := io.ReadFull(r, head); err != nil {
+			return 0, 0, 0, nil, err
+		}
+		b := make([]byte, int(head[0])+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return 0, 0, 0, nil, err
+		}
+		return b[0], b[1], b[2], b[3 : len(b)-2], nil
+	}
+	return 0, 0, 0, nil, errors.New("unexpected start byte")
+}
+
+func longFrame(c, a, ci byte, data []byte) []byte {
+	l := byte(3 + len(data))
+	b := append([]byte{0x68, l, l, 0x68, c, a, ci}, data...)
+	var sum byte
+	for _, x := range b[4:] {
+		sum += x
+	}
+	return append(b, sum, 0x16)
+}
+
+func encodeID(id string) [4]byte {
+	var b [4]byte
+	for i := 0; i < len(id) && i < 8; i++ {
+		n := id[i] - '0'
+		j := 3 - i/2
+		if i%2 == 0 {
+			b[j] |= n << 4
+		} else {
+			b[j] |= n
+		}
+	}
+	return b
+}
+
+func encodeManufacturer(s string) uint16 {
+	var m uint16
+	for i := 0; i < len(s) && i < 3; i++ {
+		m = m<<5 | uint16(s[i]-64)&0x1F
+	}
+	return m
+}
@@ -0,0 +1,129 @@
+package mbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mbus/comm"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/statuspb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+// scanner finds meters on a bus, reading the ones that aren't configured as devices.
+type scanner struct {
+	cfg        config.Scan
+	client     *comm.Client
+	pollPeriod time.Duration
+	announcer  node.Announcer
+	statuses   *statuspb.Map
+	logger     *zap.Logger
+
+	// known are the secondary addresses of meters that are already being read.
+	known map[string]bool
+	// knownPrimary are the primary addresses of configured devices, meters respond from their primary address however
+	// they are addressed.
+	knownPrimary map[int]bool
+}
+
+func newScanner(cfg config.Scan, client *comm.Client, devices []config.Device, pollPeriod time.Duration, a node.Announcer, statuses *statuspb.Map, logger *zap.Logger) *scanner {
+	s := &scanner{
+		cfg:          cfg,
+		client:       client,
+		pollPeriod:   pollPeriod,
+		announcer:    a,
+		statuses:     statuses,
+		logger:       logger,
+		known:        make(map[string]bool),
+		knownPrimary: make(map[int]bool),
+	}
+	for _, d := range devices {
+		if d.Conn == nil || d.Conn.Key() != client.Name() {
+			continue
+		}
+		if d.PrimaryAddress != nil {
+			s.knownPrimary[*d.PrimaryAddress] = true
+		} else {
+			s.known[d.SecondaryAddress] = true
+		}
+	}
+	return s
+}
+
+// run scans the bus every scan period until ctx is done.
+func (s *scanner) run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Period.Duration)
+	defer ticker.Stop()
+	for {
+		s.scan(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *scanner) scan(ctx context.Context) {
+	start := time.Now()
+	var found int
+	if s.cfg.Primary {
+		ts, err := s.client.ScanPrimary(ctx)
+		if err != nil {
+			return // ctx is done
+		}
+		for _, t := range ts {
+			if s.add(ctx, t, readPrimary(s.client, t.Address)) {
+				found++
+			}
+		}
+	}
+	if s.cfg.Secondary {
+		ts, err := s.client.ScanSecondary(ctx)
+		if err != nil {
+			return
+		}
+		for _, t := range ts {
+			if s.add(ctx, t, readSecondary(s.client, t.Header.ID)) {
+				found++
+			}
+		}
+	}
+	s.logger.Info("scan complete", zap.Int("new", found), zap.Int("total", len(s.known)),
+		zap.Duration("duration", time.Since(start)))
+}
+
+// add starts reading the meter that sent t, unless it is already being read.
+func (s *scanner) add(ctx context.Context, t *comm.Telegram, read func(ctx context.Context) (*comm.Telegram, error)) bool {
+	h := t.Header
+	if h.ID == "" {
+		s.logger.Warn("ignoring meter without a secondary address", zap.Uint8("primaryAddress", t.Address))
+		return false
+	}
+	if s.known[h.ID] {
+		return false
+	}
+	if s.knownPrimary[int(t.Address)] {
+		// a configured device, which is being read using its primary address
+		s.known[h.ID] = true
+		return false
+	}
+	s.known[h.ID] = true
+	name := s.cfg.NamePrefix + "/" + h.ID
+	s.logger.Debug("found meter", zap.String("name", name), zap.String("manufacturer", h.Manufacturer), zap.Stringer("medium", h.Medium))
+	s.announcer.Announce(name, node.HasMetadata(&traits.Metadata{
+		Id: &traits.Metadata_ID{SerialNumber: h.ID},
+		Product: &traits.Metadata_Product{
+			Manufacturer:    h.Manufacturer,
+			HardwareVersion: fmt.Sprint(h.Version),
+			Kind:            &traits.Metadata_Product_Kind{Title: h.Medium.String() + " meter"},
+		},
+	}))
+	dev := newDefaultDevice(name, read, s.pollPeriod, s.statuses, s.logger)
+	go dev.run(ctx, s.announcer, t)
+	return true
+}
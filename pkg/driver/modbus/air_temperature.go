@@ -0,0 +1,82 @@
+package modbus
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-api/go/types"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+	"github.com/smart-core-os/sc-golang/pkg/trait/airtemperaturepb"
+)
+
+type airTemperature struct {
+	*airtemperaturepb.ModelServer
+	name  string
+	dev   *device
+	cfg   config.AirTemperatureConfig
+	model *airtemperaturepb.Model
+}
+
+func readAirTemperatureConfig(raw []byte) (cfg config.AirTemperatureConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newAirTemperature(dev *device, raw config.RawTrait) (*airTemperature, error) {
+	cfg, err := readAirTemperatureConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPoints(dev, cfg.AmbientTemperature, cfg.TemperatureSetPoint, cfg.AmbientHumidity); err != nil {
+		return nil, err
+	}
+	model := airtemperaturepb.NewModel()
+	return &airTemperature{
+		ModelServer: airtemperaturepb.NewModelServer(model),
+		name:        traitName(dev, raw),
+		dev:         dev,
+		cfg:         cfg,
+		model:       model,
+	}, nil
+}
+
+func (t *airTemperature) AnnounceSelf(a node.Announcer) node.Undo {
+	return a.Announce(t.name, withMetadata(t.cfg.Trait,
+		node.HasTrait(trait.AirTemperature, node.WithClients(airtemperaturepb.WrapApi(t))),
+	)...)
+}
+
+// UpdateAirTemperature writes the set point to the device, other fields can't be updated.
+func (t *airTemperature) UpdateAirTemperature(ctx context.Context, request *traits.UpdateAirTemperatureRequest) (*traits.AirTemperature, error) {
+	setPoint := request.GetState().GetTemperatureSetPoint()
+	if setPoint == nil {
+		return nil, status.Error(codes.InvalidArgument, "only the temperature set point can be updated")
+	}
+	if t.cfg.TemperatureSetPoint == "" {
+		return nil, status.Error(codes.Unimplemented, "temperature set point not configured")
+	}
+	if err := t.dev.write(ctx, t.cfg.TemperatureSetPoint, setPoint.ValueCelsius); err != nil {
+		return nil, err
+	}
+	return t.model.GetAirTemperature()
+}
+
+func (t *airTemperature) update(values map[string]float64) {
+	data := &traits.AirTemperature{}
+	if v, ok := values[t.cfg.AmbientTemperature]; ok && t.cfg.AmbientTemperature != "" {
+		data.AmbientTemperature = &types.Temperature{ValueCelsius: v}
+	}
+	if v, ok := values[t.cfg.TemperatureSetPoint]; ok && t.cfg.TemperatureSetPoint != "" {
+		data.TemperatureGoal = &traits.AirTemperature_TemperatureSetPoint{TemperatureSetPoint: &types.Temperature{ValueCelsius: v}}
+	}
+	if v, ok := float32Value(values, t.cfg.AmbientHumidity); ok {
+		data.AmbientHumidity = &v
+	}
+	_, _ = t.model.UpdateAirTemperature(data)
+}
@@ -0,0 +1,114 @@
+package modbus
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/comm"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/config"
+)
+
+// block is a range of addresses in a table read using a single request.
+type block struct {
+	table   config.Table
+	address uint16
+	size    int
+	points  []config.Point
+}
+
+func (b block) String() string {
+	return fmt.Sprintf("%s %d-%d", b.table, b.address, int(b.address)+b.size-1)
+}
+
+// planBlocks groups points into as few blocks as possible.
+// Points in the same table are read together when there are at most maxGap unused addresses between them,
+// and the block doesn't exceed the maximum a single request can read.
+// A negative maxGap reads each point on its own.
+func planBlocks(points []config.Point, maxGap int) []block {
+	sorted := slices.Clone(points)
+	slices.SortStableFunc(sorted, func(a, b config.Point) int {
+		if c := cmp.Compare(a.Table, b.Table); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Address, b.Address)
+	})
+
+	var blocks []block
+	for _, p := range sorted {
+		if n := len(blocks); n > 0 && maxGap >= 0 {
+			last := &blocks[n-1]
+			end := int(last.address) + last.size
+			newEnd := max(end, int(p.Address)+p.Size())
+			if last.table == p.Table && int(p.Address)-end <= maxGap && newEnd-int(last.address) <= maxQuantity(p.Table) {
+				last.size = newEnd - int(last.address)
+				last.points = append(last.points, p)
+				continue
+			}
+		}
+		blocks = append(blocks, block{table: p.Table, address: p.Address, size: p.Size(), points: []config.Point{p}})
+	}
+	return blocks
+}
+
+func maxQuantity(t config.Table) int {
+	if t.IsBits() {
+		return comm.MaxReadBits
+	}
+	return comm.MaxReadRegisters
+}
+
+// readBlock reads b from unit and decodes the value of each of its points.
+func readBlock(ctx context.Context, client *comm.Client, unit uint8, b block) (map[string]float64, error) {
+	values := make(map[string]float64, len(b.points))
+	quantity := uint16(b.size)
+	switch b.table {
+	case config.Coils, config.DiscreteInputs:
+		read := client.ReadCoils
+		if b.table == config.DiscreteInputs {
+			read = client.ReadDiscreteInputs
+		}
+		bits, err := read(ctx, unit, b.address, quantity)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range b.points {
+			values[p.Name] = p.DecodeBit(bits[p.Address-b.address])
+		}
+	default:
+		read := client.ReadHoldingRegisters
+		if b.table == config.InputRegisters {
+			read = client.ReadInputRegisters
+		}
+		regs, err := read(ctx, unit, b.address, quantity)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range b.points {
+			off := int(p.Address - b.address)
+			v, err := p.Decode(regs[off : off+p.Size()])
+			if err != nil {
+				return nil, fmt.Errorf("point %q: %w", p.Name, err)
+			}
+			values[p.Name] = v
+		}
+	}
+	return values, nil
+}
+
+// writePoint writes v to the point p of unit.
+func writePoint(ctx context.Context, client *comm.Client, unit uint8, p config.Point, v float64) error {
+	switch p.Table {
+	case config.Coils:
+		return client.WriteCoils(ctx, unit, p.Address, p.EncodeBit(v))
+	case config.HoldingRegisters:
+		regs, err := p.Encode(v)
+		if err != nil {
+			return err
+		}
+		return client.WriteRegisters(ctx, unit, p.Address, regs...)
+	default:
+		return fmt.Errorf("point %q: %s can't be written", p.Name, p.Table)
+	}
+}
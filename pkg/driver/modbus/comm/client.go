@@ -0,0 +1,317 @@
+// Package comm implements the Modbus protocol over TCP and RTU serial lines.
+package comm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+	"github.com/smart-core-os/sc-bos/pkg/util/transport"
+)
+
+// ErrNotConnected is returned when a request times out waiting for the connection to be made.
+var ErrNotConnected = errors.New("not connected")
+
+// DefaultBaudRate is the baud rate of RTU serial lines if none is configured, the default of the Modbus specification.
+const DefaultBaudRate = 19200
+
+// Client makes requests to Modbus devices over a single connection, to a TCP server or gateway or to a serial line.
+// Requests are made one at a time.
+// The connection is a [transport.Connection], it's made when the first request is made and made again after any
+// error communicating, failures to connect are retried with an exponential backoff.
+// Requests wait for the connection, up to their timeout.
+type Client struct {
+	name   string
+	conn   *transport.Connection
+	framer framer
+	// frameGap is the minimum silence between frames, required by RTU.
+	frameGap time.Duration
+	timeout  time.Duration
+	// backoff between attempts to connect, for clients that create their own connection
+	backoffMin, backoffMax time.Duration
+	logger                 *zap.Logger
+
+	mu        sync.Mutex
+	lastFrame time.Time
+	stop      func() // stops connecting, nil until the first request
+}
+
+// Option configures a Client.
+type Option func(c *Client)
+
+// WithTimeout sets how long each request, including waiting for the connection, can take. Defaults to 5s.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithBackoff sets the delay between failed attempts to connect, doubling from start up to max.
+// Defaults to 1s and 1m.
+// Clients created using NewClient use the backoff of the connection they are given.
+func WithBackoff(start, max time.Duration) Option {
+	return func(c *Client) {
+		c.backoffMin, c.backoffMax = start, max
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewTCPClient returns a Client that connects to the Modbus TCP server at address, as host:port.
+func NewTCPClient(address string, opts ...Option) (*Client, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("port %q: %w", portStr, err)
+	}
+	c := newClient(address, &tcpFramer{}, opts...)
+	tcp := transport.NewTcp(transport.TcpConfig{ConnectionConfig: c.connectionConfig(), Ip: host, Port: port}, c.logger)
+	c.conn = tcp.Connection
+	return c, nil
+}
+
+// NewRTUClient returns a Client that talks Modbus RTU over the serial port described by cfg.
+// The baud rate defaults to DefaultBaudRate, cfg.ConnectionConfig is replaced using the options.
+func NewRTUClient(cfg transport.SerialConfig, opts ...Option) *Client {
+	if cfg.BaudRate == 0 {
+		cfg.BaudRate = DefaultBaudRate
+	}
+	c := newClient(cfg.Port, rtuFramer{}, opts...)
+	c.frameGap = rtuFrameGap(cfg.BaudRate)
+	cfg.ConnectionConfig = c.connectionConfig()
+	c.conn = transport.NewSerial(cfg, c.logger).Connection
+	return c
+}
+
+// NewClient returns a Client that makes requests over conn.
+// When rtu is true RTU framing is used, otherwise TCP framing.
+// This is useful for tunnelling Modbus over other transports, and for tests.
+// Requests can only be abandoned if reads of conn time out.
+func NewClient(name string, conn *transport.Connection, rtu bool, opts ...Option) *Client {
+	var f framer = &tcpFramer{}
+	if rtu {
+		f = rtuFramer{}
+	}
+	c := newClient(name, f, opts...)
+	c.conn = conn
+	return c
+}
+
+func newClient(name string, f framer, opts ...Option) *Client {
+	c := &Client{
+		name:       name,
+		framer:     f,
+		timeout:    5 * time.Second,
+		backoffMin: time.Second,
+		backoffMax: time.Minute,
+		logger:     zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// connectionConfig returns the config of the connection for clients that create their own.
+// Reads time out with the request so a device that doesn't respond doesn't hold up the connection.
+func (c *Client) connectionConfig() transport.ConnectionConfig {
+	return transport.ConnectionConfig{
+		Timeout:      jsontypes.Duration{Duration: c.timeout},
+		ReadTimeout:  jsontypes.Duration{Duration: c.timeout},
+		WriteTimeout: jsontypes.Duration{Duration: c.timeout},
+		MinBackoff:   jsontypes.Duration{Duration: c.backoffMin},
+		MaxBackoff:   jsontypes.Duration{Duration: c.backoffMax},
+	}
+}
+
+// rtuFrameGap returns the silence of 3.5 characters required between RTU frames, which is fixed above 19200 baud.
+func rtuFrameGap(baud int) time.Duration {
+	if baud > 19200 {
+		return 1750 * time.Microsecond
+	}
+	// 11 bits per character
+	return time.Duration(float64(time.Second) * 3.5 * 11 / float64(baud))
+}
+
+// Close stops connecting and closes the connection, the client can't be used afterwards.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		c.stop()
+	}
+	c.stop = func() {} // don't start connecting again
+	return c.conn.Close()
+}
+
+// connect starts connecting in the background, if it hasn't already.
+func (c *Client) connect() {
+	if c.stop != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = c.conn.Connect(ctx)
+	}()
+	c.stop = func() {
+		cancel()
+		<-done // so the connection isn't dialled again after it's closed
+	}
+}
+
+// ReadCoils reads quantity coils starting at address from unit.
+func (c *Client) ReadCoils(ctx context.Context, unit byte, address, quantity uint16) ([]bool, error) {
+	return c.readBits(ctx, unit, FuncReadCoils, address, quantity)
+}
+
+// ReadDiscreteInputs reads quantity discrete inputs starting at address from unit.
+func (c *Client) ReadDiscreteInputs(ctx context.Context, unit byte, address, quantity uint16) ([]bool, error) {
+	return c.readBits(ctx, unit, FuncReadDiscreteInputs, address, quantity)
+}
+
+// ReadHoldingRegisters reads quantity holding registers starting at address from unit.
+func (c *Client) ReadHoldingRegisters(ctx context.Context, unit byte, address, quantity uint16) ([]uint16, error) {
+	return c.readRegisters(ctx, unit, FuncReadHoldingRegisters, address, quantity)
+}
+
+// ReadInputRegisters reads quantity input registers starting at address from unit.
+func (c *Client) ReadInputRegisters(ctx context.Context, unit byte, address, quantity uint16) ([]uint16, error) {
+	return c.readRegisters(ctx, unit, FuncReadInputRegisters, address, quantity)
+}
+
+// WriteCoils writes values to the coils starting at address of unit.
+// A single value is written using the write single coil function.
+func (c *Client) WriteCoils(ctx context.Context, unit byte, address uint16, values ...bool) error {
+	var req pdu
+	switch n := len(values); {
+	case n == 0:
+		return nil
+	case n == 1:
+		v := uint16(0x0000)
+		if values[0] {
+			v = 0xFF00
+		}
+		req = newPDU(FuncWriteSingleCoil, be16(address)...).appendUint16(v)
+	case n > MaxWriteBits:
+		return fmt.Errorf("can't write more than %d coils, got %d", MaxWriteBits, n)
+	default:
+		data := packBits(values)
+		req = newPDU(FuncWriteMultipleCoils, be16(address)...).appendUint16(uint16(n))
+		req = append(append(req, byte(len(data))), data...)
+	}
+	_, err := c.do(ctx, unit, req)
+	return err
+}
+
+// WriteRegisters writes values to the holding registers starting at address of unit.
+// A single value is written using the write single register function.
+func (c *Client) WriteRegisters(ctx context.Context, unit byte, address uint16, values ...uint16) error {
+	var req pdu
+	switch n := len(values); {
+	case n == 0:
+		return nil
+	case n == 1:
+		req = newPDU(FuncWriteSingleRegister, be16(address)...).appendUint16(values[0])
+	case n > MaxWriteRegisters:
+		return fmt.Errorf("can't write more than %d registers, got %d", MaxWriteRegisters, n)
+	default:
+		data := packRegisters(values)
+		req = newPDU(FuncWriteMultipleRegisters, be16(address)...).appendUint16(uint16(n))
+		req = append(append(req, byte(len(data))), data...)
+	}
+	_, err := c.do(ctx, unit, req)
+	return err
+}
+
+func (c *Client) readBits(ctx context.Context, unit, function byte, address, quantity uint16) ([]bool, error) {
+	if quantity < 1 || quantity > MaxReadBits {
+		return nil, fmt.Errorf("can only read 1 to %d bits, got %d", MaxReadBits, quantity)
+	}
+	res, err := c.do(ctx, unit, readRequest(function, address, quantity))
+	if err != nil {
+		return nil, err
+	}
+	return decodeBits(res, quantity)
+}
+
+func (c *Client) readRegisters(ctx context.Context, unit, function byte, address, quantity uint16) ([]uint16, error) {
+	if quantity < 1 || quantity > MaxReadRegisters {
+		return nil, fmt.Errorf("can only read 1 to %d registers, got %d", MaxReadRegisters, quantity)
+	}
+	res, err := c.do(ctx, unit, readRequest(function, address, quantity))
+	if err != nil {
+		return nil, err
+	}
+	return decodeRegisters(res, quantity)
+}
+
+// do sends req to unit and returns the response.
+func (c *Client) do(ctx context.Context, unit byte, req pdu) (pdu, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	c.connect()
+	if !transport.WaitConnected(ctx, c.conn) {
+		return nil, fmt.Errorf("%w to %s: %w", ErrNotConnected, c.name, ctx.Err())
+	}
+
+	if c.frameGap > 0 {
+		if wait := c.frameGap - time.Since(c.lastFrame); wait > 0 {
+			time.Sleep(wait)
+		}
+		defer func() { c.lastFrame = time.Now() }()
+	}
+	txID, err := c.framer.writeRequest(c.conn, unit, req)
+	if err == nil {
+		var res pdu
+		res, err = c.framer.readResponse(eofReader{c.conn}, unit, txID)
+		if err == nil {
+			return res, checkResponse(req, res)
+		}
+	}
+	// the connection is in an unknown state, start again with a new one
+	c.logger.Debug("reconnecting after error", zap.String("conn", c.name), zap.Error(err))
+	c.conn.Reconnect()
+	if cause := ctx.Err(); cause != nil || errors.Is(err, os.ErrDeadlineExceeded) {
+		if cause == nil {
+			cause = context.DeadlineExceeded
+		}
+		err = fmt.Errorf("%w: %w", cause, err)
+	}
+	return nil, err
+}
+
+// eofReader reports the end of the connection as an error.
+// A transport.Connection returns no data and no error when the other end closes the connection,
+// which would otherwise leave a reader waiting for the rest of a frame.
+type eofReader struct {
+	r io.Reader
+}
+
+func (e eofReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if n == 0 && err == nil && len(p) > 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return n, err
+}
@@ -0,0 +1,250 @@
+package comm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/smart-core-os/sc-bos/pkg/util/transport"
+)
+
+func TestClient_TCP(t *testing.T) {
+	server := NewServer()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+	go func() { _ = server.Serve(lis) }()
+
+	client, err := NewTCPClient(lis.Addr().String(), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	testClient(t, server, client)
+
+	t.Run("unknown unit", func(t *testing.T) {
+		_, err := client.ReadHoldingRegisters(context.Background(), 99, 0, 1)
+		var ex *Exception
+		if !errors.As(err, &ex) || ex.Code != GatewayTargetDeviceFailedToRespond {
+			t.Fatalf("want gateway exception, got %v", err)
+		}
+	})
+}
+
+func TestClient_RTU(t *testing.T) {
+	server := NewServer()
+	pipe := &pipeDialer{serve: server.ServeRTU, readTimeout: 100 * time.Millisecond}
+	client := NewClient("pipe", pipe.connection(), true, WithTimeout(time.Second))
+	t.Cleanup(func() { _ = client.Close() })
+	testClient(t, server, client)
+
+	t.Run("unknown unit", func(t *testing.T) {
+		// other devices on the line don't respond
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := client.ReadHoldingRegisters(ctx, 99, 0, 1)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("want deadline exceeded, got %v", err)
+		}
+	})
+}
+
+func testClient(t *testing.T, server *Server, client *Client) {
+	t.Helper()
+	ctx := context.Background()
+	unit := server.Unit(1)
+	unit.SetCoils(0, true, false, true, true, false, false, false, false, true)
+	unit.SetDiscreteInputs(10, false, true)
+	unit.SetHoldingRegisters(100, 1, 2, 3, 4)
+	unit.SetInputRegisters(200, 0xFFFF, 0x1234)
+
+	t.Run("read coils", func(t *testing.T) {
+		got, err := client.ReadCoils(ctx, 1, 0, 9)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []bool{true, false, true, true, false, false, false, false, true}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("(-want,+got)\n%s", diff)
+		}
+	})
+	t.Run("read discrete inputs", func(t *testing.T) {
+		got, err := client.ReadDiscreteInputs(ctx, 1, 10, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]bool{false, true}, got); diff != "" {
+			t.Fatalf("(-want,+got)\n%s", diff)
+		}
+	})
+	t.Run("read registers", func(t *testing.T) {
+		got, err := client.ReadHoldingRegisters(ctx, 1, 101, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]uint16{2, 3, 4}, got); diff != "" {
+			t.Fatalf("(-want,+got)\n%s", diff)
+		}
+		got, err = client.ReadInputRegisters(ctx, 1, 200, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]uint16{0xFFFF, 0x1234}, got); diff != "" {
+			t.Fatalf("(-want,+got)\n%s", diff)
+		}
+	})
+	t.Run("write", func(t *testing.T) {
+		if err := client.WriteRegisters(ctx, 1, 100, 10); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.WriteRegisters(ctx, 1, 102, 30, 40); err != nil {
+			t.Fatal(err)
+		}
+		regs, _ := unit.HoldingRegisters(100, 4)
+		if diff := cmp.Diff([]uint16{10, 2, 30, 40}, regs); diff != "" {
+			t.Fatalf("registers (-want,+got)\n%s", diff)
+		}
+		if err := client.WriteCoils(ctx, 1, 1, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.WriteCoils(ctx, 1, 2, false, false); err != nil {
+			t.Fatal(err)
+		}
+		coils, _ := unit.Coils(0, 4)
+		if diff := cmp.Diff([]bool{true, true, false, false}, coils); diff != "" {
+			t.Fatalf("coils (-want,+got)\n%s", diff)
+		}
+	})
+	t.Run("illegal address", func(t *testing.T) {
+		_, err := client.ReadHoldingRegisters(ctx, 1, 103, 2)
+		var ex *Exception
+		if !errors.As(err, &ex) || ex.Code != IllegalDataAddress {
+			t.Fatalf("want illegal address exception, got %v", err)
+		}
+		// exceptions don't break the connection
+		if _, err := client.ReadHoldingRegisters(ctx, 1, 100, 1); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestClient_Reconnect(t *testing.T) {
+	server := NewServer()
+	server.Unit(1).SetHoldingRegisters(0, 42)
+	pipe := &pipeDialer{serve: server.ServeTCP, readTimeout: time.Second, refuse: 1}
+	client := NewClient("pipe", pipe.connection(), false, WithTimeout(time.Second))
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	// the first attempt to connect fails, the request waits for the next
+	got, err := client.ReadHoldingRegisters(ctx, 1, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != 42 || pipe.dialCount() != 2 {
+		t.Fatalf("got %v after %d dials, want [42] after 2", got, pipe.dialCount())
+	}
+
+	// the connection breaks, the next request fails and the one after reconnects
+	_ = pipe.current().Close()
+	if _, err := client.ReadHoldingRegisters(ctx, 1, 0, 1); err == nil {
+		t.Fatal("want error on broken connection")
+	}
+	got, err = client.ReadHoldingRegisters(ctx, 1, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != 42 || pipe.dialCount() != 3 {
+		t.Fatalf("got %v after %d dials, want [42] after 3", got, pipe.dialCount())
+	}
+}
+
+func TestClient_NotConnected(t *testing.T) {
+	pipe := &pipeDialer{serve: NewServer().ServeTCP, refuse: -1}
+	client := NewClient("pipe", pipe.connection(), false, WithTimeout(50*time.Millisecond))
+	t.Cleanup(func() { _ = client.Close() })
+	if _, err := client.ReadHoldingRegisters(context.Background(), 1, 0, 1); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("want ErrNotConnected, got %v", err)
+	}
+}
+
+// pipeDialer connects to a server using net.Pipe, each dial makes a new pipe.
+type pipeDialer struct {
+	serve       func(rw io.ReadWriter) error
+	readTimeout time.Duration
+	refuse      int // the number of dials that fail, -1 for all of them
+
+	mu    sync.Mutex
+	dials int
+	conn  net.Conn
+}
+
+// connection returns a transport.Connection that uses p, reconnecting after 10ms.
+func (p *pipeDialer) connection() *transport.Connection {
+	return transport.NewConnection(transport.ConnectionConfig{}, backoff.NewConstantBackOff(10*time.Millisecond), p.dial, p)
+}
+
+func (p *pipeDialer) dial() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dials++
+	if p.refuse < 0 || p.dials <= p.refuse {
+		return errors.New("refused")
+	}
+	if p.conn != nil {
+		_ = p.conn.Close()
+	}
+	c, s := net.Pipe()
+	go func() {
+		defer s.Close()
+		_ = p.serve(s)
+	}()
+	p.conn = c
+	return nil
+}
+
+func (p *pipeDialer) dialCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.dials
+}
+
+func (p *pipeDialer) current() net.Conn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.conn
+}
+
+func (p *pipeDialer) Read(b []byte) (int, error) {
+	c := p.current()
+	if c == nil {
+		return 0, net.ErrClosed
+	}
+	_ = c.SetReadDeadline(time.Now().Add(p.readTimeout))
+	return c.Read(b)
+}
+
+func (p *pipeDialer) Write(b []byte) (int, error) {
+	c := p.current()
+	if c == nil {
+		return 0, net.ErrClosed
+	}
+	return c.Write(b)
+}
+
+func (p *pipeDialer) Close() error {
+	c := p.current()
+	if c == nil {
+		return nil
+	}
+	return c.Close()
+}
@@ -0,0 +1,174 @@
+package comm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// framer wraps protocol data units for sending over a connection.
+type framer interface {
+	// writeRequest writes req addressed to unit to w, returning a value that identifies the response.
+	writeRequest(w io.Writer, unit byte, req pdu) (txID uint16, err error)
+	// readResponse reads a response from r to the request identified by txID.
+	readResponse(r io.Reader, unit byte, txID uint16) (pdu, error)
+}
+
+// tcpFramer frames requests using the Modbus application protocol header used by Modbus TCP.
+type tcpFramer struct {
+	nextID uint16
+}
+
+const (
+	mbapHeaderLength = 7
+	tcpProtocolID    = 0
+)
+
+func (f *tcpFramer) writeRequest(w io.Writer, unit byte, req pdu) (uint16, error) {
+	f.nextID++
+	_, err := w.Write(encodeTCP(f.nextID, unit, req))
+	return f.nextID, err
+}
+
+func (f *tcpFramer) readResponse(r io.Reader, unit byte, txID uint16) (pdu, error) {
+	gotID, gotUnit, res, err := readTCP(r)
+	if err != nil {
+		return nil, err
+	}
+	if gotID != txID || gotUnit != unit {
+		return nil, fmt.Errorf("%w: transaction %d unit %d, want transaction %d unit %d", errInvalidResponse, gotID, gotUnit, txID, unit)
+	}
+	return res, nil
+}
+
+func encodeTCP(txID uint16, unit byte, p pdu) []byte {
+	frame := make([]byte, mbapHeaderLength, mbapHeaderLength+len(p))
+	binary.BigEndian.PutUint16(frame[0:], txID)
+	binary.BigEndian.PutUint16(frame[2:], tcpProtocolID)
+	binary.BigEndian.PutUint16(frame[4:], uint16(len(p)+1))
+	frame[6] = unit
+	return append(frame, p...)
+}
+
+func readTCP(r io.Reader) (txID uint16, unit byte, p pdu, err error) {
+	var header [mbapHeaderLength]byte
+	if _, err = io.ReadFull(r, header[:]); err != nil {
+		return
+	}
+	txID = binary.BigEndian.Uint16(header[0:])
+	if protocol := binary.BigEndian.Uint16(header[2:]); protocol != tcpProtocolID {
+		err = fmt.Errorf("%w: protocol %d", errInvalidResponse, protocol)
+		return
+	}
+	length := int(binary.BigEndian.Uint16(header[4:]))
+	if length < 2 || length > maxPDULength+1 {
+		err = fmt.Errorf("%w: length %d", errInvalidResponse, length)
+		return
+	}
+	unit = header[6]
+	p = make(pdu, length-1)
+	_, err = io.ReadFull(r, p)
+	return
+}
+
+// rtuFramer frames requests for Modbus RTU over a serial line, with a trailing CRC.
+type rtuFramer struct{}
+
+func (rtuFramer) writeRequest(w io.Writer, unit byte, req pdu) (uint16, error) {
+	_, err := w.Write(encodeRTU(unit, req))
+	return 0, err
+}
+
+func (rtuFramer) readResponse(r io.Reader, unit byte, _ uint16) (pdu, error) {
+	gotUnit, res, err := readRTU(r, rtuResponseLength)
+	if err != nil {
+		return nil, err
+	}
+	if gotUnit != unit {
+		return nil, fmt.Errorf("%w: unit %d, want %d", errInvalidResponse, gotUnit, unit)
+	}
+	return res, nil
+}
+
+func encodeRTU(unit byte, p pdu) []byte {
+	frame := make([]byte, 0, len(p)+3)
+	frame = append(frame, unit)
+	frame = append(frame, p...)
+	return binary.LittleEndian.AppendUint16(frame, crc16(frame))
+}
+
+// readRTU reads an RTU frame from r.
+// RTU frames don't include their length, so dataLength returns how many more bytes of data follow those read so far.
+// It is called with the function code then with each byte it asks for until it returns 0.
+func readRTU(r io.Reader, dataLength func(function byte, data []byte) int) (unit byte, p pdu, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(r, head[:]); err != nil {
+		return
+	}
+	frame := head[:]
+	for n := dataLength(head[1], nil); n > 0; n = dataLength(head[1], frame[2:]) {
+		if len(frame)+n > maxPDULength+1 {
+			err = fmt.Errorf("%w: frame too long", errInvalidResponse)
+			return
+		}
+		buf := make([]byte, n)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return
+		}
+		frame = append(frame, buf...)
+	}
+	var crc [2]byte
+	if _, err = io.ReadFull(r, crc[:]); err != nil {
+		return
+	}
+	if got, want := binary.LittleEndian.Uint16(crc[:]), crc16(frame); got != want {
+		err = fmt.Errorf("%w: crc 0x%04x, want 0x%04x", errInvalidResponse, got, want)
+		return
+	}
+	return frame[0], frame[1:], nil
+}
+
+// rtuResponseLength returns how much more data follows data in a response with the given function code.
+func rtuResponseLength(function byte, data []byte) int {
+	if function&0x80 != 0 {
+		return 1 - len(data) // exception code
+	}
+	switch function {
+	case FuncReadCoils, FuncReadDiscreteInputs, FuncReadHoldingRegisters, FuncReadInputRegisters:
+		if len(data) == 0 {
+			return 1 // byte count
+		}
+		return 1 + int(data[0]) - len(data)
+	default:
+		return 4 - len(data) // address and value or quantity
+	}
+}
+
+// rtuRequestLength returns how much more data follows data in a request with the given function code.
+func rtuRequestLength(function byte, data []byte) int {
+	switch function {
+	case FuncWriteMultipleCoils, FuncWriteMultipleRegisters:
+		if len(data) < 5 {
+			return 5 - len(data) // address, quantity and byte count
+		}
+		return 5 + int(data[4]) - len(data)
+	default:
+		return 4 - len(data)
+	}
+}
+
+// crc16 calculates the Modbus CRC of data.
+func crc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for range 8 {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,178 @@
+package comm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Function codes supported by the client and server.
+const (
+	FuncReadCoils              byte = 0x01
+	FuncReadDiscreteInputs     byte = 0x02
+	FuncReadHoldingRegisters   byte = 0x03
+	FuncReadInputRegisters     byte = 0x04
+	FuncWriteSingleCoil        byte = 0x05
+	FuncWriteSingleRegister    byte = 0x06
+	FuncWriteMultipleCoils     byte = 0x0F
+	FuncWriteMultipleRegisters byte = 0x10
+)
+
+// Limits of the number of items a single request can read or write.
+const (
+	MaxReadBits       = 2000
+	MaxReadRegisters  = 125
+	MaxWriteBits      = 1968
+	MaxWriteRegisters = 123
+)
+
+// maxPDULength is the largest protocol data unit, function code and data, allowed by the spec.
+const maxPDULength = 253
+
+// ExceptionCode is the reason a device gives for rejecting a request.
+type ExceptionCode byte
+
+const (
+	IllegalFunction                    ExceptionCode = 0x01
+	IllegalDataAddress                 ExceptionCode = 0x02
+	IllegalDataValue                   ExceptionCode = 0x03
+	ServerDeviceFailure                ExceptionCode = 0x04
+	Acknowledge                        ExceptionCode = 0x05
+	ServerDeviceBusy                   ExceptionCode = 0x06
+	GatewayPathUnavailable             ExceptionCode = 0x0A
+	GatewayTargetDeviceFailedToRespond ExceptionCode = 0x0B
+)
+
+func (c ExceptionCode) String() string {
+	switch c {
+	case IllegalFunction:
+		return "illegal function"
+	case IllegalDataAddress:
+		return "illegal data address"
+	case IllegalDataValue:
+		return "illegal data value"
+	case ServerDeviceFailure:
+		return "server device failure"
+	case Acknowledge:
+		return "acknowledge"
+	case ServerDeviceBusy:
+		return "server device busy"
+	case GatewayPathUnavailable:
+		return "gateway path unavailable"
+	case GatewayTargetDeviceFailedToRespond:
+		return "gateway target device failed to respond"
+	default:
+		return fmt.Sprintf("exception 0x%02x", byte(c))
+	}
+}
+
+// Exception is an error response from a device.
+// The connection to the device is still healthy when a request fails with an Exception.
+type Exception struct {
+	Function byte
+	Code     ExceptionCode
+}
+
+func (e *Exception) Error() string {
+	return fmt.Sprintf("modbus function 0x%02x: %s", e.Function, e.Code)
+}
+
+var errInvalidResponse = errors.New("invalid response")
+
+// pdu is a protocol data unit: a function code followed by its data.
+type pdu []byte
+
+func (p pdu) function() byte {
+	return p[0]
+}
+
+func (p pdu) data() []byte {
+	return p[1:]
+}
+
+func newPDU(function byte, data ...byte) pdu {
+	return append(pdu{function}, data...)
+}
+
+// checkResponse returns an error if res is not a valid response to req.
+func checkResponse(req, res pdu) error {
+	if len(res) == 0 {
+		return fmt.Errorf("%w: empty", errInvalidResponse)
+	}
+	if res.function() == req.function()|0x80 {
+		if len(res) != 2 {
+			return fmt.Errorf("%w: exception length %d", errInvalidResponse, len(res))
+		}
+		return &Exception{Function: req.function(), Code: ExceptionCode(res[1])}
+	}
+	if res.function() != req.function() {
+		return fmt.Errorf("%w: function 0x%02x, want 0x%02x", errInvalidResponse, res.function(), req.function())
+	}
+	return nil
+}
+
+func readRequest(function byte, address, quantity uint16) pdu {
+	return newPDU(function, be16(address)...).appendUint16(quantity)
+}
+
+func (p pdu) appendUint16(v uint16) pdu {
+	return binary.BigEndian.AppendUint16(p, v)
+}
+
+func be16(v uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, v)
+}
+
+// decodeBits returns quantity bits from a read coils or discrete inputs response.
+func decodeBits(res pdu, quantity uint16) ([]bool, error) {
+	data := res.data()
+	n := int(quantity+7) / 8
+	if len(data) < 1 || int(data[0]) != n || len(data) != n+1 {
+		return nil, fmt.Errorf("%w: %d bytes for %d bits", errInvalidResponse, len(data), quantity)
+	}
+	return unpackBits(data[1:], int(quantity)), nil
+}
+
+// decodeRegisters returns quantity registers from a read holding or input registers response.
+func decodeRegisters(res pdu, quantity uint16) ([]uint16, error) {
+	data := res.data()
+	n := int(quantity) * 2
+	if len(data) < 1 || int(data[0]) != n || len(data) != n+1 {
+		return nil, fmt.Errorf("%w: %d bytes for %d registers", errInvalidResponse, len(data), quantity)
+	}
+	return unpackRegisters(data[1:]), nil
+}
+
+func packBits(bits []bool) []byte {
+	res := make([]byte, (len(bits)+7)/8)
+	for i, b := range bits {
+		if b {
+			res[i/8] |= 1 << (i % 8)
+		}
+	}
+	return res
+}
+
+func unpackBits(data []byte, n int) []bool {
+	res := make([]bool, n)
+	for i := range res {
+		res[i] = data[i/8]&(1<<(i%8)) != 0
+	}
+	return res
+}
+
+func packRegisters(regs []uint16) []byte {
+	res := make([]byte, 0, len(regs)*2)
+	for _, r := range regs {
+		res = binary.BigEndian.AppendUint16(res, r)
+	}
+	return res
+}
+
+func unpackRegisters(data []byte) []uint16 {
+	res := make([]uint16, len(data)/2)
+	for i := range res {
+		res[i] = binary.BigEndian.Uint16(data[i*2:])
+	}
+	return res
+}
@@ -0,0 +1,272 @@
+package comm
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+)
+
+// Server is an in-memory Modbus server, useful for testing clients and simulating devices.
+// Only addresses that have been set can be read or written, others result in an IllegalDataAddress exception.
+type Server struct {
+	mu    sync.Mutex
+	units map[byte]*Memory
+}
+
+func NewServer() *Server {
+	return &Server{units: make(map[byte]*Memory)}
+}
+
+// Unit returns the memory of the unit with the given id, creating it if needed.
+func (s *Server) Unit(id byte) *Memory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.units[id]
+	if !ok {
+		m = newMemory()
+		s.units[id] = m
+	}
+	return m
+}
+
+func (s *Server) unit(id byte) (*Memory, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.units[id]
+	return m, ok
+}
+
+// Serve accepts Modbus TCP connections from l until it is closed.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			_ = s.ServeTCP(conn)
+		}()
+	}
+}
+
+// ServeTCP responds to Modbus TCP requests read from rw until it fails or is closed.
+// Requests to unknown units fail with a GatewayTargetDeviceFailedToRespond exception.
+func (s *Server) ServeTCP(rw io.ReadWriter) error {
+	for {
+		txID, unit, req, err := readTCP(rw)
+		if err != nil {
+			return eofIsNil(err)
+		}
+		res, ok := s.handle(unit, req)
+		if !ok {
+			res = exception(req, GatewayTargetDeviceFailedToRespond)
+		}
+		if _, err := rw.Write(encodeTCP(txID, unit, res)); err != nil {
+			return err
+		}
+	}
+}
+
+// ServeRTU responds to Modbus RTU requests read from rw until it fails or is closed.
+// Requests to unknown units are ignored, as they would be for other devices on the serial line.
+func (s *Server) ServeRTU(rw io.ReadWriter) error {
+	for {
+		unit, req, err := readRTU(rw, rtuRequestLength)
+		if err != nil {
+			return eofIsNil(err)
+		}
+		res, ok := s.handle(unit, req)
+		if !ok {
+			continue
+		}
+		if _, err := rw.Write(encodeRTU(unit, res)); err != nil {
+			return err
+		}
+	}
+}
+
+func eofIsNil(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || errors.Is(err, io.ErrClosedPipe) {
+		return nil
+	}
+	return err
+}
+
+// handle returns the response to req, and whether the unit exists.
+func (s *Server) handle(unit byte, req pdu) (pdu, bool) {
+	m, ok := s.unit(unit)
+	if !ok {
+		return nil, false
+	}
+	data := req.data()
+	if len(data) < 4 {
+		return exception(req, IllegalDataValue), true
+	}
+	address := binary.BigEndian.Uint16(data)
+	value := binary.BigEndian.Uint16(data[2:])
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch req.function() {
+	case FuncReadCoils, FuncReadDiscreteInputs:
+		table := m.coils
+		if req.function() == FuncReadDiscreteInputs {
+			table = m.discreteInputs
+		}
+		if value < 1 || value > MaxReadBits {
+			return exception(req, IllegalDataValue), true
+		}
+		bits, ok := readTable(table, address, value)
+		if !ok {
+			return exception(req, IllegalDataAddress), true
+		}
+		packed := packBits(bits)
+		return append(newPDU(req.function(), byte(len(packed))), packed...), true
+	case FuncReadHoldingRegisters, FuncReadInputRegisters:
+		table := m.holdingRegisters
+		if req.function() == FuncReadInputRegisters {
+			table = m.inputRegisters
+		}
+		if value < 1 || value > MaxReadRegisters {
+			return exception(req, IllegalDataValue), true
+		}
+		regs, ok := readTable(table, address, value)
+		if !ok {
+			return exception(req, IllegalDataAddress), true
+		}
+		packed := packRegisters(regs)
+		return append(newPDU(req.function(), byte(len(packed))), packed...), true
+	case FuncWriteSingleCoil:
+		if value != 0 && value != 0xFF00 {
+			return exception(req, IllegalDataValue), true
+		}
+		if !writeTable(m.coils, address, []bool{value == 0xFF00}) {
+			return exception(req, IllegalDataAddress), true
+		}
+		return req, true
+	case FuncWriteSingleRegister:
+		if !writeTable(m.holdingRegisters, address, []uint16{value}) {
+			return exception(req, IllegalDataAddress), true
+		}
+		return req, true
+	case FuncWriteMultipleCoils, FuncWriteMultipleRegisters:
+		if len(data) < 5 || int(data[4]) != len(data)-5 {
+			return exception(req, IllegalDataValue), true
+		}
+		payload := data[5:]
+		var ok bool
+		if req.function() == FuncWriteMultipleCoils {
+			if int(value+7)/8 != len(payload) {
+				return exception(req, IllegalDataValue), true
+			}
+			ok = writeTable(m.coils, address, unpackBits(payload, int(value)))
+		} else {
+			if int(value)*2 != len(payload) {
+				return exception(req, IllegalDataValue), true
+			}
+			ok = writeTable(m.holdingRegisters, address, unpackRegisters(payload))
+		}
+		if !ok {
+			return exception(req, IllegalDataAddress), true
+		}
+		return req[:5], true
+	default:
+		return exception(req, IllegalFunction), true
+	}
+}
+
+func exception(req pdu, code ExceptionCode) pdu {
+	return newPDU(req.function()|0x80, byte(code))
+}
+
+// Memory holds the values of a unit.
+type Memory struct {
+	mu               sync.Mutex
+	coils            map[uint16]bool
+	discreteInputs   map[uint16]bool
+	holdingRegisters map[uint16]uint16
+	inputRegisters   map[uint16]uint16
+}
+
+func newMemory() *Memory {
+	return &Memory{
+		coils:            make(map[uint16]bool),
+		discreteInputs:   make(map[uint16]bool),
+		holdingRegisters: make(map[uint16]uint16),
+		inputRegisters:   make(map[uint16]uint16),
+	}
+}
+
+// SetCoils sets the coils starting at address, making them readable.
+func (m *Memory) SetCoils(address uint16, values ...bool) {
+	m.set(func() { setTable(m.coils, address, values) })
+}
+
+// SetDiscreteInputs sets the discrete inputs starting at address, making them readable.
+func (m *Memory) SetDiscreteInputs(address uint16, values ...bool) {
+	m.set(func() { setTable(m.discreteInputs, address, values) })
+}
+
+// SetHoldingRegisters sets the holding registers starting at address, making them readable.
+func (m *Memory) SetHoldingRegisters(address uint16, values ...uint16) {
+	m.set(func() { setTable(m.holdingRegisters, address, values) })
+}
+
+// SetInputRegisters sets the input registers starting at address, making them readable.
+func (m *Memory) SetInputRegisters(address uint16, values ...uint16) {
+	m.set(func() { setTable(m.inputRegisters, address, values) })
+}
+
+// Coils returns quantity coils starting at address, and whether they have all been set.
+func (m *Memory) Coils(address, quantity uint16) ([]bool, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return readTable(m.coils, address, quantity)
+}
+
+// HoldingRegisters returns quantity holding registers starting at address, and whether they have all been set.
+func (m *Memory) HoldingRegisters(address, quantity uint16) ([]uint16, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return readTable(m.holdingRegisters, address, quantity)
+}
+
+func (m *Memory) set(f func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f()
+}
+
+func setTable[T any](table map[uint16]T, address uint16, values []T) {
+	for i, v := range values {
+		table[address+uint16(i)] = v
+	}
+}
+
+func readTable[T any](table map[uint16]T, address, quantity uint16) ([]T, bool) {
+	res := make([]T, quantity)
+	for i := range res {
+		v, ok := table[address+uint16(i)]
+		if !ok {
+			return nil, false
+		}
+		res[i] = v
+	}
+	return res, true
+}
+
+func writeTable[T any](table map[uint16]T, address uint16, values []T) bool {
+	for i := range values {
+		if _, ok := table[address+uint16(i)]; !ok {
+			return false
+		}
+	}
+	setTable(table, address, values)
+	return true
+}
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Point is a value stored in one or more registers or bits of a device.
+//
+// Values are converted to and from numbers using Type, ByteOrder, and WordOrder, then scaled:
+//
+//	value = raw * Scale + Offset
+type Point struct {
+	// Name identifies the point within the device, traits refer to points by name.
+	Name  string `json:"name,omitempty"`
+	Table Table  `json:"table,omitempty"`
+	// Address is the zero based address of the first register or bit of the point, as sent in requests.
+	Address uint16 `json:"address"`
+	// Type is how the register values are interpreted.
+	// Defaults to bool for coils and discrete inputs, and uint16 for registers.
+	Type DataType `json:"type,omitempty"`
+	// ByteOrder is the order of the bytes in each register, defaults to big endian as in the Modbus spec.
+	ByteOrder Order `json:"byteOrder,omitempty"`
+	// WordOrder is the order of the registers of values that span more than one, defaults to big endian.
+	WordOrder Order `json:"wordOrder,omitempty"`
+	// Scale multiplies raw values, defaults to 1.
+	Scale float64 `json:"scale,omitempty"`
+	// Offset is added to raw values after scaling.
+	Offset float64 `json:"offset,omitempty"`
+	// PollGroup is the name of the poll group that reads the point, defaults to the device poll period.
+	PollGroup string `json:"pollGroup,omitempty"`
+}
+
+// Table is a Modbus data table.
+type Table string
+
+const (
+	Coils            Table = "coil"
+	DiscreteInputs   Table = "discreteInput"
+	InputRegisters   Table = "inputRegister"
+	HoldingRegisters Table = "holdingRegister"
+)
+
+// IsBits returns whether the table holds single bits, rather than 16 bit registers.
+func (t Table) IsBits() bool {
+	return t == Coils || t == DiscreteInputs
+}
+
+// Writable returns whether values in the table can be written.
+func (t Table) Writable() bool {
+	return t == Coils || t == HoldingRegisters
+}
+
+// DataType describes how register values are interpreted.
+type DataType string
+
+const (
+	Bool    DataType = "bool"
+	Int16   DataType = "int16"
+	Uint16  DataType = "uint16"
+	Int32   DataType = "int32"
+	Uint32  DataType = "uint32"
+	Float32 DataType = "float32"
+	Int64   DataType = "int64"
+	Uint64  DataType = "uint64"
+	Float64 DataType = "float64"
+)
+
+// Registers returns how many registers the type uses.
+func (t DataType) Registers() int {
+	switch t {
+	case Bool, Int16, Uint16:
+		return 1
+	case Int32, Uint32, Float32:
+		return 2
+	case Int64, Uint64, Float64:
+		return 4
+	}
+	return 0
+}
+
+// Order is the order of bytes or words.
+type Order string
+
+const (
+	BigEndian    Order = "bigEndian"
+	LittleEndian Order = "littleEndian"
+)
+
+func (p *Point) defaults() {
+	if p.Type == "" {
+		if p.Table.IsBits() {
+			p.Type = Bool
+		} else {
+			p.Type = Uint16
+		}
+	}
+	if p.ByteOrder == "" {
+		p.ByteOrder = BigEndian
+	}
+	if p.WordOrder == "" {
+		p.WordOrder = BigEndian
+	}
+	if p.Scale == 0 {
+		p.Scale = 1
+	}
+}
+
+func (p Point) validate() error {
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	if !slices.Contains([]Table{Coils, DiscreteInputs, InputRegisters, HoldingRegisters}, p.Table) {
+		return fmt.Errorf("unknown table %q", p.Table)
+	}
+	if p.Type.Registers() == 0 {
+		return fmt.Errorf("unknown type %q", p.Type)
+	}
+	if p.Table.IsBits() && p.Type != Bool {
+		return fmt.Errorf("%s points must be bool, not %s", p.Table, p.Type)
+	}
+	for _, o := range []Order{p.ByteOrder, p.WordOrder} {
+		if o != BigEndian && o != LittleEndian {
+			return fmt.Errorf("unknown order %q", o)
+		}
+	}
+	if int(p.Address)+p.Size() > math.MaxUint16+1 {
+		return errors.New("address out of range")
+	}
+	return nil
+}
+
+// Size returns the number of registers or bits the point uses.
+func (p Point) Size() int {
+	if p.Table.IsBits() {
+		return 1
+	}
+	return p.Type.Registers()
+}
+
+// Decode returns the scaled value of the point from its registers.
+func (p Point) Decode(regs []uint16) (float64, error) {
+	if len(regs) != p.Type.Registers() {
+		return 0, fmt.Errorf("%s needs %d registers, got %d", p.Type, p.Type.Registers(), len(regs))
+	}
+	raw := p.fromRegisters(regs)
+	var v float64
+	switch p.Type {
+	case Bool:
+		if raw != 0 {
+			v = 1
+		}
+	case Int16:
+		v = float64(int16(raw))
+	case Uint16, Uint32, Uint64:
+		v = float64(raw)
+	case Int32:
+		v = float64(int32(raw))
+	case Float32:
+		v = float64(math.Float32frombits(uint32(raw)))
+	case Int64:
+		v = float64(int64(raw))
+	case Float64:
+		v = math.Float64frombits(raw)
+	}
+	return v*p.Scale + p.Offset, nil
+}
+
+// DecodeBit returns the scaled value of a bool point from its bit.
+func (p Point) DecodeBit(b bool) float64 {
+	var v float64
+	if b {
+		v = 1
+	}
+	return v*p.Scale + p.Offset
+}
+
+// Encode returns the registers that store the scaled value v.
+// Integer types round to the nearest integer, and fail if the value is out of range.
+func (p Point) Encode(v float64) ([]uint16, error) {
+	v = (v - p.Offset) / p.Scale
+	var raw uint64
+	switch p.Type {
+	case Bool:
+		if v != 0 {
+			raw = 1
+		}
+	case Float32:
+		raw = uint64(math.Float32bits(float32(v)))
+	case Float64:
+		raw = math.Float64bits(v)
+	default:
+		r := math.Round(v)
+		lo, hi := p.Type.intRange()
+		if math.IsNaN(r) || r < lo || r > hi {
+			return nil, fmt.Errorf("%v out of range for %s", v, p.Type)
+		}
+		if r < 0 {
+			raw = uint64(int64(r))
+		} else {
+			raw = uint64(r)
+		}
+	}
+	return p.toRegisters(raw), nil
+}
+
+// EncodeBit returns the bit that stores the scaled value v.
+func (p Point) EncodeBit(v float64) bool {
+	return (v-p.Offset)/p.Scale != 0
+}
+
+func (t DataType) intRange() (lo, hi float64) {
+	switch t {
+	case Int16:
+		return math.MinInt16, math.MaxInt16
+	case Uint16:
+		return 0, math.MaxUint16
+	case Int32:
+		return math.MinInt32, math.MaxInt32
+	case Uint32:
+		return 0, math.MaxUint32
+	case Int64:
+		return math.MinInt64, math.MaxInt64
+	case Uint64:
+		return 0, math.MaxUint64
+	}
+	return 0, 0
+}
+
+// fromRegisters joins registers into a single value, most significant bits first.
+func (p Point) fromRegisters(regs []uint16) uint64 {
+	var raw uint64
+	for i := range regs {
+		r := regs[i]
+		if p.WordOrder == LittleEndian {
+			r = regs[len(regs)-1-i]
+		}
+		if p.ByteOrder == LittleEndian {
+			r = r<<8 | r>>8
+		}
+		raw = raw<<16 | uint64(r)
+	}
+	return raw
+}
+
+// toRegisters splits raw into registers, the inverse of fromRegisters.
+func (p Point) toRegisters(raw uint64) []uint16 {
+	n := p.Type.Registers()
+	regs := make([]uint16, n)
+	for i := n - 1; i >= 0; i-- {
+		r := uint16(raw)
+		raw >>= 16
+		if p.ByteOrder == LittleEndian {
+			r = r<<8 | r>>8
+		}
+		if p.WordOrder == LittleEndian {
+			regs[n-1-i] = r
+		} else {
+			regs[i] = r
+		}
+	}
+	return regs
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPoint_EncodeDecode(t *testing.T) {
+	tests := []struct {
+		name  string
+		point Point
+		regs  []uint16
+		value float64
+	}{
+		{name: "uint16", point: Point{Type: Uint16}, regs: []uint16{0xFFFE}, value: 65534},
+		{name: "int16", point: Point{Type: Int16}, regs: []uint16{0xFFFE}, value: -2},
+		{name: "int16 scaled", point: Point{Type: Int16, Scale: 0.1, Offset: -10}, regs: []uint16{215}, value: 11.5},
+		{name: "uint32", point: Point{Type: Uint32}, regs: []uint16{0x0001, 0x0002}, value: 0x00010002},
+		{name: "uint32 word swapped", point: Point{Type: Uint32, WordOrder: LittleEndian}, regs: []uint16{0x0002, 0x0001}, value: 0x00010002},
+		{name: "uint32 byte swapped", point: Point{Type: Uint32, ByteOrder: LittleEndian}, regs: []uint16{0x0100, 0x0200}, value: 0x00010002},
+		{name: "int32", point: Point{Type: Int32}, regs: []uint16{0xFFFF, 0xFFFB}, value: -5},
+		{name: "float32", point: Point{Type: Float32}, regs: []uint16{0x41C8, 0x0000}, value: 25},
+		{name: "float32 little endian", point: Point{Type: Float32, ByteOrder: LittleEndian, WordOrder: LittleEndian}, regs: []uint16{0x0000, 0xC841}, value: 25},
+		{name: "int64", point: Point{Type: Int64}, regs: []uint16{0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF}, value: -1},
+		{name: "uint64", point: Point{Type: Uint64, WordOrder: LittleEndian}, regs: []uint16{4, 3, 2, 1}, value: 0x0001000200030004},
+		{name: "float64", point: Point{Type: Float64}, regs: []uint16{0x4039, 0x0000, 0x0000, 0x0000}, value: 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.point
+			p.defaults()
+			got, err := p.Decode(tt.regs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.value {
+				t.Errorf("Decode() = %v, want %v", got, tt.value)
+			}
+			regs, err := p.Encode(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.regs, regs); diff != "" {
+				t.Errorf("Encode() (-want,+got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPoint_Encode_outOfRange(t *testing.T) {
+	p := Point{Type: Uint16}
+	p.defaults()
+	for _, v := range []float64{-1, 65536} {
+		if _, err := p.Encode(v); err == nil {
+			t.Errorf("Encode(%v) want error", v)
+		}
+	}
+}
+
+func TestReadBytes(t *testing.T) {
+	cfg, err := ReadBytes([]byte(`{
+		"conn": {"tcp": "10.0.0.1"},
+		"devices": [{"name": "meter", "points": [{"name": "energy", "table": "inputRegister", "type": "float32"}, {"name": "run", "table": "coil"}]}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev := cfg.Devices[0]
+	if got := dev.Conn.TCPAddress(); got != "10.0.0.1:502" {
+		t.Errorf("TCPAddress() = %q", got)
+	}
+	if got := dev.Points[1].Type; got != Bool {
+		t.Errorf("coil type = %q, want bool", got)
+	}
+
+	_, err = ReadBytes([]byte(`{"devices": [{"name": "meter", "points": [{"name": "x", "table": "coil", "type": "float32"}]}]}`))
+	if err == nil {
+		t.Error("want error for missing conn and non-bool coil")
+	}
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	DefaultPort        = "502"
+	DefaultPollPeriod  = 10 * time.Second
+	DefaultMaxBlockGap = 8
+	DefaultTimeout     = 5 * time.Second
+	DefaultBackoffMin  = time.Second
+	DefaultBackoffMax  = time.Minute
+)
+
+// Root is the configuration of the modbus driver.
+type Root struct {
+	driver.BaseConfig
+
+	// Metadata is applied to the driver name.
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+	// Conn is the connection used by devices that don't have their own.
+	Conn    *Conn    `json:"conn,omitempty"`
+	Timing  Timing   `json:"timing,omitempty"`
+	Devices []Device `json:"devices,omitempty"`
+}
+
+// Conn configures how to reach Modbus devices, either TCP or Serial must be set.
+// Devices that share a Conn share a connection, requests to them are made one at a time.
+type Conn struct {
+	// TCP is the host:port of a Modbus TCP server or gateway, the port defaults to 502.
+	TCP string `json:"tcp,omitempty"`
+	// Serial connects to Modbus RTU devices using a serial port.
+	Serial *Serial `json:"serial,omitempty"`
+}
+
+// Key uniquely identifies the connection.
+func (c Conn) Key() string {
+	if c.Serial != nil {
+		return "serial:" + c.Serial.Port
+	}
+	return "tcp:" + c.TCPAddress()
+}
+
+// TCPAddress returns TCP with the default port added if needed.
+func (c Conn) TCPAddress() string {
+	if _, _, err := net.SplitHostPort(c.TCP); err != nil {
+		return net.JoinHostPort(c.TCP, DefaultPort)
+	}
+	return c.TCP
+}
+
+// Serial configures a serial port for Modbus RTU.
+type Serial struct {
+	// Port is the path to the serial port, like /dev/ttyUSB0.
+	Port string `json:"port,omitempty"`
+	// BaudRate defaults to 19200.
+	BaudRate int `json:"baudRate,omitempty"`
+	// DataBits defaults to 8.
+	DataBits int `json:"dataBits,omitempty"`
+	// StopBits defaults to 1.
+	StopBits int `json:"stopBits,omitempty"`
+	// Parity is N, E, or O, defaults to E.
+	Parity string `json:"parity,omitempty"`
+}
+
+type Timing struct {
+	// Timeout is how long each request can take, defaults to 5s.
+	Timeout *jsontypes.Duration `json:"timeout,omitempty"`
+	// BackoffMin is the delay after failing to connect, doubling for each failure up to BackoffMax.
+	// Defaults to 1s.
+	BackoffMin *jsontypes.Duration `json:"backoffMin,omitempty"`
+	// BackoffMax defaults to 1m.
+	BackoffMax *jsontypes.Duration `json:"backoffMax,omitempty"`
+}
+
+// Device is a Modbus device, identified by its unit id on a connection.
+type Device struct {
+	// Name is the Smart Core name of the device, used for its traits unless they have their own name.
+	Name string `json:"name,omitempty"`
+	// Metadata is announced for Name.
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+	// Conn is how to reach the device, defaults to Root.Conn.
+	Conn *Conn `json:"conn,omitempty"`
+	// UnitID is the address of the device, also called the slave id.
+	UnitID uint8 `json:"unitId,omitempty"`
+
+	// PollPeriod is how often points not in a poll group are read, defaults to 10s.
+	PollPeriod *jsontypes.Duration `json:"pollPeriod,omitempty"`
+	// PollGroups are points read at a different rate.
+	PollGroups []PollGroup `json:"pollGroups,omitempty"`
+	// MaxBlockGap is how many unused registers or bits there can be between points read using a single request.
+	// Some devices fail block reads that include addresses they don't have, use -1 to read each point separately.
+	// Defaults to 8.
+	MaxBlockGap int `json:"maxBlockGap,omitempty"`
+
+	// Points are the values that can be read from the device.
+	Points []Point `json:"points,omitempty"`
+	// Traits map points to Smart Core traits.
+	Traits []RawTrait `json:"traits,omitempty"`
+}
+
+// PollGroup is a group of points read at the same rate.
+type PollGroup struct {
+	Name   string              `json:"name,omitempty"`
+	Period *jsontypes.Duration `json:"period,omitempty"`
+}
+
+// ReadBytes decodes and validates data, filling in defaults.
+func ReadBytes(data []byte) (cfg Root, err error) {
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.Timing.Timeout == nil {
+		cfg.Timing.Timeout = &jsontypes.Duration{Duration: DefaultTimeout}
+	}
+	if cfg.Timing.BackoffMin == nil {
+		cfg.Timing.BackoffMin = &jsontypes.Duration{Duration: DefaultBackoffMin}
+	}
+	if cfg.Timing.BackoffMax == nil {
+		cfg.Timing.BackoffMax = &jsontypes.Duration{Duration: DefaultBackoffMax}
+	}
+	if cfg.Timing.BackoffMax.Duration < cfg.Timing.BackoffMin.Duration {
+		cfg.Timing.BackoffMax = cfg.Timing.BackoffMin
+	}
+	for i := range cfg.Devices {
+		d := &cfg.Devices[i]
+		if d.Conn == nil {
+			d.Conn = cfg.Conn
+		}
+		if d.MaxBlockGap == 0 {
+			d.MaxBlockGap = DefaultMaxBlockGap
+		}
+		for j := range d.Points {
+			d.Points[j].defaults()
+		}
+	}
+	return cfg, cfg.validate()
+}
+
+func (r Root) validate() error {
+	var errs []error
+	names := make(map[string]bool)
+	for _, d := range r.Devices {
+		if d.Name == "" {
+			errs = append(errs, errors.New("device name is required"))
+			continue
+		}
+		if names[d.Name] {
+			errs = append(errs, fmt.Errorf("device %q: name is used more than once", d.Name))
+		}
+		names[d.Name] = true
+		if err := d.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("device %q: %w", d.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d Device) validate() error {
+	var errs []error
+	switch {
+	case d.Conn == nil || (d.Conn.TCP == "" && d.Conn.Serial == nil):
+		errs = append(errs, errors.New("conn.tcp or conn.serial is required"))
+	case d.Conn.TCP != "" && d.Conn.Serial != nil:
+		errs = append(errs, errors.New("only one of conn.tcp and conn.serial can be set"))
+	case d.Conn.Serial != nil && d.Conn.Serial.Port == "":
+		errs = append(errs, errors.New("conn.serial.port is required"))
+	}
+	groups := make(map[string]bool)
+	for _, g := range d.PollGroups {
+		if g.Name == "" {
+			errs = append(errs, errors.New("poll group name is required"))
+		}
+		groups[g.Name] = true
+	}
+	points := make(map[string]bool)
+	for _, p := range d.Points {
+		if points[p.Name] {
+			errs = append(errs, fmt.Errorf("point %q: name is used more than once", p.Name))
+		}
+		points[p.Name] = true
+		if err := p.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("point %q: %w", p.Name, err))
+		}
+		if p.PollGroup != "" && !groups[p.PollGroup] {
+			errs = append(errs, fmt.Errorf("point %q: unknown poll group %q", p.Name, p.PollGroup))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Point returns the point with the given name.
+func (d Device) Point(name string) (Point, bool) {
+	for _, p := range d.Points {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Point{}, false
+}
+
+// PollPeriodFor returns how often points in the named poll group are read.
+func (d Device) PollPeriodFor(group string) time.Duration {
+	for _, g := range d.PollGroups {
+		if g.Name == group {
+			return g.Period.Or(DefaultPollPeriod)
+		}
+	}
+	return d.PollPeriod.Or(DefaultPollPeriod)
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"encoding/json"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+)
+
+type Trait struct {
+	// Name of the device implementing the trait, defaults to the name of the Modbus device.
+	Name     string           `json:"name,omitempty"`
+	Kind     trait.Name       `json:"kind,omitempty"`
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+}
+
+type RawTrait struct {
+	Trait
+	Raw json.RawMessage `json:"-"`
+}
+
+func (c *RawTrait) MarshalJSON() ([]byte, error) {
+	return c.Raw, nil
+}
+
+func (c *RawTrait) UnmarshalJSON(buf []byte) error {
+	c.Raw = buf
+	return json.Unmarshal(buf, &c.Trait)
+}
+
+// The trait configs below refer to points of the device by name.
+// Points that aren't configured leave the corresponding trait fields unset.
+
+// MeterConfig is configured by a Device that wants to implement the Meter trait.
+type MeterConfig struct {
+	Trait
+	Unit string `json:"unit,omitempty"`
+	// Usage is the point holding the total consumed, in Unit.
+	Usage string `json:"usage,omitempty"`
+	// Produced is the point holding the total produced, in Unit.
+	Produced string `json:"produced,omitempty"`
+}
+
+// ElectricConfig is configured by a Device that wants to implement the Electric trait.
+// Points should be scaled to volts, amps, watts, VA, and VAR.
+type ElectricConfig struct {
+	Trait
+	Voltage       string `json:"voltage,omitempty"`
+	Current       string `json:"current,omitempty"`
+	RealPower     string `json:"realPower,omitempty"`
+	ApparentPower string `json:"apparentPower,omitempty"`
+	ReactivePower string `json:"reactivePower,omitempty"`
+	PowerFactor   string `json:"powerFactor,omitempty"`
+}
+
+// AirTemperatureConfig is configured by a Device that wants to implement the AirTemperature trait.
+// Temperatures should be scaled to degrees Celsius.
+type AirTemperatureConfig struct {
+	Trait
+	AmbientTemperature string `json:"ambientTemperature,omitempty"`
+	// TemperatureSetPoint is written when the set point is updated, so should be a holding register.
+	TemperatureSetPoint string `json:"temperatureSetPoint,omitempty"`
+	// AmbientHumidity should be scaled to a percentage.
+	AmbientHumidity string `json:"ambientHumidity,omitempty"`
+}
+
+// OnOffConfig is configured by a Device that wants to implement the OnOff trait.
+type OnOffConfig struct {
+	Trait
+	// OnOff is the point read and written for the state.
+	OnOff string `json:"onOff,omitempty"`
+	// OnValue is the value of OnOff that means on, defaults to 1.
+	OnValue *float64 `json:"onValue,omitempty"`
+	// OffValue is the value written to turn off, defaults to 0.
+	// Any value other than OnValue is read as off.
+	OffValue *float64 `json:"offValue,omitempty"`
+}
+
+func (c OnOffConfig) OnValueOrDefault() float64 {
+	if c.OnValue == nil {
+		return 1
+	}
+	return *c.OnValue
+}
+
+func (c OnOffConfig) OffValueOrDefault() float64 {
+	if c.OffValue == nil {
+		return 0
+	}
+	return *c.OffValue
+}
+
+// FanSpeedConfig is configured by a Device that wants to implement the FanSpeed trait.
+type FanSpeedConfig struct {
+	Trait
+	// Percentage is the point read and written for the fan speed, scaled to 0-100.
+	Percentage string `json:"percentage,omitempty"`
+}
+
+// StatusConfig is configured by a Device that wants to report problems using the Status trait.
+type StatusConfig struct {
+	Trait
+	Problems []ProblemConfig `json:"problems,omitempty"`
+}
+
+// ProblemConfig reports a problem when Point doesn't equal NormalValue.
+type ProblemConfig struct {
+	// Name of the problem, defaults to the point name.
+	Name  string `json:"name,omitempty"`
+	Point string `json:"point,omitempty"`
+	// NormalValue is the value of Point when there is no problem, defaults to 0.
+	NormalValue float64 `json:"normalValue,omitempty"`
+	// Level is the status level of the problem, one of the gen.StatusLog_Level names.
+	// Defaults to NOTICE.
+	Level       string `json:"level,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// UdmiConfig is configured by a Device that wants to implement the UDMI trait.
+type UdmiConfig struct {
+	Trait
+	// TopicPrefix is the prefix prepended to the topic in a gen.MqttMessage
+	TopicPrefix string `json:"topicPrefix,omitempty"`
+	// Points are the names of the points sent in UDMI pointset events.
+	Points []string `json:"points,omitempty"`
+}
+
+const PointsEventTopicSuffix = "/event/pointset"
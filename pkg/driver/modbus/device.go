@@ -0,0 +1,168 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/comm"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/statuspb"
+)
+
+// device polls the points of a Modbus device, notifying listeners of new values.
+type device struct {
+	cfg      config.Device
+	client   *comm.Client
+	statuses *statuspb.Map
+	logger   *zap.Logger
+
+	mu        sync.Mutex
+	values    map[string]float64
+	listeners []func(values map[string]float64)
+	// notifyMu makes sure listeners are called one at a time, and in the order values change.
+	notifyMu sync.Mutex
+}
+
+func newDevice(cfg config.Device, client *comm.Client, statuses *statuspb.Map, logger *zap.Logger) *device {
+	return &device{
+		cfg:      cfg,
+		client:   client,
+		statuses: statuses,
+		logger:   logger.With(zap.String("device", cfg.Name)),
+		values:   make(map[string]float64),
+	}
+}
+
+// onUpdate registers f to be called with the latest values of all points whenever any are read or written.
+// It should be called before run.
+func (d *device) onUpdate(f func(values map[string]float64)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.listeners = append(d.listeners, f)
+}
+
+// run polls each poll group of the device until ctx is done.
+func (d *device) run(ctx context.Context) {
+	groups := make(map[string][]config.Point)
+	for _, p := range d.cfg.Points {
+		groups[p.PollGroup] = append(groups[p.PollGroup], p)
+	}
+	var wg sync.WaitGroup
+	for group, points := range groups {
+		blocks := planBlocks(points, d.cfg.MaxBlockGap)
+		period := d.cfg.PollPeriodFor(group)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.pollEvery(ctx, group, period, blocks)
+		}()
+	}
+	wg.Wait()
+}
+
+func (d *device) pollEvery(ctx context.Context, group string, period time.Duration, blocks []block) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		d.poll(ctx, group, blocks)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll reads blocks, updating the values of their points and the status of the device.
+func (d *device) poll(ctx context.Context, group string, blocks []block) {
+	values := make(map[string]float64)
+	var failed int
+	var firstErr error
+	for _, b := range blocks {
+		vs, err := readBlock(ctx, d.client, d.cfg.UnitID, b)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			d.logger.Debug("failed to read block", zap.String("block", b.String()), zap.Error(err))
+			failed++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", b, err)
+			}
+			continue
+		}
+		maps.Copy(values, vs)
+	}
+	if len(values) > 0 {
+		d.update(values)
+	}
+	d.updatePollStatus(group, len(blocks), failed, firstErr)
+}
+
+func (d *device) updatePollStatus(group string, total, failed int, err error) {
+	problemName := d.cfg.Name + ":poll"
+	if group != "" {
+		problemName += ":" + group
+	}
+	problem := &gen.StatusLog_Problem{
+		Name:        problemName,
+		Level:       gen.StatusLog_NOMINAL,
+		Description: "poll nominal",
+		RecordTime:  timestamppb.Now(),
+	}
+	switch {
+	case failed == 0:
+	case failed == total:
+		problem.Level = gen.StatusLog_NON_FUNCTIONAL
+		problem.Description = fmt.Sprintf("all reads failed, %v", err)
+	default:
+		problem.Level = gen.StatusLog_REDUCED_FUNCTION
+		problem.Description = fmt.Sprintf("%d of %d reads failed, %v", failed, total, err)
+	}
+	d.statuses.UpdateProblem(d.cfg.Name, problem)
+}
+
+func (d *device) update(values map[string]float64) {
+	d.notifyMu.Lock()
+	defer d.notifyMu.Unlock()
+	d.mu.Lock()
+	maps.Copy(d.values, values)
+	snapshot := maps.Clone(d.values)
+	listeners := d.listeners
+	d.mu.Unlock()
+	for _, l := range listeners {
+		l(snapshot)
+	}
+}
+
+// value returns the last known value of the named point.
+func (d *device) value(name string) (float64, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, ok := d.values[name]
+	return v, ok
+}
+
+// write writes v to the named point, then reads the point back so listeners see the value the device has.
+func (d *device) write(ctx context.Context, name string, v float64) error {
+	p, ok := d.cfg.Point(name)
+	if !ok {
+		return fmt.Errorf("unknown point %q", name)
+	}
+	if err := writePoint(ctx, d.client, d.cfg.UnitID, p, v); err != nil {
+		return err
+	}
+	values, err := readBlock(ctx, d.client, d.cfg.UnitID, block{table: p.Table, address: p.Address, size: p.Size(), points: []config.Point{p}})
+	if err != nil {
+		return err
+	}
+	d.update(values)
+	return nil
+}
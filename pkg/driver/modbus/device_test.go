@@ -0,0 +1,159 @@
+package modbus
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-api/go/types"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/comm"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/statuspb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+func Test_planBlocks(t *testing.T) {
+	points := []config.Point{
+		{Name: "a", Table: config.HoldingRegisters, Address: 10, Type: config.Float32},
+		{Name: "c", Table: config.HoldingRegisters, Address: 30, Type: config.Uint16},
+		{Name: "b", Table: config.HoldingRegisters, Address: 14, Type: config.Uint16},
+		{Name: "d", Table: config.Coils, Address: 14, Type: config.Bool},
+	}
+	summary := func(blocks []block) []string {
+		var res []string
+		for _, b := range blocks {
+			res = append(res, b.String())
+		}
+		return res
+	}
+	tests := []struct {
+		maxGap int
+		want   []string
+	}{
+		{maxGap: 2, want: []string{"coil 14-14", "holdingRegister 10-14", "holdingRegister 30-30"}},
+		{maxGap: 1, want: []string{"coil 14-14", "holdingRegister 10-11", "holdingRegister 14-14", "holdingRegister 30-30"}},
+		{maxGap: 100, want: []string{"coil 14-14", "holdingRegister 10-30"}},
+		{maxGap: -1, want: []string{"coil 14-14", "holdingRegister 10-11", "holdingRegister 14-14", "holdingRegister 30-30"}},
+	}
+	for _, tt := range tests {
+		got := summary(planBlocks(points, tt.maxGap))
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("maxGap %d (-want,+got)\n%s", tt.maxGap, diff)
+		}
+	}
+
+	// blocks can't be bigger than a single request can read
+	far := []config.Point{
+		{Name: "a", Table: config.InputRegisters, Address: 0, Type: config.Uint16},
+		{Name: "b", Table: config.InputRegisters, Address: comm.MaxReadRegisters, Type: config.Uint16},
+	}
+	if got := len(planBlocks(far, 1000)); got != 2 {
+		t.Errorf("got %d blocks, want 2", got)
+	}
+}
+
+func TestDevice(t *testing.T) {
+	server := comm.NewServer()
+	unit := server.Unit(3)
+	unit.SetInputRegisters(0, 0x41B4, 0x0000) // 22.5
+	unit.SetInputRegisters(2, 450)            // 45.0 %
+	unit.SetHoldingRegisters(10, 210)         // 21.0
+	unit.SetCoils(0, false)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+	go func() { _ = server.Serve(lis) }()
+
+	cfg, err := config.ReadBytes([]byte(`{
+		"conn": {"tcp": "` + lis.Addr().String() + `"},
+		"devices": [{
+			"name": "ahu", "unitId": 3,
+			"points": [
+				{"name": "temp", "table": "inputRegister", "address": 0, "type": "float32"},
+				{"name": "humidity", "table": "inputRegister", "address": 2, "scale": 0.1},
+				{"name": "setPoint", "table": "holdingRegister", "address": 10, "type": "int16", "scale": 0.1},
+				{"name": "run", "table": "coil", "address": 0}
+			],
+			"traits": [
+				{"kind": "smartcore.traits.AirTemperature", "ambientTemperature": "temp", "ambientHumidity": "humidity", "temperatureSetPoint": "setPoint"},
+				{"kind": "smartcore.traits.OnOff", "onOff": "run"}
+			]
+		}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	devCfg := cfg.Devices[0]
+	client, err := comm.NewTCPClient(devCfg.Conn.TCPAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	statuses := statuspb.NewMap(node.New("test"))
+	dev := newDevice(devCfg, client, statuses, zaptest.NewLogger(t))
+	airTemp, err := newTrait(dev, devCfg.Traits[0], zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	onOffTrait, err := newTrait(dev, devCfg.Traits[1], zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev.onUpdate(airTemp.update)
+	dev.onUpdate(onOffTrait.update)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	dev.poll(ctx, "", planBlocks(devCfg.Points, devCfg.MaxBlockGap))
+
+	at := airTemp.(*airTemperature)
+	got, err := at.GetAirTemperature(ctx, &traits.GetAirTemperatureRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	humidity := float32(45)
+	want := &traits.AirTemperature{
+		AmbientTemperature: &types.Temperature{ValueCelsius: 22.5},
+		AmbientHumidity:    &humidity,
+		TemperatureGoal:    &traits.AirTemperature_TemperatureSetPoint{TemperatureSetPoint: &types.Temperature{ValueCelsius: 21}},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.SortRepeatedFields(got)); diff != "" {
+		t.Fatalf("GetAirTemperature (-want,+got)\n%s", diff)
+	}
+
+	// writes go to the device and are read back
+	_, err = at.UpdateAirTemperature(ctx, &traits.UpdateAirTemperatureRequest{State: &traits.AirTemperature{
+		TemperatureGoal: &traits.AirTemperature_TemperatureSetPoint{TemperatureSetPoint: &types.Temperature{ValueCelsius: -1.5}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	regs, _ := unit.HoldingRegisters(10, 1)
+	if regs[0] != 0xFFF1 { // -15
+		t.Errorf("set point register = %#x, want 0xfff1", regs[0])
+	}
+	got, _ = at.GetAirTemperature(ctx, &traits.GetAirTemperatureRequest{})
+	if sp := got.GetTemperatureSetPoint().GetValueCelsius(); sp != -1.5 {
+		t.Errorf("set point = %v, want -1.5", sp)
+	}
+
+	oo := onOffTrait.(*onOff)
+	res, err := oo.UpdateOnOff(ctx, &traits.UpdateOnOffRequest{OnOff: &traits.OnOff{State: traits.OnOff_ON}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.State != traits.OnOff_ON {
+		t.Errorf("UpdateOnOff() state = %v, want ON", res.State)
+	}
+	if coils, _ := unit.Coils(0, 1); !coils[0] {
+		t.Error("coil not written")
+	}
+}
@@ -0,0 +1,122 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/comm"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/statuspb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+	"github.com/smart-core-os/sc-bos/pkg/util/transport"
+)
+
+const DriverName = "modbus"
+
+var Factory driver.Factory = factory{}
+
+type factory struct{}
+
+func (f factory) New(services driver.Services) service.Lifecycle {
+	d := &Driver{
+		announcer: node.NewReplaceAnnouncer(services.Node),
+		logger:    services.Logger.Named(DriverName),
+	}
+	d.Service = service.New(
+		service.MonoApply(d.applyConfig),
+		service.WithParser(config.ReadBytes),
+	)
+	return d
+}
+
+// Driver polls Modbus devices over TCP or serial lines, exposing their points as traits.
+type Driver struct {
+	*service.Service[config.Root]
+	logger    *zap.Logger
+	announcer *node.ReplaceAnnouncer
+}
+
+func (d *Driver) applyConfig(ctx context.Context, cfg config.Root) error {
+	a := d.announcer.Replace(ctx)
+	statuses := statuspb.NewMap(a)
+
+	if cfg.Metadata != nil {
+		a.Announce(cfg.Name, node.HasMetadata(cfg.Metadata))
+	}
+
+	// devices that share a connection share a client, so their requests don't interleave
+	clients := make(map[string]*comm.Client)
+	clientFor := func(c *config.Conn) (*comm.Client, error) {
+		key := c.Key()
+		if client, ok := clients[key]; ok {
+			return client, nil
+		}
+		opts := []comm.Option{
+			comm.WithTimeout(cfg.Timing.Timeout.Duration),
+			comm.WithBackoff(cfg.Timing.BackoffMin.Duration, cfg.Timing.BackoffMax.Duration),
+			comm.WithLogger(d.logger),
+		}
+		var client *comm.Client
+		if c.Serial != nil {
+			client = comm.NewRTUClient(transport.SerialConfig{
+				Port:     c.Serial.Port,
+				BaudRate: c.Serial.BaudRate,
+				DataBits: c.Serial.DataBits,
+				StopBits: c.Serial.StopBits,
+				Parity:   c.Serial.Parity,
+			}, opts...)
+		} else {
+			var err error
+			client, err = comm.NewTCPClient(c.TCPAddress(), opts...)
+			if err != nil {
+				return nil, fmt.Errorf("conn.tcp: %w", err)
+			}
+		}
+		clients[key] = client
+		return client, nil
+	}
+
+	var errs []error
+	var devices []*device
+	for _, devCfg := range cfg.Devices {
+		client, err := clientFor(devCfg.Conn)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("device %q: %w", devCfg.Name, err))
+			continue
+		}
+		dev := newDevice(devCfg, client, statuses, d.logger)
+		if devCfg.Metadata != nil {
+			a.Announce(devCfg.Name, node.HasMetadata(devCfg.Metadata))
+		}
+		for _, traitCfg := range devCfg.Traits {
+			t, err := newTrait(dev, traitCfg, d.logger)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("device %q trait %s: %w", devCfg.Name, traitCfg.Kind, err))
+				continue
+			}
+			t.AnnounceSelf(a)
+			dev.onUpdate(t.update)
+		}
+		devices = append(devices, dev)
+	}
+	if err := errors.Join(errs...); err != nil {
+		// devices and traits that are configured correctly still run
+		d.logger.Error("failed to set up some devices", zap.Error(err))
+	}
+
+	for _, dev := range devices {
+		go dev.run(ctx)
+	}
+	go func() {
+		<-ctx.Done()
+		for _, client := range clients {
+			_ = client.Close()
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,58 @@
+package modbus
+
+import (
+	"encoding/json"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+	"github.com/smart-core-os/sc-golang/pkg/trait/electricpb"
+)
+
+type electric struct {
+	name  string
+	cfg   config.ElectricConfig
+	model *electricpb.Model
+}
+
+func readElectricConfig(raw []byte) (cfg config.ElectricConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newElectric(dev *device, raw config.RawTrait) (*electric, error) {
+	cfg, err := readElectricConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPoints(dev, cfg.Voltage, cfg.Current, cfg.RealPower, cfg.ApparentPower, cfg.ReactivePower, cfg.PowerFactor); err != nil {
+		return nil, err
+	}
+	return &electric{name: traitName(dev, raw), cfg: cfg, model: electricpb.NewModel()}, nil
+}
+
+func (e *electric) AnnounceSelf(a node.Announcer) node.Undo {
+	return a.Announce(e.name, withMetadata(e.cfg.Trait,
+		node.HasTrait(trait.Electric, node.WithClients(electricpb.WrapApi(electricpb.NewModelServer(e.model)))),
+	)...)
+}
+
+func (e *electric) update(values map[string]float64) {
+	demand := &traits.ElectricDemand{}
+	if v, ok := float32Value(values, e.cfg.Current); ok {
+		demand.Current = v
+	}
+	optional := func(point string) *float32 {
+		if v, ok := float32Value(values, point); ok {
+			return &v
+		}
+		return nil
+	}
+	demand.Voltage = optional(e.cfg.Voltage)
+	demand.RealPower = optional(e.cfg.RealPower)
+	demand.ApparentPower = optional(e.cfg.ApparentPower)
+	demand.ReactivePower = optional(e.cfg.ReactivePower)
+	demand.PowerFactor = optional(e.cfg.PowerFactor)
+	_, _ = e.model.UpdateDemand(demand)
+}
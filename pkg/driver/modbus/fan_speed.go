@@ -0,0 +1,75 @@
+package modbus
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+	"github.com/smart-core-os/sc-golang/pkg/trait/fanspeedpb"
+)
+
+type fanSpeed struct {
+	*fanspeedpb.ModelServer
+	name  string
+	dev   *device
+	cfg   config.FanSpeedConfig
+	model *fanspeedpb.Model
+}
+
+func readFanSpeedConfig(raw []byte) (cfg config.FanSpeedConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newFanSpeed(dev *device, raw config.RawTrait) (*fanSpeed, error) {
+	cfg, err := readFanSpeedConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPoints(dev, cfg.Percentage); err != nil {
+		return nil, err
+	}
+	model := fanspeedpb.NewModel()
+	return &fanSpeed{
+		ModelServer: fanspeedpb.NewModelServer(model),
+		name:        traitName(dev, raw),
+		dev:         dev,
+		cfg:         cfg,
+		model:       model,
+	}, nil
+}
+
+func (f *fanSpeed) AnnounceSelf(a node.Announcer) node.Undo {
+	return a.Announce(f.name, withMetadata(f.cfg.Trait,
+		node.HasTrait(trait.FanSpeed, node.WithClients(fanspeedpb.WrapApi(f))),
+	)...)
+}
+
+// UpdateFanSpeed writes the percentage to the device, presets and direction aren't supported.
+func (f *fanSpeed) UpdateFanSpeed(ctx context.Context, request *traits.UpdateFanSpeedRequest) (*traits.FanSpeed, error) {
+	if f.cfg.Percentage == "" {
+		return nil, status.Error(codes.Unimplemented, "percentage point not configured")
+	}
+	percentage := float64(request.GetFanSpeed().GetPercentage())
+	if request.GetRelative() {
+		current, _ := f.dev.value(f.cfg.Percentage)
+		percentage += current
+	}
+	percentage = min(max(percentage, 0), 100)
+	if err := f.dev.write(ctx, f.cfg.Percentage, percentage); err != nil {
+		return nil, err
+	}
+	return f.model.FanSpeed(), nil
+}
+
+func (f *fanSpeed) update(values map[string]float64) {
+	if v, ok := float32Value(values, f.cfg.Percentage); ok {
+		_, _ = f.model.UpdateFanSpeed(&traits.FanSpeed{Percentage: v})
+	}
+}
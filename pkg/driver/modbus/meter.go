@@ -0,0 +1,56 @@
+package modbus
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/meter"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+type meterTrait struct {
+	name  string
+	cfg   config.MeterConfig
+	model *meter.Model
+}
+
+func readMeterConfig(raw []byte) (cfg config.MeterConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newMeter(dev *device, raw config.RawTrait) (*meterTrait, error) {
+	cfg, err := readMeterConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPoints(dev, cfg.Usage, cfg.Produced); err != nil {
+		return nil, err
+	}
+	return &meterTrait{name: traitName(dev, raw), cfg: cfg, model: meter.NewModel()}, nil
+}
+
+func (m *meterTrait) AnnounceSelf(a node.Announcer) node.Undo {
+	info := &meter.InfoServer{MeterReading: &gen.MeterReadingSupport{UsageUnit: m.cfg.Unit, ProducedUnit: m.cfg.Unit}}
+	return a.Announce(m.name, withMetadata(m.cfg.Trait,
+		node.HasTrait(meter.TraitName, node.WithClients(gen.WrapMeterApi(meter.NewModelServer(m.model)), gen.WrapMeterInfo(info))),
+	)...)
+}
+
+func (m *meterTrait) update(values map[string]float64) {
+	reading := &gen.MeterReading{EndTime: timestamppb.Now()}
+	var known bool
+	if v, ok := float32Value(values, m.cfg.Usage); ok {
+		reading.Usage, known = v, true
+	}
+	if v, ok := float32Value(values, m.cfg.Produced); ok {
+		reading.Produced, known = v, true
+	}
+	if !known {
+		return
+	}
+	_, _ = m.model.UpdateMeterReading(reading)
+}
@@ -0,0 +1,83 @@
+package modbus
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+	"github.com/smart-core-os/sc-golang/pkg/trait/onoffpb"
+)
+
+type onOff struct {
+	*onoffpb.ModelServer
+	name  string
+	dev   *device
+	cfg   config.OnOffConfig
+	model *onoffpb.Model
+}
+
+func readOnOffConfig(raw []byte) (cfg config.OnOffConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newOnOff(dev *device, raw config.RawTrait) (*onOff, error) {
+	cfg, err := readOnOffConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPoints(dev, cfg.OnOff); err != nil {
+		return nil, err
+	}
+	model := onoffpb.NewModel()
+	return &onOff{
+		ModelServer: onoffpb.NewModelServer(model),
+		name:        traitName(dev, raw),
+		dev:         dev,
+		cfg:         cfg,
+		model:       model,
+	}, nil
+}
+
+func (o *onOff) AnnounceSelf(a node.Announcer) node.Undo {
+	return a.Announce(o.name, withMetadata(o.cfg.Trait,
+		node.HasTrait(trait.OnOff, node.WithClients(onoffpb.WrapApi(o))),
+	)...)
+}
+
+func (o *onOff) UpdateOnOff(ctx context.Context, request *traits.UpdateOnOffRequest) (*traits.OnOff, error) {
+	var v float64
+	switch request.GetOnOff().GetState() {
+	case traits.OnOff_ON:
+		v = o.cfg.OnValueOrDefault()
+	case traits.OnOff_OFF:
+		v = o.cfg.OffValueOrDefault()
+	default:
+		return nil, status.Error(codes.InvalidArgument, "state must be ON or OFF")
+	}
+	if o.cfg.OnOff == "" {
+		return nil, status.Error(codes.Unimplemented, "on off point not configured")
+	}
+	if err := o.dev.write(ctx, o.cfg.OnOff, v); err != nil {
+		return nil, err
+	}
+	return o.model.GetOnOff()
+}
+
+func (o *onOff) update(values map[string]float64) {
+	v, ok := values[o.cfg.OnOff]
+	if !ok || o.cfg.OnOff == "" {
+		return
+	}
+	state := traits.OnOff_OFF
+	if v == o.cfg.OnValueOrDefault() {
+		state = traits.OnOff_ON
+	}
+	_, _ = o.model.UpdateOnOff(&traits.OnOff{State: state})
+}
@@ -0,0 +1,86 @@
+package modbus
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/auto/udmi"
+	"github.com/smart-core-os/sc-bos/pkg/driver/modbus/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/udmipb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+// udmiTrait exports the values of points as UDMI pointset events.
+type udmiTrait struct {
+	*udmipb.ModelServer
+	name   string
+	cfg    config.UdmiConfig
+	model  *udmipb.Model
+	logger *zap.Logger
+
+	last udmi.PointsEvent
+}
+
+func readUdmiConfig(raw []byte) (cfg config.UdmiConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newUdmi(dev *device, raw config.RawTrait, logger *zap.Logger) (*udmiTrait, error) {
+	cfg, err := readUdmiConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPoints(dev, cfg.Points...); err != nil {
+		return nil, err
+	}
+	model := udmipb.NewModel()
+	return &udmiTrait{
+		ModelServer: udmipb.NewModelServer(model),
+		name:        traitName(dev, raw),
+		cfg:         cfg,
+		model:       model,
+		logger:      logger,
+	}, nil
+}
+
+func (u *udmiTrait) AnnounceSelf(a node.Announcer) node.Undo {
+	return a.Announce(u.name, withMetadata(u.cfg.Trait,
+		node.HasTrait(udmipb.TraitName, node.WithClients(gen.WrapUdmiService(u))),
+	)...)
+}
+
+func (u *udmiTrait) PullControlTopics(_ *gen.PullControlTopicsRequest, server gen.UdmiService_PullControlTopicsServer) error {
+	// points can't be controlled via UDMI, yet
+	<-server.Context().Done()
+	return nil
+}
+
+func (u *udmiTrait) OnMessage(context.Context, *gen.OnMessageRequest) (*gen.OnMessageResponse, error) {
+	return &gen.OnMessageResponse{}, nil
+}
+
+func (u *udmiTrait) update(values map[string]float64) {
+	event := make(udmi.PointsEvent, len(u.cfg.Points))
+	for _, p := range u.cfg.Points {
+		if v, ok := values[p]; ok {
+			event[p] = udmi.PointValue{PresentValue: v}
+		}
+	}
+	if len(event) == 0 || event.Equal(u.last) {
+		return
+	}
+	u.last = event
+	body, err := json.Marshal(event)
+	if err != nil {
+		u.logger.Error("failed to marshal points event", zap.Error(err))
+		return
+	}
+	_, _ = u.model.UpdateExportMessage(&gen.MqttMessage{
+		Topic:   u.cfg.TopicPrefix + config.PointsEventTopicSuffix,
+		Payload: string(body),
+	})
+}
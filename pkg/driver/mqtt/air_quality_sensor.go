@@ -0,0 +1,74 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mqtt/config"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/resource"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+	"github.com/smart-core-os/sc-golang/pkg/trait/airqualitysensorpb"
+)
+
+type airQualitySensor struct {
+	name  string
+	cfg   config.AirQualitySensorConfig
+	model *airqualitysensorpb.Model
+}
+
+func readAirQualitySensorConfig(raw []byte) (cfg config.AirQualitySensorConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newAirQualitySensor(dev *device, raw config.RawTrait) (*airQualitySensor, error) {
+	cfg, err := readAirQualitySensorConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	t := &airQualitySensor{
+		name:  traitName(dev, raw),
+		cfg:   cfg,
+		model: airqualitysensorpb.NewModel(),
+	}
+	fields := []struct {
+		cfg  *config.Value
+		path string
+		set  func(aq *traits.AirQuality, v float32)
+	}{
+		{cfg.CarbonDioxideLevel, "carbon_dioxide_level", func(aq *traits.AirQuality, v float32) { aq.CarbonDioxideLevel = &v }},
+		{cfg.VolatileOrganicCompounds, "volatile_organic_compounds", func(aq *traits.AirQuality, v float32) { aq.VolatileOrganicCompounds = &v }},
+		{cfg.AirPressure, "air_pressure", func(aq *traits.AirQuality, v float32) { aq.AirPressure = &v }},
+		{cfg.InfectionRisk, "infection_risk", func(aq *traits.AirQuality, v float32) { aq.InfectionRisk = &v }},
+		{cfg.Score, "score", func(aq *traits.AirQuality, v float32) { aq.Score = &v }},
+		{cfg.ParticulateMatter1, "particulate_matter_1", func(aq *traits.AirQuality, v float32) { aq.ParticulateMatter_1 = &v }},
+		{cfg.ParticulateMatter25, "particulate_matter_25", func(aq *traits.AirQuality, v float32) { aq.ParticulateMatter_25 = &v }},
+		{cfg.ParticulateMatter10, "particulate_matter_10", func(aq *traits.AirQuality, v float32) { aq.ParticulateMatter_10 = &v }},
+		{cfg.AirChangePerHour, "air_change_per_hour", func(aq *traits.AirQuality, v float32) { aq.AirChangePerHour = &v }},
+	}
+	var errs []error
+	for _, f := range fields {
+		_, err := dev.value(f.cfg, func(v *value, doc any) error {
+			n, err := v.number(doc)
+			if err != nil {
+				return err
+			}
+			aq := &traits.AirQuality{}
+			f.set(aq, float32(n))
+			_, err = t.model.UpdateAirQuality(aq, resource.WithUpdatePaths(f.path))
+			return err
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return t, errors.Join(errs...)
+}
+
+func (t *airQualitySensor) AnnounceSelf(a node.Announcer) node.Undo {
+	return a.Announce(t.name, withMetadata(t.cfg.Trait,
+		node.HasTrait(trait.AirQualitySensor, node.WithClients(airqualitysensorpb.WrapApi(airqualitysensorpb.NewModelServer(t.model)))),
+	)...)
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+// Root is the configuration of the mqtt driver.
+type Root struct {
+	driver.BaseConfig
+
+	// Metadata is applied to the driver name.
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+	Broker   Broker           `json:"broker,omitempty"`
+	Devices  []Device         `json:"devices,omitempty"`
+}
+
+// Broker configures the connection to the MQTT broker.
+type Broker struct {
+	// Host is the url of the broker, like tcp://localhost:1883.
+	Host     string `json:"host,omitempty"`
+	Username string `json:"username,omitempty"`
+	jsontypes.Password
+	// ClientID identifies the connection to the broker, defaults to a random id.
+	ClientID string `json:"clientId,omitempty"`
+	// QoS is used for subscriptions and publishing commands, defaults to 0.
+	QoS byte `json:"qos,omitempty"`
+}
+
+func (b Broker) ClientOptions() (*paho.ClientOptions, error) {
+	opts := paho.NewClientOptions()
+	opts.AddBroker(b.Host)
+	opts.SetClientID(b.ClientID)
+	opts.SetUsername(b.Username)
+	if b.Password != (jsontypes.Password{}) {
+		password, err := b.Password.Read()
+		if err != nil {
+			return nil, fmt.Errorf("broker password: %w", err)
+		}
+		opts.SetPassword(password)
+	}
+	// messages are handled in the order they arrive, so traits report the latest value
+	opts.SetOrderMatters(true)
+	// we subscribe again when reconnected, so the broker doesn't need to remember us
+	opts.SetCleanSession(true)
+	opts.SetAutoReconnect(true)
+	return opts, nil
+}
+
+// Device is a device whose state is published to MQTT topics.
+type Device struct {
+	// Name is the Smart Core name of the device, used for its traits unless they have their own name.
+	Name     string           `json:"name,omitempty"`
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+	// Topic is the topic the device publishes to, used by values that don't have their own.
+	// Topics can include MQTT wildcards.
+	Topic string `json:"topic,omitempty"`
+	// Payload describes how messages on the device topics are decoded.
+	Payload Payload `json:"payload,omitempty"`
+
+	Traits []RawTrait `json:"traits,omitempty"`
+}
+
+// Payload describes the format of messages.
+type Payload struct {
+	// Format is either "json" (the default) or "proto".
+	Format string `json:"format,omitempty"`
+	// ProtoMessage is the full name of the protobuf message used when Format is "proto", like smartcore.traits.AirQuality.
+	// The message is converted to JSON using the protojson field names before values are read from it.
+	ProtoMessage string `json:"protoMessage,omitempty"`
+}
+
+const (
+	FormatJSON  = "json"
+	FormatProto = "proto"
+)
+
+// ReadBytes decodes and validates data.
+func ReadBytes(data []byte) (cfg Root, err error) {
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.validate()
+}
+
+func (r Root) validate() error {
+	var errs []error
+	if r.Broker.Host == "" {
+		errs = append(errs, errors.New("broker.host is required"))
+	}
+	if r.Broker.QoS > 2 {
+		errs = append(errs, fmt.Errorf("broker.qos must be 0, 1, or 2, got %d", r.Broker.QoS))
+	}
+	names := make(map[string]bool)
+	for _, d := range r.Devices {
+		if d.Name == "" {
+			errs = append(errs, errors.New("device name is required"))
+			continue
+		}
+		if names[d.Name] {
+			errs = append(errs, fmt.Errorf("device %q: name is used more than once", d.Name))
+		}
+		names[d.Name] = true
+		switch d.Payload.Format {
+		case "", FormatJSON:
+		case FormatProto:
+			if d.Payload.ProtoMessage == "" {
+				errs = append(errs, fmt.Errorf("device %q: payload.protoMessage is required for proto payloads", d.Name))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("device %q: unknown payload format %q", d.Name, d.Payload.Format))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+)
+
+type Trait struct {
+	// Name of the device implementing the trait, defaults to the name of the device.
+	Name     string           `json:"name,omitempty"`
+	Kind     trait.Name       `json:"kind,omitempty"`
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+}
+
+type RawTrait struct {
+	Trait
+	Raw json.RawMessage `json:"-"`
+}
+
+func (c *RawTrait) MarshalJSON() ([]byte, error) {
+	return c.Raw, nil
+}
+
+func (c *RawTrait) UnmarshalJSON(buf []byte) error {
+	c.Raw = buf
+	return json.Unmarshal(buf, &c.Trait)
+}
+
+// Value configures where a trait value comes from.
+type Value struct {
+	// Topic the value is published to, defaults to the device topic.
+	Topic string `json:"topic,omitempty"`
+	// Path to the value in the payload, like "sensors.co2" or "readings[0].value".
+	// An empty path uses the whole payload.
+	Path string `json:"path,omitempty"`
+	// Scale multiplies numeric values, defaults to 1.
+	Scale float64 `json:"scale,omitempty"`
+	// Offset is added to numeric values after scaling.
+	Offset float64 `json:"offset,omitempty"`
+	// Enum maps payload values, formatted as strings, to trait values.
+	// For example {"true": "OCCUPIED", "false": "UNOCCUPIED"}.
+	Enum map[string]string `json:"enum,omitempty"`
+}
+
+// Command configures how a trait is written to, by publishing to a topic.
+type Command struct {
+	Topic string `json:"topic,omitempty"`
+	// Retain asks the broker to keep the last command for new subscribers.
+	Retain bool `json:"retain,omitempty"`
+}
+
+// AirQualitySensorConfig is configured by a Device that wants to implement the AirQualitySensor trait.
+type AirQualitySensorConfig struct {
+	Trait
+	CarbonDioxideLevel       *Value `json:"carbonDioxideLevel,omitempty"`
+	VolatileOrganicCompounds *Value `json:"volatileOrganicCompounds,omitempty"`
+	AirPressure              *Value `json:"airPressure,omitempty"`
+	InfectionRisk            *Value `json:"infectionRisk,omitempty"`
+	Score                    *Value `json:"score,omitempty"`
+	ParticulateMatter1       *Value `json:"particulateMatter1,omitempty"`
+	ParticulateMatter25      *Value `json:"particulateMatter25,omitempty"`
+	ParticulateMatter10      *Value `json:"particulateMatter10,omitempty"`
+	AirChangePerHour         *Value `json:"airChangePerHour,omitempty"`
+}
+
+// OccupancySensorConfig is configured by a Device that wants to implement the OccupancySensor trait.
+type OccupancySensorConfig struct {
+	Trait
+	// State is mapped to an Occupancy_State name using its Enum.
+	// Without an Enum true and non-zero numbers are OCCUPIED, false and zero are UNOCCUPIED.
+	State       *Value `json:"state,omitempty"`
+	PeopleCount *Value `json:"peopleCount,omitempty"`
+}
+
+// MeterConfig is configured by a Device that wants to implement the Meter trait.
+type MeterConfig struct {
+	Trait
+	Unit     string `json:"unit,omitempty"`
+	Usage    *Value `json:"usage,omitempty"`
+	Produced *Value `json:"produced,omitempty"`
+}
+
+// OnOffConfig is configured by a Device that wants to implement the OnOff trait.
+type OnOffConfig struct {
+	Trait
+	// State is mapped to ON or OFF using its Enum.
+	// Without an Enum true, non-zero numbers, and "on" are ON, other values are OFF.
+	State *Value `json:"state,omitempty"`
+	// Command is published to when the state is updated, the trait can't be updated without it.
+	Command *Command `json:"command,omitempty"`
+	// OnPayload is published to turn on, defaults to "ON".
+	OnPayload string `json:"onPayload,omitempty"`
+	// OffPayload is published to turn off, defaults to "OFF".
+	OffPayload string `json:"offPayload,omitempty"`
+}
@@ -0,0 +1,74 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/mqtt/config"
+)
+
+// publishFunc publishes payload to topic.
+type publishFunc func(ctx context.Context, topic string, retain bool, payload []byte) error
+
+// device decodes messages published to its topics, passing them to the traits that read values from them.
+type device struct {
+	cfg     config.Device
+	decode  decoder
+	publish publishFunc
+	logger  *zap.Logger
+
+	// handlers of decoded messages, keyed by topic filter
+	handlers map[string][]func(doc any) error
+}
+
+func newDevice(cfg config.Device, publish publishFunc, logger *zap.Logger) (*device, error) {
+	decode, err := newDecoder(cfg.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return &device{
+		cfg:      cfg,
+		decode:   decode,
+		publish:  publish,
+		logger:   logger.With(zap.String("device", cfg.Name)),
+		handlers: make(map[string][]func(doc any) error),
+	}, nil
+}
+
+// value returns a value reading cfg from the device topics, or nil if cfg is nil.
+// f is called with each message published to the topic of the value.
+func (d *device) value(cfg *config.Value, f func(v *value, doc any) error) (*value, error) {
+	v, err := newValue(cfg, d.cfg.Topic)
+	if err != nil || v == nil {
+		return v, err
+	}
+	d.handlers[v.topic] = append(d.handlers[v.topic], func(doc any) error {
+		return f(v, doc)
+	})
+	return v, nil
+}
+
+// topics returns the topic filters the device needs to subscribe to.
+func (d *device) topics() []string {
+	var topics []string
+	for t := range d.handlers {
+		topics = append(topics, t)
+	}
+	return topics
+}
+
+// handleMessage decodes payload, which was published to a topic matching filter.
+func (d *device) handleMessage(filter, topic string, payload []byte) {
+	doc, err := d.decode(payload)
+	if err != nil {
+		d.logger.Debug("failed to decode message", zap.String("topic", topic), zap.Error(err))
+		return
+	}
+	for _, h := range d.handlers[filter] {
+		if err := h(doc); err != nil && !errors.Is(err, errNoValue) {
+			d.logger.Debug("failed to read value from message", zap.String("topic", topic), zap.Error(err))
+		}
+	}
+}
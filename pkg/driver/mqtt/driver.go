@@ -0,0 +1,156 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mqtt/config"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+)
+
+const DriverName = "mqtt"
+
+var Factory driver.Factory = factory{}
+
+type factory struct{}
+
+func (f factory) New(services driver.Services) service.Lifecycle {
+	logger := services.Logger.Named(DriverName)
+	d := &Driver{
+		announcer: node.NewReplaceAnnouncer(services.Node),
+		logger:    logger,
+	}
+	d.Service = service.New(
+		service.MonoApply(d.applyConfig),
+		service.WithParser(config.ReadBytes),
+		service.WithRetry[config.Root](service.RetryWithLogger(func(logContext service.RetryContext) {
+			logContext.LogTo("applyConfig", logger)
+		}), service.RetryWithMinDelay(5*time.Second), service.RetryWithInitialDelay(5*time.Second)),
+	)
+	return d
+}
+
+// Driver subscribes to MQTT topics, exposing values from the messages published to them as traits.
+type Driver struct {
+	*service.Service[config.Root]
+	logger    *zap.Logger
+	announcer *node.ReplaceAnnouncer
+}
+
+func (d *Driver) applyConfig(ctx context.Context, cfg config.Root) error {
+	opts, err := cfg.Broker.ClientOptions()
+	if err != nil {
+		return err
+	}
+
+	var client paho.Client
+	publish := func(ctx context.Context, topic string, retain bool, payload []byte) error {
+		return waitToken(ctx, client.Publish(topic, cfg.Broker.QoS, retain, payload))
+	}
+
+	var devices []*device
+	var deviceTraits []node.SelfAnnouncer
+	var traitErrs []error
+	for _, devCfg := range cfg.Devices {
+		dev, err := newDevice(devCfg, publish, d.logger)
+		if err != nil {
+			return fmt.Errorf("device %q: %w", devCfg.Name, err)
+		}
+		for _, traitCfg := range devCfg.Traits {
+			t, err := newTrait(dev, traitCfg)
+			if err != nil {
+				traitErrs = append(traitErrs, fmt.Errorf("device %q trait %s: %w", devCfg.Name, traitCfg.Kind, err))
+				continue
+			}
+			deviceTraits = append(deviceTraits, t)
+		}
+		devices = append(devices, dev)
+	}
+	if err := errors.Join(traitErrs...); err != nil {
+		// the other traits are still useful
+		d.logger.Error("failed to set up some traits", zap.Error(err))
+	}
+
+	r := newRouter(devices)
+	// subscriptions are made again each time we connect, as the session isn't kept by the broker
+	opts.SetOnConnectHandler(func(c paho.Client) {
+		for filter, handle := range r.routes {
+			token := c.Subscribe(filter, cfg.Broker.QoS, func(_ paho.Client, msg paho.Message) {
+				handle(msg.Topic(), msg.Payload())
+			})
+			go func() {
+				if err := waitToken(ctx, token); err != nil {
+					d.logger.Warn("failed to subscribe", zap.String("topic", filter), zap.Error(err))
+				}
+			}()
+		}
+	})
+	opts.SetConnectionLostHandler(func(_ paho.Client, err error) {
+		d.logger.Warn("connection lost", zap.Error(err))
+	})
+	client = paho.NewClient(opts)
+	if err := waitToken(ctx, client.Connect()); err != nil {
+		client.Disconnect(0)
+		return fmt.Errorf("connect: %w", err)
+	}
+	d.logger.Debug("connected", zap.String("host", cfg.Broker.Host))
+	go func() {
+		<-ctx.Done()
+		client.Disconnect(250)
+	}()
+
+	a := d.announcer.Replace(ctx)
+	if cfg.Metadata != nil {
+		a.Announce(cfg.Name, node.HasMetadata(cfg.Metadata))
+	}
+	for _, devCfg := range cfg.Devices {
+		if devCfg.Metadata != nil {
+			a.Announce(devCfg.Name, node.HasMetadata(devCfg.Metadata))
+		}
+	}
+	for _, t := range deviceTraits {
+		t.AnnounceSelf(a)
+	}
+	return nil
+}
+
+// waitToken waits for t to complete, returning its error, or for ctx to be done.
+func waitToken(ctx context.Context, t paho.Token) error {
+	select {
+	case <-t.Done():
+		return t.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// router dispatches messages to devices.
+// Devices can subscribe to the same topic filters, but the client only allows one handler for each.
+type router struct {
+	routes map[string]func(topic string, payload []byte)
+}
+
+func newRouter(devices []*device) *router {
+	byFilter := make(map[string][]*device)
+	for _, dev := range devices {
+		for _, filter := range dev.topics() {
+			byFilter[filter] = append(byFilter[filter], dev)
+		}
+	}
+	r := &router{routes: make(map[string]func(topic string, payload []byte))}
+	for filter, devs := range byFilter {
+		r.routes[filter] = func(topic string, payload []byte) {
+			for _, dev := range devs {
+				dev.handleMessage(filter, topic, payload)
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,154 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mqtt/internal/broker"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+func TestDriver(t *testing.T) {
+	b, err := broker.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = b.Close() })
+
+	n := node.New("test")
+	d := Factory.New(driver.Services{Logger: zaptest.NewLogger(t), Node: n})
+	if _, err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = d.Stop() })
+	_, err = d.Configure([]byte(fmt.Sprintf(`{
+		"name": "mqtt", "type": "mqtt",
+		"broker": {"host": %q, "qos": 1},
+		"devices": [
+			{
+				"name": "room1/sensor", "topic": "sensors/room1",
+				"traits": [
+					{"kind": "smartcore.traits.AirQualitySensor", "carbonDioxideLevel": {"path": "air.co2"}, "particulateMatter25": {"path": "pm[1]", "scale": 0.1}},
+					{"kind": "smartcore.traits.OccupancySensor", "state": {"path": "presence", "enum": {"true": "OCCUPIED", "false": "UNOCCUPIED"}}}
+				]
+			},
+			{
+				"name": "room1/plug",
+				"traits": [
+					{"kind": "smartcore.traits.OnOff", "state": {"topic": "zigbee2mqtt/plug", "path": "state"}, "command": {"topic": "zigbee2mqtt/plug/set"}, "onPayload": "{\"state\":\"ON\"}", "offPayload": "{\"state\":\"OFF\"}"},
+					{"kind": "smartcore.bos.Meter", "unit": "kWh", "usage": {"topic": "zigbee2mqtt/plug", "path": "energy"}}
+				]
+			},
+			{
+				"name": "room1/proto", "topic": "proto/room1",
+				"payload": {"format": "proto", "protoMessage": "smartcore.traits.AirQuality"},
+				"traits": [
+					{"kind": "smartcore.traits.AirQualitySensor", "carbonDioxideLevel": {"path": "carbonDioxideLevel"}}
+				]
+			}
+		]
+	}`, b.URL())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	// wait for the driver to connect and subscribe
+	eventually(t, ctx, func() bool {
+		return b.Subscribers("sensors/room1") > 0 && b.Subscribers("zigbee2mqtt/plug") > 0 && b.Subscribers("proto/room1") > 0
+	})
+
+	var aqClient traits.AirQualitySensorApiClient
+	var occClient traits.OccupancySensorApiClient
+	var onOffClient traits.OnOffApiClient
+	var meterClient gen.MeterApiClient
+	for _, c := range []any{&aqClient, &occClient, &onOffClient, &meterClient} {
+		if err := n.Client(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("json", func(t *testing.T) {
+		b.Publish("sensors/room1", []byte(`{"air": {"co2": 612}, "pm": [1, 85], "presence": true}`), false)
+		eventually(t, ctx, func() bool {
+			aq, err := aqClient.GetAirQuality(ctx, &traits.GetAirQualityRequest{Name: "room1/sensor"})
+			return err == nil && aq.GetCarbonDioxideLevel() == 612 && aq.GetParticulateMatter_25() == 8.5
+		})
+		eventually(t, ctx, func() bool {
+			occ, err := occClient.GetOccupancy(ctx, &traits.GetOccupancyRequest{Name: "room1/sensor"})
+			return err == nil && occ.State == traits.Occupancy_OCCUPIED
+		})
+
+		// messages without a value leave it unchanged
+		b.Publish("sensors/room1", []byte(`{"presence": false}`), false)
+		eventually(t, ctx, func() bool {
+			occ, err := occClient.GetOccupancy(ctx, &traits.GetOccupancyRequest{Name: "room1/sensor"})
+			return err == nil && occ.State == traits.Occupancy_UNOCCUPIED
+		})
+		aq, err := aqClient.GetAirQuality(ctx, &traits.GetAirQualityRequest{Name: "room1/sensor"})
+		if err != nil || aq.GetCarbonDioxideLevel() != 612 {
+			t.Fatalf("co2 = %v, %v; want 612", aq.GetCarbonDioxideLevel(), err)
+		}
+	})
+
+	t.Run("proto", func(t *testing.T) {
+		co2 := float32(450)
+		payload, err := proto.Marshal(&traits.AirQuality{CarbonDioxideLevel: &co2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Publish("proto/room1", payload, false)
+		eventually(t, ctx, func() bool {
+			aq, err := aqClient.GetAirQuality(ctx, &traits.GetAirQualityRequest{Name: "room1/proto"})
+			return err == nil && aq.GetCarbonDioxideLevel() == 450
+		})
+	})
+
+	t.Run("command", func(t *testing.T) {
+		b.Publish("zigbee2mqtt/plug", []byte(`{"state": "OFF", "energy": 1.25}`), false)
+		eventually(t, ctx, func() bool {
+			m, err := meterClient.GetMeterReading(ctx, &gen.GetMeterReadingRequest{Name: "room1/plug"})
+			return err == nil && m.Usage == 1.25
+		})
+
+		published := b.Watch()
+		_, err := onOffClient.UpdateOnOff(ctx, &traits.UpdateOnOffRequest{Name: "room1/plug", OnOff: &traits.OnOff{State: traits.OnOff_ON}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case msg := <-published:
+			if msg.Topic != "zigbee2mqtt/plug/set" || string(msg.Payload) != `{"state":"ON"}` {
+				t.Fatalf("published %s %s", msg.Topic, msg.Payload)
+			}
+		case <-ctx.Done():
+			t.Fatal("command not published")
+		}
+
+		// the state comes from the device
+		b.Publish("zigbee2mqtt/plug", []byte(`{"state": "ON", "energy": 1.26}`), false)
+		eventually(t, ctx, func() bool {
+			oo, err := onOffClient.GetOnOff(ctx, &traits.GetOnOffRequest{Name: "room1/plug"})
+			return err == nil && oo.State == traits.OnOff_ON
+		})
+	})
+}
+
+func eventually(t *testing.T, ctx context.Context, f func() bool) {
+	t.Helper()
+	for !f() {
+		select {
+		case <-ctx.Done():
+			t.Fatal("condition not met before timeout")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,379 @@
+// Package broker is a minimal in-process MQTT 3.1.1 broker for testing MQTT clients.
+//
+// It supports QoS 0 and 1 publishing, subscriptions with wildcards, and retained messages.
+// Messages are always delivered to subscribers with QoS 0.
+// Sessions, wills, and QoS 2 aren't supported.
+package broker
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+)
+
+const (
+	typeConnect     = 1
+	typeConnack     = 2
+	typePublish     = 3
+	typePuback      = 4
+	typeSubscribe   = 8
+	typeSuback      = 9
+	typeUnsubscribe = 10
+	typeUnsuback    = 11
+	typePingreq     = 12
+	typePingresp    = 13
+	typeDisconnect  = 14
+)
+
+// Message is a message published to the broker.
+type Message struct {
+	Topic   string
+	Payload []byte
+	Retain  bool
+}
+
+type Broker struct {
+	lis net.Listener
+
+	mu       sync.Mutex
+	conns    map[*conn]struct{}
+	retained map[string]Message
+	watchers []chan Message
+}
+
+// Start starts a broker listening on a random local port.
+func Start() (*Broker, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	b := &Broker{
+		lis:      lis,
+		conns:    make(map[*conn]struct{}),
+		retained: make(map[string]Message),
+	}
+	go b.serve()
+	return b, nil
+}
+
+// URL is the address clients connect to.
+func (b *Broker) URL() string {
+	return "tcp://" + b.lis.Addr().String()
+}
+
+// Close stops the broker, disconnecting all clients.
+func (b *Broker) Close() error {
+	err := b.lis.Close()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for c := range b.conns {
+		_ = c.rw.Close()
+	}
+	return err
+}
+
+// Publish sends a message to subscribers, as if published by a client.
+func (b *Broker) Publish(topic string, payload []byte, retain bool) {
+	b.route(Message{Topic: topic, Payload: payload, Retain: retain})
+}
+
+// Watch returns a chan that receives every message published by clients from now on.
+// The chan is buffered, messages are dropped if it is full.
+func (b *Broker) Watch() <-chan Message {
+	ch := make(chan Message, 100)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.watchers = append(b.watchers, ch)
+	return ch
+}
+
+// Subscribers returns how many subscriptions match topic.
+func (b *Broker) Subscribers(topic string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var n int
+	for c := range b.conns {
+		for _, f := range c.filters() {
+			if Match(f, topic) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func (b *Broker) serve() {
+	for {
+		rw, err := b.lis.Accept()
+		if err != nil {
+			return
+		}
+		c := &conn{rw: rw, subs: make(map[string]struct{})}
+		b.mu.Lock()
+		b.conns[c] = struct{}{}
+		b.mu.Unlock()
+		go func() {
+			defer func() {
+				b.mu.Lock()
+				delete(b.conns, c)
+				b.mu.Unlock()
+				_ = rw.Close()
+			}()
+			_ = b.handle(c)
+		}()
+	}
+}
+
+func (b *Broker) route(msg Message) {
+	b.mu.Lock()
+	if msg.Retain {
+		if len(msg.Payload) == 0 {
+			delete(b.retained, msg.Topic)
+		} else {
+			b.retained[msg.Topic] = msg
+		}
+	}
+	var targets []*conn
+	for c := range b.conns {
+		for _, f := range c.filters() {
+			if Match(f, msg.Topic) {
+				targets = append(targets, c)
+				break
+			}
+		}
+	}
+	b.mu.Unlock()
+	for _, c := range targets {
+		_ = c.publish(Message{Topic: msg.Topic, Payload: msg.Payload})
+	}
+}
+
+func (b *Broker) handle(c *conn) error {
+	r := bufio.NewReader(c.rw)
+	for {
+		header, body, err := readPacket(r)
+		if err != nil {
+			return err
+		}
+		switch header >> 4 {
+		case typeConnect:
+			if err := c.write(typeConnack<<4, []byte{0, 0}); err != nil {
+				return err
+			}
+		case typePublish:
+			qos := (header >> 1) & 0x3
+			topic, rest, err := readString(body)
+			if err != nil {
+				return err
+			}
+			if qos > 0 {
+				if len(rest) < 2 {
+					return errors.New("missing packet id")
+				}
+				if err := c.write(typePuback<<4, rest[:2]); err != nil {
+					return err
+				}
+				rest = rest[2:]
+			}
+			msg := Message{Topic: topic, Payload: append([]byte(nil), rest...), Retain: header&1 != 0}
+			b.notify(msg)
+			b.route(msg)
+		case typeSubscribe:
+			if len(body) < 2 {
+				return errors.New("missing packet id")
+			}
+			id, rest := body[:2], body[2:]
+			ack := append([]byte(nil), id...)
+			var filters []string
+			for len(rest) > 0 {
+				var filter string
+				filter, rest, err = readString(rest)
+				if err != nil || len(rest) < 1 {
+					return errors.New("bad subscribe")
+				}
+				ack = append(ack, min(rest[0], 1))
+				rest = rest[1:]
+				filters = append(filters, filter)
+			}
+			c.mu.Lock()
+			for _, f := range filters {
+				c.subs[f] = struct{}{}
+			}
+			c.mu.Unlock()
+			if err := c.write(typeSuback<<4, ack); err != nil {
+				return err
+			}
+			b.sendRetained(c, filters)
+		case typeUnsubscribe:
+			if len(body) < 2 {
+				return errors.New("missing packet id")
+			}
+			id, rest := body[:2], body[2:]
+			c.mu.Lock()
+			for len(rest) > 0 {
+				var filter string
+				filter, rest, err = readString(rest)
+				if err != nil {
+					c.mu.Unlock()
+					return err
+				}
+				delete(c.subs, filter)
+			}
+			c.mu.Unlock()
+			if err := c.write(typeUnsuback<<4, id); err != nil {
+				return err
+			}
+		case typePingreq:
+			if err := c.write(typePingresp<<4, nil); err != nil {
+				return err
+			}
+		case typeDisconnect:
+			return nil
+		case typePuback:
+		default:
+			return fmt.Errorf("unsupported packet type %d", header>>4)
+		}
+	}
+}
+
+func (b *Broker) notify(msg Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.watchers {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+func (b *Broker) sendRetained(c *conn, filters []string) {
+	b.mu.Lock()
+	var msgs []Message
+	for _, msg := range b.retained {
+		for _, f := range filters {
+			if Match(f, msg.Topic) {
+				msgs = append(msgs, msg)
+				break
+			}
+		}
+	}
+	b.mu.Unlock()
+	for _, msg := range msgs {
+		_ = c.publish(msg)
+	}
+}
+
+type conn struct {
+	rw      net.Conn
+	writeMu sync.Mutex
+
+	mu   sync.Mutex
+	subs map[string]struct{}
+}
+
+func (c *conn) filters() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var fs []string
+	for f := range c.subs {
+		fs = append(fs, f)
+	}
+	return fs
+}
+
+func (c *conn) publish(msg Message) error {
+	var header byte = typePublish << 4
+	if msg.Retain {
+		header |= 1
+	}
+	body := appendString(nil, msg.Topic)
+	return c.write(header, append(body, msg.Payload...))
+}
+
+func (c *conn) write(header byte, body []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	packet := []byte{header}
+	packet = appendLength(packet, len(body))
+	_, err := c.rw.Write(append(packet, body...))
+	return err
+}
+
+func readPacket(r *bufio.Reader) (header byte, body []byte, err error) {
+	header, err = r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	var length, shift int
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length |= int(b&0x7F) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		shift += 7
+		if shift > 21 {
+			return 0, nil, errors.New("bad remaining length")
+		}
+	}
+	body = make([]byte, length)
+	_, err = io.ReadFull(r, body)
+	return header, body, err
+}
+
+func appendLength(b []byte, n int) []byte {
+	for {
+		d := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			d |= 0x80
+		}
+		b = append(b, d)
+		if n == 0 {
+			return b
+		}
+	}
+}
+
+func readString(b []byte) (string, []byte, error) {
+	if len(b) < 2 {
+		return "", nil, errors.New("short string")
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+n {
+		return "", nil, errors.New("short string")
+	}
+	return string(b[2 : 2+n]), b[2+n:], nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// Match returns whether topic matches the subscription filter, which can include + and # wildcards.
+func Match(filter, topic string) bool {
+	fs := strings.Split(filter, "/")
+	ts := strings.Split(topic, "/")
+	for i, f := range fs {
+		if f == "#" {
+			return true
+		}
+		if i >= len(ts) {
+			return false
+		}
+		if f != "+" && f != ts[i] {
+			return false
+		}
+	}
+	return len(fs) == len(ts)
+}
@@ -0,0 +1,59 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"errors"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/mqtt/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/meter"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/resource"
+)
+
+type meterTrait struct {
+	name  string
+	cfg   config.MeterConfig
+	model *meter.Model
+}
+
+func readMeterConfig(raw []byte) (cfg config.MeterConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newMeter(dev *device, raw config.RawTrait) (*meterTrait, error) {
+	cfg, err := readMeterConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	m := &meterTrait{name: traitName(dev, raw), cfg: cfg, model: meter.NewModel()}
+	_, usageErr := dev.value(cfg.Usage, func(v *value, doc any) error {
+		n, err := v.number(doc)
+		if err != nil {
+			return err
+		}
+		_, err = m.model.UpdateMeterReading(&gen.MeterReading{Usage: float32(n), EndTime: timestamppb.Now()},
+			resource.WithUpdatePaths("usage", "end_time"))
+		return err
+	})
+	_, producedErr := dev.value(cfg.Produced, func(v *value, doc any) error {
+		n, err := v.number(doc)
+		if err != nil {
+			return err
+		}
+		_, err = m.model.UpdateMeterReading(&gen.MeterReading{Produced: float32(n), EndTime: timestamppb.Now()},
+			resource.WithUpdatePaths("produced", "end_time"))
+		return err
+	})
+	return m, errors.Join(usageErr, producedErr)
+}
+
+func (m *meterTrait) AnnounceSelf(a node.Announcer) node.Undo {
+	info := &meter.InfoServer{MeterReading: &gen.MeterReadingSupport{UsageUnit: m.cfg.Unit, ProducedUnit: m.cfg.Unit}}
+	return a.Announce(m.name, withMetadata(m.cfg.Trait,
+		node.HasTrait(meter.TraitName, node.WithClients(gen.WrapMeterApi(meter.NewModelServer(m.model)), gen.WrapMeterInfo(info))),
+	)...)
+}
@@ -0,0 +1,86 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mqtt/config"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/resource"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+	"github.com/smart-core-os/sc-golang/pkg/trait/occupancysensorpb"
+)
+
+type occupancySensor struct {
+	name  string
+	cfg   config.OccupancySensorConfig
+	model *occupancysensorpb.Model
+}
+
+func readOccupancySensorConfig(raw []byte) (cfg config.OccupancySensorConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newOccupancySensor(dev *device, raw config.RawTrait) (*occupancySensor, error) {
+	cfg, err := readOccupancySensorConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	o := &occupancySensor{name: traitName(dev, raw), cfg: cfg, model: occupancysensorpb.NewModel()}
+	_, stateErr := dev.value(cfg.State, func(v *value, doc any) error {
+		state, err := occupancyState(v, doc)
+		if err != nil {
+			return err
+		}
+		old, _ := o.model.GetOccupancy()
+		if old.GetState() == state {
+			return nil
+		}
+		_, err = o.model.SetOccupancy(&traits.Occupancy{State: state, StateChangeTime: timestamppb.Now()},
+			resource.WithUpdatePaths("state", "state_change_time"))
+		return err
+	})
+	_, countErr := dev.value(cfg.PeopleCount, func(v *value, doc any) error {
+		n, err := v.number(doc)
+		if err != nil {
+			return err
+		}
+		_, err = o.model.SetOccupancy(&traits.Occupancy{PeopleCount: int32(n)}, resource.WithUpdatePaths("people_count"))
+		return err
+	})
+	return o, errors.Join(stateErr, countErr)
+}
+
+// occupancyState reads the state from doc, using the enum of v if it has one.
+func occupancyState(v *value, doc any) (traits.Occupancy_State, error) {
+	if v.cfg.Enum != nil {
+		s, err := v.string(doc)
+		if err != nil {
+			return 0, err
+		}
+		state, ok := traits.Occupancy_State_value[s]
+		if !ok {
+			return 0, fmt.Errorf("unknown occupancy state %q", s)
+		}
+		return traits.Occupancy_State(state), nil
+	}
+	n, err := v.number(doc)
+	if err != nil {
+		return 0, err
+	}
+	if n != 0 {
+		return traits.Occupancy_OCCUPIED, nil
+	}
+	return traits.Occupancy_UNOCCUPIED, nil
+}
+
+func (o *occupancySensor) AnnounceSelf(a node.Announcer) node.Undo {
+	return a.Announce(o.name, withMetadata(o.cfg.Trait,
+		node.HasTrait(trait.OccupancySensor, node.WithClients(occupancysensorpb.WrapApi(occupancysensorpb.NewModelServer(o.model)))),
+	)...)
+}
@@ -0,0 +1,116 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver/mqtt/config"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+	"github.com/smart-core-os/sc-golang/pkg/trait/onoffpb"
+)
+
+type onOff struct {
+	*onoffpb.ModelServer
+	name  string
+	dev   *device
+	cfg   config.OnOffConfig
+	model *onoffpb.Model
+	state *value
+}
+
+func readOnOffConfig(raw []byte) (cfg config.OnOffConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	if cfg.OnPayload == "" {
+		cfg.OnPayload = "ON"
+	}
+	if cfg.OffPayload == "" {
+		cfg.OffPayload = "OFF"
+	}
+	return
+}
+
+func newOnOff(dev *device, raw config.RawTrait) (*onOff, error) {
+	cfg, err := readOnOffConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Command != nil && cfg.Command.Topic == "" {
+		return nil, errors.New("command.topic is required")
+	}
+	model := onoffpb.NewModel()
+	o := &onOff{
+		ModelServer: onoffpb.NewModelServer(model),
+		name:        traitName(dev, raw),
+		dev:         dev,
+		cfg:         cfg,
+		model:       model,
+	}
+	o.state, err = dev.value(cfg.State, func(v *value, doc any) error {
+		state, err := onOffState(v, doc)
+		if err != nil {
+			return err
+		}
+		_, err = o.model.UpdateOnOff(&traits.OnOff{State: state})
+		return err
+	})
+	return o, err
+}
+
+// onOffState reads the state from doc, using the enum of v if it has one.
+func onOffState(v *value, doc any) (traits.OnOff_State, error) {
+	s, err := v.string(doc)
+	if err != nil {
+		return 0, err
+	}
+	if v.cfg.Enum != nil {
+		if state, ok := traits.OnOff_State_value[s]; ok {
+			return traits.OnOff_State(state), nil
+		}
+		return traits.OnOff_OFF, nil
+	}
+	switch strings.ToLower(s) {
+	case "true", "on":
+		return traits.OnOff_ON, nil
+	}
+	if n, err := v.number(doc); err == nil && n != 0 {
+		return traits.OnOff_ON, nil
+	}
+	return traits.OnOff_OFF, nil
+}
+
+func (o *onOff) AnnounceSelf(a node.Announcer) node.Undo {
+	return a.Announce(o.name, withMetadata(o.cfg.Trait,
+		node.HasTrait(trait.OnOff, node.WithClients(onoffpb.WrapApi(o))),
+	)...)
+}
+
+// UpdateOnOff publishes a command to change the state.
+// If the device publishes its state the model is updated when it does, otherwise the new state is assumed.
+func (o *onOff) UpdateOnOff(ctx context.Context, request *traits.UpdateOnOffRequest) (*traits.OnOff, error) {
+	if o.cfg.Command == nil {
+		return nil, status.Error(codes.Unimplemented, "command not configured")
+	}
+	var payload string
+	switch request.GetOnOff().GetState() {
+	case traits.OnOff_ON:
+		payload = o.cfg.OnPayload
+	case traits.OnOff_OFF:
+		payload = o.cfg.OffPayload
+	default:
+		return nil, status.Error(codes.InvalidArgument, "state must be ON or OFF")
+	}
+	if err := o.dev.publish(ctx, o.cfg.Command.Topic, o.cfg.Command.Retain, []byte(payload)); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "publish command: %v", err)
+	}
+	if o.state != nil {
+		return &traits.OnOff{State: request.GetOnOff().GetState()}, nil
+	}
+	return o.model.UpdateOnOff(&traits.OnOff{State: request.GetOnOff().GetState()})
+}
@@ -0,0 +1,44 @@
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/mqtt/config"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/meter"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+)
+
+var ErrTraitNotSupported = errors.New("trait not supported")
+
+// newTrait returns the trait described by cfg, reading values from messages received by dev.
+func newTrait(dev *device, cfg config.RawTrait) (node.SelfAnnouncer, error) {
+	switch cfg.Kind {
+	case trait.AirQualitySensor:
+		return newAirQualitySensor(dev, cfg)
+	case meter.TraitName:
+		return newMeter(dev, cfg)
+	case trait.OccupancySensor:
+		return newOccupancySensor(dev, cfg)
+	case trait.OnOff:
+		return newOnOff(dev, cfg)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTraitNotSupported, cfg.Kind)
+}
+
+// traitName returns the name the trait is announced with, which defaults to the device name.
+func traitName(dev *device, cfg config.RawTrait) string {
+	if cfg.Name != "" {
+		return cfg.Name
+	}
+	return dev.cfg.Name
+}
+
+// withMetadata adds the metadata of the trait config to features, if it has any.
+func withMetadata(cfg config.Trait, features ...node.Feature) []node.Feature {
+	if cfg.Metadata != nil {
+		features = append(features, node.HasMetadata(cfg.Metadata))
+	}
+	return features
+}
@@ -0,0 +1,212 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/mqtt/config"
+)
+
+// errNoValue is returned when a payload doesn't contain a value.
+// Devices often publish different values in different messages to the same topic, so this isn't a problem.
+var errNoValue = errors.New("no value")
+
+// decoder converts a message payload into a document that values are read from.
+type decoder func(payload []byte) (any, error)
+
+func newDecoder(p config.Payload) (decoder, error) {
+	switch p.Format {
+	case "", config.FormatJSON:
+		return decodeJSON, nil
+	case config.FormatProto:
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(p.ProtoMessage))
+		if err != nil {
+			return nil, fmt.Errorf("proto message %q: %w", p.ProtoMessage, err)
+		}
+		return func(payload []byte) (any, error) {
+			msg := mt.New().Interface()
+			if err := proto.Unmarshal(payload, msg); err != nil {
+				return nil, err
+			}
+			js, err := protojson.Marshal(msg)
+			if err != nil {
+				return nil, err
+			}
+			return decodeJSON(js)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown payload format %q", p.Format)
+	}
+}
+
+// decodeJSON decodes payload as JSON.
+// Payloads that aren't JSON, like a bare ON, are returned as a string.
+func decodeJSON(payload []byte) (any, error) {
+	var doc any
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return strings.TrimSpace(string(payload)), nil
+	}
+	return doc, nil
+}
+
+// value reads a trait value from documents.
+type value struct {
+	cfg   config.Value
+	topic string
+	path  []pathElem
+}
+
+// newValue returns a value for cfg, or nil if cfg is nil.
+func newValue(cfg *config.Value, defaultTopic string) (*value, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	path, err := parsePath(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("path %q: %w", cfg.Path, err)
+	}
+	v := &value{cfg: *cfg, topic: cfg.Topic, path: path}
+	if v.topic == "" {
+		v.topic = defaultTopic
+	}
+	if v.topic == "" {
+		return nil, errors.New("topic is required, on the value or device")
+	}
+	if v.cfg.Scale == 0 {
+		v.cfg.Scale = 1
+	}
+	return v, nil
+}
+
+// raw returns the value from doc, mapped using the enum if there is one.
+func (v *value) raw(doc any) (any, error) {
+	for _, e := range v.path {
+		var ok bool
+		if e.index >= 0 {
+			var arr []any
+			arr, ok = doc.([]any)
+			if ok && e.index < len(arr) {
+				doc = arr[e.index]
+			} else {
+				ok = false
+			}
+		} else {
+			var obj map[string]any
+			obj, ok = doc.(map[string]any)
+			if ok {
+				doc, ok = obj[e.key]
+			}
+		}
+		if !ok || doc == nil {
+			return nil, errNoValue
+		}
+	}
+	if v.cfg.Enum != nil {
+		s, ok := v.cfg.Enum[formatRaw(doc)]
+		if !ok {
+			return nil, fmt.Errorf("%v not in enum", doc)
+		}
+		return s, nil
+	}
+	return doc, nil
+}
+
+// number returns the scaled numeric value from doc.
+// Booleans are 0 or 1, and strings are parsed.
+func (v *value) number(doc any) (float64, error) {
+	raw, err := v.raw(doc)
+	if err != nil {
+		return 0, err
+	}
+	var n float64
+	switch r := raw.(type) {
+	case float64:
+		n = r
+	case bool:
+		if r {
+			n = 1
+		}
+	case string:
+		n, err = strconv.ParseFloat(strings.TrimSpace(r), 64)
+		if err != nil {
+			return 0, fmt.Errorf("not a number: %q", r)
+		}
+	default:
+		return 0, fmt.Errorf("not a number: %v", raw)
+	}
+	return n*v.cfg.Scale + v.cfg.Offset, nil
+}
+
+// string returns the value from doc formatted as a string.
+func (v *value) string(doc any) (string, error) {
+	raw, err := v.raw(doc)
+	if err != nil {
+		return "", err
+	}
+	return formatRaw(raw), nil
+}
+
+func formatRaw(raw any) string {
+	switch r := raw.(type) {
+	case string:
+		return r
+	case float64:
+		return strconv.FormatFloat(r, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(r)
+	default:
+		b, _ := json.Marshal(r)
+		return string(b)
+	}
+}
+
+// pathElem is either an object key or, when index is not negative, an array index.
+type pathElem struct {
+	key   string
+	index int
+}
+
+// parsePath parses paths like "a.b[0].c", an optional leading "$" refers to the whole document.
+func parsePath(path string) ([]pathElem, error) {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return nil, nil
+	}
+	var elems []pathElem
+	for _, part := range strings.Split(path, ".") {
+		key, rest, _ := strings.Cut(part, "[")
+		if key != "" {
+			elems = append(elems, pathElem{key: key, index: -1})
+		} else if rest == "" {
+			return nil, errors.New("empty key")
+		}
+		for rest != "" {
+			idx, after, ok := strings.Cut(rest, "]")
+			if !ok {
+				return nil, errors.New("missing ]")
+			}
+			i, err := strconv.Atoi(idx)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("bad index %q", idx)
+			}
+			elems = append(elems, pathElem{index: i})
+			if after == "" {
+				break
+			}
+			if !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("unexpected %q", after)
+			}
+			rest = after[1:]
+		}
+	}
+	return elems, nil
+}
@@ -0,0 +1,54 @@
+package mqtt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/mqtt/config"
+)
+
+func TestValue(t *testing.T) {
+	doc, _ := decodeJSON([]byte(`{"a": {"b": [10, {"c": "21.5"}]}, "on": true, "mode": 2}`))
+	tests := []struct {
+		name    string
+		cfg     config.Value
+		want    float64
+		wantErr error
+	}{
+		{name: "index", cfg: config.Value{Path: "a.b[0]"}, want: 10},
+		{name: "string number", cfg: config.Value{Path: "$.a.b[1].c"}, want: 21.5},
+		{name: "scale and offset", cfg: config.Value{Path: "a.b[0]", Scale: 0.5, Offset: 1}, want: 6},
+		{name: "bool", cfg: config.Value{Path: "on"}, want: 1},
+		{name: "enum", cfg: config.Value{Path: "mode", Enum: map[string]string{"2": "40"}}, want: 40},
+		{name: "missing", cfg: config.Value{Path: "a.x"}, wantErr: errNoValue},
+		{name: "out of range", cfg: config.Value{Path: "a.b[5]"}, wantErr: errNoValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := newValue(&tt.cfg, "topic")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := v.number(doc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("number() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("number() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// payloads that aren't JSON are strings
+	plain, _ := decodeJSON([]byte("ON\n"))
+	v, _ := newValue(&config.Value{}, "topic")
+	if s, _ := v.string(plain); s != "ON" {
+		t.Errorf("string() = %q, want ON", s)
+	}
+
+	for _, path := range []string{"a..b", "a[x]", "a[0"} {
+		if _, err := parsePath(path); err == nil {
+			t.Errorf("parsePath(%q) want error", path)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"net/http"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -71,12 +73,13 @@ func (d *Driver) applyConfig(ctx context.Context, cfg config.Root) error {
 		tlsConfig := proxyTLSConfig(d.clientTLSConfig, n)
 		dialOpts := []grpc.DialOption{
 			grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()), // propagate trace context to the node
 		}
 		if n.OAuth2 != nil {
 			httpClient := &http.Client{
-				Transport: &http.Transport{
+				Transport: otelhttp.NewTransport(&http.Transport{
 					TLSClientConfig: tlsConfig,
-				},
+				}),
 			}
 			creds, err := newOAuth2Credentials(*n.OAuth2, httpClient)
 			if err != nil {
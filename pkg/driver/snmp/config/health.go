@@ -0,0 +1,82 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// HealthCheck reports a fault against the device when Point has an abnormal value.
+// Exactly one of NormalValues or FaultValues should be set.
+type HealthCheck struct {
+	// ID identifies the check within the device, defaults to the point name.
+	ID    string `json:"id,omitempty"`
+	Point string `json:"point,omitempty"`
+	// NormalValues are the values of Point when there is no fault, all other values are faults.
+	NormalValues []float64 `json:"normalValues,omitempty"`
+	// FaultValues are the values of Point that are faults, all other values are normal.
+	FaultValues []float64 `json:"faultValues,omitempty"`
+
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+	// FaultSummary describes the fault, like "UPS on battery", defaults to the point value.
+	FaultSummary string `json:"faultSummary,omitempty"`
+	// OccupantImpact is one of the gen.HealthCheck_OccupantImpact names, like LIFE.
+	OccupantImpact string `json:"occupantImpact,omitempty"`
+	// EquipmentImpact is one of the gen.HealthCheck_EquipmentImpact names, like FUNCTION.
+	EquipmentImpact string `json:"equipmentImpact,omitempty"`
+
+	// Affects are the names of devices that rely on this one, like those connected to a switch port.
+	// While the check has a fault, the affected devices have a health check whose reliability is caused by this device.
+	Affects []string `json:"affects,omitempty"`
+}
+
+// IDOrDefault returns the ID of the check.
+func (h HealthCheck) IDOrDefault() string {
+	if h.ID != "" {
+		return h.ID
+	}
+	return h.Point
+}
+
+// IsFault returns whether v is a fault.
+func (h HealthCheck) IsFault(v float64) bool {
+	if len(h.NormalValues) > 0 {
+		return !slices.Contains(h.NormalValues, v)
+	}
+	return slices.Contains(h.FaultValues, v)
+}
+
+// Impacts returns the parsed OccupantImpact and EquipmentImpact.
+func (h HealthCheck) Impacts() (gen.HealthCheck_OccupantImpact, gen.HealthCheck_EquipmentImpact, error) {
+	var errs []error
+	occupant, equipment := int32(0), int32(0)
+	if h.OccupantImpact != "" {
+		var ok bool
+		occupant, ok = gen.HealthCheck_OccupantImpact_value[h.OccupantImpact]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown occupant impact %q", h.OccupantImpact))
+		}
+	}
+	if h.EquipmentImpact != "" {
+		var ok bool
+		equipment, ok = gen.HealthCheck_EquipmentImpact_value[h.EquipmentImpact]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown equipment impact %q", h.EquipmentImpact))
+		}
+	}
+	return gen.HealthCheck_OccupantImpact(occupant), gen.HealthCheck_EquipmentImpact(equipment), errors.Join(errs...)
+}
+
+func (h HealthCheck) validate() error {
+	var errs []error
+	if (len(h.NormalValues) > 0) == (len(h.FaultValues) > 0) {
+		errs = append(errs, errors.New("exactly one of normalValues or faultValues is required"))
+	}
+	if _, _, err := h.Impacts(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Point is a value read from a single OID of a device, no MIBs are needed.
+//
+// Numeric values are scaled:
+//
+//	value = raw * Scale + Offset
+type Point struct {
+	// Name identifies the point within the device, traits and health checks refer to points by name.
+	Name string `json:"name,omitempty"`
+	// OID is the numeric object identifier of the value, like 1.3.6.1.2.1.33.1.4.1.0.
+	// Points without an OID aren't polled, they are only updated by traps.
+	OID string `json:"oid,omitempty"`
+	// Scale multiplies raw values, defaults to 1.
+	Scale float64 `json:"scale,omitempty"`
+	// Offset is added to raw values after scaling.
+	Offset float64 `json:"offset,omitempty"`
+	// Enum maps string values to numbers.
+	// Strings not in Enum are parsed as numbers.
+	Enum map[string]float64 `json:"enum,omitempty"`
+}
+
+func (p *Point) defaults() {
+	p.OID = NormalizeOID(p.OID)
+	if p.Scale == 0 {
+		p.Scale = 1
+	}
+}
+
+func (p Point) validate() error {
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	if p.OID == "" {
+		return nil
+	}
+	return validateOID(p.OID)
+}
+
+// Scaled returns the point value for the raw value v.
+func (p Point) Scaled(v float64) float64 {
+	return v*p.Scale + p.Offset
+}
+
+// NormalizeOID removes the leading dot some tools include in OIDs.
+func NormalizeOID(oid string) string {
+	return strings.TrimPrefix(strings.TrimSpace(oid), ".")
+}
+
+func validateOID(oid string) error {
+	if oid == "" {
+		return errors.New("oid is required")
+	}
+	for _, part := range strings.Split(oid, ".") {
+		if _, err := strconv.ParseUint(part, 10, 32); err != nil {
+			return fmt.Errorf("invalid oid %q", oid)
+		}
+	}
+	return nil
+}
+
+// TrapRule sets Point to Value when a trap identified by TrapOID is received from the device.
+// Variables in any trap with the OID of a point update that point, rules are only needed for traps that don't include a value,
+// like upsTrapOnBattery.
+type TrapRule struct {
+	// TrapOID is the snmpTrapOID.0 of v2c and v3 traps.
+	// For v1 traps this is the enterprise OID followed by .0 and the specific trap number,
+	// or 1.3.6.1.6.3.1.1.5 followed by the generic trap number plus 1, as described by RFC 3584.
+	TrapOID string  `json:"trapOid,omitempty"`
+	Point   string  `json:"point,omitempty"`
+	Value   float64 `json:"value,omitempty"`
+}
@@ -0,0 +1,345 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	DefaultPort       = 161
+	DefaultCommunity  = "public"
+	DefaultPollPeriod = 30 * time.Second
+	DefaultTimeout    = 5 * time.Second
+	DefaultRetries    = 1
+	DefaultTrapListen = ":162"
+)
+
+// Root is the configuration of the snmp driver.
+type Root struct {
+	driver.BaseConfig
+
+	// Metadata is applied to the driver name.
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+	Timing   Timing           `json:"timing,omitempty"`
+	// Traps configures receiving traps, no traps are received if absent.
+	Traps   *Traps   `json:"traps,omitempty"`
+	Devices []Device `json:"devices,omitempty"`
+}
+
+type Timing struct {
+	// Timeout is how long to wait for a response to each request, defaults to 5s.
+	Timeout *jsontypes.Duration `json:"timeout,omitempty"`
+	// Retries is how many times a request is sent again after timing out, defaults to 1.
+	Retries *int `json:"retries,omitempty"`
+	// PollPeriod is how often devices are polled, unless they have their own, defaults to 30s.
+	PollPeriod *jsontypes.Duration `json:"pollPeriod,omitempty"`
+}
+
+// Traps configures the trap listener.
+// Traps are matched to devices by the address they are sent from.
+type Traps struct {
+	// Listen is the udp host:port to receive traps on, defaults to :162.
+	Listen string `json:"listen,omitempty"`
+	// Community, if set, is the community v1 and v2c traps must have, others are ignored.
+	// Like Device.Community it can be given directly, as a file path, or as a secret reference.
+	Community jsontypes.String `json:"community,omitempty"`
+	// V3 configures the user v3 traps are sent by.
+	V3 *V3 `json:"v3,omitempty"`
+}
+
+// Device is an SNMP agent, like a UPS, PDU, or network switch.
+type Device struct {
+	// Name is the Smart Core name of the device, used for its traits unless they have their own name.
+	Name string `json:"name,omitempty"`
+	// Metadata is announced for Name.
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+	// Host is the hostname or ip address of the agent.
+	// Traps sent from this address are handled by the device.
+	Host string `json:"host,omitempty"`
+	// Port is the udp port of the agent, defaults to 161.
+	Port uint16 `json:"port,omitempty"`
+	// Version is the SNMP version, one of "1", "2c", or "3", defaults to "2c".
+	Version string `json:"version,omitempty"`
+	// Community is used for v1 and v2c requests, defaults to public.
+	// It can be given directly, as an absolute or relative file path, or as a reference to a secret like {"$secret": "snmp/ups"}.
+	Community jsontypes.String `json:"community,omitempty"`
+	// V3 configures the user, required for v3.
+	V3 *V3 `json:"v3,omitempty"`
+	// PollPeriod is how often points are read, defaults to Timing.PollPeriod.
+	PollPeriod *jsontypes.Duration `json:"pollPeriod,omitempty"`
+
+	// Points are the values read from the device.
+	Points []Point `json:"points,omitempty"`
+	// Traps update points when traps are received from the device.
+	Traps []TrapRule `json:"traps,omitempty"`
+	// Traits map points to Smart Core traits.
+	Traits []RawTrait `json:"traits,omitempty"`
+	// HealthChecks report faults when points don't have their normal value.
+	HealthChecks []HealthCheck `json:"healthChecks,omitempty"`
+}
+
+// V3 configures an SNMPv3 user.
+type V3 struct {
+	User string `json:"user,omitempty"`
+	// AuthProtocol is one of MD5, SHA, SHA224, SHA256, SHA384, or SHA512, no authentication is used if absent.
+	AuthProtocol string `json:"authProtocol,omitempty"`
+	// AuthPassphrase and PrivPassphrase, like Device.Community, can be given directly, as a file path, or as a secret reference.
+	AuthPassphrase jsontypes.String `json:"authPassphrase,omitempty"`
+	// PrivProtocol is one of DES, AES, AES192, AES256, AES192C, or AES256C, messages aren't encrypted if absent.
+	// Privacy requires authentication.
+	PrivProtocol   string           `json:"privProtocol,omitempty"`
+	PrivPassphrase jsontypes.String `json:"privPassphrase,omitempty"`
+	// ContextName is sent in the scoped PDU of requests.
+	ContextName string `json:"contextName,omitempty"`
+}
+
+var authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"MD5":    gosnmp.MD5,
+	"SHA":    gosnmp.SHA,
+	"SHA224": gosnmp.SHA224,
+	"SHA256": gosnmp.SHA256,
+	"SHA384": gosnmp.SHA384,
+	"SHA512": gosnmp.SHA512,
+}
+
+var privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"DES":     gosnmp.DES,
+	"AES":     gosnmp.AES,
+	"AES192":  gosnmp.AES192,
+	"AES256":  gosnmp.AES256,
+	"AES192C": gosnmp.AES192C,
+	"AES256C": gosnmp.AES256C,
+}
+
+func (v V3) validate() error {
+	var errs []error
+	if v.User == "" {
+		errs = append(errs, errors.New("user is required"))
+	}
+	if _, ok := authProtocols[strings.ToUpper(v.AuthProtocol)]; v.AuthProtocol != "" && !ok {
+		errs = append(errs, fmt.Errorf("unknown auth protocol %q", v.AuthProtocol))
+	}
+	if _, ok := privProtocols[strings.ToUpper(v.PrivProtocol)]; v.PrivProtocol != "" && !ok {
+		errs = append(errs, fmt.Errorf("unknown priv protocol %q", v.PrivProtocol))
+	}
+	if v.PrivProtocol != "" && v.AuthProtocol == "" {
+		errs = append(errs, errors.New("privProtocol requires authProtocol"))
+	}
+	return errors.Join(errs...)
+}
+
+// Apply sets the v3 security parameters of c.
+func (v V3) Apply(c *gosnmp.GoSNMP) error {
+	auth, err := readSecret(v.AuthPassphrase)
+	if err != nil {
+		return fmt.Errorf("auth passphrase: %w", err)
+	}
+	priv, err := readSecret(v.PrivPassphrase)
+	if err != nil {
+		return fmt.Errorf("priv passphrase: %w", err)
+	}
+	params := &gosnmp.UsmSecurityParameters{
+		UserName:               v.User,
+		AuthenticationProtocol: gosnmp.NoAuth,
+		PrivacyProtocol:        gosnmp.NoPriv,
+	}
+	c.MsgFlags = gosnmp.NoAuthNoPriv
+	if v.AuthProtocol != "" {
+		c.MsgFlags = gosnmp.AuthNoPriv
+		params.AuthenticationProtocol = authProtocols[strings.ToUpper(v.AuthProtocol)]
+		params.AuthenticationPassphrase = auth
+	}
+	if v.PrivProtocol != "" {
+		c.MsgFlags = gosnmp.AuthPriv
+		params.PrivacyProtocol = privProtocols[strings.ToUpper(v.PrivProtocol)]
+		params.PrivacyPassphrase = priv
+	}
+	c.Version = gosnmp.Version3
+	c.SecurityModel = gosnmp.UserSecurityModel
+	c.SecurityParameters = params
+	c.ContextName = v.ContextName
+	return nil
+}
+
+// ReadCommunity returns the community traps must have, or "" if any community is accepted.
+func (t Traps) ReadCommunity() (string, error) {
+	return readSecret(t.Community)
+}
+
+// readSecret reads s, trimming the trailing new line files often have.
+func readSecret(s jsontypes.String) (string, error) {
+	v, err := s.Read()
+	return strings.TrimSpace(v), err
+}
+
+// Client returns a client for the device, which still needs to connect.
+func (d Device) Client(t Timing) (*gosnmp.GoSNMP, error) {
+	c := &gosnmp.GoSNMP{
+		Target:    d.Host,
+		Port:      d.Port,
+		Transport: "udp",
+		Timeout:   t.Timeout.Duration,
+		Retries:   *t.Retries,
+		MaxOids:   gosnmp.MaxOids,
+	}
+	switch d.Version {
+	case "1", "2c":
+		c.Version = gosnmp.Version2c
+		if d.Version == "1" {
+			c.Version = gosnmp.Version1
+		}
+		community, err := readSecret(d.Community)
+		if err != nil {
+			return nil, fmt.Errorf("community: %w", err)
+		}
+		if community == "" {
+			community = DefaultCommunity
+		}
+		c.Community = community
+	case "3":
+		if err := d.V3.Apply(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Address returns the host:port of the agent.
+func (d Device) Address() string {
+	return net.JoinHostPort(d.Host, strconv.Itoa(int(d.Port)))
+}
+
+// ReadBytes decodes and validates data, filling in defaults.
+func ReadBytes(data []byte) (cfg Root, err error) {
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.Timing.Timeout == nil {
+		cfg.Timing.Timeout = &jsontypes.Duration{Duration: DefaultTimeout}
+	}
+	if cfg.Timing.Retries == nil {
+		retries := DefaultRetries
+		cfg.Timing.Retries = &retries
+	}
+	if cfg.Timing.PollPeriod == nil {
+		cfg.Timing.PollPeriod = &jsontypes.Duration{Duration: DefaultPollPeriod}
+	}
+	if cfg.Traps != nil && cfg.Traps.Listen == "" {
+		cfg.Traps.Listen = DefaultTrapListen
+	}
+	for i := range cfg.Devices {
+		d := &cfg.Devices[i]
+		if d.Port == 0 {
+			d.Port = DefaultPort
+		}
+		if d.Version == "" {
+			d.Version = "2c"
+		}
+		if d.PollPeriod == nil {
+			d.PollPeriod = cfg.Timing.PollPeriod
+		}
+		for j := range d.Points {
+			d.Points[j].defaults()
+		}
+		for j := range d.Traps {
+			d.Traps[j].TrapOID = NormalizeOID(d.Traps[j].TrapOID)
+		}
+	}
+	return cfg, cfg.validate()
+}
+
+func (r Root) validate() error {
+	var errs []error
+	if r.Traps != nil && r.Traps.V3 != nil {
+		if err := r.Traps.V3.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("traps.v3: %w", err))
+		}
+	}
+	names := make(map[string]bool)
+	for _, d := range r.Devices {
+		if d.Name == "" {
+			errs = append(errs, errors.New("device name is required"))
+			continue
+		}
+		if names[d.Name] {
+			errs = append(errs, fmt.Errorf("device %q: name is used more than once", d.Name))
+		}
+		names[d.Name] = true
+		if err := d.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("device %q: %w", d.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d Device) validate() error {
+	var errs []error
+	if d.Host == "" {
+		errs = append(errs, errors.New("host is required"))
+	}
+	switch d.Version {
+	case "1", "2c":
+	case "3":
+		if d.V3 == nil {
+			errs = append(errs, errors.New("v3 is required for version 3"))
+		} else if err := d.V3.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("v3: %w", err))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown version %q", d.Version))
+	}
+	points := make(map[string]bool)
+	for _, p := range d.Points {
+		if points[p.Name] {
+			errs = append(errs, fmt.Errorf("point %q: name is used more than once", p.Name))
+		}
+		points[p.Name] = true
+		if err := p.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("point %q: %w", p.Name, err))
+		}
+	}
+	for i, t := range d.Traps {
+		if err := validateOID(t.TrapOID); err != nil {
+			errs = append(errs, fmt.Errorf("trap %d: trapOid: %w", i, err))
+		}
+		if !points[t.Point] {
+			errs = append(errs, fmt.Errorf("trap %d: unknown point %q", i, t.Point))
+		}
+	}
+	ids := make(map[string]bool)
+	for i, h := range d.HealthChecks {
+		if ids[h.IDOrDefault()] {
+			errs = append(errs, fmt.Errorf("health check %d: id %q is used more than once", i, h.IDOrDefault()))
+		}
+		ids[h.IDOrDefault()] = true
+		if !points[h.Point] {
+			errs = append(errs, fmt.Errorf("health check %d: unknown point %q", i, h.Point))
+		}
+		if err := h.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("health check %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Point returns the point with the given name.
+func (d Device) Point(name string) (Point, bool) {
+	for _, p := range d.Points {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Point{}, false
+}
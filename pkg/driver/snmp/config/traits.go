@@ -0,0 +1,89 @@
+package config
+
+import (
+	"encoding/json"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+)
+
+type Trait struct {
+	// Name of the device implementing the trait, defaults to the name of the SNMP device.
+	Name     string           `json:"name,omitempty"`
+	Kind     trait.Name       `json:"kind,omitempty"`
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+}
+
+type RawTrait struct {
+	Trait
+	Raw json.RawMessage `json:"-"`
+}
+
+func (c *RawTrait) MarshalJSON() ([]byte, error) {
+	return c.Raw, nil
+}
+
+func (c *RawTrait) UnmarshalJSON(buf []byte) error {
+	c.Raw = buf
+	return json.Unmarshal(buf, &c.Trait)
+}
+
+// The trait configs below refer to points of the device by name.
+// Points that aren't configured leave the corresponding trait fields unset.
+// Traits are read only, SNMP sets aren't supported.
+
+// ElectricConfig is configured by a Device that wants to implement the Electric trait, like a PDU or UPS output.
+// Points should be scaled to volts, amps, watts, VA, and VAR.
+type ElectricConfig struct {
+	Trait
+	Voltage       string `json:"voltage,omitempty"`
+	Current       string `json:"current,omitempty"`
+	RealPower     string `json:"realPower,omitempty"`
+	ApparentPower string `json:"apparentPower,omitempty"`
+	ReactivePower string `json:"reactivePower,omitempty"`
+	PowerFactor   string `json:"powerFactor,omitempty"`
+}
+
+// EnergyStorageConfig is configured by a Device that wants to implement the EnergyStorage trait, like a UPS battery.
+type EnergyStorageConfig struct {
+	Trait
+	// Percentage is the point holding the charge remaining, scaled to 0-100.
+	Percentage string `json:"percentage,omitempty"`
+	EnergyKwh  string `json:"energyKwh,omitempty"`
+	Voltage    string `json:"voltage,omitempty"`
+	// MinutesRemaining is the point holding the estimated run time while discharging.
+	MinutesRemaining string `json:"minutesRemaining,omitempty"`
+	// Flow is the point that says whether the battery is charging or discharging, like upsOutputSource.
+	// Values in neither ChargeValues nor DischargeValues are idle.
+	Flow            string    `json:"flow,omitempty"`
+	ChargeValues    []float64 `json:"chargeValues,omitempty"`
+	DischargeValues []float64 `json:"dischargeValues,omitempty"`
+}
+
+// AirTemperatureConfig is configured by a Device that wants to implement the AirTemperature trait, like a CRAC unit.
+type AirTemperatureConfig struct {
+	Trait
+	// AmbientTemperature should be scaled to degrees Celsius.
+	AmbientTemperature string `json:"ambientTemperature,omitempty"`
+	// AmbientHumidity should be scaled to a percentage.
+	AmbientHumidity string `json:"ambientHumidity,omitempty"`
+}
+
+// StatusConfig is configured by a Device that wants to report problems using the Status trait.
+type StatusConfig struct {
+	Trait
+	Problems []ProblemConfig `json:"problems,omitempty"`
+}
+
+// ProblemConfig reports a problem when Point doesn't equal NormalValue.
+type ProblemConfig struct {
+	// Name of the problem, defaults to the point name.
+	Name  string `json:"name,omitempty"`
+	Point string `json:"point,omitempty"`
+	// NormalValue is the value of Point when there is no problem, defaults to 0.
+	NormalValue float64 `json:"normalValue,omitempty"`
+	// Level is the status level of the problem, one of the gen.StatusLog_Level names.
+	// Defaults to NOTICE.
+	Level       string `json:"level,omitempty"`
+	Description string `json:"description,omitempty"`
+}
@@ -0,0 +1,298 @@
+package snmp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/snmp/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/statuspb"
+)
+
+// device polls the points of an SNMP agent and handles its traps, notifying listeners and health checks of new values.
+type device struct {
+	cfg        config.Device
+	client     *gosnmp.GoSNMP
+	conn       *timeoutConn // the connection of client, used while polling
+	pollPeriod time.Duration
+	statuses   *statuspb.Map
+	logger     *zap.Logger
+	byOID      map[string]config.Point
+
+	mu        sync.Mutex
+	values    map[string]float64
+	listeners []func(values map[string]float64)
+
+	// notifyMu makes sure listeners and checks are updated one at a time, and in the order values change.
+	notifyMu sync.Mutex
+	checks   []*healthCheck
+	closed   bool
+}
+
+func newDevice(cfg config.Device, client *gosnmp.GoSNMP, pollPeriod time.Duration, statuses *statuspb.Map, logger *zap.Logger) *device {
+	byOID := make(map[string]config.Point)
+	for _, p := range cfg.Points {
+		if p.OID != "" {
+			byOID[p.OID] = p
+		}
+	}
+	return &device{
+		cfg:        cfg,
+		client:     client,
+		pollPeriod: pollPeriod,
+		statuses:   statuses,
+		logger:     logger.With(zap.String("device", cfg.Name)),
+		byOID:      byOID,
+		values:     make(map[string]float64),
+	}
+}
+
+// onUpdate registers f to be called with the latest values of all points whenever any are read or trapped.
+// It should be called before run.
+func (d *device) onUpdate(f func(values map[string]float64)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.listeners = append(d.listeners, f)
+}
+
+// addHealthCheck adds c to be updated with the value of its point.
+// It should be called before run.
+func (d *device) addHealthCheck(c *healthCheck) {
+	d.notifyMu.Lock()
+	defer d.notifyMu.Unlock()
+	d.checks = append(d.checks, c)
+}
+
+// run polls the device until ctx is done.
+func (d *device) run(ctx context.Context) {
+	if len(d.byOID) == 0 {
+		return // only updated by traps
+	}
+	d.client.Context = ctx
+	ticker := time.NewTicker(d.pollPeriod)
+	defer ticker.Stop()
+	connected := false
+	defer func() {
+		if connected {
+			_ = d.client.Conn.Close()
+		}
+	}()
+	for {
+		if !connected {
+			if err := d.connect(); err != nil {
+				d.updatePollStatus(1, 1, 0, fmt.Errorf("connect: %w", err))
+			} else {
+				connected = true
+			}
+		}
+		if connected {
+			d.poll(ctx)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *device) connect() error {
+	if err := d.client.Connect(); err != nil {
+		return err
+	}
+	d.conn = &timeoutConn{Conn: d.client.Conn}
+	d.client.Conn = d.conn
+	return nil
+}
+
+// poll gets the value of every point that has an OID.
+func (d *device) poll(ctx context.Context) {
+	var oids []string
+	for _, p := range d.cfg.Points {
+		if p.OID != "" {
+			oids = append(oids, p.OID)
+		}
+	}
+
+	values := make(map[string]float64)
+	errs := make(map[string]error)
+	var requests, failed int
+	var firstErr error
+	for chunk := range chunks(oids, d.client.MaxOids) {
+		requests++
+		vars, err := d.get(chunk)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			d.logger.Debug("failed to get points", zap.Strings("oids", chunk), zap.Error(err))
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			for _, oid := range chunk {
+				errs[d.byOID[oid].Name] = err
+			}
+			continue
+		}
+		for _, v := range vars {
+			p, ok := d.byOID[config.NormalizeOID(v.Name)]
+			if !ok {
+				continue
+			}
+			val, err := pointValue(p, v)
+			if err != nil {
+				d.logger.Debug("bad point value", zap.String("point", p.Name), zap.Error(err))
+				errs[p.Name] = err
+				continue
+			}
+			values[p.Name] = val
+		}
+	}
+	d.update(ctx, values, errs)
+	var pointErr error
+	for _, err := range errs {
+		pointErr = err
+		break
+	}
+	if firstErr == nil {
+		firstErr = pointErr
+	}
+	d.updatePollStatus(requests, failed, len(errs), firstErr)
+}
+
+func (d *device) get(oids []string) ([]gosnmp.SnmpPDU, error) {
+	pkt, err := d.client.Get(oids)
+	if err != nil {
+		if d.conn != nil && d.conn.timedOut {
+			return nil, fmt.Errorf("%w: %w", os.ErrDeadlineExceeded, err)
+		}
+		return nil, err
+	}
+	if pkt.Error != gosnmp.NoError {
+		return nil, fmt.Errorf("agent error %s for variable %d", pkt.Error, pkt.ErrorIndex)
+	}
+	return pkt.Variables, nil
+}
+
+// timeoutConn records whether the last read timed out.
+// gosnmp replaces read timeouts with its own error, which can't be checked using errors.Is.
+type timeoutConn struct {
+	net.Conn
+	timedOut bool
+}
+
+func (c *timeoutConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.timedOut = errors.Is(err, os.ErrDeadlineExceeded)
+	return n, err
+}
+
+func chunks(oids []string, size int) func(yield func([]string) bool) {
+	return func(yield func([]string) bool) {
+		for len(oids) > 0 {
+			n := min(size, len(oids))
+			if !yield(oids[:n]) {
+				return
+			}
+			oids = oids[n:]
+		}
+	}
+}
+
+func (d *device) updatePollStatus(requests, failedRequests, failedPoints int, err error) {
+	problem := &gen.StatusLog_Problem{
+		Name:        d.cfg.Name + ":poll",
+		Level:       gen.StatusLog_NOMINAL,
+		Description: "poll nominal",
+		RecordTime:  timestamppb.Now(),
+	}
+	switch {
+	case failedRequests == requests:
+		problem.Level = gen.StatusLog_NON_FUNCTIONAL
+		problem.Description = fmt.Sprintf("agent not responding, %v", err)
+	case failedPoints > 0:
+		problem.Level = gen.StatusLog_REDUCED_FUNCTION
+		problem.Description = fmt.Sprintf("%d of %d points failed, %v", failedPoints, len(d.byOID), err)
+	}
+	d.statuses.UpdateProblem(d.cfg.Name, problem)
+}
+
+// handleTrap updates points using the variables of pkt, and any trap rules matching its trap OID.
+func (d *device) handleTrap(ctx context.Context, pkt *gosnmp.SnmpPacket) {
+	values := make(map[string]float64)
+	oid := trapOID(pkt)
+	for _, r := range d.cfg.Traps {
+		if r.TrapOID == oid {
+			values[r.Point] = r.Value
+		}
+	}
+	for _, v := range pkt.Variables {
+		p, ok := d.byOID[config.NormalizeOID(v.Name)]
+		if !ok {
+			continue
+		}
+		val, err := pointValue(p, v)
+		if err != nil {
+			d.logger.Debug("bad trap value", zap.String("point", p.Name), zap.Error(err))
+			continue
+		}
+		values[p.Name] = val
+	}
+	if len(values) == 0 {
+		d.logger.Debug("ignoring trap", zap.String("trapOid", oid))
+		return
+	}
+	d.update(ctx, values, nil)
+}
+
+// update stores values, notifying listeners and health checks.
+// Health checks of points in errs are marked as unreliable.
+func (d *device) update(ctx context.Context, values map[string]float64, errs map[string]error) {
+	d.notifyMu.Lock()
+	defer d.notifyMu.Unlock()
+	if d.closed {
+		return
+	}
+	for _, c := range d.checks {
+		if v, ok := values[c.cfg.Point]; ok {
+			c.update(ctx, v)
+		} else if err, ok := errs[c.cfg.Point]; ok {
+			c.updateErr(ctx, err)
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	d.mu.Lock()
+	maps.Copy(d.values, values)
+	snapshot := maps.Clone(d.values)
+	listeners := d.listeners
+	d.mu.Unlock()
+	for _, l := range listeners {
+		l(snapshot)
+	}
+}
+
+// close disposes of the health checks of the device, it's values are no longer updated.
+func (d *device) close() {
+	d.notifyMu.Lock()
+	defer d.notifyMu.Unlock()
+	if d.closed {
+		return
+	}
+	d.closed = true
+	for _, c := range d.checks {
+		c.dispose()
+	}
+}
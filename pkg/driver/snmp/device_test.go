@@ -0,0 +1,45 @@
+package snmp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/snmp/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+func TestDevice_getTimeout(t *testing.T) {
+	// an agent that never responds
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	addr := conn.LocalAddr().(*net.UDPAddr)
+
+	cfg := config.Device{Name: "ups", Host: addr.IP.String(), Port: uint16(addr.Port), Version: "2c"}
+	retries := 0
+	client, err := cfg.Client(config.Timing{Timeout: &jsontypes.Duration{Duration: 50 * time.Millisecond}, Retries: &retries})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Context = context.Background()
+	d := newDevice(cfg, client, time.Second, nil, zap.NewNop())
+	if err := d.connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Conn.Close() })
+
+	_, err = d.get([]string{".1.3.6.1.2.1.1.3.0"})
+	if err == nil {
+		t.Fatal("get() expected an error")
+	}
+	if got := reliabilityFromErr(err).GetState(); got != gen.HealthCheck_Reliability_NO_RESPONSE {
+		t.Errorf("reliabilityFromErr(%v) = %v, want %v", err, got, gen.HealthCheck_Reliability_NO_RESPONSE)
+	}
+}
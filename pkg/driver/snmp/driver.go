@@ -0,0 +1,207 @@
+package snmp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/gosnmp/gosnmp"
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/driver/snmp/config"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/healthpb"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/statuspb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+)
+
+const DriverName = "snmp"
+
+var Factory driver.Factory = factory{}
+
+type factory struct{}
+
+func (f factory) New(services driver.Services) service.Lifecycle {
+	d := &Driver{
+		announcer: node.NewReplaceAnnouncer(services.Node),
+		health:    services.Health,
+		logger:    services.Logger.Named(DriverName),
+	}
+	d.Service = service.New(
+		service.MonoApply(d.applyConfig),
+		service.WithParser(config.ReadBytes),
+	)
+	return d
+}
+
+// Driver polls SNMP agents and receives their traps, exposing their values as traits and health checks.
+type Driver struct {
+	*service.Service[config.Root]
+	logger    *zap.Logger
+	announcer *node.ReplaceAnnouncer
+	health    *healthpb.Checks
+
+	mu      sync.Mutex
+	running *running
+}
+
+// running is what an applied config has started.
+type running struct {
+	devices []*device
+	traps   *gosnmp.TrapListener
+}
+
+// stop closes the trap listener and devices, so a new config can listen on the same port and create the same health checks.
+func (r *running) stop() {
+	if r.traps != nil {
+		r.traps.Close()
+	}
+	for _, dev := range r.devices {
+		dev.close()
+	}
+}
+
+func (d *Driver) applyConfig(ctx context.Context, cfg config.Root) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running != nil {
+		d.running.stop()
+		d.running = nil
+	}
+
+	a := d.announcer.Replace(ctx)
+	statuses := statuspb.NewMap(a)
+
+	if cfg.Metadata != nil {
+		a.Announce(cfg.Name, node.HasMetadata(cfg.Metadata))
+	}
+
+	r := &running{}
+	var errs []error
+	for _, devCfg := range cfg.Devices {
+		client, err := devCfg.Client(cfg.Timing)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("device %q: %w", devCfg.Name, err))
+			continue
+		}
+		dev := newDevice(devCfg, client, devCfg.PollPeriod.Duration, statuses, d.logger)
+		if devCfg.Metadata != nil {
+			a.Announce(devCfg.Name, node.HasMetadata(devCfg.Metadata))
+		}
+		for _, traitCfg := range devCfg.Traits {
+			t, err := newTrait(dev, traitCfg)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("device %q trait %s: %w", devCfg.Name, traitCfg.Kind, err))
+				continue
+			}
+			t.AnnounceSelf(a)
+			dev.onUpdate(t.update)
+		}
+		for _, checkCfg := range devCfg.HealthChecks {
+			if d.health == nil {
+				errs = append(errs, fmt.Errorf("device %q health check %s: health checks not available", devCfg.Name, checkCfg.IDOrDefault()))
+				continue
+			}
+			c, err := newHealthCheck(d.health, devCfg.Name, checkCfg)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("device %q health check %s: %w", devCfg.Name, checkCfg.IDOrDefault(), err))
+				continue
+			}
+			dev.addHealthCheck(c)
+		}
+		r.devices = append(r.devices, dev)
+	}
+	if err := errors.Join(errs...); err != nil {
+		// devices still run with the traits and checks that are configured correctly
+		d.logger.Error("failed to set up some devices", zap.Error(err))
+	}
+
+	if cfg.Traps != nil {
+		traps, err := d.listenForTraps(ctx, *cfg.Traps, r.devices)
+		if err != nil {
+			r.stop()
+			return fmt.Errorf("traps: %w", err)
+		}
+		r.traps = traps
+	}
+
+	for _, dev := range r.devices {
+		go dev.run(ctx)
+	}
+	d.running = r
+	go func() {
+		<-ctx.Done()
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.running == r {
+			r.stop()
+			d.running = nil
+		}
+	}()
+	return nil
+}
+
+// listenForTraps starts a trap listener, passing traps to the devices they were sent from.
+func (d *Driver) listenForTraps(ctx context.Context, cfg config.Traps, devices []*device) (*gosnmp.TrapListener, error) {
+	byIP := make(map[string][]*device)
+	for _, dev := range devices {
+		ips, err := net.DefaultResolver.LookupHost(ctx, dev.cfg.Host)
+		if err != nil {
+			// we'll still poll the device, but can't tell which traps it sent
+			d.logger.Warn("failed to resolve device host, traps from it will be ignored",
+				zap.String("device", dev.cfg.Name), zap.Error(err))
+			continue
+		}
+		for _, ip := range ips {
+			byIP[ip] = append(byIP[ip], dev)
+		}
+	}
+
+	params := &gosnmp.GoSNMP{
+		Version: gosnmp.Version2c,
+		Timeout: gosnmp.Default.Timeout,
+		MaxOids: gosnmp.MaxOids,
+	}
+	if cfg.V3 != nil {
+		if err := cfg.V3.Apply(params); err != nil {
+			return nil, err
+		}
+	}
+	community, err := cfg.ReadCommunity()
+	if err != nil {
+		return nil, fmt.Errorf("community: %w", err)
+	}
+	tl := gosnmp.NewTrapListener()
+	tl.Params = params
+	tl.OnNewTrap = func(pkt *gosnmp.SnmpPacket, addr *net.UDPAddr) {
+		if pkt.Version != gosnmp.Version3 && community != "" && pkt.Community != community {
+			d.logger.Debug("ignoring trap with unknown community", zap.Stringer("from", addr))
+			return
+		}
+		devs := byIP[addr.IP.String()]
+		if len(devs) == 0 {
+			d.logger.Debug("ignoring trap from unknown device", zap.Stringer("from", addr), zap.String("trapOid", trapOID(pkt)))
+			return
+		}
+		for _, dev := range devs {
+			dev.handleTrap(ctx, pkt)
+		}
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- tl.Listen(cfg.Listen)
+	}()
+	select {
+	case <-tl.Listening():
+		return tl, nil
+	case err := <-errCh:
+		return nil, err
+	case <-ctx.Done():
+		tl.Close()
+		return nil, ctx.Err()
+	}
+}
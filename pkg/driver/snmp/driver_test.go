@@ -0,0 +1,235 @@
+package snmp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/driver/snmp/internal/agent"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/healthpb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+const (
+	upsEstimatedMinutesRemaining = "1.3.6.1.2.1.33.1.2.3.0"
+	upsEstimatedChargeRemaining  = "1.3.6.1.2.1.33.1.2.4.0"
+	upsOutputSource              = "1.3.6.1.2.1.33.1.4.1.0"
+	upsOutputVoltage             = "1.3.6.1.2.1.33.1.4.4.1.2.1"
+	upsOutputCurrent             = "1.3.6.1.2.1.33.1.4.4.1.3.1"
+	upsTrapOnBattery             = "1.3.6.1.2.1.33.2.1"
+	ifOperStatus3                = "1.3.6.1.2.1.2.2.1.8.3"
+	linkDown                     = "1.3.6.1.6.3.1.1.5.3"
+	cracReturnTemp               = "1.3.6.1.4.1.99999.1.1.0"
+)
+
+func TestDriver(t *testing.T) {
+	ag, err := agent.Start("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ag.Close() })
+	ag.Set(upsEstimatedMinutesRemaining, gosnmp.Integer, 42)
+	ag.Set(upsEstimatedChargeRemaining, gosnmp.Integer, 85)
+	ag.Set(upsOutputSource, gosnmp.Integer, 3) // normal
+	ag.Set(upsOutputVoltage, gosnmp.Integer, 230)
+	ag.Set(upsOutputCurrent, gosnmp.Integer, 52) // 0.1 A
+	ag.Set(ifOperStatus3, gosnmp.Integer, 1)     // up
+	ag.Set(cracReturnTemp, gosnmp.OctetString, []byte("23.5"))
+
+	checks := newCheckRecorder()
+	n := node.New("test")
+	d := Factory.New(driver.Services{
+		Logger: zaptest.NewLogger(t),
+		Node:   n,
+		Health: healthpb.NewRegistry(healthpb.WithOnCheckCreate(checks.create), healthpb.WithOnCheckUpdate(checks.update)).ForOwner("snmp"),
+	})
+	if _, err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = d.Stop() })
+	trapAddr := freeUDPAddr(t)
+	_, err = d.Configure([]byte(fmt.Sprintf(`{
+		"name": "snmp", "type": "snmp",
+		"timing": {"timeout": "200ms", "retries": 0, "pollPeriod": "100ms"},
+		"traps": {"listen": %q, "community": "secret"},
+		"devices": [
+			{
+				"name": "ups", "host": "127.0.0.1", "port": %d, "community": "secret",
+				"points": [
+					{"name": "minutesRemaining", "oid": %q},
+					{"name": "charge", "oid": %q},
+					{"name": "outputSource", "oid": %q},
+					{"name": "voltage", "oid": %q},
+					{"name": "current", "oid": %q, "scale": 0.1},
+					{"name": "returnTemp", "oid": %q}
+				],
+				"traps": [{"trapOid": %q, "point": "outputSource", "value": 5}],
+				"traits": [
+					{"kind": "smartcore.traits.EnergyStorage", "percentage": "charge", "minutesRemaining": "minutesRemaining", "flow": "outputSource", "dischargeValues": [5]},
+					{"kind": "smartcore.traits.Electric", "voltage": "voltage", "current": "current"},
+					{"kind": "smartcore.traits.AirTemperature", "name": "crac", "ambientTemperature": "returnTemp"}
+				],
+				"healthChecks": [
+					{"id": "onBattery", "point": "outputSource", "faultValues": [5], "faultSummary": "UPS on battery", "occupantImpact": "LIFE", "equipmentImpact": "FUNCTION"}
+				]
+			},
+			{
+				"name": "switch", "host": "127.0.0.1", "port": %d, "community": "secret",
+				"points": [{"name": "port3", "oid": %q}],
+				"healthChecks": [
+					{"id": "port3", "point": "port3", "normalValues": [1], "faultSummary": "Port 3 down", "affects": ["ahu"]}
+				]
+			}
+		]
+	}`, trapAddr, ag.Addr().Port,
+		upsEstimatedMinutesRemaining, upsEstimatedChargeRemaining, upsOutputSource, upsOutputVoltage, upsOutputCurrent, cracReturnTemp,
+		upsTrapOnBattery, ag.Addr().Port, ifOperStatus3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	var esClient traits.EnergyStorageApiClient
+	var elClient traits.ElectricApiClient
+	var atClient traits.AirTemperatureApiClient
+	for _, c := range []any{&esClient, &elClient, &atClient} {
+		if err := n.Client(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("poll", func(t *testing.T) {
+		eventually(t, ctx, func() bool {
+			es, err := esClient.GetEnergyLevel(ctx, &traits.GetEnergyLevelRequest{Name: "ups"})
+			return err == nil && es.GetQuantity().GetPercentage() == 85 && es.GetIdle() != nil
+		})
+		eventually(t, ctx, func() bool {
+			el, err := elClient.GetDemand(ctx, &traits.GetDemandRequest{Name: "ups"})
+			return err == nil && el.GetVoltage() == 230 && el.GetCurrent() == 5.2
+		})
+		eventually(t, ctx, func() bool {
+			at, err := atClient.GetAirTemperature(ctx, &traits.GetAirTemperatureRequest{Name: "crac"})
+			return err == nil && at.GetAmbientTemperature().GetValueCelsius() == 23.5
+		})
+		eventually(t, ctx, func() bool {
+			c := checks.get("ups", "snmp:onBattery")
+			return c.GetNormality() == gen.HealthCheck_NORMAL && c.GetOccupantImpact() == gen.HealthCheck_LIFE
+		})
+	})
+
+	t.Run("trap", func(t *testing.T) {
+		ag.Set(upsOutputSource, gosnmp.Integer, 5) // battery
+		if err := ag.SendTrap(udpAddr(t, trapAddr), upsTrapOnBattery); err != nil {
+			t.Fatal(err)
+		}
+		eventually(t, ctx, func() bool {
+			c := checks.get("ups", "snmp:onBattery")
+			return c.GetNormality() == gen.HealthCheck_ABNORMAL && len(c.GetFaults().GetCurrentFaults()) == 1 &&
+				c.GetFaults().GetCurrentFaults()[0].GetSummaryText() == "UPS on battery"
+		})
+		eventually(t, ctx, func() bool {
+			es, err := esClient.GetEnergyLevel(ctx, &traits.GetEnergyLevelRequest{Name: "ups"})
+			return err == nil && es.GetDischarge().GetTime().AsDuration() == 42*time.Minute
+		})
+		ag.Set(upsOutputSource, gosnmp.Integer, 3)
+		eventually(t, ctx, func() bool {
+			return checks.get("ups", "snmp:onBattery").GetNormality() == gen.HealthCheck_NORMAL
+		})
+	})
+
+	t.Run("affects", func(t *testing.T) {
+		ag.Set(ifOperStatus3, gosnmp.Integer, 2) // down
+		err := ag.SendTrap(udpAddr(t, trapAddr), linkDown, gosnmp.SnmpPDU{Name: ifOperStatus3, Type: gosnmp.Integer, Value: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		eventually(t, ctx, func() bool {
+			return checks.get("switch", "snmp:port3").GetNormality() == gen.HealthCheck_ABNORMAL
+		})
+		eventually(t, ctx, func() bool {
+			rel := checks.get("ahu", "snmp:switch:port3").GetReliability()
+			return rel.GetState() == gen.HealthCheck_Reliability_UNRELIABLE && rel.GetCause().GetName() == "switch"
+		})
+		ag.Set(ifOperStatus3, gosnmp.Integer, 1)
+		eventually(t, ctx, func() bool {
+			rel := checks.get("ahu", "snmp:switch:port3").GetReliability()
+			return rel.GetState() == gen.HealthCheck_Reliability_RELIABLE && rel.GetCause() == nil
+		})
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		_ = ag.Close()
+		eventually(t, ctx, func() bool {
+			rel := checks.get("ups", "snmp:onBattery").GetReliability()
+			return rel.GetState() == gen.HealthCheck_Reliability_NO_RESPONSE
+		})
+	})
+}
+
+// checkRecorder records health checks as they change, so tests can read them without racing the driver.
+type checkRecorder struct {
+	mu     sync.Mutex
+	checks map[string]*gen.HealthCheck
+}
+
+func newCheckRecorder() *checkRecorder {
+	return &checkRecorder{checks: make(map[string]*gen.HealthCheck)}
+}
+
+func (r *checkRecorder) create(name string, c *gen.HealthCheck) *gen.HealthCheck {
+	r.update(name, c)
+	return nil
+}
+
+func (r *checkRecorder) update(name string, c *gen.HealthCheck) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name+"/"+c.GetId()] = proto.Clone(c).(*gen.HealthCheck)
+}
+
+func (r *checkRecorder) get(name, id string) *gen.HealthCheck {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.checks[name+"/"+id]
+}
+
+func freeUDPAddr(t *testing.T) string {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	return conn.LocalAddr().String()
+}
+
+func udpAddr(t *testing.T, addr string) *net.UDPAddr {
+	t.Helper()
+	a, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func eventually(t *testing.T, ctx context.Context, f func() bool) {
+	t.Helper()
+	for !f() {
+		select {
+		case <-ctx.Done():
+			t.Fatal("condition not met before timeout")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package snmp
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver/snmp/config"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+	"github.com/smart-core-os/sc-golang/pkg/trait/energystoragepb"
+)
+
+type energyStorage struct {
+	*energystoragepb.ModelServer
+	name  string
+	cfg   config.EnergyStorageConfig
+	model *energystoragepb.Model
+}
+
+func readEnergyStorageConfig(raw []byte) (cfg config.EnergyStorageConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newEnergyStorage(dev *device, raw config.RawTrait) (*energyStorage, error) {
+	cfg, err := readEnergyStorageConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPoints(dev, cfg.Percentage, cfg.EnergyKwh, cfg.Voltage, cfg.MinutesRemaining, cfg.Flow); err != nil {
+		return nil, err
+	}
+	model := energystoragepb.NewModel()
+	return &energyStorage{
+		ModelServer: energystoragepb.NewModelServer(model),
+		name:        traitName(dev, raw),
+		cfg:         cfg,
+		model:       model,
+	}, nil
+}
+
+func (e *energyStorage) AnnounceSelf(a node.Announcer) node.Undo {
+	return a.Announce(e.name, withMetadata(e.cfg.Trait,
+		node.HasTrait(trait.EnergyStorage, node.WithClients(energystoragepb.WrapApi(e))),
+	)...)
+}
+
+func (e *energyStorage) Charge(context.Context, *traits.ChargeRequest) (*traits.ChargeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "read only")
+}
+
+func (e *energyStorage) update(values map[string]float64) {
+	data := &traits.EnergyLevel{Quantity: &traits.EnergyLevel_Quantity{}}
+	if v, ok := float32Value(values, e.cfg.Percentage); ok {
+		data.Quantity.Percentage = v
+	}
+	if v, ok := float32Value(values, e.cfg.EnergyKwh); ok {
+		data.Quantity.EnergyKwh = v
+	}
+	if v, ok := float32Value(values, e.cfg.Voltage); ok {
+		data.Quantity.Voltage = &v
+	}
+	if flow, ok := values[e.cfg.Flow]; ok && e.cfg.Flow != "" {
+		switch {
+		case slices.Contains(e.cfg.DischargeValues, flow):
+			transfer := &traits.EnergyLevel_Transfer{}
+			if m, ok := values[e.cfg.MinutesRemaining]; ok && e.cfg.MinutesRemaining != "" && m >= 0 {
+				transfer.Time = durationpb.New(time.Duration(math.Round(m * float64(time.Minute))))
+			}
+			data.Flow = &traits.EnergyLevel_Discharge{Discharge: transfer}
+		case slices.Contains(e.cfg.ChargeValues, flow):
+			data.Flow = &traits.EnergyLevel_Charge{Charge: &traits.EnergyLevel_Transfer{}}
+		default:
+			data.Flow = &traits.EnergyLevel_Idle{Idle: &traits.EnergyLevel_Steady{}}
+		}
+	}
+	_, _ = e.model.UpdateEnergyLevel(data)
+}
@@ -0,0 +1,127 @@
+package snmp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/snmp/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/healthpb"
+)
+
+const SystemName = "SNMP"
+
+// healthCheck reports a fault against a device when a point has an abnormal value.
+// Devices that rely on the device, like those connected to a switch port, are told it is the cause of their unreliability.
+type healthCheck struct {
+	cfg         config.HealthCheck
+	device      string
+	displayName string
+	check       *healthpb.FaultCheck
+	affected    []*healthpb.FaultCheck
+}
+
+func newHealthCheck(checks *healthpb.Checks, device string, cfg config.HealthCheck) (*healthCheck, error) {
+	occupantImpact, equipmentImpact, err := cfg.Impacts()
+	if err != nil {
+		return nil, err
+	}
+	displayName := cfg.DisplayName
+	if displayName == "" {
+		displayName = cfg.Point
+	}
+	h := &healthCheck{cfg: cfg, device: device, displayName: displayName}
+	h.check, err = checks.NewFaultCheck(device, &gen.HealthCheck{
+		Id:              cfg.IDOrDefault(),
+		DisplayName:     displayName,
+		Description:     cfg.Description,
+		OccupantImpact:  occupantImpact,
+		EquipmentImpact: equipmentImpact,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range cfg.Affects {
+		c, err := checks.NewFaultCheck(name, &gen.HealthCheck{
+			Id:              device + ":" + cfg.IDOrDefault(),
+			DisplayName:     fmt.Sprintf("%s: %s", device, displayName),
+			Description:     fmt.Sprintf("Checks %s, which this device relies on", device),
+			OccupantImpact:  occupantImpact,
+			EquipmentImpact: equipmentImpact,
+		})
+		if err != nil {
+			h.dispose()
+			return nil, err
+		}
+		h.affected = append(h.affected, c)
+	}
+	return h, nil
+}
+
+// update sets or clears the fault based on the value v of the point.
+func (h *healthCheck) update(ctx context.Context, v float64) {
+	if !h.cfg.IsFault(v) {
+		h.check.ClearFaults()
+		for _, c := range h.affected {
+			c.ClearFaults()
+		}
+		return
+	}
+	summary := h.cfg.FaultSummary
+	if summary == "" {
+		summary = fmt.Sprintf("%s is %v", h.cfg.Point, v)
+	}
+	fault := &gen.HealthCheck_Error{
+		SummaryText: summary,
+		DetailsText: fmt.Sprintf("%s is %v", h.cfg.Point, v),
+		Code: &gen.HealthCheck_Error_Code{
+			System: SystemName,
+			Code:   strconv.FormatFloat(v, 'f', -1, 64),
+		},
+	}
+	h.check.SetFault(fault)
+	for _, c := range h.affected {
+		c.UpdateReliability(ctx, &gen.HealthCheck_Reliability{
+			State:     gen.HealthCheck_Reliability_UNRELIABLE,
+			LastError: fault,
+			Cause: &gen.HealthCheck_Reliability_Cause{
+				Name:        h.device,
+				DisplayName: h.displayName,
+				Error:       fault,
+			},
+		})
+	}
+}
+
+// updateErr marks the check as unreliable because the value of the point couldn't be read.
+func (h *healthCheck) updateErr(ctx context.Context, err error) {
+	h.check.UpdateReliability(ctx, reliabilityFromErr(err))
+}
+
+func (h *healthCheck) dispose() {
+	if h.check != nil {
+		h.check.Dispose()
+	}
+	for _, c := range h.affected {
+		c.Dispose()
+	}
+}
+
+// reliabilityFromErr is like healthpb.ReliabilityFromErr, also recognising errors from agents that don't respond,
+// or don't have a value for the point.
+func reliabilityFromErr(err error) *gen.HealthCheck_Reliability {
+	rel := healthpb.ReliabilityFromErr(err)
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errNoValue):
+		rel.State = gen.HealthCheck_Reliability_NOT_FOUND
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout(), errors.Is(err, syscall.ECONNREFUSED):
+		rel.State = gen.HealthCheck_Reliability_NO_RESPONSE
+	}
+	return rel
+}
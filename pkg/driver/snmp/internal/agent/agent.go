@@ -0,0 +1,145 @@
+// Package agent is a minimal in-process SNMP agent for testing SNMP clients.
+//
+// It answers v1 and v2c get requests from a table of values, and can send traps.
+// Get next, get bulk, set, and v3 aren't supported.
+package agent
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+type Agent struct {
+	conn      *net.UDPConn
+	community string
+
+	mu     sync.Mutex
+	values map[string]gosnmp.SnmpPDU
+	gets   int
+}
+
+// Start starts an agent listening on a random local port.
+// Requests with a different community are ignored.
+func Start(community string) (*Agent, error) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		return nil, err
+	}
+	a := &Agent{
+		conn:      conn,
+		community: community,
+		values:    make(map[string]gosnmp.SnmpPDU),
+	}
+	go a.serve()
+	return a, nil
+}
+
+// Addr returns the address the agent is listening on, traps are also sent from this address.
+func (a *Agent) Addr() *net.UDPAddr {
+	return a.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// Set sets the value returned for oid.
+func (a *Agent) Set(oid string, typ gosnmp.Asn1BER, value any) {
+	oid = normalize(oid)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values[oid] = gosnmp.SnmpPDU{Name: "." + oid, Type: typ, Value: value}
+}
+
+// Delete removes oid, it is reported as noSuchObject.
+func (a *Agent) Delete(oid string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.values, normalize(oid))
+}
+
+// Gets returns how many get requests have been answered.
+func (a *Agent) Gets() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.gets
+}
+
+// SendTrap sends a v2c trap identified by trapOID to addr, with the given variables.
+func (a *Agent) SendTrap(addr *net.UDPAddr, trapOID string, vars ...gosnmp.SnmpPDU) error {
+	pkt := &gosnmp.SnmpPacket{
+		Version:   gosnmp.Version2c,
+		Community: a.community,
+		PDUType:   gosnmp.SNMPv2Trap,
+		RequestID: 1,
+		Variables: append([]gosnmp.SnmpPDU{
+			{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(0)},
+			{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: "." + normalize(trapOID)},
+		}, vars...),
+	}
+	out, err := pkt.MarshalMsg()
+	if err != nil {
+		return err
+	}
+	_, err = a.conn.WriteToUDP(out, addr)
+	return err
+}
+
+// Close stops the agent, requests are no longer answered.
+func (a *Agent) Close() error {
+	return a.conn.Close()
+}
+
+func (a *Agent) serve() {
+	decoder := &gosnmp.GoSNMP{Version: gosnmp.Version2c, MaxOids: gosnmp.MaxOids}
+	buf := make([]byte, 65535)
+	for {
+		n, from, err := a.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		req, err := decoder.SnmpDecodePacket(buf[:n])
+		if err != nil || req.PDUType != gosnmp.GetRequest || req.Community != a.community {
+			continue
+		}
+		res := a.respond(req)
+		out, err := res.MarshalMsg()
+		if err != nil {
+			continue
+		}
+		_, _ = a.conn.WriteToUDP(out, from)
+	}
+}
+
+func (a *Agent) respond(req *gosnmp.SnmpPacket) *gosnmp.SnmpPacket {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.gets++
+	res := &gosnmp.SnmpPacket{
+		Version:   req.Version,
+		Community: req.Community,
+		PDUType:   gosnmp.GetResponse,
+		RequestID: req.RequestID,
+	}
+	for i, v := range req.Variables {
+		val, ok := a.values[normalize(v.Name)]
+		if !ok {
+			if req.Version == gosnmp.Version1 {
+				res.Error = gosnmp.NoSuchName
+				res.ErrorIndex = uint8(i + 1)
+				res.Variables = req.Variables
+				return res
+			}
+			val = gosnmp.SnmpPDU{Name: v.Name, Type: gosnmp.NoSuchObject}
+		}
+		res.Variables = append(res.Variables, val)
+	}
+	return res
+}
+
+func normalize(oid string) string {
+	return strings.TrimPrefix(oid, ".")
+}
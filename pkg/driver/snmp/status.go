@@ -0,0 +1,103 @@
+package snmp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/snmp/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+// statusTrait reports problems when points don't have their normal value.
+// The Status trait itself is announced by the device status map, which also reports poll errors.
+type statusTrait struct {
+	name     string
+	dev      *device
+	cfg      config.StatusConfig
+	levels   []gen.StatusLog_Level
+	reported []gen.StatusLog_Level
+}
+
+func readStatusConfig(raw []byte) (cfg config.StatusConfig, err error) {
+	err = json.Unmarshal(raw, &cfg)
+	return
+}
+
+func newStatus(dev *device, raw config.RawTrait) (*statusTrait, error) {
+	cfg, err := readStatusConfig(raw.Raw)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	levels := make([]gen.StatusLog_Level, len(cfg.Problems))
+	for i, p := range cfg.Problems {
+		if p.Point == "" {
+			errs = append(errs, fmt.Errorf("problem %d: point is required", i))
+			continue
+		}
+		if err := checkPoints(dev, p.Point); err != nil {
+			errs = append(errs, fmt.Errorf("problem %d: %w", i, err))
+		}
+		levels[i] = gen.StatusLog_NOTICE
+		if p.Level != "" {
+			l, ok := gen.StatusLog_Level_value[p.Level]
+			if !ok {
+				errs = append(errs, fmt.Errorf("problem %d: unknown level %q", i, p.Level))
+			}
+			levels[i] = gen.StatusLog_Level(l)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &statusTrait{
+		name:     traitName(dev, raw),
+		dev:      dev,
+		cfg:      cfg,
+		levels:   levels,
+		reported: make([]gen.StatusLog_Level, len(cfg.Problems)),
+	}, nil
+}
+
+func (s *statusTrait) AnnounceSelf(a node.Announcer) node.Undo {
+	if s.cfg.Metadata == nil {
+		return node.NilUndo
+	}
+	return a.Announce(s.name, node.HasMetadata(s.cfg.Metadata))
+}
+
+func (s *statusTrait) update(values map[string]float64) {
+	for i, p := range s.cfg.Problems {
+		v, ok := values[p.Point]
+		if !ok {
+			continue
+		}
+		level := gen.StatusLog_NOMINAL
+		desc := fmt.Sprintf("%s nominal", p.Point)
+		if v != p.NormalValue {
+			level = s.levels[i]
+			desc = p.Description
+			if desc == "" {
+				desc = fmt.Sprintf("%s is %v, normally %v", p.Point, v, p.NormalValue)
+			}
+		}
+		if s.reported[i] == level {
+			continue
+		}
+		s.reported[i] = level
+		name := p.Name
+		if name == "" {
+			name = p.Point
+		}
+		s.dev.statuses.UpdateProblem(s.name, &gen.StatusLog_Problem{
+			Name:        s.name + ":" + name,
+			Level:       level,
+			Description: desc,
+			RecordTime:  timestamppb.Now(),
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package snmp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/snmp/config"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/statuspb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+)
+
+var ErrTraitNotSupported = errors.New("trait not supported")
+
+// deviceTrait is a trait implemented using the points of a device.
+type deviceTrait interface {
+	node.SelfAnnouncer
+	// update is called with the latest values of the device points.
+	update(values map[string]float64)
+}
+
+// newTrait returns the trait described by cfg, implemented using dev.
+func newTrait(dev *device, cfg config.RawTrait) (deviceTrait, error) {
+	switch cfg.Kind {
+	case trait.AirTemperature:
+		return newAirTemperature(dev, cfg)
+	case trait.Electric:
+		return newElectric(dev, cfg)
+	case trait.EnergyStorage:
+		return newEnergyStorage(dev, cfg)
+	case statuspb.TraitName:
+		return newStatus(dev, cfg)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTraitNotSupported, cfg.Kind)
+}
+
+// traitName returns the name the trait is announced with, which defaults to the device name.
+func traitName(dev *device, cfg config.RawTrait) string {
+	if cfg.Name != "" {
+		return cfg.Name
+	}
+	return dev.cfg.Name
+}
+
+// checkPoints returns an error if any of points aren't points of dev, empty names are ignored.
+func checkPoints(dev *device, points ...string) error {
+	var errs []error
+	for _, p := range points {
+		if p == "" {
+			continue
+		}
+		if _, ok := dev.cfg.Point(p); !ok {
+			errs = append(errs, fmt.Errorf("unknown point %q", p))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// float32Value returns the value of the named point, and whether it is known.
+func float32Value(values map[string]float64, point string) (float32, bool) {
+	if point == "" {
+		return 0, false
+	}
+	v, ok := values[point]
+	return float32(v), ok
+}
+
+// withMetadata adds the metadata of the trait config to features, if it has any.
+func withMetadata(cfg config.Trait, features ...node.Feature) []node.Feature {
+	if cfg.Metadata != nil {
+		features = append(features, node.HasMetadata(cfg.Metadata))
+	}
+	return features
+}
@@ -0,0 +1,100 @@
+package snmp
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/snmp/config"
+)
+
+// errNoValue is returned for variables the agent doesn't have a value for.
+var errNoValue = errors.New("no value")
+
+// pointValue returns the scaled value of p from the variable v.
+func pointValue(p config.Point, v gosnmp.SnmpPDU) (float64, error) {
+	raw, err := rawValue(p, v)
+	if err != nil {
+		return 0, err
+	}
+	return p.Scaled(raw), nil
+}
+
+func rawValue(p config.Point, v gosnmp.SnmpPDU) (float64, error) {
+	switch v.Type {
+	case gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView, gosnmp.Null:
+		return 0, fmt.Errorf("%w: %s", errNoValue, v.Type)
+	case gosnmp.OctetString, gosnmp.ObjectIdentifier, gosnmp.IPAddress:
+		var s string
+		switch raw := v.Value.(type) {
+		case []byte:
+			s = string(raw)
+		case string:
+			s = raw
+		default:
+			return 0, fmt.Errorf("unexpected %s value %T", v.Type, v.Value)
+		}
+		s = strings.TrimSpace(s)
+		if v.Type == gosnmp.ObjectIdentifier {
+			s = config.NormalizeOID(s)
+		}
+		if n, ok := p.Enum[s]; ok {
+			return n, nil
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q isn't a number or enum value", s)
+		}
+		return n, nil
+	case gosnmp.OpaqueFloat:
+		if f, ok := v.Value.(float32); ok {
+			return float64(f), nil
+		}
+	case gosnmp.OpaqueDouble:
+		if f, ok := v.Value.(float64); ok {
+			return f, nil
+		}
+	case gosnmp.Boolean:
+		if b, ok := v.Value.(bool); ok {
+			if b {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	default:
+		// integers, counters, gauges and time ticks
+		f, _ := new(big.Float).SetInt(gosnmp.ToBigInt(v.Value)).Float64()
+		return f, nil
+	}
+	return 0, fmt.Errorf("unexpected %s value %T", v.Type, v.Value)
+}
+
+// trapOID returns the OID that identifies the trap, for v1 traps this is converted as described by RFC 3584.
+func trapOID(pkt *gosnmp.SnmpPacket) string {
+	if pkt.PDUType == gosnmp.Trap {
+		if pkt.GenericTrap == 6 { // enterpriseSpecific
+			return config.NormalizeOID(pkt.Enterprise) + ".0." + strconv.Itoa(pkt.SpecificTrap)
+		}
+		return snmpTrapsOID + "." + strconv.Itoa(pkt.GenericTrap+1)
+	}
+	for _, v := range pkt.Variables {
+		if config.NormalizeOID(v.Name) != snmpTrapOIDOID {
+			continue
+		}
+		if s, ok := v.Value.(string); ok {
+			return config.NormalizeOID(s)
+		}
+	}
+	return ""
+}
+
+const (
+	// snmpTrapOIDOID is the OID of the variable holding the OID of v2c and v3 traps.
+	snmpTrapOIDOID = "1.3.6.1.6.3.1.1.4.1.0"
+	// snmpTrapsOID is the prefix of the standard traps, like coldStart and linkDown.
+	snmpTrapsOID = "1.3.6.1.6.3.1.1.5"
+)
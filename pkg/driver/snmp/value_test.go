@@ -0,0 +1,79 @@
+package snmp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/snmp/config"
+)
+
+func Test_pointValue(t *testing.T) {
+	point := config.Point{Name: "p", Scale: 0.1, Offset: 1, Enum: map[string]float64{"online": 2}}
+	tests := []struct {
+		name    string
+		pdu     gosnmp.SnmpPDU
+		want    float64
+		wantErr bool
+	}{
+		{name: "integer", pdu: gosnmp.SnmpPDU{Type: gosnmp.Integer, Value: -20}, want: -1},
+		{name: "gauge", pdu: gosnmp.SnmpPDU{Type: gosnmp.Gauge32, Value: uint(50)}, want: 6},
+		{name: "counter64", pdu: gosnmp.SnmpPDU{Type: gosnmp.Counter64, Value: uint64(100)}, want: 11},
+		{name: "string number", pdu: gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte(" 23.5 ")}, want: 3.35},
+		{name: "string enum", pdu: gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte("online")}, want: 1.2},
+		{name: "string other", pdu: gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte("offline")}, wantErr: true},
+		{name: "float", pdu: gosnmp.SnmpPDU{Type: gosnmp.OpaqueFloat, Value: float32(10)}, want: 2},
+		{name: "no such object", pdu: gosnmp.SnmpPDU{Type: gosnmp.NoSuchObject}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pointValue(point, tt.pdu)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pointValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := got - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("pointValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	_, err := pointValue(point, gosnmp.SnmpPDU{Type: gosnmp.NoSuchInstance})
+	if !errors.Is(err, errNoValue) {
+		t.Errorf("pointValue(noSuchInstance) error = %v, want %v", err, errNoValue)
+	}
+}
+
+func Test_trapOID(t *testing.T) {
+	tests := []struct {
+		name string
+		pkt  *gosnmp.SnmpPacket
+		want string
+	}{
+		{
+			name: "v2c",
+			pkt: &gosnmp.SnmpPacket{PDUType: gosnmp.SNMPv2Trap, Variables: []gosnmp.SnmpPDU{
+				{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(10)},
+				{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.2.1.33.2.1"},
+			}},
+			want: "1.3.6.1.2.1.33.2.1",
+		},
+		{
+			name: "v1 generic",
+			pkt:  &gosnmp.SnmpPacket{PDUType: gosnmp.Trap, SnmpTrap: gosnmp.SnmpTrap{Enterprise: ".1.3.6.1.4.1.9", GenericTrap: 2}},
+			want: "1.3.6.1.6.3.1.1.5.3",
+		},
+		{
+			name: "v1 specific",
+			pkt:  &gosnmp.SnmpPacket{PDUType: gosnmp.Trap, SnmpTrap: gosnmp.SnmpTrap{Enterprise: ".1.3.6.1.4.1.318", GenericTrap: 6, SpecificTrap: 5}},
+			want: "1.3.6.1.4.1.318.0.5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trapOID(tt.pkt); got != tt.want {
+				t.Errorf("trapOID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
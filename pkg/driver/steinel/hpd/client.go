@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type Client struct {
@@ -58,12 +60,12 @@ func NewInsecureClient(host string, password string) *Client {
 			Path:   "/rest",
 		},
 		Client: &http.Client{
-			Transport: &http.Transport{
+			Transport: otelhttp.NewTransport(&http.Transport{
 				TLSClientConfig: &tls.Config{
 					InsecureSkipVerify: true,
 					CipherSuites:       getAllowedCiphers(),
 				},
-			},
+			}),
 		},
 	}
 	if len(password) > 0 {
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+// Traits that virtual devices implement, or that inputs are read from.
+const (
+	TraitMeter       = "meter"
+	TraitElectric    = "electric"
+	TraitTemperature = "temperature" // input only, the ambient temperature of an AirTemperature device
+)
+
+// Fields of the Electric demand, the value virtual electric devices compute or electric inputs read.
+const (
+	FieldRealPower     = "realPower"
+	FieldApparentPower = "apparentPower"
+	FieldReactivePower = "reactivePower"
+	FieldCurrent       = "current"
+	FieldVoltage       = "voltage"
+	FieldPowerFactor   = "powerFactor"
+)
+
+// ElectricUnits are the units of each electric demand field.
+var ElectricUnits = map[string]string{
+	FieldRealPower:     "W",
+	FieldApparentPower: "VA",
+	FieldReactivePower: "VAR",
+	FieldCurrent:       "A",
+	FieldVoltage:       "V",
+	FieldPowerFactor:   "",
+}
+
+const DefaultStaleAfter = 15 * time.Minute
+
+func ReadBytes(data []byte) (cfg Root, err error) {
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return
+	}
+	for i := range cfg.Devices {
+		d := &cfg.Devices[i]
+		if d.Trait == "" {
+			d.Trait = TraitMeter
+		}
+		if d.Trait == TraitElectric && d.Field == "" {
+			d.Field = FieldRealPower
+		}
+		for name, in := range d.Inputs {
+			if in.Trait == "" {
+				in.Trait = d.Trait
+				if in.Trait == TraitElectric && in.Field == "" {
+					in.Field = d.Field
+				}
+			}
+			if in.Trait == TraitElectric && in.Field == "" {
+				in.Field = FieldRealPower
+			}
+			d.Inputs[name] = in
+		}
+	}
+	return cfg, cfg.validate()
+}
+
+type Root struct {
+	driver.BaseConfig
+	Devices []Device `json:"devices,omitempty"`
+}
+
+// Device is a virtual device whose value is calculated from the live values of other devices.
+type Device struct {
+	Name     string           `json:"name,omitempty"`
+	Metadata *traits.Metadata `json:"metadata,omitempty"`
+	// Trait the device implements, one of TraitMeter or TraitElectric.
+	// Defaults to TraitMeter.
+	Trait string `json:"trait,omitempty"`
+	// Field of the electric demand the expression computes, defaults to FieldRealPower.
+	// Only used when Trait is TraitElectric.
+	Field string `json:"field,omitempty"`
+
+	// Expression calculates the value of the device from Inputs.
+	// It supports numbers, input names, + - * /, parentheses, and the functions min, max, and abs.
+	// For example "incomer - tenantA - tenantB".
+	Expression string `json:"expression,omitempty"`
+	// Inputs are named by the identifier used in Expression.
+	Inputs map[string]Input `json:"inputs,omitempty"`
+	// Unit of the calculated value.
+	// Optional when the unit can be derived from the inputs, required when it can't, for example "flow * deltaT * 1.16".
+	Unit string `json:"unit,omitempty"`
+
+	// StaleAfter is how long the last value of an input that can't be read is used for.
+	// Once an input is stale the device reports an error until the input can be read again.
+	// Defaults to DefaultStaleAfter.
+	StaleAfter *jsontypes.Duration `json:"staleAfter,omitempty"`
+}
+
+type Input struct {
+	// Name of the device to read.
+	Name string `json:"name,omitempty"`
+	// Trait to read, one of TraitMeter, TraitElectric, or TraitTemperature.
+	// Defaults to the trait of the virtual device.
+	Trait string `json:"trait,omitempty"`
+	// Field of the electric demand to read, defaults to FieldRealPower.
+	// Only used when Trait is TraitElectric.
+	Field string `json:"field,omitempty"`
+}
+
+func (d Device) StaleAfterOrDefault() time.Duration {
+	if d.StaleAfter == nil {
+		return DefaultStaleAfter
+	}
+	return d.StaleAfter.Duration
+}
+
+func (c Root) validate() error {
+	var errs []error
+	names := make(map[string]bool)
+	for i, d := range c.Devices {
+		if d.Name == "" {
+			errs = append(errs, fmt.Errorf("devices[%d]: name is required", i))
+			continue
+		}
+		if names[d.Name] {
+			errs = append(errs, fmt.Errorf("%s: duplicate device name", d.Name))
+		}
+		names[d.Name] = true
+		switch d.Trait {
+		case TraitMeter:
+		case TraitElectric:
+			if _, ok := ElectricUnits[d.Field]; !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown field %q", d.Name, d.Field))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%s: unsupported trait %q", d.Name, d.Trait))
+		}
+		if d.Expression == "" {
+			errs = append(errs, fmt.Errorf("%s: expression is required", d.Name))
+		}
+		for id, in := range d.Inputs {
+			if in.Name == "" {
+				errs = append(errs, fmt.Errorf("%s: input %s: name is required", d.Name, id))
+			}
+			if in.Name == d.Name {
+				errs = append(errs, fmt.Errorf("%s: input %s: can't read itself", d.Name, id))
+			}
+			switch in.Trait {
+			case TraitMeter, TraitTemperature:
+			case TraitElectric:
+				if _, ok := ElectricUnits[in.Field]; !ok {
+					errs = append(errs, fmt.Errorf("%s: input %s: unknown field %q", d.Name, id, in.Field))
+				}
+			default:
+				errs = append(errs, fmt.Errorf("%s: input %s: unsupported trait %q", d.Name, id, in.Trait))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,262 @@
+package virtualmeter
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-api/go/types"
+	"github.com/smart-core-os/sc-bos/pkg/driver/virtualmeter/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/meter"
+	"github.com/smart-core-os/sc-bos/pkg/task"
+	"github.com/smart-core-os/sc-bos/pkg/util/pull"
+	"github.com/smart-core-os/sc-golang/pkg/resource"
+	"github.com/smart-core-os/sc-golang/pkg/trait/electricpb"
+)
+
+// device calculates the value of a virtual device from its inputs.
+type device struct {
+	cfg    config.Device
+	expr   *expr
+	conn   grpc.ClientConnInterface
+	logger *zap.Logger
+	now    func() time.Time
+
+	meter    *meter.Model      // set if the device is a meter
+	electric *electricpb.Model // set if the device is electric
+
+	mu    sync.Mutex
+	unit  string
+	err   error // why the value can't be calculated, nil if it can
+	last  float64
+	valid bool // whether last was written to the model
+}
+
+func newDevice(cfg config.Device, conn grpc.ClientConnInterface, logger *zap.Logger, now func() time.Time) (*device, error) {
+	vars := make(map[string]bool, len(cfg.Inputs))
+	for id := range cfg.Inputs {
+		vars[id] = true
+	}
+	e, err := parseExpr(cfg.Expression, vars)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.Name, err)
+	}
+	d := &device{
+		cfg:    cfg,
+		expr:   e,
+		conn:   conn,
+		logger: logger,
+		now:    now,
+		err:    status.Error(codes.Unavailable, "collecting initial data, please try again soon"),
+	}
+	switch cfg.Trait {
+	case config.TraitMeter:
+		d.meter = meter.NewModel()
+	case config.TraitElectric:
+		d.electric = electricpb.NewModel()
+	}
+	return d, nil
+}
+
+// run reads the inputs and updates the value of the device until ctx is done.
+func (d *device) run(ctx context.Context) {
+	// units don't tend to change, we only check them once
+	err := task.Run(ctx, func(ctx context.Context) (task.Next, error) {
+		return task.Normal, d.resolveUnit(ctx)
+	}, task.WithRetry(task.RetryUnlimited), task.WithBackoff(time.Second, time.Minute), task.WithErrorLogger(d.logger))
+	if err != nil {
+		return
+	}
+	if err := d.error(); status.Code(err) == codes.FailedPrecondition {
+		d.logger.Error("virtual device disabled", zap.Error(err))
+		return
+	}
+
+	changes := make(chan sample)
+	for _, id := range slices.Sorted(maps.Keys(d.cfg.Inputs)) {
+		in := d.cfg.Inputs[id]
+		go func() {
+			logger := d.logger.With(zap.String("input", in.Name))
+			// only returns when ctx is done
+			_ = watchInput(ctx, d.conn, id, in, changes, pull.WithLogger(logger))
+		}()
+	}
+
+	staleAfter := d.cfg.StaleAfterOrDefault()
+	// check for stale inputs even if nothing changes
+	ticker := time.NewTicker(min(staleAfter/2, time.Minute))
+	defer ticker.Stop()
+	inputs := make(map[string]*inputState, len(d.cfg.Inputs))
+	for id := range d.cfg.Inputs {
+		inputs[id] = &inputState{}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s := <-changes:
+			in := inputs[s.input]
+			if s.err != nil {
+				in.err = s.err
+			} else {
+				in.value, in.has, in.at, in.err = s.value, true, d.now(), nil
+			}
+		case <-ticker.C:
+		}
+		d.update(inputs)
+	}
+}
+
+// resolveUnit works out the unit of the device from the units of its inputs.
+// Units that don't match are recorded as a FailedPrecondition error.
+func (d *device) resolveUnit(ctx context.Context) error {
+	units := make(map[string]string, len(d.cfg.Inputs))
+	for id, in := range d.cfg.Inputs {
+		u, err := inputUnit(ctx, d.conn, in)
+		if err != nil {
+			return err
+		}
+		units[id] = u
+	}
+	unit, err := d.checkUnit(units)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil {
+		d.err = status.Errorf(codes.FailedPrecondition, "%s: %v", d.cfg.Name, err)
+		return nil
+	}
+	d.unit = unit
+	return nil
+}
+
+// checkUnit returns the unit of the device given the units of each input.
+func (d *device) checkUnit(units map[string]string) (string, error) {
+	derived, err := d.expr.unit(units)
+	if err != nil {
+		return "", err
+	}
+	want := d.cfg.Unit
+	if d.cfg.Trait == config.TraitElectric {
+		want = config.ElectricUnits[d.cfg.Field]
+	}
+	switch {
+	case want == "" && isCompound(derived):
+		return "", fmt.Errorf("unit is required, the expression has unit %q", derived)
+	case want == "":
+		return derived, nil
+	case derived != "" && !isCompound(derived) && derived != want:
+		return "", fmt.Errorf("expression has unit %q, want %q", derived, want)
+	}
+	return want, nil
+}
+
+// update recalculates the value of the device from inputs.
+func (d *device) update(inputs map[string]*inputState) {
+	now := d.now()
+	staleAfter := d.cfg.StaleAfterOrDefault()
+	vars := make(map[string]float64, len(inputs))
+	var err error
+	for _, id := range slices.Sorted(maps.Keys(inputs)) {
+		in := inputs[id]
+		switch {
+		case !in.has && in.err != nil:
+			err = status.Errorf(codes.Unavailable, "input %s: %v", id, in.err)
+		case !in.has:
+			err = status.Errorf(codes.Unavailable, "collecting initial data for input %s, please try again soon", id)
+		case in.err != nil && now.Sub(in.at) > staleAfter:
+			err = status.Errorf(codes.Unavailable, "input %s is stale, last read %s: %v", id, in.at.Format(time.RFC3339), in.err)
+		}
+		if err != nil {
+			break
+		}
+		vars[id] = in.value
+	}
+	var v float64
+	if err == nil {
+		v, err = d.expr.eval(vars)
+		if err != nil {
+			err = status.Errorf(codes.FailedPrecondition, "%s: %v", d.cfg.Expression, err)
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil {
+		if d.err == nil || d.err.Error() != err.Error() {
+			d.logger.Debug("can't calculate value", zap.Error(err))
+		}
+		d.err = err
+		return
+	}
+	d.err = nil
+	if d.valid && v == d.last {
+		return
+	}
+	d.last, d.valid = v, true
+	switch {
+	case d.meter != nil:
+		_, err = d.meter.RecordReading(float32(v))
+	case d.electric != nil:
+		demand := &traits.ElectricDemand{}
+		setDemandField(demand, d.cfg.Field, v)
+		_, err = d.electric.UpdateDemand(demand, resource.WithUpdatePaths(demandPath(d.cfg.Field)))
+	}
+	if err != nil {
+		d.logger.Warn("failed to record value", zap.Error(err))
+	}
+}
+
+func (d *device) error() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
+
+// meterServer serves the MeterApi and MeterInfo of a virtual meter.
+type meterServer struct {
+	*meter.ModelServer
+	gen.UnimplementedMeterInfoServer
+	d *device
+}
+
+func (s *meterServer) GetMeterReading(ctx context.Context, request *gen.GetMeterReadingRequest) (*gen.MeterReading, error) {
+	if err := s.d.error(); err != nil {
+		return nil, err
+	}
+	return s.ModelServer.GetMeterReading(ctx, request)
+}
+
+func (s *meterServer) DescribeMeterReading(context.Context, *gen.DescribeMeterReadingRequest) (*gen.MeterReadingSupport, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if status.Code(s.d.err) == codes.FailedPrecondition {
+		return nil, s.d.err
+	}
+	return &gen.MeterReadingSupport{
+		ResourceSupport: &types.ResourceSupport{Readable: true, Observable: true},
+		UsageUnit:       s.d.unit,
+	}, nil
+}
+
+// electricServer serves the ElectricApi of a virtual electric device.
+type electricServer struct {
+	*electricpb.ModelServer
+	d *device
+}
+
+func (s *electricServer) GetDemand(ctx context.Context, request *traits.GetDemandRequest) (*traits.ElectricDemand, error) {
+	if err := s.d.error(); err != nil {
+		return nil, err
+	}
+	return s.ModelServer.GetDemand(ctx, request)
+}
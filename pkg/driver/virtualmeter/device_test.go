@@ -0,0 +1,85 @@
+package virtualmeter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver/virtualmeter/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+func TestDevice_update(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := config.Device{
+		Name:       "landlord",
+		Trait:      config.TraitMeter,
+		Expression: "incomer - tenant",
+		Inputs: map[string]config.Input{
+			"incomer": {Name: "incomer", Trait: config.TraitMeter},
+			"tenant":  {Name: "tenant", Trait: config.TraitMeter},
+		},
+		StaleAfter: &jsontypes.Duration{Duration: 10 * time.Minute},
+	}
+	d, err := newDevice(cfg, nil, zap.NewNop(), func() time.Time { return now })
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &meterServer{d: d}
+	get := func() (*gen.MeterReading, error) {
+		if err := d.error(); err != nil {
+			return nil, err
+		}
+		return d.meter.GetMeterReading()
+	}
+
+	inputs := map[string]*inputState{
+		"incomer": {value: 100, has: true, at: now},
+		"tenant":  {},
+	}
+	d.update(inputs)
+	if _, err := get(); status.Code(err) != codes.Unavailable {
+		t.Fatalf("missing input: want Unavailable, got %v", err)
+	}
+
+	inputs["tenant"] = &inputState{value: 40, has: true, at: now}
+	d.update(inputs)
+	got, err := get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Usage != 60 {
+		t.Fatalf("usage got %v, want 60", got.Usage)
+	}
+
+	// a failing input holds its last value until it is stale
+	inputs["tenant"].err = errors.New("offline")
+	now = now.Add(5 * time.Minute)
+	d.update(inputs)
+	if _, err := get(); err != nil {
+		t.Fatalf("recently failed input: %v", err)
+	}
+	now = now.Add(10 * time.Minute)
+	d.update(inputs)
+	if _, err := get(); status.Code(err) != codes.Unavailable {
+		t.Fatalf("stale input: want Unavailable, got %v", err)
+	}
+
+	// units are derived from the inputs
+	if u, err := d.checkUnit(map[string]string{"incomer": "kWh", "tenant": "kWh"}); err != nil || u != "kWh" {
+		t.Fatalf("checkUnit got %q, %v, want kWh", u, err)
+	}
+	if _, err := d.checkUnit(map[string]string{"incomer": "kWh", "tenant": "m3"}); err == nil {
+		t.Fatal("checkUnit expected error for mismatched units")
+	}
+	d.err = status.Error(codes.FailedPrecondition, "bad units")
+	if _, err := srv.DescribeMeterReading(context.Background(), nil); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("describe with bad units: want FailedPrecondition, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+// Package virtualmeter provides a driver for virtual meters, devices whose value is calculated from the live values of
+// other devices.
+// For example a landlord meter can be defined as "incomer - tenantA - tenantB".
+// Virtual devices implement the Meter or Electric traits so they can be recorded by the history automation like any
+// other device.
+package virtualmeter
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/driver"
+	"github.com/smart-core-os/sc-bos/pkg/driver/virtualmeter/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/meter"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+	"github.com/smart-core-os/sc-golang/pkg/trait/electricpb"
+)
+
+const DriverName = "virtual-meter"
+
+var Factory driver.Factory = factory{}
+
+type factory struct{}
+
+func (f factory) New(services driver.Services) service.Lifecycle {
+	d := &Driver{
+		announcer: node.NewReplaceAnnouncer(services.Node),
+		clients:   services.Node,
+	}
+	d.Service = service.New(service.MonoApply(d.applyConfig), service.WithParser(config.ReadBytes))
+	d.logger = services.Logger.Named(DriverName)
+	return d
+}
+
+type Driver struct {
+	*service.Service[config.Root]
+	logger    *zap.Logger
+	announcer *node.ReplaceAnnouncer
+	clients   node.ClientConner
+}
+
+func (d *Driver) applyConfig(ctx context.Context, cfg config.Root) error {
+	announcer := d.announcer.Replace(ctx)
+	conn := d.clients.ClientConn()
+
+	// create all devices first so a bad expression doesn't leave us half configured
+	devices := make([]*device, 0, len(cfg.Devices))
+	for _, dc := range cfg.Devices {
+		dev, err := newDevice(dc, conn, d.logger.With(zap.String("device", dc.Name)), time.Now)
+		if err != nil {
+			return err
+		}
+		devices = append(devices, dev)
+	}
+
+	for _, dev := range devices {
+		name := dev.cfg.Name
+		if dev.cfg.Metadata != nil {
+			announcer.Announce(name, node.HasMetadata(dev.cfg.Metadata))
+		}
+		switch {
+		case dev.meter != nil:
+			srv := &meterServer{ModelServer: meter.NewModelServer(dev.meter), d: dev}
+			announcer.Announce(name, node.HasTrait(meter.TraitName, node.WithClients(gen.WrapMeterApi(srv), gen.WrapMeterInfo(srv))))
+		case dev.electric != nil:
+			srv := &electricServer{ModelServer: electricpb.NewModelServer(dev.electric), d: dev}
+			announcer.Announce(name, node.HasTrait(trait.Electric, node.WithClients(electricpb.WrapApi(srv))))
+		}
+		go dev.run(ctx)
+	}
+	return nil
+}
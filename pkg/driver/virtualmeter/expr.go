@@ -0,0 +1,260 @@
+package virtualmeter
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// expr is a parsed arithmetic expression over named inputs.
+type expr struct {
+	src  string
+	root ast.Expr
+}
+
+// parseExpr parses and validates src, which may only refer to the identifiers in vars.
+// All of vars must be used.
+func parseExpr(src string, vars map[string]bool) (*expr, error) {
+	root, err := parser.ParseExpr(src)
+	if err != nil {
+		return nil, fmt.Errorf("parse %q: %w", src, err)
+	}
+	used := make(map[string]bool)
+	if err := checkNode(root, vars, used); err != nil {
+		return nil, fmt.Errorf("%q: %w", src, err)
+	}
+	for name := range vars {
+		if !used[name] {
+			return nil, fmt.Errorf("%q: input %q is not used", src, name)
+		}
+	}
+	return &expr{src: src, root: root}, nil
+}
+
+func checkNode(n ast.Expr, vars, used map[string]bool) error {
+	switch n := n.(type) {
+	case *ast.ParenExpr:
+		return checkNode(n.X, vars, used)
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT {
+			return fmt.Errorf("unsupported literal %s", n.Value)
+		}
+		return nil
+	case *ast.Ident:
+		if !vars[n.Name] {
+			return fmt.Errorf("unknown input %q", n.Name)
+		}
+		used[n.Name] = true
+		return nil
+	case *ast.UnaryExpr:
+		if n.Op != token.ADD && n.Op != token.SUB {
+			return fmt.Errorf("unsupported operator %s", n.Op)
+		}
+		return checkNode(n.X, vars, used)
+	case *ast.BinaryExpr:
+		switch n.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO:
+		default:
+			return fmt.Errorf("unsupported operator %s", n.Op)
+		}
+		return errors.Join(checkNode(n.X, vars, used), checkNode(n.Y, vars, used))
+	case *ast.CallExpr:
+		fn, ok := n.Fun.(*ast.Ident)
+		if !ok {
+			return errors.New("unsupported function call")
+		}
+		switch fn.Name {
+		case "min", "max":
+			if len(n.Args) < 2 {
+				return fmt.Errorf("%s needs at least 2 arguments", fn.Name)
+			}
+		case "abs":
+			if len(n.Args) != 1 {
+				return errors.New("abs needs 1 argument")
+			}
+		default:
+			return fmt.Errorf("unknown function %q", fn.Name)
+		}
+		var errs []error
+		for _, arg := range n.Args {
+			errs = append(errs, checkNode(arg, vars, used))
+		}
+		return errors.Join(errs...)
+	}
+	return fmt.Errorf("unsupported expression %T", n)
+}
+
+// eval calculates the value of the expression using vars as input values.
+func (e *expr) eval(vars map[string]float64) (float64, error) {
+	return evalNode(e.root, vars)
+}
+
+func evalNode(n ast.Expr, vars map[string]float64) (float64, error) {
+	switch n := n.(type) {
+	case *ast.ParenExpr:
+		return evalNode(n.X, vars)
+	case *ast.BasicLit:
+		return strconv.ParseFloat(n.Value, 64)
+	case *ast.Ident:
+		v, ok := vars[n.Name]
+		if !ok {
+			return 0, fmt.Errorf("no value for %q", n.Name)
+		}
+		return v, nil
+	case *ast.UnaryExpr:
+		x, err := evalNode(n.X, vars)
+		if err != nil {
+			return 0, err
+		}
+		if n.Op == token.SUB {
+			return -x, nil
+		}
+		return x, nil
+	case *ast.BinaryExpr:
+		x, err := evalNode(n.X, vars)
+		if err != nil {
+			return 0, err
+		}
+		y, err := evalNode(n.Y, vars)
+		if err != nil {
+			return 0, err
+		}
+		switch n.Op {
+		case token.ADD:
+			return x + y, nil
+		case token.SUB:
+			return x - y, nil
+		case token.MUL:
+			return x * y, nil
+		case token.QUO:
+			if y == 0 {
+				return 0, errors.New("division by zero")
+			}
+			return x / y, nil
+		}
+	case *ast.CallExpr:
+		args := make([]float64, len(n.Args))
+		for i, arg := range n.Args {
+			v, err := evalNode(arg, vars)
+			if err != nil {
+				return 0, err
+			}
+			args[i] = v
+		}
+		switch n.Fun.(*ast.Ident).Name {
+		case "min":
+			res := args[0]
+			for _, v := range args[1:] {
+				res = math.Min(res, v)
+			}
+			return res, nil
+		case "max":
+			res := args[0]
+			for _, v := range args[1:] {
+				res = math.Max(res, v)
+			}
+			return res, nil
+		case "abs":
+			return math.Abs(args[0]), nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported expression %T", n)
+}
+
+// unit derives the unit of the expression from the units of its inputs.
+// Adding or subtracting values requires them to have the same unit,
+// numbers and inputs without a unit take on the unit of the other side.
+// Multiplying or dividing values results in a compound unit, like "m3*K", or no unit if the units cancel out.
+func (e *expr) unit(units map[string]string) (string, error) {
+	u, _, err := unitNode(e.root, units)
+	return u, err
+}
+
+// unitNode returns the unit of n and whether n has no unit of its own, adopting any unit it is combined with.
+func unitNode(n ast.Expr, units map[string]string) (string, bool, error) {
+	switch n := n.(type) {
+	case *ast.ParenExpr:
+		return unitNode(n.X, units)
+	case *ast.BasicLit:
+		return "", true, nil
+	case *ast.Ident:
+		u := units[n.Name]
+		return u, u == "", nil // inputs without a unit are treated like numbers
+	case *ast.UnaryExpr:
+		return unitNode(n.X, units)
+	case *ast.BinaryExpr:
+		x, xNum, err := unitNode(n.X, units)
+		if err != nil {
+			return "", false, err
+		}
+		y, yNum, err := unitNode(n.Y, units)
+		if err != nil {
+			return "", false, err
+		}
+		switch n.Op {
+		case token.ADD, token.SUB:
+			switch {
+			case xNum:
+				return y, yNum, nil
+			case yNum:
+				return x, false, nil
+			case x != y:
+				return "", false, fmt.Errorf("can't %s %q and %q", opVerb(n.Op), x, y)
+			}
+			return x, false, nil
+		case token.MUL:
+			switch {
+			case x == "":
+				return y, xNum && yNum, nil
+			case y == "":
+				return x, false, nil
+			}
+			return x + "*" + y, false, nil
+		case token.QUO:
+			switch {
+			case x == y:
+				return "", xNum && yNum, nil
+			case y == "":
+				return x, false, nil
+			case x == "":
+				return "1/" + y, false, nil
+			}
+			return x + "/" + y, false, nil
+		}
+	case *ast.CallExpr:
+		var unit string
+		num := true
+		for i, arg := range n.Args {
+			u, uNum, err := unitNode(arg, units)
+			if err != nil {
+				return "", false, err
+			}
+			if uNum {
+				continue
+			}
+			if !num && u != unit {
+				return "", false, fmt.Errorf("can't compare %q and %q in argument %d", unit, u, i+1)
+			}
+			unit, num = u, false
+		}
+		return unit, num, nil
+	}
+	return "", false, fmt.Errorf("unsupported expression %T", n)
+}
+
+func opVerb(op token.Token) string {
+	if op == token.ADD {
+		return "add"
+	}
+	return "subtract"
+}
+
+// isCompound returns true if unit was derived by multiplying or dividing other units.
+func isCompound(unit string) bool {
+	return strings.ContainsAny(unit, "*/")
+}
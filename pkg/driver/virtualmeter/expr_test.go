@@ -0,0 +1,88 @@
+package virtualmeter
+
+import (
+	"go/parser"
+	"math"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+	vars := map[string]bool{"incomer": true, "a": true, "b": true}
+	e, err := parseExpr("incomer - (a + b) * 1.0", vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := e.eval(map[string]float64{"incomer": 100, "a": 30, "b": 20.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 49.5 {
+		t.Fatalf("eval got %v, want 49.5", got)
+	}
+
+	e, err = parseExpr("max(a - b, 0) / abs(-b)", map[string]bool{"a": true, "b": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = e.eval(map[string]float64{"a": 30, "b": 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got-0.5) > 1e-9 {
+		t.Fatalf("eval got %v, want 0.5", got)
+	}
+	if _, err := e.eval(map[string]float64{"a": 30, "b": 0}); err == nil {
+		t.Fatal("expected division by zero error")
+	}
+}
+
+func TestParseExpr_invalid(t *testing.T) {
+	vars := map[string]bool{"a": true, "b": true}
+	for _, src := range []string{
+		"a - ",
+		"a - c",        // unknown input
+		"a",            // b not used
+		"a % b",        // unsupported operator
+		"a.x + b",      // selectors
+		"pow(a, b)",    // unknown function
+		"a + b + \"\"", // strings
+	} {
+		if _, err := parseExpr(src, vars); err == nil {
+			t.Errorf("parseExpr(%q) expected error", src)
+		}
+	}
+}
+
+func TestExpr_unit(t *testing.T) {
+	units := map[string]string{"incomer": "kWh", "tenant": "kWh", "water": "m3", "it": "kWh", "pf": ""}
+	tests := []struct {
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{src: "incomer - tenant", want: "kWh"},
+		{src: "incomer * 0.9 - 10", want: "kWh"},
+		{src: "incomer / it", want: ""},
+		{src: "incomer * pf", want: "kWh"},
+		{src: "water * incomer", want: "m3*kWh"},
+		{src: "incomer / water", want: "kWh/m3"},
+		{src: "max(incomer, tenant, 0)", want: "kWh"},
+		{src: "incomer - water", wantErr: true},
+		{src: "min(incomer, water)", wantErr: true},
+	}
+	for _, tt := range tests {
+		root, err := parser.ParseExpr(tt.src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := &expr{src: tt.src, root: root}
+		got, err := e.unit(units)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("unit(%q) err %v, wantErr %v", tt.src, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("unit(%q) got %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package virtualmeter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/driver/virtualmeter/config"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/pull"
+)
+
+// sample is a value read from an input, or the error reading it.
+type sample struct {
+	input string // the identifier of the input in the expression
+	value float64
+	err   error
+}
+
+// inputUnit returns the unit of values read from in.
+func inputUnit(ctx context.Context, conn grpc.ClientConnInterface, in config.Input) (string, error) {
+	switch in.Trait {
+	case config.TraitMeter:
+		res, err := gen.NewMeterInfoClient(conn).DescribeMeterReading(ctx, &gen.DescribeMeterReadingRequest{Name: in.Name})
+		if err != nil {
+			return "", fmt.Errorf("describe %s: %w", in.Name, err)
+		}
+		return res.UsageUnit, nil
+	case config.TraitElectric:
+		return config.ElectricUnits[in.Field], nil
+	case config.TraitTemperature:
+		return "°C", nil
+	}
+	return "", fmt.Errorf("unsupported trait %q", in.Trait)
+}
+
+// watchInput sends samples of the input to changes until ctx is done.
+func watchInput(ctx context.Context, conn grpc.ClientConnInterface, id string, in config.Input, changes chan<- sample, opts ...pull.Option) error {
+	var fetcher pull.Fetcher[sample]
+	switch in.Trait {
+	case config.TraitMeter:
+		fetcher = meterFetcher(gen.NewMeterApiClient(conn), id, in)
+	case config.TraitElectric:
+		fetcher = electricFetcher(traits.NewElectricApiClient(conn), id, in)
+	case config.TraitTemperature:
+		fetcher = temperatureFetcher(traits.NewAirTemperatureApiClient(conn), id, in)
+	default:
+		return fmt.Errorf("unsupported trait %q", in.Trait)
+	}
+	return pull.Changes(ctx, fetcher, changes, opts...)
+}
+
+// send sends s to changes unless ctx is done first.
+func send(ctx context.Context, changes chan<- sample, s sample) {
+	select {
+	case <-ctx.Done():
+	case changes <- s:
+	}
+}
+
+func meterFetcher(client gen.MeterApiClient, id string, in config.Input) pull.Fetcher[sample] {
+	return pull.NewFetcher(
+		func(ctx context.Context, changes chan<- sample) error {
+			stream, err := client.PullMeterReadings(ctx, &gen.PullMeterReadingsRequest{Name: in.Name})
+			if err != nil {
+				send(ctx, changes, sample{input: id, err: err})
+				return err
+			}
+			for {
+				res, err := stream.Recv()
+				if err != nil {
+					send(ctx, changes, sample{input: id, err: err})
+					return err
+				}
+				for _, change := range res.Changes {
+					send(ctx, changes, sample{input: id, value: float64(change.GetMeterReading().GetUsage())})
+				}
+			}
+		},
+		func(ctx context.Context, changes chan<- sample) error {
+			res, err := client.GetMeterReading(ctx, &gen.GetMeterReadingRequest{Name: in.Name})
+			if err != nil {
+				send(ctx, changes, sample{input: id, err: err})
+				return err
+			}
+			send(ctx, changes, sample{input: id, value: float64(res.Usage)})
+			return nil
+		},
+	)
+}
+
+func electricFetcher(client traits.ElectricApiClient, id string, in config.Input) pull.Fetcher[sample] {
+	toSample := func(d *traits.ElectricDemand) sample {
+		v, ok := demandField(d, in.Field)
+		if !ok {
+			return sample{input: id, err: fmt.Errorf("%s has no %s", in.Name, in.Field)}
+		}
+		return sample{input: id, value: v}
+	}
+	return pull.NewFetcher(
+		func(ctx context.Context, changes chan<- sample) error {
+			stream, err := client.PullDemand(ctx, &traits.PullDemandRequest{Name: in.Name})
+			if err != nil {
+				send(ctx, changes, sample{input: id, err: err})
+				return err
+			}
+			for {
+				res, err := stream.Recv()
+				if err != nil {
+					send(ctx, changes, sample{input: id, err: err})
+					return err
+				}
+				for _, change := range res.Changes {
+					send(ctx, changes, toSample(change.Demand))
+				}
+			}
+		},
+		func(ctx context.Context, changes chan<- sample) error {
+			res, err := client.GetDemand(ctx, &traits.GetDemandRequest{Name: in.Name})
+			if err != nil {
+				send(ctx, changes, sample{input: id, err: err})
+				return err
+			}
+			send(ctx, changes, toSample(res))
+			return nil
+		},
+	)
+}
+
+func temperatureFetcher(client traits.AirTemperatureApiClient, id string, in config.Input) pull.Fetcher[sample] {
+	toSample := func(t *traits.AirTemperature) sample {
+		if t.GetAmbientTemperature() == nil {
+			return sample{input: id, err: fmt.Errorf("%s has no ambient temperature", in.Name)}
+		}
+		return sample{input: id, value: t.GetAmbientTemperature().GetValueCelsius()}
+	}
+	return pull.NewFetcher(
+		func(ctx context.Context, changes chan<- sample) error {
+			stream, err := client.PullAirTemperature(ctx, &traits.PullAirTemperatureRequest{Name: in.Name})
+			if err != nil {
+				send(ctx, changes, sample{input: id, err: err})
+				return err
+			}
+			for {
+				res, err := stream.Recv()
+				if err != nil {
+					send(ctx, changes, sample{input: id, err: err})
+					return err
+				}
+				for _, change := range res.Changes {
+					send(ctx, changes, toSample(change.AirTemperature))
+				}
+			}
+		},
+		func(ctx context.Context, changes chan<- sample) error {
+			res, err := client.GetAirTemperature(ctx, &traits.GetAirTemperatureRequest{Name: in.Name})
+			if err != nil {
+				send(ctx, changes, sample{input: id, err: err})
+				return err
+			}
+			send(ctx, changes, toSample(res))
+			return nil
+		},
+	)
+}
+
+// demandField returns the value of the named field of d, and whether it is set.
+func demandField(d *traits.ElectricDemand, field string) (float64, bool) {
+	opt := func(v *float32) (float64, bool) {
+		if v == nil {
+			return 0, false
+		}
+		return float64(*v), true
+	}
+	switch field {
+	case config.FieldRealPower:
+		return opt(d.RealPower)
+	case config.FieldApparentPower:
+		return opt(d.ApparentPower)
+	case config.FieldReactivePower:
+		return opt(d.ReactivePower)
+	case config.FieldPowerFactor:
+		return opt(d.PowerFactor)
+	case config.FieldVoltage:
+		return opt(d.Voltage)
+	case config.FieldCurrent:
+		return float64(d.GetCurrent()), true
+	}
+	return 0, false
+}
+
+// setDemandField sets the named field of d to v.
+func setDemandField(d *traits.ElectricDemand, field string, v float64) {
+	f := float32(v)
+	switch field {
+	case config.FieldRealPower:
+		d.RealPower = &f
+	case config.FieldApparentPower:
+		d.ApparentPower = &f
+	case config.FieldReactivePower:
+		d.ReactivePower = &f
+	case config.FieldPowerFactor:
+		d.PowerFactor = &f
+	case config.FieldVoltage:
+		d.Voltage = &f
+	case config.FieldCurrent:
+		d.Current = f
+	}
+}
+
+// demandPath returns the proto field path of the named demand field.
+func demandPath(field string) string {
+	switch field {
+	case config.FieldRealPower:
+		return "real_power"
+	case config.FieldApparentPower:
+		return "apparent_power"
+	case config.FieldReactivePower:
+		return "reactive_power"
+	case config.FieldPowerFactor:
+		return "power_factor"
+	}
+	return field // current and voltage are the same
+}
+
+// inputState tracks the latest sample of an input.
+type inputState struct {
+	value float64
+	has   bool      // whether value has ever been set
+	at    time.Time // when value was last read successfully
+	err   error     // the error reading the input since value was read, if any
+}
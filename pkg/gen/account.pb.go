@@ -69,7 +69,7 @@ func (x Account_Type) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Account_Type.Descriptor instead.
 func (Account_Type) EnumDescriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{28, 0}
+	return file_account_proto_rawDescGZIP(), []int{38, 0}
 }
 
 type RoleAssignment_ResourceType int32
@@ -82,16 +82,16 @@ const (
 	// by a '/'.
 	// Example:
 	//
-	//	Scope {
-	//	  resource_type: NAMED_RESOURCE_PATH_PREFIX
-	//	  resource: "foo/bar"
-	//	}
+	//    Scope {
+	//      resource_type: NAMED_RESOURCE_PATH_PREFIX
+	//      resource: "foo/bar"
+	//    }
 	//
-	//	Matches resources with Smart Core names:
-	//	  - foo/bar
-	//	  - foo/bar/baz
-	//	Does not match:
-	//	  - foo/barbaz
+	//    Matches resources with Smart Core names:
+	//      - foo/bar
+	//      - foo/bar/baz
+	//    Does not match:
+	//      - foo/barbaz
 	RoleAssignment_NAMED_RESOURCE_PATH_PREFIX RoleAssignment_ResourceType = 2
 	// Matches resources advertised by the Smart Core node with the given name.
 	RoleAssignment_NODE RoleAssignment_ResourceType = 3
@@ -145,7 +145,7 @@ func (x RoleAssignment_ResourceType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use RoleAssignment_ResourceType.Descriptor instead.
 func (RoleAssignment_ResourceType) EnumDescriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{32, 0}
+	return file_account_proto_rawDescGZIP(), []int{42, 0}
 }
 
 type GetAccountRequest struct {
@@ -782,6 +782,508 @@ func (*DeleteAccountResponse) Descriptor() ([]byte, []int) {
 	return file_account_proto_rawDescGZIP(), []int{10}
 }
 
+type BeginTotpEnrollmentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node where the account is located.
+	// Optional - if absent, the node you are connected to is assumed.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The id of the account to enroll. Must be a user account.
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginTotpEnrollmentRequest) Reset() {
+	*x = BeginTotpEnrollmentRequest{}
+	mi := &file_account_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginTotpEnrollmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginTotpEnrollmentRequest) ProtoMessage() {}
+
+func (x *BeginTotpEnrollmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginTotpEnrollmentRequest.ProtoReflect.Descriptor instead.
+func (*BeginTotpEnrollmentRequest) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BeginTotpEnrollmentRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BeginTotpEnrollmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type BeginTotpEnrollmentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The shared secret, base32 encoded, for manual entry into an authenticator app.
+	Secret string `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// An otpauth:// URI containing the secret, typically presented to the user as a QR code.
+	Uri           string `protobuf:"bytes,2,opt,name=uri,proto3" json:"uri,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginTotpEnrollmentResponse) Reset() {
+	*x = BeginTotpEnrollmentResponse{}
+	mi := &file_account_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginTotpEnrollmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginTotpEnrollmentResponse) ProtoMessage() {}
+
+func (x *BeginTotpEnrollmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginTotpEnrollmentResponse.ProtoReflect.Descriptor instead.
+func (*BeginTotpEnrollmentResponse) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BeginTotpEnrollmentResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *BeginTotpEnrollmentResponse) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+type ConfirmTotpEnrollmentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node where the account is located.
+	// Optional - if absent, the node you are connected to is assumed.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The id of the account with a pending enrollment.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// A code generated by the authenticator app using the secret from BeginTotpEnrollment.
+	Code          string `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTotpEnrollmentRequest) Reset() {
+	*x = ConfirmTotpEnrollmentRequest{}
+	mi := &file_account_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTotpEnrollmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTotpEnrollmentRequest) ProtoMessage() {}
+
+func (x *ConfirmTotpEnrollmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTotpEnrollmentRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmTotpEnrollmentRequest) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ConfirmTotpEnrollmentRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ConfirmTotpEnrollmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ConfirmTotpEnrollmentRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ConfirmTotpEnrollmentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Single use codes that can be used in place of a one-time password.
+	// These are only returned once, and cannot be retrieved later.
+	RecoveryCodes []string `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTotpEnrollmentResponse) Reset() {
+	*x = ConfirmTotpEnrollmentResponse{}
+	mi := &file_account_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTotpEnrollmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTotpEnrollmentResponse) ProtoMessage() {}
+
+func (x *ConfirmTotpEnrollmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTotpEnrollmentResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmTotpEnrollmentResponse) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ConfirmTotpEnrollmentResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node where the account is located.
+	// Optional - if absent, the node you are connected to is assumed.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The id of the account to regenerate codes for. The account must have TOTP enabled.
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegenerateRecoveryCodesRequest) Reset() {
+	*x = RegenerateRecoveryCodesRequest{}
+	mi := &file_account_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegenerateRecoveryCodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegenerateRecoveryCodesRequest) ProtoMessage() {}
+
+func (x *RegenerateRecoveryCodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegenerateRecoveryCodesRequest.ProtoReflect.Descriptor instead.
+func (*RegenerateRecoveryCodesRequest) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RegenerateRecoveryCodesRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegenerateRecoveryCodesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RegenerateRecoveryCodesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Single use codes that can be used in place of a one-time password.
+	// These are only returned once, and cannot be retrieved later.
+	RecoveryCodes []string `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegenerateRecoveryCodesResponse) Reset() {
+	*x = RegenerateRecoveryCodesResponse{}
+	mi := &file_account_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegenerateRecoveryCodesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegenerateRecoveryCodesResponse) ProtoMessage() {}
+
+func (x *RegenerateRecoveryCodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegenerateRecoveryCodesResponse.ProtoReflect.Descriptor instead.
+func (*RegenerateRecoveryCodesResponse) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RegenerateRecoveryCodesResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+type ResetAccountMfaRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node where the account is located.
+	// Optional - if absent, the node you are connected to is assumed.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The id of the account to reset.
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetAccountMfaRequest) Reset() {
+	*x = ResetAccountMfaRequest{}
+	mi := &file_account_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetAccountMfaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetAccountMfaRequest) ProtoMessage() {}
+
+func (x *ResetAccountMfaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetAccountMfaRequest.ProtoReflect.Descriptor instead.
+func (*ResetAccountMfaRequest) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ResetAccountMfaRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ResetAccountMfaRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ResetAccountMfaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetAccountMfaResponse) Reset() {
+	*x = ResetAccountMfaResponse{}
+	mi := &file_account_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetAccountMfaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetAccountMfaResponse) ProtoMessage() {}
+
+func (x *ResetAccountMfaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetAccountMfaResponse.ProtoReflect.Descriptor instead.
+func (*ResetAccountMfaResponse) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{18}
+}
+
+type UnlockAccountRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node where the account is located.
+	// Optional - if absent, the node you are connected to is assumed.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The id of the account to unlock.
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockAccountRequest) Reset() {
+	*x = UnlockAccountRequest{}
+	mi := &file_account_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockAccountRequest) ProtoMessage() {}
+
+func (x *UnlockAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockAccountRequest.ProtoReflect.Descriptor instead.
+func (*UnlockAccountRequest) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UnlockAccountRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UnlockAccountRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UnlockAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockAccountResponse) Reset() {
+	*x = UnlockAccountResponse{}
+	mi := &file_account_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockAccountResponse) ProtoMessage() {}
+
+func (x *UnlockAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockAccountResponse.ProtoReflect.Descriptor instead.
+func (*UnlockAccountResponse) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{20}
+}
+
 type GetRoleRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The name of the node where the role is located.
@@ -795,7 +1297,7 @@ type GetRoleRequest struct {
 
 func (x *GetRoleRequest) Reset() {
 	*x = GetRoleRequest{}
-	mi := &file_account_proto_msgTypes[11]
+	mi := &file_account_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -807,7 +1309,7 @@ func (x *GetRoleRequest) String() string {
 func (*GetRoleRequest) ProtoMessage() {}
 
 func (x *GetRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[11]
+	mi := &file_account_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -820,7 +1322,7 @@ func (x *GetRoleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRoleRequest.ProtoReflect.Descriptor instead.
 func (*GetRoleRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{11}
+	return file_account_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *GetRoleRequest) GetName() string {
@@ -852,7 +1354,7 @@ type ListRolesRequest struct {
 
 func (x *ListRolesRequest) Reset() {
 	*x = ListRolesRequest{}
-	mi := &file_account_proto_msgTypes[12]
+	mi := &file_account_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -864,7 +1366,7 @@ func (x *ListRolesRequest) String() string {
 func (*ListRolesRequest) ProtoMessage() {}
 
 func (x *ListRolesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[12]
+	mi := &file_account_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -877,7 +1379,7 @@ func (x *ListRolesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRolesRequest.ProtoReflect.Descriptor instead.
 func (*ListRolesRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{12}
+	return file_account_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *ListRolesRequest) GetName() string {
@@ -916,7 +1418,7 @@ type ListRolesResponse struct {
 
 func (x *ListRolesResponse) Reset() {
 	*x = ListRolesResponse{}
-	mi := &file_account_proto_msgTypes[13]
+	mi := &file_account_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -928,7 +1430,7 @@ func (x *ListRolesResponse) String() string {
 func (*ListRolesResponse) ProtoMessage() {}
 
 func (x *ListRolesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[13]
+	mi := &file_account_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -941,7 +1443,7 @@ func (x *ListRolesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRolesResponse.ProtoReflect.Descriptor instead.
 func (*ListRolesResponse) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{13}
+	return file_account_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *ListRolesResponse) GetRoles() []*Role {
@@ -977,7 +1479,7 @@ type CreateRoleRequest struct {
 
 func (x *CreateRoleRequest) Reset() {
 	*x = CreateRoleRequest{}
-	mi := &file_account_proto_msgTypes[14]
+	mi := &file_account_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -989,7 +1491,7 @@ func (x *CreateRoleRequest) String() string {
 func (*CreateRoleRequest) ProtoMessage() {}
 
 func (x *CreateRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[14]
+	mi := &file_account_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1002,7 +1504,7 @@ func (x *CreateRoleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRoleRequest.ProtoReflect.Descriptor instead.
 func (*CreateRoleRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{14}
+	return file_account_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *CreateRoleRequest) GetName() string {
@@ -1034,7 +1536,7 @@ type UpdateRoleRequest struct {
 
 func (x *UpdateRoleRequest) Reset() {
 	*x = UpdateRoleRequest{}
-	mi := &file_account_proto_msgTypes[15]
+	mi := &file_account_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1046,7 +1548,7 @@ func (x *UpdateRoleRequest) String() string {
 func (*UpdateRoleRequest) ProtoMessage() {}
 
 func (x *UpdateRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[15]
+	mi := &file_account_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1059,7 +1561,7 @@ func (x *UpdateRoleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateRoleRequest.ProtoReflect.Descriptor instead.
 func (*UpdateRoleRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{15}
+	return file_account_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *UpdateRoleRequest) GetName() string {
@@ -1098,7 +1600,7 @@ type DeleteRoleRequest struct {
 
 func (x *DeleteRoleRequest) Reset() {
 	*x = DeleteRoleRequest{}
-	mi := &file_account_proto_msgTypes[16]
+	mi := &file_account_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1110,7 +1612,7 @@ func (x *DeleteRoleRequest) String() string {
 func (*DeleteRoleRequest) ProtoMessage() {}
 
 func (x *DeleteRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[16]
+	mi := &file_account_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1123,7 +1625,7 @@ func (x *DeleteRoleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteRoleRequest.ProtoReflect.Descriptor instead.
 func (*DeleteRoleRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{16}
+	return file_account_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *DeleteRoleRequest) GetName() string {
@@ -1155,7 +1657,7 @@ type DeleteRoleResponse struct {
 
 func (x *DeleteRoleResponse) Reset() {
 	*x = DeleteRoleResponse{}
-	mi := &file_account_proto_msgTypes[17]
+	mi := &file_account_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1167,7 +1669,7 @@ func (x *DeleteRoleResponse) String() string {
 func (*DeleteRoleResponse) ProtoMessage() {}
 
 func (x *DeleteRoleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[17]
+	mi := &file_account_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1180,7 +1682,7 @@ func (x *DeleteRoleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteRoleResponse.ProtoReflect.Descriptor instead.
 func (*DeleteRoleResponse) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{17}
+	return file_account_proto_rawDescGZIP(), []int{27}
 }
 
 type GetRoleAssignmentRequest struct {
@@ -1196,7 +1698,7 @@ type GetRoleAssignmentRequest struct {
 
 func (x *GetRoleAssignmentRequest) Reset() {
 	*x = GetRoleAssignmentRequest{}
-	mi := &file_account_proto_msgTypes[18]
+	mi := &file_account_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1208,7 +1710,7 @@ func (x *GetRoleAssignmentRequest) String() string {
 func (*GetRoleAssignmentRequest) ProtoMessage() {}
 
 func (x *GetRoleAssignmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[18]
+	mi := &file_account_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1221,7 +1723,7 @@ func (x *GetRoleAssignmentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRoleAssignmentRequest.ProtoReflect.Descriptor instead.
 func (*GetRoleAssignmentRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{18}
+	return file_account_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *GetRoleAssignmentRequest) GetName() string {
@@ -1250,9 +1752,8 @@ type ListRoleAssignmentsRequest struct {
 	// Expression to limit the RoleAssignments returned.
 	// If absent, all RoleAssignments are returned.
 	// Supported syntax:
-	//
-	//	'account_id = <id>' - return only RoleAssignments for the specified account
-	//	'role_id = <id>' - return only RoleAssignments for the specified role
+	//   'account_id = <id>' - return only RoleAssignments for the specified account
+	//   'role_id = <id>' - return only RoleAssignments for the specified role
 	//
 	// If a page_token is supplied, the filter must be the same as the filter used to get the page_token.
 	Filter        string `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
@@ -1262,7 +1763,7 @@ type ListRoleAssignmentsRequest struct {
 
 func (x *ListRoleAssignmentsRequest) Reset() {
 	*x = ListRoleAssignmentsRequest{}
-	mi := &file_account_proto_msgTypes[19]
+	mi := &file_account_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1274,7 +1775,7 @@ func (x *ListRoleAssignmentsRequest) String() string {
 func (*ListRoleAssignmentsRequest) ProtoMessage() {}
 
 func (x *ListRoleAssignmentsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[19]
+	mi := &file_account_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1287,7 +1788,7 @@ func (x *ListRoleAssignmentsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRoleAssignmentsRequest.ProtoReflect.Descriptor instead.
 func (*ListRoleAssignmentsRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{19}
+	return file_account_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *ListRoleAssignmentsRequest) GetName() string {
@@ -1333,7 +1834,7 @@ type ListRoleAssignmentsResponse struct {
 
 func (x *ListRoleAssignmentsResponse) Reset() {
 	*x = ListRoleAssignmentsResponse{}
-	mi := &file_account_proto_msgTypes[20]
+	mi := &file_account_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1345,7 +1846,7 @@ func (x *ListRoleAssignmentsResponse) String() string {
 func (*ListRoleAssignmentsResponse) ProtoMessage() {}
 
 func (x *ListRoleAssignmentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[20]
+	mi := &file_account_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1358,7 +1859,7 @@ func (x *ListRoleAssignmentsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRoleAssignmentsResponse.ProtoReflect.Descriptor instead.
 func (*ListRoleAssignmentsResponse) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{20}
+	return file_account_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *ListRoleAssignmentsResponse) GetRoleAssignments() []*RoleAssignment {
@@ -1395,7 +1896,7 @@ type CreateRoleAssignmentRequest struct {
 
 func (x *CreateRoleAssignmentRequest) Reset() {
 	*x = CreateRoleAssignmentRequest{}
-	mi := &file_account_proto_msgTypes[21]
+	mi := &file_account_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1407,7 +1908,7 @@ func (x *CreateRoleAssignmentRequest) String() string {
 func (*CreateRoleAssignmentRequest) ProtoMessage() {}
 
 func (x *CreateRoleAssignmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[21]
+	mi := &file_account_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1420,7 +1921,7 @@ func (x *CreateRoleAssignmentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRoleAssignmentRequest.ProtoReflect.Descriptor instead.
 func (*CreateRoleAssignmentRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{21}
+	return file_account_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *CreateRoleAssignmentRequest) GetName() string {
@@ -1452,7 +1953,7 @@ type DeleteRoleAssignmentRequest struct {
 
 func (x *DeleteRoleAssignmentRequest) Reset() {
 	*x = DeleteRoleAssignmentRequest{}
-	mi := &file_account_proto_msgTypes[22]
+	mi := &file_account_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1464,7 +1965,7 @@ func (x *DeleteRoleAssignmentRequest) String() string {
 func (*DeleteRoleAssignmentRequest) ProtoMessage() {}
 
 func (x *DeleteRoleAssignmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[22]
+	mi := &file_account_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1477,7 +1978,7 @@ func (x *DeleteRoleAssignmentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteRoleAssignmentRequest.ProtoReflect.Descriptor instead.
 func (*DeleteRoleAssignmentRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{22}
+	return file_account_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *DeleteRoleAssignmentRequest) GetName() string {
@@ -1509,7 +2010,7 @@ type DeleteRoleAssignmentResponse struct {
 
 func (x *DeleteRoleAssignmentResponse) Reset() {
 	*x = DeleteRoleAssignmentResponse{}
-	mi := &file_account_proto_msgTypes[23]
+	mi := &file_account_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1521,7 +2022,7 @@ func (x *DeleteRoleAssignmentResponse) String() string {
 func (*DeleteRoleAssignmentResponse) ProtoMessage() {}
 
 func (x *DeleteRoleAssignmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[23]
+	mi := &file_account_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1534,7 +2035,7 @@ func (x *DeleteRoleAssignmentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteRoleAssignmentResponse.ProtoReflect.Descriptor instead.
 func (*DeleteRoleAssignmentResponse) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{23}
+	return file_account_proto_rawDescGZIP(), []int{33}
 }
 
 type GetPermissionRequest struct {
@@ -1550,7 +2051,7 @@ type GetPermissionRequest struct {
 
 func (x *GetPermissionRequest) Reset() {
 	*x = GetPermissionRequest{}
-	mi := &file_account_proto_msgTypes[24]
+	mi := &file_account_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1562,7 +2063,7 @@ func (x *GetPermissionRequest) String() string {
 func (*GetPermissionRequest) ProtoMessage() {}
 
 func (x *GetPermissionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[24]
+	mi := &file_account_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1575,7 +2076,7 @@ func (x *GetPermissionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPermissionRequest.ProtoReflect.Descriptor instead.
 func (*GetPermissionRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{24}
+	return file_account_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *GetPermissionRequest) GetName() string {
@@ -1607,7 +2108,7 @@ type ListPermissionsRequest struct {
 
 func (x *ListPermissionsRequest) Reset() {
 	*x = ListPermissionsRequest{}
-	mi := &file_account_proto_msgTypes[25]
+	mi := &file_account_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1619,7 +2120,7 @@ func (x *ListPermissionsRequest) String() string {
 func (*ListPermissionsRequest) ProtoMessage() {}
 
 func (x *ListPermissionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[25]
+	mi := &file_account_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1632,7 +2133,7 @@ func (x *ListPermissionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListPermissionsRequest.ProtoReflect.Descriptor instead.
 func (*ListPermissionsRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{25}
+	return file_account_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *ListPermissionsRequest) GetName() string {
@@ -1670,7 +2171,7 @@ type ListPermissionsResponse struct {
 
 func (x *ListPermissionsResponse) Reset() {
 	*x = ListPermissionsResponse{}
-	mi := &file_account_proto_msgTypes[26]
+	mi := &file_account_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1682,7 +2183,7 @@ func (x *ListPermissionsResponse) String() string {
 func (*ListPermissionsResponse) ProtoMessage() {}
 
 func (x *ListPermissionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[26]
+	mi := &file_account_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1695,7 +2196,7 @@ func (x *ListPermissionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListPermissionsResponse.ProtoReflect.Descriptor instead.
 func (*ListPermissionsResponse) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{26}
+	return file_account_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *ListPermissionsResponse) GetPermissions() []*Permission {
@@ -1729,7 +2230,7 @@ type GetAccountLimitsRequest struct {
 
 func (x *GetAccountLimitsRequest) Reset() {
 	*x = GetAccountLimitsRequest{}
-	mi := &file_account_proto_msgTypes[27]
+	mi := &file_account_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1741,7 +2242,7 @@ func (x *GetAccountLimitsRequest) String() string {
 func (*GetAccountLimitsRequest) ProtoMessage() {}
 
 func (x *GetAccountLimitsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[27]
+	mi := &file_account_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1754,7 +2255,7 @@ func (x *GetAccountLimitsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAccountLimitsRequest.ProtoReflect.Descriptor instead.
 func (*GetAccountLimitsRequest) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{27}
+	return file_account_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *GetAccountLimitsRequest) GetName() string {
@@ -1795,7 +2296,7 @@ type Account struct {
 
 func (x *Account) Reset() {
 	*x = Account{}
-	mi := &file_account_proto_msgTypes[28]
+	mi := &file_account_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1807,7 +2308,7 @@ func (x *Account) String() string {
 func (*Account) ProtoMessage() {}
 
 func (x *Account) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[28]
+	mi := &file_account_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1820,7 +2321,7 @@ func (x *Account) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Account.ProtoReflect.Descriptor instead.
 func (*Account) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{28}
+	return file_account_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *Account) GetId() string {
@@ -1906,14 +2407,19 @@ type UserAccount struct {
 	// ASCII alphanumerics, and the following special characters are allowed: .-_@
 	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
 	// Output only. True if a password is set for this account.
-	HasPassword   bool `protobuf:"varint,2,opt,name=has_password,json=hasPassword,proto3" json:"has_password,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	HasPassword bool `protobuf:"varint,2,opt,name=has_password,json=hasPassword,proto3" json:"has_password,omitempty"`
+	// Output only. True if a TOTP one-time password is required, in addition to the password, to log in.
+	TotpEnabled bool `protobuf:"varint,3,opt,name=totp_enabled,json=totpEnabled,proto3" json:"totp_enabled,omitempty"`
+	// Output only. If present, too many failed login attempts have been made and the account can't be logged in to
+	// until this time, or until it is unlocked.
+	LockoutExpireTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=lockout_expire_time,json=lockoutExpireTime,proto3" json:"lockout_expire_time,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *UserAccount) Reset() {
 	*x = UserAccount{}
-	mi := &file_account_proto_msgTypes[29]
+	mi := &file_account_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1925,7 +2431,7 @@ func (x *UserAccount) String() string {
 func (*UserAccount) ProtoMessage() {}
 
 func (x *UserAccount) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[29]
+	mi := &file_account_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1938,7 +2444,7 @@ func (x *UserAccount) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserAccount.ProtoReflect.Descriptor instead.
 func (*UserAccount) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{29}
+	return file_account_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *UserAccount) GetUsername() string {
@@ -1955,6 +2461,20 @@ func (x *UserAccount) GetHasPassword() bool {
 	return false
 }
 
+func (x *UserAccount) GetTotpEnabled() bool {
+	if x != nil {
+		return x.TotpEnabled
+	}
+	return false
+}
+
+func (x *UserAccount) GetLockoutExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LockoutExpireTime
+	}
+	return nil
+}
+
 type ServiceAccount struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The OAuth2 Client ID to use when authenticating against this service account.
@@ -1973,7 +2493,7 @@ type ServiceAccount struct {
 
 func (x *ServiceAccount) Reset() {
 	*x = ServiceAccount{}
-	mi := &file_account_proto_msgTypes[30]
+	mi := &file_account_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1985,7 +2505,7 @@ func (x *ServiceAccount) String() string {
 func (*ServiceAccount) ProtoMessage() {}
 
 func (x *ServiceAccount) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[30]
+	mi := &file_account_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1998,7 +2518,7 @@ func (x *ServiceAccount) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServiceAccount.ProtoReflect.Descriptor instead.
 func (*ServiceAccount) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{30}
+	return file_account_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *ServiceAccount) GetClientId() string {
@@ -2047,7 +2567,7 @@ type Role struct {
 
 func (x *Role) Reset() {
 	*x = Role{}
-	mi := &file_account_proto_msgTypes[31]
+	mi := &file_account_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2059,7 +2579,7 @@ func (x *Role) String() string {
 func (*Role) ProtoMessage() {}
 
 func (x *Role) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[31]
+	mi := &file_account_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2072,7 +2592,7 @@ func (x *Role) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Role.ProtoReflect.Descriptor instead.
 func (*Role) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{31}
+	return file_account_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *Role) GetId() string {
@@ -2137,7 +2657,7 @@ type RoleAssignment struct {
 
 func (x *RoleAssignment) Reset() {
 	*x = RoleAssignment{}
-	mi := &file_account_proto_msgTypes[32]
+	mi := &file_account_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2149,7 +2669,7 @@ func (x *RoleAssignment) String() string {
 func (*RoleAssignment) ProtoMessage() {}
 
 func (x *RoleAssignment) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[32]
+	mi := &file_account_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2162,7 +2682,7 @@ func (x *RoleAssignment) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoleAssignment.ProtoReflect.Descriptor instead.
 func (*RoleAssignment) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{32}
+	return file_account_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *RoleAssignment) GetId() string {
@@ -2208,7 +2728,7 @@ type Permission struct {
 
 func (x *Permission) Reset() {
 	*x = Permission{}
-	mi := &file_account_proto_msgTypes[33]
+	mi := &file_account_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2220,7 +2740,7 @@ func (x *Permission) String() string {
 func (*Permission) ProtoMessage() {}
 
 func (x *Permission) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[33]
+	mi := &file_account_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2233,7 +2753,7 @@ func (x *Permission) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Permission.ProtoReflect.Descriptor instead.
 func (*Permission) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{33}
+	return file_account_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *Permission) GetId() string {
@@ -2269,7 +2789,7 @@ type AccountLimits struct {
 
 func (x *AccountLimits) Reset() {
 	*x = AccountLimits{}
-	mi := &file_account_proto_msgTypes[34]
+	mi := &file_account_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2281,7 +2801,7 @@ func (x *AccountLimits) String() string {
 func (*AccountLimits) ProtoMessage() {}
 
 func (x *AccountLimits) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[34]
+	mi := &file_account_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2294,7 +2814,7 @@ func (x *AccountLimits) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AccountLimits.ProtoReflect.Descriptor instead.
 func (*AccountLimits) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{34}
+	return file_account_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *AccountLimits) GetUsername() *AccountLimits_Field {
@@ -2335,7 +2855,7 @@ type RoleAssignment_Scope struct {
 
 func (x *RoleAssignment_Scope) Reset() {
 	*x = RoleAssignment_Scope{}
-	mi := &file_account_proto_msgTypes[35]
+	mi := &file_account_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2347,7 +2867,7 @@ func (x *RoleAssignment_Scope) String() string {
 func (*RoleAssignment_Scope) ProtoMessage() {}
 
 func (x *RoleAssignment_Scope) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[35]
+	mi := &file_account_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2360,7 +2880,7 @@ func (x *RoleAssignment_Scope) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoleAssignment_Scope.ProtoReflect.Descriptor instead.
 func (*RoleAssignment_Scope) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{32, 0}
+	return file_account_proto_rawDescGZIP(), []int{42, 0}
 }
 
 func (x *RoleAssignment_Scope) GetResourceType() RoleAssignment_ResourceType {
@@ -2387,7 +2907,7 @@ type AccountLimits_Field struct {
 
 func (x *AccountLimits_Field) Reset() {
 	*x = AccountLimits_Field{}
-	mi := &file_account_proto_msgTypes[36]
+	mi := &file_account_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2399,7 +2919,7 @@ func (x *AccountLimits_Field) String() string {
 func (*AccountLimits_Field) ProtoMessage() {}
 
 func (x *AccountLimits_Field) ProtoReflect() protoreflect.Message {
-	mi := &file_account_proto_msgTypes[36]
+	mi := &file_account_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2412,7 +2932,7 @@ func (x *AccountLimits_Field) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AccountLimits_Field.ProtoReflect.Descriptor instead.
 func (*AccountLimits_Field) Descriptor() ([]byte, []int) {
-	return file_account_proto_rawDescGZIP(), []int{34, 0}
+	return file_account_proto_rawDescGZIP(), []int{44, 0}
 }
 
 func (x *AccountLimits_Field) GetMinLength() int32 {
@@ -2472,7 +2992,32 @@ const file_account_proto_rawDesc = "" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\x12#\n" +
 	"\rallow_missing\x18\x03 \x01(\bR\fallowMissing\"\x17\n" +
-	"\x15DeleteAccountResponse\"4\n" +
+	"\x15DeleteAccountResponse\"@\n" +
+	"\x1aBeginTotpEnrollmentRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"G\n" +
+	"\x1bBeginTotpEnrollmentResponse\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12\x10\n" +
+	"\x03uri\x18\x02 \x01(\tR\x03uri\"V\n" +
+	"\x1cConfirmTotpEnrollmentRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\"F\n" +
+	"\x1dConfirmTotpEnrollmentResponse\x12%\n" +
+	"\x0erecovery_codes\x18\x01 \x03(\tR\rrecoveryCodes\"D\n" +
+	"\x1eRegenerateRecoveryCodesRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"H\n" +
+	"\x1fRegenerateRecoveryCodesResponse\x12%\n" +
+	"\x0erecovery_codes\x18\x01 \x03(\tR\rrecoveryCodes\"<\n" +
+	"\x16ResetAccountMfaRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"\x19\n" +
+	"\x17ResetAccountMfaResponse\":\n" +
+	"\x14UnlockAccountRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"\x17\n" +
+	"\x15UnlockAccountResponse\"4\n" +
 	"\x0eGetRoleRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\"b\n" +
@@ -2549,10 +3094,12 @@ const file_account_proto_rawDesc = "" +
 	"\x18ACCOUNT_TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
 	"\fUSER_ACCOUNT\x10\x01\x12\x13\n" +
 	"\x0fSERVICE_ACCOUNT\x10\x02B\t\n" +
-	"\adetails\"L\n" +
+	"\adetails\"\xbb\x01\n" +
 	"\vUserAccount\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12!\n" +
-	"\fhas_password\x18\x02 \x01(\bR\vhasPassword\"\xad\x01\n" +
+	"\fhas_password\x18\x02 \x01(\bR\vhasPassword\x12!\n" +
+	"\ftotp_enabled\x18\x03 \x01(\bR\vtotpEnabled\x12J\n" +
+	"\x13lockout_expire_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x11lockoutExpireTime\"\xad\x01\n" +
 	"\x0eServiceAccount\x12\x1b\n" +
 	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12#\n" +
 	"\rclient_secret\x18\x02 \x01(\tR\fclientSecret\x12Y\n" +
@@ -2594,7 +3141,7 @@ const file_account_proto_rawDesc = "" +
 	"\n" +
 	"min_length\x18\x01 \x01(\x05R\tminLength\x12\x1d\n" +
 	"\n" +
-	"max_length\x18\x02 \x01(\x05R\tmaxLengthJ\x04\b\x05\x10\x062\xa4\v\n" +
+	"max_length\x18\x02 \x01(\x05R\tmaxLengthJ\x04\b\x05\x10\x062\xbe\x0f\n" +
 	"\n" +
 	"AccountApi\x12F\n" +
 	"\n" +
@@ -2604,7 +3151,12 @@ const file_account_proto_rawDesc = "" +
 	"\rUpdateAccount\x12#.smartcore.bos.UpdateAccountRequest\x1a\x16.smartcore.bos.Account\x12r\n" +
 	"\x15UpdateAccountPassword\x12+.smartcore.bos.UpdateAccountPasswordRequest\x1a,.smartcore.bos.UpdateAccountPasswordResponse\x12~\n" +
 	"\x19RotateAccountClientSecret\x12/.smartcore.bos.RotateAccountClientSecretRequest\x1a0.smartcore.bos.RotateAccountClientSecretResponse\x12Z\n" +
-	"\rDeleteAccount\x12#.smartcore.bos.DeleteAccountRequest\x1a$.smartcore.bos.DeleteAccountResponse\x12=\n" +
+	"\rDeleteAccount\x12#.smartcore.bos.DeleteAccountRequest\x1a$.smartcore.bos.DeleteAccountResponse\x12l\n" +
+	"\x13BeginTotpEnrollment\x12).smartcore.bos.BeginTotpEnrollmentRequest\x1a*.smartcore.bos.BeginTotpEnrollmentResponse\x12r\n" +
+	"\x15ConfirmTotpEnrollment\x12+.smartcore.bos.ConfirmTotpEnrollmentRequest\x1a,.smartcore.bos.ConfirmTotpEnrollmentResponse\x12x\n" +
+	"\x17RegenerateRecoveryCodes\x12-.smartcore.bos.RegenerateRecoveryCodesRequest\x1a..smartcore.bos.RegenerateRecoveryCodesResponse\x12`\n" +
+	"\x0fResetAccountMfa\x12%.smartcore.bos.ResetAccountMfaRequest\x1a&.smartcore.bos.ResetAccountMfaResponse\x12Z\n" +
+	"\rUnlockAccount\x12#.smartcore.bos.UnlockAccountRequest\x1a$.smartcore.bos.UnlockAccountResponse\x12=\n" +
 	"\aGetRole\x12\x1d.smartcore.bos.GetRoleRequest\x1a\x13.smartcore.bos.Role\x12N\n" +
 	"\tListRoles\x12\x1f.smartcore.bos.ListRolesRequest\x1a .smartcore.bos.ListRolesResponse\x12C\n" +
 	"\n" +
@@ -2635,7 +3187,7 @@ func file_account_proto_rawDescGZIP() []byte {
 }
 
 var file_account_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_account_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
+var file_account_proto_msgTypes = make([]protoimpl.MessageInfo, 47)
 var file_account_proto_goTypes = []any{
 	(Account_Type)(0),                         // 0: smartcore.bos.Account.Type
 	(RoleAssignment_ResourceType)(0),          // 1: smartcore.bos.RoleAssignment.ResourceType
@@ -2650,102 +3202,123 @@ var file_account_proto_goTypes = []any{
 	(*RotateAccountClientSecretResponse)(nil), // 10: smartcore.bos.RotateAccountClientSecretResponse
 	(*DeleteAccountRequest)(nil),              // 11: smartcore.bos.DeleteAccountRequest
 	(*DeleteAccountResponse)(nil),             // 12: smartcore.bos.DeleteAccountResponse
-	(*GetRoleRequest)(nil),                    // 13: smartcore.bos.GetRoleRequest
-	(*ListRolesRequest)(nil),                  // 14: smartcore.bos.ListRolesRequest
-	(*ListRolesResponse)(nil),                 // 15: smartcore.bos.ListRolesResponse
-	(*CreateRoleRequest)(nil),                 // 16: smartcore.bos.CreateRoleRequest
-	(*UpdateRoleRequest)(nil),                 // 17: smartcore.bos.UpdateRoleRequest
-	(*DeleteRoleRequest)(nil),                 // 18: smartcore.bos.DeleteRoleRequest
-	(*DeleteRoleResponse)(nil),                // 19: smartcore.bos.DeleteRoleResponse
-	(*GetRoleAssignmentRequest)(nil),          // 20: smartcore.bos.GetRoleAssignmentRequest
-	(*ListRoleAssignmentsRequest)(nil),        // 21: smartcore.bos.ListRoleAssignmentsRequest
-	(*ListRoleAssignmentsResponse)(nil),       // 22: smartcore.bos.ListRoleAssignmentsResponse
-	(*CreateRoleAssignmentRequest)(nil),       // 23: smartcore.bos.CreateRoleAssignmentRequest
-	(*DeleteRoleAssignmentRequest)(nil),       // 24: smartcore.bos.DeleteRoleAssignmentRequest
-	(*DeleteRoleAssignmentResponse)(nil),      // 25: smartcore.bos.DeleteRoleAssignmentResponse
-	(*GetPermissionRequest)(nil),              // 26: smartcore.bos.GetPermissionRequest
-	(*ListPermissionsRequest)(nil),            // 27: smartcore.bos.ListPermissionsRequest
-	(*ListPermissionsResponse)(nil),           // 28: smartcore.bos.ListPermissionsResponse
-	(*GetAccountLimitsRequest)(nil),           // 29: smartcore.bos.GetAccountLimitsRequest
-	(*Account)(nil),                           // 30: smartcore.bos.Account
-	(*UserAccount)(nil),                       // 31: smartcore.bos.UserAccount
-	(*ServiceAccount)(nil),                    // 32: smartcore.bos.ServiceAccount
-	(*Role)(nil),                              // 33: smartcore.bos.Role
-	(*RoleAssignment)(nil),                    // 34: smartcore.bos.RoleAssignment
-	(*Permission)(nil),                        // 35: smartcore.bos.Permission
-	(*AccountLimits)(nil),                     // 36: smartcore.bos.AccountLimits
-	(*RoleAssignment_Scope)(nil),              // 37: smartcore.bos.RoleAssignment.Scope
-	(*AccountLimits_Field)(nil),               // 38: smartcore.bos.AccountLimits.Field
-	(*fieldmaskpb.FieldMask)(nil),             // 39: google.protobuf.FieldMask
-	(*timestamppb.Timestamp)(nil),             // 40: google.protobuf.Timestamp
+	(*BeginTotpEnrollmentRequest)(nil),        // 13: smartcore.bos.BeginTotpEnrollmentRequest
+	(*BeginTotpEnrollmentResponse)(nil),       // 14: smartcore.bos.BeginTotpEnrollmentResponse
+	(*ConfirmTotpEnrollmentRequest)(nil),      // 15: smartcore.bos.ConfirmTotpEnrollmentRequest
+	(*ConfirmTotpEnrollmentResponse)(nil),     // 16: smartcore.bos.ConfirmTotpEnrollmentResponse
+	(*RegenerateRecoveryCodesRequest)(nil),    // 17: smartcore.bos.RegenerateRecoveryCodesRequest
+	(*RegenerateRecoveryCodesResponse)(nil),   // 18: smartcore.bos.RegenerateRecoveryCodesResponse
+	(*ResetAccountMfaRequest)(nil),            // 19: smartcore.bos.ResetAccountMfaRequest
+	(*ResetAccountMfaResponse)(nil),           // 20: smartcore.bos.ResetAccountMfaResponse
+	(*UnlockAccountRequest)(nil),              // 21: smartcore.bos.UnlockAccountRequest
+	(*UnlockAccountResponse)(nil),             // 22: smartcore.bos.UnlockAccountResponse
+	(*GetRoleRequest)(nil),                    // 23: smartcore.bos.GetRoleRequest
+	(*ListRolesRequest)(nil),                  // 24: smartcore.bos.ListRolesRequest
+	(*ListRolesResponse)(nil),                 // 25: smartcore.bos.ListRolesResponse
+	(*CreateRoleRequest)(nil),                 // 26: smartcore.bos.CreateRoleRequest
+	(*UpdateRoleRequest)(nil),                 // 27: smartcore.bos.UpdateRoleRequest
+	(*DeleteRoleRequest)(nil),                 // 28: smartcore.bos.DeleteRoleRequest
+	(*DeleteRoleResponse)(nil),                // 29: smartcore.bos.DeleteRoleResponse
+	(*GetRoleAssignmentRequest)(nil),          // 30: smartcore.bos.GetRoleAssignmentRequest
+	(*ListRoleAssignmentsRequest)(nil),        // 31: smartcore.bos.ListRoleAssignmentsRequest
+	(*ListRoleAssignmentsResponse)(nil),       // 32: smartcore.bos.ListRoleAssignmentsResponse
+	(*CreateRoleAssignmentRequest)(nil),       // 33: smartcore.bos.CreateRoleAssignmentRequest
+	(*DeleteRoleAssignmentRequest)(nil),       // 34: smartcore.bos.DeleteRoleAssignmentRequest
+	(*DeleteRoleAssignmentResponse)(nil),      // 35: smartcore.bos.DeleteRoleAssignmentResponse
+	(*GetPermissionRequest)(nil),              // 36: smartcore.bos.GetPermissionRequest
+	(*ListPermissionsRequest)(nil),            // 37: smartcore.bos.ListPermissionsRequest
+	(*ListPermissionsResponse)(nil),           // 38: smartcore.bos.ListPermissionsResponse
+	(*GetAccountLimitsRequest)(nil),           // 39: smartcore.bos.GetAccountLimitsRequest
+	(*Account)(nil),                           // 40: smartcore.bos.Account
+	(*UserAccount)(nil),                       // 41: smartcore.bos.UserAccount
+	(*ServiceAccount)(nil),                    // 42: smartcore.bos.ServiceAccount
+	(*Role)(nil),                              // 43: smartcore.bos.Role
+	(*RoleAssignment)(nil),                    // 44: smartcore.bos.RoleAssignment
+	(*Permission)(nil),                        // 45: smartcore.bos.Permission
+	(*AccountLimits)(nil),                     // 46: smartcore.bos.AccountLimits
+	(*RoleAssignment_Scope)(nil),              // 47: smartcore.bos.RoleAssignment.Scope
+	(*AccountLimits_Field)(nil),               // 48: smartcore.bos.AccountLimits.Field
+	(*fieldmaskpb.FieldMask)(nil),             // 49: google.protobuf.FieldMask
+	(*timestamppb.Timestamp)(nil),             // 50: google.protobuf.Timestamp
 }
 var file_account_proto_depIdxs = []int32{
-	30, // 0: smartcore.bos.CreateAccountRequest.account:type_name -> smartcore.bos.Account
-	30, // 1: smartcore.bos.ListAccountsResponse.accounts:type_name -> smartcore.bos.Account
-	30, // 2: smartcore.bos.UpdateAccountRequest.account:type_name -> smartcore.bos.Account
-	39, // 3: smartcore.bos.UpdateAccountRequest.update_mask:type_name -> google.protobuf.FieldMask
-	40, // 4: smartcore.bos.RotateAccountClientSecretRequest.previous_secret_expire_time:type_name -> google.protobuf.Timestamp
-	33, // 5: smartcore.bos.ListRolesResponse.roles:type_name -> smartcore.bos.Role
-	33, // 6: smartcore.bos.CreateRoleRequest.role:type_name -> smartcore.bos.Role
-	33, // 7: smartcore.bos.UpdateRoleRequest.role:type_name -> smartcore.bos.Role
-	39, // 8: smartcore.bos.UpdateRoleRequest.update_mask:type_name -> google.protobuf.FieldMask
-	34, // 9: smartcore.bos.ListRoleAssignmentsResponse.role_assignments:type_name -> smartcore.bos.RoleAssignment
-	34, // 10: smartcore.bos.CreateRoleAssignmentRequest.role_assignment:type_name -> smartcore.bos.RoleAssignment
-	35, // 11: smartcore.bos.ListPermissionsResponse.permissions:type_name -> smartcore.bos.Permission
-	40, // 12: smartcore.bos.Account.create_time:type_name -> google.protobuf.Timestamp
+	40, // 0: smartcore.bos.CreateAccountRequest.account:type_name -> smartcore.bos.Account
+	40, // 1: smartcore.bos.ListAccountsResponse.accounts:type_name -> smartcore.bos.Account
+	40, // 2: smartcore.bos.UpdateAccountRequest.account:type_name -> smartcore.bos.Account
+	49, // 3: smartcore.bos.UpdateAccountRequest.update_mask:type_name -> google.protobuf.FieldMask
+	50, // 4: smartcore.bos.RotateAccountClientSecretRequest.previous_secret_expire_time:type_name -> google.protobuf.Timestamp
+	43, // 5: smartcore.bos.ListRolesResponse.roles:type_name -> smartcore.bos.Role
+	43, // 6: smartcore.bos.CreateRoleRequest.role:type_name -> smartcore.bos.Role
+	43, // 7: smartcore.bos.UpdateRoleRequest.role:type_name -> smartcore.bos.Role
+	49, // 8: smartcore.bos.UpdateRoleRequest.update_mask:type_name -> google.protobuf.FieldMask
+	44, // 9: smartcore.bos.ListRoleAssignmentsResponse.role_assignments:type_name -> smartcore.bos.RoleAssignment
+	44, // 10: smartcore.bos.CreateRoleAssignmentRequest.role_assignment:type_name -> smartcore.bos.RoleAssignment
+	45, // 11: smartcore.bos.ListPermissionsResponse.permissions:type_name -> smartcore.bos.Permission
+	50, // 12: smartcore.bos.Account.create_time:type_name -> google.protobuf.Timestamp
 	0,  // 13: smartcore.bos.Account.type:type_name -> smartcore.bos.Account.Type
-	31, // 14: smartcore.bos.Account.user_details:type_name -> smartcore.bos.UserAccount
-	32, // 15: smartcore.bos.Account.service_details:type_name -> smartcore.bos.ServiceAccount
-	40, // 16: smartcore.bos.ServiceAccount.previous_secret_expire_time:type_name -> google.protobuf.Timestamp
-	37, // 17: smartcore.bos.RoleAssignment.scope:type_name -> smartcore.bos.RoleAssignment.Scope
-	38, // 18: smartcore.bos.AccountLimits.username:type_name -> smartcore.bos.AccountLimits.Field
-	38, // 19: smartcore.bos.AccountLimits.password:type_name -> smartcore.bos.AccountLimits.Field
-	38, // 20: smartcore.bos.AccountLimits.display_name:type_name -> smartcore.bos.AccountLimits.Field
-	38, // 21: smartcore.bos.AccountLimits.description:type_name -> smartcore.bos.AccountLimits.Field
-	1,  // 22: smartcore.bos.RoleAssignment.Scope.resource_type:type_name -> smartcore.bos.RoleAssignment.ResourceType
-	2,  // 23: smartcore.bos.AccountApi.GetAccount:input_type -> smartcore.bos.GetAccountRequest
-	4,  // 24: smartcore.bos.AccountApi.ListAccounts:input_type -> smartcore.bos.ListAccountsRequest
-	3,  // 25: smartcore.bos.AccountApi.CreateAccount:input_type -> smartcore.bos.CreateAccountRequest
-	6,  // 26: smartcore.bos.AccountApi.UpdateAccount:input_type -> smartcore.bos.UpdateAccountRequest
-	7,  // 27: smartcore.bos.AccountApi.UpdateAccountPassword:input_type -> smartcore.bos.UpdateAccountPasswordRequest
-	9,  // 28: smartcore.bos.AccountApi.RotateAccountClientSecret:input_type -> smartcore.bos.RotateAccountClientSecretRequest
-	11, // 29: smartcore.bos.AccountApi.DeleteAccount:input_type -> smartcore.bos.DeleteAccountRequest
-	13, // 30: smartcore.bos.AccountApi.GetRole:input_type -> smartcore.bos.GetRoleRequest
-	14, // 31: smartcore.bos.AccountApi.ListRoles:input_type -> smartcore.bos.ListRolesRequest
-	16, // 32: smartcore.bos.AccountApi.CreateRole:input_type -> smartcore.bos.CreateRoleRequest
-	17, // 33: smartcore.bos.AccountApi.UpdateRole:input_type -> smartcore.bos.UpdateRoleRequest
-	18, // 34: smartcore.bos.AccountApi.DeleteRole:input_type -> smartcore.bos.DeleteRoleRequest
-	20, // 35: smartcore.bos.AccountApi.GetRoleAssignment:input_type -> smartcore.bos.GetRoleAssignmentRequest
-	21, // 36: smartcore.bos.AccountApi.ListRoleAssignments:input_type -> smartcore.bos.ListRoleAssignmentsRequest
-	23, // 37: smartcore.bos.AccountApi.CreateRoleAssignment:input_type -> smartcore.bos.CreateRoleAssignmentRequest
-	24, // 38: smartcore.bos.AccountApi.DeleteRoleAssignment:input_type -> smartcore.bos.DeleteRoleAssignmentRequest
-	26, // 39: smartcore.bos.AccountInfo.GetPermission:input_type -> smartcore.bos.GetPermissionRequest
-	27, // 40: smartcore.bos.AccountInfo.ListPermissions:input_type -> smartcore.bos.ListPermissionsRequest
-	29, // 41: smartcore.bos.AccountInfo.GetAccountLimits:input_type -> smartcore.bos.GetAccountLimitsRequest
-	30, // 42: smartcore.bos.AccountApi.GetAccount:output_type -> smartcore.bos.Account
-	5,  // 43: smartcore.bos.AccountApi.ListAccounts:output_type -> smartcore.bos.ListAccountsResponse
-	30, // 44: smartcore.bos.AccountApi.CreateAccount:output_type -> smartcore.bos.Account
-	30, // 45: smartcore.bos.AccountApi.UpdateAccount:output_type -> smartcore.bos.Account
-	8,  // 46: smartcore.bos.AccountApi.UpdateAccountPassword:output_type -> smartcore.bos.UpdateAccountPasswordResponse
-	10, // 47: smartcore.bos.AccountApi.RotateAccountClientSecret:output_type -> smartcore.bos.RotateAccountClientSecretResponse
-	12, // 48: smartcore.bos.AccountApi.DeleteAccount:output_type -> smartcore.bos.DeleteAccountResponse
-	33, // 49: smartcore.bos.AccountApi.GetRole:output_type -> smartcore.bos.Role
-	15, // 50: smartcore.bos.AccountApi.ListRoles:output_type -> smartcore.bos.ListRolesResponse
-	33, // 51: smartcore.bos.AccountApi.CreateRole:output_type -> smartcore.bos.Role
-	33, // 52: smartcore.bos.AccountApi.UpdateRole:output_type -> smartcore.bos.Role
-	19, // 53: smartcore.bos.AccountApi.DeleteRole:output_type -> smartcore.bos.DeleteRoleResponse
-	34, // 54: smartcore.bos.AccountApi.GetRoleAssignment:output_type -> smartcore.bos.RoleAssignment
-	22, // 55: smartcore.bos.AccountApi.ListRoleAssignments:output_type -> smartcore.bos.ListRoleAssignmentsResponse
-	34, // 56: smartcore.bos.AccountApi.CreateRoleAssignment:output_type -> smartcore.bos.RoleAssignment
-	25, // 57: smartcore.bos.AccountApi.DeleteRoleAssignment:output_type -> smartcore.bos.DeleteRoleAssignmentResponse
-	35, // 58: smartcore.bos.AccountInfo.GetPermission:output_type -> smartcore.bos.Permission
-	28, // 59: smartcore.bos.AccountInfo.ListPermissions:output_type -> smartcore.bos.ListPermissionsResponse
-	36, // 60: smartcore.bos.AccountInfo.GetAccountLimits:output_type -> smartcore.bos.AccountLimits
-	42, // [42:61] is the sub-list for method output_type
-	23, // [23:42] is the sub-list for method input_type
-	23, // [23:23] is the sub-list for extension type_name
-	23, // [23:23] is the sub-list for extension extendee
-	0,  // [0:23] is the sub-list for field type_name
+	41, // 14: smartcore.bos.Account.user_details:type_name -> smartcore.bos.UserAccount
+	42, // 15: smartcore.bos.Account.service_details:type_name -> smartcore.bos.ServiceAccount
+	50, // 16: smartcore.bos.UserAccount.lockout_expire_time:type_name -> google.protobuf.Timestamp
+	50, // 17: smartcore.bos.ServiceAccount.previous_secret_expire_time:type_name -> google.protobuf.Timestamp
+	47, // 18: smartcore.bos.RoleAssignment.scope:type_name -> smartcore.bos.RoleAssignment.Scope
+	48, // 19: smartcore.bos.AccountLimits.username:type_name -> smartcore.bos.AccountLimits.Field
+	48, // 20: smartcore.bos.AccountLimits.password:type_name -> smartcore.bos.AccountLimits.Field
+	48, // 21: smartcore.bos.AccountLimits.display_name:type_name -> smartcore.bos.AccountLimits.Field
+	48, // 22: smartcore.bos.AccountLimits.description:type_name -> smartcore.bos.AccountLimits.Field
+	1,  // 23: smartcore.bos.RoleAssignment.Scope.resource_type:type_name -> smartcore.bos.RoleAssignment.ResourceType
+	2,  // 24: smartcore.bos.AccountApi.GetAccount:input_type -> smartcore.bos.GetAccountRequest
+	4,  // 25: smartcore.bos.AccountApi.ListAccounts:input_type -> smartcore.bos.ListAccountsRequest
+	3,  // 26: smartcore.bos.AccountApi.CreateAccount:input_type -> smartcore.bos.CreateAccountRequest
+	6,  // 27: smartcore.bos.AccountApi.UpdateAccount:input_type -> smartcore.bos.UpdateAccountRequest
+	7,  // 28: smartcore.bos.AccountApi.UpdateAccountPassword:input_type -> smartcore.bos.UpdateAccountPasswordRequest
+	9,  // 29: smartcore.bos.AccountApi.RotateAccountClientSecret:input_type -> smartcore.bos.RotateAccountClientSecretRequest
+	11, // 30: smartcore.bos.AccountApi.DeleteAccount:input_type -> smartcore.bos.DeleteAccountRequest
+	13, // 31: smartcore.bos.AccountApi.BeginTotpEnrollment:input_type -> smartcore.bos.BeginTotpEnrollmentRequest
+	15, // 32: smartcore.bos.AccountApi.ConfirmTotpEnrollment:input_type -> smartcore.bos.ConfirmTotpEnrollmentRequest
+	17, // 33: smartcore.bos.AccountApi.RegenerateRecoveryCodes:input_type -> smartcore.bos.RegenerateRecoveryCodesRequest
+	19, // 34: smartcore.bos.AccountApi.ResetAccountMfa:input_type -> smartcore.bos.ResetAccountMfaRequest
+	21, // 35: smartcore.bos.AccountApi.UnlockAccount:input_type -> smartcore.bos.UnlockAccountRequest
+	23, // 36: smartcore.bos.AccountApi.GetRole:input_type -> smartcore.bos.GetRoleRequest
+	24, // 37: smartcore.bos.AccountApi.ListRoles:input_type -> smartcore.bos.ListRolesRequest
+	26, // 38: smartcore.bos.AccountApi.CreateRole:input_type -> smartcore.bos.CreateRoleRequest
+	27, // 39: smartcore.bos.AccountApi.UpdateRole:input_type -> smartcore.bos.UpdateRoleRequest
+	28, // 40: smartcore.bos.AccountApi.DeleteRole:input_type -> smartcore.bos.DeleteRoleRequest
+	30, // 41: smartcore.bos.AccountApi.GetRoleAssignment:input_type -> smartcore.bos.GetRoleAssignmentRequest
+	31, // 42: smartcore.bos.AccountApi.ListRoleAssignments:input_type -> smartcore.bos.ListRoleAssignmentsRequest
+	33, // 43: smartcore.bos.AccountApi.CreateRoleAssignment:input_type -> smartcore.bos.CreateRoleAssignmentRequest
+	34, // 44: smartcore.bos.AccountApi.DeleteRoleAssignment:input_type -> smartcore.bos.DeleteRoleAssignmentRequest
+	36, // 45: smartcore.bos.AccountInfo.GetPermission:input_type -> smartcore.bos.GetPermissionRequest
+	37, // 46: smartcore.bos.AccountInfo.ListPermissions:input_type -> smartcore.bos.ListPermissionsRequest
+	39, // 47: smartcore.bos.AccountInfo.GetAccountLimits:input_type -> smartcore.bos.GetAccountLimitsRequest
+	40, // 48: smartcore.bos.AccountApi.GetAccount:output_type -> smartcore.bos.Account
+	5,  // 49: smartcore.bos.AccountApi.ListAccounts:output_type -> smartcore.bos.ListAccountsResponse
+	40, // 50: smartcore.bos.AccountApi.CreateAccount:output_type -> smartcore.bos.Account
+	40, // 51: smartcore.bos.AccountApi.UpdateAccount:output_type -> smartcore.bos.Account
+	8,  // 52: smartcore.bos.AccountApi.UpdateAccountPassword:output_type -> smartcore.bos.UpdateAccountPasswordResponse
+	10, // 53: smartcore.bos.AccountApi.RotateAccountClientSecret:output_type -> smartcore.bos.RotateAccountClientSecretResponse
+	12, // 54: smartcore.bos.AccountApi.DeleteAccount:output_type -> smartcore.bos.DeleteAccountResponse
+	14, // 55: smartcore.bos.AccountApi.BeginTotpEnrollment:output_type -> smartcore.bos.BeginTotpEnrollmentResponse
+	16, // 56: smartcore.bos.AccountApi.ConfirmTotpEnrollment:output_type -> smartcore.bos.ConfirmTotpEnrollmentResponse
+	18, // 57: smartcore.bos.AccountApi.RegenerateRecoveryCodes:output_type -> smartcore.bos.RegenerateRecoveryCodesResponse
+	20, // 58: smartcore.bos.AccountApi.ResetAccountMfa:output_type -> smartcore.bos.ResetAccountMfaResponse
+	22, // 59: smartcore.bos.AccountApi.UnlockAccount:output_type -> smartcore.bos.UnlockAccountResponse
+	43, // 60: smartcore.bos.AccountApi.GetRole:output_type -> smartcore.bos.Role
+	25, // 61: smartcore.bos.AccountApi.ListRoles:output_type -> smartcore.bos.ListRolesResponse
+	43, // 62: smartcore.bos.AccountApi.CreateRole:output_type -> smartcore.bos.Role
+	43, // 63: smartcore.bos.AccountApi.UpdateRole:output_type -> smartcore.bos.Role
+	29, // 64: smartcore.bos.AccountApi.DeleteRole:output_type -> smartcore.bos.DeleteRoleResponse
+	44, // 65: smartcore.bos.AccountApi.GetRoleAssignment:output_type -> smartcore.bos.RoleAssignment
+	32, // 66: smartcore.bos.AccountApi.ListRoleAssignments:output_type -> smartcore.bos.ListRoleAssignmentsResponse
+	44, // 67: smartcore.bos.AccountApi.CreateRoleAssignment:output_type -> smartcore.bos.RoleAssignment
+	35, // 68: smartcore.bos.AccountApi.DeleteRoleAssignment:output_type -> smartcore.bos.DeleteRoleAssignmentResponse
+	45, // 69: smartcore.bos.AccountInfo.GetPermission:output_type -> smartcore.bos.Permission
+	38, // 70: smartcore.bos.AccountInfo.ListPermissions:output_type -> smartcore.bos.ListPermissionsResponse
+	46, // 71: smartcore.bos.AccountInfo.GetAccountLimits:output_type -> smartcore.bos.AccountLimits
+	48, // [48:72] is the sub-list for method output_type
+	24, // [24:48] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_account_proto_init() }
@@ -2753,7 +3326,7 @@ func file_account_proto_init() {
 	if File_account_proto != nil {
 		return
 	}
-	file_account_proto_msgTypes[28].OneofWrappers = []any{
+	file_account_proto_msgTypes[38].OneofWrappers = []any{
 		(*Account_UserDetails)(nil),
 		(*Account_ServiceDetails)(nil),
 	}
@@ -2763,7 +3336,7 @@ func file_account_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_account_proto_rawDesc), len(file_account_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   37,
+			NumMessages:   47,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
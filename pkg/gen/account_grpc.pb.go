@@ -26,6 +26,11 @@ const (
 	AccountApi_UpdateAccountPassword_FullMethodName     = "/smartcore.bos.AccountApi/UpdateAccountPassword"
 	AccountApi_RotateAccountClientSecret_FullMethodName = "/smartcore.bos.AccountApi/RotateAccountClientSecret"
 	AccountApi_DeleteAccount_FullMethodName             = "/smartcore.bos.AccountApi/DeleteAccount"
+	AccountApi_BeginTotpEnrollment_FullMethodName       = "/smartcore.bos.AccountApi/BeginTotpEnrollment"
+	AccountApi_ConfirmTotpEnrollment_FullMethodName     = "/smartcore.bos.AccountApi/ConfirmTotpEnrollment"
+	AccountApi_RegenerateRecoveryCodes_FullMethodName   = "/smartcore.bos.AccountApi/RegenerateRecoveryCodes"
+	AccountApi_ResetAccountMfa_FullMethodName           = "/smartcore.bos.AccountApi/ResetAccountMfa"
+	AccountApi_UnlockAccount_FullMethodName             = "/smartcore.bos.AccountApi/UnlockAccount"
 	AccountApi_GetRole_FullMethodName                   = "/smartcore.bos.AccountApi/GetRole"
 	AccountApi_ListRoles_FullMethodName                 = "/smartcore.bos.AccountApi/ListRoles"
 	AccountApi_CreateRole_FullMethodName                = "/smartcore.bos.AccountApi/CreateRole"
@@ -56,6 +61,25 @@ type AccountApiClient interface {
 	// grace period, it is immediately invalidated.
 	RotateAccountClientSecret(ctx context.Context, in *RotateAccountClientSecretRequest, opts ...grpc.CallOption) (*RotateAccountClientSecretResponse, error)
 	DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error)
+	// Starts enrollment of a TOTP authenticator as a second factor for a user account.
+	// The returned secret should be added to an authenticator app, then confirmed using ConfirmTotpEnrollment.
+	// Until confirmed the account can continue to log in using only its password.
+	// Calling this again before the enrollment is confirmed replaces the pending secret.
+	// If the account already has TOTP enabled, the request will fail with FAILED_PRECONDITION, use ResetAccountMfa first.
+	BeginTotpEnrollment(ctx context.Context, in *BeginTotpEnrollmentRequest, opts ...grpc.CallOption) (*BeginTotpEnrollmentResponse, error)
+	// Completes a pending TOTP enrollment, after which a one-time password is required to log in to the account.
+	// If the code is not valid for the pending secret, the request will fail with FAILED_PRECONDITION.
+	// Returns a new set of recovery codes for the account.
+	ConfirmTotpEnrollment(ctx context.Context, in *ConfirmTotpEnrollmentRequest, opts ...grpc.CallOption) (*ConfirmTotpEnrollmentResponse, error)
+	// Replaces all recovery codes for a user account with new ones.
+	// The new codes will only be returned once, and cannot be retrieved later.
+	RegenerateRecoveryCodes(ctx context.Context, in *RegenerateRecoveryCodesRequest, opts ...grpc.CallOption) (*RegenerateRecoveryCodesResponse, error)
+	// Removes any TOTP enrollment and recovery codes from a user account.
+	// Intended for administrators helping users who have lost access to their authenticator.
+	ResetAccountMfa(ctx context.Context, in *ResetAccountMfaRequest, opts ...grpc.CallOption) (*ResetAccountMfaResponse, error)
+	// Clears any login lockout and record of failed login attempts for a user account.
+	// User accounts are locked for a time after too many failed login attempts, see UserAccount.lockout_expire_time.
+	UnlockAccount(ctx context.Context, in *UnlockAccountRequest, opts ...grpc.CallOption) (*UnlockAccountResponse, error)
 	GetRole(ctx context.Context, in *GetRoleRequest, opts ...grpc.CallOption) (*Role, error)
 	ListRoles(ctx context.Context, in *ListRolesRequest, opts ...grpc.CallOption) (*ListRolesResponse, error)
 	CreateRole(ctx context.Context, in *CreateRoleRequest, opts ...grpc.CallOption) (*Role, error)
@@ -145,6 +169,56 @@ func (c *accountApiClient) DeleteAccount(ctx context.Context, in *DeleteAccountR
 	return out, nil
 }
 
+func (c *accountApiClient) BeginTotpEnrollment(ctx context.Context, in *BeginTotpEnrollmentRequest, opts ...grpc.CallOption) (*BeginTotpEnrollmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BeginTotpEnrollmentResponse)
+	err := c.cc.Invoke(ctx, AccountApi_BeginTotpEnrollment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountApiClient) ConfirmTotpEnrollment(ctx context.Context, in *ConfirmTotpEnrollmentRequest, opts ...grpc.CallOption) (*ConfirmTotpEnrollmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmTotpEnrollmentResponse)
+	err := c.cc.Invoke(ctx, AccountApi_ConfirmTotpEnrollment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountApiClient) RegenerateRecoveryCodes(ctx context.Context, in *RegenerateRecoveryCodesRequest, opts ...grpc.CallOption) (*RegenerateRecoveryCodesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegenerateRecoveryCodesResponse)
+	err := c.cc.Invoke(ctx, AccountApi_RegenerateRecoveryCodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountApiClient) ResetAccountMfa(ctx context.Context, in *ResetAccountMfaRequest, opts ...grpc.CallOption) (*ResetAccountMfaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResetAccountMfaResponse)
+	err := c.cc.Invoke(ctx, AccountApi_ResetAccountMfa_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountApiClient) UnlockAccount(ctx context.Context, in *UnlockAccountRequest, opts ...grpc.CallOption) (*UnlockAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockAccountResponse)
+	err := c.cc.Invoke(ctx, AccountApi_UnlockAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *accountApiClient) GetRole(ctx context.Context, in *GetRoleRequest, opts ...grpc.CallOption) (*Role, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Role)
@@ -254,6 +328,25 @@ type AccountApiServer interface {
 	// grace period, it is immediately invalidated.
 	RotateAccountClientSecret(context.Context, *RotateAccountClientSecretRequest) (*RotateAccountClientSecretResponse, error)
 	DeleteAccount(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error)
+	// Starts enrollment of a TOTP authenticator as a second factor for a user account.
+	// The returned secret should be added to an authenticator app, then confirmed using ConfirmTotpEnrollment.
+	// Until confirmed the account can continue to log in using only its password.
+	// Calling this again before the enrollment is confirmed replaces the pending secret.
+	// If the account already has TOTP enabled, the request will fail with FAILED_PRECONDITION, use ResetAccountMfa first.
+	BeginTotpEnrollment(context.Context, *BeginTotpEnrollmentRequest) (*BeginTotpEnrollmentResponse, error)
+	// Completes a pending TOTP enrollment, after which a one-time password is required to log in to the account.
+	// If the code is not valid for the pending secret, the request will fail with FAILED_PRECONDITION.
+	// Returns a new set of recovery codes for the account.
+	ConfirmTotpEnrollment(context.Context, *ConfirmTotpEnrollmentRequest) (*ConfirmTotpEnrollmentResponse, error)
+	// Replaces all recovery codes for a user account with new ones.
+	// The new codes will only be returned once, and cannot be retrieved later.
+	RegenerateRecoveryCodes(context.Context, *RegenerateRecoveryCodesRequest) (*RegenerateRecoveryCodesResponse, error)
+	// Removes any TOTP enrollment and recovery codes from a user account.
+	// Intended for administrators helping users who have lost access to their authenticator.
+	ResetAccountMfa(context.Context, *ResetAccountMfaRequest) (*ResetAccountMfaResponse, error)
+	// Clears any login lockout and record of failed login attempts for a user account.
+	// User accounts are locked for a time after too many failed login attempts, see UserAccount.lockout_expire_time.
+	UnlockAccount(context.Context, *UnlockAccountRequest) (*UnlockAccountResponse, error)
 	GetRole(context.Context, *GetRoleRequest) (*Role, error)
 	ListRoles(context.Context, *ListRolesRequest) (*ListRolesResponse, error)
 	CreateRole(context.Context, *CreateRoleRequest) (*Role, error)
@@ -294,6 +387,21 @@ func (UnimplementedAccountApiServer) RotateAccountClientSecret(context.Context,
 func (UnimplementedAccountApiServer) DeleteAccount(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccount not implemented")
 }
+func (UnimplementedAccountApiServer) BeginTotpEnrollment(context.Context, *BeginTotpEnrollmentRequest) (*BeginTotpEnrollmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BeginTotpEnrollment not implemented")
+}
+func (UnimplementedAccountApiServer) ConfirmTotpEnrollment(context.Context, *ConfirmTotpEnrollmentRequest) (*ConfirmTotpEnrollmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTotpEnrollment not implemented")
+}
+func (UnimplementedAccountApiServer) RegenerateRecoveryCodes(context.Context, *RegenerateRecoveryCodesRequest) (*RegenerateRecoveryCodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegenerateRecoveryCodes not implemented")
+}
+func (UnimplementedAccountApiServer) ResetAccountMfa(context.Context, *ResetAccountMfaRequest) (*ResetAccountMfaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetAccountMfa not implemented")
+}
+func (UnimplementedAccountApiServer) UnlockAccount(context.Context, *UnlockAccountRequest) (*UnlockAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockAccount not implemented")
+}
 func (UnimplementedAccountApiServer) GetRole(context.Context, *GetRoleRequest) (*Role, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRole not implemented")
 }
@@ -468,6 +576,96 @@ func _AccountApi_DeleteAccount_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AccountApi_BeginTotpEnrollment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginTotpEnrollmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountApiServer).BeginTotpEnrollment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountApi_BeginTotpEnrollment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountApiServer).BeginTotpEnrollment(ctx, req.(*BeginTotpEnrollmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountApi_ConfirmTotpEnrollment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmTotpEnrollmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountApiServer).ConfirmTotpEnrollment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountApi_ConfirmTotpEnrollment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountApiServer).ConfirmTotpEnrollment(ctx, req.(*ConfirmTotpEnrollmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountApi_RegenerateRecoveryCodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegenerateRecoveryCodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountApiServer).RegenerateRecoveryCodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountApi_RegenerateRecoveryCodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountApiServer).RegenerateRecoveryCodes(ctx, req.(*RegenerateRecoveryCodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountApi_ResetAccountMfa_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetAccountMfaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountApiServer).ResetAccountMfa(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountApi_ResetAccountMfa_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountApiServer).ResetAccountMfa(ctx, req.(*ResetAccountMfaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountApi_UnlockAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountApiServer).UnlockAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountApi_UnlockAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountApiServer).UnlockAccount(ctx, req.(*UnlockAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AccountApi_GetRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetRoleRequest)
 	if err := dec(in); err != nil {
@@ -665,6 +863,26 @@ var AccountApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteAccount",
 			Handler:    _AccountApi_DeleteAccount_Handler,
 		},
+		{
+			MethodName: "BeginTotpEnrollment",
+			Handler:    _AccountApi_BeginTotpEnrollment_Handler,
+		},
+		{
+			MethodName: "ConfirmTotpEnrollment",
+			Handler:    _AccountApi_ConfirmTotpEnrollment_Handler,
+		},
+		{
+			MethodName: "RegenerateRecoveryCodes",
+			Handler:    _AccountApi_RegenerateRecoveryCodes_Handler,
+		},
+		{
+			MethodName: "ResetAccountMfa",
+			Handler:    _AccountApi_ResetAccountMfa_Handler,
+		},
+		{
+			MethodName: "UnlockAccount",
+			Handler:    _AccountApi_UnlockAccount_Handler,
+		},
 		{
 			MethodName: "GetRole",
 			Handler:    _AccountApi_GetRole_Handler,
@@ -140,6 +140,51 @@ func (r *AccountApiRouter) DeleteAccount(ctx context.Context, request *DeleteAcc
 	return child.DeleteAccount(ctx, request)
 }
 
+func (r *AccountApiRouter) BeginTotpEnrollment(ctx context.Context, request *BeginTotpEnrollmentRequest) (*BeginTotpEnrollmentResponse, error) {
+	child, err := r.GetAccountApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.BeginTotpEnrollment(ctx, request)
+}
+
+func (r *AccountApiRouter) ConfirmTotpEnrollment(ctx context.Context, request *ConfirmTotpEnrollmentRequest) (*ConfirmTotpEnrollmentResponse, error) {
+	child, err := r.GetAccountApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.ConfirmTotpEnrollment(ctx, request)
+}
+
+func (r *AccountApiRouter) RegenerateRecoveryCodes(ctx context.Context, request *RegenerateRecoveryCodesRequest) (*RegenerateRecoveryCodesResponse, error) {
+	child, err := r.GetAccountApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.RegenerateRecoveryCodes(ctx, request)
+}
+
+func (r *AccountApiRouter) ResetAccountMfa(ctx context.Context, request *ResetAccountMfaRequest) (*ResetAccountMfaResponse, error) {
+	child, err := r.GetAccountApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.ResetAccountMfa(ctx, request)
+}
+
+func (r *AccountApiRouter) UnlockAccount(ctx context.Context, request *UnlockAccountRequest) (*UnlockAccountResponse, error) {
+	child, err := r.GetAccountApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.UnlockAccount(ctx, request)
+}
+
 func (r *AccountApiRouter) GetRole(ctx context.Context, request *GetRoleRequest) (*Role, error) {
 	child, err := r.GetAccountApiClient(request.Name)
 	if err != nil {
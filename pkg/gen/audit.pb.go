@@ -0,0 +1,893 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.32.1
+// source: audit.proto
+
+package gen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AuditEvent records a single request made to the node.
+type AuditEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A unique id identifying this event within the audit log.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The time the request was made.
+	RecordTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=record_time,json=recordTime,proto3" json:"record_time,omitempty"`
+	Actor      *AuditEvent_Actor      `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	// The fully qualified gRPC service the request targeted, for example "smartcore.traits.OnOffApi".
+	Service string `protobuf:"bytes,4,opt,name=service,proto3" json:"service,omitempty"`
+	// The gRPC method the request invoked, for example "UpdateOnOff".
+	Method string `protobuf:"bytes,5,opt,name=method,proto3" json:"method,omitempty"`
+	// The resource the request targeted, typically the name field of the request.
+	// Absent if the request has no name.
+	Resource string `protobuf:"bytes,6,opt,name=resource,proto3" json:"resource,omitempty"`
+	// A JSON summary of the request message.
+	// Sensitive fields, like passwords and secrets, are redacted.
+	// Large requests may be truncated, see request_truncated.
+	Request string `protobuf:"bytes,7,opt,name=request,proto3" json:"request,omitempty"`
+	// True if request was cut short because the request message was too large.
+	// A truncated request is not valid JSON.
+	RequestTruncated bool               `protobuf:"varint,9,opt,name=request_truncated,json=requestTruncated,proto3" json:"request_truncated,omitempty"`
+	Result           *AuditEvent_Result `protobuf:"bytes,8,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_audit_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuditEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetRecordTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordTime
+	}
+	return nil
+}
+
+func (x *AuditEvent) GetActor() *AuditEvent_Actor {
+	if x != nil {
+		return x.Actor
+	}
+	return nil
+}
+
+func (x *AuditEvent) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetRequest() string {
+	if x != nil {
+		return x.Request
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetRequestTruncated() bool {
+	if x != nil {
+		return x.RequestTruncated
+	}
+	return false
+}
+
+func (x *AuditEvent) GetResult() *AuditEvent_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ListAuditEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the device exposing this API.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Fields to fetch relative to the AuditEvent type
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// The maximum number of events to return.
+	// The service may return fewer than this value.
+	// If unspecified, at most 50 items will be returned.
+	// The maximum value is 1000; values above 1000 will be coerced to 1000.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// A page token, received from a previous `ListAuditEventsResponse` call.
+	// Provide this to retrieve the subsequent page.
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Query allows filtering of the events returned by this request.
+	// When paging the query should match for each page or INVALID_ARGUMENT will be returned.
+	Query *AuditEvent_Query `protobuf:"bytes,5,opt,name=query,proto3" json:"query,omitempty"`
+	// Specify the order of the returned events.
+	// The default is `record_time asc` - aka oldest event first.
+	// The format is `field_name [asc|desc]`, with asc being the default.
+	// Only `record_time` is supported.
+	OrderBy       string `protobuf:"bytes,6,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_audit_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRequest) ProtoMessage() {}
+
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListAuditEventsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *ListAuditEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetQuery() *AuditEvent_Query {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *ListAuditEventsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+type ListAuditEventsResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AuditEvents []*AuditEvent          `protobuf:"bytes,1,rep,name=audit_events,json=auditEvents,proto3" json:"audit_events,omitempty"`
+	// A token, which can be sent as `page_token` to retrieve the next page.
+	// If this field is omitted, there are no subsequent pages.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// If non-zero this is the total number of events matched by the query.
+	// This may be an estimate.
+	TotalSize     int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	mi := &file_audit_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsResponse) ProtoMessage() {}
+
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListAuditEventsResponse) GetAuditEvents() []*AuditEvent {
+	if x != nil {
+		return x.AuditEvents
+	}
+	return nil
+}
+
+func (x *ListAuditEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListAuditEventsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type PullAuditEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the device exposing this API.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Fields to fetch relative to the AuditEvent type
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// Query allows filtering of the events returned by this request.
+	// The record time bounds of the query are ignored.
+	Query         *AuditEvent_Query `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullAuditEventsRequest) Reset() {
+	*x = PullAuditEventsRequest{}
+	mi := &file_audit_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullAuditEventsRequest) ProtoMessage() {}
+
+func (x *PullAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*PullAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PullAuditEventsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PullAuditEventsRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *PullAuditEventsRequest) GetQuery() *AuditEvent_Query {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+type PullAuditEventsResponse struct {
+	state         protoimpl.MessageState            `protogen:"open.v1"`
+	Changes       []*PullAuditEventsResponse_Change `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullAuditEventsResponse) Reset() {
+	*x = PullAuditEventsResponse{}
+	mi := &file_audit_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullAuditEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullAuditEventsResponse) ProtoMessage() {}
+
+func (x *PullAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*PullAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PullAuditEventsResponse) GetChanges() []*PullAuditEventsResponse_Change {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+// Actor describes who made the request.
+type AuditEvent_Actor struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The subject of the access token used to make the request.
+	// Typically an account or client id.
+	Subject string `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	// A human readable name for the subject, if known.
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// True if the subject is an application acting on its own behalf.
+	IsService bool `protobuf:"varint,3,opt,name=is_service,json=isService,proto3" json:"is_service,omitempty"`
+	// Roles associated with the access token.
+	Roles []string `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`
+	// The common name of the client certificate presented with the request, if any.
+	CertificateSubject string `protobuf:"bytes,5,opt,name=certificate_subject,json=certificateSubject,proto3" json:"certificate_subject,omitempty"`
+	// The network address the request came from.
+	RemoteAddress string `protobuf:"bytes,6,opt,name=remote_address,json=remoteAddress,proto3" json:"remote_address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent_Actor) Reset() {
+	*x = AuditEvent_Actor{}
+	mi := &file_audit_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent_Actor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent_Actor) ProtoMessage() {}
+
+func (x *AuditEvent_Actor) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent_Actor.ProtoReflect.Descriptor instead.
+func (*AuditEvent_Actor) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *AuditEvent_Actor) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *AuditEvent_Actor) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *AuditEvent_Actor) GetIsService() bool {
+	if x != nil {
+		return x.IsService
+	}
+	return false
+}
+
+func (x *AuditEvent_Actor) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *AuditEvent_Actor) GetCertificateSubject() string {
+	if x != nil {
+		return x.CertificateSubject
+	}
+	return ""
+}
+
+func (x *AuditEvent_Actor) GetRemoteAddress() string {
+	if x != nil {
+		return x.RemoteAddress
+	}
+	return ""
+}
+
+// Result describes the outcome of the request.
+type AuditEvent_Result struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The gRPC status code, as defined by google.rpc.Code.
+	Code int32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	// The error message, absent for successful requests.
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// How long the request took to complete.
+	Duration      *durationpb.Duration `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent_Result) Reset() {
+	*x = AuditEvent_Result{}
+	mi := &file_audit_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent_Result) ProtoMessage() {}
+
+func (x *AuditEvent_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent_Result.ProtoReflect.Descriptor instead.
+func (*AuditEvent_Result) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *AuditEvent_Result) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *AuditEvent_Result) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AuditEvent_Result) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+// Query allows filtering for list and pull requests.
+// If multiple fields are present they are ANDed together.
+type AuditEvent_Query struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Don't return events that were recorded before this time.
+	RecordNotBefore *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=record_not_before,json=recordNotBefore,proto3" json:"record_not_before,omitempty"`
+	// Don't return events that were recorded after this time.
+	RecordNotAfter *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=record_not_after,json=recordNotAfter,proto3" json:"record_not_after,omitempty"`
+	// Only return events made by this actor subject.
+	Actor string `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	// Only return events that target this resource.
+	Resource string `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`
+	// Only return events that target resources starting with this prefix.
+	ResourcePrefix string `protobuf:"bytes,5,opt,name=resource_prefix,json=resourcePrefix,proto3" json:"resource_prefix,omitempty"`
+	// Only return events that target this service.
+	Service string `protobuf:"bytes,6,opt,name=service,proto3" json:"service,omitempty"`
+	// Only return events that invoke this method.
+	Method string `protobuf:"bytes,7,opt,name=method,proto3" json:"method,omitempty"`
+	// When true, only include events for requests that completed successfully.
+	// When false, only include events for requests that failed.
+	// When absent, the result does not affect whether an event is returned or not.
+	Success       *bool `protobuf:"varint,8,opt,name=success,proto3,oneof" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent_Query) Reset() {
+	*x = AuditEvent_Query{}
+	mi := &file_audit_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent_Query) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent_Query) ProtoMessage() {}
+
+func (x *AuditEvent_Query) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent_Query.ProtoReflect.Descriptor instead.
+func (*AuditEvent_Query) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{0, 2}
+}
+
+func (x *AuditEvent_Query) GetRecordNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordNotBefore
+	}
+	return nil
+}
+
+func (x *AuditEvent_Query) GetRecordNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordNotAfter
+	}
+	return nil
+}
+
+func (x *AuditEvent_Query) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditEvent_Query) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *AuditEvent_Query) GetResourcePrefix() string {
+	if x != nil {
+		return x.ResourcePrefix
+	}
+	return ""
+}
+
+func (x *AuditEvent_Query) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *AuditEvent_Query) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AuditEvent_Query) GetSuccess() bool {
+	if x != nil && x.Success != nil {
+		return *x.Success
+	}
+	return false
+}
+
+type PullAuditEventsResponse_Change struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the device that recorded the event.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The newly recorded event.
+	AuditEvent *AuditEvent `protobuf:"bytes,2,opt,name=audit_event,json=auditEvent,proto3" json:"audit_event,omitempty"`
+	// When the change occurred.
+	ChangeTime    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=change_time,json=changeTime,proto3" json:"change_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullAuditEventsResponse_Change) Reset() {
+	*x = PullAuditEventsResponse_Change{}
+	mi := &file_audit_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullAuditEventsResponse_Change) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullAuditEventsResponse_Change) ProtoMessage() {}
+
+func (x *PullAuditEventsResponse_Change) ProtoReflect() protoreflect.Message {
+	mi := &file_audit_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullAuditEventsResponse_Change.ProtoReflect.Descriptor instead.
+func (*PullAuditEventsResponse_Change) Descriptor() ([]byte, []int) {
+	return file_audit_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *PullAuditEventsResponse_Change) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PullAuditEventsResponse_Change) GetAuditEvent() *AuditEvent {
+	if x != nil {
+		return x.AuditEvent
+	}
+	return nil
+}
+
+func (x *PullAuditEventsResponse_Change) GetChangeTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangeTime
+	}
+	return nil
+}
+
+var File_audit_proto protoreflect.FileDescriptor
+
+const file_audit_proto_rawDesc = "" +
+	"\n" +
+	"\vaudit.proto\x12\rsmartcore.bos\x1a\x1egoogle/protobuf/duration.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf2\a\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12;\n" +
+	"\vrecord_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"recordTime\x125\n" +
+	"\x05actor\x18\x03 \x01(\v2\x1f.smartcore.bos.AuditEvent.ActorR\x05actor\x12\x18\n" +
+	"\aservice\x18\x04 \x01(\tR\aservice\x12\x16\n" +
+	"\x06method\x18\x05 \x01(\tR\x06method\x12\x1a\n" +
+	"\bresource\x18\x06 \x01(\tR\bresource\x12\x18\n" +
+	"\arequest\x18\a \x01(\tR\arequest\x12+\n" +
+	"\x11request_truncated\x18\t \x01(\bR\x10requestTruncated\x128\n" +
+	"\x06result\x18\b \x01(\v2 .smartcore.bos.AuditEvent.ResultR\x06result\x1a\xd1\x01\n" +
+	"\x05Actor\x12\x18\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"is_service\x18\x03 \x01(\bR\tisService\x12\x14\n" +
+	"\x05roles\x18\x04 \x03(\tR\x05roles\x12/\n" +
+	"\x13certificate_subject\x18\x05 \x01(\tR\x12certificateSubject\x12%\n" +
+	"\x0eremote_address\x18\x06 \x01(\tR\rremoteAddress\x1am\n" +
+	"\x06Result\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x125\n" +
+	"\bduration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bduration\x1a\xcd\x02\n" +
+	"\x05Query\x12F\n" +
+	"\x11record_not_before\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x0frecordNotBefore\x12D\n" +
+	"\x10record_not_after\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0erecordNotAfter\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x1a\n" +
+	"\bresource\x18\x04 \x01(\tR\bresource\x12'\n" +
+	"\x0fresource_prefix\x18\x05 \x01(\tR\x0eresourcePrefix\x12\x18\n" +
+	"\aservice\x18\x06 \x01(\tR\aservice\x12\x16\n" +
+	"\x06method\x18\a \x01(\tR\x06method\x12\x1d\n" +
+	"\asuccess\x18\b \x01(\bH\x00R\asuccess\x88\x01\x01B\n" +
+	"\n" +
+	"\b_success\"\xf3\x01\n" +
+	"\x16ListAuditEventsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x125\n" +
+	"\x05query\x18\x05 \x01(\v2\x1f.smartcore.bos.AuditEvent.QueryR\x05query\x12\x19\n" +
+	"\border_by\x18\x06 \x01(\tR\aorderBy\"\x9e\x01\n" +
+	"\x17ListAuditEventsResponse\x12<\n" +
+	"\faudit_events\x18\x01 \x03(\v2\x19.smartcore.bos.AuditEventR\vauditEvents\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"\x9c\x01\n" +
+	"\x16PullAuditEventsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x125\n" +
+	"\x05query\x18\x03 \x01(\v2\x1f.smartcore.bos.AuditEvent.QueryR\x05query\"\xfa\x01\n" +
+	"\x17PullAuditEventsResponse\x12G\n" +
+	"\achanges\x18\x01 \x03(\v2-.smartcore.bos.PullAuditEventsResponse.ChangeR\achanges\x1a\x95\x01\n" +
+	"\x06Change\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12:\n" +
+	"\vaudit_event\x18\x02 \x01(\v2\x19.smartcore.bos.AuditEventR\n" +
+	"auditEvent\x12;\n" +
+	"\vchange_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"changeTime2\xd0\x01\n" +
+	"\bAuditApi\x12`\n" +
+	"\x0fListAuditEvents\x12%.smartcore.bos.ListAuditEventsRequest\x1a&.smartcore.bos.ListAuditEventsResponse\x12b\n" +
+	"\x0fPullAuditEvents\x12%.smartcore.bos.PullAuditEventsRequest\x1a&.smartcore.bos.PullAuditEventsResponse0\x01B)Z'github.com/smart-core-os/sc-bos/pkg/genb\x06proto3"
+
+var (
+	file_audit_proto_rawDescOnce sync.Once
+	file_audit_proto_rawDescData []byte
+)
+
+func file_audit_proto_rawDescGZIP() []byte {
+	file_audit_proto_rawDescOnce.Do(func() {
+		file_audit_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_audit_proto_rawDesc), len(file_audit_proto_rawDesc)))
+	})
+	return file_audit_proto_rawDescData
+}
+
+var file_audit_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_audit_proto_goTypes = []any{
+	(*AuditEvent)(nil),                     // 0: smartcore.bos.AuditEvent
+	(*ListAuditEventsRequest)(nil),         // 1: smartcore.bos.ListAuditEventsRequest
+	(*ListAuditEventsResponse)(nil),        // 2: smartcore.bos.ListAuditEventsResponse
+	(*PullAuditEventsRequest)(nil),         // 3: smartcore.bos.PullAuditEventsRequest
+	(*PullAuditEventsResponse)(nil),        // 4: smartcore.bos.PullAuditEventsResponse
+	(*AuditEvent_Actor)(nil),               // 5: smartcore.bos.AuditEvent.Actor
+	(*AuditEvent_Result)(nil),              // 6: smartcore.bos.AuditEvent.Result
+	(*AuditEvent_Query)(nil),               // 7: smartcore.bos.AuditEvent.Query
+	(*PullAuditEventsResponse_Change)(nil), // 8: smartcore.bos.PullAuditEventsResponse.Change
+	(*timestamppb.Timestamp)(nil),          // 9: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),          // 10: google.protobuf.FieldMask
+	(*durationpb.Duration)(nil),            // 11: google.protobuf.Duration
+}
+var file_audit_proto_depIdxs = []int32{
+	9,  // 0: smartcore.bos.AuditEvent.record_time:type_name -> google.protobuf.Timestamp
+	5,  // 1: smartcore.bos.AuditEvent.actor:type_name -> smartcore.bos.AuditEvent.Actor
+	6,  // 2: smartcore.bos.AuditEvent.result:type_name -> smartcore.bos.AuditEvent.Result
+	10, // 3: smartcore.bos.ListAuditEventsRequest.read_mask:type_name -> google.protobuf.FieldMask
+	7,  // 4: smartcore.bos.ListAuditEventsRequest.query:type_name -> smartcore.bos.AuditEvent.Query
+	0,  // 5: smartcore.bos.ListAuditEventsResponse.audit_events:type_name -> smartcore.bos.AuditEvent
+	10, // 6: smartcore.bos.PullAuditEventsRequest.read_mask:type_name -> google.protobuf.FieldMask
+	7,  // 7: smartcore.bos.PullAuditEventsRequest.query:type_name -> smartcore.bos.AuditEvent.Query
+	8,  // 8: smartcore.bos.PullAuditEventsResponse.changes:type_name -> smartcore.bos.PullAuditEventsResponse.Change
+	11, // 9: smartcore.bos.AuditEvent.Result.duration:type_name -> google.protobuf.Duration
+	9,  // 10: smartcore.bos.AuditEvent.Query.record_not_before:type_name -> google.protobuf.Timestamp
+	9,  // 11: smartcore.bos.AuditEvent.Query.record_not_after:type_name -> google.protobuf.Timestamp
+	0,  // 12: smartcore.bos.PullAuditEventsResponse.Change.audit_event:type_name -> smartcore.bos.AuditEvent
+	9,  // 13: smartcore.bos.PullAuditEventsResponse.Change.change_time:type_name -> google.protobuf.Timestamp
+	1,  // 14: smartcore.bos.AuditApi.ListAuditEvents:input_type -> smartcore.bos.ListAuditEventsRequest
+	3,  // 15: smartcore.bos.AuditApi.PullAuditEvents:input_type -> smartcore.bos.PullAuditEventsRequest
+	2,  // 16: smartcore.bos.AuditApi.ListAuditEvents:output_type -> smartcore.bos.ListAuditEventsResponse
+	4,  // 17: smartcore.bos.AuditApi.PullAuditEvents:output_type -> smartcore.bos.PullAuditEventsResponse
+	16, // [16:18] is the sub-list for method output_type
+	14, // [14:16] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_audit_proto_init() }
+func file_audit_proto_init() {
+	if File_audit_proto != nil {
+		return
+	}
+	file_audit_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_audit_proto_rawDesc), len(file_audit_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_audit_proto_goTypes,
+		DependencyIndexes: file_audit_proto_depIdxs,
+		MessageInfos:      file_audit_proto_msgTypes,
+	}.Build()
+	File_audit_proto = out.File
+	file_audit_proto_goTypes = nil
+	file_audit_proto_depIdxs = nil
+}
@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: audit.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AuditApi_ListAuditEvents_FullMethodName = "/smartcore.bos.AuditApi/ListAuditEvents"
+	AuditApi_PullAuditEvents_FullMethodName = "/smartcore.bos.AuditApi/PullAuditEvents"
+)
+
+// AuditApiClient is the client API for AuditApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AuditApi provides read access to the audit log of a node.
+// The audit log records every request that could modify the state of the system,
+// along with who made the request and what the outcome was.
+type AuditApiClient interface {
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+	// Pull new audit events as they are recorded.
+	// Events recorded before the call is made are not returned, use ListAuditEvents for those.
+	PullAuditEvents(ctx context.Context, in *PullAuditEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PullAuditEventsResponse], error)
+}
+
+type auditApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuditApiClient(cc grpc.ClientConnInterface) AuditApiClient {
+	return &auditApiClient{cc}
+}
+
+func (c *auditApiClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, AuditApi_ListAuditEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auditApiClient) PullAuditEvents(ctx context.Context, in *PullAuditEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PullAuditEventsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AuditApi_ServiceDesc.Streams[0], AuditApi_PullAuditEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PullAuditEventsRequest, PullAuditEventsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuditApi_PullAuditEventsClient = grpc.ServerStreamingClient[PullAuditEventsResponse]
+
+// AuditApiServer is the server API for AuditApi service.
+// All implementations must embed UnimplementedAuditApiServer
+// for forward compatibility.
+//
+// AuditApi provides read access to the audit log of a node.
+// The audit log records every request that could modify the state of the system,
+// along with who made the request and what the outcome was.
+type AuditApiServer interface {
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	// Pull new audit events as they are recorded.
+	// Events recorded before the call is made are not returned, use ListAuditEvents for those.
+	PullAuditEvents(*PullAuditEventsRequest, grpc.ServerStreamingServer[PullAuditEventsResponse]) error
+	mustEmbedUnimplementedAuditApiServer()
+}
+
+// UnimplementedAuditApiServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAuditApiServer struct{}
+
+func (UnimplementedAuditApiServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedAuditApiServer) PullAuditEvents(*PullAuditEventsRequest, grpc.ServerStreamingServer[PullAuditEventsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method PullAuditEvents not implemented")
+}
+func (UnimplementedAuditApiServer) mustEmbedUnimplementedAuditApiServer() {}
+func (UnimplementedAuditApiServer) testEmbeddedByValue()                  {}
+
+// UnsafeAuditApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuditApiServer will
+// result in compilation errors.
+type UnsafeAuditApiServer interface {
+	mustEmbedUnimplementedAuditApiServer()
+}
+
+func RegisterAuditApiServer(s grpc.ServiceRegistrar, srv AuditApiServer) {
+	// If the following call pancis, it indicates UnimplementedAuditApiServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AuditApi_ServiceDesc, srv)
+}
+
+func _AuditApi_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditApiServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuditApi_ListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditApiServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuditApi_PullAuditEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PullAuditEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuditApiServer).PullAuditEvents(m, &grpc.GenericServerStream[PullAuditEventsRequest, PullAuditEventsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuditApi_PullAuditEventsServer = grpc.ServerStreamingServer[PullAuditEventsResponse]
+
+// AuditApi_ServiceDesc is the grpc.ServiceDesc for AuditApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuditApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smartcore.bos.AuditApi",
+	HandlerType: (*AuditApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _AuditApi_ListAuditEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PullAuditEvents",
+			Handler:       _AuditApi_PullAuditEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "audit.proto",
+}
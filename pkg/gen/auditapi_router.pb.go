@@ -0,0 +1,147 @@
+// Code generated by protoc-gen-router. DO NOT EDIT.
+
+package gen
+
+import (
+	context "context"
+	fmt "fmt"
+	router "github.com/smart-core-os/sc-golang/pkg/router"
+	grpc "google.golang.org/grpc"
+	io "io"
+)
+
+// AuditApiRouter is a AuditApiServer that allows routing named requests to specific AuditApiClient
+type AuditApiRouter struct {
+	UnimplementedAuditApiServer
+
+	router.Router
+}
+
+// compile time check that we implement the interface we need
+var _ AuditApiServer = (*AuditApiRouter)(nil)
+
+func NewAuditApiRouter(opts ...router.Option) *AuditApiRouter {
+	return &AuditApiRouter{
+		Router: router.NewRouter(opts...),
+	}
+}
+
+// WithAuditApiClientFactory instructs the router to create a new
+// client the first time Get is called for that name.
+func WithAuditApiClientFactory(f func(name string) (AuditApiClient, error)) router.Option {
+	return router.WithFactory(func(name string) (any, error) {
+		return f(name)
+	})
+}
+
+func (r *AuditApiRouter) Register(server grpc.ServiceRegistrar) {
+	RegisterAuditApiServer(server, r)
+}
+
+// Add extends Router.Add to panic if client is not of type AuditApiClient.
+func (r *AuditApiRouter) Add(name string, client any) any {
+	if !r.HoldsType(client) {
+		panic(fmt.Sprintf("not correct type: client of type %T is not a AuditApiClient", client))
+	}
+	return r.Router.Add(name, client)
+}
+
+func (r *AuditApiRouter) HoldsType(client any) bool {
+	_, ok := client.(AuditApiClient)
+	return ok
+}
+
+func (r *AuditApiRouter) AddAuditApiClient(name string, client AuditApiClient) AuditApiClient {
+	res := r.Add(name, client)
+	if res == nil {
+		return nil
+	}
+	return res.(AuditApiClient)
+}
+
+func (r *AuditApiRouter) RemoveAuditApiClient(name string) AuditApiClient {
+	res := r.Remove(name)
+	if res == nil {
+		return nil
+	}
+	return res.(AuditApiClient)
+}
+
+func (r *AuditApiRouter) GetAuditApiClient(name string) (AuditApiClient, error) {
+	res, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(AuditApiClient), nil
+}
+
+func (r *AuditApiRouter) ListAuditEvents(ctx context.Context, request *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	child, err := r.GetAuditApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.ListAuditEvents(ctx, request)
+}
+
+func (r *AuditApiRouter) PullAuditEvents(request *PullAuditEventsRequest, server AuditApi_PullAuditEventsServer) error {
+	child, err := r.GetAuditApiClient(request.Name)
+	if err != nil {
+		return err
+	}
+
+	// so we can cancel our forwarding request if we can't send responses to our caller
+	reqCtx, reqDone := context.WithCancel(server.Context())
+	// issue the request
+	stream, err := child.PullAuditEvents(reqCtx, request)
+	if err != nil {
+		return err
+	}
+
+	// send the stream header
+	header, err := stream.Header()
+	if err != nil {
+		return err
+	}
+	if err = server.SendHeader(header); err != nil {
+		return err
+	}
+
+	// send all the messages
+	// false means the error is from the child, true means the error is from the caller
+	var callerError bool
+	for {
+		// Impl note: we could improve throughput here by issuing the Recv and Send in different goroutines, but we're doing
+		// it synchronously until we have a need to change the behaviour
+
+		var msg *PullAuditEventsResponse
+		msg, err = stream.Recv()
+		if err != nil {
+			break
+		}
+
+		err = server.Send(msg)
+		if err != nil {
+			callerError = true
+			break
+		}
+	}
+
+	// err is guaranteed to be non-nil as it's the only way to exit the loop
+	if callerError {
+		// cancel the request
+		reqDone()
+		return err
+	} else {
+		if trailer := stream.Trailer(); trailer != nil {
+			server.SetTrailer(trailer)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+}
@@ -0,0 +1,42 @@
+// Code generated by protoc-gen-wrapper. DO NOT EDIT.
+
+package gen
+
+import (
+	wrap "github.com/smart-core-os/sc-golang/pkg/wrap"
+	grpc "google.golang.org/grpc"
+)
+
+// WrapAuditApi	adapts a AuditApiServer	and presents it as a AuditApiClient
+func WrapAuditApi(server AuditApiServer) *AuditApiWrapper {
+	conn := wrap.ServerToClient(AuditApi_ServiceDesc, server)
+	client := NewAuditApiClient(conn)
+	return &AuditApiWrapper{
+		AuditApiClient: client,
+		server:         server,
+		conn:           conn,
+		desc:           AuditApi_ServiceDesc,
+	}
+}
+
+type AuditApiWrapper struct {
+	AuditApiClient
+
+	server AuditApiServer
+	conn   grpc.ClientConnInterface
+	desc   grpc.ServiceDesc
+}
+
+// UnwrapServer returns the underlying server instance.
+func (w *AuditApiWrapper) UnwrapServer() AuditApiServer {
+	return w.server
+}
+
+// Unwrap implements wrap.Unwrapper and returns the underlying server instance as an unknown type.
+func (w *AuditApiWrapper) Unwrap() any {
+	return w.UnwrapServer()
+}
+
+func (w *AuditApiWrapper) UnwrapService() (grpc.ClientConnInterface, grpc.ServiceDesc) {
+	return w.conn, w.desc
+}
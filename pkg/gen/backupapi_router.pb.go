@@ -0,0 +1,165 @@
+// Code generated by protoc-gen-router. DO NOT EDIT.
+
+package gen
+
+import (
+	context "context"
+	fmt "fmt"
+	router "github.com/smart-core-os/sc-golang/pkg/router"
+	grpc "google.golang.org/grpc"
+	io "io"
+)
+
+// BackupApiRouter is a BackupApiServer that allows routing named requests to specific BackupApiClient
+type BackupApiRouter struct {
+	UnimplementedBackupApiServer
+
+	router.Router
+}
+
+// compile time check that we implement the interface we need
+var _ BackupApiServer = (*BackupApiRouter)(nil)
+
+func NewBackupApiRouter(opts ...router.Option) *BackupApiRouter {
+	return &BackupApiRouter{
+		Router: router.NewRouter(opts...),
+	}
+}
+
+// WithBackupApiClientFactory instructs the router to create a new
+// client the first time Get is called for that name.
+func WithBackupApiClientFactory(f func(name string) (BackupApiClient, error)) router.Option {
+	return router.WithFactory(func(name string) (any, error) {
+		return f(name)
+	})
+}
+
+func (r *BackupApiRouter) Register(server grpc.ServiceRegistrar) {
+	RegisterBackupApiServer(server, r)
+}
+
+// Add extends Router.Add to panic if client is not of type BackupApiClient.
+func (r *BackupApiRouter) Add(name string, client any) any {
+	if !r.HoldsType(client) {
+		panic(fmt.Sprintf("not correct type: client of type %T is not a BackupApiClient", client))
+	}
+	return r.Router.Add(name, client)
+}
+
+func (r *BackupApiRouter) HoldsType(client any) bool {
+	_, ok := client.(BackupApiClient)
+	return ok
+}
+
+func (r *BackupApiRouter) AddBackupApiClient(name string, client BackupApiClient) BackupApiClient {
+	res := r.Add(name, client)
+	if res == nil {
+		return nil
+	}
+	return res.(BackupApiClient)
+}
+
+func (r *BackupApiRouter) RemoveBackupApiClient(name string) BackupApiClient {
+	res := r.Remove(name)
+	if res == nil {
+		return nil
+	}
+	return res.(BackupApiClient)
+}
+
+func (r *BackupApiRouter) GetBackupApiClient(name string) (BackupApiClient, error) {
+	res, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(BackupApiClient), nil
+}
+
+func (r *BackupApiRouter) ListBackups(ctx context.Context, request *ListBackupsRequest) (*ListBackupsResponse, error) {
+	child, err := r.GetBackupApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.ListBackups(ctx, request)
+}
+
+func (r *BackupApiRouter) CreateBackup(ctx context.Context, request *CreateBackupRequest) (*Backup, error) {
+	child, err := r.GetBackupApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.CreateBackup(ctx, request)
+}
+
+func (r *BackupApiRouter) DownloadBackup(request *DownloadBackupRequest, server BackupApi_DownloadBackupServer) error {
+	child, err := r.GetBackupApiClient(request.Name)
+	if err != nil {
+		return err
+	}
+
+	// so we can cancel our forwarding request if we can't send responses to our caller
+	reqCtx, reqDone := context.WithCancel(server.Context())
+	// issue the request
+	stream, err := child.DownloadBackup(reqCtx, request)
+	if err != nil {
+		return err
+	}
+
+	// send the stream header
+	header, err := stream.Header()
+	if err != nil {
+		return err
+	}
+	if err = server.SendHeader(header); err != nil {
+		return err
+	}
+
+	// send all the messages
+	// false means the error is from the child, true means the error is from the caller
+	var callerError bool
+	for {
+		// Impl note: we could improve throughput here by issuing the Recv and Send in different goroutines, but we're doing
+		// it synchronously until we have a need to change the behaviour
+
+		var msg *DownloadBackupResponse
+		msg, err = stream.Recv()
+		if err != nil {
+			break
+		}
+
+		err = server.Send(msg)
+		if err != nil {
+			callerError = true
+			break
+		}
+	}
+
+	// err is guaranteed to be non-nil as it's the only way to exit the loop
+	if callerError {
+		// cancel the request
+		reqDone()
+		return err
+	} else {
+		if trailer := stream.Trailer(); trailer != nil {
+			server.SetTrailer(trailer)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+}
+
+func (r *BackupApiRouter) DeleteBackup(ctx context.Context, request *DeleteBackupRequest) (*DeleteBackupResponse, error) {
+	child, err := r.GetBackupApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.DeleteBackup(ctx, request)
+}
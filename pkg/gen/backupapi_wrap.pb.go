@@ -0,0 +1,42 @@
+// Code generated by protoc-gen-wrapper. DO NOT EDIT.
+
+package gen
+
+import (
+	wrap "github.com/smart-core-os/sc-golang/pkg/wrap"
+	grpc "google.golang.org/grpc"
+)
+
+// WrapBackupApi	adapts a BackupApiServer	and presents it as a BackupApiClient
+func WrapBackupApi(server BackupApiServer) *BackupApiWrapper {
+	conn := wrap.ServerToClient(BackupApi_ServiceDesc, server)
+	client := NewBackupApiClient(conn)
+	return &BackupApiWrapper{
+		BackupApiClient: client,
+		server:          server,
+		conn:            conn,
+		desc:            BackupApi_ServiceDesc,
+	}
+}
+
+type BackupApiWrapper struct {
+	BackupApiClient
+
+	server BackupApiServer
+	conn   grpc.ClientConnInterface
+	desc   grpc.ServiceDesc
+}
+
+// UnwrapServer returns the underlying server instance.
+func (w *BackupApiWrapper) UnwrapServer() BackupApiServer {
+	return w.server
+}
+
+// Unwrap implements wrap.Unwrapper and returns the underlying server instance as an unknown type.
+func (w *BackupApiWrapper) Unwrap() any {
+	return w.UnwrapServer()
+}
+
+func (w *BackupApiWrapper) UnwrapService() (grpc.ClientConnInterface, grpc.ServiceDesc) {
+	return w.conn, w.desc
+}
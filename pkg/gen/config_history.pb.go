@@ -0,0 +1,714 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.32.1
+// source: config_history.proto
+
+package gen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ConfigRevision is a version of the app config of a node.
+type ConfigRevision struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identifies the revision, increases with each change to the config.
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// When the revision was recorded.
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Absent if the change wasn't made via an authenticated request, for example changes to external config files.
+	Author *ConfigRevision_Author `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	// A short description of the change, for example "configure driver bacnet-1".
+	Description string `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// The full config, encoded as JSON.
+	// Only populated by GetConfigRevision.
+	ConfigRaw     string `protobuf:"bytes,5,opt,name=config_raw,json=configRaw,proto3" json:"config_raw,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigRevision) Reset() {
+	*x = ConfigRevision{}
+	mi := &file_config_history_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigRevision) ProtoMessage() {}
+
+func (x *ConfigRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_config_history_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigRevision.ProtoReflect.Descriptor instead.
+func (*ConfigRevision) Descriptor() ([]byte, []int) {
+	return file_config_history_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ConfigRevision) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ConfigRevision) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *ConfigRevision) GetAuthor() *ConfigRevision_Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *ConfigRevision) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ConfigRevision) GetConfigRaw() string {
+	if x != nil {
+		return x.ConfigRaw
+	}
+	return ""
+}
+
+// ConfigPatch describes a change to one section of the config.
+type ConfigPatch struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The location of the section in the config.
+	// For example `/drivers[name="bacnet-1"]`.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// The new value of the section, encoded as JSON.
+	// Absent if the section was deleted.
+	ValueRaw string `protobuf:"bytes,2,opt,name=value_raw,json=valueRaw,proto3" json:"value_raw,omitempty"`
+	// True if the section was deleted.
+	Deleted       bool `protobuf:"varint,3,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigPatch) Reset() {
+	*x = ConfigPatch{}
+	mi := &file_config_history_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigPatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigPatch) ProtoMessage() {}
+
+func (x *ConfigPatch) ProtoReflect() protoreflect.Message {
+	mi := &file_config_history_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigPatch.ProtoReflect.Descriptor instead.
+func (*ConfigPatch) Descriptor() ([]byte, []int) {
+	return file_config_history_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConfigPatch) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ConfigPatch) GetValueRaw() string {
+	if x != nil {
+		return x.ValueRaw
+	}
+	return ""
+}
+
+func (x *ConfigPatch) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type ListConfigRevisionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Fields to fetch relative to the ConfigRevision type.
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// The maximum number of revisions to return.
+	// The service may return fewer than this value.
+	// If unspecified, at most 50 items will be returned.
+	// The maximum value is 1000; values above 1000 will be coerced to 1000.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// A page token, received from a previous `ListConfigRevisionsResponse` call.
+	// Provide this to retrieve the subsequent page.
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConfigRevisionsRequest) Reset() {
+	*x = ListConfigRevisionsRequest{}
+	mi := &file_config_history_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConfigRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConfigRevisionsRequest) ProtoMessage() {}
+
+func (x *ListConfigRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_config_history_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConfigRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListConfigRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_config_history_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListConfigRevisionsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListConfigRevisionsRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *ListConfigRevisionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListConfigRevisionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListConfigRevisionsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ConfigRevisions []*ConfigRevision      `protobuf:"bytes,1,rep,name=config_revisions,json=configRevisions,proto3" json:"config_revisions,omitempty"`
+	// A token, which can be sent as `page_token` to retrieve the next page.
+	// If this field is omitted, there are no subsequent pages.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// The total number of revisions that are kept.
+	TotalSize     int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConfigRevisionsResponse) Reset() {
+	*x = ListConfigRevisionsResponse{}
+	mi := &file_config_history_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConfigRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConfigRevisionsResponse) ProtoMessage() {}
+
+func (x *ListConfigRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_config_history_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConfigRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListConfigRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_config_history_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListConfigRevisionsResponse) GetConfigRevisions() []*ConfigRevision {
+	if x != nil {
+		return x.ConfigRevisions
+	}
+	return nil
+}
+
+func (x *ListConfigRevisionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListConfigRevisionsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type GetConfigRevisionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The id of the revision, or 0 for the latest revision.
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigRevisionRequest) Reset() {
+	*x = GetConfigRevisionRequest{}
+	mi := &file_config_history_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigRevisionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigRevisionRequest) ProtoMessage() {}
+
+func (x *GetConfigRevisionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_config_history_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigRevisionRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigRevisionRequest) Descriptor() ([]byte, []int) {
+	return file_config_history_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetConfigRevisionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetConfigRevisionRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetConfigRevisionRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+type DiffConfigRevisionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The revision to compare from.
+	FromId int64 `protobuf:"varint,2,opt,name=from_id,json=fromId,proto3" json:"from_id,omitempty"`
+	// The revision to compare to, or 0 for the latest revision.
+	ToId          int64 `protobuf:"varint,3,opt,name=to_id,json=toId,proto3" json:"to_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffConfigRevisionsRequest) Reset() {
+	*x = DiffConfigRevisionsRequest{}
+	mi := &file_config_history_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffConfigRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffConfigRevisionsRequest) ProtoMessage() {}
+
+func (x *DiffConfigRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_config_history_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffConfigRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*DiffConfigRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_config_history_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DiffConfigRevisionsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DiffConfigRevisionsRequest) GetFromId() int64 {
+	if x != nil {
+		return x.FromId
+	}
+	return 0
+}
+
+func (x *DiffConfigRevisionsRequest) GetToId() int64 {
+	if x != nil {
+		return x.ToId
+	}
+	return 0
+}
+
+type DiffConfigRevisionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The changes that turn from_id into to_id, sorted by path.
+	Patches       []*ConfigPatch `protobuf:"bytes,1,rep,name=patches,proto3" json:"patches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffConfigRevisionsResponse) Reset() {
+	*x = DiffConfigRevisionsResponse{}
+	mi := &file_config_history_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffConfigRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffConfigRevisionsResponse) ProtoMessage() {}
+
+func (x *DiffConfigRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_config_history_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffConfigRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*DiffConfigRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_config_history_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DiffConfigRevisionsResponse) GetPatches() []*ConfigPatch {
+	if x != nil {
+		return x.Patches
+	}
+	return nil
+}
+
+type RollbackConfigRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The id of the revision to roll back to.
+	Id            int64 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackConfigRequest) Reset() {
+	*x = RollbackConfigRequest{}
+	mi := &file_config_history_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackConfigRequest) ProtoMessage() {}
+
+func (x *RollbackConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_config_history_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackConfigRequest.ProtoReflect.Descriptor instead.
+func (*RollbackConfigRequest) Descriptor() ([]byte, []int) {
+	return file_config_history_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RollbackConfigRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RollbackConfigRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Author describes who made the change.
+type ConfigRevision_Author struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The subject of the access token used to make the change.
+	// Typically an account or client id.
+	Subject string `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	// A human readable name for the subject, if known.
+	DisplayName   string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigRevision_Author) Reset() {
+	*x = ConfigRevision_Author{}
+	mi := &file_config_history_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigRevision_Author) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigRevision_Author) ProtoMessage() {}
+
+func (x *ConfigRevision_Author) ProtoReflect() protoreflect.Message {
+	mi := &file_config_history_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigRevision_Author.ProtoReflect.Descriptor instead.
+func (*ConfigRevision_Author) Descriptor() ([]byte, []int) {
+	return file_config_history_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *ConfigRevision_Author) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ConfigRevision_Author) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+var File_config_history_proto protoreflect.FileDescriptor
+
+const file_config_history_proto_rawDesc = "" +
+	"\n" +
+	"\x14config_history.proto\x12\rsmartcore.bos\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa3\x02\n" +
+	"\x0eConfigRevision\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12;\n" +
+	"\vcreate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12<\n" +
+	"\x06author\x18\x03 \x01(\v2$.smartcore.bos.ConfigRevision.AuthorR\x06author\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"config_raw\x18\x05 \x01(\tR\tconfigRaw\x1aE\n" +
+	"\x06Author\x12\x18\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\"X\n" +
+	"\vConfigPatch\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1b\n" +
+	"\tvalue_raw\x18\x02 \x01(\tR\bvalueRaw\x12\x18\n" +
+	"\adeleted\x18\x03 \x01(\bR\adeleted\"\xa5\x01\n" +
+	"\x1aListConfigRevisionsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\xae\x01\n" +
+	"\x1bListConfigRevisionsResponse\x12H\n" +
+	"\x10config_revisions\x18\x01 \x03(\v2\x1d.smartcore.bos.ConfigRevisionR\x0fconfigRevisions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"w\n" +
+	"\x18GetConfigRevisionRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\x127\n" +
+	"\tread_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"^\n" +
+	"\x1aDiffConfigRevisionsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x17\n" +
+	"\afrom_id\x18\x02 \x01(\x03R\x06fromId\x12\x13\n" +
+	"\x05to_id\x18\x03 \x01(\x03R\x04toId\"S\n" +
+	"\x1bDiffConfigRevisionsResponse\x124\n" +
+	"\apatches\x18\x01 \x03(\v2\x1a.smartcore.bos.ConfigPatchR\apatches\";\n" +
+	"\x15RollbackConfigRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id2\xa2\x03\n" +
+	"\x10ConfigHistoryApi\x12l\n" +
+	"\x13ListConfigRevisions\x12).smartcore.bos.ListConfigRevisionsRequest\x1a*.smartcore.bos.ListConfigRevisionsResponse\x12[\n" +
+	"\x11GetConfigRevision\x12'.smartcore.bos.GetConfigRevisionRequest\x1a\x1d.smartcore.bos.ConfigRevision\x12l\n" +
+	"\x13DiffConfigRevisions\x12).smartcore.bos.DiffConfigRevisionsRequest\x1a*.smartcore.bos.DiffConfigRevisionsResponse\x12U\n" +
+	"\x0eRollbackConfig\x12$.smartcore.bos.RollbackConfigRequest\x1a\x1d.smartcore.bos.ConfigRevisionB)Z'github.com/smart-core-os/sc-bos/pkg/genb\x06proto3"
+
+var (
+	file_config_history_proto_rawDescOnce sync.Once
+	file_config_history_proto_rawDescData []byte
+)
+
+func file_config_history_proto_rawDescGZIP() []byte {
+	file_config_history_proto_rawDescOnce.Do(func() {
+		file_config_history_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_config_history_proto_rawDesc), len(file_config_history_proto_rawDesc)))
+	})
+	return file_config_history_proto_rawDescData
+}
+
+var file_config_history_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_config_history_proto_goTypes = []any{
+	(*ConfigRevision)(nil),              // 0: smartcore.bos.ConfigRevision
+	(*ConfigPatch)(nil),                 // 1: smartcore.bos.ConfigPatch
+	(*ListConfigRevisionsRequest)(nil),  // 2: smartcore.bos.ListConfigRevisionsRequest
+	(*ListConfigRevisionsResponse)(nil), // 3: smartcore.bos.ListConfigRevisionsResponse
+	(*GetConfigRevisionRequest)(nil),    // 4: smartcore.bos.GetConfigRevisionRequest
+	(*DiffConfigRevisionsRequest)(nil),  // 5: smartcore.bos.DiffConfigRevisionsRequest
+	(*DiffConfigRevisionsResponse)(nil), // 6: smartcore.bos.DiffConfigRevisionsResponse
+	(*RollbackConfigRequest)(nil),       // 7: smartcore.bos.RollbackConfigRequest
+	(*ConfigRevision_Author)(nil),       // 8: smartcore.bos.ConfigRevision.Author
+	(*timestamppb.Timestamp)(nil),       // 9: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),       // 10: google.protobuf.FieldMask
+}
+var file_config_history_proto_depIdxs = []int32{
+	9,  // 0: smartcore.bos.ConfigRevision.create_time:type_name -> google.protobuf.Timestamp
+	8,  // 1: smartcore.bos.ConfigRevision.author:type_name -> smartcore.bos.ConfigRevision.Author
+	10, // 2: smartcore.bos.ListConfigRevisionsRequest.read_mask:type_name -> google.protobuf.FieldMask
+	0,  // 3: smartcore.bos.ListConfigRevisionsResponse.config_revisions:type_name -> smartcore.bos.ConfigRevision
+	10, // 4: smartcore.bos.GetConfigRevisionRequest.read_mask:type_name -> google.protobuf.FieldMask
+	1,  // 5: smartcore.bos.DiffConfigRevisionsResponse.patches:type_name -> smartcore.bos.ConfigPatch
+	2,  // 6: smartcore.bos.ConfigHistoryApi.ListConfigRevisions:input_type -> smartcore.bos.ListConfigRevisionsRequest
+	4,  // 7: smartcore.bos.ConfigHistoryApi.GetConfigRevision:input_type -> smartcore.bos.GetConfigRevisionRequest
+	5,  // 8: smartcore.bos.ConfigHistoryApi.DiffConfigRevisions:input_type -> smartcore.bos.DiffConfigRevisionsRequest
+	7,  // 9: smartcore.bos.ConfigHistoryApi.RollbackConfig:input_type -> smartcore.bos.RollbackConfigRequest
+	3,  // 10: smartcore.bos.ConfigHistoryApi.ListConfigRevisions:output_type -> smartcore.bos.ListConfigRevisionsResponse
+	0,  // 11: smartcore.bos.ConfigHistoryApi.GetConfigRevision:output_type -> smartcore.bos.ConfigRevision
+	6,  // 12: smartcore.bos.ConfigHistoryApi.DiffConfigRevisions:output_type -> smartcore.bos.DiffConfigRevisionsResponse
+	0,  // 13: smartcore.bos.ConfigHistoryApi.RollbackConfig:output_type -> smartcore.bos.ConfigRevision
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_config_history_proto_init() }
+func file_config_history_proto_init() {
+	if File_config_history_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_config_history_proto_rawDesc), len(file_config_history_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_config_history_proto_goTypes,
+		DependencyIndexes: file_config_history_proto_depIdxs,
+		MessageInfos:      file_config_history_proto_msgTypes,
+	}.Build()
+	File_config_history_proto = out.File
+	file_config_history_proto_goTypes = nil
+	file_config_history_proto_depIdxs = nil
+}
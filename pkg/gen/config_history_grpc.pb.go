@@ -0,0 +1,255 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: config_history.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ConfigHistoryApi_ListConfigRevisions_FullMethodName = "/smartcore.bos.ConfigHistoryApi/ListConfigRevisions"
+	ConfigHistoryApi_GetConfigRevision_FullMethodName   = "/smartcore.bos.ConfigHistoryApi/GetConfigRevision"
+	ConfigHistoryApi_DiffConfigRevisions_FullMethodName = "/smartcore.bos.ConfigHistoryApi/DiffConfigRevisions"
+	ConfigHistoryApi_RollbackConfig_FullMethodName      = "/smartcore.bos.ConfigHistoryApi/RollbackConfig"
+)
+
+// ConfigHistoryApiClient is the client API for ConfigHistoryApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ConfigHistoryApi provides access to previous versions of the app config of a node.
+// A new revision is recorded each time the config is changed, either by the ServicesApi or by changes to the
+// external config files the node was started with.
+type ConfigHistoryApiClient interface {
+	// List revisions of the config, newest first.
+	ListConfigRevisions(ctx context.Context, in *ListConfigRevisionsRequest, opts ...grpc.CallOption) (*ListConfigRevisionsResponse, error)
+	GetConfigRevision(ctx context.Context, in *GetConfigRevisionRequest, opts ...grpc.CallOption) (*ConfigRevision, error)
+	// Describe the changes needed to turn one revision into another.
+	DiffConfigRevisions(ctx context.Context, in *DiffConfigRevisionsRequest, opts ...grpc.CallOption) (*DiffConfigRevisionsResponse, error)
+	// Make the config of a previous revision the active config.
+	// Services whose config changed are reconfigured, started, or stopped as needed,
+	// services that don't exist in the revision are removed.
+	// A new revision is recorded for the rollback, which is returned.
+	RollbackConfig(ctx context.Context, in *RollbackConfigRequest, opts ...grpc.CallOption) (*ConfigRevision, error)
+}
+
+type configHistoryApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConfigHistoryApiClient(cc grpc.ClientConnInterface) ConfigHistoryApiClient {
+	return &configHistoryApiClient{cc}
+}
+
+func (c *configHistoryApiClient) ListConfigRevisions(ctx context.Context, in *ListConfigRevisionsRequest, opts ...grpc.CallOption) (*ListConfigRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListConfigRevisionsResponse)
+	err := c.cc.Invoke(ctx, ConfigHistoryApi_ListConfigRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configHistoryApiClient) GetConfigRevision(ctx context.Context, in *GetConfigRevisionRequest, opts ...grpc.CallOption) (*ConfigRevision, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigRevision)
+	err := c.cc.Invoke(ctx, ConfigHistoryApi_GetConfigRevision_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configHistoryApiClient) DiffConfigRevisions(ctx context.Context, in *DiffConfigRevisionsRequest, opts ...grpc.CallOption) (*DiffConfigRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiffConfigRevisionsResponse)
+	err := c.cc.Invoke(ctx, ConfigHistoryApi_DiffConfigRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configHistoryApiClient) RollbackConfig(ctx context.Context, in *RollbackConfigRequest, opts ...grpc.CallOption) (*ConfigRevision, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigRevision)
+	err := c.cc.Invoke(ctx, ConfigHistoryApi_RollbackConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConfigHistoryApiServer is the server API for ConfigHistoryApi service.
+// All implementations must embed UnimplementedConfigHistoryApiServer
+// for forward compatibility.
+//
+// ConfigHistoryApi provides access to previous versions of the app config of a node.
+// A new revision is recorded each time the config is changed, either by the ServicesApi or by changes to the
+// external config files the node was started with.
+type ConfigHistoryApiServer interface {
+	// List revisions of the config, newest first.
+	ListConfigRevisions(context.Context, *ListConfigRevisionsRequest) (*ListConfigRevisionsResponse, error)
+	GetConfigRevision(context.Context, *GetConfigRevisionRequest) (*ConfigRevision, error)
+	// Describe the changes needed to turn one revision into another.
+	DiffConfigRevisions(context.Context, *DiffConfigRevisionsRequest) (*DiffConfigRevisionsResponse, error)
+	// Make the config of a previous revision the active config.
+	// Services whose config changed are reconfigured, started, or stopped as needed,
+	// services that don't exist in the revision are removed.
+	// A new revision is recorded for the rollback, which is returned.
+	RollbackConfig(context.Context, *RollbackConfigRequest) (*ConfigRevision, error)
+	mustEmbedUnimplementedConfigHistoryApiServer()
+}
+
+// UnimplementedConfigHistoryApiServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedConfigHistoryApiServer struct{}
+
+func (UnimplementedConfigHistoryApiServer) ListConfigRevisions(context.Context, *ListConfigRevisionsRequest) (*ListConfigRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConfigRevisions not implemented")
+}
+func (UnimplementedConfigHistoryApiServer) GetConfigRevision(context.Context, *GetConfigRevisionRequest) (*ConfigRevision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfigRevision not implemented")
+}
+func (UnimplementedConfigHistoryApiServer) DiffConfigRevisions(context.Context, *DiffConfigRevisionsRequest) (*DiffConfigRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffConfigRevisions not implemented")
+}
+func (UnimplementedConfigHistoryApiServer) RollbackConfig(context.Context, *RollbackConfigRequest) (*ConfigRevision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackConfig not implemented")
+}
+func (UnimplementedConfigHistoryApiServer) mustEmbedUnimplementedConfigHistoryApiServer() {}
+func (UnimplementedConfigHistoryApiServer) testEmbeddedByValue()                          {}
+
+// UnsafeConfigHistoryApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConfigHistoryApiServer will
+// result in compilation errors.
+type UnsafeConfigHistoryApiServer interface {
+	mustEmbedUnimplementedConfigHistoryApiServer()
+}
+
+func RegisterConfigHistoryApiServer(s grpc.ServiceRegistrar, srv ConfigHistoryApiServer) {
+	// If the following call pancis, it indicates UnimplementedConfigHistoryApiServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ConfigHistoryApi_ServiceDesc, srv)
+}
+
+func _ConfigHistoryApi_ListConfigRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConfigRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigHistoryApiServer).ListConfigRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigHistoryApi_ListConfigRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigHistoryApiServer).ListConfigRevisions(ctx, req.(*ListConfigRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigHistoryApi_GetConfigRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigHistoryApiServer).GetConfigRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigHistoryApi_GetConfigRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigHistoryApiServer).GetConfigRevision(ctx, req.(*GetConfigRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigHistoryApi_DiffConfigRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffConfigRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigHistoryApiServer).DiffConfigRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigHistoryApi_DiffConfigRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigHistoryApiServer).DiffConfigRevisions(ctx, req.(*DiffConfigRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigHistoryApi_RollbackConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigHistoryApiServer).RollbackConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConfigHistoryApi_RollbackConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigHistoryApiServer).RollbackConfig(ctx, req.(*RollbackConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConfigHistoryApi_ServiceDesc is the grpc.ServiceDesc for ConfigHistoryApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConfigHistoryApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smartcore.bos.ConfigHistoryApi",
+	HandlerType: (*ConfigHistoryApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListConfigRevisions",
+			Handler:    _ConfigHistoryApi_ListConfigRevisions_Handler,
+		},
+		{
+			MethodName: "GetConfigRevision",
+			Handler:    _ConfigHistoryApi_GetConfigRevision_Handler,
+		},
+		{
+			MethodName: "DiffConfigRevisions",
+			Handler:    _ConfigHistoryApi_DiffConfigRevisions_Handler,
+		},
+		{
+			MethodName: "RollbackConfig",
+			Handler:    _ConfigHistoryApi_RollbackConfig_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "config_history.proto",
+}
@@ -0,0 +1,114 @@
+// Code generated by protoc-gen-router. DO NOT EDIT.
+
+package gen
+
+import (
+	context "context"
+	fmt "fmt"
+	router "github.com/smart-core-os/sc-golang/pkg/router"
+	grpc "google.golang.org/grpc"
+)
+
+// ConfigHistoryApiRouter is a ConfigHistoryApiServer that allows routing named requests to specific ConfigHistoryApiClient
+type ConfigHistoryApiRouter struct {
+	UnimplementedConfigHistoryApiServer
+
+	router.Router
+}
+
+// compile time check that we implement the interface we need
+var _ ConfigHistoryApiServer = (*ConfigHistoryApiRouter)(nil)
+
+func NewConfigHistoryApiRouter(opts ...router.Option) *ConfigHistoryApiRouter {
+	return &ConfigHistoryApiRouter{
+		Router: router.NewRouter(opts...),
+	}
+}
+
+// WithConfigHistoryApiClientFactory instructs the router to create a new
+// client the first time Get is called for that name.
+func WithConfigHistoryApiClientFactory(f func(name string) (ConfigHistoryApiClient, error)) router.Option {
+	return router.WithFactory(func(name string) (any, error) {
+		return f(name)
+	})
+}
+
+func (r *ConfigHistoryApiRouter) Register(server grpc.ServiceRegistrar) {
+	RegisterConfigHistoryApiServer(server, r)
+}
+
+// Add extends Router.Add to panic if client is not of type ConfigHistoryApiClient.
+func (r *ConfigHistoryApiRouter) Add(name string, client any) any {
+	if !r.HoldsType(client) {
+		panic(fmt.Sprintf("not correct type: client of type %T is not a ConfigHistoryApiClient", client))
+	}
+	return r.Router.Add(name, client)
+}
+
+func (r *ConfigHistoryApiRouter) HoldsType(client any) bool {
+	_, ok := client.(ConfigHistoryApiClient)
+	return ok
+}
+
+func (r *ConfigHistoryApiRouter) AddConfigHistoryApiClient(name string, client ConfigHistoryApiClient) ConfigHistoryApiClient {
+	res := r.Add(name, client)
+	if res == nil {
+		return nil
+	}
+	return res.(ConfigHistoryApiClient)
+}
+
+func (r *ConfigHistoryApiRouter) RemoveConfigHistoryApiClient(name string) ConfigHistoryApiClient {
+	res := r.Remove(name)
+	if res == nil {
+		return nil
+	}
+	return res.(ConfigHistoryApiClient)
+}
+
+func (r *ConfigHistoryApiRouter) GetConfigHistoryApiClient(name string) (ConfigHistoryApiClient, error) {
+	res, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(ConfigHistoryApiClient), nil
+}
+
+func (r *ConfigHistoryApiRouter) ListConfigRevisions(ctx context.Context, request *ListConfigRevisionsRequest) (*ListConfigRevisionsResponse, error) {
+	child, err := r.GetConfigHistoryApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.ListConfigRevisions(ctx, request)
+}
+
+func (r *ConfigHistoryApiRouter) GetConfigRevision(ctx context.Context, request *GetConfigRevisionRequest) (*ConfigRevision, error) {
+	child, err := r.GetConfigHistoryApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.GetConfigRevision(ctx, request)
+}
+
+func (r *ConfigHistoryApiRouter) DiffConfigRevisions(ctx context.Context, request *DiffConfigRevisionsRequest) (*DiffConfigRevisionsResponse, error) {
+	child, err := r.GetConfigHistoryApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.DiffConfigRevisions(ctx, request)
+}
+
+func (r *ConfigHistoryApiRouter) RollbackConfig(ctx context.Context, request *RollbackConfigRequest) (*ConfigRevision, error) {
+	child, err := r.GetConfigHistoryApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.RollbackConfig(ctx, request)
+}
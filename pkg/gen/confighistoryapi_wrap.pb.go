@@ -0,0 +1,42 @@
+// Code generated by protoc-gen-wrapper. DO NOT EDIT.
+
+package gen
+
+import (
+	wrap "github.com/smart-core-os/sc-golang/pkg/wrap"
+	grpc "google.golang.org/grpc"
+)
+
+// WrapConfigHistoryApi	adapts a ConfigHistoryApiServer	and presents it as a ConfigHistoryApiClient
+func WrapConfigHistoryApi(server ConfigHistoryApiServer) *ConfigHistoryApiWrapper {
+	conn := wrap.ServerToClient(ConfigHistoryApi_ServiceDesc, server)
+	client := NewConfigHistoryApiClient(conn)
+	return &ConfigHistoryApiWrapper{
+		ConfigHistoryApiClient: client,
+		server:                 server,
+		conn:                   conn,
+		desc:                   ConfigHistoryApi_ServiceDesc,
+	}
+}
+
+type ConfigHistoryApiWrapper struct {
+	ConfigHistoryApiClient
+
+	server ConfigHistoryApiServer
+	conn   grpc.ClientConnInterface
+	desc   grpc.ServiceDesc
+}
+
+// UnwrapServer returns the underlying server instance.
+func (w *ConfigHistoryApiWrapper) UnwrapServer() ConfigHistoryApiServer {
+	return w.server
+}
+
+// Unwrap implements wrap.Unwrapper and returns the underlying server instance as an unknown type.
+func (w *ConfigHistoryApiWrapper) Unwrap() any {
+	return w.UnwrapServer()
+}
+
+func (w *ConfigHistoryApiWrapper) UnwrapService() (grpc.ClientConnInterface, grpc.ServiceDesc) {
+	return w.conn, w.desc
+}
@@ -0,0 +1,950 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.32.1
+// source: demand_response.proto
+
+package gen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DemandResponseAction_Kind int32
+
+const (
+	DemandResponseAction_KIND_UNSPECIFIED DemandResponseAction_Kind = 0
+	DemandResponseAction_SHED             DemandResponseAction_Kind = 1
+	DemandResponseAction_RESTORE          DemandResponseAction_Kind = 2
+)
+
+// Enum value maps for DemandResponseAction_Kind.
+var (
+	DemandResponseAction_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "SHED",
+		2: "RESTORE",
+	}
+	DemandResponseAction_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"SHED":             1,
+		"RESTORE":          2,
+	}
+)
+
+func (x DemandResponseAction_Kind) Enum() *DemandResponseAction_Kind {
+	p := new(DemandResponseAction_Kind)
+	*p = x
+	return p
+}
+
+func (x DemandResponseAction_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DemandResponseAction_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_demand_response_proto_enumTypes[0].Descriptor()
+}
+
+func (DemandResponseAction_Kind) Type() protoreflect.EnumType {
+	return &file_demand_response_proto_enumTypes[0]
+}
+
+func (x DemandResponseAction_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DemandResponseAction_Kind.Descriptor instead.
+func (DemandResponseAction_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{2, 0}
+}
+
+type DemandResponseStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The latest site demand, in watts.
+	Demand float32 `protobuf:"fixed32,1,opt,name=demand,proto3" json:"demand,omitempty"`
+	// The demand limit currently in force, in watts.
+	// This is the configured limit, or a lower limit set by an active event.
+	DemandLimit float32 `protobuf:"fixed32,2,opt,name=demand_limit,json=demandLimit,proto3" json:"demand_limit,omitempty"`
+	// The number of stages currently shed, 0 if no load is shed.
+	Stage int32 `protobuf:"varint,3,opt,name=stage,proto3" json:"stage,omitempty"`
+	// The name of the highest stage currently shed.
+	StageName string `protobuf:"bytes,4,opt,name=stage_name,json=stageName,proto3" json:"stage_name,omitempty"`
+	// Ids of events that are currently active.
+	ActiveEventIds []string `protobuf:"bytes,5,rep,name=active_event_ids,json=activeEventIds,proto3" json:"active_event_ids,omitempty"`
+	// When demand was last read.
+	DemandTime    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=demand_time,json=demandTime,proto3" json:"demand_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DemandResponseStatus) Reset() {
+	*x = DemandResponseStatus{}
+	mi := &file_demand_response_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DemandResponseStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DemandResponseStatus) ProtoMessage() {}
+
+func (x *DemandResponseStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_response_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DemandResponseStatus.ProtoReflect.Descriptor instead.
+func (*DemandResponseStatus) Descriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DemandResponseStatus) GetDemand() float32 {
+	if x != nil {
+		return x.Demand
+	}
+	return 0
+}
+
+func (x *DemandResponseStatus) GetDemandLimit() float32 {
+	if x != nil {
+		return x.DemandLimit
+	}
+	return 0
+}
+
+func (x *DemandResponseStatus) GetStage() int32 {
+	if x != nil {
+		return x.Stage
+	}
+	return 0
+}
+
+func (x *DemandResponseStatus) GetStageName() string {
+	if x != nil {
+		return x.StageName
+	}
+	return ""
+}
+
+func (x *DemandResponseStatus) GetActiveEventIds() []string {
+	if x != nil {
+		return x.ActiveEventIds
+	}
+	return nil
+}
+
+func (x *DemandResponseStatus) GetDemandTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DemandTime
+	}
+	return nil
+}
+
+// DemandResponseEvent is a request to reduce demand for a period of time.
+type DemandResponseEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Opaque id of the event, assigned by the automation.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Who requested the event, for example the id of the utility program or VTN.
+	Source    string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// Events without an end time last until they are updated.
+	EndTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// If set, the demand limit in watts while the event is active, if lower than the configured limit.
+	DemandLimit *float32 `protobuf:"fixed32,5,opt,name=demand_limit,json=demandLimit,proto3,oneof" json:"demand_limit,omitempty"`
+	// The minimum number of stages to shed while the event is active, regardless of demand.
+	MinStage      int32                  `protobuf:"varint,6,opt,name=min_stage,json=minStage,proto3" json:"min_stage,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DemandResponseEvent) Reset() {
+	*x = DemandResponseEvent{}
+	mi := &file_demand_response_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DemandResponseEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DemandResponseEvent) ProtoMessage() {}
+
+func (x *DemandResponseEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_response_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DemandResponseEvent.ProtoReflect.Descriptor instead.
+func (*DemandResponseEvent) Descriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DemandResponseEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DemandResponseEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *DemandResponseEvent) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *DemandResponseEvent) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *DemandResponseEvent) GetDemandLimit() float32 {
+	if x != nil && x.DemandLimit != nil {
+		return *x.DemandLimit
+	}
+	return 0
+}
+
+func (x *DemandResponseEvent) GetMinStage() int32 {
+	if x != nil {
+		return x.MinStage
+	}
+	return 0
+}
+
+func (x *DemandResponseEvent) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+// DemandResponseAction records a change the automation made to a device.
+type DemandResponseAction struct {
+	state      protoimpl.MessageState    `protogen:"open.v1"`
+	Id         string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActionTime *timestamppb.Timestamp    `protobuf:"bytes,2,opt,name=action_time,json=actionTime,proto3" json:"action_time,omitempty"`
+	Kind       DemandResponseAction_Kind `protobuf:"varint,3,opt,name=kind,proto3,enum=smartcore.bos.DemandResponseAction_Kind" json:"kind,omitempty"`
+	// The stage the action belongs to, starting at 1.
+	Stage     int32  `protobuf:"varint,4,opt,name=stage,proto3" json:"stage,omitempty"`
+	StageName string `protobuf:"bytes,5,opt,name=stage_name,json=stageName,proto3" json:"stage_name,omitempty"`
+	// Smart Core name of the device changed.
+	DeviceName string `protobuf:"bytes,6,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	// A human readable description of the change, like "brightness 80% -> 64%".
+	Description string `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	// Why the stage was shed or restored, like "demand 105 kW above 100 kW".
+	Reason string `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Set if the action failed.
+	Error         string `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DemandResponseAction) Reset() {
+	*x = DemandResponseAction{}
+	mi := &file_demand_response_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DemandResponseAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DemandResponseAction) ProtoMessage() {}
+
+func (x *DemandResponseAction) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_response_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DemandResponseAction.ProtoReflect.Descriptor instead.
+func (*DemandResponseAction) Descriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DemandResponseAction) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DemandResponseAction) GetActionTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActionTime
+	}
+	return nil
+}
+
+func (x *DemandResponseAction) GetKind() DemandResponseAction_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return DemandResponseAction_KIND_UNSPECIFIED
+}
+
+func (x *DemandResponseAction) GetStage() int32 {
+	if x != nil {
+		return x.Stage
+	}
+	return 0
+}
+
+func (x *DemandResponseAction) GetStageName() string {
+	if x != nil {
+		return x.StageName
+	}
+	return ""
+}
+
+func (x *DemandResponseAction) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *DemandResponseAction) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *DemandResponseAction) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DemandResponseAction) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetDemandResponseStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDemandResponseStatusRequest) Reset() {
+	*x = GetDemandResponseStatusRequest{}
+	mi := &file_demand_response_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDemandResponseStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDemandResponseStatusRequest) ProtoMessage() {}
+
+func (x *GetDemandResponseStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_response_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDemandResponseStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDemandResponseStatusRequest) Descriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetDemandResponseStatusRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetDemandResponseStatusRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+type ListDemandResponseEventsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// Include events that have ended.
+	IncludeEnded  bool `protobuf:"varint,3,opt,name=include_ended,json=includeEnded,proto3" json:"include_ended,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDemandResponseEventsRequest) Reset() {
+	*x = ListDemandResponseEventsRequest{}
+	mi := &file_demand_response_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDemandResponseEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDemandResponseEventsRequest) ProtoMessage() {}
+
+func (x *ListDemandResponseEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_response_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDemandResponseEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListDemandResponseEventsRequest) Descriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListDemandResponseEventsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListDemandResponseEventsRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *ListDemandResponseEventsRequest) GetIncludeEnded() bool {
+	if x != nil {
+		return x.IncludeEnded
+	}
+	return false
+}
+
+type ListDemandResponseEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Events ordered by start time.
+	Events        []*DemandResponseEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDemandResponseEventsResponse) Reset() {
+	*x = ListDemandResponseEventsResponse{}
+	mi := &file_demand_response_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDemandResponseEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDemandResponseEventsResponse) ProtoMessage() {}
+
+func (x *ListDemandResponseEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_response_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDemandResponseEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListDemandResponseEventsResponse) Descriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListDemandResponseEventsResponse) GetEvents() []*DemandResponseEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type CreateDemandResponseEventRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The event to create, start_time defaults to now. Output only fields are ignored.
+	Event         *DemandResponseEvent `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDemandResponseEventRequest) Reset() {
+	*x = CreateDemandResponseEventRequest{}
+	mi := &file_demand_response_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDemandResponseEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDemandResponseEventRequest) ProtoMessage() {}
+
+func (x *CreateDemandResponseEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_response_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDemandResponseEventRequest.ProtoReflect.Descriptor instead.
+func (*CreateDemandResponseEventRequest) Descriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateDemandResponseEventRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateDemandResponseEventRequest) GetEvent() *DemandResponseEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type UpdateDemandResponseEventRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The event to update, identified by id.
+	Event *DemandResponseEvent `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	// Fields to update, defaults to all fields that can be updated: end_time, demand_limit, and min_stage.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDemandResponseEventRequest) Reset() {
+	*x = UpdateDemandResponseEventRequest{}
+	mi := &file_demand_response_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDemandResponseEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDemandResponseEventRequest) ProtoMessage() {}
+
+func (x *UpdateDemandResponseEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_response_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDemandResponseEventRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDemandResponseEventRequest) Descriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateDemandResponseEventRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateDemandResponseEventRequest) GetEvent() *DemandResponseEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *UpdateDemandResponseEventRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type ListDemandResponseActionsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// The maximum number of actions to return.
+	// The service may return fewer than this value.
+	// If unspecified, at most 50 items will be returned.
+	// The maximum value is 1000; values above 1000 will be coerced to 1000.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// A page token, received from a previous `ListDemandResponseActionsResponse` call.
+	// Provide this to retrieve the subsequent page.
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDemandResponseActionsRequest) Reset() {
+	*x = ListDemandResponseActionsRequest{}
+	mi := &file_demand_response_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDemandResponseActionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDemandResponseActionsRequest) ProtoMessage() {}
+
+func (x *ListDemandResponseActionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_response_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDemandResponseActionsRequest.ProtoReflect.Descriptor instead.
+func (*ListDemandResponseActionsRequest) Descriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListDemandResponseActionsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListDemandResponseActionsRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *ListDemandResponseActionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDemandResponseActionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListDemandResponseActionsResponse struct {
+	state   protoimpl.MessageState  `protogen:"open.v1"`
+	Actions []*DemandResponseAction `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`
+	// A token, which can be sent as `page_token` to retrieve the next page.
+	// If this field is omitted, there are no subsequent pages.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// If non-zero this is the total number of actions.
+	TotalSize     int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDemandResponseActionsResponse) Reset() {
+	*x = ListDemandResponseActionsResponse{}
+	mi := &file_demand_response_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDemandResponseActionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDemandResponseActionsResponse) ProtoMessage() {}
+
+func (x *ListDemandResponseActionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_demand_response_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDemandResponseActionsResponse.ProtoReflect.Descriptor instead.
+func (*ListDemandResponseActionsResponse) Descriptor() ([]byte, []int) {
+	return file_demand_response_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListDemandResponseActionsResponse) GetActions() []*DemandResponseAction {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *ListDemandResponseActionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListDemandResponseActionsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+var File_demand_response_proto protoreflect.FileDescriptor
+
+const file_demand_response_proto_rawDesc = "" +
+	"\n" +
+	"\x15demand_response.proto\x12\rsmartcore.bos\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xed\x01\n" +
+	"\x14DemandResponseStatus\x12\x16\n" +
+	"\x06demand\x18\x01 \x01(\x02R\x06demand\x12!\n" +
+	"\fdemand_limit\x18\x02 \x01(\x02R\vdemandLimit\x12\x14\n" +
+	"\x05stage\x18\x03 \x01(\x05R\x05stage\x12\x1d\n" +
+	"\n" +
+	"stage_name\x18\x04 \x01(\tR\tstageName\x12(\n" +
+	"\x10active_event_ids\x18\x05 \x03(\tR\x0eactiveEventIds\x12;\n" +
+	"\vdemand_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"demandTime\"\xc2\x02\n" +
+	"\x13DemandResponseEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x129\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12&\n" +
+	"\fdemand_limit\x18\x05 \x01(\x02H\x00R\vdemandLimit\x88\x01\x01\x12\x1b\n" +
+	"\tmin_stage\x18\x06 \x01(\x05R\bminStage\x12;\n" +
+	"\vcreate_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTimeB\x0f\n" +
+	"\r_demand_limit\"\xfc\x02\n" +
+	"\x14DemandResponseAction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12;\n" +
+	"\vaction_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"actionTime\x12<\n" +
+	"\x04kind\x18\x03 \x01(\x0e2(.smartcore.bos.DemandResponseAction.KindR\x04kind\x12\x14\n" +
+	"\x05stage\x18\x04 \x01(\x05R\x05stage\x12\x1d\n" +
+	"\n" +
+	"stage_name\x18\x05 \x01(\tR\tstageName\x12\x1f\n" +
+	"\vdevice_name\x18\x06 \x01(\tR\n" +
+	"deviceName\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\"3\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\b\n" +
+	"\x04SHED\x10\x01\x12\v\n" +
+	"\aRESTORE\x10\x02\"m\n" +
+	"\x1eGetDemandResponseStatusRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"\x93\x01\n" +
+	"\x1fListDemandResponseEventsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12#\n" +
+	"\rinclude_ended\x18\x03 \x01(\bR\fincludeEnded\"^\n" +
+	" ListDemandResponseEventsResponse\x12:\n" +
+	"\x06events\x18\x01 \x03(\v2\".smartcore.bos.DemandResponseEventR\x06events\"p\n" +
+	" CreateDemandResponseEventRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x128\n" +
+	"\x05event\x18\x02 \x01(\v2\".smartcore.bos.DemandResponseEventR\x05event\"\xad\x01\n" +
+	" UpdateDemandResponseEventRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x128\n" +
+	"\x05event\x18\x02 \x01(\v2\".smartcore.bos.DemandResponseEventR\x05event\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"\xab\x01\n" +
+	" ListDemandResponseActionsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\xa9\x01\n" +
+	"!ListDemandResponseActionsResponse\x12=\n" +
+	"\aactions\x18\x01 \x03(\v2#.smartcore.bos.DemandResponseActionR\aactions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize2\xe3\x04\n" +
+	"\x11DemandResponseApi\x12m\n" +
+	"\x17GetDemandResponseStatus\x12-.smartcore.bos.GetDemandResponseStatusRequest\x1a#.smartcore.bos.DemandResponseStatus\x12{\n" +
+	"\x18ListDemandResponseEvents\x12..smartcore.bos.ListDemandResponseEventsRequest\x1a/.smartcore.bos.ListDemandResponseEventsResponse\x12p\n" +
+	"\x19CreateDemandResponseEvent\x12/.smartcore.bos.CreateDemandResponseEventRequest\x1a\".smartcore.bos.DemandResponseEvent\x12p\n" +
+	"\x19UpdateDemandResponseEvent\x12/.smartcore.bos.UpdateDemandResponseEventRequest\x1a\".smartcore.bos.DemandResponseEvent\x12~\n" +
+	"\x19ListDemandResponseActions\x12/.smartcore.bos.ListDemandResponseActionsRequest\x1a0.smartcore.bos.ListDemandResponseActionsResponseB)Z'github.com/smart-core-os/sc-bos/pkg/genb\x06proto3"
+
+var (
+	file_demand_response_proto_rawDescOnce sync.Once
+	file_demand_response_proto_rawDescData []byte
+)
+
+func file_demand_response_proto_rawDescGZIP() []byte {
+	file_demand_response_proto_rawDescOnce.Do(func() {
+		file_demand_response_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_demand_response_proto_rawDesc), len(file_demand_response_proto_rawDesc)))
+	})
+	return file_demand_response_proto_rawDescData
+}
+
+var file_demand_response_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_demand_response_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_demand_response_proto_goTypes = []any{
+	(DemandResponseAction_Kind)(0),            // 0: smartcore.bos.DemandResponseAction.Kind
+	(*DemandResponseStatus)(nil),              // 1: smartcore.bos.DemandResponseStatus
+	(*DemandResponseEvent)(nil),               // 2: smartcore.bos.DemandResponseEvent
+	(*DemandResponseAction)(nil),              // 3: smartcore.bos.DemandResponseAction
+	(*GetDemandResponseStatusRequest)(nil),    // 4: smartcore.bos.GetDemandResponseStatusRequest
+	(*ListDemandResponseEventsRequest)(nil),   // 5: smartcore.bos.ListDemandResponseEventsRequest
+	(*ListDemandResponseEventsResponse)(nil),  // 6: smartcore.bos.ListDemandResponseEventsResponse
+	(*CreateDemandResponseEventRequest)(nil),  // 7: smartcore.bos.CreateDemandResponseEventRequest
+	(*UpdateDemandResponseEventRequest)(nil),  // 8: smartcore.bos.UpdateDemandResponseEventRequest
+	(*ListDemandResponseActionsRequest)(nil),  // 9: smartcore.bos.ListDemandResponseActionsRequest
+	(*ListDemandResponseActionsResponse)(nil), // 10: smartcore.bos.ListDemandResponseActionsResponse
+	(*timestamppb.Timestamp)(nil),             // 11: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),             // 12: google.protobuf.FieldMask
+}
+var file_demand_response_proto_depIdxs = []int32{
+	11, // 0: smartcore.bos.DemandResponseStatus.demand_time:type_name -> google.protobuf.Timestamp
+	11, // 1: smartcore.bos.DemandResponseEvent.start_time:type_name -> google.protobuf.Timestamp
+	11, // 2: smartcore.bos.DemandResponseEvent.end_time:type_name -> google.protobuf.Timestamp
+	11, // 3: smartcore.bos.DemandResponseEvent.create_time:type_name -> google.protobuf.Timestamp
+	11, // 4: smartcore.bos.DemandResponseAction.action_time:type_name -> google.protobuf.Timestamp
+	0,  // 5: smartcore.bos.DemandResponseAction.kind:type_name -> smartcore.bos.DemandResponseAction.Kind
+	12, // 6: smartcore.bos.GetDemandResponseStatusRequest.read_mask:type_name -> google.protobuf.FieldMask
+	12, // 7: smartcore.bos.ListDemandResponseEventsRequest.read_mask:type_name -> google.protobuf.FieldMask
+	2,  // 8: smartcore.bos.ListDemandResponseEventsResponse.events:type_name -> smartcore.bos.DemandResponseEvent
+	2,  // 9: smartcore.bos.CreateDemandResponseEventRequest.event:type_name -> smartcore.bos.DemandResponseEvent
+	2,  // 10: smartcore.bos.UpdateDemandResponseEventRequest.event:type_name -> smartcore.bos.DemandResponseEvent
+	12, // 11: smartcore.bos.UpdateDemandResponseEventRequest.update_mask:type_name -> google.protobuf.FieldMask
+	12, // 12: smartcore.bos.ListDemandResponseActionsRequest.read_mask:type_name -> google.protobuf.FieldMask
+	3,  // 13: smartcore.bos.ListDemandResponseActionsResponse.actions:type_name -> smartcore.bos.DemandResponseAction
+	4,  // 14: smartcore.bos.DemandResponseApi.GetDemandResponseStatus:input_type -> smartcore.bos.GetDemandResponseStatusRequest
+	5,  // 15: smartcore.bos.DemandResponseApi.ListDemandResponseEvents:input_type -> smartcore.bos.ListDemandResponseEventsRequest
+	7,  // 16: smartcore.bos.DemandResponseApi.CreateDemandResponseEvent:input_type -> smartcore.bos.CreateDemandResponseEventRequest
+	8,  // 17: smartcore.bos.DemandResponseApi.UpdateDemandResponseEvent:input_type -> smartcore.bos.UpdateDemandResponseEventRequest
+	9,  // 18: smartcore.bos.DemandResponseApi.ListDemandResponseActions:input_type -> smartcore.bos.ListDemandResponseActionsRequest
+	1,  // 19: smartcore.bos.DemandResponseApi.GetDemandResponseStatus:output_type -> smartcore.bos.DemandResponseStatus
+	6,  // 20: smartcore.bos.DemandResponseApi.ListDemandResponseEvents:output_type -> smartcore.bos.ListDemandResponseEventsResponse
+	2,  // 21: smartcore.bos.DemandResponseApi.CreateDemandResponseEvent:output_type -> smartcore.bos.DemandResponseEvent
+	2,  // 22: smartcore.bos.DemandResponseApi.UpdateDemandResponseEvent:output_type -> smartcore.bos.DemandResponseEvent
+	10, // 23: smartcore.bos.DemandResponseApi.ListDemandResponseActions:output_type -> smartcore.bos.ListDemandResponseActionsResponse
+	19, // [19:24] is the sub-list for method output_type
+	14, // [14:19] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_demand_response_proto_init() }
+func file_demand_response_proto_init() {
+	if File_demand_response_proto != nil {
+		return
+	}
+	file_demand_response_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_demand_response_proto_rawDesc), len(file_demand_response_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_demand_response_proto_goTypes,
+		DependencyIndexes: file_demand_response_proto_depIdxs,
+		EnumInfos:         file_demand_response_proto_enumTypes,
+		MessageInfos:      file_demand_response_proto_msgTypes,
+	}.Build()
+	File_demand_response_proto = out.File
+	file_demand_response_proto_goTypes = nil
+	file_demand_response_proto_depIdxs = nil
+}
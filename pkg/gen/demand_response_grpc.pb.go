@@ -0,0 +1,289 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: demand_response.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DemandResponseApi_GetDemandResponseStatus_FullMethodName   = "/smartcore.bos.DemandResponseApi/GetDemandResponseStatus"
+	DemandResponseApi_ListDemandResponseEvents_FullMethodName  = "/smartcore.bos.DemandResponseApi/ListDemandResponseEvents"
+	DemandResponseApi_CreateDemandResponseEvent_FullMethodName = "/smartcore.bos.DemandResponseApi/CreateDemandResponseEvent"
+	DemandResponseApi_UpdateDemandResponseEvent_FullMethodName = "/smartcore.bos.DemandResponseApi/UpdateDemandResponseEvent"
+	DemandResponseApi_ListDemandResponseActions_FullMethodName = "/smartcore.bos.DemandResponseApi/ListDemandResponseActions"
+)
+
+// DemandResponseApiClient is the client API for DemandResponseApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DemandResponseApi controls a load shedding automation.
+// The automation sheds load in stages when site demand approaches a limit, and restores it when demand falls.
+// Demand response events, for example from a grid operator or aggregator, can lower the limit or require a minimum
+// stage for a period of time.
+type DemandResponseApiClient interface {
+	GetDemandResponseStatus(ctx context.Context, in *GetDemandResponseStatusRequest, opts ...grpc.CallOption) (*DemandResponseStatus, error)
+	ListDemandResponseEvents(ctx context.Context, in *ListDemandResponseEventsRequest, opts ...grpc.CallOption) (*ListDemandResponseEventsResponse, error)
+	CreateDemandResponseEvent(ctx context.Context, in *CreateDemandResponseEventRequest, opts ...grpc.CallOption) (*DemandResponseEvent, error)
+	// Update an event, for example to end it early by setting end_time.
+	// Events that haven't started can be cancelled by setting end_time to start_time.
+	UpdateDemandResponseEvent(ctx context.Context, in *UpdateDemandResponseEventRequest, opts ...grpc.CallOption) (*DemandResponseEvent, error)
+	// List the shed and restore actions taken by the automation, newest first.
+	ListDemandResponseActions(ctx context.Context, in *ListDemandResponseActionsRequest, opts ...grpc.CallOption) (*ListDemandResponseActionsResponse, error)
+}
+
+type demandResponseApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDemandResponseApiClient(cc grpc.ClientConnInterface) DemandResponseApiClient {
+	return &demandResponseApiClient{cc}
+}
+
+func (c *demandResponseApiClient) GetDemandResponseStatus(ctx context.Context, in *GetDemandResponseStatusRequest, opts ...grpc.CallOption) (*DemandResponseStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DemandResponseStatus)
+	err := c.cc.Invoke(ctx, DemandResponseApi_GetDemandResponseStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demandResponseApiClient) ListDemandResponseEvents(ctx context.Context, in *ListDemandResponseEventsRequest, opts ...grpc.CallOption) (*ListDemandResponseEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDemandResponseEventsResponse)
+	err := c.cc.Invoke(ctx, DemandResponseApi_ListDemandResponseEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demandResponseApiClient) CreateDemandResponseEvent(ctx context.Context, in *CreateDemandResponseEventRequest, opts ...grpc.CallOption) (*DemandResponseEvent, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DemandResponseEvent)
+	err := c.cc.Invoke(ctx, DemandResponseApi_CreateDemandResponseEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demandResponseApiClient) UpdateDemandResponseEvent(ctx context.Context, in *UpdateDemandResponseEventRequest, opts ...grpc.CallOption) (*DemandResponseEvent, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DemandResponseEvent)
+	err := c.cc.Invoke(ctx, DemandResponseApi_UpdateDemandResponseEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demandResponseApiClient) ListDemandResponseActions(ctx context.Context, in *ListDemandResponseActionsRequest, opts ...grpc.CallOption) (*ListDemandResponseActionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDemandResponseActionsResponse)
+	err := c.cc.Invoke(ctx, DemandResponseApi_ListDemandResponseActions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DemandResponseApiServer is the server API for DemandResponseApi service.
+// All implementations must embed UnimplementedDemandResponseApiServer
+// for forward compatibility.
+//
+// DemandResponseApi controls a load shedding automation.
+// The automation sheds load in stages when site demand approaches a limit, and restores it when demand falls.
+// Demand response events, for example from a grid operator or aggregator, can lower the limit or require a minimum
+// stage for a period of time.
+type DemandResponseApiServer interface {
+	GetDemandResponseStatus(context.Context, *GetDemandResponseStatusRequest) (*DemandResponseStatus, error)
+	ListDemandResponseEvents(context.Context, *ListDemandResponseEventsRequest) (*ListDemandResponseEventsResponse, error)
+	CreateDemandResponseEvent(context.Context, *CreateDemandResponseEventRequest) (*DemandResponseEvent, error)
+	// Update an event, for example to end it early by setting end_time.
+	// Events that haven't started can be cancelled by setting end_time to start_time.
+	UpdateDemandResponseEvent(context.Context, *UpdateDemandResponseEventRequest) (*DemandResponseEvent, error)
+	// List the shed and restore actions taken by the automation, newest first.
+	ListDemandResponseActions(context.Context, *ListDemandResponseActionsRequest) (*ListDemandResponseActionsResponse, error)
+	mustEmbedUnimplementedDemandResponseApiServer()
+}
+
+// UnimplementedDemandResponseApiServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDemandResponseApiServer struct{}
+
+func (UnimplementedDemandResponseApiServer) GetDemandResponseStatus(context.Context, *GetDemandResponseStatusRequest) (*DemandResponseStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDemandResponseStatus not implemented")
+}
+func (UnimplementedDemandResponseApiServer) ListDemandResponseEvents(context.Context, *ListDemandResponseEventsRequest) (*ListDemandResponseEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDemandResponseEvents not implemented")
+}
+func (UnimplementedDemandResponseApiServer) CreateDemandResponseEvent(context.Context, *CreateDemandResponseEventRequest) (*DemandResponseEvent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDemandResponseEvent not implemented")
+}
+func (UnimplementedDemandResponseApiServer) UpdateDemandResponseEvent(context.Context, *UpdateDemandResponseEventRequest) (*DemandResponseEvent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDemandResponseEvent not implemented")
+}
+func (UnimplementedDemandResponseApiServer) ListDemandResponseActions(context.Context, *ListDemandResponseActionsRequest) (*ListDemandResponseActionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDemandResponseActions not implemented")
+}
+func (UnimplementedDemandResponseApiServer) mustEmbedUnimplementedDemandResponseApiServer() {}
+func (UnimplementedDemandResponseApiServer) testEmbeddedByValue()                           {}
+
+// UnsafeDemandResponseApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DemandResponseApiServer will
+// result in compilation errors.
+type UnsafeDemandResponseApiServer interface {
+	mustEmbedUnimplementedDemandResponseApiServer()
+}
+
+func RegisterDemandResponseApiServer(s grpc.ServiceRegistrar, srv DemandResponseApiServer) {
+	// If the following call pancis, it indicates UnimplementedDemandResponseApiServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DemandResponseApi_ServiceDesc, srv)
+}
+
+func _DemandResponseApi_GetDemandResponseStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDemandResponseStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemandResponseApiServer).GetDemandResponseStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemandResponseApi_GetDemandResponseStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemandResponseApiServer).GetDemandResponseStatus(ctx, req.(*GetDemandResponseStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemandResponseApi_ListDemandResponseEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDemandResponseEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemandResponseApiServer).ListDemandResponseEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemandResponseApi_ListDemandResponseEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemandResponseApiServer).ListDemandResponseEvents(ctx, req.(*ListDemandResponseEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemandResponseApi_CreateDemandResponseEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDemandResponseEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemandResponseApiServer).CreateDemandResponseEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemandResponseApi_CreateDemandResponseEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemandResponseApiServer).CreateDemandResponseEvent(ctx, req.(*CreateDemandResponseEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemandResponseApi_UpdateDemandResponseEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDemandResponseEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemandResponseApiServer).UpdateDemandResponseEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemandResponseApi_UpdateDemandResponseEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemandResponseApiServer).UpdateDemandResponseEvent(ctx, req.(*UpdateDemandResponseEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemandResponseApi_ListDemandResponseActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDemandResponseActionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemandResponseApiServer).ListDemandResponseActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemandResponseApi_ListDemandResponseActions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemandResponseApiServer).ListDemandResponseActions(ctx, req.(*ListDemandResponseActionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DemandResponseApi_ServiceDesc is the grpc.ServiceDesc for DemandResponseApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DemandResponseApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smartcore.bos.DemandResponseApi",
+	HandlerType: (*DemandResponseApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDemandResponseStatus",
+			Handler:    _DemandResponseApi_GetDemandResponseStatus_Handler,
+		},
+		{
+			MethodName: "ListDemandResponseEvents",
+			Handler:    _DemandResponseApi_ListDemandResponseEvents_Handler,
+		},
+		{
+			MethodName: "CreateDemandResponseEvent",
+			Handler:    _DemandResponseApi_CreateDemandResponseEvent_Handler,
+		},
+		{
+			MethodName: "UpdateDemandResponseEvent",
+			Handler:    _DemandResponseApi_UpdateDemandResponseEvent_Handler,
+		},
+		{
+			MethodName: "ListDemandResponseActions",
+			Handler:    _DemandResponseApi_ListDemandResponseActions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "demand_response.proto",
+}
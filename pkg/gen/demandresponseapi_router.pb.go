@@ -0,0 +1,123 @@
+// Code generated by protoc-gen-router. DO NOT EDIT.
+
+package gen
+
+import (
+	context "context"
+	fmt "fmt"
+	router "github.com/smart-core-os/sc-golang/pkg/router"
+	grpc "google.golang.org/grpc"
+)
+
+// DemandResponseApiRouter is a DemandResponseApiServer that allows routing named requests to specific DemandResponseApiClient
+type DemandResponseApiRouter struct {
+	UnimplementedDemandResponseApiServer
+
+	router.Router
+}
+
+// compile time check that we implement the interface we need
+var _ DemandResponseApiServer = (*DemandResponseApiRouter)(nil)
+
+func NewDemandResponseApiRouter(opts ...router.Option) *DemandResponseApiRouter {
+	return &DemandResponseApiRouter{
+		Router: router.NewRouter(opts...),
+	}
+}
+
+// WithDemandResponseApiClientFactory instructs the router to create a new
+// client the first time Get is called for that name.
+func WithDemandResponseApiClientFactory(f func(name string) (DemandResponseApiClient, error)) router.Option {
+	return router.WithFactory(func(name string) (any, error) {
+		return f(name)
+	})
+}
+
+func (r *DemandResponseApiRouter) Register(server grpc.ServiceRegistrar) {
+	RegisterDemandResponseApiServer(server, r)
+}
+
+// Add extends Router.Add to panic if client is not of type DemandResponseApiClient.
+func (r *DemandResponseApiRouter) Add(name string, client any) any {
+	if !r.HoldsType(client) {
+		panic(fmt.Sprintf("not correct type: client of type %T is not a DemandResponseApiClient", client))
+	}
+	return r.Router.Add(name, client)
+}
+
+func (r *DemandResponseApiRouter) HoldsType(client any) bool {
+	_, ok := client.(DemandResponseApiClient)
+	return ok
+}
+
+func (r *DemandResponseApiRouter) AddDemandResponseApiClient(name string, client DemandResponseApiClient) DemandResponseApiClient {
+	res := r.Add(name, client)
+	if res == nil {
+		return nil
+	}
+	return res.(DemandResponseApiClient)
+}
+
+func (r *DemandResponseApiRouter) RemoveDemandResponseApiClient(name string) DemandResponseApiClient {
+	res := r.Remove(name)
+	if res == nil {
+		return nil
+	}
+	return res.(DemandResponseApiClient)
+}
+
+func (r *DemandResponseApiRouter) GetDemandResponseApiClient(name string) (DemandResponseApiClient, error) {
+	res, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(DemandResponseApiClient), nil
+}
+
+func (r *DemandResponseApiRouter) GetDemandResponseStatus(ctx context.Context, request *GetDemandResponseStatusRequest) (*DemandResponseStatus, error) {
+	child, err := r.GetDemandResponseApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.GetDemandResponseStatus(ctx, request)
+}
+
+func (r *DemandResponseApiRouter) ListDemandResponseEvents(ctx context.Context, request *ListDemandResponseEventsRequest) (*ListDemandResponseEventsResponse, error) {
+	child, err := r.GetDemandResponseApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.ListDemandResponseEvents(ctx, request)
+}
+
+func (r *DemandResponseApiRouter) CreateDemandResponseEvent(ctx context.Context, request *CreateDemandResponseEventRequest) (*DemandResponseEvent, error) {
+	child, err := r.GetDemandResponseApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.CreateDemandResponseEvent(ctx, request)
+}
+
+func (r *DemandResponseApiRouter) UpdateDemandResponseEvent(ctx context.Context, request *UpdateDemandResponseEventRequest) (*DemandResponseEvent, error) {
+	child, err := r.GetDemandResponseApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.UpdateDemandResponseEvent(ctx, request)
+}
+
+func (r *DemandResponseApiRouter) ListDemandResponseActions(ctx context.Context, request *ListDemandResponseActionsRequest) (*ListDemandResponseActionsResponse, error) {
+	child, err := r.GetDemandResponseApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.ListDemandResponseActions(ctx, request)
+}
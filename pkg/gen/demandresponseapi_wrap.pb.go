@@ -0,0 +1,42 @@
+// Code generated by protoc-gen-wrapper. DO NOT EDIT.
+
+package gen
+
+import (
+	wrap "github.com/smart-core-os/sc-golang/pkg/wrap"
+	grpc "google.golang.org/grpc"
+)
+
+// WrapDemandResponseApi	adapts a DemandResponseApiServer	and presents it as a DemandResponseApiClient
+func WrapDemandResponseApi(server DemandResponseApiServer) *DemandResponseApiWrapper {
+	conn := wrap.ServerToClient(DemandResponseApi_ServiceDesc, server)
+	client := NewDemandResponseApiClient(conn)
+	return &DemandResponseApiWrapper{
+		DemandResponseApiClient: client,
+		server:                  server,
+		conn:                    conn,
+		desc:                    DemandResponseApi_ServiceDesc,
+	}
+}
+
+type DemandResponseApiWrapper struct {
+	DemandResponseApiClient
+
+	server DemandResponseApiServer
+	conn   grpc.ClientConnInterface
+	desc   grpc.ServiceDesc
+}
+
+// UnwrapServer returns the underlying server instance.
+func (w *DemandResponseApiWrapper) UnwrapServer() DemandResponseApiServer {
+	return w.server
+}
+
+// Unwrap implements wrap.Unwrapper and returns the underlying server instance as an unknown type.
+func (w *DemandResponseApiWrapper) Unwrap() any {
+	return w.UnwrapServer()
+}
+
+func (w *DemandResponseApiWrapper) UnwrapService() (grpc.ClientConnInterface, grpc.ServiceDesc) {
+	return w.conn, w.desc
+}
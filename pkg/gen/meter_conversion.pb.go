@@ -0,0 +1,349 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.32.1
+// source: meter_conversion.proto
+
+package gen
+
+import (
+	time "github.com/smart-core-os/sc-api/go/types/time"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ConvertedUsage is the consumption of a meter over a period, and its carbon and cost.
+type ConvertedUsage struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// Consumption in usage_unit.
+	Usage float64 `protobuf:"fixed64,3,opt,name=usage,proto3" json:"usage,omitempty"`
+	// Emissions in kgCO2e, zero if the fuel has no emission factors.
+	Carbon float64 `protobuf:"fixed64,4,opt,name=carbon,proto3" json:"carbon,omitempty"`
+	// Cost in currency, zero if the fuel has no tariffs.
+	Cost          float64 `protobuf:"fixed64,5,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertedUsage) Reset() {
+	*x = ConvertedUsage{}
+	mi := &file_meter_conversion_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertedUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertedUsage) ProtoMessage() {}
+
+func (x *ConvertedUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_conversion_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertedUsage.ProtoReflect.Descriptor instead.
+func (*ConvertedUsage) Descriptor() ([]byte, []int) {
+	return file_meter_conversion_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ConvertedUsage) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ConvertedUsage) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ConvertedUsage) GetUsage() float64 {
+	if x != nil {
+		return x.Usage
+	}
+	return 0
+}
+
+func (x *ConvertedUsage) GetCarbon() float64 {
+	if x != nil {
+		return x.Carbon
+	}
+	return 0
+}
+
+func (x *ConvertedUsage) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+type ListConvertedMeterReadingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Smart Core name of a meter with recorded history.
+	MeterName string `protobuf:"bytes,1,opt,name=meter_name,json=meterName,proto3" json:"meter_name,omitempty"`
+	// The period to convert consumption for.
+	// Start is required, end defaults to now.
+	Period *time.Period `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	// The length of each entry in the series, starting at period.start_time.
+	// If absent a single entry covering the whole period is returned.
+	Interval *durationpb.Duration `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	// The fuel the meter measures, overriding any configured for the meter.
+	Fuel          string `protobuf:"bytes,4,opt,name=fuel,proto3" json:"fuel,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConvertedMeterReadingsRequest) Reset() {
+	*x = ListConvertedMeterReadingsRequest{}
+	mi := &file_meter_conversion_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConvertedMeterReadingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConvertedMeterReadingsRequest) ProtoMessage() {}
+
+func (x *ListConvertedMeterReadingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_conversion_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConvertedMeterReadingsRequest.ProtoReflect.Descriptor instead.
+func (*ListConvertedMeterReadingsRequest) Descriptor() ([]byte, []int) {
+	return file_meter_conversion_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListConvertedMeterReadingsRequest) GetMeterName() string {
+	if x != nil {
+		return x.MeterName
+	}
+	return ""
+}
+
+func (x *ListConvertedMeterReadingsRequest) GetPeriod() *time.Period {
+	if x != nil {
+		return x.Period
+	}
+	return nil
+}
+
+func (x *ListConvertedMeterReadingsRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *ListConvertedMeterReadingsRequest) GetFuel() string {
+	if x != nil {
+		return x.Fuel
+	}
+	return ""
+}
+
+type ListConvertedMeterReadingsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Consumption for each interval in the period, oldest first.
+	Readings []*ConvertedUsage `protobuf:"bytes,1,rep,name=readings,proto3" json:"readings,omitempty"`
+	// The sum of all readings.
+	Total *ConvertedUsage `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	// The fuel consumption was converted as, e.g. electricity.
+	Fuel string `protobuf:"bytes,3,opt,name=fuel,proto3" json:"fuel,omitempty"`
+	// The unit of all usage in this response, the unit of the fuel, e.g. kWh.
+	UsageUnit string `protobuf:"bytes,4,opt,name=usage_unit,json=usageUnit,proto3" json:"usage_unit,omitempty"`
+	// ISO 4217 currency code of all costs in this response, e.g. GBP.
+	Currency      string `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConvertedMeterReadingsResponse) Reset() {
+	*x = ListConvertedMeterReadingsResponse{}
+	mi := &file_meter_conversion_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConvertedMeterReadingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConvertedMeterReadingsResponse) ProtoMessage() {}
+
+func (x *ListConvertedMeterReadingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_meter_conversion_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConvertedMeterReadingsResponse.ProtoReflect.Descriptor instead.
+func (*ListConvertedMeterReadingsResponse) Descriptor() ([]byte, []int) {
+	return file_meter_conversion_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListConvertedMeterReadingsResponse) GetReadings() []*ConvertedUsage {
+	if x != nil {
+		return x.Readings
+	}
+	return nil
+}
+
+func (x *ListConvertedMeterReadingsResponse) GetTotal() *ConvertedUsage {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *ListConvertedMeterReadingsResponse) GetFuel() string {
+	if x != nil {
+		return x.Fuel
+	}
+	return ""
+}
+
+func (x *ListConvertedMeterReadingsResponse) GetUsageUnit() string {
+	if x != nil {
+		return x.UsageUnit
+	}
+	return ""
+}
+
+func (x *ListConvertedMeterReadingsResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+var File_meter_conversion_proto protoreflect.FileDescriptor
+
+const file_meter_conversion_proto_rawDesc = "" +
+	"\n" +
+	"\x16meter_conversion.proto\x12\rsmartcore.bos\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17types/time/period.proto\"\xc4\x01\n" +
+	"\x0eConvertedUsage\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x14\n" +
+	"\x05usage\x18\x03 \x01(\x01R\x05usage\x12\x16\n" +
+	"\x06carbon\x18\x04 \x01(\x01R\x06carbon\x12\x12\n" +
+	"\x04cost\x18\x05 \x01(\x01R\x04cost\"\xc3\x01\n" +
+	"!ListConvertedMeterReadingsRequest\x12\x1d\n" +
+	"\n" +
+	"meter_name\x18\x01 \x01(\tR\tmeterName\x124\n" +
+	"\x06period\x18\x02 \x01(\v2\x1c.smartcore.types.time.PeriodR\x06period\x125\n" +
+	"\binterval\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x12\n" +
+	"\x04fuel\x18\x04 \x01(\tR\x04fuel\"\xe3\x01\n" +
+	"\"ListConvertedMeterReadingsResponse\x129\n" +
+	"\breadings\x18\x01 \x03(\v2\x1d.smartcore.bos.ConvertedUsageR\breadings\x123\n" +
+	"\x05total\x18\x02 \x01(\v2\x1d.smartcore.bos.ConvertedUsageR\x05total\x12\x12\n" +
+	"\x04fuel\x18\x03 \x01(\tR\x04fuel\x12\x1d\n" +
+	"\n" +
+	"usage_unit\x18\x04 \x01(\tR\tusageUnit\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency2\x98\x01\n" +
+	"\x12MeterConversionApi\x12\x81\x01\n" +
+	"\x1aListConvertedMeterReadings\x120.smartcore.bos.ListConvertedMeterReadingsRequest\x1a1.smartcore.bos.ListConvertedMeterReadingsResponseB)Z'github.com/smart-core-os/sc-bos/pkg/genb\x06proto3"
+
+var (
+	file_meter_conversion_proto_rawDescOnce sync.Once
+	file_meter_conversion_proto_rawDescData []byte
+)
+
+func file_meter_conversion_proto_rawDescGZIP() []byte {
+	file_meter_conversion_proto_rawDescOnce.Do(func() {
+		file_meter_conversion_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_meter_conversion_proto_rawDesc), len(file_meter_conversion_proto_rawDesc)))
+	})
+	return file_meter_conversion_proto_rawDescData
+}
+
+var file_meter_conversion_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_meter_conversion_proto_goTypes = []any{
+	(*ConvertedUsage)(nil),                     // 0: smartcore.bos.ConvertedUsage
+	(*ListConvertedMeterReadingsRequest)(nil),  // 1: smartcore.bos.ListConvertedMeterReadingsRequest
+	(*ListConvertedMeterReadingsResponse)(nil), // 2: smartcore.bos.ListConvertedMeterReadingsResponse
+	(*timestamppb.Timestamp)(nil),              // 3: google.protobuf.Timestamp
+	(*time.Period)(nil),                        // 4: smartcore.types.time.Period
+	(*durationpb.Duration)(nil),                // 5: google.protobuf.Duration
+}
+var file_meter_conversion_proto_depIdxs = []int32{
+	3, // 0: smartcore.bos.ConvertedUsage.start_time:type_name -> google.protobuf.Timestamp
+	3, // 1: smartcore.bos.ConvertedUsage.end_time:type_name -> google.protobuf.Timestamp
+	4, // 2: smartcore.bos.ListConvertedMeterReadingsRequest.period:type_name -> smartcore.types.time.Period
+	5, // 3: smartcore.bos.ListConvertedMeterReadingsRequest.interval:type_name -> google.protobuf.Duration
+	0, // 4: smartcore.bos.ListConvertedMeterReadingsResponse.readings:type_name -> smartcore.bos.ConvertedUsage
+	0, // 5: smartcore.bos.ListConvertedMeterReadingsResponse.total:type_name -> smartcore.bos.ConvertedUsage
+	1, // 6: smartcore.bos.MeterConversionApi.ListConvertedMeterReadings:input_type -> smartcore.bos.ListConvertedMeterReadingsRequest
+	2, // 7: smartcore.bos.MeterConversionApi.ListConvertedMeterReadings:output_type -> smartcore.bos.ListConvertedMeterReadingsResponse
+	7, // [7:8] is the sub-list for method output_type
+	6, // [6:7] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_meter_conversion_proto_init() }
+func file_meter_conversion_proto_init() {
+	if File_meter_conversion_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_meter_conversion_proto_rawDesc), len(file_meter_conversion_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_meter_conversion_proto_goTypes,
+		DependencyIndexes: file_meter_conversion_proto_depIdxs,
+		MessageInfos:      file_meter_conversion_proto_msgTypes,
+	}.Build()
+	File_meter_conversion_proto = out.File
+	file_meter_conversion_proto_goTypes = nil
+	file_meter_conversion_proto_depIdxs = nil
+}
@@ -0,0 +1,129 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: meter_conversion.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MeterConversionApi_ListConvertedMeterReadings_FullMethodName = "/smartcore.bos.MeterConversionApi/ListConvertedMeterReadings"
+)
+
+// MeterConversionApiClient is the client API for MeterConversionApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MeterConversionApi converts recorded meter consumption into carbon emissions and cost.
+// Emission factors and tariffs are configured per fuel type and can vary by time of day and over time,
+// consumption is converted using the factors that applied when it happened.
+type MeterConversionApiClient interface {
+	ListConvertedMeterReadings(ctx context.Context, in *ListConvertedMeterReadingsRequest, opts ...grpc.CallOption) (*ListConvertedMeterReadingsResponse, error)
+}
+
+type meterConversionApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMeterConversionApiClient(cc grpc.ClientConnInterface) MeterConversionApiClient {
+	return &meterConversionApiClient{cc}
+}
+
+func (c *meterConversionApiClient) ListConvertedMeterReadings(ctx context.Context, in *ListConvertedMeterReadingsRequest, opts ...grpc.CallOption) (*ListConvertedMeterReadingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListConvertedMeterReadingsResponse)
+	err := c.cc.Invoke(ctx, MeterConversionApi_ListConvertedMeterReadings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MeterConversionApiServer is the server API for MeterConversionApi service.
+// All implementations must embed UnimplementedMeterConversionApiServer
+// for forward compatibility.
+//
+// MeterConversionApi converts recorded meter consumption into carbon emissions and cost.
+// Emission factors and tariffs are configured per fuel type and can vary by time of day and over time,
+// consumption is converted using the factors that applied when it happened.
+type MeterConversionApiServer interface {
+	ListConvertedMeterReadings(context.Context, *ListConvertedMeterReadingsRequest) (*ListConvertedMeterReadingsResponse, error)
+	mustEmbedUnimplementedMeterConversionApiServer()
+}
+
+// UnimplementedMeterConversionApiServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMeterConversionApiServer struct{}
+
+func (UnimplementedMeterConversionApiServer) ListConvertedMeterReadings(context.Context, *ListConvertedMeterReadingsRequest) (*ListConvertedMeterReadingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConvertedMeterReadings not implemented")
+}
+func (UnimplementedMeterConversionApiServer) mustEmbedUnimplementedMeterConversionApiServer() {}
+func (UnimplementedMeterConversionApiServer) testEmbeddedByValue()                            {}
+
+// UnsafeMeterConversionApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MeterConversionApiServer will
+// result in compilation errors.
+type UnsafeMeterConversionApiServer interface {
+	mustEmbedUnimplementedMeterConversionApiServer()
+}
+
+func RegisterMeterConversionApiServer(s grpc.ServiceRegistrar, srv MeterConversionApiServer) {
+	// If the following call pancis, it indicates UnimplementedMeterConversionApiServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MeterConversionApi_ServiceDesc, srv)
+}
+
+func _MeterConversionApi_ListConvertedMeterReadings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConvertedMeterReadingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeterConversionApiServer).ListConvertedMeterReadings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeterConversionApi_ListConvertedMeterReadings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeterConversionApiServer).ListConvertedMeterReadings(ctx, req.(*ListConvertedMeterReadingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MeterConversionApi_ServiceDesc is the grpc.ServiceDesc for MeterConversionApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MeterConversionApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smartcore.bos.MeterConversionApi",
+	HandlerType: (*MeterConversionApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListConvertedMeterReadings",
+			Handler:    _MeterConversionApi_ListConvertedMeterReadings_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "meter_conversion.proto",
+}
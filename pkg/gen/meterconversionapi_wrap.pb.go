@@ -0,0 +1,42 @@
+// Code generated by protoc-gen-wrapper. DO NOT EDIT.
+
+package gen
+
+import (
+	wrap "github.com/smart-core-os/sc-golang/pkg/wrap"
+	grpc "google.golang.org/grpc"
+)
+
+// WrapMeterConversionApi	adapts a MeterConversionApiServer	and presents it as a MeterConversionApiClient
+func WrapMeterConversionApi(server MeterConversionApiServer) *MeterConversionApiWrapper {
+	conn := wrap.ServerToClient(MeterConversionApi_ServiceDesc, server)
+	client := NewMeterConversionApiClient(conn)
+	return &MeterConversionApiWrapper{
+		MeterConversionApiClient: client,
+		server:                   server,
+		conn:                     conn,
+		desc:                     MeterConversionApi_ServiceDesc,
+	}
+}
+
+type MeterConversionApiWrapper struct {
+	MeterConversionApiClient
+
+	server MeterConversionApiServer
+	conn   grpc.ClientConnInterface
+	desc   grpc.ServiceDesc
+}
+
+// UnwrapServer returns the underlying server instance.
+func (w *MeterConversionApiWrapper) UnwrapServer() MeterConversionApiServer {
+	return w.server
+}
+
+// Unwrap implements wrap.Unwrapper and returns the underlying server instance as an unknown type.
+func (w *MeterConversionApiWrapper) Unwrap() any {
+	return w.UnwrapServer()
+}
+
+func (w *MeterConversionApiWrapper) UnwrapService() (grpc.ClientConnInterface, grpc.ServiceDesc) {
+	return w.conn, w.desc
+}
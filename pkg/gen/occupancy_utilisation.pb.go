@@ -0,0 +1,493 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.32.1
+// source: occupancy_utilisation.proto
+
+package gen
+
+import (
+	time "github.com/smart-core-os/sc-api/go/types/time"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OccupancyUtilisation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The device the metrics are for.
+	DeviceName string `protobuf:"bytes,1,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	// The period the metrics cover.
+	Period *time.Period `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	// The name of the calendar defining business hours.
+	Calendar string `protobuf:"bytes,3,opt,name=calendar,proto3" json:"calendar,omitempty"`
+	// The total business hours within the period.
+	BusinessDuration *durationpb.Duration `protobuf:"bytes,4,opt,name=business_duration,json=businessDuration,proto3" json:"business_duration,omitempty"`
+	// How long the space was occupied during business hours.
+	OccupiedDuration *durationpb.Duration `protobuf:"bytes,5,opt,name=occupied_duration,json=occupiedDuration,proto3" json:"occupied_duration,omitempty"`
+	// The percentage of business hours the space was occupied, 0-100.
+	UtilisationPercent float32 `protobuf:"fixed32,6,opt,name=utilisation_percent,json=utilisationPercent,proto3" json:"utilisation_percent,omitempty"`
+	// The highest people count recorded during business hours.
+	PeakPeopleCount int32 `protobuf:"varint,7,opt,name=peak_people_count,json=peakPeopleCount,proto3" json:"peak_people_count,omitempty"`
+	// When the peak people count was first seen.
+	PeakTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=peak_time,json=peakTime,proto3" json:"peak_time,omitempty"`
+	// The average people count during business hours, weighted by time.
+	AveragePeopleCount float32 `protobuf:"fixed32,9,opt,name=average_people_count,json=averagePeopleCount,proto3" json:"average_people_count,omitempty"`
+	// Booking metrics, absent if the space can't be booked.
+	Bookings *OccupancyUtilisation_Bookings `protobuf:"bytes,10,opt,name=bookings,proto3" json:"bookings,omitempty"`
+	// Occupancy for each hour of the week, present if requested.
+	// Unlike other metrics the heatmap covers all hours, not just business hours.
+	Heatmap       []*OccupancyUtilisation_HourOfWeek `protobuf:"bytes,11,rep,name=heatmap,proto3" json:"heatmap,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OccupancyUtilisation) Reset() {
+	*x = OccupancyUtilisation{}
+	mi := &file_occupancy_utilisation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OccupancyUtilisation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OccupancyUtilisation) ProtoMessage() {}
+
+func (x *OccupancyUtilisation) ProtoReflect() protoreflect.Message {
+	mi := &file_occupancy_utilisation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OccupancyUtilisation.ProtoReflect.Descriptor instead.
+func (*OccupancyUtilisation) Descriptor() ([]byte, []int) {
+	return file_occupancy_utilisation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OccupancyUtilisation) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *OccupancyUtilisation) GetPeriod() *time.Period {
+	if x != nil {
+		return x.Period
+	}
+	return nil
+}
+
+func (x *OccupancyUtilisation) GetCalendar() string {
+	if x != nil {
+		return x.Calendar
+	}
+	return ""
+}
+
+func (x *OccupancyUtilisation) GetBusinessDuration() *durationpb.Duration {
+	if x != nil {
+		return x.BusinessDuration
+	}
+	return nil
+}
+
+func (x *OccupancyUtilisation) GetOccupiedDuration() *durationpb.Duration {
+	if x != nil {
+		return x.OccupiedDuration
+	}
+	return nil
+}
+
+func (x *OccupancyUtilisation) GetUtilisationPercent() float32 {
+	if x != nil {
+		return x.UtilisationPercent
+	}
+	return 0
+}
+
+func (x *OccupancyUtilisation) GetPeakPeopleCount() int32 {
+	if x != nil {
+		return x.PeakPeopleCount
+	}
+	return 0
+}
+
+func (x *OccupancyUtilisation) GetPeakTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PeakTime
+	}
+	return nil
+}
+
+func (x *OccupancyUtilisation) GetAveragePeopleCount() float32 {
+	if x != nil {
+		return x.AveragePeopleCount
+	}
+	return 0
+}
+
+func (x *OccupancyUtilisation) GetBookings() *OccupancyUtilisation_Bookings {
+	if x != nil {
+		return x.Bookings
+	}
+	return nil
+}
+
+func (x *OccupancyUtilisation) GetHeatmap() []*OccupancyUtilisation_HourOfWeek {
+	if x != nil {
+		return x.Heatmap
+	}
+	return nil
+}
+
+type GetOccupancyUtilisationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Smart Core name of a device or zone with recorded OccupancySensor history.
+	DeviceName string `protobuf:"bytes,1,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	// The period to compute metrics for.
+	// Start is required, end defaults to now.
+	Period *time.Period `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	// The name of the calendar defining business hours, defaults to the configured default calendar.
+	Calendar string `protobuf:"bytes,3,opt,name=calendar,proto3" json:"calendar,omitempty"`
+	// Whether to include the hour of week heatmap in the response.
+	IncludeHeatmap bool `protobuf:"varint,4,opt,name=include_heatmap,json=includeHeatmap,proto3" json:"include_heatmap,omitempty"`
+	// Smart Core name of the device implementing BookingApi for the space.
+	// Defaults to device_name, booking metrics are omitted if that device can't list bookings.
+	BookingName   string `protobuf:"bytes,5,opt,name=booking_name,json=bookingName,proto3" json:"booking_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOccupancyUtilisationRequest) Reset() {
+	*x = GetOccupancyUtilisationRequest{}
+	mi := &file_occupancy_utilisation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOccupancyUtilisationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOccupancyUtilisationRequest) ProtoMessage() {}
+
+func (x *GetOccupancyUtilisationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_occupancy_utilisation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOccupancyUtilisationRequest.ProtoReflect.Descriptor instead.
+func (*GetOccupancyUtilisationRequest) Descriptor() ([]byte, []int) {
+	return file_occupancy_utilisation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetOccupancyUtilisationRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *GetOccupancyUtilisationRequest) GetPeriod() *time.Period {
+	if x != nil {
+		return x.Period
+	}
+	return nil
+}
+
+func (x *GetOccupancyUtilisationRequest) GetCalendar() string {
+	if x != nil {
+		return x.Calendar
+	}
+	return ""
+}
+
+func (x *GetOccupancyUtilisationRequest) GetIncludeHeatmap() bool {
+	if x != nil {
+		return x.IncludeHeatmap
+	}
+	return false
+}
+
+func (x *GetOccupancyUtilisationRequest) GetBookingName() string {
+	if x != nil {
+		return x.BookingName
+	}
+	return ""
+}
+
+type OccupancyUtilisation_Bookings struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The number of bookings that started during the period and have finished.
+	BookingCount int32 `protobuf:"varint,1,opt,name=booking_count,json=bookingCount,proto3" json:"booking_count,omitempty"`
+	// The number of those bookings where nobody used the space, they weren't checked in to and the space
+	// wasn't occupied at any time during the booking.
+	NoShowCount int32 `protobuf:"varint,2,opt,name=no_show_count,json=noShowCount,proto3" json:"no_show_count,omitempty"`
+	// no_show_count as a percentage of booking_count, 0-100.
+	NoShowPercent float32 `protobuf:"fixed32,3,opt,name=no_show_percent,json=noShowPercent,proto3" json:"no_show_percent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OccupancyUtilisation_Bookings) Reset() {
+	*x = OccupancyUtilisation_Bookings{}
+	mi := &file_occupancy_utilisation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OccupancyUtilisation_Bookings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OccupancyUtilisation_Bookings) ProtoMessage() {}
+
+func (x *OccupancyUtilisation_Bookings) ProtoReflect() protoreflect.Message {
+	mi := &file_occupancy_utilisation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OccupancyUtilisation_Bookings.ProtoReflect.Descriptor instead.
+func (*OccupancyUtilisation_Bookings) Descriptor() ([]byte, []int) {
+	return file_occupancy_utilisation_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *OccupancyUtilisation_Bookings) GetBookingCount() int32 {
+	if x != nil {
+		return x.BookingCount
+	}
+	return 0
+}
+
+func (x *OccupancyUtilisation_Bookings) GetNoShowCount() int32 {
+	if x != nil {
+		return x.NoShowCount
+	}
+	return 0
+}
+
+func (x *OccupancyUtilisation_Bookings) GetNoShowPercent() float32 {
+	if x != nil {
+		return x.NoShowPercent
+	}
+	return 0
+}
+
+type OccupancyUtilisation_HourOfWeek struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ISO 8601 day of the week, 1 is Monday and 7 is Sunday.
+	DayOfWeek int32 `protobuf:"varint,1,opt,name=day_of_week,json=dayOfWeek,proto3" json:"day_of_week,omitempty"`
+	// The hour of the day in the calendar time zone, 0-23.
+	Hour int32 `protobuf:"varint,2,opt,name=hour,proto3" json:"hour,omitempty"`
+	// How much of the period fell within this hour of the week, the sum of every occurrence of it.
+	SampleDuration *durationpb.Duration `protobuf:"bytes,3,opt,name=sample_duration,json=sampleDuration,proto3" json:"sample_duration,omitempty"`
+	// The percentage of sample_duration the space was occupied, 0-100.
+	OccupiedPercent float32 `protobuf:"fixed32,4,opt,name=occupied_percent,json=occupiedPercent,proto3" json:"occupied_percent,omitempty"`
+	// The average people count during this hour of the week, weighted by time.
+	AveragePeopleCount float32 `protobuf:"fixed32,5,opt,name=average_people_count,json=averagePeopleCount,proto3" json:"average_people_count,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *OccupancyUtilisation_HourOfWeek) Reset() {
+	*x = OccupancyUtilisation_HourOfWeek{}
+	mi := &file_occupancy_utilisation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OccupancyUtilisation_HourOfWeek) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OccupancyUtilisation_HourOfWeek) ProtoMessage() {}
+
+func (x *OccupancyUtilisation_HourOfWeek) ProtoReflect() protoreflect.Message {
+	mi := &file_occupancy_utilisation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OccupancyUtilisation_HourOfWeek.ProtoReflect.Descriptor instead.
+func (*OccupancyUtilisation_HourOfWeek) Descriptor() ([]byte, []int) {
+	return file_occupancy_utilisation_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *OccupancyUtilisation_HourOfWeek) GetDayOfWeek() int32 {
+	if x != nil {
+		return x.DayOfWeek
+	}
+	return 0
+}
+
+func (x *OccupancyUtilisation_HourOfWeek) GetHour() int32 {
+	if x != nil {
+		return x.Hour
+	}
+	return 0
+}
+
+func (x *OccupancyUtilisation_HourOfWeek) GetSampleDuration() *durationpb.Duration {
+	if x != nil {
+		return x.SampleDuration
+	}
+	return nil
+}
+
+func (x *OccupancyUtilisation_HourOfWeek) GetOccupiedPercent() float32 {
+	if x != nil {
+		return x.OccupiedPercent
+	}
+	return 0
+}
+
+func (x *OccupancyUtilisation_HourOfWeek) GetAveragePeopleCount() float32 {
+	if x != nil {
+		return x.AveragePeopleCount
+	}
+	return 0
+}
+
+var File_occupancy_utilisation_proto protoreflect.FileDescriptor
+
+const file_occupancy_utilisation_proto_rawDesc = "" +
+	"\n" +
+	"\x1boccupancy_utilisation.proto\x12\rsmartcore.bos\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17types/time/period.proto\"\xd6\a\n" +
+	"\x14OccupancyUtilisation\x12\x1f\n" +
+	"\vdevice_name\x18\x01 \x01(\tR\n" +
+	"deviceName\x124\n" +
+	"\x06period\x18\x02 \x01(\v2\x1c.smartcore.types.time.PeriodR\x06period\x12\x1a\n" +
+	"\bcalendar\x18\x03 \x01(\tR\bcalendar\x12F\n" +
+	"\x11business_duration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x10businessDuration\x12F\n" +
+	"\x11occupied_duration\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x10occupiedDuration\x12/\n" +
+	"\x13utilisation_percent\x18\x06 \x01(\x02R\x12utilisationPercent\x12*\n" +
+	"\x11peak_people_count\x18\a \x01(\x05R\x0fpeakPeopleCount\x127\n" +
+	"\tpeak_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\bpeakTime\x120\n" +
+	"\x14average_people_count\x18\t \x01(\x02R\x12averagePeopleCount\x12H\n" +
+	"\bbookings\x18\n" +
+	" \x01(\v2,.smartcore.bos.OccupancyUtilisation.BookingsR\bbookings\x12H\n" +
+	"\aheatmap\x18\v \x03(\v2..smartcore.bos.OccupancyUtilisation.HourOfWeekR\aheatmap\x1a{\n" +
+	"\bBookings\x12#\n" +
+	"\rbooking_count\x18\x01 \x01(\x05R\fbookingCount\x12\"\n" +
+	"\rno_show_count\x18\x02 \x01(\x05R\vnoShowCount\x12&\n" +
+	"\x0fno_show_percent\x18\x03 \x01(\x02R\rnoShowPercent\x1a\xe1\x01\n" +
+	"\n" +
+	"HourOfWeek\x12\x1e\n" +
+	"\vday_of_week\x18\x01 \x01(\x05R\tdayOfWeek\x12\x12\n" +
+	"\x04hour\x18\x02 \x01(\x05R\x04hour\x12B\n" +
+	"\x0fsample_duration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x0esampleDuration\x12)\n" +
+	"\x10occupied_percent\x18\x04 \x01(\x02R\x0foccupiedPercent\x120\n" +
+	"\x14average_people_count\x18\x05 \x01(\x02R\x12averagePeopleCount\"\xdf\x01\n" +
+	"\x1eGetOccupancyUtilisationRequest\x12\x1f\n" +
+	"\vdevice_name\x18\x01 \x01(\tR\n" +
+	"deviceName\x124\n" +
+	"\x06period\x18\x02 \x01(\v2\x1c.smartcore.types.time.PeriodR\x06period\x12\x1a\n" +
+	"\bcalendar\x18\x03 \x01(\tR\bcalendar\x12'\n" +
+	"\x0finclude_heatmap\x18\x04 \x01(\bR\x0eincludeHeatmap\x12!\n" +
+	"\fbooking_name\x18\x05 \x01(\tR\vbookingName2\x88\x01\n" +
+	"\x17OccupancyUtilisationApi\x12m\n" +
+	"\x17GetOccupancyUtilisation\x12-.smartcore.bos.GetOccupancyUtilisationRequest\x1a#.smartcore.bos.OccupancyUtilisationB)Z'github.com/smart-core-os/sc-bos/pkg/genb\x06proto3"
+
+var (
+	file_occupancy_utilisation_proto_rawDescOnce sync.Once
+	file_occupancy_utilisation_proto_rawDescData []byte
+)
+
+func file_occupancy_utilisation_proto_rawDescGZIP() []byte {
+	file_occupancy_utilisation_proto_rawDescOnce.Do(func() {
+		file_occupancy_utilisation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_occupancy_utilisation_proto_rawDesc), len(file_occupancy_utilisation_proto_rawDesc)))
+	})
+	return file_occupancy_utilisation_proto_rawDescData
+}
+
+var file_occupancy_utilisation_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_occupancy_utilisation_proto_goTypes = []any{
+	(*OccupancyUtilisation)(nil),            // 0: smartcore.bos.OccupancyUtilisation
+	(*GetOccupancyUtilisationRequest)(nil),  // 1: smartcore.bos.GetOccupancyUtilisationRequest
+	(*OccupancyUtilisation_Bookings)(nil),   // 2: smartcore.bos.OccupancyUtilisation.Bookings
+	(*OccupancyUtilisation_HourOfWeek)(nil), // 3: smartcore.bos.OccupancyUtilisation.HourOfWeek
+	(*time.Period)(nil),                     // 4: smartcore.types.time.Period
+	(*durationpb.Duration)(nil),             // 5: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),           // 6: google.protobuf.Timestamp
+}
+var file_occupancy_utilisation_proto_depIdxs = []int32{
+	4, // 0: smartcore.bos.OccupancyUtilisation.period:type_name -> smartcore.types.time.Period
+	5, // 1: smartcore.bos.OccupancyUtilisation.business_duration:type_name -> google.protobuf.Duration
+	5, // 2: smartcore.bos.OccupancyUtilisation.occupied_duration:type_name -> google.protobuf.Duration
+	6, // 3: smartcore.bos.OccupancyUtilisation.peak_time:type_name -> google.protobuf.Timestamp
+	2, // 4: smartcore.bos.OccupancyUtilisation.bookings:type_name -> smartcore.bos.OccupancyUtilisation.Bookings
+	3, // 5: smartcore.bos.OccupancyUtilisation.heatmap:type_name -> smartcore.bos.OccupancyUtilisation.HourOfWeek
+	4, // 6: smartcore.bos.GetOccupancyUtilisationRequest.period:type_name -> smartcore.types.time.Period
+	5, // 7: smartcore.bos.OccupancyUtilisation.HourOfWeek.sample_duration:type_name -> google.protobuf.Duration
+	1, // 8: smartcore.bos.OccupancyUtilisationApi.GetOccupancyUtilisation:input_type -> smartcore.bos.GetOccupancyUtilisationRequest
+	0, // 9: smartcore.bos.OccupancyUtilisationApi.GetOccupancyUtilisation:output_type -> smartcore.bos.OccupancyUtilisation
+	9, // [9:10] is the sub-list for method output_type
+	8, // [8:9] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_occupancy_utilisation_proto_init() }
+func file_occupancy_utilisation_proto_init() {
+	if File_occupancy_utilisation_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_occupancy_utilisation_proto_rawDesc), len(file_occupancy_utilisation_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_occupancy_utilisation_proto_goTypes,
+		DependencyIndexes: file_occupancy_utilisation_proto_depIdxs,
+		MessageInfos:      file_occupancy_utilisation_proto_msgTypes,
+	}.Build()
+	File_occupancy_utilisation_proto = out.File
+	file_occupancy_utilisation_proto_goTypes = nil
+	file_occupancy_utilisation_proto_depIdxs = nil
+}
@@ -0,0 +1,130 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: occupancy_utilisation.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OccupancyUtilisationApi_GetOccupancyUtilisation_FullMethodName = "/smartcore.bos.OccupancyUtilisationApi/GetOccupancyUtilisation"
+)
+
+// OccupancyUtilisationApiClient is the client API for OccupancyUtilisationApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// OccupancyUtilisationApi computes utilisation metrics for spaces from their recorded occupancy history.
+// Metrics are calculated for business hours, as defined by a named calendar, so out of hours vacancy doesn't lower
+// utilisation.
+type OccupancyUtilisationApiClient interface {
+	GetOccupancyUtilisation(ctx context.Context, in *GetOccupancyUtilisationRequest, opts ...grpc.CallOption) (*OccupancyUtilisation, error)
+}
+
+type occupancyUtilisationApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOccupancyUtilisationApiClient(cc grpc.ClientConnInterface) OccupancyUtilisationApiClient {
+	return &occupancyUtilisationApiClient{cc}
+}
+
+func (c *occupancyUtilisationApiClient) GetOccupancyUtilisation(ctx context.Context, in *GetOccupancyUtilisationRequest, opts ...grpc.CallOption) (*OccupancyUtilisation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OccupancyUtilisation)
+	err := c.cc.Invoke(ctx, OccupancyUtilisationApi_GetOccupancyUtilisation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OccupancyUtilisationApiServer is the server API for OccupancyUtilisationApi service.
+// All implementations must embed UnimplementedOccupancyUtilisationApiServer
+// for forward compatibility.
+//
+// OccupancyUtilisationApi computes utilisation metrics for spaces from their recorded occupancy history.
+// Metrics are calculated for business hours, as defined by a named calendar, so out of hours vacancy doesn't lower
+// utilisation.
+type OccupancyUtilisationApiServer interface {
+	GetOccupancyUtilisation(context.Context, *GetOccupancyUtilisationRequest) (*OccupancyUtilisation, error)
+	mustEmbedUnimplementedOccupancyUtilisationApiServer()
+}
+
+// UnimplementedOccupancyUtilisationApiServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOccupancyUtilisationApiServer struct{}
+
+func (UnimplementedOccupancyUtilisationApiServer) GetOccupancyUtilisation(context.Context, *GetOccupancyUtilisationRequest) (*OccupancyUtilisation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOccupancyUtilisation not implemented")
+}
+func (UnimplementedOccupancyUtilisationApiServer) mustEmbedUnimplementedOccupancyUtilisationApiServer() {
+}
+func (UnimplementedOccupancyUtilisationApiServer) testEmbeddedByValue() {}
+
+// UnsafeOccupancyUtilisationApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OccupancyUtilisationApiServer will
+// result in compilation errors.
+type UnsafeOccupancyUtilisationApiServer interface {
+	mustEmbedUnimplementedOccupancyUtilisationApiServer()
+}
+
+func RegisterOccupancyUtilisationApiServer(s grpc.ServiceRegistrar, srv OccupancyUtilisationApiServer) {
+	// If the following call pancis, it indicates UnimplementedOccupancyUtilisationApiServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OccupancyUtilisationApi_ServiceDesc, srv)
+}
+
+func _OccupancyUtilisationApi_GetOccupancyUtilisation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOccupancyUtilisationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OccupancyUtilisationApiServer).GetOccupancyUtilisation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OccupancyUtilisationApi_GetOccupancyUtilisation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OccupancyUtilisationApiServer).GetOccupancyUtilisation(ctx, req.(*GetOccupancyUtilisationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OccupancyUtilisationApi_ServiceDesc is the grpc.ServiceDesc for OccupancyUtilisationApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OccupancyUtilisationApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smartcore.bos.OccupancyUtilisationApi",
+	HandlerType: (*OccupancyUtilisationApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOccupancyUtilisation",
+			Handler:    _OccupancyUtilisationApi_GetOccupancyUtilisation_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "occupancy_utilisation.proto",
+}
@@ -0,0 +1,42 @@
+// Code generated by protoc-gen-wrapper. DO NOT EDIT.
+
+package gen
+
+import (
+	wrap "github.com/smart-core-os/sc-golang/pkg/wrap"
+	grpc "google.golang.org/grpc"
+)
+
+// WrapOccupancyUtilisationApi	adapts a OccupancyUtilisationApiServer	and presents it as a OccupancyUtilisationApiClient
+func WrapOccupancyUtilisationApi(server OccupancyUtilisationApiServer) *OccupancyUtilisationApiWrapper {
+	conn := wrap.ServerToClient(OccupancyUtilisationApi_ServiceDesc, server)
+	client := NewOccupancyUtilisationApiClient(conn)
+	return &OccupancyUtilisationApiWrapper{
+		OccupancyUtilisationApiClient: client,
+		server:                        server,
+		conn:                          conn,
+		desc:                          OccupancyUtilisationApi_ServiceDesc,
+	}
+}
+
+type OccupancyUtilisationApiWrapper struct {
+	OccupancyUtilisationApiClient
+
+	server OccupancyUtilisationApiServer
+	conn   grpc.ClientConnInterface
+	desc   grpc.ServiceDesc
+}
+
+// UnwrapServer returns the underlying server instance.
+func (w *OccupancyUtilisationApiWrapper) UnwrapServer() OccupancyUtilisationApiServer {
+	return w.server
+}
+
+// Unwrap implements wrap.Unwrapper and returns the underlying server instance as an unknown type.
+func (w *OccupancyUtilisationApiWrapper) Unwrap() any {
+	return w.UnwrapServer()
+}
+
+func (w *OccupancyUtilisationApiWrapper) UnwrapService() (grpc.ClientConnInterface, grpc.ServiceDesc) {
+	return w.conn, w.desc
+}
@@ -0,0 +1,576 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.32.1
+// source: secret_store.proto
+
+package gen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StoredSecret describes a secret stored by a node, without its value.
+type StoredSecret struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name config uses to refer to the secret, for example "mqtt/broker".
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Increases each time the value of the secret changes, starting at 1.
+	Version    int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// When the value of the secret last changed.
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoredSecret) Reset() {
+	*x = StoredSecret{}
+	mi := &file_secret_store_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoredSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredSecret) ProtoMessage() {}
+
+func (x *StoredSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_secret_store_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredSecret.ProtoReflect.Descriptor instead.
+func (*StoredSecret) Descriptor() ([]byte, []int) {
+	return file_secret_store_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StoredSecret) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StoredSecret) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StoredSecret) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *StoredSecret) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+type ListStoredSecretsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Fields to fetch relative to the StoredSecret type.
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// The maximum number of secrets to return.
+	// The service may return fewer than this value.
+	// If unspecified, at most 50 items will be returned.
+	// The maximum value is 1000; values above 1000 will be coerced to 1000.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// A page token, received from a previous `ListStoredSecretsResponse` call.
+	// Provide this to retrieve the subsequent page.
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStoredSecretsRequest) Reset() {
+	*x = ListStoredSecretsRequest{}
+	mi := &file_secret_store_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStoredSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStoredSecretsRequest) ProtoMessage() {}
+
+func (x *ListStoredSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secret_store_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStoredSecretsRequest.ProtoReflect.Descriptor instead.
+func (*ListStoredSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_secret_store_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListStoredSecretsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListStoredSecretsRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *ListStoredSecretsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListStoredSecretsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListStoredSecretsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Secrets sorted by name.
+	StoredSecrets []*StoredSecret `protobuf:"bytes,1,rep,name=stored_secrets,json=storedSecrets,proto3" json:"stored_secrets,omitempty"`
+	// A token, which can be sent as `page_token` to retrieve the next page.
+	// If this field is omitted, there are no subsequent pages.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// The total number of secrets.
+	TotalSize     int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStoredSecretsResponse) Reset() {
+	*x = ListStoredSecretsResponse{}
+	mi := &file_secret_store_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStoredSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStoredSecretsResponse) ProtoMessage() {}
+
+func (x *ListStoredSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_secret_store_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStoredSecretsResponse.ProtoReflect.Descriptor instead.
+func (*ListStoredSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_secret_store_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListStoredSecretsResponse) GetStoredSecrets() []*StoredSecret {
+	if x != nil {
+		return x.StoredSecrets
+	}
+	return nil
+}
+
+func (x *ListStoredSecretsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListStoredSecretsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type SetStoredSecretRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The name of the secret.
+	SecretName string `protobuf:"bytes,2,opt,name=secret_name,json=secretName,proto3" json:"secret_name,omitempty"`
+	// The new value of the secret.
+	// Named so the audit log redacts it from recorded requests.
+	SecretValue   string `protobuf:"bytes,3,opt,name=secret_value,json=secretValue,proto3" json:"secret_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetStoredSecretRequest) Reset() {
+	*x = SetStoredSecretRequest{}
+	mi := &file_secret_store_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetStoredSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetStoredSecretRequest) ProtoMessage() {}
+
+func (x *SetStoredSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secret_store_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetStoredSecretRequest.ProtoReflect.Descriptor instead.
+func (*SetStoredSecretRequest) Descriptor() ([]byte, []int) {
+	return file_secret_store_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetStoredSecretRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetStoredSecretRequest) GetSecretName() string {
+	if x != nil {
+		return x.SecretName
+	}
+	return ""
+}
+
+func (x *SetStoredSecretRequest) GetSecretValue() string {
+	if x != nil {
+		return x.SecretValue
+	}
+	return ""
+}
+
+type RotateStoredSecretRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The name of the secret.
+	SecretName string `protobuf:"bytes,2,opt,name=secret_name,json=secretName,proto3" json:"secret_name,omitempty"`
+	// The new value of the secret.
+	// Named so the audit log redacts it from recorded requests.
+	SecretValue   string `protobuf:"bytes,3,opt,name=secret_value,json=secretValue,proto3" json:"secret_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateStoredSecretRequest) Reset() {
+	*x = RotateStoredSecretRequest{}
+	mi := &file_secret_store_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateStoredSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateStoredSecretRequest) ProtoMessage() {}
+
+func (x *RotateStoredSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secret_store_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateStoredSecretRequest.ProtoReflect.Descriptor instead.
+func (*RotateStoredSecretRequest) Descriptor() ([]byte, []int) {
+	return file_secret_store_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RotateStoredSecretRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RotateStoredSecretRequest) GetSecretName() string {
+	if x != nil {
+		return x.SecretName
+	}
+	return ""
+}
+
+func (x *RotateStoredSecretRequest) GetSecretValue() string {
+	if x != nil {
+		return x.SecretValue
+	}
+	return ""
+}
+
+type DeleteStoredSecretRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the node.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The name of the secret.
+	SecretName string `protobuf:"bytes,2,opt,name=secret_name,json=secretName,proto3" json:"secret_name,omitempty"`
+	// If true, deleting a secret that doesn't exist is not an error.
+	AllowMissing  bool `protobuf:"varint,3,opt,name=allow_missing,json=allowMissing,proto3" json:"allow_missing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteStoredSecretRequest) Reset() {
+	*x = DeleteStoredSecretRequest{}
+	mi := &file_secret_store_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteStoredSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteStoredSecretRequest) ProtoMessage() {}
+
+func (x *DeleteStoredSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_secret_store_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteStoredSecretRequest.ProtoReflect.Descriptor instead.
+func (*DeleteStoredSecretRequest) Descriptor() ([]byte, []int) {
+	return file_secret_store_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteStoredSecretRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DeleteStoredSecretRequest) GetSecretName() string {
+	if x != nil {
+		return x.SecretName
+	}
+	return ""
+}
+
+func (x *DeleteStoredSecretRequest) GetAllowMissing() bool {
+	if x != nil {
+		return x.AllowMissing
+	}
+	return false
+}
+
+type DeleteStoredSecretResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteStoredSecretResponse) Reset() {
+	*x = DeleteStoredSecretResponse{}
+	mi := &file_secret_store_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteStoredSecretResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteStoredSecretResponse) ProtoMessage() {}
+
+func (x *DeleteStoredSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_secret_store_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteStoredSecretResponse.ProtoReflect.Descriptor instead.
+func (*DeleteStoredSecretResponse) Descriptor() ([]byte, []int) {
+	return file_secret_store_proto_rawDescGZIP(), []int{6}
+}
+
+var File_secret_store_proto protoreflect.FileDescriptor
+
+const file_secret_store_proto_rawDesc = "" +
+	"\n" +
+	"\x12secret_store.proto\x12\rsmartcore.bos\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb6\x01\n" +
+	"\fStoredSecret\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12;\n" +
+	"\vcreate_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12;\n" +
+	"\vupdate_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime\"\xa3\x01\n" +
+	"\x18ListStoredSecretsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\xa6\x01\n" +
+	"\x19ListStoredSecretsResponse\x12B\n" +
+	"\x0estored_secrets\x18\x01 \x03(\v2\x1b.smartcore.bos.StoredSecretR\rstoredSecrets\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"p\n" +
+	"\x16SetStoredSecretRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vsecret_name\x18\x02 \x01(\tR\n" +
+	"secretName\x12!\n" +
+	"\fsecret_value\x18\x03 \x01(\tR\vsecretValue\"s\n" +
+	"\x19RotateStoredSecretRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vsecret_name\x18\x02 \x01(\tR\n" +
+	"secretName\x12!\n" +
+	"\fsecret_value\x18\x03 \x01(\tR\vsecretValue\"u\n" +
+	"\x19DeleteStoredSecretRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vsecret_name\x18\x02 \x01(\tR\n" +
+	"secretName\x12#\n" +
+	"\rallow_missing\x18\x03 \x01(\bR\fallowMissing\"\x1c\n" +
+	"\x1aDeleteStoredSecretResponse2\x97\x03\n" +
+	"\x0eSecretStoreApi\x12f\n" +
+	"\x11ListStoredSecrets\x12'.smartcore.bos.ListStoredSecretsRequest\x1a(.smartcore.bos.ListStoredSecretsResponse\x12U\n" +
+	"\x0fSetStoredSecret\x12%.smartcore.bos.SetStoredSecretRequest\x1a\x1b.smartcore.bos.StoredSecret\x12[\n" +
+	"\x12RotateStoredSecret\x12(.smartcore.bos.RotateStoredSecretRequest\x1a\x1b.smartcore.bos.StoredSecret\x12i\n" +
+	"\x12DeleteStoredSecret\x12(.smartcore.bos.DeleteStoredSecretRequest\x1a).smartcore.bos.DeleteStoredSecretResponseB)Z'github.com/smart-core-os/sc-bos/pkg/genb\x06proto3"
+
+var (
+	file_secret_store_proto_rawDescOnce sync.Once
+	file_secret_store_proto_rawDescData []byte
+)
+
+func file_secret_store_proto_rawDescGZIP() []byte {
+	file_secret_store_proto_rawDescOnce.Do(func() {
+		file_secret_store_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_secret_store_proto_rawDesc), len(file_secret_store_proto_rawDesc)))
+	})
+	return file_secret_store_proto_rawDescData
+}
+
+var file_secret_store_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_secret_store_proto_goTypes = []any{
+	(*StoredSecret)(nil),               // 0: smartcore.bos.StoredSecret
+	(*ListStoredSecretsRequest)(nil),   // 1: smartcore.bos.ListStoredSecretsRequest
+	(*ListStoredSecretsResponse)(nil),  // 2: smartcore.bos.ListStoredSecretsResponse
+	(*SetStoredSecretRequest)(nil),     // 3: smartcore.bos.SetStoredSecretRequest
+	(*RotateStoredSecretRequest)(nil),  // 4: smartcore.bos.RotateStoredSecretRequest
+	(*DeleteStoredSecretRequest)(nil),  // 5: smartcore.bos.DeleteStoredSecretRequest
+	(*DeleteStoredSecretResponse)(nil), // 6: smartcore.bos.DeleteStoredSecretResponse
+	(*timestamppb.Timestamp)(nil),      // 7: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),      // 8: google.protobuf.FieldMask
+}
+var file_secret_store_proto_depIdxs = []int32{
+	7, // 0: smartcore.bos.StoredSecret.create_time:type_name -> google.protobuf.Timestamp
+	7, // 1: smartcore.bos.StoredSecret.update_time:type_name -> google.protobuf.Timestamp
+	8, // 2: smartcore.bos.ListStoredSecretsRequest.read_mask:type_name -> google.protobuf.FieldMask
+	0, // 3: smartcore.bos.ListStoredSecretsResponse.stored_secrets:type_name -> smartcore.bos.StoredSecret
+	1, // 4: smartcore.bos.SecretStoreApi.ListStoredSecrets:input_type -> smartcore.bos.ListStoredSecretsRequest
+	3, // 5: smartcore.bos.SecretStoreApi.SetStoredSecret:input_type -> smartcore.bos.SetStoredSecretRequest
+	4, // 6: smartcore.bos.SecretStoreApi.RotateStoredSecret:input_type -> smartcore.bos.RotateStoredSecretRequest
+	5, // 7: smartcore.bos.SecretStoreApi.DeleteStoredSecret:input_type -> smartcore.bos.DeleteStoredSecretRequest
+	2, // 8: smartcore.bos.SecretStoreApi.ListStoredSecrets:output_type -> smartcore.bos.ListStoredSecretsResponse
+	0, // 9: smartcore.bos.SecretStoreApi.SetStoredSecret:output_type -> smartcore.bos.StoredSecret
+	0, // 10: smartcore.bos.SecretStoreApi.RotateStoredSecret:output_type -> smartcore.bos.StoredSecret
+	6, // 11: smartcore.bos.SecretStoreApi.DeleteStoredSecret:output_type -> smartcore.bos.DeleteStoredSecretResponse
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_secret_store_proto_init() }
+func file_secret_store_proto_init() {
+	if File_secret_store_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_secret_store_proto_rawDesc), len(file_secret_store_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_secret_store_proto_goTypes,
+		DependencyIndexes: file_secret_store_proto_depIdxs,
+		MessageInfos:      file_secret_store_proto_msgTypes,
+	}.Build()
+	File_secret_store_proto = out.File
+	file_secret_store_proto_goTypes = nil
+	file_secret_store_proto_depIdxs = nil
+}
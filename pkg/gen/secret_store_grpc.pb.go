@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: secret_store.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SecretStoreApi_ListStoredSecrets_FullMethodName  = "/smartcore.bos.SecretStoreApi/ListStoredSecrets"
+	SecretStoreApi_SetStoredSecret_FullMethodName    = "/smartcore.bos.SecretStoreApi/SetStoredSecret"
+	SecretStoreApi_RotateStoredSecret_FullMethodName = "/smartcore.bos.SecretStoreApi/RotateStoredSecret"
+	SecretStoreApi_DeleteStoredSecret_FullMethodName = "/smartcore.bos.SecretStoreApi/DeleteStoredSecret"
+)
+
+// SecretStoreApiClient is the client API for SecretStoreApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SecretStoreApi manages the secrets of a node, like passwords and API keys used by drivers and automations.
+// Secrets are encrypted at rest, and are referenced by name from config like {"$secret": "mqtt/broker"}.
+//
+// Secret values can be set but never read back using this API.
+type SecretStoreApiClient interface {
+	ListStoredSecrets(ctx context.Context, in *ListStoredSecretsRequest, opts ...grpc.CallOption) (*ListStoredSecretsResponse, error)
+	// Set the value of a secret, creating it if it doesn't exist.
+	SetStoredSecret(ctx context.Context, in *SetStoredSecretRequest, opts ...grpc.CallOption) (*StoredSecret, error)
+	// Replace the value of an existing secret.
+	// Unlike SetStoredSecret, a NotFound error is returned if the secret doesn't exist.
+	RotateStoredSecret(ctx context.Context, in *RotateStoredSecretRequest, opts ...grpc.CallOption) (*StoredSecret, error)
+	DeleteStoredSecret(ctx context.Context, in *DeleteStoredSecretRequest, opts ...grpc.CallOption) (*DeleteStoredSecretResponse, error)
+}
+
+type secretStoreApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSecretStoreApiClient(cc grpc.ClientConnInterface) SecretStoreApiClient {
+	return &secretStoreApiClient{cc}
+}
+
+func (c *secretStoreApiClient) ListStoredSecrets(ctx context.Context, in *ListStoredSecretsRequest, opts ...grpc.CallOption) (*ListStoredSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStoredSecretsResponse)
+	err := c.cc.Invoke(ctx, SecretStoreApi_ListStoredSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretStoreApiClient) SetStoredSecret(ctx context.Context, in *SetStoredSecretRequest, opts ...grpc.CallOption) (*StoredSecret, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StoredSecret)
+	err := c.cc.Invoke(ctx, SecretStoreApi_SetStoredSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretStoreApiClient) RotateStoredSecret(ctx context.Context, in *RotateStoredSecretRequest, opts ...grpc.CallOption) (*StoredSecret, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StoredSecret)
+	err := c.cc.Invoke(ctx, SecretStoreApi_RotateStoredSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretStoreApiClient) DeleteStoredSecret(ctx context.Context, in *DeleteStoredSecretRequest, opts ...grpc.CallOption) (*DeleteStoredSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteStoredSecretResponse)
+	err := c.cc.Invoke(ctx, SecretStoreApi_DeleteStoredSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SecretStoreApiServer is the server API for SecretStoreApi service.
+// All implementations must embed UnimplementedSecretStoreApiServer
+// for forward compatibility.
+//
+// SecretStoreApi manages the secrets of a node, like passwords and API keys used by drivers and automations.
+// Secrets are encrypted at rest, and are referenced by name from config like {"$secret": "mqtt/broker"}.
+//
+// Secret values can be set but never read back using this API.
+type SecretStoreApiServer interface {
+	ListStoredSecrets(context.Context, *ListStoredSecretsRequest) (*ListStoredSecretsResponse, error)
+	// Set the value of a secret, creating it if it doesn't exist.
+	SetStoredSecret(context.Context, *SetStoredSecretRequest) (*StoredSecret, error)
+	// Replace the value of an existing secret.
+	// Unlike SetStoredSecret, a NotFound error is returned if the secret doesn't exist.
+	RotateStoredSecret(context.Context, *RotateStoredSecretRequest) (*StoredSecret, error)
+	DeleteStoredSecret(context.Context, *DeleteStoredSecretRequest) (*DeleteStoredSecretResponse, error)
+	mustEmbedUnimplementedSecretStoreApiServer()
+}
+
+// UnimplementedSecretStoreApiServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSecretStoreApiServer struct{}
+
+func (UnimplementedSecretStoreApiServer) ListStoredSecrets(context.Context, *ListStoredSecretsRequest) (*ListStoredSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStoredSecrets not implemented")
+}
+func (UnimplementedSecretStoreApiServer) SetStoredSecret(context.Context, *SetStoredSecretRequest) (*StoredSecret, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetStoredSecret not implemented")
+}
+func (UnimplementedSecretStoreApiServer) RotateStoredSecret(context.Context, *RotateStoredSecretRequest) (*StoredSecret, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateStoredSecret not implemented")
+}
+func (UnimplementedSecretStoreApiServer) DeleteStoredSecret(context.Context, *DeleteStoredSecretRequest) (*DeleteStoredSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteStoredSecret not implemented")
+}
+func (UnimplementedSecretStoreApiServer) mustEmbedUnimplementedSecretStoreApiServer() {}
+func (UnimplementedSecretStoreApiServer) testEmbeddedByValue()                        {}
+
+// UnsafeSecretStoreApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SecretStoreApiServer will
+// result in compilation errors.
+type UnsafeSecretStoreApiServer interface {
+	mustEmbedUnimplementedSecretStoreApiServer()
+}
+
+func RegisterSecretStoreApiServer(s grpc.ServiceRegistrar, srv SecretStoreApiServer) {
+	// If the following call pancis, it indicates UnimplementedSecretStoreApiServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SecretStoreApi_ServiceDesc, srv)
+}
+
+func _SecretStoreApi_ListStoredSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStoredSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretStoreApiServer).ListStoredSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SecretStoreApi_ListStoredSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretStoreApiServer).ListStoredSecrets(ctx, req.(*ListStoredSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretStoreApi_SetStoredSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStoredSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretStoreApiServer).SetStoredSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SecretStoreApi_SetStoredSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretStoreApiServer).SetStoredSecret(ctx, req.(*SetStoredSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretStoreApi_RotateStoredSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateStoredSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretStoreApiServer).RotateStoredSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SecretStoreApi_RotateStoredSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretStoreApiServer).RotateStoredSecret(ctx, req.(*RotateStoredSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretStoreApi_DeleteStoredSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteStoredSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretStoreApiServer).DeleteStoredSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SecretStoreApi_DeleteStoredSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretStoreApiServer).DeleteStoredSecret(ctx, req.(*DeleteStoredSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SecretStoreApi_ServiceDesc is the grpc.ServiceDesc for SecretStoreApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SecretStoreApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smartcore.bos.SecretStoreApi",
+	HandlerType: (*SecretStoreApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListStoredSecrets",
+			Handler:    _SecretStoreApi_ListStoredSecrets_Handler,
+		},
+		{
+			MethodName: "SetStoredSecret",
+			Handler:    _SecretStoreApi_SetStoredSecret_Handler,
+		},
+		{
+			MethodName: "RotateStoredSecret",
+			Handler:    _SecretStoreApi_RotateStoredSecret_Handler,
+		},
+		{
+			MethodName: "DeleteStoredSecret",
+			Handler:    _SecretStoreApi_DeleteStoredSecret_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "secret_store.proto",
+}
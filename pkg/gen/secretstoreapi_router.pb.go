@@ -0,0 +1,114 @@
+// Code generated by protoc-gen-router. DO NOT EDIT.
+
+package gen
+
+import (
+	context "context"
+	fmt "fmt"
+	router "github.com/smart-core-os/sc-golang/pkg/router"
+	grpc "google.golang.org/grpc"
+)
+
+// SecretStoreApiRouter is a SecretStoreApiServer that allows routing named requests to specific SecretStoreApiClient
+type SecretStoreApiRouter struct {
+	UnimplementedSecretStoreApiServer
+
+	router.Router
+}
+
+// compile time check that we implement the interface we need
+var _ SecretStoreApiServer = (*SecretStoreApiRouter)(nil)
+
+func NewSecretStoreApiRouter(opts ...router.Option) *SecretStoreApiRouter {
+	return &SecretStoreApiRouter{
+		Router: router.NewRouter(opts...),
+	}
+}
+
+// WithSecretStoreApiClientFactory instructs the router to create a new
+// client the first time Get is called for that name.
+func WithSecretStoreApiClientFactory(f func(name string) (SecretStoreApiClient, error)) router.Option {
+	return router.WithFactory(func(name string) (any, error) {
+		return f(name)
+	})
+}
+
+func (r *SecretStoreApiRouter) Register(server grpc.ServiceRegistrar) {
+	RegisterSecretStoreApiServer(server, r)
+}
+
+// Add extends Router.Add to panic if client is not of type SecretStoreApiClient.
+func (r *SecretStoreApiRouter) Add(name string, client any) any {
+	if !r.HoldsType(client) {
+		panic(fmt.Sprintf("not correct type: client of type %T is not a SecretStoreApiClient", client))
+	}
+	return r.Router.Add(name, client)
+}
+
+func (r *SecretStoreApiRouter) HoldsType(client any) bool {
+	_, ok := client.(SecretStoreApiClient)
+	return ok
+}
+
+func (r *SecretStoreApiRouter) AddSecretStoreApiClient(name string, client SecretStoreApiClient) SecretStoreApiClient {
+	res := r.Add(name, client)
+	if res == nil {
+		return nil
+	}
+	return res.(SecretStoreApiClient)
+}
+
+func (r *SecretStoreApiRouter) RemoveSecretStoreApiClient(name string) SecretStoreApiClient {
+	res := r.Remove(name)
+	if res == nil {
+		return nil
+	}
+	return res.(SecretStoreApiClient)
+}
+
+func (r *SecretStoreApiRouter) GetSecretStoreApiClient(name string) (SecretStoreApiClient, error) {
+	res, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(SecretStoreApiClient), nil
+}
+
+func (r *SecretStoreApiRouter) ListStoredSecrets(ctx context.Context, request *ListStoredSecretsRequest) (*ListStoredSecretsResponse, error) {
+	child, err := r.GetSecretStoreApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.ListStoredSecrets(ctx, request)
+}
+
+func (r *SecretStoreApiRouter) SetStoredSecret(ctx context.Context, request *SetStoredSecretRequest) (*StoredSecret, error) {
+	child, err := r.GetSecretStoreApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.SetStoredSecret(ctx, request)
+}
+
+func (r *SecretStoreApiRouter) RotateStoredSecret(ctx context.Context, request *RotateStoredSecretRequest) (*StoredSecret, error) {
+	child, err := r.GetSecretStoreApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.RotateStoredSecret(ctx, request)
+}
+
+func (r *SecretStoreApiRouter) DeleteStoredSecret(ctx context.Context, request *DeleteStoredSecretRequest) (*DeleteStoredSecretResponse, error) {
+	child, err := r.GetSecretStoreApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.DeleteStoredSecret(ctx, request)
+}
@@ -0,0 +1,42 @@
+// Code generated by protoc-gen-wrapper. DO NOT EDIT.
+
+package gen
+
+import (
+	wrap "github.com/smart-core-os/sc-golang/pkg/wrap"
+	grpc "google.golang.org/grpc"
+)
+
+// WrapSecretStoreApi	adapts a SecretStoreApiServer	and presents it as a SecretStoreApiClient
+func WrapSecretStoreApi(server SecretStoreApiServer) *SecretStoreApiWrapper {
+	conn := wrap.ServerToClient(SecretStoreApi_ServiceDesc, server)
+	client := NewSecretStoreApiClient(conn)
+	return &SecretStoreApiWrapper{
+		SecretStoreApiClient: client,
+		server:               server,
+		conn:                 conn,
+		desc:                 SecretStoreApi_ServiceDesc,
+	}
+}
+
+type SecretStoreApiWrapper struct {
+	SecretStoreApiClient
+
+	server SecretStoreApiServer
+	conn   grpc.ClientConnInterface
+	desc   grpc.ServiceDesc
+}
+
+// UnwrapServer returns the underlying server instance.
+func (w *SecretStoreApiWrapper) UnwrapServer() SecretStoreApiServer {
+	return w.server
+}
+
+// Unwrap implements wrap.Unwrapper and returns the underlying server instance as an unknown type.
+func (w *SecretStoreApiWrapper) Unwrap() any {
+	return w.UnwrapServer()
+}
+
+func (w *SecretStoreApiWrapper) UnwrapService() (grpc.ClientConnInterface, grpc.ServiceDesc) {
+	return w.conn, w.desc
+}
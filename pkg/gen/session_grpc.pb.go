@@ -0,0 +1,213 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: session.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SessionApi_ListSessions_FullMethodName          = "/smartcore.bos.SessionApi/ListSessions"
+	SessionApi_RevokeSession_FullMethodName         = "/smartcore.bos.SessionApi/RevokeSession"
+	SessionApi_RevokeAccountSessions_FullMethodName = "/smartcore.bos.SessionApi/RevokeAccountSessions"
+)
+
+// SessionApiClient is the client API for SessionApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SessionApi allows administrators to see and end the login sessions of a node.
+// A session is created when a user logs in using the local token server.
+// While the session is active the user's application can obtain new access tokens using a refresh token,
+// without asking the user for their password again.
+type SessionApiClient interface {
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// Revoke a single session.
+	// Refresh tokens and access tokens issued for the session are no longer accepted.
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
+	// Revoke all active sessions belonging to an account.
+	RevokeAccountSessions(ctx context.Context, in *RevokeAccountSessionsRequest, opts ...grpc.CallOption) (*RevokeAccountSessionsResponse, error)
+}
+
+type sessionApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSessionApiClient(cc grpc.ClientConnInterface) SessionApiClient {
+	return &sessionApiClient{cc}
+}
+
+func (c *sessionApiClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, SessionApi_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionApiClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, SessionApi_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionApiClient) RevokeAccountSessions(ctx context.Context, in *RevokeAccountSessionsRequest, opts ...grpc.CallOption) (*RevokeAccountSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAccountSessionsResponse)
+	err := c.cc.Invoke(ctx, SessionApi_RevokeAccountSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SessionApiServer is the server API for SessionApi service.
+// All implementations must embed UnimplementedSessionApiServer
+// for forward compatibility.
+//
+// SessionApi allows administrators to see and end the login sessions of a node.
+// A session is created when a user logs in using the local token server.
+// While the session is active the user's application can obtain new access tokens using a refresh token,
+// without asking the user for their password again.
+type SessionApiServer interface {
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// Revoke a single session.
+	// Refresh tokens and access tokens issued for the session are no longer accepted.
+	RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
+	// Revoke all active sessions belonging to an account.
+	RevokeAccountSessions(context.Context, *RevokeAccountSessionsRequest) (*RevokeAccountSessionsResponse, error)
+	mustEmbedUnimplementedSessionApiServer()
+}
+
+// UnimplementedSessionApiServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSessionApiServer struct{}
+
+func (UnimplementedSessionApiServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedSessionApiServer) RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedSessionApiServer) RevokeAccountSessions(context.Context, *RevokeAccountSessionsRequest) (*RevokeAccountSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAccountSessions not implemented")
+}
+func (UnimplementedSessionApiServer) mustEmbedUnimplementedSessionApiServer() {}
+func (UnimplementedSessionApiServer) testEmbeddedByValue()                    {}
+
+// UnsafeSessionApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SessionApiServer will
+// result in compilation errors.
+type UnsafeSessionApiServer interface {
+	mustEmbedUnimplementedSessionApiServer()
+}
+
+func RegisterSessionApiServer(s grpc.ServiceRegistrar, srv SessionApiServer) {
+	// If the following call pancis, it indicates UnimplementedSessionApiServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SessionApi_ServiceDesc, srv)
+}
+
+func _SessionApi_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionApiServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionApi_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionApiServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionApi_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionApiServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionApi_RevokeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionApiServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionApi_RevokeAccountSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAccountSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionApiServer).RevokeAccountSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionApi_RevokeAccountSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionApiServer).RevokeAccountSessions(ctx, req.(*RevokeAccountSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SessionApi_ServiceDesc is the grpc.ServiceDesc for SessionApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SessionApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smartcore.bos.SessionApi",
+	HandlerType: (*SessionApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSessions",
+			Handler:    _SessionApi_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _SessionApi_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeAccountSessions",
+			Handler:    _SessionApi_RevokeAccountSessions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "session.proto",
+}
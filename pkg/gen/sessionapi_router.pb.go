@@ -0,0 +1,105 @@
+// Code generated by protoc-gen-router. DO NOT EDIT.
+
+package gen
+
+import (
+	context "context"
+	fmt "fmt"
+	router "github.com/smart-core-os/sc-golang/pkg/router"
+	grpc "google.golang.org/grpc"
+)
+
+// SessionApiRouter is a SessionApiServer that allows routing named requests to specific SessionApiClient
+type SessionApiRouter struct {
+	UnimplementedSessionApiServer
+
+	router.Router
+}
+
+// compile time check that we implement the interface we need
+var _ SessionApiServer = (*SessionApiRouter)(nil)
+
+func NewSessionApiRouter(opts ...router.Option) *SessionApiRouter {
+	return &SessionApiRouter{
+		Router: router.NewRouter(opts...),
+	}
+}
+
+// WithSessionApiClientFactory instructs the router to create a new
+// client the first time Get is called for that name.
+func WithSessionApiClientFactory(f func(name string) (SessionApiClient, error)) router.Option {
+	return router.WithFactory(func(name string) (any, error) {
+		return f(name)
+	})
+}
+
+func (r *SessionApiRouter) Register(server grpc.ServiceRegistrar) {
+	RegisterSessionApiServer(server, r)
+}
+
+// Add extends Router.Add to panic if client is not of type SessionApiClient.
+func (r *SessionApiRouter) Add(name string, client any) any {
+	if !r.HoldsType(client) {
+		panic(fmt.Sprintf("not correct type: client of type %T is not a SessionApiClient", client))
+	}
+	return r.Router.Add(name, client)
+}
+
+func (r *SessionApiRouter) HoldsType(client any) bool {
+	_, ok := client.(SessionApiClient)
+	return ok
+}
+
+func (r *SessionApiRouter) AddSessionApiClient(name string, client SessionApiClient) SessionApiClient {
+	res := r.Add(name, client)
+	if res == nil {
+		return nil
+	}
+	return res.(SessionApiClient)
+}
+
+func (r *SessionApiRouter) RemoveSessionApiClient(name string) SessionApiClient {
+	res := r.Remove(name)
+	if res == nil {
+		return nil
+	}
+	return res.(SessionApiClient)
+}
+
+func (r *SessionApiRouter) GetSessionApiClient(name string) (SessionApiClient, error) {
+	res, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(SessionApiClient), nil
+}
+
+func (r *SessionApiRouter) ListSessions(ctx context.Context, request *ListSessionsRequest) (*ListSessionsResponse, error) {
+	child, err := r.GetSessionApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.ListSessions(ctx, request)
+}
+
+func (r *SessionApiRouter) RevokeSession(ctx context.Context, request *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	child, err := r.GetSessionApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.RevokeSession(ctx, request)
+}
+
+func (r *SessionApiRouter) RevokeAccountSessions(ctx context.Context, request *RevokeAccountSessionsRequest) (*RevokeAccountSessionsResponse, error) {
+	child, err := r.GetSessionApiClient(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.RevokeAccountSessions(ctx, request)
+}
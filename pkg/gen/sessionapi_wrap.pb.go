@@ -0,0 +1,42 @@
+// Code generated by protoc-gen-wrapper. DO NOT EDIT.
+
+package gen
+
+import (
+	wrap "github.com/smart-core-os/sc-golang/pkg/wrap"
+	grpc "google.golang.org/grpc"
+)
+
+// WrapSessionApi	adapts a SessionApiServer	and presents it as a SessionApiClient
+func WrapSessionApi(server SessionApiServer) *SessionApiWrapper {
+	conn := wrap.ServerToClient(SessionApi_ServiceDesc, server)
+	client := NewSessionApiClient(conn)
+	return &SessionApiWrapper{
+		SessionApiClient: client,
+		server:           server,
+		conn:             conn,
+		desc:             SessionApi_ServiceDesc,
+	}
+}
+
+type SessionApiWrapper struct {
+	SessionApiClient
+
+	server SessionApiServer
+	conn   grpc.ClientConnInterface
+	desc   grpc.ServiceDesc
+}
+
+// UnwrapServer returns the underlying server instance.
+func (w *SessionApiWrapper) UnwrapServer() SessionApiServer {
+	return w.server
+}
+
+// Unwrap implements wrap.Unwrapper and returns the underlying server instance as an unknown type.
+func (w *SessionApiWrapper) Unwrap() any {
+	return w.UnwrapServer()
+}
+
+func (w *SessionApiWrapper) UnwrapService() (grpc.ClientConnInterface, grpc.ServiceDesc) {
+	return w.conn, w.desc
+}
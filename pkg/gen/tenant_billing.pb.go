@@ -0,0 +1,608 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.32.1
+// source: tenant_billing.proto
+
+package gen
+
+import (
+	time "github.com/smart-core-os/sc-api/go/types/time"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Statement struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Opaque id of the statement, unique across all tenants.
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// The title of the tenant at the time the statement was generated.
+	TenantTitle string `protobuf:"bytes,3,opt,name=tenant_title,json=tenantTitle,proto3" json:"tenant_title,omitempty"`
+	// The period the statement covers.
+	Period     *time.Period           `protobuf:"bytes,4,opt,name=period,proto3" json:"period,omitempty"`
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Charges for consumption, one per meter and rate.
+	Lines []*Statement_Line `protobuf:"bytes,6,rep,name=lines,proto3" json:"lines,omitempty"`
+	// Fixed charges that don't depend on consumption, for example a daily standing charge.
+	FixedCharges []*Statement_FixedCharge `protobuf:"bytes,7,rep,name=fixed_charges,json=fixedCharges,proto3" json:"fixed_charges,omitempty"`
+	// The sum of usage for all lines.
+	TotalUsage float64 `protobuf:"fixed64,8,opt,name=total_usage,json=totalUsage,proto3" json:"total_usage,omitempty"`
+	// The sum of cost for all lines and fixed charges.
+	TotalCost float64 `protobuf:"fixed64,9,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	// ISO 4217 currency code of all costs in this statement, e.g. GBP.
+	Currency string `protobuf:"bytes,10,opt,name=currency,proto3" json:"currency,omitempty"`
+	// The unit of all usage in this statement, e.g. kWh.
+	UsageUnit     string `protobuf:"bytes,11,opt,name=usage_unit,json=usageUnit,proto3" json:"usage_unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Statement) Reset() {
+	*x = Statement{}
+	mi := &file_tenant_billing_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statement) ProtoMessage() {}
+
+func (x *Statement) ProtoReflect() protoreflect.Message {
+	mi := &file_tenant_billing_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statement.ProtoReflect.Descriptor instead.
+func (*Statement) Descriptor() ([]byte, []int) {
+	return file_tenant_billing_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Statement) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Statement) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Statement) GetTenantTitle() string {
+	if x != nil {
+		return x.TenantTitle
+	}
+	return ""
+}
+
+func (x *Statement) GetPeriod() *time.Period {
+	if x != nil {
+		return x.Period
+	}
+	return nil
+}
+
+func (x *Statement) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Statement) GetLines() []*Statement_Line {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *Statement) GetFixedCharges() []*Statement_FixedCharge {
+	if x != nil {
+		return x.FixedCharges
+	}
+	return nil
+}
+
+func (x *Statement) GetTotalUsage() float64 {
+	if x != nil {
+		return x.TotalUsage
+	}
+	return 0
+}
+
+func (x *Statement) GetTotalCost() float64 {
+	if x != nil {
+		return x.TotalCost
+	}
+	return 0
+}
+
+func (x *Statement) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Statement) GetUsageUnit() string {
+	if x != nil {
+		return x.UsageUnit
+	}
+	return ""
+}
+
+type ListStatementsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only return statements for this tenant.
+	// Required unless the caller can see all tenants statements.
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// The maximum number of statements to return.
+	// The service may return fewer than this value.
+	// If unspecified, at most 50 items will be returned.
+	// The maximum value is 1000; values above 1000 will be coerced to 1000.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// A page token, received from a previous `ListStatementsResponse` call.
+	// Provide this to retrieve the subsequent page.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStatementsRequest) Reset() {
+	*x = ListStatementsRequest{}
+	mi := &file_tenant_billing_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStatementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStatementsRequest) ProtoMessage() {}
+
+func (x *ListStatementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tenant_billing_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStatementsRequest.ProtoReflect.Descriptor instead.
+func (*ListStatementsRequest) Descriptor() ([]byte, []int) {
+	return file_tenant_billing_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListStatementsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ListStatementsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListStatementsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListStatementsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Statements, newest first.
+	Statements []*Statement `protobuf:"bytes,1,rep,name=statements,proto3" json:"statements,omitempty"`
+	// A token, which can be sent as `page_token` to retrieve the next page.
+	// If this field is omitted, there are no subsequent pages.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// If non-zero this is the total number of statements matching the request.
+	TotalSize     int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStatementsResponse) Reset() {
+	*x = ListStatementsResponse{}
+	mi := &file_tenant_billing_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStatementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStatementsResponse) ProtoMessage() {}
+
+func (x *ListStatementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tenant_billing_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStatementsResponse.ProtoReflect.Descriptor instead.
+func (*ListStatementsResponse) Descriptor() ([]byte, []int) {
+	return file_tenant_billing_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListStatementsResponse) GetStatements() []*Statement {
+	if x != nil {
+		return x.Statements
+	}
+	return nil
+}
+
+func (x *ListStatementsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListStatementsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type GetStatementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatementRequest) Reset() {
+	*x = GetStatementRequest{}
+	mi := &file_tenant_billing_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatementRequest) ProtoMessage() {}
+
+func (x *GetStatementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tenant_billing_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatementRequest.ProtoReflect.Descriptor instead.
+func (*GetStatementRequest) Descriptor() ([]byte, []int) {
+	return file_tenant_billing_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetStatementRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *GetStatementRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Statement_Line struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Smart Core name of the meter.
+	MeterName string `protobuf:"bytes,1,opt,name=meter_name,json=meterName,proto3" json:"meter_name,omitempty"`
+	// True if the meter is shared with other tenants.
+	Shared bool `protobuf:"varint,2,opt,name=shared,proto3" json:"shared,omitempty"`
+	// The fraction of the meter's consumption apportioned to this tenant, 1 for meters that aren't shared.
+	Share float64 `protobuf:"fixed64,3,opt,name=share,proto3" json:"share,omitempty"`
+	// The name of the tariff rate this usage was charged at.
+	Rate string `protobuf:"bytes,4,opt,name=rate,proto3" json:"rate,omitempty"`
+	// Usage apportioned to the tenant, already multiplied by share.
+	Usage         float64 `protobuf:"fixed64,5,opt,name=usage,proto3" json:"usage,omitempty"`
+	UnitPrice     float64 `protobuf:"fixed64,6,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Cost          float64 `protobuf:"fixed64,7,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Statement_Line) Reset() {
+	*x = Statement_Line{}
+	mi := &file_tenant_billing_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statement_Line) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statement_Line) ProtoMessage() {}
+
+func (x *Statement_Line) ProtoReflect() protoreflect.Message {
+	mi := &file_tenant_billing_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statement_Line.ProtoReflect.Descriptor instead.
+func (*Statement_Line) Descriptor() ([]byte, []int) {
+	return file_tenant_billing_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *Statement_Line) GetMeterName() string {
+	if x != nil {
+		return x.MeterName
+	}
+	return ""
+}
+
+func (x *Statement_Line) GetShared() bool {
+	if x != nil {
+		return x.Shared
+	}
+	return false
+}
+
+func (x *Statement_Line) GetShare() float64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+func (x *Statement_Line) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *Statement_Line) GetUsage() float64 {
+	if x != nil {
+		return x.Usage
+	}
+	return 0
+}
+
+func (x *Statement_Line) GetUnitPrice() float64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *Statement_Line) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+type Statement_FixedCharge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Cost          float64                `protobuf:"fixed64,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Statement_FixedCharge) Reset() {
+	*x = Statement_FixedCharge{}
+	mi := &file_tenant_billing_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statement_FixedCharge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statement_FixedCharge) ProtoMessage() {}
+
+func (x *Statement_FixedCharge) ProtoReflect() protoreflect.Message {
+	mi := &file_tenant_billing_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statement_FixedCharge.ProtoReflect.Descriptor instead.
+func (*Statement_FixedCharge) Descriptor() ([]byte, []int) {
+	return file_tenant_billing_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *Statement_FixedCharge) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Statement_FixedCharge) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+var File_tenant_billing_proto protoreflect.FileDescriptor
+
+const file_tenant_billing_proto_rawDesc = "" +
+	"\n" +
+	"\x14tenant_billing.proto\x12\x15smartcore.bos.tenants\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17types/time/period.proto\"\xc5\x05\n" +
+	"\tStatement\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12!\n" +
+	"\ftenant_title\x18\x03 \x01(\tR\vtenantTitle\x124\n" +
+	"\x06period\x18\x04 \x01(\v2\x1c.smartcore.types.time.PeriodR\x06period\x12;\n" +
+	"\vcreate_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12;\n" +
+	"\x05lines\x18\x06 \x03(\v2%.smartcore.bos.tenants.Statement.LineR\x05lines\x12Q\n" +
+	"\rfixed_charges\x18\a \x03(\v2,.smartcore.bos.tenants.Statement.FixedChargeR\ffixedCharges\x12\x1f\n" +
+	"\vtotal_usage\x18\b \x01(\x01R\n" +
+	"totalUsage\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\t \x01(\x01R\ttotalCost\x12\x1a\n" +
+	"\bcurrency\x18\n" +
+	" \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"usage_unit\x18\v \x01(\tR\tusageUnit\x1a\xb0\x01\n" +
+	"\x04Line\x12\x1d\n" +
+	"\n" +
+	"meter_name\x18\x01 \x01(\tR\tmeterName\x12\x16\n" +
+	"\x06shared\x18\x02 \x01(\bR\x06shared\x12\x14\n" +
+	"\x05share\x18\x03 \x01(\x01R\x05share\x12\x12\n" +
+	"\x04rate\x18\x04 \x01(\tR\x04rate\x12\x14\n" +
+	"\x05usage\x18\x05 \x01(\x01R\x05usage\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x06 \x01(\x01R\tunitPrice\x12\x12\n" +
+	"\x04cost\x18\a \x01(\x01R\x04cost\x1a7\n" +
+	"\vFixedCharge\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04cost\x18\x02 \x01(\x01R\x04cost\"p\n" +
+	"\x15ListStatementsRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"\xa1\x01\n" +
+	"\x16ListStatementsResponse\x12@\n" +
+	"\n" +
+	"statements\x18\x01 \x03(\v2 .smartcore.bos.tenants.StatementR\n" +
+	"statements\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"B\n" +
+	"\x13GetStatementRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id2\xdf\x01\n" +
+	"\x10TenantBillingApi\x12m\n" +
+	"\x0eListStatements\x12,.smartcore.bos.tenants.ListStatementsRequest\x1a-.smartcore.bos.tenants.ListStatementsResponse\x12\\\n" +
+	"\fGetStatement\x12*.smartcore.bos.tenants.GetStatementRequest\x1a .smartcore.bos.tenants.StatementB)Z'github.com/smart-core-os/sc-bos/pkg/genb\x06proto3"
+
+var (
+	file_tenant_billing_proto_rawDescOnce sync.Once
+	file_tenant_billing_proto_rawDescData []byte
+)
+
+func file_tenant_billing_proto_rawDescGZIP() []byte {
+	file_tenant_billing_proto_rawDescOnce.Do(func() {
+		file_tenant_billing_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tenant_billing_proto_rawDesc), len(file_tenant_billing_proto_rawDesc)))
+	})
+	return file_tenant_billing_proto_rawDescData
+}
+
+var file_tenant_billing_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_tenant_billing_proto_goTypes = []any{
+	(*Statement)(nil),              // 0: smartcore.bos.tenants.Statement
+	(*ListStatementsRequest)(nil),  // 1: smartcore.bos.tenants.ListStatementsRequest
+	(*ListStatementsResponse)(nil), // 2: smartcore.bos.tenants.ListStatementsResponse
+	(*GetStatementRequest)(nil),    // 3: smartcore.bos.tenants.GetStatementRequest
+	(*Statement_Line)(nil),         // 4: smartcore.bos.tenants.Statement.Line
+	(*Statement_FixedCharge)(nil),  // 5: smartcore.bos.tenants.Statement.FixedCharge
+	(*time.Period)(nil),            // 6: smartcore.types.time.Period
+	(*timestamppb.Timestamp)(nil),  // 7: google.protobuf.Timestamp
+}
+var file_tenant_billing_proto_depIdxs = []int32{
+	6, // 0: smartcore.bos.tenants.Statement.period:type_name -> smartcore.types.time.Period
+	7, // 1: smartcore.bos.tenants.Statement.create_time:type_name -> google.protobuf.Timestamp
+	4, // 2: smartcore.bos.tenants.Statement.lines:type_name -> smartcore.bos.tenants.Statement.Line
+	5, // 3: smartcore.bos.tenants.Statement.fixed_charges:type_name -> smartcore.bos.tenants.Statement.FixedCharge
+	0, // 4: smartcore.bos.tenants.ListStatementsResponse.statements:type_name -> smartcore.bos.tenants.Statement
+	1, // 5: smartcore.bos.tenants.TenantBillingApi.ListStatements:input_type -> smartcore.bos.tenants.ListStatementsRequest
+	3, // 6: smartcore.bos.tenants.TenantBillingApi.GetStatement:input_type -> smartcore.bos.tenants.GetStatementRequest
+	2, // 7: smartcore.bos.tenants.TenantBillingApi.ListStatements:output_type -> smartcore.bos.tenants.ListStatementsResponse
+	0, // 8: smartcore.bos.tenants.TenantBillingApi.GetStatement:output_type -> smartcore.bos.tenants.Statement
+	7, // [7:9] is the sub-list for method output_type
+	5, // [5:7] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_tenant_billing_proto_init() }
+func file_tenant_billing_proto_init() {
+	if File_tenant_billing_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tenant_billing_proto_rawDesc), len(file_tenant_billing_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tenant_billing_proto_goTypes,
+		DependencyIndexes: file_tenant_billing_proto_depIdxs,
+		MessageInfos:      file_tenant_billing_proto_msgTypes,
+	}.Build()
+	File_tenant_billing_proto = out.File
+	file_tenant_billing_proto_goTypes = nil
+	file_tenant_billing_proto_depIdxs = nil
+}
@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: tenant_billing.proto
+
+package gen
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TenantBillingApi_ListStatements_FullMethodName = "/smartcore.bos.tenants.TenantBillingApi/ListStatements"
+	TenantBillingApi_GetStatement_FullMethodName   = "/smartcore.bos.tenants.TenantBillingApi/GetStatement"
+)
+
+// TenantBillingApiClient is the client API for TenantBillingApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TenantBillingApi provides access to energy statements generated for tenants.
+// Statements apportion the consumption of meters in each tenant's zones, and a share of any shared meters,
+// and price it using the configured tariff.
+//
+// Tenants can read their own statements using the credentials issued via the TenantApi.
+type TenantBillingApiClient interface {
+	ListStatements(ctx context.Context, in *ListStatementsRequest, opts ...grpc.CallOption) (*ListStatementsResponse, error)
+	GetStatement(ctx context.Context, in *GetStatementRequest, opts ...grpc.CallOption) (*Statement, error)
+}
+
+type tenantBillingApiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTenantBillingApiClient(cc grpc.ClientConnInterface) TenantBillingApiClient {
+	return &tenantBillingApiClient{cc}
+}
+
+func (c *tenantBillingApiClient) ListStatements(ctx context.Context, in *ListStatementsRequest, opts ...grpc.CallOption) (*ListStatementsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStatementsResponse)
+	err := c.cc.Invoke(ctx, TenantBillingApi_ListStatements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenantBillingApiClient) GetStatement(ctx context.Context, in *GetStatementRequest, opts ...grpc.CallOption) (*Statement, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Statement)
+	err := c.cc.Invoke(ctx, TenantBillingApi_GetStatement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TenantBillingApiServer is the server API for TenantBillingApi service.
+// All implementations must embed UnimplementedTenantBillingApiServer
+// for forward compatibility.
+//
+// TenantBillingApi provides access to energy statements generated for tenants.
+// Statements apportion the consumption of meters in each tenant's zones, and a share of any shared meters,
+// and price it using the configured tariff.
+//
+// Tenants can read their own statements using the credentials issued via the TenantApi.
+type TenantBillingApiServer interface {
+	ListStatements(context.Context, *ListStatementsRequest) (*ListStatementsResponse, error)
+	GetStatement(context.Context, *GetStatementRequest) (*Statement, error)
+	mustEmbedUnimplementedTenantBillingApiServer()
+}
+
+// UnimplementedTenantBillingApiServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTenantBillingApiServer struct{}
+
+func (UnimplementedTenantBillingApiServer) ListStatements(context.Context, *ListStatementsRequest) (*ListStatementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStatements not implemented")
+}
+func (UnimplementedTenantBillingApiServer) GetStatement(context.Context, *GetStatementRequest) (*Statement, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatement not implemented")
+}
+func (UnimplementedTenantBillingApiServer) mustEmbedUnimplementedTenantBillingApiServer() {}
+func (UnimplementedTenantBillingApiServer) testEmbeddedByValue()                          {}
+
+// UnsafeTenantBillingApiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TenantBillingApiServer will
+// result in compilation errors.
+type UnsafeTenantBillingApiServer interface {
+	mustEmbedUnimplementedTenantBillingApiServer()
+}
+
+func RegisterTenantBillingApiServer(s grpc.ServiceRegistrar, srv TenantBillingApiServer) {
+	// If the following call pancis, it indicates UnimplementedTenantBillingApiServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TenantBillingApi_ServiceDesc, srv)
+}
+
+func _TenantBillingApi_ListStatements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStatementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenantBillingApiServer).ListStatements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenantBillingApi_ListStatements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenantBillingApiServer).ListStatements(ctx, req.(*ListStatementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenantBillingApi_GetStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenantBillingApiServer).GetStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenantBillingApi_GetStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenantBillingApiServer).GetStatement(ctx, req.(*GetStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TenantBillingApi_ServiceDesc is the grpc.ServiceDesc for TenantBillingApi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TenantBillingApi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smartcore.bos.tenants.TenantBillingApi",
+	HandlerType: (*TenantBillingApiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListStatements",
+			Handler:    _TenantBillingApi_ListStatements_Handler,
+		},
+		{
+			MethodName: "GetStatement",
+			Handler:    _TenantBillingApi_GetStatement_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "tenant_billing.proto",
+}
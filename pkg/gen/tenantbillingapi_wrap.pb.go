@@ -0,0 +1,42 @@
+// Code generated by protoc-gen-wrapper. DO NOT EDIT.
+
+package gen
+
+import (
+	wrap "github.com/smart-core-os/sc-golang/pkg/wrap"
+	grpc "google.golang.org/grpc"
+)
+
+// WrapTenantBillingApi	adapts a TenantBillingApiServer	and presents it as a TenantBillingApiClient
+func WrapTenantBillingApi(server TenantBillingApiServer) *TenantBillingApiWrapper {
+	conn := wrap.ServerToClient(TenantBillingApi_ServiceDesc, server)
+	client := NewTenantBillingApiClient(conn)
+	return &TenantBillingApiWrapper{
+		TenantBillingApiClient: client,
+		server:                 server,
+		conn:                   conn,
+		desc:                   TenantBillingApi_ServiceDesc,
+	}
+}
+
+type TenantBillingApiWrapper struct {
+	TenantBillingApiClient
+
+	server TenantBillingApiServer
+	conn   grpc.ClientConnInterface
+	desc   grpc.ServiceDesc
+}
+
+// UnwrapServer returns the underlying server instance.
+func (w *TenantBillingApiWrapper) UnwrapServer() TenantBillingApiServer {
+	return w.server
+}
+
+// Unwrap implements wrap.Unwrapper and returns the underlying server instance as an unknown type.
+func (w *TenantBillingApiWrapper) Unwrap() any {
+	return w.UnwrapServer()
+}
+
+func (w *TenantBillingApiWrapper) UnwrapService() (grpc.ClientConnInterface, grpc.ServiceDesc) {
+	return w.conn, w.desc
+}
@@ -0,0 +1,16 @@
+package devicespb
+
+import (
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+)
+
+// HasTrait returns whether the metadata of device lists the named trait.
+func HasTrait(device *gen.Device, name trait.Name) bool {
+	for _, t := range device.GetMetadata().GetTraits() {
+		if t.Name == name.String() {
+			return true
+		}
+	}
+	return false
+}
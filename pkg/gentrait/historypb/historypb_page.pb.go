@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.34.2
-// 	protoc        v5.29.1
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.32.1
 // source: historypb_page.proto
 
 package historypb
@@ -11,6 +11,7 @@ import (
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -21,21 +22,21 @@ const (
 )
 
 type PageToken struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RecordId  string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	TotalSize int32                  `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	// Identifies the query and ordering the token was created for, so changes between pages can be rejected.
+	// Optional, not all users of PageToken check it.
+	QueryHash     []byte `protobuf:"bytes,3,opt,name=query_hash,json=queryHash,proto3" json:"query_hash,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	RecordId  string `protobuf:"bytes,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
-	TotalSize int32  `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PageToken) Reset() {
 	*x = PageToken{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_historypb_page_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_historypb_page_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *PageToken) String() string {
@@ -46,7 +47,7 @@ func (*PageToken) ProtoMessage() {}
 
 func (x *PageToken) ProtoReflect() protoreflect.Message {
 	mi := &file_historypb_page_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -75,31 +76,33 @@ func (x *PageToken) GetTotalSize() int32 {
 	return 0
 }
 
+func (x *PageToken) GetQueryHash() []byte {
+	if x != nil {
+		return x.QueryHash
+	}
+	return nil
+}
+
 var File_historypb_page_proto protoreflect.FileDescriptor
 
-var file_historypb_page_proto_rawDesc = []byte{
-	0x0a, 0x14, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x70, 0x62, 0x5f, 0x70, 0x61, 0x67, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x20, 0x73, 0x6d, 0x61, 0x72, 0x74, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x62, 0x6f, 0x73, 0x2e, 0x67, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x69, 0x74, 0x2e, 0x68,
-	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x70, 0x62, 0x22, 0x47, 0x0a, 0x09, 0x50, 0x61, 0x67, 0x65,
-	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a,
-	0x65, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x76, 0x61, 0x6e, 0x74, 0x69, 0x2d, 0x64, 0x65, 0x76, 0x2f, 0x73, 0x63, 0x2d, 0x62, 0x6f, 0x73,
-	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x69, 0x74, 0x2f, 0x68, 0x69,
-	0x73, 0x74, 0x6f, 0x72, 0x79, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
+const file_historypb_page_proto_rawDesc = "" +
+	"\n" +
+	"\x14historypb_page.proto\x12 smartcore.bos.gentrait.historypb\"f\n" +
+	"\tPageToken\x12\x1b\n" +
+	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x02 \x01(\x05R\ttotalSize\x12\x1d\n" +
+	"\n" +
+	"query_hash\x18\x03 \x01(\fR\tqueryHashB8Z6github.com/smart-core-os/sc-bos/pkg/gentrait/historypbb\x06proto3"
 
 var (
 	file_historypb_page_proto_rawDescOnce sync.Once
-	file_historypb_page_proto_rawDescData = file_historypb_page_proto_rawDesc
+	file_historypb_page_proto_rawDescData []byte
 )
 
 func file_historypb_page_proto_rawDescGZIP() []byte {
 	file_historypb_page_proto_rawDescOnce.Do(func() {
-		file_historypb_page_proto_rawDescData = protoimpl.X.CompressGZIP(file_historypb_page_proto_rawDescData)
+		file_historypb_page_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_historypb_page_proto_rawDesc), len(file_historypb_page_proto_rawDesc)))
 	})
 	return file_historypb_page_proto_rawDescData
 }
@@ -121,25 +124,11 @@ func file_historypb_page_proto_init() {
 	if File_historypb_page_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_historypb_page_proto_msgTypes[0].Exporter = func(v any, i int) any {
-			switch v := v.(*PageToken); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_historypb_page_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_historypb_page_proto_rawDesc), len(file_historypb_page_proto_rawDesc)),
 			NumEnums:      0,
 			NumMessages:   1,
 			NumExtensions: 0,
@@ -150,7 +139,6 @@ func file_historypb_page_proto_init() {
 		MessageInfos:      file_historypb_page_proto_msgTypes,
 	}.Build()
 	File_historypb_page_proto = out.File
-	file_historypb_page_proto_rawDesc = nil
 	file_historypb_page_proto_goTypes = nil
 	file_historypb_page_proto_depIdxs = nil
 }
@@ -0,0 +1,6 @@
+// Package historyread reads the records of a period from the history APIs of Smart Core traits.
+// Records are read using the *HistoryClient of the trait, so can be read from local or remote stores.
+package historyread
+
+// PageSize is the number of records requested per page when reading all the records in a period.
+const PageSize = 1000
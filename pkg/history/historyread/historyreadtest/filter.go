@@ -0,0 +1,33 @@
+package historyreadtest
+
+import (
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+)
+
+// filter returns the records within period, in the requested order, limited to one page.
+// Page tokens aren't supported, every request returns the first page.
+func filter[T any](records []T, period *timepb.Period, orderBy string, pageSize int32, recordTime func(T) *timestamppb.Timestamp) []T {
+	var res []T
+	for _, r := range records {
+		at := recordTime(r).AsTime()
+		if s := period.GetStartTime(); s != nil && at.Before(s.AsTime()) {
+			continue
+		}
+		if e := period.GetEndTime(); e != nil && !at.Before(e.AsTime()) {
+			continue
+		}
+		res = append(res, r)
+	}
+	if strings.EqualFold(orderBy, "record_time desc") {
+		slices.Reverse(res)
+	}
+	if pageSize > 0 && len(res) > int(pageSize) {
+		res = res[:pageSize]
+	}
+	return res
+}
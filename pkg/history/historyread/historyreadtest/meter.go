@@ -0,0 +1,28 @@
+package historyreadtest
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// Reading returns a meter reading record recorded at.
+func Reading(at time.Time, usage float32) *gen.MeterReadingRecord {
+	return &gen.MeterReadingRecord{
+		RecordTime:   timestamppb.New(at),
+		MeterReading: &gen.MeterReading{Usage: usage},
+	}
+}
+
+// MeterHistory returns records by meter name, filtered by the request period.
+// Records must be ordered by record time, the period end is exclusive.
+type MeterHistory map[string][]*gen.MeterReadingRecord
+
+func (f MeterHistory) ListMeterReadingHistory(_ context.Context, in *gen.ListMeterReadingHistoryRequest, _ ...grpc.CallOption) (*gen.ListMeterReadingHistoryResponse, error) {
+	records := filter(f[in.Name], in.Period, in.OrderBy, in.PageSize, (*gen.MeterReadingRecord).GetRecordTime)
+	return &gen.ListMeterReadingHistoryResponse{MeterReadingRecords: records}, nil
+}
@@ -0,0 +1,30 @@
+// Package historyreadtest provides fake history clients for testing code that uses historyread.
+package historyreadtest
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// Occupancy returns an occupancy record recorded at.
+func Occupancy(at time.Time, state traits.Occupancy_State, people int32) *gen.OccupancyRecord {
+	return &gen.OccupancyRecord{
+		RecordTime: timestamppb.New(at),
+		Occupancy:  &traits.Occupancy{State: state, PeopleCount: people},
+	}
+}
+
+// OccupancyHistory returns records by device name, filtered by the request period.
+// Records must be ordered by record time, the period end is exclusive.
+type OccupancyHistory map[string][]*gen.OccupancyRecord
+
+func (f OccupancyHistory) ListOccupancyHistory(_ context.Context, in *gen.ListOccupancyHistoryRequest, _ ...grpc.CallOption) (*gen.ListOccupancyHistoryResponse, error) {
+	records := filter(f[in.Name], in.Period, in.OrderBy, in.PageSize, (*gen.OccupancyRecord).GetRecordTime)
+	return &gen.ListOccupancyHistoryResponse{OccupancyRecords: records}, nil
+}
@@ -0,0 +1,97 @@
+package historyread
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/util/timeutil"
+)
+
+// MeterReadings returns all the meter readings recorded for the named meter between start and end,
+// and the closest readings either side, so consumption up to the edges of the period can be worked out.
+func MeterReadings(ctx context.Context, client gen.MeterHistoryClient, name string, start, end time.Time) ([]*gen.MeterReadingRecord, error) {
+	edge := func(p *timepb.Period, order string) (*gen.MeterReadingRecord, error) {
+		res, err := client.ListMeterReadingHistory(ctx, &gen.ListMeterReadingHistoryRequest{
+			Name: name, Period: p, PageSize: 1, OrderBy: order,
+		})
+		if err != nil || len(res.MeterReadingRecords) == 0 {
+			return nil, err
+		}
+		return res.MeterReadingRecords[0], nil
+	}
+
+	var records []*gen.MeterReadingRecord
+	before, err := edge(&timepb.Period{EndTime: timestamppb.New(start)}, "record_time desc")
+	if err != nil {
+		return nil, err
+	}
+	if before != nil {
+		records = append(records, before)
+	}
+	req := &gen.ListMeterReadingHistoryRequest{
+		Name:     name,
+		Period:   &timepb.Period{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)},
+		PageSize: PageSize,
+	}
+	for {
+		res, err := client.ListMeterReadingHistory(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, res.MeterReadingRecords...)
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
+	}
+	after, err := edge(&timepb.Period{StartTime: timestamppb.New(end)}, "record_time asc")
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		records = append(records, after)
+	}
+	return records, nil
+}
+
+// MeterUsage is the consumption of a meter between two readings.
+type MeterUsage struct {
+	Start, End time.Time
+	Usage      float64
+}
+
+// Clip returns the part of u between start and end.
+// Consumption is assumed to be even over time, false is returned if none of u is between start and end.
+func (u MeterUsage) Clip(start, end time.Time) (MeterUsage, bool) {
+	clipped := MeterUsage{Start: timeutil.Latest(u.Start, start), End: timeutil.Earliest(u.End, end)}
+	if !clipped.End.After(clipped.Start) {
+		return MeterUsage{}, false
+	}
+	clipped.Usage = u.Usage * float64(clipped.End.Sub(clipped.Start)) / float64(u.End.Sub(u.Start))
+	return clipped, true
+}
+
+// MeterUsages returns the consumption between each consecutive pair of records, which should be ordered by record time.
+// Pairs recorded at the same time are skipped.
+// If a reading is less than the one before it the meter was reset or replaced,
+// the new reading is all that is known to have been consumed.
+func MeterUsages(records []*gen.MeterReadingRecord) []MeterUsage {
+	var res []MeterUsage
+	for i := 1; i < len(records); i++ {
+		prev, cur := records[i-1], records[i]
+		from, to := prev.GetRecordTime().AsTime(), cur.GetRecordTime().AsTime()
+		if !to.After(from) {
+			continue
+		}
+		delta := float64(cur.GetMeterReading().GetUsage() - prev.GetMeterReading().GetUsage())
+		if delta < 0 {
+			delta = float64(cur.GetMeterReading().GetUsage())
+		}
+		res = append(res, MeterUsage{Start: from, End: to, Usage: delta})
+	}
+	return res
+}
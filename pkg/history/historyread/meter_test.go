@@ -0,0 +1,49 @@
+package historyread_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread/historyreadtest"
+)
+
+func TestMeterUsages(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2024, 2, 1, hour, 0, 0, 0, time.UTC) }
+	client := historyreadtest.MeterHistory{"meter": {
+		historyreadtest.Reading(at(0), 10),
+		historyreadtest.Reading(at(2), 14),
+		historyreadtest.Reading(at(2), 14), // recorded twice
+		historyreadtest.Reading(at(4), 3),  // reset
+		historyreadtest.Reading(at(6), 5),
+		historyreadtest.Reading(at(8), 9),
+	}}
+
+	records, err := historyread.MeterReadings(context.Background(), client, "meter", at(1), at(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("got %d records, want 5 including the readings either side of the period", len(records))
+	}
+	got := historyread.MeterUsages(records)
+	want := []historyread.MeterUsage{
+		{Start: at(0), End: at(2), Usage: 4},
+		{Start: at(2), End: at(4), Usage: 3},
+		{Start: at(4), End: at(6), Usage: 2},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("MeterUsages (-want,+got)\n%s", diff)
+	}
+
+	clipped, ok := got[0].Clip(at(1), at(5))
+	if want := (historyread.MeterUsage{Start: at(1), End: at(2), Usage: 2}); !ok || clipped != want {
+		t.Errorf("Clip got %v %v, want %v", clipped, ok, want)
+	}
+	if _, ok := got[0].Clip(at(2), at(5)); ok {
+		t.Errorf("Clip outside the period got ok")
+	}
+}
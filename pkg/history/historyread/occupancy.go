@@ -0,0 +1,84 @@
+package historyread
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// OccupancySegment is a period, start inclusive and end exclusive, during which the occupancy of a space didn't change.
+type OccupancySegment struct {
+	Start, End  time.Time
+	State       traits.Occupancy_State
+	PeopleCount int32
+}
+
+// OccupancySegments reads the occupancy history of the named device between start and end.
+// The returned segments are in order and cover the whole period,
+// the occupancy at start is the last recorded before start, or unknown if there is none.
+func OccupancySegments(ctx context.Context, client gen.OccupancySensorHistoryClient, name string, start, end time.Time) ([]OccupancySegment, error) {
+	before, err := client.ListOccupancyHistory(ctx, &gen.ListOccupancyHistoryRequest{
+		Name:     name,
+		Period:   &timepb.Period{EndTime: timestamppb.New(start)},
+		PageSize: 1,
+		OrderBy:  "record_time desc",
+	})
+	if err != nil {
+		return nil, err
+	}
+	current := OccupancySegment{Start: start}
+	if len(before.OccupancyRecords) > 0 {
+		o := before.OccupancyRecords[0].GetOccupancy()
+		current.State, current.PeopleCount = o.GetState(), o.GetPeopleCount()
+	}
+
+	var segments []OccupancySegment
+	req := &gen.ListOccupancyHistoryRequest{
+		Name:     name,
+		Period:   &timepb.Period{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)},
+		PageSize: PageSize,
+	}
+	for {
+		res, err := client.ListOccupancyHistory(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range res.OccupancyRecords {
+			at := record.GetRecordTime().AsTime()
+			if at.After(current.Start) {
+				current.End = at
+				segments = append(segments, current)
+			}
+			o := record.GetOccupancy()
+			if at.Before(start) {
+				at = start
+			}
+			current = OccupancySegment{Start: at, State: o.GetState(), PeopleCount: o.GetPeopleCount()}
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
+	}
+	if current.Start.Before(end) {
+		current.End = end
+		segments = append(segments, current)
+	}
+	return segments, nil
+}
+
+// Occupied returns how long the segments were occupied for, in total.
+func Occupied(segments []OccupancySegment) time.Duration {
+	var occupied time.Duration
+	for _, s := range segments {
+		if s.State == traits.Occupancy_OCCUPIED {
+			occupied += s.End.Sub(s.Start)
+		}
+	}
+	return occupied
+}
@@ -0,0 +1,48 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	appends = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "scbos",
+		Subsystem: "history",
+		Name:      "appends_total",
+		Help:      "Number of records appended to history stores, by storage type and result.",
+	}, []string{"storage", "result"})
+	appendSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "scbos",
+		Subsystem: "history",
+		Name:      "append_seconds",
+		Help:      "Time taken to append records to history stores.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"storage"})
+)
+
+// WithMetrics returns a Store that records the count and duration of appends to s as Prometheus metrics.
+// The storage type, like bolt or postgres, is used to label the metrics.
+func WithMetrics(s Store, storage string) Store {
+	return &metricsStore{Store: s, storage: storage}
+}
+
+type metricsStore struct {
+	Store
+	storage string
+}
+
+func (s *metricsStore) Append(ctx context.Context, payload []byte) (Record, error) {
+	start := time.Now()
+	r, err := s.Store.Append(ctx, payload)
+	appendSeconds.WithLabelValues(s.storage).Observe(time.Since(start).Seconds())
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	appends.WithLabelValues(s.storage, result).Inc()
+	return r, err
+}
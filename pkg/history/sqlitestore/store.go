@@ -31,6 +31,10 @@ var migrationFS embed.FS
 
 var schema = sqlite.MustLoadVersionedSchema(migrationFS, "migrations")
 
+func init() {
+	sqlite.RegisterSchema("history", appID, schema)
+}
+
 type Database struct {
 	db     *sqlite.Database
 	logger *zap.Logger
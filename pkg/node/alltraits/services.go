@@ -11,6 +11,7 @@ import (
 	"github.com/smart-core-os/sc-bos/pkg/gentrait/anprcamera"
 	"github.com/smart-core-os/sc-bos/pkg/gentrait/button"
 	"github.com/smart-core-os/sc-bos/pkg/gentrait/dalipb"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/demandresponsepb"
 	"github.com/smart-core-os/sc-bos/pkg/gentrait/emergencylightpb"
 	"github.com/smart-core-os/sc-bos/pkg/gentrait/healthpb"
 	"github.com/smart-core-os/sc-bos/pkg/gentrait/meter"
@@ -62,6 +63,7 @@ var serviceRegistry = map[trait.Name][]grpc.ServiceDesc{
 	anprcamera.TraitName:       {gen.AnprCameraApi_ServiceDesc},
 	button.TraitName:           {gen.ButtonApi_ServiceDesc},
 	dalipb.TraitName:           {gen.DaliApi_ServiceDesc},
+	demandresponsepb.TraitName: {gen.DemandResponseApi_ServiceDesc},
 	emergencylightpb.TraitName: {gen.DaliApi_ServiceDesc, gen.EmergencyLightApi_ServiceDesc},
 	healthpb.TraitName:         {gen.HealthApi_ServiceDesc, gen.HealthHistory_ServiceDesc},
 	meter.TraitName:            {gen.MeterApi_ServiceDesc, gen.MeterInfo_ServiceDesc, gen.MeterHistory_ServiceDesc},
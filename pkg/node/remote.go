@@ -5,6 +5,7 @@ import (
 	"io"
 	"sync"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
@@ -20,6 +21,7 @@ type Remote interface {
 }
 
 // Dial calls grpc.NewClient and returns it as a Remote.
+// Trace context is propagated to the target with each request.
 func Dial(ctx context.Context, target string, opts ...grpc.DialOption) Remote {
 	conn, err := dial(target, opts...)
 	return &eagerRemote{
@@ -146,6 +148,7 @@ func (c *chanRemote) Connect(_ context.Context) (*grpc.ClientConn, error) {
 func dial(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	dialOpts := processOpts(opts)
 
+	opts = append([]grpc.DialOption{grpc.WithStatsHandler(otelgrpc.NewClientHandler())}, opts...)
 	conn, err := grpc.NewClient(target, opts...)
 	if err != nil {
 		return nil, err
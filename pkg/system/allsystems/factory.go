@@ -4,11 +4,16 @@ import (
 	"github.com/smart-core-os/sc-bos/pkg/system"
 	"github.com/smart-core-os/sc-bos/pkg/system/alerts"
 	"github.com/smart-core-os/sc-bos/pkg/system/authn"
+	"github.com/smart-core-os/sc-bos/pkg/system/billing"
+	"github.com/smart-core-os/sc-bos/pkg/system/conversion"
 	"github.com/smart-core-os/sc-bos/pkg/system/gateway"
 	"github.com/smart-core-os/sc-bos/pkg/system/history"
 	"github.com/smart-core-os/sc-bos/pkg/system/hub"
+	"github.com/smart-core-os/sc-bos/pkg/system/lighttest"
 	"github.com/smart-core-os/sc-bos/pkg/system/publications"
+	"github.com/smart-core-os/sc-bos/pkg/system/reports"
 	"github.com/smart-core-os/sc-bos/pkg/system/tenants"
+	"github.com/smart-core-os/sc-bos/pkg/system/utilisation"
 )
 
 // Factories returns a new map containing all known system factories.
@@ -17,11 +22,16 @@ func Factories() map[string]system.Factory {
 	return map[string]system.Factory{
 		"alerts":           alerts.Factory,
 		"authn":            authn.Factory(),
+		"billing":          billing.Factory,
+		"conversion":       conversion.Factory,
 		"history":          history.Factory,
 		"hub":              hub.Factory(),
 		gateway.Name:       gatewayFactory,
 		gateway.LegacyName: gatewayFactory,
+		"lighttest":        lighttest.Factory,
 		"publications":     publications.Factory,
+		"reports":          reports.Factory(),
 		"tenants":          tenants.Factory,
+		"utilisation":      utilisation.Factory,
 	}
 }
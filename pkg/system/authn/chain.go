@@ -0,0 +1,94 @@
+package authn
+
+import (
+	"context"
+	"errors"
+
+	"github.com/smart-core-os/sc-bos/internal/auth/accesstoken"
+)
+
+// chainVerifier checks user credentials against each of its members in turn,
+// moving on to the next member only if the credentials are not recognised.
+// This allows users from multiple sources, like the local account database and an LDAP directory, to log in.
+type chainVerifier []accesstoken.Verifier
+
+// newPasswordVerifier returns a verifier that checks credentials against all of vs.
+func newPasswordVerifier(vs []accesstoken.Verifier) accesstoken.Verifier {
+	if len(vs) == 1 {
+		return vs[0]
+	}
+	return chainVerifier(vs)
+}
+
+func (c chainVerifier) Verify(ctx context.Context, username, password string) (accesstoken.SecretData, error) {
+	return c.VerifyWithSecondFactor(ctx, username, password, "")
+}
+
+func (c chainVerifier) VerifyWithSecondFactor(ctx context.Context, username, password, code string) (accesstoken.SecretData, error) {
+	return c.first(func(v accesstoken.Verifier) (accesstoken.SecretData, error) {
+		if v, ok := v.(accesstoken.SecondFactorVerifier); ok {
+			return v.VerifyWithSecondFactor(ctx, username, password, code)
+		}
+		return v.Verify(ctx, username, password)
+	})
+}
+
+// VerifyRefresh asks each member that is a RefreshVerifier to refresh data.
+// Members are expected to return ErrInvalidCredentials for identities they didn't issue.
+// If no member recognises data but some members can't refresh identities, data is returned unchanged.
+func (c chainVerifier) VerifyRefresh(ctx context.Context, data accesstoken.SecretData) (accesstoken.SecretData, error) {
+	var unrefreshable bool
+	res, err := c.first(func(v accesstoken.Verifier) (accesstoken.SecretData, error) {
+		rv, ok := v.(accesstoken.RefreshVerifier)
+		if !ok {
+			unrefreshable = true
+			return accesstoken.SecretData{}, accesstoken.ErrInvalidCredentials
+		}
+		return rv.VerifyRefresh(ctx, data)
+	})
+	if errors.Is(err, accesstoken.ErrInvalidCredentials) && unrefreshable {
+		return data, nil
+	}
+	return res, err
+}
+
+// first returns the result of the first call to fn that isn't ErrInvalidCredentials.
+// Unexpected errors, like a directory being unavailable, don't stop later members being tried, but are returned
+// instead of ErrInvalidCredentials if no member succeeds.
+func (c chainVerifier) first(fn func(v accesstoken.Verifier) (accesstoken.SecretData, error)) (accesstoken.SecretData, error) {
+	var firstErr error
+	for _, v := range c {
+		data, err := fn(v)
+		switch {
+		case err == nil:
+			return data, nil
+		case errors.Is(err, accesstoken.ErrInvalidCredentials):
+		case isTokenError(err):
+			// the member recognised the credentials but rejected them, for example no roles or an otp is needed
+			return accesstoken.SecretData{}, err
+		default:
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if firstErr != nil {
+		return accesstoken.SecretData{}, firstErr
+	}
+	return accesstoken.SecretData{}, accesstoken.ErrInvalidCredentials
+}
+
+func isTokenError(err error) bool {
+	for _, e := range []error{
+		accesstoken.ErrNoRolesAssigned,
+		accesstoken.ErrSecondFactorRequired,
+		accesstoken.ErrInvalidSecondFactor,
+		// a locked account mustn't be bypassed by a later member, like a directory user with the same name
+		accesstoken.ErrAccountLocked,
+	} {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
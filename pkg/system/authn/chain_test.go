@@ -0,0 +1,148 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/internal/account"
+	"github.com/smart-core-os/sc-bos/internal/auth/accesstoken"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+func TestChainVerifier_Verify(t *testing.T) {
+	var first, second accesstoken.MemoryVerifier
+	addRecord := func(v *accesstoken.MemoryVerifier, data accesstoken.SecretData, secret string) {
+		t.Helper()
+		if err := v.AddRecord(data); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := v.AddSecret(data.TenantID, secret); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addRecord(&first, accesstoken.SecretData{TenantID: "alice", SystemRoles: []string{"admin"}}, "a")
+	addRecord(&first, accesstoken.SecretData{TenantID: "carol"}, "c") // no roles
+	addRecord(&second, accesstoken.SecretData{TenantID: "bob", SystemRoles: []string{"viewer"}}, "b")
+	addRecord(&second, accesstoken.SecretData{TenantID: "carol", SystemRoles: []string{"viewer"}}, "c")
+	unavailable := errors.New("unavailable")
+
+	chain := newPasswordVerifier([]accesstoken.Verifier{&first, accesstoken.NeverVerify(unavailable), &second})
+	tests := []struct {
+		username, password string
+		want               string
+		wantErr            error
+	}{
+		{username: "alice", password: "a", want: "alice"},
+		{username: "bob", password: "b", want: "bob"},
+		{username: "alice", password: "b", wantErr: unavailable},
+		{username: "carol", password: "c", wantErr: accesstoken.ErrNoRolesAssigned},
+	}
+	for _, tt := range tests {
+		got, err := chain.Verify(context.Background(), tt.username, tt.password)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Verify(%q) error got %v, want %v", tt.username, err, tt.wantErr)
+		}
+		if got.TenantID != tt.want {
+			t.Errorf("Verify(%q) got %q, want %q", tt.username, got.TenantID, tt.want)
+		}
+	}
+
+	chain = newPasswordVerifier([]accesstoken.Verifier{&first, &second})
+	if _, err := chain.Verify(context.Background(), "dave", "d"); !errors.Is(err, accesstoken.ErrInvalidCredentials) {
+		t.Errorf("Verify(unknown) error got %v, want %v", err, accesstoken.ErrInvalidCredentials)
+	}
+}
+
+func TestChainVerifier_Verify_locked(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	accountStore := account.NewMemoryStore(logger)
+	accountServer := account.NewServer(accountStore, logger)
+	_, err := accountServer.CreateAccount(ctx, &gen.CreateAccountRequest{
+		Account: &gen.Account{
+			Type:        gen.Account_USER_ACCOUNT,
+			DisplayName: "Alice",
+			Details:     &gen.Account_UserDetails{UserDetails: &gen.UserAccount{Username: "alice"}},
+		},
+		Password: "local-password",
+	})
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	local := newLocalUserVerifier(accountStore)
+	local.lockout = &account.LockoutPolicy{MaxFailures: 3, Duration: time.Minute}
+
+	// a directory, like LDAP, with a user of the same name but a different password
+	var directory accesstoken.MemoryVerifier
+	if err := directory.AddRecord(accesstoken.SecretData{TenantID: "alice", SystemRoles: []string{"admin"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := directory.AddSecret("alice", "directory-password"); err != nil {
+		t.Fatal(err)
+	}
+
+	chain := newPasswordVerifier([]accesstoken.Verifier{local, &directory})
+	for range 3 {
+		if _, err := chain.Verify(ctx, "alice", "wrong"); !errors.Is(err, accesstoken.ErrInvalidCredentials) {
+			t.Fatalf("Verify(wrong) error got %v, want %v", err, accesstoken.ErrInvalidCredentials)
+		}
+	}
+	// the local account is locked, the directory user mustn't be able to log in in its place
+	got, err := chain.Verify(ctx, "alice", "directory-password")
+	if !errors.Is(err, accesstoken.ErrAccountLocked) {
+		t.Fatalf("Verify error got %v, want %v", err, accesstoken.ErrAccountLocked)
+	}
+	if got.TenantID != "" {
+		t.Fatalf("Verify got %q, want no identity", got.TenantID)
+	}
+}
+
+func TestChainVerifier_VerifyRefresh(t *testing.T) {
+	refresher := func(subject string) accesstoken.Verifier {
+		return refreshFunc(func(data accesstoken.SecretData) (accesstoken.SecretData, error) {
+			if data.TenantID != subject {
+				return accesstoken.SecretData{}, accesstoken.ErrInvalidCredentials
+			}
+			data.Title = "refreshed " + subject
+			return data, nil
+		})
+	}
+
+	chain := chainVerifier{refresher("alice"), refresher("bob")}
+	got, err := chain.VerifyRefresh(context.Background(), accesstoken.SecretData{TenantID: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(accesstoken.SecretData{TenantID: "bob", Title: "refreshed bob"}, got); diff != "" {
+		t.Errorf("VerifyRefresh (-want,+got)\n%s", diff)
+	}
+	if _, err := chain.VerifyRefresh(context.Background(), accesstoken.SecretData{TenantID: "carol"}); !errors.Is(err, accesstoken.ErrInvalidCredentials) {
+		t.Errorf("VerifyRefresh(unknown) error got %v, want %v", err, accesstoken.ErrInvalidCredentials)
+	}
+
+	// members that can't refresh keep their sessions as they are
+	chain = append(chain, &accesstoken.MemoryVerifier{})
+	data := accesstoken.SecretData{TenantID: "carol"}
+	got, err = chain.VerifyRefresh(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(data, got); diff != "" {
+		t.Errorf("VerifyRefresh (-want,+got)\n%s", diff)
+	}
+}
+
+type refreshFunc func(data accesstoken.SecretData) (accesstoken.SecretData, error)
+
+func (f refreshFunc) Verify(context.Context, string, string) (accesstoken.SecretData, error) {
+	return accesstoken.SecretData{}, accesstoken.ErrInvalidCredentials
+}
+
+func (f refreshFunc) VerifyRefresh(_ context.Context, data accesstoken.SecretData) (accesstoken.SecretData, error) {
+	return f(data)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 
 	"github.com/smart-core-os/sc-bos/internal/auth/keycloak"
+	"github.com/smart-core-os/sc-bos/internal/auth/ldap"
+	"github.com/smart-core-os/sc-bos/pkg/auth/oidc"
 	"github.com/smart-core-os/sc-bos/pkg/system"
 	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
 )
@@ -31,6 +33,65 @@ type User struct {
 	LocalAccounts bool `json:"localAccounts,omitempty"`
 	// Keycloak configures access token validation against a KeyCloak server using OIDC.
 	Keycloak *keycloak.Config `json:"keycloakAccounts,omitempty"`
+	// OIDCAccounts configures access token validation against generic OpenID Connect providers, like Entra ID or Okta.
+	// Groups from the token are mapped to system roles and zones.
+	OIDCAccounts []oidc.ProviderConfig `json:"oidcAccounts,omitempty"`
+	// LDAPAccounts enables user login using a username and password checked against an LDAP directory,
+	// like Active Directory or OpenLDAP.
+	// Directory groups are mapped to system roles and zones.
+	LDAPAccounts *ldap.Config `json:"ldapAccounts,omitempty"`
+	// Sessions configures refresh tokens for users that log in using a password via FileAccounts, LocalAccounts, or LDAPAccounts.
+	// Refresh tokens are issued unless Sessions.Disabled is true.
+	Sessions *Sessions `json:"sessions,omitempty"`
+	// PasswordPolicy configures the passwords LocalAccounts are allowed to have.
+	// The policy is checked whenever a password is set, existing passwords are not affected.
+	PasswordPolicy *PasswordPolicy `json:"passwordPolicy,omitempty"`
+	// Lockout configures protection against password guessing.
+	// Lockout is enabled unless Lockout.Disabled is true.
+	Lockout *Lockout `json:"lockout,omitempty"`
+}
+
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters in a password, the minimum allowed is 10.
+	MinLength int `json:"minLength,omitempty"`
+	// MinCharacterClasses is the number of different classes of character a password must contain, up to 4.
+	// The classes are lower case letters, upper case letters, digits, and symbols.
+	MinCharacterClasses int `json:"minCharacterClasses,omitempty"`
+	// DenylistFile is a text file of passwords that are not allowed, one per line, compared case-insensitively.
+	// Use this to prevent passwords known to be breached or commonly used.
+	// Relative paths are resolved against the config directories.
+	DenylistFile string `json:"denylistFile,omitempty"`
+	// History is the number of most recent passwords of an account, including the current one, that can't be reused.
+	// At most 25 passwords are remembered.
+	History int `json:"history,omitempty"`
+}
+
+type Lockout struct {
+	// Disabled turns off all lockouts.
+	Disabled bool `json:"disabled,omitempty"`
+	// MaxFailures is the number of consecutive failed logins that lock a LocalAccounts user account.
+	// Defaults to 5.
+	MaxFailures int `json:"maxFailures,omitempty"`
+	// Duration is how long accounts and addresses are locked out for the first time, defaults to 1 minute.
+	// Each consecutive lockout doubles the duration, up to MaxDuration.
+	Duration *jsontypes.Duration `json:"duration,omitempty"`
+	// MaxDuration is the longest accounts and addresses are locked out for, defaults to 1 hour.
+	MaxDuration *jsontypes.Duration `json:"maxDuration,omitempty"`
+	// FailureWindow is how long failed logins are remembered for after the last failure or lockout.
+	// Defaults to 15 minutes.
+	FailureWindow *jsontypes.Duration `json:"failureWindow,omitempty"`
+	// MaxAddressFailures is the number of consecutive failed logins from a single network address, for any account,
+	// after which logins from that address are refused.
+	// Defaults to 20.
+	MaxAddressFailures int `json:"maxAddressFailures,omitempty"`
+}
+
+type Sessions struct {
+	// Disabled stops refresh tokens being issued, users have to log in again when their access token expires.
+	Disabled bool `json:"disabled,omitempty"`
+	// RefreshValidity defaults to 7 days and specifies how long a session lasts if its refresh token isn't used.
+	// Each refresh extends the session by this amount.
+	RefreshValidity *jsontypes.Duration `json:"refreshValidity,omitempty"`
 }
 
 type System struct {
@@ -0,0 +1,179 @@
+package authn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-api/go/types"
+	auditlog "github.com/smart-core-os/sc-bos/internal/audit"
+	"github.com/smart-core-os/sc-bos/internal/audit/auditdb"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const (
+	// maxLoginEvents is the number of login events kept in memory if the audit log is disabled,
+	// and the number of existing events sent by PullSecurityEvents.
+	maxLoginEvents = 100
+	// loginEventSubsystem is the SecurityEvent.Source.Subsystem of login events.
+	loginEventSubsystem = "authentication"
+	// loginAuditService is the AuditEvent.Service of login events recorded in the audit log.
+	loginAuditService = "smartcore.bos.authn"
+	// lockAccountMethod and blockAddressMethod are the AuditEvent.Method of login events.
+	lockAccountMethod  = "LockAccount"
+	blockAddressMethod = "BlockAddress"
+)
+
+// loginEvents records security events about logins, like accounts being locked after failed attempts.
+// Events are stored in the audit log, so they are kept after a restart, and served via the SecurityEventApi.
+type loginEvents struct {
+	gen.UnimplementedSecurityEventApiServer
+
+	now    func() time.Time
+	log    *auditlog.Log
+	logs   *auditlog.Server // to list events from log
+	logger *zap.Logger
+}
+
+// newLoginEvents returns loginEvents storing events in log.
+// If log is nil, recent events are kept in memory instead.
+func newLoginEvents(log *auditlog.Log, logger *zap.Logger) *loginEvents {
+	if log == nil {
+		db, err := auditdb.OpenMemory(context.Background(), auditdb.WithRetention(0, maxLoginEvents))
+		if err != nil {
+			// opening an in-memory database doesn't access any external resources, so this should never happen
+			panic("failed to open memory audit log: " + err.Error())
+		}
+		log = auditlog.NewLog(db)
+	}
+	return &loginEvents{now: time.Now, log: log, logs: auditlog.NewServer(log), logger: logger}
+}
+
+// accountLocked records that a user account was locked until the given time.
+func (e *loginEvents) accountLocked(username string, until time.Time) {
+	e.record(&gen.AuditEvent{
+		Method:   lockAccountMethod,
+		Resource: username,
+	}, until)
+}
+
+// addressBlocked records that logins from a network address were blocked until the given time.
+func (e *loginEvents) addressBlocked(addr string, until time.Time) {
+	e.record(&gen.AuditEvent{
+		Actor:    &gen.AuditEvent_Actor{RemoteAddress: addr},
+		Method:   blockAddressMethod,
+		Resource: addr,
+	}, until)
+}
+
+// loginEventRequest is the AuditEvent.Request of login events.
+type loginEventRequest struct {
+	Until  string `json:"until"`
+	Reason string `json:"reason"`
+}
+
+func (e *loginEvents) record(event *gen.AuditEvent, until time.Time) {
+	request, _ := json.Marshal(loginEventRequest{
+		Until:  until.Format(time.RFC3339),
+		Reason: "too many failed login attempts",
+	})
+	event.Service = loginAuditService
+	event.Request = string(request)
+	event.RecordTime = timestamppb.New(e.now())
+	event.Result = &gen.AuditEvent_Result{Code: int32(codes.OK)}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.log.Record(ctx, event); err != nil {
+		e.logger.Warn("failed to record login event in audit log", zap.String("method", event.Method),
+			zap.String("resource", event.Resource), zap.Error(err))
+	}
+}
+
+// ListSecurityEvents returns login events, newest first.
+// Page tokens are those of the AuditApi, so pages don't shift as new events are recorded.
+func (e *loginEvents) ListSecurityEvents(ctx context.Context, req *gen.ListSecurityEventsRequest) (*gen.ListSecurityEventsResponse, error) {
+	logRes, err := e.logs.ListAuditEvents(ctx, &gen.ListAuditEventsRequest{
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+		Query:     &gen.AuditEvent_Query{Service: loginAuditService},
+		OrderBy:   "record_time desc",
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &gen.ListSecurityEventsResponse{
+		NextPageToken: logRes.NextPageToken,
+		TotalSize:     logRes.TotalSize,
+	}
+	for _, event := range logRes.AuditEvents {
+		res.SecurityEvents = append(res.SecurityEvents, securityEvent(event))
+	}
+	return res, nil
+}
+
+func (e *loginEvents) PullSecurityEvents(req *gen.PullSecurityEventsRequest, server gen.SecurityEventApi_PullSecurityEventsServer) error {
+	// listen before reading existing events so none are missed, an event added in between may be sent twice
+	added := e.log.Listen(server.Context())
+	if !req.UpdatesOnly {
+		existing, err := e.ListSecurityEvents(server.Context(), &gen.ListSecurityEventsRequest{PageSize: maxLoginEvents})
+		if err != nil {
+			return err
+		}
+		res := &gen.PullSecurityEventsResponse{}
+		for _, event := range slices.Backward(existing.SecurityEvents) {
+			res.Changes = append(res.Changes, addedChange(req.Name, event))
+		}
+		if len(res.Changes) > 0 {
+			if err := server.Send(res); err != nil {
+				return err
+			}
+		}
+	}
+	for event := range added {
+		if event.Service != loginAuditService {
+			continue
+		}
+		if err := server.Send(&gen.PullSecurityEventsResponse{Changes: []*gen.PullSecurityEventsResponse_Change{addedChange(req.Name, securityEvent(event))}}); err != nil {
+			return err
+		}
+	}
+	return server.Context().Err()
+}
+
+// securityEvent converts a login event recorded in the audit log into a SecurityEvent.
+func securityEvent(event *gen.AuditEvent) *gen.SecurityEvent {
+	var req loginEventRequest
+	_ = json.Unmarshal([]byte(event.Request), &req) // a bad request only affects the description
+	var description string
+	switch event.Method {
+	case lockAccountMethod:
+		description = fmt.Sprintf("Account %q locked until %s after %s", event.Resource, req.Until, req.Reason)
+	case blockAddressMethod:
+		description = fmt.Sprintf("Logins from %s blocked until %s after %s", event.Resource, req.Until, req.Reason)
+	default:
+		description = fmt.Sprintf("%s %s", event.Method, event.Resource)
+	}
+	return &gen.SecurityEvent{
+		Id:                event.Id,
+		SecurityEventTime: event.RecordTime,
+		Description:       description,
+		Source:            &gen.SecurityEvent_Source{Id: event.Resource, Name: event.Resource, Subsystem: loginEventSubsystem},
+		Priority:          1,
+		EventType:         gen.SecurityEvent_INVALID_LOGON_ATTEMPT,
+	}
+}
+
+func addedChange(name string, event *gen.SecurityEvent) *gen.PullSecurityEventsResponse_Change {
+	return &gen.PullSecurityEventsResponse_Change{
+		Name:       name,
+		NewValue:   event,
+		ChangeTime: event.SecurityEventTime,
+		Type:       types.ChangeType_ADD,
+	}
+}
@@ -0,0 +1,90 @@
+package authn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	auditlog "github.com/smart-core-os/sc-bos/internal/audit"
+	"github.com/smart-core-os/sc-bos/internal/audit/auditdb"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+func TestLoginEvents_audit(t *testing.T) {
+	ctx := context.Background()
+	db, err := auditdb.OpenMemory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	log := auditlog.NewLog(db)
+	events := newLoginEvents(log, zap.NewNop())
+	until := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	events.accountLocked("alice", until)
+	events.addressBlocked("192.0.2.1", until)
+
+	recorded, err := auditlog.NewServer(log).ListAuditEvents(ctx, &gen.ListAuditEventsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded.AuditEvents) != 2 {
+		t.Fatalf("recorded %d audit events, want 2", len(recorded.AuditEvents))
+	}
+	for i, want := range []struct{ method, resource string }{{lockAccountMethod, "alice"}, {blockAddressMethod, "192.0.2.1"}} {
+		got := recorded.AuditEvents[i]
+		if got.Service != loginAuditService || got.Method != want.method || got.Resource != want.resource {
+			t.Errorf("event %d = %s/%s %s, want %s/%s %s", i, got.Service, got.Method, got.Resource, loginAuditService, want.method, want.resource)
+		}
+	}
+
+	// events are read from the audit log, so are still available after a restart
+	restarted := newLoginEvents(log, zap.NewNop())
+	res, err := restarted.ListSecurityEvents(ctx, &gen.ListSecurityEventsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range res.SecurityEvents {
+		got = append(got, e.Description)
+	}
+	want := []string{
+		`Logins from 192.0.2.1 blocked until 2024-01-01T12:00:00Z after too many failed login attempts`,
+		`Account "alice" locked until 2024-01-01T12:00:00Z after too many failed login attempts`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListSecurityEvents descriptions (-want,+got)\n%s", diff)
+	}
+}
+
+func TestLoginEvents_ListSecurityEvents_paging(t *testing.T) {
+	ctx := context.Background()
+	events := newLoginEvents(nil, zap.NewNop())
+	until := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, username := range []string{"alice", "bob", "carol"} {
+		events.accountLocked(username, until)
+	}
+
+	var got []string
+	req := &gen.ListSecurityEventsRequest{PageSize: 1}
+	for {
+		res, err := events.ListSecurityEvents(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range res.SecurityEvents {
+			got = append(got, e.Source.Id)
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
+		// new events don't shift the pages being read
+		events.accountLocked("dave", until)
+	}
+	if diff := cmp.Diff([]string{"carol", "bob", "alice"}, got); diff != "" {
+		t.Errorf("ListSecurityEvents sources (-want,+got)\n%s", diff)
+	}
+}
@@ -2,6 +2,7 @@ package authn
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -12,6 +13,9 @@ import (
 	"github.com/smart-core-os/sc-bos/internal/account"
 	"github.com/smart-core-os/sc-bos/internal/auth/accesstoken"
 	"github.com/smart-core-os/sc-bos/internal/auth/keycloak"
+	"github.com/smart-core-os/sc-bos/internal/auth/ldap"
+	"github.com/smart-core-os/sc-bos/internal/auth/session"
+	"github.com/smart-core-os/sc-bos/pkg/auth/oidc"
 	"github.com/smart-core-os/sc-bos/pkg/auth/token"
 	"github.com/smart-core-os/sc-bos/pkg/node"
 	"github.com/smart-core-os/sc-bos/pkg/system"
@@ -19,31 +23,42 @@ import (
 	"github.com/smart-core-os/sc-bos/pkg/task/service"
 )
 
-const TokenEndpointPath = "/oauth2/token"
+const (
+	TokenEndpointPath      = "/oauth2/token"
+	RevocationEndpointPath = "/oauth2/revoke"
+)
 
 func Factory() system.Factory {
 	return &factory{
-		server: &nextOrNotFound{},
+		server:  &nextOrNotFound{},
+		revoker: &nextOrNotFound{},
 	}
 }
 
 type factory struct {
 	handleOnce sync.Once // ensures we only mux.Handle once - otherwise it would panic
 	server     *nextOrNotFound
+	revoker    *nextOrNotFound
 }
 
 func (f *factory) New(services system.Services) service.Lifecycle {
 	f.handleOnce.Do(func() {
 		services.HTTPMux.Handle(TokenEndpointPath, f.server)
+		services.HTTPMux.Handle(RevocationEndpointPath, f.revoker)
 	})
+	logger := services.Logger.Named("authn")
 	s := &System{
 		server:        f.server,
+		revoker:       f.revoker,
 		configDirs:    services.ConfigDirs,
+		dataDir:       services.DataDir,
+		announcer:     services.Node,
 		clienter:      services.Node,
 		cohortManager: services.CohortManager,
-		logger:        services.Logger.Named("authn"),
+		logger:        logger,
 		validators:    services.TokenValidators,
 		accounts:      services.Accounts,
+		events:        newLoginEvents(services.AuditLog, logger.Named("events")),
 	}
 	s.Service = service.New(service.MonoApply(s.applyConfig),
 		service.WithParser(config.ReadConfig),
@@ -56,9 +71,12 @@ func (f *factory) New(services system.Services) service.Lifecycle {
 
 type System struct {
 	*service.Service[config.Root]
-	server *nextOrNotFound
+	server  *nextOrNotFound
+	revoker *nextOrNotFound
 
 	configDirs    []string
+	dataDir       string
+	announcer     *node.Node
 	clienter      node.ClientConner
 	cohortManager node.Remote
 	logger        *zap.Logger
@@ -66,6 +84,12 @@ type System struct {
 
 	validators      *token.ValidatorSet
 	addedValidators []token.Validator // the validators we setup in applyConfig, used to remove them again
+
+	sessions     *session.Store // nil until a config needs it
+	undoSessions node.Undo
+
+	events     *loginEvents
+	undoEvents node.Undo // nil unless events are announced
 }
 
 func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
@@ -73,6 +97,7 @@ func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
 	s.deleteValidators()
 
 	var serveTokenEndpoint bool
+	var passwordVerifiers []accesstoken.Verifier
 	tokenServerOpts := []accesstoken.ServerOption{
 		accesstoken.WithLogger(s.logger.Named("server")),
 		accesstoken.WithPermittedSignatureAlgorithms(keycloak.DefaultPermittedSignatureAlgorithms),
@@ -95,18 +120,34 @@ func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
 		s.logger.Debug("using system tenant verifier", zap.Duration("validity", validity))
 	}
 
+	var passwordPolicy account.PasswordPolicy
+	if cfg.User != nil {
+		var err error
+		passwordPolicy, err = s.passwordPolicy(cfg.User.PasswordPolicy)
+		if err != nil {
+			return err
+		}
+	}
+	if s.accounts != nil {
+		s.accounts.SetPasswordPolicy(passwordPolicy)
+	}
+
+	var lockedOut bool
 	if cfg.User != nil {
 		// User accounts that are verified by external authorization servers don't need us to
 		// host the oauth/token endpoint, so don't
 
 		validity := cfg.User.Validity.Or(24 * time.Hour)
+		lockoutPolicy, addressLimiter := lockoutPolicies(cfg.User.Lockout)
 
 		var localAccountsAvailable bool
 		if cfg.User.LocalAccounts && s.accounts != nil {
 			localAccountsAvailable = true
-			serveTokenEndpoint = true
 			verifier := newLocalUserVerifier(s.accounts)
-			tokenServerOpts = append(tokenServerOpts, accesstoken.WithPasswordFlow(verifier, validity))
+			verifier.logger = s.logger.Named("local")
+			verifier.lockout = lockoutPolicy
+			verifier.onLock = s.events.accountLocked
+			passwordVerifiers = append(passwordVerifiers, verifier)
 			s.logger.Debug("using local user database verifier", zap.Duration("validity", validity))
 		}
 
@@ -130,8 +171,7 @@ func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
 				if err != nil {
 					return fmt.Errorf("user %w", err)
 				}
-				tokenServerOpts = append(tokenServerOpts, accesstoken.WithPasswordFlow(fileVerifier, validity))
-				serveTokenEndpoint = true
+				passwordVerifiers = append(passwordVerifiers, fileVerifier)
 			}
 		}
 
@@ -149,6 +189,67 @@ func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
 			s.logger.Debug("using keycloak OIDC token validator")
 		}
 
+		// Validate access tokens issued by other OpenID Connect providers, discovering their keys via OIDC metadata.
+		for _, providerConfig := range cfg.User.OIDCAccounts {
+			if providerConfig.Issuer == "" {
+				return errors.New("oidcAccounts: issuer is required")
+			}
+			if providerConfig.Audience == "" {
+				return fmt.Errorf("oidcAccounts: audience is required for issuer %q", providerConfig.Issuer)
+			}
+			validator := oidc.NewProviderValidator(providerConfig)
+			s.addedValidators = append(s.addedValidators, validator)
+			s.validators.Append(validator)
+			s.logger.Debug("using OIDC token validator", zap.String("issuer", providerConfig.Issuer))
+		}
+
+		// Verify user credentials via the OAuth2 Password Flow against an LDAP directory.
+		if cfg.User.LDAPAccounts != nil {
+			verifier, err := ldap.NewVerifier(*cfg.User.LDAPAccounts)
+			if err != nil {
+				return fmt.Errorf("ldapAccounts: %w", err)
+			}
+			passwordVerifiers = append(passwordVerifiers, verifier)
+			s.logger.Debug("using LDAP user verifier", zap.String("url", cfg.User.LDAPAccounts.URL))
+		}
+
+		passwordFlow := len(passwordVerifiers) > 0
+		if passwordFlow {
+			serveTokenEndpoint = true
+			tokenServerOpts = append(tokenServerOpts, accesstoken.WithPasswordFlow(newPasswordVerifier(passwordVerifiers), validity))
+		}
+
+		// Slow down password guessing, recording lockouts as security events.
+		if passwordFlow && addressLimiter != nil {
+			lockedOut = true
+			addressLimiter.OnBlock = s.events.addressBlocked
+			tokenServerOpts = append(tokenServerOpts, accesstoken.WithAddressLimiter(addressLimiter))
+			s.logger.Debug("locking out repeated failed logins",
+				zap.Int("maxFailures", lockoutPolicy.MaxFailures), zap.Int("maxAddressFailures", addressLimiter.MaxFailures))
+		}
+
+		// Issue refresh tokens so users don't need to enter their password each time their access token expires.
+		if passwordFlow && (cfg.User.Sessions == nil || !cfg.User.Sessions.Disabled) {
+			refreshValidity := 7 * 24 * time.Hour
+			if cfg.User.Sessions != nil {
+				refreshValidity = cfg.User.Sessions.RefreshValidity.Or(refreshValidity)
+			}
+			sessions, err := s.openSessions(ctx)
+			if err != nil {
+				return fmt.Errorf("sessions: %w", err)
+			}
+			tokenServerOpts = append(tokenServerOpts, accesstoken.WithSessions(sessions, refreshValidity))
+			s.logger.Debug("issuing refresh tokens for user sessions", zap.Duration("refreshValidity", refreshValidity))
+		} else {
+			s.closeSessions()
+		}
+	} else {
+		s.closeSessions()
+	}
+	if lockedOut {
+		s.announceLoginEvents()
+	} else {
+		s.unannounceLoginEvents()
 	}
 
 	if serveTokenEndpoint {
@@ -163,6 +264,7 @@ func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
 		s.validators.Append(validator)
 		// serve the handler
 		s.server.Next(cors.Default().Handler(server))
+		s.revoker.Next(cors.Default().Handler(server.RevocationHandler()))
 	}
 
 	return nil
@@ -170,7 +272,13 @@ func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
 
 func (s *System) Clear() {
 	s.server.Clear()
+	s.revoker.Clear()
 	s.deleteValidators()
+	s.closeSessions()
+	s.unannounceLoginEvents()
+	if s.accounts != nil {
+		s.accounts.SetPasswordPolicy(account.PasswordPolicy{})
+	}
 }
 
 func (s *System) deleteValidators() {
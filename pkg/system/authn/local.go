@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"slices"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -21,16 +22,58 @@ import (
 
 type localUserVerifier struct {
 	accounts *account.Store
+	// lockout, if not nil, locks accounts after failed login attempts.
+	lockout *account.LockoutPolicy
+	// onLock, if not nil, is called when an account becomes locked.
+	onLock func(username string, until time.Time)
+	logger *zap.Logger
+	now    func() time.Time
 }
 
 func newLocalUserVerifier(accounts *account.Store) *localUserVerifier {
 	return &localUserVerifier{
 		accounts: accounts,
+		logger:   zap.NewNop(),
+		now:      time.Now,
 	}
 }
 
 func (l *localUserVerifier) Verify(ctx context.Context, username, password string) (accesstoken.SecretData, error) {
-	var data accesstoken.SecretData
+	return l.VerifyWithSecondFactor(ctx, username, password, "")
+}
+
+// VerifyWithSecondFactor checks the username and password, and code if the account has TOTP enabled.
+func (l *localUserVerifier) VerifyWithSecondFactor(ctx context.Context, username, password, code string) (accesstoken.SecretData, error) {
+	var (
+		data       accesstoken.SecretData
+		accountID  int64
+		attempt    *account.LoginAttempt
+		mfaEnabled bool
+	)
+	now := l.now()
+	if l.lockout != nil {
+		// count the attempt before checking the password so parallel guesses can't exceed the lockout policy
+		err := l.accounts.Write(ctx, func(tx *account.Tx) error {
+			userAccount, err := tx.GetAccountByUsername(ctx, username)
+			if errors.Is(err, sql.ErrNoRows) {
+				return accesstoken.ErrInvalidCredentials
+			} else if err != nil {
+				return err
+			}
+			a, err := tx.BeginLoginAttempt(ctx, userAccount.AccountID, now, *l.lockout)
+			if errors.Is(err, account.ErrAccountLocked) {
+				return accesstoken.ErrAccountLocked
+			} else if err != nil {
+				return err
+			}
+			attempt = &a
+			return nil
+		})
+		if err != nil {
+			return accesstoken.SecretData{}, err
+		}
+	}
+
 	err := l.accounts.Read(ctx, func(tx *account.Tx) error {
 		userAccount, err := tx.GetAccountByUsername(ctx, username)
 		if errors.Is(err, sql.ErrNoRows) {
@@ -38,24 +81,108 @@ func (l *localUserVerifier) Verify(ctx context.Context, username, password strin
 		} else if err != nil {
 			return err
 		}
+		accountID = userAccount.AccountID
 
-		err = tx.CheckAccountPassword(ctx, userAccount.AccountID, password)
+		err = tx.CheckAccountPassword(ctx, accountID, password)
 		if errors.Is(err, account.ErrIncorrectPassword) {
 			return accesstoken.ErrInvalidCredentials
 		} else if err != nil {
 			return err
 		}
 
-		data, err = accountTokenData(ctx, tx, userAccount.AccountID, false)
+		mfaEnabled, err = tx.TotpEnabled(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if mfaEnabled && code == "" {
+			return accesstoken.ErrSecondFactorRequired
+		}
+
+		data, err = accountTokenData(ctx, tx, accountID, false)
 		return err
 	})
+	if errors.Is(err, accesstoken.ErrInvalidCredentials) {
+		l.failed(username, attempt)
+		return accesstoken.SecretData{}, err
+	}
 	if err != nil {
+		l.cancelAttempt(ctx, username, attempt)
 		return accesstoken.SecretData{}, err
 	}
 
+	if mfaEnabled {
+		// checking the code consumes it, so needs a write transaction
+		err = l.accounts.Write(ctx, func(tx *account.Tx) error {
+			return tx.CheckSecondFactor(ctx, accountID, code, now)
+		})
+		if errors.Is(err, account.ErrIncorrectCode) {
+			l.failed(username, attempt)
+			return accesstoken.SecretData{}, accesstoken.ErrInvalidSecondFactor
+		} else if err != nil {
+			l.cancelAttempt(ctx, username, attempt)
+			return accesstoken.SecretData{}, err
+		}
+	}
+
+	if l.lockout != nil {
+		err = l.accounts.Write(ctx, func(tx *account.Tx) error {
+			return tx.ResetLoginFailures(ctx, accountID)
+		})
+		if err != nil {
+			return accesstoken.SecretData{}, err
+		}
+	}
+
 	return data, nil
 }
 
+// failed reports a lockout caused by attempt, which has already been counted as a failure.
+func (l *localUserVerifier) failed(username string, attempt *account.LoginAttempt) {
+	if attempt == nil || attempt.LockedUntil.IsZero() {
+		return
+	}
+	l.logger.Info("account locked after too many failed logins", zap.String("username", username), zap.Time("until", attempt.LockedUntil))
+	if l.onLock != nil {
+		l.onLock(username, attempt.LockedUntil)
+	}
+}
+
+// cancelAttempt stops attempt counting towards locking the account, as it neither failed nor succeeded.
+// Errors are logged rather than returned so the client still sees why their login didn't complete.
+func (l *localUserVerifier) cancelAttempt(ctx context.Context, username string, attempt *account.LoginAttempt) {
+	if attempt == nil {
+		return
+	}
+	err := l.accounts.Write(ctx, func(tx *account.Tx) error {
+		return tx.CancelLoginAttempt(ctx, *attempt)
+	})
+	if err != nil {
+		l.logger.Warn("failed to cancel login attempt", zap.String("username", username), zap.Error(err))
+	}
+}
+
+// VerifyRefresh returns the current token data for the account data was issued for.
+// Sessions for accounts that have been deleted, or no longer have any roles, can't be refreshed.
+func (l *localUserVerifier) VerifyRefresh(ctx context.Context, data accesstoken.SecretData) (accesstoken.SecretData, error) {
+	accountID, ok := account.ParseAccountID(data.TenantID)
+	if !ok {
+		return accesstoken.SecretData{}, accesstoken.ErrInvalidCredentials
+	}
+	var newData accesstoken.SecretData
+	err := l.accounts.Read(ctx, func(tx *account.Tx) error {
+		var err error
+		newData, err = accountTokenData(ctx, tx, accountID, false)
+		if errors.Is(err, sql.ErrNoRows) {
+			return accesstoken.ErrInvalidCredentials
+		}
+		return err
+	})
+	if err != nil {
+		return accesstoken.SecretData{}, err
+	}
+	return newData, nil
+}
+
 type localServiceVerifier struct {
 	accounts *account.Store
 }
@@ -3,18 +3,25 @@ package authn
 import (
 	"context"
 	"database/sql"
+	"encoding/base32"
 	"errors"
+	"path/filepath"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/smart-core-os/sc-bos/internal/account"
 	"github.com/smart-core-os/sc-bos/internal/auth/accesstoken"
 	"github.com/smart-core-os/sc-bos/internal/auth/permission"
 	"github.com/smart-core-os/sc-bos/internal/util/pass"
+	"github.com/smart-core-os/sc-bos/internal/util/totp"
 	"github.com/smart-core-os/sc-bos/pkg/auth/token"
 	"github.com/smart-core-os/sc-bos/pkg/gen"
 )
@@ -451,6 +458,276 @@ func TestLocalUserVerifier_Verify(t *testing.T) {
 	}
 }
 
+func TestLocalUserVerifier_VerifyWithSecondFactor(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	accountStore := account.NewMemoryStore(logger)
+	accountServer := account.NewServer(accountStore, logger)
+	verifier := newLocalUserVerifier(accountStore)
+
+	adminRoles, err := accountServer.ListRoles(ctx, &gen.ListRolesRequest{})
+	if err != nil {
+		t.Fatalf("failed to list roles: %v", err)
+	}
+	user, err := accountServer.CreateAccount(ctx, &gen.CreateAccountRequest{
+		Account: &gen.Account{
+			Type:        gen.Account_USER_ACCOUNT,
+			DisplayName: "User",
+			Details:     &gen.Account_UserDetails{UserDetails: &gen.UserAccount{Username: "user"}},
+		},
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	_, err = accountServer.CreateRoleAssignment(ctx, &gen.CreateRoleAssignmentRequest{
+		RoleAssignment: &gen.RoleAssignment{AccountId: user.Id, RoleId: adminRoles.Roles[0].Id},
+	})
+	if err != nil {
+		t.Fatalf("failed to assign role: %v", err)
+	}
+
+	// without enrollment, no code is needed
+	if _, err := verifier.Verify(ctx, "user", "password123"); err != nil {
+		t.Fatalf("Verify without mfa: %v", err)
+	}
+
+	begin, err := accountServer.BeginTotpEnrollment(ctx, &gen.BeginTotpEnrollmentRequest{Id: user.Id})
+	if err != nil {
+		t.Fatalf("failed to begin enrollment: %v", err)
+	}
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(begin.Secret)
+	if err != nil {
+		t.Fatalf("failed to decode secret: %v", err)
+	}
+	step := totp.Step(time.Now())
+	_, err = accountServer.ConfirmTotpEnrollment(ctx, &gen.ConfirmTotpEnrollmentRequest{Id: user.Id, Code: totp.Code(secret, step-1)})
+	if err != nil {
+		t.Fatalf("failed to confirm enrollment: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		code     string
+		wantErr  error
+	}{
+		{name: "wrong password", password: "wrong", code: totp.Code(secret, step), wantErr: accesstoken.ErrInvalidCredentials},
+		{name: "missing code", password: "password123", wantErr: accesstoken.ErrSecondFactorRequired},
+		{name: "wrong code", password: "password123", code: "000000", wantErr: accesstoken.ErrInvalidSecondFactor},
+		{name: "correct code", password: "password123", code: totp.Code(secret, step)},
+		{name: "reused code", password: "password123", code: totp.Code(secret, step), wantErr: accesstoken.ErrInvalidSecondFactor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.VerifyWithSecondFactor(ctx, "user", tt.password, tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyWithSecondFactor() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("lockout", func(t *testing.T) {
+		verifier.lockout = &account.LockoutPolicy{MaxFailures: 2, Duration: time.Minute}
+		verify := func(password string, wantErr error) {
+			t.Helper()
+			if _, err := verifier.VerifyWithSecondFactor(ctx, "user", password, ""); !errors.Is(err, wantErr) {
+				t.Errorf("VerifyWithSecondFactor(%q) error = %v, want %v", password, err, wantErr)
+			}
+		}
+		verify("wrong", accesstoken.ErrInvalidCredentials)
+		// asking for the second factor isn't a failed attempt
+		for range 3 {
+			verify("password123", accesstoken.ErrSecondFactorRequired)
+		}
+		verify("wrong", accesstoken.ErrInvalidCredentials)
+		verify("password123", accesstoken.ErrAccountLocked)
+	})
+}
+
+func TestLocalUserVerifier_lockout(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	accountStore := account.NewMemoryStore(logger)
+	accountServer := account.NewServer(accountStore, logger)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	var locked []string
+	verifier := newLocalUserVerifier(accountStore)
+	verifier.lockout = &account.LockoutPolicy{MaxFailures: 3, Duration: time.Minute}
+	verifier.onLock = func(username string, _ time.Time) { locked = append(locked, username) }
+	verifier.now = func() time.Time { return now }
+
+	adminRoles, err := accountServer.ListRoles(ctx, &gen.ListRolesRequest{})
+	if err != nil {
+		t.Fatalf("failed to list roles: %v", err)
+	}
+	user, err := accountServer.CreateAccount(ctx, &gen.CreateAccountRequest{
+		Account: &gen.Account{
+			Type:        gen.Account_USER_ACCOUNT,
+			DisplayName: "User",
+			Details:     &gen.Account_UserDetails{UserDetails: &gen.UserAccount{Username: "user"}},
+		},
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	_, err = accountServer.CreateRoleAssignment(ctx, &gen.CreateRoleAssignmentRequest{
+		RoleAssignment: &gen.RoleAssignment{AccountId: user.Id, RoleId: adminRoles.Roles[0].Id},
+	})
+	if err != nil {
+		t.Fatalf("failed to assign role: %v", err)
+	}
+
+	verify := func(password string, wantErr error) {
+		t.Helper()
+		if _, err := verifier.Verify(ctx, "user", password); !errors.Is(err, wantErr) {
+			t.Errorf("Verify(%q) error = %v, want %v", password, err, wantErr)
+		}
+	}
+
+	// a successful login resets the failure count
+	verify("wrong", accesstoken.ErrInvalidCredentials)
+	verify("wrong", accesstoken.ErrInvalidCredentials)
+	verify("password123", nil)
+	verify("wrong", accesstoken.ErrInvalidCredentials)
+	verify("wrong", accesstoken.ErrInvalidCredentials)
+	if len(locked) != 0 {
+		t.Fatalf("expected no lockouts yet, got %v", locked)
+	}
+	verify("wrong", accesstoken.ErrInvalidCredentials)
+	if diff := cmp.Diff([]string{"user"}, locked); diff != "" {
+		t.Errorf("locked accounts (-want,+got)\n%s", diff)
+	}
+	// the correct password doesn't help while locked
+	verify("password123", accesstoken.ErrAccountLocked)
+
+	now = now.Add(time.Minute)
+	verify("password123", nil)
+
+	// admins can unlock accounts early
+	for range 3 {
+		verify("wrong", accesstoken.ErrInvalidCredentials)
+	}
+	verify("password123", accesstoken.ErrAccountLocked)
+	if _, err := accountServer.UnlockAccount(ctx, &gen.UnlockAccountRequest{Id: user.Id}); err != nil {
+		t.Fatalf("failed to unlock account: %v", err)
+	}
+	verify("password123", nil)
+}
+
+func TestLocalUserVerifier_lockoutParallel(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	// the memory store doesn't allow reads during writes, use a file like a real node
+	accountStore, err := account.OpenStore(ctx, filepath.Join(t.TempDir(), "accounts.db"), logger)
+	if err != nil {
+		t.Fatalf("failed to open account store: %v", err)
+	}
+	t.Cleanup(func() { _ = accountStore.Close() })
+	accountServer := account.NewServer(accountStore, logger)
+	verifier := newLocalUserVerifier(accountStore)
+	verifier.lockout = &account.LockoutPolicy{MaxFailures: 3, Duration: time.Minute}
+
+	_, err = accountServer.CreateAccount(ctx, &gen.CreateAccountRequest{
+		Account: &gen.Account{
+			Type:        gen.Account_USER_ACCOUNT,
+			DisplayName: "User",
+			Details:     &gen.Account_UserDetails{UserDetails: &gen.UserAccount{Username: "user"}},
+		},
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	// parallel guesses can't check more passwords than the policy allows
+	var wg sync.WaitGroup
+	var checked, lockedOut atomic.Int32
+	for range 10 {
+		wg.Go(func() {
+			_, err := verifier.Verify(ctx, "user", "wrong")
+			switch {
+			case errors.Is(err, accesstoken.ErrInvalidCredentials):
+				checked.Add(1)
+			case errors.Is(err, accesstoken.ErrAccountLocked):
+				lockedOut.Add(1)
+			default:
+				t.Errorf("Verify error = %v", err)
+			}
+		})
+	}
+	wg.Wait()
+	if checked.Load() != 3 || lockedOut.Load() != 7 {
+		t.Errorf("parallel guesses got %d checked and %d locked, want 3 and 7", checked.Load(), lockedOut.Load())
+	}
+}
+
+func TestLocalUserVerifier_VerifyRefresh(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	accountStore := account.NewMemoryStore(logger)
+	accountServer := account.NewServer(accountStore, logger)
+	verifier := newLocalUserVerifier(accountStore)
+
+	roles, err := accountServer.ListRoles(ctx, &gen.ListRolesRequest{})
+	if err != nil {
+		t.Fatalf("failed to list roles: %v", err)
+	}
+	user, err := accountServer.CreateAccount(ctx, &gen.CreateAccountRequest{
+		Account: &gen.Account{
+			Type:        gen.Account_USER_ACCOUNT,
+			DisplayName: "User",
+			Details:     &gen.Account_UserDetails{UserDetails: &gen.UserAccount{Username: "user"}},
+		},
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	assignment, err := accountServer.CreateRoleAssignment(ctx, &gen.CreateRoleAssignmentRequest{
+		RoleAssignment: &gen.RoleAssignment{AccountId: user.Id, RoleId: roles.Roles[0].Id},
+	})
+	if err != nil {
+		t.Fatalf("failed to assign role: %v", err)
+	}
+
+	data, err := verifier.Verify(ctx, "user", "password123")
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	_, err = accountServer.UpdateAccount(ctx, &gen.UpdateAccountRequest{
+		Account:    &gen.Account{Id: user.Id, DisplayName: "Renamed"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"display_name"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to update account: %v", err)
+	}
+	refreshed, err := verifier.VerifyRefresh(ctx, data)
+	if err != nil {
+		t.Fatalf("VerifyRefresh: %v", err)
+	}
+	if refreshed.Title != "Renamed" {
+		t.Errorf("VerifyRefresh title = %q, want %q", refreshed.Title, "Renamed")
+	}
+
+	_, err = accountServer.DeleteRoleAssignment(ctx, &gen.DeleteRoleAssignmentRequest{Id: assignment.Id})
+	if err != nil {
+		t.Fatalf("failed to delete role assignment: %v", err)
+	}
+	if _, err := verifier.VerifyRefresh(ctx, data); !errors.Is(err, accesstoken.ErrNoRolesAssigned) {
+		t.Errorf("VerifyRefresh without roles error = %v, want %v", err, accesstoken.ErrNoRolesAssigned)
+	}
+
+	_, err = accountServer.DeleteAccount(ctx, &gen.DeleteAccountRequest{Id: user.Id})
+	if err != nil {
+		t.Fatalf("failed to delete account: %v", err)
+	}
+	if _, err := verifier.VerifyRefresh(ctx, data); !errors.Is(err, accesstoken.ErrInvalidCredentials) {
+		t.Errorf("VerifyRefresh after delete error = %v, want %v", err, accesstoken.ErrInvalidCredentials)
+	}
+}
+
 func TestLocalServiceVerifier_Verify(t *testing.T) {
 	ctx := context.Background()
 	logger, err := zap.NewDevelopment()
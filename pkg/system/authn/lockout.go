@@ -0,0 +1,102 @@
+package authn
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/smart-core-os/sc-bos/internal/account"
+	"github.com/smart-core-os/sc-bos/internal/auth/accesstoken"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/securityevent"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/system/authn/config"
+)
+
+// defaultMaxAddressFailures is the default for config.Lockout.MaxAddressFailures.
+const defaultMaxAddressFailures = 20
+
+// passwordPolicy converts cfg into a policy for the account store, loading any denylist file.
+func (s *System) passwordPolicy(cfg *config.PasswordPolicy) (account.PasswordPolicy, error) {
+	if cfg == nil {
+		return account.PasswordPolicy{}, nil
+	}
+	policy := account.PasswordPolicy{
+		MinLength:           cfg.MinLength,
+		MinCharacterClasses: cfg.MinCharacterClasses,
+		History:             cfg.History,
+	}
+	if cfg.DenylistFile != "" {
+		denylist, err := account.LoadDenylist(s.resolveConfigFile(cfg.DenylistFile))
+		if err != nil {
+			return account.PasswordPolicy{}, fmt.Errorf("passwordPolicy: %w", err)
+		}
+		policy.Denylist = denylist
+	}
+	return policy, nil
+}
+
+// resolveConfigFile returns the path of name in the first config dir it exists in.
+// Absolute paths, and names that aren't found, are returned unchanged.
+func (s *System) resolveConfigFile(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	for _, dir := range s.configDirs {
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return name
+}
+
+// lockoutPolicies returns the account lockout policy and address limiter described by cfg.
+// Both are nil if lockouts are disabled.
+func lockoutPolicies(cfg *config.Lockout) (*account.LockoutPolicy, *accesstoken.AddressLimiter) {
+	if cfg == nil {
+		cfg = &config.Lockout{}
+	}
+	if cfg.Disabled {
+		return nil, nil
+	}
+	defaults := account.DefaultLockoutPolicy
+	policy := &account.LockoutPolicy{
+		MaxFailures:   cfg.MaxFailures,
+		Duration:      cfg.Duration.Or(defaults.Duration),
+		MaxDuration:   cfg.MaxDuration.Or(defaults.MaxDuration),
+		FailureWindow: cfg.FailureWindow.Or(defaults.FailureWindow),
+	}
+	if policy.MaxFailures <= 0 {
+		policy.MaxFailures = defaults.MaxFailures
+	}
+	limiter := &accesstoken.AddressLimiter{
+		MaxFailures:   cfg.MaxAddressFailures,
+		Duration:      policy.Duration,
+		MaxDuration:   policy.MaxDuration,
+		FailureWindow: policy.FailureWindow,
+	}
+	if limiter.MaxFailures <= 0 {
+		limiter.MaxFailures = defaultMaxAddressFailures
+	}
+	return policy, limiter
+}
+
+// announceLoginEvents announces the SecurityEventApi for login events, if this hasn't already been done.
+func (s *System) announceLoginEvents() {
+	if s.undoEvents != nil {
+		return
+	}
+	s.undoEvents = s.announcer.Announce(s.announcer.Name(),
+		node.HasTrait(securityevent.TraitName, node.WithClients(gen.WrapSecurityEventApi(s.events))),
+	)
+}
+
+// unannounceLoginEvents undoes announceLoginEvents.
+func (s *System) unannounceLoginEvents() {
+	if s.undoEvents == nil {
+		return
+	}
+	s.undoEvents()
+	s.undoEvents = nil
+}
@@ -0,0 +1,46 @@
+package authn
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/internal/auth/session"
+	"github.com/smart-core-os/sc-bos/pkg/app/files"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+)
+
+// SessionDBPath is where login sessions are stored, relative to the data directory.
+const SessionDBPath = "authn/sessions.sqlite3"
+
+// openSessions opens the session store and announces the SessionApi, if this hasn't already been done.
+func (s *System) openSessions(ctx context.Context) (*session.Store, error) {
+	if s.sessions != nil {
+		return s.sessions, nil
+	}
+	store, err := session.Open(ctx, files.Path(s.dataDir, SessionDBPath),
+		session.WithLogger(s.logger.Named("sessions")),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.sessions = store
+	s.undoSessions = s.announcer.Announce(s.announcer.Name(),
+		node.HasServer[gen.SessionApiServer](gen.RegisterSessionApiServer, session.NewServer(store)),
+	)
+	return store, nil
+}
+
+// closeSessions undoes openSessions.
+func (s *System) closeSessions() {
+	if s.sessions == nil {
+		return
+	}
+	s.undoSessions()
+	if err := s.sessions.Close(); err != nil {
+		s.logger.Warn("failed to close session store", zap.Error(err))
+	}
+	s.sessions = nil
+	s.undoSessions = nil
+}
@@ -0,0 +1,290 @@
+package billing
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/devicespb"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/meter"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread"
+	"github.com/smart-core-os/sc-bos/pkg/system/billing/config"
+	"github.com/smart-core-os/sc-golang/pkg/resource"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+)
+
+// tenant is a tenant statements are generated for.
+type tenant struct {
+	ID    string
+	Title string
+	Zones []string
+}
+
+// inZones returns whether the device belongs to one of zones.
+// Devices belong to a zone if their name is, or is under, the zone name, or their location metadata names the zone.
+func (t tenant) inZones(device *gen.Device) bool {
+	locZone := device.GetMetadata().GetLocation().GetZone()
+	for _, zone := range t.Zones {
+		if device.Name == zone || strings.HasPrefix(device.Name, zone+"/") || locZone == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// deviceLister lists devices known to the controller.
+type deviceLister interface {
+	ListDevices(opts ...resource.ReadOption) []*gen.Device
+}
+
+// generator apportions meter consumption between tenants and prices it.
+type generator struct {
+	meterHistory     gen.MeterHistoryClient
+	occupancyHistory gen.OccupancySensorHistoryClient
+	devices          deviceLister
+	location         *time.Location // tariff rates apply in this time zone
+	now              func() time.Time
+}
+
+// generate returns a statement for each tenant covering the period [start, end).
+func (g *generator) generate(ctx context.Context, cfg config.Root, tenants []tenant, start, end time.Time) ([]*gen.Statement, error) {
+	shares, err := g.meterShares(ctx, cfg, tenants, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make(map[string]*gen.Statement, len(tenants))
+	for _, t := range tenants {
+		statements[t.ID] = &gen.Statement{
+			Id:          fmt.Sprintf("%s-%s", start.Format("2006-01"), t.ID),
+			TenantId:    t.ID,
+			TenantTitle: t.Title,
+			Period:      &timepb.Period{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)},
+			CreateTime:  timestamppb.New(g.now()),
+			Currency:    cmp.Or(cfg.Tariff.Currency, config.DefaultCurrency),
+			UsageUnit:   cmp.Or(cfg.UsageUnit, config.DefaultUsageUnit),
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(shares)) {
+		usage, err := g.meterUsage(ctx, name, start, end, cfg.Tariff.Rates)
+		if err != nil {
+			return nil, fmt.Errorf("meter %s: %w", name, err)
+		}
+		meterShares := shares[name]
+		for _, tenantID := range slices.Sorted(maps.Keys(meterShares.byTenant)) {
+			share := meterShares.byTenant[tenantID]
+			st := statements[tenantID]
+			for i, rate := range cfg.Tariff.Rates {
+				if usage[i] == 0 || share == 0 {
+					continue
+				}
+				line := &gen.Statement_Line{
+					MeterName: name,
+					Shared:    meterShares.shared,
+					Share:     share,
+					Rate:      rate.Name,
+					Usage:     usage[i] * share,
+					UnitPrice: rate.UnitPrice,
+				}
+				line.Cost = roundCost(line.Usage * line.UnitPrice)
+				st.Lines = append(st.Lines, line)
+				st.TotalUsage += line.Usage
+				st.TotalCost += line.Cost
+			}
+		}
+	}
+
+	if cfg.Tariff.StandingCharge != 0 {
+		days := math.Round(end.Sub(start).Hours() / 24)
+		for _, st := range statements {
+			charge := &gen.Statement_FixedCharge{
+				Title: fmt.Sprintf("Standing charge, %v days", days),
+				Cost:  roundCost(days * cfg.Tariff.StandingCharge),
+			}
+			st.FixedCharges = append(st.FixedCharges, charge)
+			st.TotalCost += charge.Cost
+		}
+	}
+
+	res := make([]*gen.Statement, 0, len(tenants))
+	for _, t := range tenants {
+		st := statements[t.ID]
+		st.TotalCost = roundCost(st.TotalCost)
+		res = append(res, st)
+	}
+	return res, nil
+}
+
+// meterShare records how the consumption of a meter is split between tenants.
+type meterShare struct {
+	shared   bool
+	byTenant map[string]float64 // tenant id to fraction, summing to 1
+}
+
+// meterShares works out which tenants each meter should be billed to, and in what proportion.
+func (g *generator) meterShares(ctx context.Context, cfg config.Root, tenants []tenant, start, end time.Time) (map[string]meterShare, error) {
+	res := make(map[string]meterShare)
+	sharedMeters := make(map[string]config.SharedMeter, len(cfg.SharedMeters))
+	for _, m := range cfg.SharedMeters {
+		sharedMeters[m.Name] = m
+	}
+
+	meters := g.devices.ListDevices(resource.WithInclude(func(_ string, item proto.Message) bool {
+		device, ok := item.(*gen.Device)
+		return ok && devicespb.HasTrait(device, meter.TraitName)
+	}))
+	for _, device := range meters {
+		if _, ok := sharedMeters[device.Name]; ok {
+			continue
+		}
+		var owners []string
+		for _, t := range tenants {
+			if t.inZones(device) {
+				owners = append(owners, t.ID)
+			}
+		}
+		if len(owners) == 0 {
+			continue
+		}
+		res[device.Name] = meterShare{shared: len(owners) > 1, byTenant: equalShares(owners)}
+	}
+
+	var occupancy map[string]time.Duration // lazily computed
+	for _, m := range cfg.SharedMeters {
+		sharers := tenants
+		if len(m.Tenants) > 0 {
+			sharers = slices.DeleteFunc(slices.Clone(tenants), func(t tenant) bool {
+				return !slices.Contains(m.Tenants, t.ID)
+			})
+		}
+		if len(sharers) == 0 {
+			continue
+		}
+		weights := make(map[string]float64, len(sharers))
+		switch m.Split {
+		case config.SplitEqual:
+		case config.SplitOccupancy:
+			if occupancy == nil {
+				var err error
+				occupancy, err = g.tenantOccupancy(ctx, tenants, start, end)
+				if err != nil {
+					return nil, err
+				}
+			}
+			for _, t := range sharers {
+				weights[t.ID] = occupancy[t.ID].Hours()
+			}
+		default: // area
+			for _, t := range sharers {
+				for _, zone := range t.Zones {
+					weights[t.ID] += cfg.ZoneAreas[zone]
+				}
+			}
+		}
+		res[m.Name] = meterShare{shared: true, byTenant: weightedShares(sharers, weights)}
+	}
+	return res, nil
+}
+
+func equalShares(ids []string) map[string]float64 {
+	res := make(map[string]float64, len(ids))
+	for _, id := range ids {
+		res[id] = 1 / float64(len(ids))
+	}
+	return res
+}
+
+// weightedShares splits between tenants in proportion to weights, or equally if there are no weights.
+func weightedShares(tenants []tenant, weights map[string]float64) map[string]float64 {
+	var total float64
+	for _, t := range tenants {
+		total += weights[t.ID]
+	}
+	ids := make([]string, len(tenants))
+	for i, t := range tenants {
+		ids[i] = t.ID
+	}
+	if total <= 0 {
+		return equalShares(ids)
+	}
+	res := make(map[string]float64, len(ids))
+	for _, id := range ids {
+		res[id] = weights[id] / total
+	}
+	return res
+}
+
+// tenantOccupancy returns how long the occupancy sensors in each tenant's zones were occupied, in total.
+func (g *generator) tenantOccupancy(ctx context.Context, tenants []tenant, start, end time.Time) (map[string]time.Duration, error) {
+	sensors := g.devices.ListDevices(resource.WithInclude(func(_ string, item proto.Message) bool {
+		device, ok := item.(*gen.Device)
+		return ok && devicespb.HasTrait(device, trait.OccupancySensor)
+	}))
+	res := make(map[string]time.Duration, len(tenants))
+	for _, sensor := range sensors {
+		var occupied *time.Duration
+		for _, t := range tenants {
+			if !t.inZones(sensor) {
+				continue
+			}
+			if occupied == nil {
+				segments, err := historyread.OccupancySegments(ctx, g.occupancyHistory, sensor.Name, start, end)
+				if err != nil {
+					return nil, fmt.Errorf("occupancy sensor %s: %w", sensor.Name, err)
+				}
+				d := historyread.Occupied(segments)
+				occupied = &d
+			}
+			res[t.ID] += *occupied
+		}
+	}
+	return res, nil
+}
+
+// meterUsage returns the consumption of the named meter between start and end, indexed by the rate it applies to.
+// Consumption between two readings is assumed to be even over time,
+// readings either side of the period are used to include consumption up to its edges.
+func (g *generator) meterUsage(ctx context.Context, name string, start, end time.Time, rates []config.Rate) ([]float64, error) {
+	records, err := historyread.MeterReadings(ctx, g.meterHistory, name, start, end)
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]float64, len(rates))
+	for _, u := range historyread.MeterUsages(records) {
+		// only count the part of the interval within the period
+		u, ok := u.Clip(start, end)
+		if !ok {
+			continue
+		}
+		mid := u.Start.Add(u.End.Sub(u.Start) / 2).In(g.location)
+		usage[rateAt(rates, mid)] += u.Usage
+	}
+	return usage, nil
+}
+
+// rateAt returns the index of the first rate that applies at t.
+// Valid config guarantees the last rate applies at all times.
+func rateAt(rates []config.Rate, t time.Time) int {
+	for i, r := range rates {
+		if r.Applies(t) {
+			return i
+		}
+	}
+	return len(rates) - 1
+}
+
+// roundCost rounds c to the nearest hundredth, the minor unit of most currencies.
+func roundCost(c float64) float64 {
+	return math.Round(c*100) / 100
+}
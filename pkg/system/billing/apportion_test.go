@@ -0,0 +1,146 @@
+package billing
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/meter"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread/historyreadtest"
+	"github.com/smart-core-os/sc-bos/pkg/system/billing/config"
+	"github.com/smart-core-os/sc-golang/pkg/resource"
+)
+
+func TestGenerator_generate(t *testing.T) {
+	start := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return time.Date(2024, 2, day, hour, 0, 0, 0, time.UTC) }
+
+	history := historyreadtest.MeterHistory{
+		"floor1/a/meter": {
+			historyreadtest.Reading(at(0, 23), 0),    // before the period, half of the next interval is in the period
+			historyreadtest.Reading(at(1, 1), 10),    // 5 at night
+			historyreadtest.Reading(at(1, 2), 12),    // 2 at night
+			historyreadtest.Reading(at(1, 9), 12),    // nothing used
+			historyreadtest.Reading(at(1, 10), 20),   // 8 during the day
+			historyreadtest.Reading(at(29, 23), 100), // 80, the middle of the interval is during the day
+			historyreadtest.Reading(at(30, 1), 110),  // after the period, 5 at night is in the period
+		},
+		"building/meter": {
+			historyreadtest.Reading(start, 0),
+			historyreadtest.Reading(at(1, 2), 40), // 40 at night
+			historyreadtest.Reading(end, 40),
+		},
+	}
+	devices := fakeDevices{
+		{Name: "floor1/a/meter", Metadata: &traits.Metadata{Traits: []*traits.TraitMetadata{{Name: meter.TraitName.String()}}}},
+		{Name: "plant/meter", Metadata: &traits.Metadata{Traits: []*traits.TraitMetadata{{Name: meter.TraitName.String()}}}},
+	}
+	now := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+	g := &generator{
+		meterHistory: history,
+		devices:      devices,
+		location:     time.UTC,
+		now:          func() time.Time { return now },
+	}
+	cfg := config.Root{
+		SharedMeters: []config.SharedMeter{{Name: "building/meter", Split: config.SplitArea}},
+		ZoneAreas:    map[string]float64{"floor1/a": 100, "floor1/b": 300},
+		Tariff: config.Tariff{
+			Rates: []config.Rate{
+				{Name: "day", UnitPrice: 0.3, Start: "07:00", End: "23:00"},
+				{Name: "night", UnitPrice: 0.1},
+			},
+			StandingCharge: 1,
+		},
+	}
+	tenants := []tenant{
+		{ID: "a", Title: "Tenant A", Zones: []string{"floor1/a"}},
+		{ID: "b", Title: "Tenant B", Zones: []string{"floor1/b"}},
+	}
+
+	got, err := g.generate(context.Background(), cfg, tenants, start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := func(id, title string) *gen.Statement {
+		return &gen.Statement{
+			Id:           "2024-02-" + id,
+			TenantId:     id,
+			TenantTitle:  title,
+			Period:       &timepb.Period{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)},
+			CreateTime:   timestamppb.New(now),
+			Currency:     "GBP",
+			UsageUnit:    "kWh",
+			FixedCharges: []*gen.Statement_FixedCharge{{Title: "Standing charge, 29 days", Cost: 29}},
+		}
+	}
+	wantA := base("a", "Tenant A")
+	wantA.Lines = []*gen.Statement_Line{
+		{MeterName: "building/meter", Shared: true, Share: 0.25, Rate: "night", Usage: 10, UnitPrice: 0.1, Cost: 1},
+		{MeterName: "floor1/a/meter", Share: 1, Rate: "day", Usage: 88, UnitPrice: 0.3, Cost: 26.4},
+		{MeterName: "floor1/a/meter", Share: 1, Rate: "night", Usage: 12, UnitPrice: 0.1, Cost: 1.2},
+	}
+	wantA.TotalUsage = 110
+	wantA.TotalCost = 57.6
+	wantB := base("b", "Tenant B")
+	wantB.Lines = []*gen.Statement_Line{
+		{MeterName: "building/meter", Shared: true, Share: 0.75, Rate: "night", Usage: 30, UnitPrice: 0.1, Cost: 3},
+	}
+	wantB.TotalUsage = 30
+	wantB.TotalCost = 32
+
+	approx := protocmp.FilterField(&gen.Statement_Line{}, "usage", cmp.Comparer(func(a, b float64) bool {
+		return math.Abs(a-b) < 1e-4
+	}))
+	approxTotal := protocmp.FilterField(&gen.Statement{}, "total_usage", cmp.Comparer(func(a, b float64) bool {
+		return math.Abs(a-b) < 1e-4
+	}))
+	if diff := cmp.Diff([]*gen.Statement{wantA, wantB}, got, protocmp.Transform(), approx, approxTotal); diff != "" {
+		t.Fatalf("statements (-want,+got)\n%s", diff)
+	}
+}
+
+func TestRate_Applies(t *testing.T) {
+	overnight := config.Rate{Name: "night", Start: "23:00", End: "07:00"}
+	weekend := config.Rate{Name: "weekend", Days: []string{"sat", "sun"}}
+	tests := []struct {
+		rate config.Rate
+		at   time.Time
+		want bool
+	}{
+		{overnight, time.Date(2024, 2, 1, 23, 30, 0, 0, time.UTC), true},
+		{overnight, time.Date(2024, 2, 1, 6, 59, 0, 0, time.UTC), true},
+		{overnight, time.Date(2024, 2, 1, 7, 0, 0, 0, time.UTC), false},
+		{weekend, time.Date(2024, 2, 3, 12, 0, 0, 0, time.UTC), true}, // Saturday
+		{weekend, time.Date(2024, 2, 5, 12, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := tt.rate.Applies(tt.at); got != tt.want {
+			t.Errorf("%s.Applies(%v) got %v, want %v", tt.rate.Name, tt.at, got, tt.want)
+		}
+	}
+}
+
+type fakeDevices []*gen.Device
+
+func (f fakeDevices) ListDevices(opts ...resource.ReadOption) []*gen.Device {
+	readReq := resource.ComputeReadConfig(opts...)
+	var res []*gen.Device
+	for _, d := range f {
+		if !readReq.Exclude(d.Name, d) {
+			res = append(res, proto.Clone(d).(*gen.Device))
+		}
+	}
+	return res
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/system"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	DefaultCurrency  = "GBP"
+	DefaultUsageUnit = "kWh"
+)
+
+var DefaultSchedule = jsontypes.MustParseSchedule("0 3 1 * *") // 3am on the first of each month
+
+type Root struct {
+	system.Config
+	// Schedule is when statements for the previous calendar month are generated.
+	// Defaults to 3am on the first of each month.
+	Schedule *jsontypes.Schedule `json:"schedule,omitempty"`
+	// Tenants limits statements to these tenant ids, defaults to all tenants known to the TenantApi.
+	Tenants []string `json:"tenants,omitempty"`
+	// SharedMeters are meters whose consumption is split between tenants.
+	// Meters in more than one tenant's zones that aren't listed here are split equally between those tenants.
+	SharedMeters []SharedMeter `json:"sharedMeters,omitempty"`
+	// ZoneAreas records the floor area of each zone, used when splitting shared meters by area.
+	ZoneAreas map[string]float64 `json:"zoneAreas,omitempty"`
+	Tariff    Tariff             `json:"tariff,omitempty"`
+	// UsageUnit is the unit meter readings are recorded in, defaults to kWh.
+	UsageUnit string `json:"usageUnit,omitempty"`
+}
+
+type Split string
+
+const (
+	// SplitEqual splits consumption equally between tenants.
+	SplitEqual Split = "equal"
+	// SplitArea splits consumption by the floor area of each tenant's zones.
+	SplitArea Split = "area"
+	// SplitOccupancy splits consumption by how long the occupancy sensors in each tenant's zones were occupied.
+	SplitOccupancy Split = "occupancy"
+)
+
+type SharedMeter struct {
+	Name string `json:"name,omitempty"`
+	// Split is how consumption is apportioned, defaults to area.
+	Split Split `json:"split,omitempty"`
+	// Tenants are the ids of tenants sharing the meter, defaults to all tenants.
+	Tenants []string `json:"tenants,omitempty"`
+}
+
+type Tariff struct {
+	// Currency is the ISO 4217 code costs are in, defaults to GBP.
+	Currency string `json:"currency,omitempty"`
+	// Rates are checked in order, the first rate that applies at the time of consumption is used.
+	// The last rate must apply at all times.
+	Rates []Rate `json:"rates,omitempty"`
+	// StandingCharge is a fixed cost per day added to each statement.
+	StandingCharge float64 `json:"standingCharge,omitempty"`
+}
+
+type Rate struct {
+	Name      string  `json:"name,omitempty"`
+	UnitPrice float64 `json:"unitPrice,omitempty"`
+	// Days the rate applies, like "mon", "tue". Defaults to every day.
+	Days []string `json:"days,omitempty"`
+	// Start and End are local times of day in the form 15:04 the rate applies between.
+	// If End is before Start the rate applies overnight. Defaults to all day.
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// AllTimes returns whether the rate applies at all times.
+func (r Rate) AllTimes() bool {
+	return len(r.Days) == 0 && r.Start == "" && r.End == ""
+}
+
+// Applies returns whether the rate applies at t.
+// The rate must be valid.
+func (r Rate) Applies(t time.Time) bool {
+	if len(r.Days) > 0 {
+		var ok bool
+		for _, d := range r.Days {
+			if weekdays[d] == t.Weekday() {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if r.Start == "" && r.End == "" {
+		return true
+	}
+	start, _ := parseTimeOfDay(r.Start)
+	end, _ := parseTimeOfDay(r.End)
+	if r.End == "" {
+		end = 24 * time.Hour
+	}
+	y, m, d := t.Date()
+	sinceMidnight := t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+	if start <= end {
+		return sinceMidnight >= start && sinceMidnight < end
+	}
+	return sinceMidnight >= start || sinceMidnight < end
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Validate returns an error if the config isn't valid.
+func (r Root) Validate() error {
+	if len(r.Tariff.Rates) == 0 {
+		return errors.New("tariff.rates is required")
+	}
+	for i, rate := range r.Tariff.Rates {
+		if rate.Name == "" {
+			return fmt.Errorf("tariff.rates[%d].name is required", i)
+		}
+		for _, d := range rate.Days {
+			if _, ok := weekdays[d]; !ok {
+				return fmt.Errorf("tariff.rates[%d].days %q is not a day", i, d)
+			}
+		}
+		if _, err := parseTimeOfDay(rate.Start); err != nil {
+			return fmt.Errorf("tariff.rates[%d].start: %w", i, err)
+		}
+		if _, err := parseTimeOfDay(rate.End); err != nil {
+			return fmt.Errorf("tariff.rates[%d].end: %w", i, err)
+		}
+	}
+	if last := r.Tariff.Rates[len(r.Tariff.Rates)-1]; !last.AllTimes() {
+		return fmt.Errorf("tariff.rates[%d] must apply at all times", len(r.Tariff.Rates)-1)
+	}
+	for i, m := range r.SharedMeters {
+		if m.Name == "" {
+			return fmt.Errorf("sharedMeters[%d].name is required", i)
+		}
+		switch m.Split {
+		case "", SplitEqual, SplitArea, SplitOccupancy:
+		default:
+			return fmt.Errorf("sharedMeters[%d].split %q is not supported", i, m.Split)
+		}
+	}
+	return nil
+}
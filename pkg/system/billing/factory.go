@@ -0,0 +1,171 @@
+// Package billing provides a system that generates monthly energy statements for tenants.
+// Consumption of the meters in each tenant's zones, and a share of any shared meters, is priced using a tariff and
+// served via the TenantBillingApi, where tenants can read their own statements.
+package billing
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/system"
+	"github.com/smart-core-os/sc-bos/pkg/system/billing/config"
+	"github.com/smart-core-os/sc-bos/pkg/task"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+)
+
+// dbFile is the path of the database, relative to the data dir.
+const dbFile = "billing/billing.sqlite3"
+
+var Factory factory
+
+type factory struct{}
+
+func (_ factory) New(services system.Services) service.Lifecycle {
+	return NewSystem(services)
+}
+
+func NewSystem(services system.Services) *System {
+	logger := services.Logger.Named("billing")
+	s := &System{
+		node:    services.Node,
+		dataDir: services.DataDir,
+		logger:  logger,
+		now:     time.Now,
+	}
+	s.Service = service.New(
+		service.MonoApply(s.applyConfig),
+		service.WithRetry[config.Root](service.RetryWithLogger(func(logContext service.RetryContext) {
+			logContext.LogTo("applyConfig", logger)
+		})),
+	)
+	return s
+}
+
+type System struct {
+	*service.Service[config.Root]
+	node    *node.Node
+	dataDir string
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	store, err := openStore(ctx, filepath.Join(s.dataDir, dbFile), s.logger)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+
+	srv, err := node.RegistryService(gen.TenantBillingApi_ServiceDesc, &server{store: store})
+	if err != nil {
+		_ = store.Close()
+		return fmt.Errorf("can't create TenantBillingApi service: %w", err)
+	}
+	undo, err := s.node.AnnounceService(srv)
+	if err != nil {
+		_ = store.Close()
+		return fmt.Errorf("can't announce TenantBillingApi service: %w", err)
+	}
+
+	conn := s.node.ClientConn()
+	g := &generator{
+		meterHistory:     gen.NewMeterHistoryClient(conn),
+		occupancyHistory: gen.NewOccupancySensorHistoryClient(conn),
+		devices:          s.node,
+		location:         time.Local,
+		now:              s.now,
+	}
+	go s.run(ctx, g, store, cfg)
+	go func() {
+		<-ctx.Done()
+		undo()
+		_ = store.Close()
+	}()
+	return nil
+}
+
+// run generates statements each time the schedule fires, until ctx is done.
+// Statements for the last month are generated straight away if they are missing.
+func (s *System) run(ctx context.Context, g *generator, store *store, cfg config.Root) {
+	sched := cfg.Schedule
+	if sched == nil {
+		sched = config.DefaultSchedule
+	}
+	t := s.now()
+	start, _ := lastMonth(t)
+	if done, err := store.hasPeriod(ctx, start); err != nil {
+		s.logger.Warn("failed to check for existing statements", zap.Error(err))
+	} else if !done {
+		s.generate(ctx, g, store, cfg, t)
+	}
+
+	for {
+		next := sched.Next(t)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+			// Use the time we were planning on running instead of the current time.
+			// We do this to make the statement period predictable.
+			t = next
+		}
+		s.generate(ctx, g, store, cfg, t)
+	}
+}
+
+// generate saves statements for all tenants for the month before t, retrying on failure.
+func (s *System) generate(ctx context.Context, g *generator, store *store, cfg config.Root, t time.Time) {
+	start, end := lastMonth(t)
+	logger := s.logger.With(zap.Time("periodStart", start))
+	err := task.Run(ctx, func(ctx context.Context) (task.Next, error) {
+		tenants, err := s.listTenants(ctx, cfg)
+		if err != nil {
+			return task.Normal, fmt.Errorf("list tenants: %w", err)
+		}
+		statements, err := g.generate(ctx, cfg, tenants, start, end)
+		if err != nil {
+			return task.Normal, err
+		}
+		for _, st := range statements {
+			if err := store.saveStatement(ctx, st); err != nil {
+				return task.Normal, fmt.Errorf("save statement %s: %w", st.Id, err)
+			}
+		}
+		logger.Debug("generated statements", zap.Int("count", len(statements)))
+		return task.Normal, nil
+	}, task.WithRetry(24), task.WithBackoff(time.Minute, time.Hour), task.WithErrorLogger(logger))
+	if err != nil && ctx.Err() == nil {
+		logger.Error("failed to generate statements", zap.Error(err))
+	}
+}
+
+// listTenants returns the tenants statements should be generated for.
+func (s *System) listTenants(ctx context.Context, cfg config.Root) ([]tenant, error) {
+	res, err := gen.NewTenantApiClient(s.node.ClientConn()).ListTenants(ctx, &gen.ListTenantsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var tenants []tenant
+	for _, t := range res.Tenants {
+		if len(cfg.Tenants) > 0 && !slices.Contains(cfg.Tenants, t.Id) {
+			continue
+		}
+		tenants = append(tenants, tenant{ID: t.Id, Title: t.Title, Zones: t.ZoneNames})
+	}
+	return tenants, nil
+}
+
+// lastMonth returns the calendar month before the one containing t.
+func lastMonth(t time.Time) (start, end time.Time) {
+	end = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return end.AddDate(0, -1, 0), end
+}
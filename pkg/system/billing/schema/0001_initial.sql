@@ -0,0 +1,14 @@
+-- Statements generated for tenants, one per tenant per period.
+CREATE TABLE statements
+(
+    id           TEXT PRIMARY KEY,
+    tenant_id    TEXT    NOT NULL,
+    -- unix milliseconds
+    period_start INTEGER NOT NULL,
+    create_time  INTEGER NOT NULL,
+    -- A binary Statement proto message.
+    payload      BLOB    NOT NULL,
+    UNIQUE (tenant_id, period_start)
+);
+
+CREATE INDEX statements_period_start ON statements (period_start);
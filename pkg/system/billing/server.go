@@ -0,0 +1,68 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// server implements gen.TenantBillingApiServer backed by a store.
+type server struct {
+	gen.UnimplementedTenantBillingApiServer
+	store *store
+}
+
+func (s *server) ListStatements(ctx context.Context, request *gen.ListStatementsRequest) (*gen.ListStatementsResponse, error) {
+	var offset int
+	if request.PageToken != "" {
+		var err error
+		offset, err = strconv.Atoi(request.PageToken)
+		if err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+	}
+	pageSize := int(request.PageSize)
+	switch {
+	case pageSize <= 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	statements, total, err := s.store.listStatements(ctx, request.TenantId, offset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	res := &gen.ListStatementsResponse{Statements: statements, TotalSize: int32(total)}
+	if next := offset + len(statements); next < total {
+		res.NextPageToken = strconv.Itoa(next)
+	}
+	return res, nil
+}
+
+func (s *server) GetStatement(ctx context.Context, request *gen.GetStatementRequest) (*gen.Statement, error) {
+	if request.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	st, err := s.store.getStatement(ctx, request.Id)
+	if errors.Is(err, errStatementNotFound) {
+		return nil, status.Errorf(codes.NotFound, "statement %q", request.Id)
+	} else if err != nil {
+		return nil, err
+	}
+	// tenants are only authorised to request their own statements, don't leak the existence of others
+	if request.TenantId != "" && request.TenantId != st.TenantId {
+		return nil, status.Errorf(codes.NotFound, "statement %q", request.Id)
+	}
+	return st, nil
+}
@@ -0,0 +1,71 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	st, err := openMemoryStore(ctx, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	save := func(id, tenantID string, month time.Month) {
+		start := time.Date(2024, month, 1, 0, 0, 0, 0, time.UTC)
+		err := st.saveStatement(ctx, &gen.Statement{
+			Id:         id,
+			TenantId:   tenantID,
+			Period:     &timepb.Period{StartTime: timestamppb.New(start), EndTime: timestamppb.New(start.AddDate(0, 1, 0))},
+			CreateTime: timestamppb.New(start.AddDate(0, 1, 0)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("2024-01-a", "a", time.January)
+	save("2024-02-a", "a", time.February)
+	save("2024-02-b", "b", time.February)
+	save("2024-02-a", "a", time.February) // regenerated, replaces the existing statement
+
+	srv := &server{store: st}
+	var ids []string
+	req := &gen.ListStatementsRequest{TenantId: "a", PageSize: 1}
+	for {
+		res, err := srv.ListStatements(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.TotalSize != 2 {
+			t.Fatalf("total size got %d, want 2", res.TotalSize)
+		}
+		for _, s := range res.Statements {
+			ids = append(ids, s.Id)
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
+	}
+	if len(ids) != 2 || ids[0] != "2024-02-a" || ids[1] != "2024-01-a" {
+		t.Fatalf("list got %v, want [2024-02-a 2024-01-a]", ids)
+	}
+
+	if _, err := srv.GetStatement(ctx, &gen.GetStatementRequest{TenantId: "a", Id: "2024-02-a"}); err != nil {
+		t.Fatalf("get own statement: %v", err)
+	}
+	if _, err := srv.GetStatement(ctx, &gen.GetStatementRequest{TenantId: "a", Id: "2024-02-b"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("get other tenants statement: want NotFound, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package billing
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/smart-core-os/sc-bos/internal/sqlite"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const appID = 0x5C0507
+
+//go:embed schema/*.sql
+var schemaVersionsFS embed.FS
+var schema = sqlite.MustLoadVersionedSchema(schemaVersionsFS, "schema")
+
+func init() {
+	sqlite.RegisterSchema("billing", appID, schema)
+}
+
+var errStatementNotFound = errors.New("statement not found")
+
+// store records generated tenant statements.
+type store struct {
+	db *sqlite.Database
+}
+
+func openStore(ctx context.Context, path string, logger *zap.Logger) (*store, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("mkdir: %w", err)
+	}
+	db, err := sqlite.Open(ctx, path,
+		sqlite.WithApplicationID(appID),
+		sqlite.WithLogger(logger),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return newStore(ctx, db)
+}
+
+func openMemoryStore(ctx context.Context, logger *zap.Logger) (*store, error) {
+	db := sqlite.OpenMemory(
+		sqlite.WithApplicationID(appID),
+		sqlite.WithLogger(logger),
+	)
+	return newStore(ctx, db)
+}
+
+func newStore(ctx context.Context, db *sqlite.Database) (*store, error) {
+	err := db.Migrate(ctx, schema)
+	if err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	return &store{db: db}, nil
+}
+
+func (s *store) Close() error {
+	return s.db.Close()
+}
+
+// saveStatement records st, replacing any statement for the same tenant and period.
+func (s *store) saveStatement(ctx context.Context, st *gen.Statement) error {
+	payload, err := proto.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return s.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			"DELETE FROM statements WHERE tenant_id = ? AND period_start = ?;",
+			st.TenantId, st.Period.GetStartTime().AsTime().UnixMilli())
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO statements (id, tenant_id, period_start, create_time, payload) VALUES (?, ?, ?, ?, ?);",
+			st.Id, st.TenantId, st.Period.GetStartTime().AsTime().UnixMilli(), st.CreateTime.AsTime().UnixMilli(), payload)
+		return err
+	})
+}
+
+// getStatement returns the statement with the given id, or errStatementNotFound.
+func (s *store) getStatement(ctx context.Context, id string) (*gen.Statement, error) {
+	var payload []byte
+	err := s.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, "SELECT payload FROM statements WHERE id = ?;", id).Scan(&payload)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errStatementNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	st := &gen.Statement{}
+	if err := proto.Unmarshal(payload, st); err != nil {
+		return nil, fmt.Errorf("statement %s: %w", id, err)
+	}
+	return st, nil
+}
+
+// listStatements returns up to limit statements, newest period first, skipping the first offset.
+// If tenantID is not empty only statements for that tenant are returned.
+// The total number of matching statements is also returned.
+func (s *store) listStatements(ctx context.Context, tenantID string, offset, limit int) ([]*gen.Statement, int, error) {
+	var (
+		statements []*gen.Statement
+		total      int
+	)
+	err := s.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		where, args := "", []any{}
+		if tenantID != "" {
+			where, args = " WHERE tenant_id = ?", append(args, tenantID)
+		}
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM statements"+where+";", args...).Scan(&total); err != nil {
+			return err
+		}
+		rows, err := tx.QueryContext(ctx,
+			"SELECT id, payload FROM statements"+where+" ORDER BY period_start DESC, tenant_id LIMIT ? OFFSET ?;",
+			append(args, limit, offset)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				id      string
+				payload []byte
+			)
+			if err := rows.Scan(&id, &payload); err != nil {
+				return err
+			}
+			st := &gen.Statement{}
+			if err := proto.Unmarshal(payload, st); err != nil {
+				return fmt.Errorf("statement %s: %w", id, err)
+			}
+			statements = append(statements, st)
+		}
+		return rows.Err()
+	})
+	return statements, total, err
+}
+
+// hasPeriod returns whether any statements have been generated for the period starting at start.
+func (s *store) hasPeriod(ctx context.Context, start time.Time) (bool, error) {
+	var n int
+	err := s.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM statements WHERE period_start = ?;", start.UnixMilli()).Scan(&n)
+	})
+	return n > 0, err
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+const DefaultCurrency = "GBP"
+
+// Factors are the emission factors and tariffs used to convert consumption of each fuel.
+type Factors struct {
+	// Currency is the ISO 4217 code tariffs are in, defaults to GBP.
+	Currency string `json:"currency,omitempty"`
+	// Fuels are keyed by the name meters refer to them by, like "electricity" or "gas".
+	Fuels map[string]Fuel `json:"fuels,omitempty"`
+}
+
+type Fuel struct {
+	// Unit is the unit factors and tariffs are per, like kWh or m3.
+	// Meters that record energy in Wh, kWh, or MWh are converted to this unit if it is also one of these.
+	Unit string `json:"unit,omitempty"`
+	// EmissionFactors are in kgCO2e per Unit.
+	EmissionFactors []Schedule `json:"emissionFactors,omitempty"`
+	// Tariffs are in Currency per Unit.
+	Tariffs []Schedule `json:"tariffs,omitempty"`
+}
+
+// Schedule is a set of values that apply from a date, until the next schedule takes effect.
+type Schedule struct {
+	// EffectiveFrom is the local date, in the form 2006-01-02, the schedule applies from.
+	// If empty the schedule applies from the beginning of time.
+	EffectiveFrom string `json:"effectiveFrom,omitempty"`
+	// Bands are checked in order, the first band that applies at the time of consumption is used.
+	// The last band must apply at all times.
+	Bands []Band `json:"bands,omitempty"`
+}
+
+type Band struct {
+	Name  string  `json:"name,omitempty"`
+	Value float64 `json:"value,omitempty"`
+	// Days the band applies, like "mon", "tue". Defaults to every day.
+	Days []string `json:"days,omitempty"`
+	// Start and End are local times of day in the form 15:04 the band applies between.
+	// If End is before Start the band applies overnight. Defaults to all day.
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// ReadFactorsFile reads and validates Factors from the JSON file at path.
+func ReadFactorsFile(path string) (Factors, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Factors{}, err
+	}
+	var f Factors
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Factors{}, err
+	}
+	if err := f.Validate(); err != nil {
+		return Factors{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// ErrNoValue is returned when no schedule is effective at a time.
+var ErrNoValue = errors.New("no schedule is effective")
+
+// ValueAt returns the value of the band that applies at t, using the latest schedule effective at t.
+// Schedules must be valid.
+// If schedules is empty, returns 0.
+func ValueAt(schedules []Schedule, t time.Time) (float64, error) {
+	if len(schedules) == 0 {
+		return 0, nil
+	}
+	date := t.Format(time.DateOnly)
+	// schedules are sorted by EffectiveFrom, and ISO dates sort as strings
+	i, found := slices.BinarySearchFunc(schedules, date, func(s Schedule, d string) int {
+		switch {
+		case s.EffectiveFrom < d:
+			return -1
+		case s.EffectiveFrom > d:
+			return 1
+		}
+		return 0
+	})
+	if !found {
+		i--
+	}
+	if i < 0 {
+		return 0, fmt.Errorf("%w on %s", ErrNoValue, date)
+	}
+	for _, b := range schedules[i].Bands {
+		if b.Applies(t) {
+			return b.Value, nil
+		}
+	}
+	return schedules[i].Bands[len(schedules[i].Bands)-1].Value, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// AllTimes returns whether the band applies at all times.
+func (b Band) AllTimes() bool {
+	return len(b.Days) == 0 && b.Start == "" && b.End == ""
+}
+
+// Applies returns whether the band applies at t.
+// The band must be valid.
+func (b Band) Applies(t time.Time) bool {
+	if len(b.Days) > 0 && !slices.ContainsFunc(b.Days, func(d string) bool { return weekdays[d] == t.Weekday() }) {
+		return false
+	}
+	if b.Start == "" && b.End == "" {
+		return true
+	}
+	start, _ := parseTimeOfDay(b.Start)
+	end, _ := parseTimeOfDay(b.End)
+	if b.End == "" {
+		end = 24 * time.Hour
+	}
+	y, m, d := t.Date()
+	sinceMidnight := t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+	if start <= end {
+		return sinceMidnight >= start && sinceMidnight < end
+	}
+	return sinceMidnight >= start || sinceMidnight < end
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Validate returns an error if the factors aren't valid.
+func (f Factors) Validate() error {
+	if len(f.Fuels) == 0 {
+		return errors.New("fuels is required")
+	}
+	for name, fuel := range f.Fuels {
+		if fuel.Unit == "" {
+			return fmt.Errorf("fuels.%s.unit is required", name)
+		}
+		if err := validateSchedules(fuel.EmissionFactors); err != nil {
+			return fmt.Errorf("fuels.%s.emissionFactors%w", name, err)
+		}
+		if err := validateSchedules(fuel.Tariffs); err != nil {
+			return fmt.Errorf("fuels.%s.tariffs%w", name, err)
+		}
+	}
+	return nil
+}
+
+func validateSchedules(schedules []Schedule) error {
+	for i, s := range schedules {
+		if s.EffectiveFrom != "" {
+			if _, err := time.Parse(time.DateOnly, s.EffectiveFrom); err != nil {
+				return fmt.Errorf("[%d].effectiveFrom: %w", i, err)
+			}
+		}
+		if i > 0 && s.EffectiveFrom <= schedules[i-1].EffectiveFrom {
+			return fmt.Errorf("[%d].effectiveFrom must be after the previous schedule", i)
+		}
+		if len(s.Bands) == 0 {
+			return fmt.Errorf("[%d].bands is required", i)
+		}
+		for j, b := range s.Bands {
+			for _, d := range b.Days {
+				if _, ok := weekdays[d]; !ok {
+					return fmt.Errorf("[%d].bands[%d].days %q is not a day", i, j, d)
+				}
+			}
+			if _, err := parseTimeOfDay(b.Start); err != nil {
+				return fmt.Errorf("[%d].bands[%d].start: %w", i, j, err)
+			}
+			if _, err := parseTimeOfDay(b.End); err != nil {
+				return fmt.Errorf("[%d].bands[%d].end: %w", i, j, err)
+			}
+		}
+		if last := s.Bands[len(s.Bands)-1]; !last.AllTimes() {
+			return fmt.Errorf("[%d].bands[%d] must apply at all times", i, len(s.Bands)-1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+
+	"github.com/smart-core-os/sc-bos/pkg/system"
+)
+
+const DefaultFuel = "electricity"
+
+type Root struct {
+	system.Config
+	// FactorsFile is the path of a JSON file containing Factors.
+	// Relative paths are resolved against the config directories.
+	// The file is read again when it changes, so factors can be updated without restarting the system.
+	FactorsFile string `json:"factorsFile,omitempty"`
+	// Factors are used if FactorsFile is not set.
+	Factors *Factors `json:"factors,omitempty"`
+	// DefaultFuel is the fuel of meters not listed in Meters, defaults to electricity.
+	DefaultFuel string `json:"defaultFuel,omitempty"`
+	// Meters maps meter names to the fuel they measure.
+	Meters map[string]string `json:"meters,omitempty"`
+}
+
+// Fuel returns the fuel the named meter measures.
+func (r Root) Fuel(meter string) string {
+	if f, ok := r.Meters[meter]; ok {
+		return f
+	}
+	return cmp.Or(r.DefaultFuel, DefaultFuel)
+}
+
+// Validate returns an error if the config isn't valid.
+func (r Root) Validate() error {
+	switch {
+	case r.FactorsFile == "" && r.Factors == nil:
+		return errors.New("factorsFile or factors is required")
+	case r.FactorsFile != "" && r.Factors != nil:
+		return errors.New("only one of factorsFile and factors can be set")
+	case r.Factors != nil:
+		return r.Factors.Validate()
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package conversion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread"
+	"github.com/smart-core-os/sc-bos/pkg/system/conversion/config"
+	"github.com/smart-core-os/sc-bos/pkg/util/timeutil"
+)
+
+// maxSpan is the longest time consumption is converted using a single factor.
+// Longer intervals between readings are split so time of day bands are applied fairly.
+const maxSpan = 30 * time.Minute
+
+// energyUnits are units that can be converted between, as multiples of Wh.
+var energyUnits = map[string]float64{
+	"wh":  1,
+	"kwh": 1e3,
+	"mwh": 1e6,
+}
+
+// unitScale returns what usage in unit from must be multiplied by to be in unit to.
+// An empty from is assumed to be the same as to.
+func unitScale(from, to string) (float64, error) {
+	if from == "" || from == to {
+		return 1, nil
+	}
+	f, fok := energyUnits[strings.ToLower(from)]
+	t, tok := energyUnits[strings.ToLower(to)]
+	if !fok || !tok {
+		return 0, fmt.Errorf("can't convert %s to %s", from, to)
+	}
+	return f / t, nil
+}
+
+// converter converts meter history into carbon and cost.
+type converter struct {
+	meterHistory gen.MeterHistoryClient
+	meterInfo    gen.MeterInfoClient
+	location     *time.Location // bands and effective dates apply in this time zone
+}
+
+// convert returns the consumption of the named meter for each interval between start and end, with its carbon and cost.
+// The last interval is shortened to end at end.
+// Consumption between two readings is assumed to be even over time,
+// readings either side of the period are used to include consumption up to its edges.
+func (c *converter) convert(ctx context.Context, name string, fuel config.Fuel, start, end time.Time, interval time.Duration) ([]*gen.ConvertedUsage, error) {
+	support, err := c.meterInfo.DescribeMeterReading(ctx, &gen.DescribeMeterReadingRequest{Name: name})
+	if err != nil && status.Code(err) != codes.Unimplemented {
+		return nil, err
+	}
+	scale, err := unitScale(support.GetUsageUnit(), fuel.Unit)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "meter %s: %v", name, err)
+	}
+	records, err := historyread.MeterReadings(ctx, c.meterHistory, name, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*gen.ConvertedUsage
+	for t := start; t.Before(end); t = t.Add(interval) {
+		res = append(res, &gen.ConvertedUsage{
+			StartTime: timestamppb.New(t),
+			EndTime:   timestamppb.New(timeutil.Earliest(t.Add(interval), end)),
+		})
+	}
+	for _, u := range historyread.MeterUsages(records) {
+		// only count the part of the interval within the period, split into spans within a single bucket
+		u, ok := u.Clip(start, end)
+		if !ok {
+			continue
+		}
+		for spanFrom := u.Start; spanFrom.Before(u.End); {
+			bucket := int(spanFrom.Sub(start) / interval)
+			spanTo := timeutil.Earliest(timeutil.Earliest(u.End, start.Add(time.Duration(bucket+1)*interval)), spanFrom.Add(maxSpan))
+			usage := scale * u.Usage * float64(spanTo.Sub(spanFrom)) / float64(u.End.Sub(u.Start))
+			mid := spanFrom.Add(spanTo.Sub(spanFrom) / 2).In(c.location)
+			emissionFactor, err := config.ValueAt(fuel.EmissionFactors, mid)
+			if err != nil {
+				return nil, status.Errorf(codes.FailedPrecondition, "emission factors: %v", err)
+			}
+			unitPrice, err := config.ValueAt(fuel.Tariffs, mid)
+			if err != nil {
+				return nil, status.Errorf(codes.FailedPrecondition, "tariffs: %v", err)
+			}
+			r := res[bucket]
+			r.Usage += usage
+			r.Carbon += usage * emissionFactor
+			r.Cost += usage * unitPrice
+			spanFrom = spanTo
+		}
+	}
+	return res, nil
+}
+
+// sum returns the total of all readings.
+func sum(readings []*gen.ConvertedUsage) *gen.ConvertedUsage {
+	total := &gen.ConvertedUsage{}
+	if len(readings) == 0 {
+		return total
+	}
+	total.StartTime = readings[0].StartTime
+	total.EndTime = readings[len(readings)-1].EndTime
+	for _, r := range readings {
+		total.Usage += r.Usage
+		total.Carbon += r.Carbon
+		total.Cost += r.Cost
+	}
+	return total
+}
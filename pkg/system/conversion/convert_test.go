@@ -0,0 +1,154 @@
+package conversion
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread/historyreadtest"
+	"github.com/smart-core-os/sc-bos/pkg/system/conversion/config"
+)
+
+func TestServer_ListConvertedMeterReadings(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2024, 2, day, hour, 0, 0, 0, time.UTC) }
+	factors := config.Factors{
+		Fuels: map[string]config.Fuel{
+			"electricity": {
+				Unit: "kWh",
+				EmissionFactors: []config.Schedule{
+					{Bands: []config.Band{{Value: 0.2}}},
+					{EffectiveFrom: "2024-02-02", Bands: []config.Band{{Value: 0.1}}},
+				},
+				Tariffs: []config.Schedule{
+					{Bands: []config.Band{
+						{Name: "day", Value: 0.3, Start: "07:00", End: "23:00"},
+						{Name: "night", Value: 0.1},
+					}},
+				},
+			},
+			"gas": {Unit: "m3"},
+		},
+	}
+	if err := factors.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	srv := &server{
+		cfg:     config.Root{Meters: map[string]string{"gas": "gas"}},
+		factors: func() (config.Factors, error) { return factors, nil },
+		conv: &converter{
+			meterHistory: historyreadtest.MeterHistory{
+				"meter": {
+					historyreadtest.Reading(at(1, 0), 0),
+					historyreadtest.Reading(at(1, 12), 12000),  // 1 kWh an hour
+					historyreadtest.Reading(at(2, 12), 36000),  // 1 kWh an hour
+					historyreadtest.Reading(at(3, 12), 100000), // after the period
+				},
+				"gas": {historyreadtest.Reading(at(1, 0), 0), historyreadtest.Reading(at(2, 0), 10)},
+			},
+			meterInfo: fakeMeterInfo{"meter": "Wh", "gas": "kWh"},
+			location:  time.UTC,
+		},
+		now: func() time.Time { return at(2, 12) },
+	}
+
+	res, err := srv.ListConvertedMeterReadings(context.Background(), &gen.ListConvertedMeterReadingsRequest{
+		MeterName: "meter",
+		Period:    &timepb.Period{StartTime: timestamppb.New(at(1, 0))},
+		Interval:  durationpb.New(24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Fuel != "electricity" || res.UsageUnit != "kWh" || res.Currency != "GBP" {
+		t.Errorf("got fuel %q, unit %q, currency %q", res.Fuel, res.UsageUnit, res.Currency)
+	}
+	want := []struct {
+		start, end          time.Time
+		usage, carbon, cost float64
+	}{
+		// 8 hours of night and 16 of day
+		{at(1, 0), at(2, 0), 24, 4.8, 0.8 + 4.8},
+		// the new emission factor applies, 7 hours of night and 5 of day
+		{at(2, 0), at(2, 12), 12, 1.2, 0.7 + 1.5},
+	}
+	if len(res.Readings) != len(want) {
+		t.Fatalf("got %d readings, want %d", len(res.Readings), len(want))
+	}
+	for i, w := range want {
+		r := res.Readings[i]
+		if !r.StartTime.AsTime().Equal(w.start) || !r.EndTime.AsTime().Equal(w.end) {
+			t.Errorf("[%d] got %v-%v, want %v-%v", i, r.StartTime.AsTime(), r.EndTime.AsTime(), w.start, w.end)
+		}
+		if !near(r.Usage, w.usage) || !near(r.Carbon, w.carbon) || !near(r.Cost, w.cost) {
+			t.Errorf("[%d] got usage %v, carbon %v, cost %v, want %v, %v, %v", i, r.Usage, r.Carbon, r.Cost, w.usage, w.carbon, w.cost)
+		}
+	}
+	if !near(res.Total.Usage, 36) || !near(res.Total.Carbon, 6) || !near(res.Total.Cost, 7.8) {
+		t.Errorf("got total %v", res.Total)
+	}
+
+	// the gas meter records kWh, which can't be converted to m3
+	_, err = srv.ListConvertedMeterReadings(context.Background(), &gen.ListConvertedMeterReadingsRequest{
+		MeterName: "gas",
+		Period:    &timepb.Period{StartTime: timestamppb.New(at(1, 0))},
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("gas meter in kWh: want FailedPrecondition, got %v", err)
+	}
+	_, err = srv.ListConvertedMeterReadings(context.Background(), &gen.ListConvertedMeterReadingsRequest{
+		MeterName: "meter",
+		Period:    &timepb.Period{StartTime: timestamppb.New(at(1, 0))},
+		Interval:  durationpb.New(time.Second),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("too many readings: want InvalidArgument, got %v", err)
+	}
+}
+
+func TestValueAt(t *testing.T) {
+	schedules := []config.Schedule{
+		{EffectiveFrom: "2024-01-01", Bands: []config.Band{{Value: 1}}},
+		{EffectiveFrom: "2024-04-01", Bands: []config.Band{{Value: 2, Days: []string{"sat", "sun"}}, {Value: 3}}},
+	}
+	tests := []struct {
+		at      time.Time
+		want    float64
+		wantErr bool
+	}{
+		{at: time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC), wantErr: true},
+		{at: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), want: 1},
+		{at: time.Date(2024, 3, 31, 23, 0, 0, 0, time.UTC), want: 1},
+		{at: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), want: 3},  // Monday
+		{at: time.Date(2024, 4, 6, 12, 0, 0, 0, time.UTC), want: 2}, // Saturday
+	}
+	for _, tt := range tests {
+		got, err := config.ValueAt(schedules, tt.at)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValueAt(%v) err %v, wantErr %v", tt.at, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ValueAt(%v) got %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+// fakeMeterInfo returns the usage unit of meters by name.
+type fakeMeterInfo map[string]string
+
+func (f fakeMeterInfo) DescribeMeterReading(_ context.Context, in *gen.DescribeMeterReadingRequest, _ ...grpc.CallOption) (*gen.MeterReadingSupport, error) {
+	return &gen.MeterReadingSupport{UsageUnit: f[in.Name]}, nil
+}
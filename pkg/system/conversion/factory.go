@@ -0,0 +1,108 @@
+// Package conversion provides a system that converts meter consumption into carbon emissions and cost.
+// Emission factors and tariffs are configured per fuel and can vary by time of day, and over time using effective
+// dates. Converted meter history is served via the MeterConversionApi.
+package conversion
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/system"
+	"github.com/smart-core-os/sc-bos/pkg/system/conversion/config"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+)
+
+var Factory factory
+
+type factory struct{}
+
+func (_ factory) New(services system.Services) service.Lifecycle {
+	return NewSystem(services)
+}
+
+func NewSystem(services system.Services) *System {
+	logger := services.Logger.Named("conversion")
+	s := &System{
+		node:       services.Node,
+		configDirs: services.ConfigDirs,
+		logger:     logger,
+	}
+	s.Service = service.New(
+		service.MonoApply(s.applyConfig),
+		service.WithRetry[config.Root](service.RetryWithLogger(func(logContext service.RetryContext) {
+			logContext.LogTo("applyConfig", logger)
+		})),
+	)
+	return s
+}
+
+type System struct {
+	*service.Service[config.Root]
+	node       *node.Node
+	configDirs []string
+	logger     *zap.Logger
+}
+
+func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	var factors func() (config.Factors, error)
+	if cfg.FactorsFile != "" {
+		ff := &fileFactors{path: s.resolveConfigFile(cfg.FactorsFile), logger: s.logger}
+		// fail early if the file is missing or invalid
+		if _, err := ff.load(); err != nil {
+			return fmt.Errorf("factorsFile: %w", err)
+		}
+		factors = ff.load
+	} else {
+		f := *cfg.Factors
+		factors = func() (config.Factors, error) { return f, nil }
+	}
+
+	conn := s.node.ClientConn()
+	srv, err := node.RegistryService(gen.MeterConversionApi_ServiceDesc, &server{
+		cfg:     cfg,
+		factors: factors,
+		conv: &converter{
+			meterHistory: gen.NewMeterHistoryClient(conn),
+			meterInfo:    gen.NewMeterInfoClient(conn),
+			location:     time.Local,
+		},
+		now: time.Now,
+	})
+	if err != nil {
+		return fmt.Errorf("can't create MeterConversionApi service: %w", err)
+	}
+	undo, err := s.node.AnnounceService(srv)
+	if err != nil {
+		return fmt.Errorf("can't announce MeterConversionApi service: %w", err)
+	}
+	go func() {
+		<-ctx.Done()
+		undo()
+	}()
+	return nil
+}
+
+// resolveConfigFile returns the path of name in the first config dir it exists in.
+// Absolute paths, and names that aren't found, are returned unchanged.
+func (s *System) resolveConfigFile(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	for _, dir := range s.configDirs {
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return name
+}
@@ -0,0 +1,111 @@
+package conversion
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/system/conversion/config"
+)
+
+// maxReadings is the most readings a single request can return.
+const maxReadings = 10000
+
+// server implements gen.MeterConversionApiServer.
+type server struct {
+	gen.UnimplementedMeterConversionApiServer
+	cfg     config.Root
+	factors func() (config.Factors, error)
+	conv    *converter
+	now     func() time.Time
+}
+
+func (s *server) ListConvertedMeterReadings(ctx context.Context, request *gen.ListConvertedMeterReadingsRequest) (*gen.ListConvertedMeterReadingsResponse, error) {
+	if request.MeterName == "" {
+		return nil, status.Error(codes.InvalidArgument, "meter_name is required")
+	}
+	if request.GetPeriod().GetStartTime() == nil {
+		return nil, status.Error(codes.InvalidArgument, "period.start_time is required")
+	}
+	start := request.Period.StartTime.AsTime()
+	end := s.now()
+	if request.Period.EndTime != nil {
+		end = request.Period.EndTime.AsTime()
+	}
+	if !end.After(start) {
+		return nil, status.Error(codes.InvalidArgument, "period.end_time must be after period.start_time")
+	}
+	interval := end.Sub(start)
+	if request.Interval != nil {
+		interval = request.Interval.AsDuration()
+		if interval <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "interval must be positive")
+		}
+	}
+	if end.Sub(start)/interval >= maxReadings {
+		return nil, status.Errorf(codes.InvalidArgument, "interval is too short, at most %d readings can be returned", maxReadings)
+	}
+
+	factors, err := s.factors()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "factors: %v", err)
+	}
+	fuelName := cmp.Or(request.Fuel, s.cfg.Fuel(request.MeterName))
+	fuel, ok := factors.Fuels[fuelName]
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "no factors for fuel %q", fuelName)
+	}
+
+	readings, err := s.conv.convert(ctx, request.MeterName, fuel, start, end, interval)
+	if err != nil {
+		return nil, err
+	}
+	return &gen.ListConvertedMeterReadingsResponse{
+		Readings:  readings,
+		Total:     sum(readings),
+		Fuel:      fuelName,
+		UsageUnit: fuel.Unit,
+		Currency:  cmp.Or(factors.Currency, config.DefaultCurrency),
+	}, nil
+}
+
+// fileFactors reads factors from a file, reading it again when it is modified.
+type fileFactors struct {
+	path   string
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	modTime time.Time
+	factors *config.Factors
+}
+
+// load returns the factors in the file.
+// If the file can't be read the last factors read are returned, if any.
+func (f *fileFactors) load() (config.Factors, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, err := os.Stat(f.path)
+	if err == nil && f.factors != nil && info.ModTime().Equal(f.modTime) {
+		return *f.factors, nil
+	}
+	var factors config.Factors
+	if err == nil {
+		factors, err = config.ReadFactorsFile(f.path)
+	}
+	if err != nil {
+		if f.factors == nil {
+			return config.Factors{}, err
+		}
+		f.logger.Warn("failed to read factors, using previous factors", zap.String("path", f.path), zap.Error(err))
+		return *f.factors, nil
+	}
+	f.factors, f.modTime = &factors, info.ModTime()
+	return factors, nil
+}
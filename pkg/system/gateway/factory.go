@@ -41,6 +41,7 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -72,7 +73,10 @@ func (f *factory) New(services system.Services) service.Lifecycle {
 		checks: services.HealthChecks,
 		ignore: []string{services.GRPCEndpoint}, // avoid infinite recursion
 		newClient: func(address string) (*grpc.ClientConn, error) {
-			return grpc.NewClient(address, grpc.WithTransportCredentials(credentials.NewTLS(services.ClientTLSConfig)))
+			return grpc.NewClient(address,
+				grpc.WithTransportCredentials(credentials.NewTLS(services.ClientTLSConfig)),
+				grpc.WithStatsHandler(otelgrpc.NewClientHandler()), // propagate trace context to the node
+			)
 		},
 		reflection: services.ReflectionServer,
 		announcer:  services.Node,
@@ -472,6 +472,8 @@ func testReflection(t *testing.T, ctx context.Context, conn *grpc.ClientConn) {
 	wantServices := []*reflectionpb.ServiceResponse{
 		{Name: "grpc.reflection.v1.ServerReflection"},
 		{Name: "grpc.reflection.v1alpha.ServerReflection"},
+		{Name: "smartcore.bos.AuditApi"},
+		{Name: "smartcore.bos.ConfigHistoryApi"},
 		{Name: "smartcore.bos.DevicesApi"},
 		{Name: "smartcore.bos.EnrollmentApi"},
 		{Name: "smartcore.bos.HealthApi"},
@@ -156,7 +156,14 @@ func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
 		return fmt.Errorf("unsuported storage type %s", cfg.Storage.Type)
 	}
 
-	server := &storeServer{store: store}
+	storage := string(cfg.Storage.Type)
+	server := &storeServer{store: func(source string) history.Store {
+		st := store(source)
+		if st == nil {
+			return nil
+		}
+		return history.WithMetrics(st, storage)
+	}}
 	announcer.Announce(s.name, node.HasClient(gen.WrapHistoryAdminApi(server)))
 
 	return nil
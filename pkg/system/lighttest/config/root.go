@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/system"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	DefaultFunctionTestInterval = 30 * 24 * time.Hour
+	DefaultFunctionTestStagger  = 7 * 24 * time.Hour
+	DefaultFunctionTestLength   = time.Minute
+	DefaultDurationTestInterval = 365 * 24 * time.Hour
+	DefaultDurationTestStagger  = 30 * 24 * time.Hour
+	DefaultDurationTestLength   = 3 * time.Hour
+	DefaultResultTimeout        = time.Hour
+	DefaultCheckInterval        = time.Minute
+	DefaultRetryInterval        = time.Hour
+)
+
+type Root struct {
+	system.Config
+	// FunctionTest configures the short, typically monthly, test of each emergency light.
+	FunctionTest *Test `json:"functionTest,omitempty"`
+	// DurationTest configures the full rated duration, typically annual, test of each emergency light.
+	DurationTest *Test `json:"durationTest,omitempty"`
+	// Window limits the local time of day that tests can be started.
+	// Tests can start at any time if absent.
+	Window *Window `json:"window,omitempty"`
+	// MaxConcurrentTests limits how many lights can be under test at once, zero means no limit.
+	// Use this to avoid large areas being without emergency lighting while batteries recharge after a duration test.
+	MaxConcurrentTests int `json:"maxConcurrentTests,omitempty"`
+	// CheckInterval is how often the scheduler starts due tests and collects results.
+	// Defaults to 1 minute.
+	CheckInterval *jsontypes.Duration `json:"checkInterval,omitempty"`
+	// RetryInterval is how long to wait before trying again after a test couldn't be started.
+	// Defaults to 1 hour.
+	RetryInterval *jsontypes.Duration `json:"retryInterval,omitempty"`
+}
+
+// Test configures how often one kind of test is run.
+type Test struct {
+	// Disabled stops this kind of test being started by the scheduler.
+	Disabled bool `json:"disabled,omitempty"`
+	// Interval is the time between tests of the same light.
+	// Defaults to 30 days for function tests and 365 days for duration tests.
+	Interval *jsontypes.Duration `json:"interval,omitempty"`
+	// Stagger spreads the first test of each light over this period, so lights aren't all tested at once.
+	// Defaults to 7 days for function tests and 30 days for duration tests.
+	Stagger *jsontypes.Duration `json:"stagger,omitempty"`
+	// Length is how long the test takes to run, results aren't collected before this.
+	// Defaults to 1 minute for function tests and 3 hours for duration tests.
+	Length *jsontypes.Duration `json:"length,omitempty"`
+	// ResultTimeout is how long after Length to wait for a result before the test is recorded as failed.
+	// Defaults to 1 hour.
+	ResultTimeout *jsontypes.Duration `json:"resultTimeout,omitempty"`
+}
+
+func (t *Test) IsDisabled() bool {
+	return t != nil && t.Disabled
+}
+
+func (t *Test) IntervalOr(d time.Duration) time.Duration {
+	if t == nil {
+		return d
+	}
+	return t.Interval.Or(d)
+}
+
+func (t *Test) StaggerOr(d time.Duration) time.Duration {
+	if t == nil {
+		return d
+	}
+	return t.Stagger.Or(d)
+}
+
+func (t *Test) LengthOr(d time.Duration) time.Duration {
+	if t == nil {
+		return d
+	}
+	return t.Length.Or(d)
+}
+
+func (t *Test) ResultTimeoutOr(d time.Duration) time.Duration {
+	if t == nil {
+		return d
+	}
+	return t.ResultTimeout.Or(d)
+}
+
+// Window is a period of each day, in local time.
+// If End is before Start the window spans midnight.
+type Window struct {
+	Start string `json:"start,omitempty"` // like "22:00"
+	End   string `json:"end,omitempty"`   // like "06:00"
+}
+
+// Parse returns the start and end of the window as offsets from midnight.
+func (w *Window) Parse() (start, end time.Duration, err error) {
+	start, err = parseTimeOfDay(w.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("window.start %w", err)
+	}
+	end, err = parseTimeOfDay(w.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("window.end %w", err)
+	}
+	return start, end, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day like 15:04", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
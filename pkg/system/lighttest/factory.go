@@ -0,0 +1,110 @@
+// Package lighttest provides a system that schedules emergency lighting tests and records their results.
+// Lights are discovered via the EmergencyLightApi and DaliApi traits,
+// and the recorded health of each light is served via the LightingTestApi.
+package lighttest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/dalipb"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/devicespb"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/emergencylightpb"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/system"
+	"github.com/smart-core-os/sc-bos/pkg/system/lighttest/config"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+	"github.com/smart-core-os/sc-golang/pkg/resource"
+)
+
+// dbFile is the path of the database, relative to the data dir.
+const dbFile = "lighttest/lighttest.sqlite3"
+
+var Factory factory
+
+type factory struct{}
+
+func (_ factory) New(services system.Services) service.Lifecycle {
+	return NewSystem(services)
+}
+
+func NewSystem(services system.Services) *System {
+	logger := services.Logger.Named("lighttest")
+	s := &System{
+		node:    services.Node,
+		dataDir: services.DataDir,
+		logger:  logger,
+	}
+	s.Service = service.New(
+		service.MonoApply(s.applyConfig),
+		service.WithRetry[config.Root](service.RetryWithLogger(func(logContext service.RetryContext) {
+			logContext.LogTo("applyConfig", logger)
+		})),
+	)
+	return s
+}
+
+type System struct {
+	*service.Service[config.Root]
+	node    *node.Node
+	dataDir string
+	logger  *zap.Logger
+}
+
+func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
+	sch, err := scheduleFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(ctx, filepath.Join(s.dataDir, dbFile), s.logger)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+
+	srv, err := node.RegistryService(gen.LightingTestApi_ServiceDesc, &server{store: store})
+	if err != nil {
+		_ = store.Close()
+		return fmt.Errorf("can't create LightingTestApi service: %w", err)
+	}
+	undo, err := s.node.AnnounceService(srv)
+	if err != nil {
+		_ = store.Close()
+		return fmt.Errorf("can't announce LightingTestApi service: %w", err)
+	}
+
+	sched := newScheduler(store, sch, s.logger)
+	go s.discoverLights(ctx, sched)
+	go sched.run(ctx, cfg.CheckInterval.Or(config.DefaultCheckInterval))
+	go func() {
+		<-ctx.Done()
+		undo()
+		_ = store.Close()
+	}()
+	return nil
+}
+
+// discoverLights adds devices with emergency lighting traits to sched until ctx is done.
+func (s *System) discoverLights(ctx context.Context, sched *scheduler) {
+	conn := s.node.ClientConn()
+	emergencyLights := emergencyLightTester{client: gen.NewEmergencyLightApiClient(conn)}
+	dali := daliTester{client: gen.NewDaliApiClient(conn)}
+	for change := range s.node.PullDevices(ctx, resource.WithReadPaths(&gen.Device{}, "metadata.traits")) {
+		var t tester
+		switch {
+		case devicespb.HasTrait(change.NewValue, emergencylightpb.TraitName):
+			t = emergencyLights
+		case devicespb.HasTrait(change.NewValue, dalipb.TraitName):
+			t = dali
+		default:
+			sched.removeDevice(change.Id)
+			continue
+		}
+		if err := sched.addDevice(ctx, change.Id, t); err != nil && ctx.Err() == nil {
+			s.logger.Warn("failed to add emergency light", zap.String("name", change.Id), zap.Error(err))
+		}
+	}
+}
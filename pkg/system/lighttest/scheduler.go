@@ -0,0 +1,346 @@
+package lighttest
+
+import (
+	"context"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/system/lighttest/config"
+)
+
+// deviceTimeout limits how long a single request to a light can take.
+const deviceTimeout = 30 * time.Second
+
+// schedule describes when lights should be tested.
+type schedule struct {
+	function, duration testSchedule
+	// tests are only started between windowStart and windowEnd, offsets from local midnight.
+	// If both are equal tests can start at any time.
+	windowStart, windowEnd time.Duration
+	maxConcurrent          int
+	retryInterval          time.Duration
+}
+
+type testSchedule struct {
+	disabled bool
+	interval time.Duration
+	stagger  time.Duration
+	length   time.Duration
+	timeout  time.Duration
+}
+
+func scheduleFromConfig(cfg config.Root) (schedule, error) {
+	sch := schedule{
+		function: testSchedule{
+			disabled: cfg.FunctionTest.IsDisabled(),
+			interval: cfg.FunctionTest.IntervalOr(config.DefaultFunctionTestInterval),
+			stagger:  cfg.FunctionTest.StaggerOr(config.DefaultFunctionTestStagger),
+			length:   cfg.FunctionTest.LengthOr(config.DefaultFunctionTestLength),
+			timeout:  cfg.FunctionTest.ResultTimeoutOr(config.DefaultResultTimeout),
+		},
+		duration: testSchedule{
+			disabled: cfg.DurationTest.IsDisabled(),
+			interval: cfg.DurationTest.IntervalOr(config.DefaultDurationTestInterval),
+			stagger:  cfg.DurationTest.StaggerOr(config.DefaultDurationTestStagger),
+			length:   cfg.DurationTest.LengthOr(config.DefaultDurationTestLength),
+			timeout:  cfg.DurationTest.ResultTimeoutOr(config.DefaultResultTimeout),
+		},
+		maxConcurrent: cfg.MaxConcurrentTests,
+		retryInterval: cfg.RetryInterval.Or(config.DefaultRetryInterval),
+	}
+	if cfg.Window != nil {
+		var err error
+		sch.windowStart, sch.windowEnd, err = cfg.Window.Parse()
+		if err != nil {
+			return schedule{}, err
+		}
+	}
+	return sch, nil
+}
+
+func (sch schedule) test(kind testKind) testSchedule {
+	if kind == durationTest {
+		return sch.duration
+	}
+	return sch.function
+}
+
+// inWindow returns whether tests can be started at t.
+func (sch schedule) inWindow(t time.Time) bool {
+	if sch.windowStart == sch.windowEnd {
+		return true
+	}
+	t = t.Local()
+	tod := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()))
+	if sch.windowStart < sch.windowEnd {
+		return tod >= sch.windowStart && tod < sch.windowEnd
+	}
+	return tod >= sch.windowStart || tod < sch.windowEnd
+}
+
+// dueTime returns when the next test of kind should be run on l.
+// Lights that have never been tested are staggered from when they were first seen, based on their name.
+func (sch schedule) dueTime(l light, kind testKind) time.Time {
+	ts := sch.test(kind)
+	last := l.LastFunctionTest
+	if kind == durationTest {
+		last = l.LastDurationTest
+	}
+	if last.IsZero() {
+		return l.FirstSeenTime.Add(staggerOffset(l.Name, ts.stagger))
+	}
+	return last.Add(ts.interval)
+}
+
+// due returns the test that should be run on l at now, or noTest.
+// Duration tests take priority as they also test the function of the light.
+func (sch schedule) due(l light, now time.Time) testKind {
+	for _, kind := range []testKind{durationTest, functionTest} {
+		if sch.test(kind).disabled {
+			continue
+		}
+		if !now.Before(sch.dueTime(l, kind)) {
+			return kind
+		}
+	}
+	return noTest
+}
+
+func staggerOffset(name string, stagger time.Duration) time.Duration {
+	if stagger <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return time.Duration(h.Sum64() % uint64(stagger))
+}
+
+// scheduler starts tests on lights when they are due and records the results.
+type scheduler struct {
+	store    *store
+	schedule schedule
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex
+	devices map[string]tester
+}
+
+func newScheduler(store *store, sch schedule, logger *zap.Logger) *scheduler {
+	return &scheduler{
+		store:    store,
+		schedule: sch,
+		logger:   logger,
+		now:      time.Now,
+		devices:  make(map[string]tester),
+	}
+}
+
+// addDevice makes the named light available for testing using t.
+func (s *scheduler) addDevice(ctx context.Context, name string, t tester) error {
+	if err := s.store.addLight(ctx, name, s.now()); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.devices[name] = t
+	return nil
+}
+
+// removeDevice stops the named light being tested.
+// Its recorded health and events are kept.
+func (s *scheduler) removeDevice(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.devices, name)
+}
+
+// run calls check every interval until ctx is done.
+func (s *scheduler) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.check(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Warn("failed to check emergency light tests", zap.Error(err))
+			}
+		}
+	}
+}
+
+// check collects the results of running tests, then starts any tests that are due.
+func (s *scheduler) check(ctx context.Context) error {
+	now := s.now()
+	s.mu.Lock()
+	devices := maps.Clone(s.devices)
+	s.mu.Unlock()
+
+	var active int
+	var idle []light
+	for _, name := range slices.Sorted(maps.Keys(devices)) {
+		l, err := s.store.getLight(ctx, name)
+		if err != nil {
+			return err
+		}
+		if l.ActiveTest == noTest {
+			idle = append(idle, l)
+			continue
+		}
+		done, err := s.collectResult(ctx, devices[name], l, now)
+		if err != nil {
+			return err
+		}
+		if !done {
+			active++
+		}
+	}
+
+	if !s.schedule.inWindow(now) {
+		return nil
+	}
+	for _, l := range idle {
+		if s.schedule.maxConcurrent > 0 && active >= s.schedule.maxConcurrent {
+			break
+		}
+		kind := s.schedule.due(l, now)
+		if kind == noTest {
+			continue
+		}
+		if !l.LastStartFailure.IsZero() && now.Sub(l.LastStartFailure) < s.schedule.retryInterval {
+			continue
+		}
+		started, err := s.startTest(ctx, devices[l.Name], l, kind, now)
+		if err != nil {
+			return err
+		}
+		if started {
+			active++
+		}
+	}
+	return nil
+}
+
+// startTest starts a test of kind on l, returning whether it started.
+func (s *scheduler) startTest(ctx context.Context, t tester, l light, kind testKind, now time.Time) (bool, error) {
+	logger := s.logger.With(zap.String("name", l.Name), zap.Stringer("test", kind))
+	reqCtx, cancel := context.WithTimeout(ctx, deviceTimeout)
+	err := t.startTest(reqCtx, l.Name, kind)
+	cancel()
+	l.UpdateTime = now
+	if err != nil {
+		logger.Debug("failed to start test", zap.Error(err))
+		l.LastStartFailure = now
+		var events []*gen.LightingEvent
+		if !slices.Contains(l.Faults, gen.LightFault_COMMUNICATION_FAILURE) {
+			l.Faults = addFaults(l.Faults, gen.LightFault_COMMUNICATION_FAILURE)
+			events = append(events, statusReportEvent(l, now))
+		}
+		return false, s.store.updateLight(ctx, l, events...)
+	}
+	logger.Debug("started test")
+	l.ActiveTest = kind
+	l.ActiveTestStart = now
+	l.LastStartFailure = time.Time{}
+	return true, s.store.updateLight(ctx, l)
+}
+
+// collectResult records the result of the active test of l, if it has completed or timed out.
+func (s *scheduler) collectResult(ctx context.Context, t tester, l light, now time.Time) (bool, error) {
+	ts := s.schedule.test(l.ActiveTest)
+	elapsed := now.Sub(l.ActiveTestStart)
+	if elapsed < ts.length {
+		return false, nil
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, deviceTimeout)
+	res, ok, err := t.testResult(reqCtx, l.Name, l.ActiveTest, l.ActiveTestStart)
+	cancel()
+	if err != nil {
+		s.logger.Debug("failed to read test result", zap.String("name", l.Name), zap.Stringer("test", l.ActiveTest), zap.Error(err))
+	}
+	if !ok {
+		if elapsed < ts.length+ts.timeout {
+			return false, nil
+		}
+		res = testResult{EndTime: now, Faults: []gen.LightFault{gen.LightFault_COMMUNICATION_FAILURE}}
+	}
+	l, events := applyResult(l, res, now)
+	return true, s.store.updateLight(ctx, l, events...)
+}
+
+// applyResult updates l with the result of its active test, returning the events that describe the change.
+func applyResult(l light, res testResult, now time.Time) (light, []*gen.LightingEvent) {
+	kind, start := l.ActiveTest, l.ActiveTestStart
+	end := res.EndTime
+	if end.IsZero() {
+		end = now
+	}
+	l.UpdateTime = now
+	l.ActiveTest = noTest
+	l.ActiveTestStart = time.Time{}
+	// a duration test also tests the function of the light
+	l.LastFunctionTest = end
+	if kind == durationTest {
+		l.LastDurationTest = end
+	}
+
+	var events []*gen.LightingEvent
+	oldFaults := l.Faults
+	if res.Pass {
+		l.Faults = nil
+		if kind == functionTest && slices.Contains(oldFaults, gen.LightFault_DURATION_TEST_FAILED) {
+			l.Faults = []gen.LightFault{gen.LightFault_DURATION_TEST_FAILED}
+		}
+		event := &gen.LightingEvent{Name: l.Name, Timestamp: timestamppb.New(end)}
+		switch kind {
+		case functionTest:
+			event.Event = &gen.LightingEvent_FunctionTestPass_{FunctionTestPass: &gen.LightingEvent_FunctionTestPass{}}
+		case durationTest:
+			achieved := res.Duration
+			if achieved <= 0 {
+				achieved = end.Sub(start)
+			}
+			event.Event = &gen.LightingEvent_DurationTestPass_{DurationTestPass: &gen.LightingEvent_DurationTestPass{
+				AchievedDuration: durationpb.New(achieved),
+			}}
+		}
+		events = append(events, event)
+	} else {
+		failed := gen.LightFault_FUNCTION_TEST_FAILED
+		if kind == durationTest {
+			failed = gen.LightFault_DURATION_TEST_FAILED
+		}
+		l.Faults = addFaults(slices.Clone(oldFaults), append([]gen.LightFault{failed}, res.Faults...)...)
+	}
+	if !slices.Equal(oldFaults, l.Faults) {
+		events = append(events, statusReportEvent(l, now))
+	}
+	return l, events
+}
+
+// addFaults returns faults with each of add included, sorted and without duplicates.
+func addFaults(faults []gen.LightFault, add ...gen.LightFault) []gen.LightFault {
+	faults = append(faults, add...)
+	slices.Sort(faults)
+	return slices.Compact(faults)
+}
+
+func statusReportEvent(l light, now time.Time) *gen.LightingEvent {
+	return &gen.LightingEvent{
+		Name:      l.Name,
+		Timestamp: timestamppb.New(now),
+		Event: &gen.LightingEvent_StatusReport_{StatusReport: &gen.LightingEvent_StatusReport{
+			Faults: slices.Clone(l.Faults),
+		}},
+	}
+}
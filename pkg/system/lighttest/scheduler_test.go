@@ -0,0 +1,211 @@
+package lighttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+type fakeTester struct {
+	started  []testKind
+	startErr error
+	results  map[testKind]testResult
+}
+
+func (f *fakeTester) startTest(_ context.Context, _ string, kind testKind) error {
+	if f.startErr != nil {
+		return f.startErr
+	}
+	f.started = append(f.started, kind)
+	return nil
+}
+
+func (f *fakeTester) testResult(_ context.Context, _ string, kind testKind, _ time.Time) (testResult, bool, error) {
+	res, ok := f.results[kind]
+	return res, ok, nil
+}
+
+func newTestScheduler(t *testing.T, sch schedule) (*scheduler, *time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	st, err := openMemoryStore(ctx, zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	s := newScheduler(st, sch, zaptest.NewLogger(t))
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func newTestSchedule() schedule {
+	return schedule{
+		function:      testSchedule{interval: 30 * 24 * time.Hour, length: time.Minute, timeout: time.Hour},
+		duration:      testSchedule{interval: 365 * 24 * time.Hour, length: 3 * time.Hour, timeout: time.Hour},
+		retryInterval: time.Hour,
+	}
+}
+
+func TestScheduler_check(t *testing.T) {
+	ctx := context.Background()
+	s, now := newTestScheduler(t, newTestSchedule())
+	dev := &fakeTester{results: map[testKind]testResult{}}
+	if err := s.addDevice(ctx, "light1", dev); err != nil {
+		t.Fatal(err)
+	}
+
+	// never tested, no stagger, so the duration test is due first
+	if err := s.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]testKind{durationTest}, dev.started); diff != "" {
+		t.Fatalf("started (-want,+got)\n%s", diff)
+	}
+
+	// no result yet, still running
+	*now = now.Add(3*time.Hour + 30*time.Minute)
+	if err := s.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	l, err := s.store.getLight(ctx, "light1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.ActiveTest != durationTest {
+		t.Fatalf("active test = %v, want %v", l.ActiveTest, durationTest)
+	}
+
+	// the test passes
+	end := now.Add(-30 * time.Minute)
+	dev.results[durationTest] = testResult{Pass: true, EndTime: end, Duration: 3 * time.Hour}
+	if err := s.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.store.getLight(ctx, "light1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &gen.LightHealth{
+		Name:             "light1",
+		UpdateTime:       optTimestamp(*now),
+		LastFunctionTest: optTimestamp(end),
+		LastDurationTest: optTimestamp(end),
+	}
+	if diff := cmp.Diff(want, got.toProto(), protocmp.Transform()); diff != "" {
+		t.Fatalf("health (-want,+got)\n%s", diff)
+	}
+	if len(dev.started) != 1 {
+		t.Fatalf("started another test after the result: %v", dev.started)
+	}
+
+	// the function test is due a month after the duration test, and fails
+	*now = end.Add(30 * 24 * time.Hour)
+	if err := s.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]testKind{durationTest, functionTest}, dev.started); diff != "" {
+		t.Fatalf("started (-want,+got)\n%s", diff)
+	}
+	*now = now.Add(2 * time.Minute)
+	dev.results[functionTest] = testResult{EndTime: *now, Faults: []gen.LightFault{gen.LightFault_LAMP_FAULT}}
+	if err := s.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.store.getLight(ctx, "light1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFaults := []gen.LightFault{gen.LightFault_FUNCTION_TEST_FAILED, gen.LightFault_LAMP_FAULT}
+	if diff := cmp.Diff(wantFaults, got.Faults); diff != "" {
+		t.Fatalf("faults (-want,+got)\n%s", diff)
+	}
+
+	events, err := s.store.listEvents(ctx, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEvents := []*gen.LightingEvent{
+		{Name: "light1", Id: "1", Timestamp: optTimestamp(end), Event: &gen.LightingEvent_DurationTestPass_{
+			DurationTestPass: &gen.LightingEvent_DurationTestPass{AchievedDuration: durationpb.New(3 * time.Hour)},
+		}},
+		{Name: "light1", Id: "2", Timestamp: optTimestamp(*now), Event: &gen.LightingEvent_StatusReport_{
+			StatusReport: &gen.LightingEvent_StatusReport{Faults: wantFaults},
+		}},
+	}
+	if diff := cmp.Diff(wantEvents, events, protocmp.Transform()); diff != "" {
+		t.Fatalf("events (-want,+got)\n%s", diff)
+	}
+}
+
+func TestScheduler_startFailure(t *testing.T) {
+	ctx := context.Background()
+	s, now := newTestScheduler(t, newTestSchedule())
+	dev := &fakeTester{startErr: errors.New("offline")}
+	if err := s.addDevice(ctx, "light1", dev); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	l, err := s.store.getLight(ctx, "light1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]gen.LightFault{gen.LightFault_COMMUNICATION_FAILURE}, l.Faults); diff != "" {
+		t.Fatalf("faults (-want,+got)\n%s", diff)
+	}
+
+	// not retried until the retry interval has passed
+	dev.startErr = nil
+	*now = now.Add(time.Minute)
+	if err := s.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(dev.started) != 0 {
+		t.Fatalf("retried too soon: %v", dev.started)
+	}
+	*now = now.Add(time.Hour)
+	if err := s.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]testKind{durationTest}, dev.started); diff != "" {
+		t.Fatalf("started (-want,+got)\n%s", diff)
+	}
+}
+
+func TestSchedule_inWindow(t *testing.T) {
+	at := func(h, m int) time.Time {
+		return time.Date(2024, 3, 1, h, m, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name       string
+		start, end time.Duration
+		t          time.Time
+		want       bool
+	}{
+		{"no window", 0, 0, at(3, 0), true},
+		{"inside", 9 * time.Hour, 17 * time.Hour, at(12, 0), true},
+		{"before", 9 * time.Hour, 17 * time.Hour, at(8, 59), false},
+		{"at end", 9 * time.Hour, 17 * time.Hour, at(17, 0), false},
+		{"overnight late", 22 * time.Hour, 6 * time.Hour, at(23, 0), true},
+		{"overnight early", 22 * time.Hour, 6 * time.Hour, at(5, 0), true},
+		{"overnight day", 22 * time.Hour, 6 * time.Hour, at(12, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := schedule{windowStart: tt.start, windowEnd: tt.end}
+			if got := sch.inWindow(tt.t); got != tt.want {
+				t.Errorf("inWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
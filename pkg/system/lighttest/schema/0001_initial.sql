@@ -0,0 +1,27 @@
+-- The test state of each emergency light known to the scheduler.
+-- All times are unix milliseconds, NULL means never.
+CREATE TABLE lights
+(
+    name               TEXT PRIMARY KEY,
+    first_seen_time    INTEGER NOT NULL,
+    update_time        INTEGER NOT NULL,
+    -- comma separated LightFault numbers
+    faults             TEXT    NOT NULL DEFAULT '',
+    last_function_test INTEGER,
+    last_duration_test INTEGER,
+    -- the kind of test in progress, 0 if none, 1 for function tests and 2 for duration tests
+    active_test        INTEGER NOT NULL DEFAULT 0,
+    active_test_start  INTEGER,
+    -- the last time a test failed to start
+    last_start_failure INTEGER
+);
+
+CREATE TABLE light_events
+(
+    id      INTEGER PRIMARY KEY,
+    name    TEXT    NOT NULL,
+    -- unix milliseconds
+    time    INTEGER NOT NULL,
+    -- A binary LightingEvent proto message, without id.
+    payload BLOB    NOT NULL
+);
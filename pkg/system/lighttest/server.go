@@ -0,0 +1,143 @@
+package lighttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// server implements gen.LightingTestApiServer using the records in a store.
+type server struct {
+	gen.UnimplementedLightingTestApiServer
+	store *store
+}
+
+func (s *server) GetLightHealth(ctx context.Context, req *gen.GetLightHealthRequest) (*gen.LightHealth, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	l, err := s.store.getLight(ctx, req.Name)
+	if errors.Is(err, errLightNotFound) {
+		return nil, status.Errorf(codes.NotFound, "light %q not found", req.Name)
+	} else if err != nil {
+		return nil, err
+	}
+	return l.toProto(), nil
+}
+
+// ListLightHealth returns lights ordered by name.
+// The page token encodes the name of the last light returned.
+func (s *server) ListLightHealth(ctx context.Context, req *gen.ListLightHealthRequest) (*gen.ListLightHealthResponse, error) {
+	after, err := base64.RawURLEncoding.DecodeString(req.PageToken)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+	pageSize := pageSize(req.PageSize)
+	// read one extra to know if there's another page
+	lights, err := s.store.listLights(ctx, string(after), pageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	res := &gen.ListLightHealthResponse{}
+	if len(lights) > pageSize {
+		lights = lights[:pageSize]
+		res.NextPageToken = base64.RawURLEncoding.EncodeToString([]byte(lights[len(lights)-1].Name))
+	}
+	for _, l := range lights {
+		res.EmergencyLights = append(res.EmergencyLights, l.toProto())
+	}
+	return res, nil
+}
+
+// ListLightEvents returns events oldest first.
+// The page token is the id of the last event returned.
+func (s *server) ListLightEvents(ctx context.Context, req *gen.ListLightEventsRequest) (*gen.ListLightEventsResponse, error) {
+	var afterID int64
+	if req.PageToken != "" {
+		var err error
+		afterID, err = strconv.ParseInt(req.PageToken, 10, 64)
+		if err != nil || afterID < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+	}
+	pageSize := pageSize(req.PageSize)
+	events, err := s.store.listEvents(ctx, afterID, pageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	res := &gen.ListLightEventsResponse{}
+	more := len(events) > pageSize
+	if more {
+		events = events[:pageSize]
+	}
+	lastID := afterID
+	if len(events) > 0 {
+		lastID, _ = strconv.ParseInt(events[len(events)-1].Id, 10, 64)
+	}
+	res.Events = events
+	res.FuturePageToken = strconv.FormatInt(lastID, 10)
+	if more {
+		res.NextPageToken = res.FuturePageToken
+	}
+	return res, nil
+}
+
+// GetReportCSV returns the health of all lights as CSV.
+func (s *server) GetReportCSV(ctx context.Context, req *gen.GetReportCSVRequest) (*gen.ReportCSV, error) {
+	lights, err := s.store.listLights(ctx, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if req.IncludeHeader {
+		_ = w.Write([]string{"Name", "Faults", "Last Function Test", "Last Duration Test", "Updated"})
+	}
+	for _, l := range lights {
+		faults := make([]string, len(l.Faults))
+		for i, f := range l.Faults {
+			faults[i] = f.String()
+		}
+		_ = w.Write([]string{
+			l.Name,
+			strings.Join(faults, ";"),
+			formatReportTime(l.LastFunctionTest),
+			formatReportTime(l.LastDurationTest),
+			formatReportTime(l.UpdateTime),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return &gen.ReportCSV{Csv: buf.Bytes()}, nil
+}
+
+func pageSize(n int32) int {
+	if n <= 0 {
+		return defaultPageSize
+	}
+	return min(int(n), maxPageSize)
+}
+
+func formatReportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
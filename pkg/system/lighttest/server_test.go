@@ -0,0 +1,106 @@
+package lighttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	st, err := openMemoryStore(ctx, zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	for _, name := range []string{"b", "a", "c"} {
+		if err := st.addLight(ctx, name, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l, err := st.getLight(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Faults = []gen.LightFault{gen.LightFault_BATTERY_FAULT, gen.LightFault_DURATION_TEST_FAILED}
+	l.LastDurationTest = now
+	if err := st.updateLight(ctx, l, statusReportEvent(l, now), statusReportEvent(l, now)); err != nil {
+		t.Fatal(err)
+	}
+	srv := &server{store: st}
+
+	t.Run("GetLightHealth", func(t *testing.T) {
+		_, err := srv.GetLightHealth(ctx, &gen.GetLightHealthRequest{Name: "missing"})
+		if code := status.Code(err); code != codes.NotFound {
+			t.Fatalf("want NotFound, got %v", err)
+		}
+	})
+
+	t.Run("ListLightHealth", func(t *testing.T) {
+		var names []string
+		var pageToken string
+		for i := 0; ; i++ {
+			res, err := srv.ListLightHealth(ctx, &gen.ListLightHealthRequest{PageSize: 2, PageToken: pageToken})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, l := range res.EmergencyLights {
+				names = append(names, l.Name)
+			}
+			pageToken = res.NextPageToken
+			if pageToken == "" || i > 3 {
+				break
+			}
+		}
+		if diff := cmp.Diff([]string{"a", "b", "c"}, names); diff != "" {
+			t.Fatalf("names (-want,+got)\n%s", diff)
+		}
+	})
+
+	t.Run("ListLightEvents", func(t *testing.T) {
+		res, err := srv.ListLightEvents(ctx, &gen.ListLightEventsRequest{PageSize: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Events) != 1 || res.NextPageToken != "1" || res.FuturePageToken != "1" {
+			t.Fatalf("first page: %v", res)
+		}
+		res, err = srv.ListLightEvents(ctx, &gen.ListLightEventsRequest{PageSize: 1, PageToken: res.NextPageToken})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Events) != 1 || res.NextPageToken != "" || res.FuturePageToken != "2" {
+			t.Fatalf("last page: %v", res)
+		}
+		res, err = srv.ListLightEvents(ctx, &gen.ListLightEventsRequest{PageToken: res.FuturePageToken})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Events) != 0 || res.FuturePageToken != "2" {
+			t.Fatalf("future page: %v", res)
+		}
+	})
+
+	t.Run("GetReportCSV", func(t *testing.T) {
+		res, err := srv.GetReportCSV(ctx, &gen.GetReportCSVRequest{IncludeHeader: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "Name,Faults,Last Function Test,Last Duration Test,Updated\n" +
+			"a,,,,2024-03-01T10:00:00Z\n" +
+			"b,DURATION_TEST_FAILED;BATTERY_FAULT,,2024-03-01T10:00:00Z,2024-03-01T10:00:00Z\n" +
+			"c,,,,2024-03-01T10:00:00Z\n"
+		if diff := cmp.Diff(want, string(res.Csv)); diff != "" {
+			t.Fatalf("csv (-want,+got)\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package lighttest
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/internal/sqlite"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const appID = 0x5C0506
+
+//go:embed schema/*.sql
+var schemaVersionsFS embed.FS
+var schema = sqlite.MustLoadVersionedSchema(schemaVersionsFS, "schema")
+
+func init() {
+	sqlite.RegisterSchema("lighttest", appID, schema)
+}
+
+var errLightNotFound = errors.New("light not found")
+
+// testKind identifies the kind of emergency lighting test.
+type testKind int
+
+const (
+	noTest testKind = iota
+	functionTest
+	durationTest
+)
+
+func (k testKind) String() string {
+	switch k {
+	case functionTest:
+		return "function"
+	case durationTest:
+		return "duration"
+	default:
+		return "none"
+	}
+}
+
+// light is the recorded state of an emergency light.
+// Zero times mean never.
+type light struct {
+	Name             string
+	FirstSeenTime    time.Time
+	UpdateTime       time.Time
+	Faults           []gen.LightFault
+	LastFunctionTest time.Time
+	LastDurationTest time.Time
+	ActiveTest       testKind
+	ActiveTestStart  time.Time
+	LastStartFailure time.Time
+}
+
+func (l light) toProto() *gen.LightHealth {
+	return &gen.LightHealth{
+		Name:             l.Name,
+		UpdateTime:       timestamppb.New(l.UpdateTime),
+		Faults:           l.Faults,
+		LastFunctionTest: optTimestamp(l.LastFunctionTest),
+		LastDurationTest: optTimestamp(l.LastDurationTest),
+	}
+}
+
+// store records the state of emergency lights and the events that happened to them.
+type store struct {
+	db *sqlite.Database
+}
+
+func openStore(ctx context.Context, path string, logger *zap.Logger) (*store, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("mkdir: %w", err)
+	}
+	db, err := sqlite.Open(ctx, path,
+		sqlite.WithApplicationID(appID),
+		sqlite.WithLogger(logger),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return newStore(ctx, db)
+}
+
+func openMemoryStore(ctx context.Context, logger *zap.Logger) (*store, error) {
+	db := sqlite.OpenMemory(
+		sqlite.WithApplicationID(appID),
+		sqlite.WithLogger(logger),
+	)
+	return newStore(ctx, db)
+}
+
+func newStore(ctx context.Context, db *sqlite.Database) (*store, error) {
+	err := db.Migrate(ctx, schema)
+	if err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	return &store{db: db}, nil
+}
+
+func (s *store) Close() error {
+	return s.db.Close()
+}
+
+const lightColumns = "name, first_seen_time, update_time, faults, last_function_test, last_duration_test, active_test, active_test_start, last_start_failure"
+
+// getLight returns the light with the given name, or errLightNotFound.
+func (s *store) getLight(ctx context.Context, name string) (light, error) {
+	var l light
+	err := s.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		l, err = scanLight(tx.QueryRowContext(ctx, "SELECT "+lightColumns+" FROM lights WHERE name = ?;", name))
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return light{}, errLightNotFound
+	}
+	return l, err
+}
+
+// listLights returns up to limit lights ordered by name, starting after the named light.
+func (s *store) listLights(ctx context.Context, after string, limit int) ([]light, error) {
+	var lights []light
+	err := s.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		query := "SELECT " + lightColumns + " FROM lights WHERE name > ? ORDER BY name"
+		args := []any{after}
+		if limit > 0 {
+			query += " LIMIT ?"
+			args = append(args, limit)
+		}
+		rows, err := tx.QueryContext(ctx, query+";", args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			l, err := scanLight(rows)
+			if err != nil {
+				return err
+			}
+			lights = append(lights, l)
+		}
+		return rows.Err()
+	})
+	return lights, err
+}
+
+// addLight records a newly seen light, doing nothing if the light is already known.
+func (s *store) addLight(ctx context.Context, name string, now time.Time) error {
+	return s.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO lights (name, first_seen_time, update_time) VALUES (?, ?, ?) ON CONFLICT (name) DO NOTHING;",
+			name, now.UnixMilli(), now.UnixMilli())
+		return err
+	})
+}
+
+// updateLight saves the state of l, and adds any events to the log.
+func (s *store) updateLight(ctx context.Context, l light, events ...*gen.LightingEvent) error {
+	return s.db.WriteTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			"UPDATE lights SET update_time = ?, faults = ?, last_function_test = ?, last_duration_test = ?, active_test = ?, active_test_start = ?, last_start_failure = ? WHERE name = ?;",
+			l.UpdateTime.UnixMilli(), formatFaults(l.Faults), nullMillis(l.LastFunctionTest), nullMillis(l.LastDurationTest),
+			int(l.ActiveTest), nullMillis(l.ActiveTestStart), nullMillis(l.LastStartFailure), l.Name)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			payload, err := proto.Marshal(event)
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx,
+				"INSERT INTO light_events (name, time, payload) VALUES (?, ?, ?);",
+				event.Name, event.Timestamp.AsTime().UnixMilli(), payload)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// listEvents returns up to limit events, oldest first, with an id greater than afterID.
+func (s *store) listEvents(ctx context.Context, afterID int64, limit int) ([]*gen.LightingEvent, error) {
+	var events []*gen.LightingEvent
+	err := s.db.ReadTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, "SELECT id, payload FROM light_events WHERE id > ? ORDER BY id LIMIT ?;", afterID, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				id      int64
+				payload []byte
+			)
+			if err := rows.Scan(&id, &payload); err != nil {
+				return err
+			}
+			event := &gen.LightingEvent{}
+			if err := proto.Unmarshal(payload, event); err != nil {
+				return fmt.Errorf("event %d: %w", id, err)
+			}
+			event.Id = strconv.FormatInt(id, 10)
+			events = append(events, event)
+		}
+		return rows.Err()
+	})
+	return events, err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanLight(row scanner) (light, error) {
+	var (
+		l                                       light
+		firstSeen, update                       int64
+		faults                                  string
+		lastFunction, lastDuration, activeStart sql.NullInt64
+		lastStartFailure                        sql.NullInt64
+		activeTest                              int
+	)
+	err := row.Scan(&l.Name, &firstSeen, &update, &faults, &lastFunction, &lastDuration, &activeTest, &activeStart, &lastStartFailure)
+	if err != nil {
+		return light{}, err
+	}
+	l.FirstSeenTime = time.UnixMilli(firstSeen)
+	l.UpdateTime = time.UnixMilli(update)
+	l.Faults, err = parseFaults(faults)
+	if err != nil {
+		return light{}, err
+	}
+	l.LastFunctionTest = fromNullMillis(lastFunction)
+	l.LastDurationTest = fromNullMillis(lastDuration)
+	l.ActiveTest = testKind(activeTest)
+	l.ActiveTestStart = fromNullMillis(activeStart)
+	l.LastStartFailure = fromNullMillis(lastStartFailure)
+	return l, nil
+}
+
+func formatFaults(faults []gen.LightFault) string {
+	strs := make([]string, len(faults))
+	for i, f := range faults {
+		strs[i] = strconv.Itoa(int(f))
+	}
+	return strings.Join(strs, ",")
+}
+
+func parseFaults(s string) ([]gen.LightFault, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var faults []gen.LightFault
+	for _, str := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(str)
+		if err != nil {
+			return nil, fmt.Errorf("fault %q: %w", str, err)
+		}
+		faults = append(faults, gen.LightFault(n))
+	}
+	slices.Sort(faults)
+	return faults, nil
+}
+
+func nullMillis(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.UnixMilli(), Valid: true}
+}
+
+func fromNullMillis(n sql.NullInt64) time.Time {
+	if !n.Valid {
+		return time.Time{}
+	}
+	return time.UnixMilli(n.Int64)
+}
+
+func optTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
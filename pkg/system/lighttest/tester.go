@@ -0,0 +1,153 @@
+package lighttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+// resultSkew allows for device clocks being a little behind ours when deciding if a result is for the test we started.
+const resultSkew = time.Minute
+
+// tester starts emergency tests on a device and reads their results.
+type tester interface {
+	startTest(ctx context.Context, name string, kind testKind) error
+	// testResult returns the result of the latest test of kind that completed after since.
+	// If the test hasn't completed yet, ok is false.
+	testResult(ctx context.Context, name string, kind testKind, since time.Time) (res testResult, ok bool, err error)
+}
+
+// testResult is the outcome of a completed test.
+type testResult struct {
+	Pass    bool
+	EndTime time.Time
+	// Duration is how long the light ran on battery, only known for some duration tests.
+	Duration time.Duration
+	// Faults found by the test, not including the fault for the test failing.
+	Faults []gen.LightFault
+}
+
+// emergencyLightTester tests devices using the EmergencyLightApi.
+type emergencyLightTester struct {
+	client gen.EmergencyLightApiClient
+}
+
+func (t emergencyLightTester) startTest(ctx context.Context, name string, kind testKind) error {
+	req := &gen.StartEmergencyTestRequest{Name: name}
+	var err error
+	switch kind {
+	case functionTest:
+		_, err = t.client.StartFunctionTest(ctx, req)
+	case durationTest:
+		_, err = t.client.StartDurationTest(ctx, req)
+	default:
+		err = fmt.Errorf("unknown test %v", kind)
+	}
+	return err
+}
+
+func (t emergencyLightTester) testResult(ctx context.Context, name string, kind testKind, since time.Time) (testResult, bool, error) {
+	set, err := t.client.GetTestResultSet(ctx, &gen.GetTestResultSetRequest{Name: name, QueryDevice: true})
+	if err != nil {
+		return testResult{}, false, err
+	}
+	res := set.GetFunctionTest()
+	if kind == durationTest {
+		res = set.GetDurationTest()
+	}
+	switch res.GetResult() {
+	case gen.EmergencyTestResult_TEST_RESULT_UNSPECIFIED, gen.EmergencyTestResult_TEST_RESULT_PENDING:
+		return testResult{}, false, nil
+	}
+	var end time.Time
+	switch {
+	case res.GetEndTime() != nil:
+		end = res.GetEndTime().AsTime()
+	case res.GetStartTime() != nil:
+		end = res.GetStartTime().AsTime()
+	}
+	if !end.IsZero() && end.Before(since.Add(-resultSkew)) {
+		return testResult{}, false, nil // an older result
+	}
+	return testResult{
+		Pass:     res.GetResult() == gen.EmergencyTestResult_TEST_PASSED,
+		EndTime:  end,
+		Duration: res.GetDuration().AsDuration(),
+		Faults:   emergencyResultFaults(res.GetResult()),
+	}, true, nil
+}
+
+func emergencyResultFaults(r gen.EmergencyTestResult_Result) []gen.LightFault {
+	switch r {
+	case gen.EmergencyTestResult_BATTERY_DURATION_FAILURE, gen.EmergencyTestResult_BATTERY_FAILURE:
+		return []gen.LightFault{gen.LightFault_BATTERY_FAULT}
+	case gen.EmergencyTestResult_LAMP_FAILURE:
+		return []gen.LightFault{gen.LightFault_LAMP_FAULT}
+	case gen.EmergencyTestResult_COMMUNICATION_FAILURE:
+		return []gen.LightFault{gen.LightFault_COMMUNICATION_FAILURE}
+	case gen.EmergencyTestResult_CIRCUIT_FAILURE, gen.EmergencyTestResult_LIGHT_FAULTY, gen.EmergencyTestResult_OTHER_FAULT:
+		return []gen.LightFault{gen.LightFault_OTHER_FAULT}
+	}
+	return nil
+}
+
+// daliTester tests devices using the DaliApi.
+type daliTester struct {
+	client gen.DaliApiClient
+}
+
+func (t daliTester) startTest(ctx context.Context, name string, kind testKind) error {
+	test, err := daliTest(kind)
+	if err != nil {
+		return err
+	}
+	_, err = t.client.StartTest(ctx, &gen.StartTestRequest{Name: name, Test: test})
+	return err
+}
+
+func (t daliTester) testResult(ctx context.Context, name string, kind testKind, since time.Time) (testResult, bool, error) {
+	test, err := daliTest(kind)
+	if err != nil {
+		return testResult{}, false, err
+	}
+	res, err := t.client.GetTestResult(ctx, &gen.GetTestResultRequest{Name: name, Test: test})
+	if err != nil {
+		return testResult{}, false, err
+	}
+	if res.GetEndTime() == nil || res.GetEndTime().AsTime().Before(since.Add(-resultSkew)) {
+		// dali lights remember their last result, which may be from before we started the test
+		return testResult{}, false, nil
+	}
+	return testResult{
+		Pass:     res.GetPass(),
+		EndTime:  res.GetEndTime().AsTime(),
+		Duration: res.GetDuration().AsDuration(),
+		Faults:   daliFailureFaults(res.GetFailureReason()),
+	}, true, nil
+}
+
+func daliTest(kind testKind) (gen.EmergencyStatus_Test, error) {
+	switch kind {
+	case functionTest:
+		return gen.EmergencyStatus_FUNCTION_TEST, nil
+	case durationTest:
+		return gen.EmergencyStatus_DURATION_TEST, nil
+	default:
+		return 0, errors.New("unknown test")
+	}
+}
+
+func daliFailureFaults(f gen.EmergencyStatus_Failure) []gen.LightFault {
+	switch f {
+	case gen.EmergencyStatus_BATTERY_DURATION_FAILURE, gen.EmergencyStatus_BATTERY_FAILURE:
+		return []gen.LightFault{gen.LightFault_BATTERY_FAULT}
+	case gen.EmergencyStatus_LAMP_FAILURE:
+		return []gen.LightFault{gen.LightFault_LAMP_FAULT}
+	case gen.EmergencyStatus_CIRCUIT_FAILURE:
+		return []gen.LightFault{gen.LightFault_OTHER_FAULT}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/system"
+	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
+)
+
+const (
+	DefaultDownloadExpiry = time.Hour
+	DefaultMaxCount       = 24
+)
+
+var DefaultSchedule = jsontypes.MustParseSchedule("0 2 1 * *") // 2am on the first of each month
+
+type Root struct {
+	system.Config
+	// Name is the Smart Core name the ReportApi is announced on.
+	// Defaults to the controller name.
+	Name string `json:"name,omitempty"`
+	// Reports lists the reports to generate.
+	Reports []Report `json:"reports,omitempty"`
+	// Retention limits how many generated reports are kept on disk, per report.
+	Retention *Retention `json:"retention,omitempty"`
+	// DownloadExpiry is how long download urls are valid for, defaults to 1 hour.
+	DownloadExpiry *jsontypes.Duration `json:"downloadExpiry,omitempty"`
+}
+
+type Retention struct {
+	// MaxAge removes reports older than this.
+	MaxAge jsontypes.Duration `json:"maxAge,omitempty"`
+	// MaxCount keeps at most this many of each report, defaults to 24.
+	MaxCount int `json:"maxCount,omitempty"`
+}
+
+type ReportType string
+
+const (
+	// ReportTypeMeter reports the consumption of each meter over the period, using MeterHistory.
+	ReportTypeMeter ReportType = "meter"
+	// ReportTypeAlerts summarises the alerts created during the period, using the AlertApi.
+	ReportTypeAlerts ReportType = "alerts"
+	// ReportTypeOccupancy reports how much of the period each space was occupied for, using OccupancySensorHistory.
+	ReportTypeOccupancy ReportType = "occupancy"
+	// ReportTypeUtilisation reports the business hours utilisation of each space, using the OccupancyUtilisationApi.
+	ReportTypeUtilisation ReportType = "utilisation"
+)
+
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodWeek  Period = "week"
+	PeriodMonth Period = "month"
+	PeriodYear  Period = "year"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatHTML Format = "html"
+)
+
+type Report struct {
+	// ID identifies the report, it is used in file names and must be unique within the system.
+	ID          string     `json:"id,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Type        ReportType `json:"type,omitempty"`
+	// Schedule is when the report is generated, defaults to 2am on the first of each month.
+	Schedule *jsontypes.Schedule `json:"schedule,omitempty"`
+	// Period is the calendar period the report covers, the one before the period the report is generated in.
+	// Defaults to month.
+	Period Period `json:"period,omitempty"`
+	// Formats are the formats each report is rendered in, defaults to csv.
+	Formats []Format `json:"formats,omitempty"`
+
+	// Devices are the names of devices to include, meters or occupancy sensors depending on Type.
+	// If absent all devices that implement the relevant trait are included.
+	Devices []string `json:"devices,omitempty"`
+	// GroupBy totals the rows of the report by device metadata, one of "floor" or "zone".
+	GroupBy string `json:"groupBy,omitempty"`
+	// Source is the name of the AlertApi device for alert reports, defaults to the controller name.
+	Source string `json:"source,omitempty"`
+	// Calendar is the name of the business hours calendar for utilisation reports,
+	// defaults to the default calendar of the utilisation system.
+	Calendar string `json:"calendar,omitempty"`
+}
+
+var idPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Validate returns an error if the config isn't valid.
+func (r Root) Validate() error {
+	ids := make(map[string]struct{}, len(r.Reports))
+	for i, report := range r.Reports {
+		if report.ID == "" {
+			return fmt.Errorf("reports[%d].id is required", i)
+		}
+		if !idPattern.MatchString(report.ID) {
+			return fmt.Errorf("reports[%d].id %q must only contain letters, digits, - and _", i, report.ID)
+		}
+		if _, ok := ids[report.ID]; ok {
+			return fmt.Errorf("reports[%d].id %q is repeated", i, report.ID)
+		}
+		ids[report.ID] = struct{}{}
+		switch report.Type {
+		case ReportTypeMeter, ReportTypeAlerts, ReportTypeOccupancy, ReportTypeUtilisation:
+		default:
+			return fmt.Errorf("reports[%d].type %q is not supported", i, report.Type)
+		}
+		switch report.Period {
+		case "", PeriodDay, PeriodWeek, PeriodMonth, PeriodYear:
+		default:
+			return fmt.Errorf("reports[%d].period %q is not supported", i, report.Period)
+		}
+		for _, f := range report.Formats {
+			switch f {
+			case FormatCSV, FormatHTML:
+			default:
+				return fmt.Errorf("reports[%d].formats %q is not supported", i, f)
+			}
+		}
+		switch report.GroupBy {
+		case "", "floor", "zone":
+		default:
+			return fmt.Errorf("reports[%d].groupBy %q is not supported", i, report.GroupBy)
+		}
+	}
+	return nil
+}
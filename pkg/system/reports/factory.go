@@ -0,0 +1,209 @@
+// Package reports provides a system that generates reports on a schedule and serves them via the ReportApi.
+// Reports summarise meter consumption, alerts, or occupancy over a calendar period,
+// are rendered as CSV or HTML, and are stored in the data directory until retention removes them.
+package reports
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/report"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/system"
+	"github.com/smart-core-os/sc-bos/pkg/system/reports/config"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+)
+
+const (
+	// DownloadPath is the http path generated reports are downloaded from.
+	DownloadPath = "/dl/reports"
+	// reportsDir is the directory reports are stored in, relative to the data dir.
+	reportsDir = "reports"
+)
+
+func Factory() system.Factory {
+	return &factory{
+		download: &nextOrNotFound{},
+	}
+}
+
+type factory struct {
+	handleOnce sync.Once // ensures we only mux.Handle once - otherwise it would panic
+	download   *nextOrNotFound
+}
+
+func (f *factory) New(services system.Services) service.Lifecycle {
+	f.handleOnce.Do(func() {
+		services.HTTPMux.Handle(DownloadPath, f.download)
+	})
+	logger := services.Logger.Named("reports")
+	s := &System{
+		node:         services.Node,
+		dataDir:      services.DataDir,
+		httpEndpoint: services.HTTPEndpoint,
+		download:     f.download,
+		logger:       logger,
+		now:          time.Now,
+	}
+	s.Service = service.New(
+		service.MonoApply(s.applyConfig),
+		service.WithRetry[config.Root](service.RetryWithLogger(func(logContext service.RetryContext) {
+			logContext.LogTo("applyConfig", logger)
+		})),
+		service.WithOnStop[config.Root](func() {
+			s.download.Clear()
+		}),
+	)
+	return s
+}
+
+type System struct {
+	*service.Service[config.Root]
+	node         *node.Node
+	dataDir      string
+	httpEndpoint string
+	download     *nextOrNotFound
+	logger       *zap.Logger
+	now          func() time.Time
+}
+
+func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	store, err := newStore(filepath.Join(s.dataDir, reportsDir))
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+
+	downloadUrlBase := url.URL{Path: DownloadPath}
+	if s.httpEndpoint != "" {
+		downloadUrlBase = url.URL{Scheme: "https", Host: s.httpEndpoint, Path: DownloadPath}
+	}
+	srv := newServer(store, downloadUrlBase, cfg.DownloadExpiry.Or(config.DefaultDownloadExpiry))
+	name := cmp.Or(cfg.Name, s.node.Name())
+	undo := s.node.Announce(name, node.HasTrait(report.TraitName, node.WithClients(gen.WrapReportApi(srv))))
+	s.download.Next(srv)
+
+	g := &generator{
+		conn:          s.node.ClientConn(),
+		devices:       s.node,
+		defaultSource: s.node.Name(),
+	}
+	for _, r := range cfg.Reports {
+		go s.runSchedule(ctx, g, store, r, cfg.Retention)
+	}
+	go func() {
+		<-ctx.Done()
+		undo()
+	}()
+	return nil
+}
+
+// runSchedule generates the report described by cfg each time its schedule fires, until ctx is done.
+func (s *System) runSchedule(ctx context.Context, g *generator, store *store, cfg config.Report, retention *config.Retention) {
+	sched := cfg.Schedule
+	if sched == nil {
+		sched = config.DefaultSchedule
+	}
+	logger := s.logger.With(zap.String("report", cfg.ID))
+	t := s.now()
+	for {
+		next := sched.Next(t)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+			// Use the time we were planning on running instead of the current time.
+			// We do this to make the reported period predictable.
+			t = next
+		}
+
+		if err := generateReport(ctx, g, store, cfg, t); err != nil {
+			logger.Warn("failed to generate report", zap.Error(err))
+		}
+		if err := trimReports(store, cfg, retention, s.now()); err != nil {
+			logger.Warn("failed to remove old reports", zap.Error(err))
+		}
+	}
+}
+
+// generateReport generates the report described by cfg for the period before t, saving each format to store.
+func generateReport(ctx context.Context, g *generator, store *store, cfg config.Report, t time.Time) error {
+	start, end := periodBounds(cfg.Period, t)
+	tbl, err := g.generate(ctx, cfg, start, end)
+	if err != nil {
+		return err
+	}
+	for _, format := range formats(cfg) {
+		data, err := render(tbl, format)
+		if err != nil {
+			return fmt.Errorf("render %s: %w", format, err)
+		}
+		err = store.save(storedReport{
+			ID:          fmt.Sprintf("%s-%s.%s", cfg.ID, t.Format("20060102T150405"), format),
+			ReportID:    cfg.ID,
+			Title:       tbl.Title,
+			Description: tbl.Description,
+			CreateTime:  t,
+			MediaType:   mediaTypes[format],
+		}, data)
+		if err != nil {
+			return fmt.Errorf("save %s: %w", format, err)
+		}
+	}
+	return nil
+}
+
+func trimReports(store *store, cfg config.Report, retention *config.Retention, now time.Time) error {
+	var maxAge time.Duration
+	maxCount := config.DefaultMaxCount
+	if retention != nil {
+		maxAge = retention.MaxAge.Duration
+		if retention.MaxCount != 0 {
+			maxCount = retention.MaxCount
+		}
+	}
+	for _, format := range formats(cfg) {
+		if err := store.trim(cfg.ID, mediaTypes[format], maxAge, maxCount, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formats(cfg config.Report) []config.Format {
+	if len(cfg.Formats) == 0 {
+		return []config.Format{config.FormatCSV}
+	}
+	return cfg.Formats
+}
+
+// periodBounds returns the calendar period before the one containing t.
+// Weeks start on Monday.
+func periodBounds(p config.Period, t time.Time) (start, end time.Time) {
+	y, m, d := t.Date()
+	switch p {
+	case config.PeriodDay:
+		end = time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+		return end.AddDate(0, 0, -1), end
+	case config.PeriodWeek:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		end = time.Date(y, m, d-daysSinceMonday, 0, 0, 0, 0, t.Location())
+		return end.AddDate(0, 0, -7), end
+	case config.PeriodYear:
+		end = time.Date(y, time.January, 1, 0, 0, 0, 0, t.Location())
+		return end.AddDate(-1, 0, 0), end
+	default: // month
+		end = time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+		return end.AddDate(0, -1, 0), end
+	}
+}
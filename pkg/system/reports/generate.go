@@ -0,0 +1,435 @@
+package reports
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/devicespb"
+	"github.com/smart-core-os/sc-bos/pkg/gentrait/meter"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread"
+	"github.com/smart-core-os/sc-bos/pkg/system/reports/config"
+	"github.com/smart-core-os/sc-golang/pkg/resource"
+	"github.com/smart-core-os/sc-golang/pkg/trait"
+)
+
+// table is the content of a generated report, before it is rendered to a particular format.
+type table struct {
+	Title       string
+	Description string
+	Start, End  time.Time
+	Columns     []string
+	Rows        [][]string
+	// Notes describe problems generating the report, like devices that couldn't be read.
+	Notes []string
+}
+
+// deviceLister lists devices known to the controller.
+type deviceLister interface {
+	ListDevices(opts ...resource.ReadOption) []*gen.Device
+}
+
+// generator produces report tables by querying Smart Core APIs.
+type generator struct {
+	conn          grpc.ClientConnInterface
+	devices       deviceLister
+	defaultSource string
+}
+
+func (g *generator) generate(ctx context.Context, cfg config.Report, start, end time.Time) (table, error) {
+	t := table{
+		Title:       cmp.Or(cfg.Title, cfg.ID),
+		Description: cfg.Description,
+		Start:       start,
+		End:         end,
+	}
+	var err error
+	switch cfg.Type {
+	case config.ReportTypeMeter:
+		err = g.meterReport(ctx, cfg, &t)
+	case config.ReportTypeAlerts:
+		err = g.alertsReport(ctx, cfg, &t)
+	case config.ReportTypeOccupancy:
+		err = g.occupancyReport(ctx, cfg, &t)
+	case config.ReportTypeUtilisation:
+		err = g.utilisationReport(ctx, cfg, &t)
+	default:
+		err = fmt.Errorf("unsupported report type %q", cfg.Type)
+	}
+	return t, err
+}
+
+// findDevices returns the devices named in cfg, or all devices with traitName if none are named.
+func (g *generator) findDevices(cfg config.Report, traitName trait.Name) []*gen.Device {
+	if len(cfg.Devices) == 0 {
+		return g.devices.ListDevices(resource.WithInclude(func(_ string, item proto.Message) bool {
+			device, ok := item.(*gen.Device)
+			return ok && devicespb.HasTrait(device, traitName)
+		}))
+	}
+	named := make(map[string]struct{}, len(cfg.Devices))
+	for _, name := range cfg.Devices {
+		named[name] = struct{}{}
+	}
+	found := g.devices.ListDevices(resource.WithInclude(func(id string, _ proto.Message) bool {
+		_, ok := named[id]
+		return ok
+	}))
+	// devices we don't know about can still be queried, they just have no metadata
+	for _, d := range found {
+		delete(named, d.Name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(named)) {
+		found = append(found, &gen.Device{Name: name})
+	}
+	return found
+}
+
+// groupKey returns the value of the groupBy metadata for device.
+func groupKey(device *gen.Device, groupBy string) string {
+	loc := device.GetMetadata().GetLocation()
+	switch groupBy {
+	case "floor":
+		return loc.GetFloor()
+	case "zone":
+		return loc.GetZone()
+	}
+	return ""
+}
+
+func period(start, end time.Time) *timepb.Period {
+	return &timepb.Period{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)}
+}
+
+// meterReport reports the consumption of each meter, the difference between the first and last reading in the period.
+func (g *generator) meterReport(ctx context.Context, cfg config.Report, t *table) error {
+	client := gen.NewMeterHistoryClient(g.conn)
+	readingAt := func(ctx context.Context, name, order string) (*gen.MeterReadingRecord, error) {
+		res, err := client.ListMeterReadingHistory(ctx, &gen.ListMeterReadingHistoryRequest{
+			Name:     name,
+			Period:   period(t.Start, t.End),
+			PageSize: 1,
+			OrderBy:  order,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(res.MeterReadingRecords) == 0 {
+			return nil, nil
+		}
+		return res.MeterReadingRecords[0], nil
+	}
+
+	type row struct {
+		device      *gen.Device
+		first, last float32
+	}
+	var rows []row
+	for _, device := range g.findDevices(cfg, meter.TraitName) {
+		first, err := readingAt(ctx, device.Name, "record_time asc")
+		if err != nil {
+			t.Notes = append(t.Notes, fmt.Sprintf("%s: %v", device.Name, err))
+			continue
+		}
+		last, err := readingAt(ctx, device.Name, "record_time desc")
+		if err != nil {
+			t.Notes = append(t.Notes, fmt.Sprintf("%s: %v", device.Name, err))
+			continue
+		}
+		if first == nil || last == nil {
+			t.Notes = append(t.Notes, fmt.Sprintf("%s: no readings in period", device.Name))
+			continue
+		}
+		rows = append(rows, row{device, first.GetMeterReading().GetUsage(), last.GetMeterReading().GetUsage()})
+	}
+
+	if cfg.GroupBy == "" {
+		t.Columns = []string{"Name", "Floor", "Zone", "Start Reading", "End Reading", "Consumption"}
+		for _, r := range rows {
+			loc := r.device.GetMetadata().GetLocation()
+			t.Rows = append(t.Rows, []string{r.device.Name, loc.GetFloor(), loc.GetZone(),
+				formatFloat(r.first), formatFloat(r.last), formatFloat(r.last - r.first)})
+		}
+		return nil
+	}
+
+	type group struct {
+		meters int
+		total  float32
+	}
+	groups := make(map[string]*group)
+	for _, r := range rows {
+		key := groupKey(r.device, cfg.GroupBy)
+		grp, ok := groups[key]
+		if !ok {
+			grp = &group{}
+			groups[key] = grp
+		}
+		grp.meters++
+		grp.total += r.last - r.first
+	}
+	t.Columns = []string{groupTitle(cfg.GroupBy), "Meters", "Consumption"}
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		grp := groups[key]
+		t.Rows = append(t.Rows, []string{key, strconv.Itoa(grp.meters), formatFloat(grp.total)})
+	}
+	return nil
+}
+
+// alertsReport counts the alerts created in the period by severity, and optionally floor or zone.
+func (g *generator) alertsReport(ctx context.Context, cfg config.Report, t *table) error {
+	client := gen.NewAlertApiClient(g.conn)
+	type key struct {
+		group    string
+		severity gen.Alert_Severity
+	}
+	type counts struct {
+		created, acknowledged, resolved int
+	}
+	groups := make(map[key]*counts)
+	req := &gen.ListAlertsRequest{
+		Name:     cmp.Or(cfg.Source, g.defaultSource),
+		PageSize: 1000,
+		Query: &gen.Alert_Query{
+			CreatedNotBefore: timestamppb.New(t.Start),
+			CreatedNotAfter:  timestamppb.New(t.End),
+		},
+	}
+	for {
+		res, err := client.ListAlerts(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, alert := range res.Alerts {
+			k := key{severity: alert.Severity}
+			switch cfg.GroupBy {
+			case "floor":
+				k.group = alert.Floor
+			case "zone":
+				k.group = alert.Zone
+			}
+			c, ok := groups[k]
+			if !ok {
+				c = &counts{}
+				groups[k] = c
+			}
+			c.created++
+			if alert.Acknowledgement != nil {
+				c.acknowledged++
+			}
+			if alert.ResolveTime != nil {
+				c.resolved++
+			}
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
+	}
+
+	keys := slices.SortedFunc(maps.Keys(groups), func(a, b key) int {
+		return cmp.Or(cmp.Compare(a.group, b.group), cmp.Compare(b.severity, a.severity))
+	})
+	t.Columns = []string{"Severity", "Created", "Acknowledged", "Resolved"}
+	if cfg.GroupBy != "" {
+		t.Columns = append([]string{groupTitle(cfg.GroupBy)}, t.Columns...)
+	}
+	for _, k := range keys {
+		c := groups[k]
+		row := []string{k.severity.String(), strconv.Itoa(c.created), strconv.Itoa(c.acknowledged), strconv.Itoa(c.resolved)}
+		if cfg.GroupBy != "" {
+			row = append([]string{k.group}, row...)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return nil
+}
+
+// occupancyReport reports the fraction of the period each space was occupied, and the peak people count.
+func (g *generator) occupancyReport(ctx context.Context, cfg config.Report, t *table) error {
+	client := gen.NewOccupancySensorHistoryClient(g.conn)
+	type row struct {
+		device   *gen.Device
+		occupied time.Duration
+		peak     int32
+	}
+	var rows []row
+	for _, device := range g.findDevices(cfg, trait.OccupancySensor) {
+		occupied, peak, err := occupancyUse(ctx, client, device.Name, t.Start, t.End)
+		if err != nil {
+			t.Notes = append(t.Notes, fmt.Sprintf("%s: %v", device.Name, err))
+			continue
+		}
+		rows = append(rows, row{device, occupied, peak})
+	}
+
+	total := t.End.Sub(t.Start)
+	if cfg.GroupBy == "" {
+		t.Columns = []string{"Name", "Floor", "Zone", "Utilisation %", "Occupied Hours", "Peak People"}
+		for _, r := range rows {
+			loc := r.device.GetMetadata().GetLocation()
+			t.Rows = append(t.Rows, []string{r.device.Name, loc.GetFloor(), loc.GetZone(),
+				formatPercent(r.occupied, total), formatHours(r.occupied), strconv.Itoa(int(r.peak))})
+		}
+		return nil
+	}
+
+	type group struct {
+		sensors  int
+		occupied time.Duration
+		peak     int32
+	}
+	groups := make(map[string]*group)
+	for _, r := range rows {
+		key := groupKey(r.device, cfg.GroupBy)
+		grp, ok := groups[key]
+		if !ok {
+			grp = &group{}
+			groups[key] = grp
+		}
+		grp.sensors++
+		grp.occupied += r.occupied
+		grp.peak = max(grp.peak, r.peak)
+	}
+	t.Columns = []string{groupTitle(cfg.GroupBy), "Sensors", "Utilisation %", "Peak People"}
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		grp := groups[key]
+		t.Rows = append(t.Rows, []string{key, strconv.Itoa(grp.sensors),
+			formatPercent(grp.occupied, total*time.Duration(grp.sensors)), strconv.Itoa(int(grp.peak))})
+	}
+	return nil
+}
+
+// occupancyUse returns how long the named sensor was occupied between start and end, and the peak people count.
+// The state at start is the state of the last record before start.
+func occupancyUse(ctx context.Context, client gen.OccupancySensorHistoryClient, name string, start, end time.Time) (time.Duration, int32, error) {
+	segments, err := historyread.OccupancySegments(ctx, client, name, start, end)
+	if err != nil {
+		return 0, 0, err
+	}
+	var peak int32
+	for _, s := range segments {
+		peak = max(peak, s.PeopleCount)
+	}
+	return historyread.Occupied(segments), peak, nil
+}
+
+// utilisationReport reports the business hours utilisation of each space, computed by the OccupancyUtilisationApi.
+func (g *generator) utilisationReport(ctx context.Context, cfg config.Report, t *table) error {
+	client := gen.NewOccupancyUtilisationApiClient(g.conn)
+	type row struct {
+		device *gen.Device
+		u      *gen.OccupancyUtilisation
+	}
+	var rows []row
+	for _, device := range g.findDevices(cfg, trait.OccupancySensor) {
+		u, err := client.GetOccupancyUtilisation(ctx, &gen.GetOccupancyUtilisationRequest{
+			DeviceName: device.Name,
+			Period:     period(t.Start, t.End),
+			Calendar:   cfg.Calendar,
+		})
+		if err != nil {
+			t.Notes = append(t.Notes, fmt.Sprintf("%s: %v", device.Name, err))
+			continue
+		}
+		rows = append(rows, row{device, u})
+	}
+
+	if cfg.GroupBy == "" {
+		t.Columns = []string{"Name", "Floor", "Zone", "Utilisation %", "Occupied Hours", "Business Hours",
+			"Average People", "Peak People", "Bookings", "No-Show %"}
+		for _, r := range rows {
+			loc := r.device.GetMetadata().GetLocation()
+			occupied, business := r.u.OccupiedDuration.AsDuration(), r.u.BusinessDuration.AsDuration()
+			bookings, noShow := "", ""
+			if b := r.u.Bookings; b != nil {
+				bookings = strconv.Itoa(int(b.BookingCount))
+				noShow = formatRatio(int(b.NoShowCount), int(b.BookingCount))
+			}
+			t.Rows = append(t.Rows, []string{r.device.Name, loc.GetFloor(), loc.GetZone(),
+				formatPercent(occupied, business), formatHours(occupied), formatHours(business),
+				formatFloat(r.u.AveragePeopleCount), strconv.Itoa(int(r.u.PeakPeopleCount)), bookings, noShow})
+		}
+		return nil
+	}
+
+	type group struct {
+		spaces             int
+		occupied, business time.Duration
+		peopleHours        float64
+		peak               int32
+		bookings, noShows  int
+	}
+	groups := make(map[string]*group)
+	for _, r := range rows {
+		key := groupKey(r.device, cfg.GroupBy)
+		grp, ok := groups[key]
+		if !ok {
+			grp = &group{}
+			groups[key] = grp
+		}
+		business := r.u.BusinessDuration.AsDuration()
+		grp.spaces++
+		grp.occupied += r.u.OccupiedDuration.AsDuration()
+		grp.business += business
+		grp.peopleHours += float64(r.u.AveragePeopleCount) * business.Hours()
+		grp.peak = max(grp.peak, r.u.PeakPeopleCount)
+		grp.bookings += int(r.u.GetBookings().GetBookingCount())
+		grp.noShows += int(r.u.GetBookings().GetNoShowCount())
+	}
+	t.Columns = []string{groupTitle(cfg.GroupBy), "Spaces", "Utilisation %", "Average People", "Peak People",
+		"Bookings", "No-Show %"}
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		grp := groups[key]
+		var avg float32
+		if grp.business > 0 {
+			avg = float32(grp.peopleHours / grp.business.Hours())
+		}
+		t.Rows = append(t.Rows, []string{key, strconv.Itoa(grp.spaces), formatPercent(grp.occupied, grp.business),
+			formatFloat(avg), strconv.Itoa(int(grp.peak)), strconv.Itoa(grp.bookings), formatRatio(grp.noShows, grp.bookings)})
+	}
+	return nil
+}
+
+func groupTitle(groupBy string) string {
+	switch groupBy {
+	case "floor":
+		return "Floor"
+	case "zone":
+		return "Zone"
+	}
+	return groupBy
+}
+
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'f', 2, 32)
+}
+
+func formatPercent(part, total time.Duration) string {
+	if total <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(100*float64(part)/float64(total), 'f', 1, 64)
+}
+
+// formatRatio formats part of total as a percentage, or the empty string if total is zero.
+func formatRatio(part, total int) string {
+	if total <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(100*float64(part)/float64(total), 'f', 1, 64)
+}
+
+func formatHours(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours(), 'f', 1, 64)
+}
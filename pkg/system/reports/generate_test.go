@@ -0,0 +1,52 @@
+package reports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread/historyreadtest"
+)
+
+func TestOccupancyUse(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	record := func(at time.Duration, state traits.Occupancy_State, people int32) *gen.OccupancyRecord {
+		return historyreadtest.Occupancy(start.Add(at), state, people)
+	}
+	client := historyreadtest.OccupancyHistory{"sensor": {
+		record(-time.Hour, traits.Occupancy_OCCUPIED, 2), // occupied at the start of the period
+		record(2*time.Hour, traits.Occupancy_UNOCCUPIED, 0),
+		record(9*time.Hour, traits.Occupancy_OCCUPIED, 12),
+		record(17*time.Hour, traits.Occupancy_UNOCCUPIED, 0),
+		record(22*time.Hour, traits.Occupancy_OCCUPIED, 1), // still occupied at the end
+	}}
+
+	occupied, peak, err := occupancyUse(context.Background(), client, "sensor", start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 12 * time.Hour; occupied != want {
+		t.Errorf("occupied got %v, want %v", occupied, want)
+	}
+	if peak != 12 {
+		t.Errorf("peak got %d, want 12", peak)
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	got, err := renderCSV(table{
+		Columns: []string{"Name", "Consumption"},
+		Rows:    [][]string{{"meter/1", "12.50"}, {"meter, 2", "3.00"}},
+		Notes:   []string{"meter/3: unavailable"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Name,Consumption\nmeter/1,12.50\n\"meter, 2\",3.00\n"
+	if string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/system/reports/config"
+)
+
+// mediaTypes maps output formats to the media type of the rendered report.
+var mediaTypes = map[config.Format]string{
+	config.FormatCSV:  "text/csv",
+	config.FormatHTML: "text/html",
+}
+
+func render(t table, format config.Format) ([]byte, error) {
+	switch format {
+	case config.FormatCSV:
+		return renderCSV(t)
+	case config.FormatHTML:
+		return renderHTML(t)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// renderCSV writes the table as CSV with a header row.
+// Notes are not included, so the output can be processed by other tools.
+func renderCSV(t table) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(t.Columns)
+	_ = w.WriteAll(t.Rows) // calls Flush
+	return buf.Bytes(), w.Error()
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("2 January 2006 15:04") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.75em; text-align: left; }
+th { background: #eee; }
+.notes { color: #a00; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{with .Description}}<p>{{.}}</p>{{end}}
+<p>{{date .Start}} to {{date .End}}</p>
+<table>
+<thead><tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+{{with .Notes}}<h2>Notes</h2>
+<ul class="notes">{{range .}}<li>{{.}}</li>{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+func renderHTML(t table) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, t); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
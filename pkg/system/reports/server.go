@@ -0,0 +1,189 @@
+package reports
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+
+	// tokenParam is the url query parameter holding the download token.
+	tokenParam = "rdt"
+)
+
+type tokenClaims struct {
+	ID string `json:"rid"`
+}
+
+// server implements gen.ReportApiServer backed by a store of generated reports.
+// Reports are downloaded via ServeHTTP using the signed urls returned by GetDownloadReportUrl.
+type server struct {
+	gen.UnimplementedReportApiServer
+
+	store           *store
+	downloadUrlBase url.URL
+	downloadExpiry  time.Duration
+	expiryLeeway    time.Duration
+	now             func() time.Time
+
+	key    []byte
+	keyErr error
+}
+
+func newServer(store *store, downloadUrlBase url.URL, downloadExpiry time.Duration) *server {
+	key := make([]byte, 64)
+	_, err := rand.Read(key)
+	return &server{
+		store:           store,
+		downloadUrlBase: downloadUrlBase,
+		downloadExpiry:  downloadExpiry,
+		expiryLeeway:    time.Minute,
+		now:             time.Now,
+		key:             key,
+		keyErr:          err,
+	}
+}
+
+func (s *server) ListReports(_ context.Context, request *gen.ListReportsRequest) (*gen.ListReportsResponse, error) {
+	var offset int
+	if request.PageToken != "" {
+		var err error
+		offset, err = strconv.Atoi(request.PageToken)
+		if err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+	}
+	pageSize := int(request.PageSize)
+	switch {
+	case pageSize <= 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	reports, err := s.store.list()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "list reports: %v", err)
+	}
+	res := &gen.ListReportsResponse{TotalSize: int32(len(reports))}
+	if offset >= len(reports) {
+		return res, nil
+	}
+	end := min(offset+pageSize, len(reports))
+	for _, r := range reports[offset:end] {
+		res.Reports = append(res.Reports, &gen.Report{
+			Id:          r.ID,
+			Title:       r.Title,
+			Description: r.Description,
+			CreateTime:  timestamppb.New(r.CreateTime),
+			MediaType:   r.MediaType,
+		})
+	}
+	if end < len(reports) {
+		res.NextPageToken = strconv.Itoa(end)
+	}
+	return res, nil
+}
+
+func (s *server) GetDownloadReportUrl(_ context.Context, request *gen.GetDownloadReportUrlRequest) (*gen.DownloadReportUrl, error) {
+	if request.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	report, err := s.store.get(request.Id)
+	if errors.Is(err, errReportNotFound) {
+		return nil, status.Errorf(codes.NotFound, "report %q", request.Id)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "get report: %v", err)
+	}
+
+	expireAfter := s.now().Add(s.downloadExpiry)
+	tokenStr, err := s.signToken(report.ID, expireAfter)
+	if err != nil {
+		return nil, err
+	}
+	u := s.downloadUrlBase
+	q := u.Query()
+	q.Set(tokenParam, tokenStr)
+	u.RawQuery = q.Encode()
+	return &gen.DownloadReportUrl{
+		Url:             u.String(),
+		Filename:        report.ID,
+		MediaType:       report.MediaType,
+		ExpireAfterTime: timestamppb.New(expireAfter),
+	}, nil
+}
+
+// ServeHTTP responds to urls returned by GetDownloadReportUrl with the content of the report.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tokenStr := r.URL.Query().Get(tokenParam)
+	if tokenStr == "" {
+		http.Error(w, "invalid download token", http.StatusUnauthorized)
+		return
+	}
+	id, err := s.parseToken(tokenStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	report, err := s.store.get(id)
+	if errors.Is(err, errReportNotFound) {
+		http.Error(w, "report no longer exists", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": report.ID}))
+	w.Header().Set("Content-Type", report.MediaType+"; charset=utf-8")
+	http.ServeFile(w, r, s.store.path(report.ID))
+}
+
+func (s *server) signToken(id string, expireAfter time.Time) (string, error) {
+	if s.keyErr != nil {
+		return "", status.Errorf(codes.Unavailable, "token key creation error: %v", s.keyErr)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: s.key}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "token signer creation error: %v", err)
+	}
+	jwtClaims := &jwt.Claims{Expiry: jwt.NewNumericDate(expireAfter)}
+	tokenStr, err := jwt.Signed(signer).Claims(tokenClaims{ID: id}).Claims(jwtClaims).Serialize()
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "token serialization error: %v", err)
+	}
+	return tokenStr, nil
+}
+
+// parseToken validates tokenStr and returns the id of the report it grants access to.
+func (s *server) parseToken(tokenStr string) (string, error) {
+	jwtToken, err := jwt.ParseSigned(tokenStr, []jose.SignatureAlgorithm{jose.HS256})
+	if err != nil {
+		return "", errors.New("invalid token")
+	}
+	jwtClaims := &jwt.Claims{}
+	var claims tokenClaims
+	if err := jwtToken.Claims(s.key, jwtClaims, &claims); err != nil {
+		return "", errors.New("untrusted token")
+	}
+	if err := jwtClaims.ValidateWithLeeway(jwt.Expected{Time: s.now()}, s.expiryLeeway); err != nil {
+		return "", errors.New("token expired")
+	}
+	return claims.ID, nil
+}
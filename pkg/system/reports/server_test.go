@@ -0,0 +1,151 @@
+package reports
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/system/reports/config"
+)
+
+func TestServer_GetDownloadReportUrl(t *testing.T) {
+	now := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)
+	st, err := newStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = st.save(storedReport{ID: "energy-1.csv", ReportID: "energy", CreateTime: now, MediaType: "text/csv"}, []byte("a,b\n1,2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newServer(st, url.URL{Path: DownloadPath}, time.Hour)
+	srv.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if _, err := srv.GetDownloadReportUrl(ctx, &gen.GetDownloadReportUrlRequest{Id: "missing.csv"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("missing report: want NotFound, got %v", err)
+	}
+	res, err := srv.GetDownloadReportUrl(ctx, &gen.GetDownloadReportUrlRequest{Id: "energy-1.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Filename != "energy-1.csv" || res.MediaType != "text/csv" {
+		t.Fatalf("unexpected response %v", res)
+	}
+	if got, want := res.ExpireAfterTime.AsTime(), now.Add(time.Hour); !got.Equal(want) {
+		t.Fatalf("expire after got %v, want %v", got, want)
+	}
+
+	get := func(u string) *http.Response {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u, nil))
+		return rec.Result()
+	}
+
+	resp := get(res.Url)
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("download: status %d %s", resp.StatusCode, body)
+	}
+	if string(body) != "a,b\n1,2\n" {
+		t.Fatalf("download: body %q", body)
+	}
+	if got := resp.Header.Get("Content-Disposition"); got != "attachment; filename=energy-1.csv" {
+		t.Fatalf("download: content disposition %q", got)
+	}
+
+	if resp := get(DownloadPath + "?rdt=not-a-token"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("bad token: want 401, got %d", resp.StatusCode)
+	}
+
+	srv.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if resp := get(res.Url); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expired token: want 401, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_ListReports(t *testing.T) {
+	now := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)
+	st, err := newStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range []string{"a.csv", "b.csv", "c.csv"} {
+		err := st.save(storedReport{ID: id, ReportID: "r", CreateTime: now.Add(time.Duration(i) * time.Hour), MediaType: "text/csv"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := newServer(st, url.URL{}, time.Hour)
+
+	var ids []string
+	req := &gen.ListReportsRequest{PageSize: 2}
+	for {
+		res, err := srv.ListReports(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.TotalSize != 3 {
+			t.Fatalf("total size got %d, want 3", res.TotalSize)
+		}
+		for _, r := range res.Reports {
+			ids = append(ids, r.Id)
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		req.PageToken = res.NextPageToken
+	}
+	want := []string{"c.csv", "b.csv", "a.csv"} // newest first
+	if len(ids) != len(want) {
+		t.Fatalf("got %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("got %v, want %v", ids, want)
+		}
+	}
+
+	// retention
+	if err := st.trim("r", "text/csv", 0, 1, now); err != nil {
+		t.Fatal(err)
+	}
+	res, err := srv.ListReports(context.Background(), &gen.ListReportsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Reports) != 1 || res.Reports[0].Id != "c.csv" {
+		t.Fatalf("after trim got %v, want only c.csv", res.Reports)
+	}
+}
+
+func TestPeriodBounds(t *testing.T) {
+	at := time.Date(2024, 3, 6, 2, 0, 0, 0, time.UTC) // a Wednesday
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		period     string
+		start, end time.Time
+	}{
+		{"day", day(2024, 3, 5), day(2024, 3, 6)},
+		{"week", day(2024, 2, 26), day(2024, 3, 4)},
+		{"month", day(2024, 2, 1), day(2024, 3, 1)},
+		{"", day(2024, 2, 1), day(2024, 3, 1)},
+		{"year", day(2023, 1, 1), day(2024, 1, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			start, end := periodBounds(config.Period(tt.period), at)
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Fatalf("got [%v, %v), want [%v, %v)", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package reports
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metaExt is appended to the file name of each report to name the file describing it.
+const metaExt = ".meta.json"
+
+var errReportNotFound = errors.New("report not found")
+
+// storedReport describes a generated report file.
+type storedReport struct {
+	// ID is the file name of the report.
+	ID string `json:"id"`
+	// ReportID is the id of the report config that generated this report.
+	ReportID    string    `json:"reportId"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreateTime  time.Time `json:"createTime"`
+	MediaType   string    `json:"mediaType"`
+}
+
+// store keeps generated reports as files in a directory.
+// Each report is stored alongside a json file describing it.
+type store struct {
+	dir string
+	mu  sync.Mutex // guards writes and removals
+}
+
+func newStore(dir string) (*store, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	return &store{dir: dir}, nil
+}
+
+// save writes a report and its description to the store.
+func (s *store) save(report storedReport, data []byte) error {
+	if !validID(report.ID) {
+		return fmt.Errorf("invalid report id %q", report.ID)
+	}
+	meta, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// write the data first, reports only appear in lists once their meta file exists
+	if err := writeFileAtomic(s.path(report.ID), data); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path(report.ID)+metaExt, meta)
+}
+
+// get returns the description of the report with the given id.
+func (s *store) get(id string) (storedReport, error) {
+	if !validID(id) {
+		return storedReport{}, errReportNotFound
+	}
+	report, err := readMeta(s.path(id) + metaExt)
+	if errors.Is(err, fs.ErrNotExist) {
+		return storedReport{}, errReportNotFound
+	}
+	return report, err
+}
+
+// list returns all stored reports, newest first.
+func (s *store) list() ([]storedReport, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var reports []storedReport
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), metaExt) {
+			continue
+		}
+		report, err := readMeta(filepath.Join(s.dir, entry.Name()))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // removed since we read the dir
+		} else if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	slices.SortFunc(reports, func(a, b storedReport) int {
+		return cmp.Or(b.CreateTime.Compare(a.CreateTime), strings.Compare(a.ID, b.ID))
+	})
+	return reports, nil
+}
+
+// trim removes reports generated by reportID with the given media type that are older than maxAge,
+// or beyond the newest maxCount. Zero values mean no limit.
+func (s *store) trim(reportID, mediaType string, maxAge time.Duration, maxCount int, now time.Time) error {
+	reports, err := s.list()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var kept int
+	var errs []error
+	for _, report := range reports {
+		if report.ReportID != reportID || report.MediaType != mediaType {
+			continue
+		}
+		expired := maxAge > 0 && now.Sub(report.CreateTime) > maxAge
+		if !expired && (maxCount <= 0 || kept < maxCount) {
+			kept++
+			continue
+		}
+		// remove the meta file first so a partially removed report isn't listed
+		errs = append(errs, removeIfExists(s.path(report.ID)+metaExt), removeIfExists(s.path(report.ID)))
+	}
+	return errors.Join(errs...)
+}
+
+func (s *store) path(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+// validID returns whether id is safe to use as a file name within the store.
+func validID(id string) bool {
+	return id != "" && filepath.Base(id) == id && !strings.HasPrefix(id, ".") && !strings.HasSuffix(id, metaExt)
+}
+
+func readMeta(path string) (storedReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return storedReport{}, err
+	}
+	var report storedReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return storedReport{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return report, nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func removeIfExists(path string) error {
+	err := os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
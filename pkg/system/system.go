@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/smart-core-os/sc-bos/internal/account"
+	auditlog "github.com/smart-core-os/sc-bos/internal/audit"
 	"github.com/smart-core-os/sc-bos/internal/util/grpc/reflectionapi"
 	"github.com/smart-core-os/sc-bos/internal/util/pki"
 	"github.com/smart-core-os/sc-bos/pkg/app/stores"
@@ -23,12 +24,14 @@ type Services struct {
 	DataDir         string
 	Logger          *zap.Logger
 	GRPCEndpoint    string     // host:port of this controllers grpc api
+	HTTPEndpoint    string     // host:port of this controllers https api, empty if unknown
 	Node            *node.Node // for advertising devices
 	HealthChecks    HealthCheckCollection
 	CohortManager   node.Remote
 	Database        *bolthold.Store
 	Stores          *stores.Stores
 	Accounts        *account.Store
+	AuditLog        *auditlog.Log       // to record security relevant events, nil if the audit log is disabled
 	HTTPMux         *http.ServeMux      // to allow systems to serve http requests
 	TokenValidators *token.ValidatorSet // to allow systems to contribute towards client validation
 
@@ -0,0 +1,142 @@
+package utilisation
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread"
+	"github.com/smart-core-os/sc-bos/pkg/util/timeutil"
+)
+
+// analyse computes the business hours metrics of segments.
+// Both segments and business must be in order without overlaps.
+func analyse(segments []historyread.OccupancySegment, business []span) *gen.OccupancyUtilisation {
+	var (
+		total, occupied time.Duration
+		peopleTime      float64 // people count multiplied by seconds
+		peak            int32
+		peakTime        time.Time
+	)
+	for _, s := range business {
+		total += s.end.Sub(s.start)
+	}
+	i := 0
+	for _, b := range business {
+		// skip segments that end before this business span starts
+		for i < len(segments) && !segments[i].End.After(b.start) {
+			i++
+		}
+		for j := i; j < len(segments) && segments[j].Start.Before(b.end); j++ {
+			seg := segments[j]
+			from, to := timeutil.Latest(seg.Start, b.start), timeutil.Earliest(seg.End, b.end)
+			d := to.Sub(from)
+			if d <= 0 {
+				continue
+			}
+			if seg.State == traits.Occupancy_OCCUPIED {
+				occupied += d
+			}
+			peopleTime += float64(seg.PeopleCount) * d.Seconds()
+			if seg.PeopleCount > peak {
+				peak, peakTime = seg.PeopleCount, from
+			}
+		}
+	}
+
+	res := &gen.OccupancyUtilisation{
+		BusinessDuration:   durationpb.New(total),
+		OccupiedDuration:   durationpb.New(occupied),
+		UtilisationPercent: percent(occupied, total),
+		PeakPeopleCount:    peak,
+	}
+	if !peakTime.IsZero() {
+		res.PeakTime = timestamppb.New(peakTime)
+	}
+	if total > 0 {
+		res.AveragePeopleCount = float32(peopleTime / total.Seconds())
+	}
+	return res
+}
+
+// heatmap returns the occupancy of each hour of the week, Monday 00:00 first, in loc.
+func heatmap(segments []historyread.OccupancySegment, loc *time.Location) []*gen.OccupancyUtilisation_HourOfWeek {
+	type cell struct {
+		sample, occupied time.Duration
+		peopleTime       float64
+	}
+	var cells [7 * 24]cell
+	for _, seg := range segments {
+		for from := seg.Start.In(loc); from.Before(seg.End); {
+			// the start of the next wall clock hour, from may not be on the hour
+			to := timeutil.Earliest(time.Date(from.Year(), from.Month(), from.Day(), from.Hour()+1, 0, 0, 0, loc), seg.End)
+			c := &cells[timeutil.HourOfWeek(from)]
+			d := to.Sub(from)
+			c.sample += d
+			if seg.State == traits.Occupancy_OCCUPIED {
+				c.occupied += d
+			}
+			c.peopleTime += float64(seg.PeopleCount) * d.Seconds()
+			from = to.In(loc)
+		}
+	}
+
+	res := make([]*gen.OccupancyUtilisation_HourOfWeek, len(cells))
+	for i, c := range cells {
+		h := &gen.OccupancyUtilisation_HourOfWeek{
+			DayOfWeek:       int32(i/24 + 1),
+			Hour:            int32(i % 24),
+			SampleDuration:  durationpb.New(c.sample),
+			OccupiedPercent: percent(c.occupied, c.sample),
+		}
+		if c.sample > 0 {
+			h.AveragePeopleCount = float32(c.peopleTime / c.sample.Seconds())
+		}
+		res[i] = h
+	}
+	return res
+}
+
+// bookingMetrics counts the bookings that started between start and end and finished before now,
+// and how many of those nobody turned up to.
+func bookingMetrics(bookings []*traits.Booking, segments []historyread.OccupancySegment, start, end, now time.Time) *gen.OccupancyUtilisation_Bookings {
+	res := &gen.OccupancyUtilisation_Bookings{}
+	for _, b := range bookings {
+		if b.GetBooked().GetStartTime() == nil || b.GetBooked().GetEndTime() == nil {
+			continue
+		}
+		booked := span{start: b.Booked.StartTime.AsTime(), end: b.Booked.EndTime.AsTime()}
+		if booked.start.Before(start) || !booked.start.Before(end) || booked.end.After(now) {
+			continue
+		}
+		res.BookingCount++
+		if b.GetCheckIn().GetStartTime() != nil || occupiedDuring(segments, booked) {
+			continue
+		}
+		res.NoShowCount++
+	}
+	if res.BookingCount > 0 {
+		res.NoShowPercent = 100 * float32(res.NoShowCount) / float32(res.BookingCount)
+	}
+	return res
+}
+
+// occupiedDuring returns whether any of segments that overlap s are occupied.
+func occupiedDuring(segments []historyread.OccupancySegment, s span) bool {
+	for _, seg := range segments {
+		if seg.State == traits.Occupancy_OCCUPIED && seg.Start.Before(s.end) && seg.End.After(s.start) {
+			return true
+		}
+	}
+	return false
+}
+
+func percent(part, total time.Duration) float32 {
+	if total <= 0 {
+		return 0
+	}
+	return float32(100 * float64(part) / float64(total))
+}
@@ -0,0 +1,134 @@
+package utilisation
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread"
+	"github.com/smart-core-os/sc-bos/pkg/system/utilisation/config"
+)
+
+func TestBusinessHours(t *testing.T) {
+	cal := config.Calendar{
+		Timezone: "UTC",
+		Hours: []config.Hours{
+			{Days: []string{"mon", "tue"}, Start: "09:00", End: "17:00"},
+			{Days: []string{"tue"}, Start: "16:00", End: "18:00"}, // overlaps
+			{Days: []string{"sun"}, Start: "22:00", End: "02:00"}, // overnight
+		},
+		Holidays: []string{"2024-01-09"}, // Tuesday the week after
+	}
+	// Sunday 7th to Wednesday 10th January 2024
+	start := time.Date(2024, 1, 7, 23, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	got := businessHours(cal, start, end)
+	want := []span{
+		{at(7, 23, 0), at(8, 2, 0)},
+		{at(8, 9, 0), at(8, 17, 0)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].start.Equal(want[i].start) || !got[i].end.Equal(want[i].end) {
+			t.Errorf("[%d] got %v-%v, want %v-%v", i, got[i].start, got[i].end, want[i].start, want[i].end)
+		}
+	}
+
+	// without the holiday Tuesday merges the overlapping hours
+	cal.Holidays = nil
+	got = businessHours(cal, start, end)
+	if len(got) != 3 || !got[2].start.Equal(at(9, 9, 0)) || !got[2].end.Equal(at(9, 18, 0)) {
+		t.Errorf("got %v, want Tuesday 09:00-18:00 last", got)
+	}
+}
+
+func TestAnalyse(t *testing.T) {
+	segments := []historyread.OccupancySegment{
+		{Start: at(8, 0, 0), End: at(8, 9, 0), State: traits.Occupancy_OCCUPIED, PeopleCount: 10}, // before business hours
+		{Start: at(8, 9, 0), End: at(8, 10, 0), State: traits.Occupancy_UNOCCUPIED, PeopleCount: 0},
+		{Start: at(8, 10, 0), End: at(8, 12, 0), State: traits.Occupancy_OCCUPIED, PeopleCount: 4},
+		{Start: at(8, 12, 0), End: at(8, 13, 0), State: traits.Occupancy_OCCUPIED, PeopleCount: 6},
+		{Start: at(8, 13, 0), End: at(8, 24, 0), State: traits.Occupancy_UNOCCUPIED, PeopleCount: 0},
+	}
+	business := []span{{at(8, 9, 0), at(8, 17, 0)}}
+	got := analyse(segments, business)
+
+	if d := got.BusinessDuration.AsDuration(); d != 8*time.Hour {
+		t.Errorf("business got %v, want 8h", d)
+	}
+	if d := got.OccupiedDuration.AsDuration(); d != 3*time.Hour {
+		t.Errorf("occupied got %v, want 3h", d)
+	}
+	if got.UtilisationPercent != 37.5 {
+		t.Errorf("utilisation got %v, want 37.5", got.UtilisationPercent)
+	}
+	if got.PeakPeopleCount != 6 || !got.PeakTime.AsTime().Equal(at(8, 12, 0)) {
+		t.Errorf("peak got %d at %v, want 6 at 12:00", got.PeakPeopleCount, got.PeakTime.AsTime())
+	}
+	// (4*2 + 6*1) / 8
+	if got.AveragePeopleCount != 1.75 {
+		t.Errorf("average got %v, want 1.75", got.AveragePeopleCount)
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	// Monday 8th 09:30 to 10:30 occupied, then the same time the next Monday unoccupied
+	segments := []historyread.OccupancySegment{
+		{Start: at(8, 9, 30), End: at(8, 10, 30), State: traits.Occupancy_OCCUPIED, PeopleCount: 2},
+		{Start: at(15, 9, 30), End: at(15, 10, 30), State: traits.Occupancy_UNOCCUPIED, PeopleCount: 0},
+	}
+	got := heatmap(segments, time.UTC)
+	if len(got) != 7*24 {
+		t.Fatalf("got %d cells, want %d", len(got), 7*24)
+	}
+	for _, hour := range []int{9, 10} {
+		c := got[hour]
+		if c.DayOfWeek != 1 || c.Hour != int32(hour) {
+			t.Fatalf("cell %d is day %d hour %d", hour, c.DayOfWeek, c.Hour)
+		}
+		if c.SampleDuration.AsDuration() != time.Hour || c.OccupiedPercent != 50 || c.AveragePeopleCount != 1 {
+			t.Errorf("hour %d got %v %v%% %v people, want 1h 50%% 1 people", hour, c.SampleDuration.AsDuration(), c.OccupiedPercent, c.AveragePeopleCount)
+		}
+	}
+	if c := got[11]; c.SampleDuration.AsDuration() != 0 {
+		t.Errorf("hour 11 got sample %v, want 0", c.SampleDuration.AsDuration())
+	}
+}
+
+func TestBookingMetrics(t *testing.T) {
+	segments := []historyread.OccupancySegment{
+		{Start: at(8, 0, 0), End: at(8, 10, 0), State: traits.Occupancy_UNOCCUPIED, PeopleCount: 0},
+		{Start: at(8, 10, 0), End: at(8, 11, 0), State: traits.Occupancy_OCCUPIED, PeopleCount: 1},
+		{Start: at(8, 11, 0), End: at(9, 0, 0), State: traits.Occupancy_UNOCCUPIED, PeopleCount: 0},
+	}
+	booking := func(startH, endH int, checkedIn bool) *traits.Booking {
+		b := &traits.Booking{Booked: &timepb.Period{
+			StartTime: timestamppb.New(at(8, startH, 0)),
+			EndTime:   timestamppb.New(at(8, endH, 0)),
+		}}
+		if checkedIn {
+			b.CheckIn = &timepb.Period{StartTime: b.Booked.StartTime}
+		}
+		return b
+	}
+	bookings := []*traits.Booking{
+		booking(10, 12, false), // used
+		booking(13, 14, false), // no-show
+		booking(14, 15, true),  // checked in, sensor missed it
+		booking(20, 21, false), // not finished
+	}
+	got := bookingMetrics(bookings, segments, at(8, 0, 0), at(9, 0, 0), at(8, 16, 0))
+	if got.BookingCount != 3 || got.NoShowCount != 1 {
+		t.Errorf("got %d bookings %d no-shows, want 3 and 1", got.BookingCount, got.NoShowCount)
+	}
+}
+
+// at returns the time on the given day of January 2024 in UTC.
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+}
@@ -0,0 +1,73 @@
+package utilisation
+
+import (
+	"slices"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/system/utilisation/config"
+	"github.com/smart-core-os/sc-bos/pkg/util/timeutil"
+)
+
+// span is a period of time, start inclusive and end exclusive.
+type span struct {
+	start, end time.Time
+}
+
+// businessHours returns the business hours of cal between start and end, in order and without overlaps.
+// The calendar must be valid.
+func businessHours(cal config.Calendar, start, end time.Time) []span {
+	loc := cal.Location()
+	hours := cal.Hours
+	if len(hours) == 0 {
+		hours = []config.Hours{{}}
+	}
+	holidays := make(map[string]bool, len(cal.Holidays))
+	for _, h := range cal.Holidays {
+		holidays[h] = true
+	}
+
+	var spans []span
+	// start a day early to include hours that run past midnight into the period
+	y, m, d := start.In(loc).AddDate(0, 0, -1).Date()
+	for day := time.Date(y, m, d, 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if holidays[day.Format(time.DateOnly)] {
+			continue
+		}
+		for _, h := range hours {
+			if len(h.Days) > 0 && !slices.ContainsFunc(h.Days, func(d string) bool { return config.Weekdays[d] == day.Weekday() }) {
+				continue
+			}
+			from, _ := config.ParseTimeOfDay(h.Start)
+			to, _ := config.ParseTimeOfDay(h.End)
+			if h.End == "" || to <= from {
+				to += 24 * time.Hour
+			}
+			s := span{start: atTimeOfDay(day, from), end: atTimeOfDay(day, to)}
+			s.start = timeutil.Latest(s.start, start)
+			s.end = timeutil.Earliest(s.end, end)
+			if s.start.Before(s.end) {
+				spans = append(spans, s)
+			}
+		}
+	}
+	return mergeSpans(spans)
+}
+
+// atTimeOfDay returns the wall clock time d after midnight on day, d may be more than 24 hours.
+func atTimeOfDay(day time.Time, d time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, int(d/time.Minute), 0, 0, day.Location())
+}
+
+// mergeSpans sorts spans and merges any that overlap or touch.
+func mergeSpans(spans []span) []span {
+	slices.SortFunc(spans, func(a, b span) int { return a.start.Compare(b.start) })
+	var merged []span
+	for _, s := range spans {
+		if n := len(merged); n > 0 && !s.start.After(merged[n-1].end) {
+			merged[n-1].end = timeutil.Latest(merged[n-1].end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
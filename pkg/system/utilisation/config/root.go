@@ -0,0 +1,131 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+
+	"github.com/smart-core-os/sc-bos/pkg/system"
+)
+
+// DefaultCalendarName is the name of the calendar used when a request doesn't name one.
+const DefaultCalendarName = "default"
+
+// DefaultCalendar is used as the "default" calendar if none is configured: 08:00 to 18:00, Monday to Friday.
+var DefaultCalendar = Calendar{
+	Hours: []Hours{{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "08:00", End: "18:00"}},
+}
+
+type Root struct {
+	system.Config
+	// DefaultCalendar is the name of the calendar used when a request doesn't name one, defaults to "default".
+	DefaultCalendar string `json:"defaultCalendar,omitempty"`
+	// Calendars define business hours by name.
+	// If no calendar is named "default" then DefaultCalendar is used.
+	Calendars map[string]Calendar `json:"calendars,omitempty"`
+}
+
+// Calendar defines business hours.
+type Calendar struct {
+	// Timezone is the IANA time zone the calendar is in, like "Europe/London". Defaults to the controller time zone.
+	Timezone string `json:"timezone,omitempty"`
+	// Hours are the business hours of each week.
+	// A calendar without hours includes all times.
+	Hours []Hours `json:"hours,omitempty"`
+	// Holidays are dates, in the form 2006-01-02, that have no business hours.
+	Holidays []string `json:"holidays,omitempty"`
+}
+
+type Hours struct {
+	// Days the hours apply, like "mon", "tue". Defaults to every day.
+	Days []string `json:"days,omitempty"`
+	// Start and End are local times of day in the form 15:04.
+	// If End is before Start the hours continue past midnight. Defaults to all day.
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// Calendar returns the named calendar, or the default calendar if name is empty.
+func (r Root) Calendar(name string) (string, Calendar, bool) {
+	name = cmp.Or(name, r.DefaultCalendar, DefaultCalendarName)
+	if c, ok := r.Calendars[name]; ok {
+		return name, c, true
+	}
+	if name == DefaultCalendarName {
+		return name, DefaultCalendar, true
+	}
+	return name, Calendar{}, false
+}
+
+// Location returns the time zone of the calendar.
+// The calendar must be valid.
+func (c Calendar) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.Local
+	}
+	loc, _ := time.LoadLocation(c.Timezone)
+	return loc
+}
+
+// Validate returns an error if the config isn't valid.
+func (r Root) Validate() error {
+	for name, c := range r.Calendars {
+		if err := c.validate(); err != nil {
+			return fmt.Errorf("calendars.%s: %w", name, err)
+		}
+	}
+	if _, _, ok := r.Calendar(""); !ok {
+		return fmt.Errorf("defaultCalendar %q is not defined", r.DefaultCalendar)
+	}
+	return nil
+}
+
+func (c Calendar) validate() error {
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+	}
+	for i, h := range c.Hours {
+		for _, d := range h.Days {
+			if _, ok := Weekdays[d]; !ok {
+				return fmt.Errorf("hours[%d].days %q is not a day, use mon, tue, etc", i, d)
+			}
+		}
+		if _, err := ParseTimeOfDay(h.Start); err != nil {
+			return fmt.Errorf("hours[%d].start: %w", i, err)
+		}
+		if _, err := ParseTimeOfDay(h.End); err != nil {
+			return fmt.Errorf("hours[%d].end: %w", i, err)
+		}
+	}
+	for i, d := range c.Holidays {
+		if _, err := time.Parse(time.DateOnly, d); err != nil {
+			return fmt.Errorf("holidays[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+var Weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseTimeOfDay parses s in the form 15:04 as a duration since midnight.
+// The empty string is midnight.
+func ParseTimeOfDay(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,73 @@
+// Package utilisation provides a system that computes occupancy utilisation metrics for spaces from their recorded
+// occupancy history. Metrics like utilisation, peak and average headcount, and booking no-shows are calculated for
+// business hours defined by configured calendars, and served via the OccupancyUtilisationApi.
+package utilisation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/node"
+	"github.com/smart-core-os/sc-bos/pkg/system"
+	"github.com/smart-core-os/sc-bos/pkg/system/utilisation/config"
+	"github.com/smart-core-os/sc-bos/pkg/task/service"
+)
+
+var Factory factory
+
+type factory struct{}
+
+func (_ factory) New(services system.Services) service.Lifecycle {
+	return NewSystem(services)
+}
+
+func NewSystem(services system.Services) *System {
+	logger := services.Logger.Named("utilisation")
+	s := &System{
+		node:   services.Node,
+		logger: logger,
+	}
+	s.Service = service.New(
+		service.MonoApply(s.applyConfig),
+		service.WithRetry[config.Root](service.RetryWithLogger(func(logContext service.RetryContext) {
+			logContext.LogTo("applyConfig", logger)
+		})),
+	)
+	return s
+}
+
+type System struct {
+	*service.Service[config.Root]
+	node   *node.Node
+	logger *zap.Logger
+}
+
+func (s *System) applyConfig(ctx context.Context, cfg config.Root) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	conn := s.node.ClientConn()
+	srv, err := node.RegistryService(gen.OccupancyUtilisationApi_ServiceDesc, &server{
+		cfg:      cfg,
+		history:  gen.NewOccupancySensorHistoryClient(conn),
+		bookings: traits.NewBookingApiClient(conn),
+		now:      time.Now,
+	})
+	if err != nil {
+		return fmt.Errorf("can't create OccupancyUtilisationApi service: %w", err)
+	}
+	undo, err := s.node.AnnounceService(srv)
+	if err != nil {
+		return fmt.Errorf("can't announce OccupancyUtilisationApi service: %w", err)
+	}
+	go func() {
+		<-ctx.Done()
+		undo()
+	}()
+	return nil
+}
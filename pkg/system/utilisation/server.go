@@ -0,0 +1,87 @@
+package utilisation
+
+import (
+	"cmp"
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/smart-core-os/sc-api/go/traits"
+	timepb "github.com/smart-core-os/sc-api/go/types/time"
+	"github.com/smart-core-os/sc-bos/pkg/gen"
+	"github.com/smart-core-os/sc-bos/pkg/history/historyread"
+	"github.com/smart-core-os/sc-bos/pkg/system/utilisation/config"
+)
+
+// maxPeriod is the longest period metrics can be computed for in one request.
+const maxPeriod = 366 * 24 * time.Hour
+
+// server implements gen.OccupancyUtilisationApiServer.
+type server struct {
+	gen.UnimplementedOccupancyUtilisationApiServer
+	cfg      config.Root
+	history  gen.OccupancySensorHistoryClient
+	bookings traits.BookingApiClient
+	now      func() time.Time
+}
+
+func (s *server) GetOccupancyUtilisation(ctx context.Context, request *gen.GetOccupancyUtilisationRequest) (*gen.OccupancyUtilisation, error) {
+	if request.DeviceName == "" {
+		return nil, status.Error(codes.InvalidArgument, "device_name is required")
+	}
+	if request.GetPeriod().GetStartTime() == nil {
+		return nil, status.Error(codes.InvalidArgument, "period.start_time is required")
+	}
+	now := s.now()
+	start := request.Period.StartTime.AsTime()
+	end := now
+	if request.Period.EndTime != nil {
+		end = request.Period.EndTime.AsTime()
+	}
+	if !end.After(start) {
+		return nil, status.Error(codes.InvalidArgument, "period.end_time must be after period.start_time")
+	}
+	if end.Sub(start) > maxPeriod {
+		return nil, status.Errorf(codes.InvalidArgument, "period must not be longer than %s", maxPeriod)
+	}
+	calName, cal, ok := s.cfg.Calendar(request.Calendar)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "calendar %q", calName)
+	}
+
+	segments, err := historyread.OccupancySegments(ctx, s.history, request.DeviceName, start, end)
+	if err != nil {
+		return nil, err
+	}
+	res := analyse(segments, businessHours(cal, start, end))
+	res.DeviceName = request.DeviceName
+	res.Period = &timepb.Period{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)}
+	res.Calendar = calName
+	if request.IncludeHeatmap {
+		res.Heatmap = heatmap(segments, cal.Location())
+	}
+
+	bookings, err := s.listBookings(ctx, cmp.Or(request.BookingName, request.DeviceName), start, end)
+	switch {
+	case err == nil:
+		res.Bookings = bookingMetrics(bookings, segments, start, end, now)
+	case request.BookingName != "":
+		return nil, err
+	}
+	// otherwise the device can't be booked, which is fine
+	return res, nil
+}
+
+func (s *server) listBookings(ctx context.Context, name string, start, end time.Time) ([]*traits.Booking, error) {
+	res, err := s.bookings.ListBookings(ctx, &traits.ListBookingsRequest{
+		Name:              name,
+		BookingIntersects: &timepb.Period{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Bookings, nil
+}
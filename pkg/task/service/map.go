@@ -32,6 +32,9 @@ type Map struct {
 	idFunc IdFunc
 	create CreateFunc
 
+	metricsGroup string
+	stopMetrics  map[string]func() // keyed by record id, guarded by mu
+
 	now func() time.Time
 }
 
@@ -45,13 +48,29 @@ type CreateFunc func(id, kind string) (Lifecycle, error)
 type IdFunc func(kind string, exists func(id string) bool) (string, error)
 
 // NewMap creates and returns a new empty Map using the given create funcs.
-func NewMap(createFunc CreateFunc, idFunc IdFunc) *Map {
-	return &Map{
-		known:  make(map[string]*Record),
-		bus:    &minibus.Bus[*Change]{},
-		idFunc: idFunc,
-		create: createFunc,
-		now:    time.Now,
+func NewMap(createFunc CreateFunc, idFunc IdFunc, opts ...MapOption) *Map {
+	m := &Map{
+		known:       make(map[string]*Record),
+		bus:         &minibus.Bus[*Change]{},
+		idFunc:      idFunc,
+		create:      createFunc,
+		stopMetrics: make(map[string]func()),
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// MapOption configures a Map created using NewMap.
+type MapOption func(m *Map)
+
+// WithMetricsGroup records the state of each service in the Map as Prometheus metrics.
+// Metrics are labelled with group, and the kind and id of the record.
+func WithMetricsGroup(group string) MapOption {
+	return func(m *Map) {
+		m.metricsGroup = group
 	}
 }
 
@@ -143,6 +162,7 @@ func (m *Map) Create(id, kind string, state State) (string, State, error) {
 			got, ok := m.known[r.Id]
 			if ok && got == r {
 				delete(m.known, r.Id)
+				m.stopMetricsLocked(r.Id)
 			}
 
 			// cleanup the service
@@ -388,6 +408,9 @@ func (m *Map) createRecord(id, kind string) (*Record, uint64, error) {
 		Service: s,
 	}
 	m.known[id] = r
+	if m.metricsGroup != "" {
+		m.stopMetrics[id] = recordMetrics(m.metricsGroup, r)
+	}
 	m.lastCID++
 	return r, m.lastCID, nil
 }
@@ -403,10 +426,20 @@ func (m *Map) deleteRecord(id string) (*Record, uint64, error) {
 		return nil, 0, ErrNotFound
 	}
 	delete(m.known, id)
+	m.stopMetricsLocked(id)
 	m.lastCID++
 	return r, m.lastCID, nil
 }
 
+// stopMetricsLocked stops recording metrics for the record with the given id.
+// m.mu lock must be held when calling this method
+func (m *Map) stopMetricsLocked(id string) {
+	if stop, ok := m.stopMetrics[id]; ok {
+		delete(m.stopMetrics, id)
+		stop()
+	}
+}
+
 // idExists returns whether the given id exists in m.known.
 // m.mu lock must be held when calling this method
 func (m *Map) idExists(id string) bool {
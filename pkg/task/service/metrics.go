@@ -0,0 +1,95 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	serviceState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "scbos",
+		Subsystem: "service",
+		Name:      "state",
+		Help:      "1 for the current state of each service, one of inactive, loading, active, or error.",
+	}, []string{"group", "kind", "id", "state"})
+	serviceStarts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "scbos",
+		Subsystem: "service",
+		Name:      "starts_total",
+		Help:      "Number of times each service has been started.",
+	}, []string{"group", "kind", "id"})
+	serviceLoads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "scbos",
+		Subsystem: "service",
+		Name:      "loads_total",
+		Help:      "Number of times each service has started applying config.",
+	}, []string{"group", "kind", "id"})
+	serviceLoadFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "scbos",
+		Subsystem: "service",
+		Name:      "load_failures_total",
+		Help:      "Number of attempts to apply config that failed, including attempts that will be retried.",
+	}, []string{"group", "kind", "id"})
+)
+
+var stateLabels = []string{"inactive", "loading", "active", "error"}
+
+func stateLabel(s State) string {
+	switch {
+	case !s.Active && s.Err != nil:
+		return "error"
+	case !s.Active:
+		return "inactive"
+	case s.Loading:
+		return "loading"
+	default:
+		return "active"
+	}
+}
+
+// stateObservable is implemented by Service, and types that embed it.
+type stateObservable interface {
+	observeState(fn func(old, new State)) func()
+}
+
+// recordMetrics records the state of the service in r as metrics labelled with group and r's kind and id.
+// The returned func stops recording and removes the metrics for r.
+// If the service in r does not support observing its state, recordMetrics does nothing.
+func recordMetrics(group string, r *Record) func() {
+	s, ok := r.Service.(stateObservable)
+	if !ok {
+		return func() {}
+	}
+
+	labels := prometheus.Labels{"group": group, "kind": r.Kind, "id": r.Id}
+	unobserve := s.observeState(func(old, new State) {
+		current := stateLabel(new)
+		for _, l := range stateLabels {
+			v := 0.0
+			if l == current {
+				v = 1
+			}
+			serviceState.WithLabelValues(group, r.Kind, r.Id, l).Set(v)
+		}
+		if !old.Active && new.Active {
+			serviceStarts.With(labels).Inc()
+		}
+		if !old.Loading && new.Loading {
+			serviceLoads.With(labels).Inc()
+		}
+		// failed attempts that will be retried set NextAttemptTime, those that won't set LastErrTime
+		if !new.NextAttemptTime.IsZero() && !new.NextAttemptTime.Equal(old.NextAttemptTime) ||
+			!new.LastErrTime.Equal(old.LastErrTime) {
+			serviceLoadFailures.With(labels).Inc()
+		}
+	})
+
+	return func() {
+		// observers are called with the service locked, so none are running once unobserve returns
+		unobserve()
+		serviceState.DeletePartialMatch(labels)
+		serviceStarts.Delete(labels)
+		serviceLoads.Delete(labels)
+		serviceLoadFailures.Delete(labels)
+	}
+}
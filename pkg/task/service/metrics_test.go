@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMap_WithMetricsGroup(t *testing.T) {
+	var fails int
+	m := NewMap(func(id, kind string) (Lifecycle, error) {
+		return New(func(ctx context.Context, config string) error {
+			if fails > 0 {
+				fails--
+				return errors.New("apply failed")
+			}
+			return nil
+		},
+			WithParser(func(data []byte) (string, error) { return string(data), nil }),
+			WithRetry[string](RetryWithMinDelay(time.Millisecond), RetryWithInitialDelay(time.Millisecond), RetryWithLogger(func(RetryContext) {})),
+		), nil
+	}, IdIsRequired, WithMetricsGroup("test"))
+
+	fails = 2
+	_, _, err := m.Create("svc1", "kind1", State{Active: true, Config: []byte("config")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type metrics struct {
+		State                      string
+		Starts, Loads, LoadFailure float64
+	}
+	read := func() metrics {
+		labels := map[string]string{"group": "test", "kind": "kind1", "id": "svc1"}
+		var got metrics
+		for _, s := range stateLabels {
+			if gatheredValue(t, "scbos_service_state", labels, "state", s) == 1 {
+				got.State = s
+			}
+		}
+		got.Starts = gatheredValue(t, "scbos_service_starts_total", labels, "", "")
+		got.Loads = gatheredValue(t, "scbos_service_loads_total", labels, "", "")
+		got.LoadFailure = gatheredValue(t, "scbos_service_load_failures_total", labels, "", "")
+		return got
+	}
+
+	want := metrics{State: "active", Starts: 1, Loads: 1, LoadFailure: 2}
+	deadline := time.Now().Add(time.Second)
+	for {
+		got := read()
+		diff := cmp.Diff(want, got)
+		if diff == "" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("metrics (-want,+got)\n%s", diff)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := m.Delete("svc1"); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(metrics{}, read()); diff != "" {
+		t.Fatalf("metrics after delete (-want,+got)\n%s", diff)
+	}
+}
+
+// gatheredValue returns the value of the gauge or counter named name from the default gatherer.
+// The metric must have all the given labels, and extraName=extraValue if extraName is not empty.
+// Returns 0 if there is no such metric.
+func gatheredValue(t *testing.T, name string, labels map[string]string, extraName, extraValue string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		want[k] = v
+	}
+	if extraName != "" {
+		want[extraName] = extraValue
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	nextMetric:
+		for _, metric := range family.GetMetric() {
+			got := make(map[string]string)
+			for _, l := range metric.GetLabel() {
+				got[l.GetName()] = l.GetValue()
+			}
+			for k, v := range want {
+				if got[k] != v {
+					continue nextMetric
+				}
+			}
+			if c := metric.GetCounter(); c != nil {
+				return c.GetValue()
+			}
+			return metric.GetGauge().GetValue()
+		}
+	}
+	return 0
+}
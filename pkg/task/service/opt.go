@@ -50,6 +50,14 @@ func WithOnStop[T any](onStop func()) Option[T] {
 	})
 }
 
+// WithOnStateChange adds a function to the Service that will be called each time the state of the service changes.
+// Like onStop, the func is called while the service is locked and should not invoke any lifecycle methods.
+func WithOnStateChange[T any](onStateChange func(old, new State)) Option[T] {
+	return OptionFunc[T](func(l *Service[T]) {
+		l.observers = append(l.observers, &stateObserver{fn: onStateChange})
+	})
+}
+
 // WithRetry configures a service to retry ApplyFunc when it returns an error.
 func WithRetry[T any](opts ...RetryOption) Option[T] {
 	return OptionFunc[T](func(l *Service[T]) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"math"
+	"slices"
 	"sync"
 	"time"
 
@@ -98,9 +99,10 @@ type Service[C any] struct {
 	stopCtx  context.Context
 	stopFunc context.CancelFunc
 
-	parse  ParseFunc[C]
-	apply  ApplyFunc[C]
-	onStop func()
+	parse     ParseFunc[C]
+	apply     ApplyFunc[C]
+	onStop    func()
+	observers []*stateObserver // guarded by mu
 
 	now func() time.Time
 
@@ -345,11 +347,37 @@ func (l *Service[C]) applyConfig(state State, config C) (State, error) {
 }
 
 func (l *Service[C]) saveLocked(state State) (State, error) {
+	old := l.state
 	l.state = state
+	for _, o := range l.observers {
+		o.fn(old, state)
+	}
 	go l.bus.Send(context.Background(), state)
 	return state, nil
 }
 
+// observeState adds fn to the funcs called each time the state of l changes, returning a func that removes it.
+// fn is called straight away with the current state as both old and new.
+// Like the func passed to WithOnStateChange, fn is called while l is locked.
+func (l *Service[C]) observeState(fn func(old, new State)) func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	o := &stateObserver{fn: fn}
+	l.observers = append(l.observers, o)
+	fn(l.state, l.state)
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.observers = slices.DeleteFunc(l.observers, func(other *stateObserver) bool {
+			return other == o
+		})
+	}
+}
+
+type stateObserver struct {
+	fn func(old, new State)
+}
+
 func (l *Service[C]) stopLocked() {
 	if stop := l.stopFunc; stop != nil {
 		// clear before calling stop to avoid races with go routines that are blocked on ctx.Done
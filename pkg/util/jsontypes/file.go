@@ -7,11 +7,30 @@ import (
 	"strings"
 )
 
-// String is either a literal string or a string loaded from a file.
+// String is either a literal string, a string loaded from a file, or a reference to a secret.
 // If the contents of String are an absolute path or start with a dot, the contents are loaded from a file.
+// In JSON a reference to a secret is an object like {"$secret": "name"}, see Secret.
 // Otherwise, the contents are used as-is.
 type String string
 
+// SecretName returns the name of the secret s refers to, if s is a reference.
+func (s String) SecretName() (string, bool) {
+	return secretName(string(s))
+}
+
+func (s String) MarshalJSON() ([]byte, error) {
+	return marshalSecret(string(s))
+}
+
+func (s *String) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalSecret(data)
+	if err != nil {
+		return err
+	}
+	*s = String(v)
+	return nil
+}
+
 // IsPath returns whether s looks like a filesystem path or not.
 // Filesystem paths are either absolute paths or paths starting with a dot.
 // Absolute paths are defined by [filepath.IsAbs].
@@ -19,15 +38,15 @@ func (s String) IsPath() bool {
 	return strings.HasPrefix(string(s), ".") || filepath.IsAbs(string(s))
 }
 
-// Open returns a reader for the contents of s, the file or string.
+// Open returns a reader for the contents of s, the file, secret, or string.
 func (s String) Open() (io.ReadCloser, error) {
 	if s.IsPath() {
 		return os.Open(string(s))
 	}
-	return io.NopCloser(strings.NewReader(string(s))), nil
+	return s.openString()
 }
 
-// OpenBase returns a reader for the contents of s, the file or string.
+// OpenBase returns a reader for the contents of s, the file, secret, or string.
 // Paths are resolved relative to base.
 func (s String) OpenBase(base string) (io.ReadCloser, error) {
 	if s.IsPath() {
@@ -37,11 +56,22 @@ func (s String) OpenBase(base string) (io.ReadCloser, error) {
 		}
 		return os.Open(p)
 	}
-	return io.NopCloser(strings.NewReader(string(s))), nil
+	return s.openString()
 }
 
-// Read reads the contents of s, the file or string.
+func (s String) openString() (io.ReadCloser, error) {
+	v, err := s.Read()
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(v)), nil
+}
+
+// Read reads the contents of s, the file, secret, or string.
 func (s String) Read() (string, error) {
+	if name, ok := s.SecretName(); ok {
+		return resolveSecret(name)
+	}
 	if !s.IsPath() {
 		return string(s), nil
 	}
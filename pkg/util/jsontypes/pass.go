@@ -6,16 +6,17 @@ import (
 	"strings"
 )
 
-// Password allows specifying a password either directly or via a password file.
+// Password allows specifying a password either directly, via a password file, or as a reference to a secret.
+// For example {"password": {"$secret": "mqtt/broker"}}.
 type Password struct {
-	Password     string `json:"password,omitempty"`
+	Password     Secret `json:"password,omitempty"`
 	PasswordFile string `json:"passwordFile,omitempty"`
 }
 
 // Read returns the password, either from Password or PasswordFile.
 func (c Password) Read() (string, error) {
 	if c.Password != "" {
-		return c.Password, nil
+		return c.Password.Read()
 	}
 	bs, err := os.ReadFile(c.PasswordFile)
 	if err != nil {
@@ -0,0 +1,108 @@
+package jsontypes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// SecretResolver returns the value of the named secret.
+type SecretResolver func(name string) (string, error)
+
+// ErrNoSecretResolver is returned when reading a secret reference before SetSecretResolver has been called.
+var ErrNoSecretResolver = errors.New("secret store not configured")
+
+var secretResolver atomic.Pointer[SecretResolver]
+
+// SetSecretResolver sets how secret references, like {"$secret": "mqtt/broker"}, are read.
+// Typically called once by the controller on startup, before any drivers or automations read their config.
+func SetSecretResolver(r SecretResolver) {
+	if r == nil {
+		secretResolver.Store(nil)
+		return
+	}
+	secretResolver.Store(&r)
+}
+
+func resolveSecret(name string) (string, error) {
+	r := secretResolver.Load()
+	if r == nil {
+		return "", fmt.Errorf("%w: secret %q", ErrNoSecretResolver, name)
+	}
+	return (*r)(name)
+}
+
+// secretRefPrefix marks a Secret or String as a reference to a named secret.
+const secretRefPrefix = "$secret:"
+
+// Secret is a string that is either given literally, or as a reference to a named secret.
+// In JSON a reference is an object like {"$secret": "mqtt/broker"},
+// the string "$secret:mqtt/broker" is also accepted.
+type Secret string
+
+// SecretRef returns a Secret that refers to the named secret.
+func SecretRef(name string) Secret {
+	return Secret(secretRefPrefix + name)
+}
+
+// SecretName returns the name of the secret s refers to, if s is a reference.
+func (s Secret) SecretName() (string, bool) {
+	return secretName(string(s))
+}
+
+// Read returns the value of s, reading the secret if s is a reference.
+func (s Secret) Read() (string, error) {
+	if name, ok := s.SecretName(); ok {
+		return resolveSecret(name)
+	}
+	return string(s), nil
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return marshalSecret(string(s))
+}
+
+func (s *Secret) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalSecret(data)
+	if err != nil {
+		return err
+	}
+	*s = Secret(v)
+	return nil
+}
+
+func secretName(s string) (string, bool) {
+	return strings.CutPrefix(s, secretRefPrefix)
+}
+
+// secretRefJSON is the JSON form of a reference to a secret.
+type secretRefJSON struct {
+	Name string `json:"$secret"`
+}
+
+func marshalSecret(s string) ([]byte, error) {
+	if name, ok := secretName(s); ok {
+		return json.Marshal(secretRefJSON{Name: name})
+	}
+	return json.Marshal(s)
+}
+
+// unmarshalSecret decodes either a JSON string, or a secret reference object into its string form.
+func unmarshalSecret(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var s string
+		err := json.Unmarshal(data, &s)
+		return s, err
+	}
+	var ref secretRefJSON
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return "", err
+	}
+	if ref.Name == "" {
+		return "", errors.New(`secret reference must have a "$secret" name`)
+	}
+	return secretRefPrefix + ref.Name, nil
+}
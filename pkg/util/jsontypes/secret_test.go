@@ -0,0 +1,56 @@
+package jsontypes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	SetSecretResolver(func(name string) (string, error) {
+		if name == "mqtt/broker" {
+			return "s3cret", nil
+		}
+		return "", fmt.Errorf("unknown secret %q", name)
+	})
+	t.Cleanup(func() { SetSecretResolver(nil) })
+
+	var c struct {
+		Password
+		Template String `json:"template"`
+	}
+	err := json.Unmarshal([]byte(`{"password": {"$secret": "mqtt/broker"}, "template": {"$secret": "mqtt/broker"}}`), &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.Password.Read(); err != nil || got != "s3cret" {
+		t.Fatalf("Password.Read = %q, %v; want %q", got, err, "s3cret")
+	}
+	if got, err := c.Template.Read(); err != nil || got != "s3cret" {
+		t.Fatalf("String.Read = %q, %v; want %q", got, err, "s3cret")
+	}
+
+	// references are written back as they were read, never as their value
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"password":{"$secret":"mqtt/broker"},"template":{"$secret":"mqtt/broker"}}`
+	if string(data) != want {
+		t.Fatalf("Marshal = %s, want %s", data, want)
+	}
+
+	// plain values are unchanged
+	if err := json.Unmarshal([]byte(`{"password": "plain"}`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.Password.Read(); err != nil || got != "plain" {
+		t.Fatalf("Password.Read = %q, %v; want %q", got, err, "plain")
+	}
+
+	SetSecretResolver(nil)
+	if _, err := SecretRef("mqtt/broker").Read(); !errors.Is(err, ErrNoSecretResolver) {
+		t.Fatalf("Read without resolver error = %v, want %v", err, ErrNoSecretResolver)
+	}
+}
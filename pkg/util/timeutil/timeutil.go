@@ -0,0 +1,26 @@
+// Package timeutil provides helpers for working with time.Time.
+package timeutil
+
+import "time"
+
+// Earliest returns whichever of a and b is earlier.
+func Earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// Latest returns whichever of a and b is later.
+func Latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// HourOfWeek returns the index of the hour of the week t is in, Monday 00:00 is 0.
+func HourOfWeek(t time.Time) int {
+	day := (int(t.Weekday()) + 6) % 7 // Monday is 0
+	return day*24 + t.Hour()
+}
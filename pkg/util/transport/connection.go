@@ -18,6 +18,7 @@ type Connection struct {
 	conf  ConnectionConfig
 	bo    backoff.BackOff
 	state *ConnectionState
+	kind  string // the type of transport, like tcp, used to label metrics
 
 	dial func() error
 	rwc  io.ReadWriteCloser
@@ -29,6 +30,7 @@ func NewConnection(conf ConnectionConfig, bo backoff.BackOff, dial func() error,
 		bo:    bo,
 		conf:  conf,
 		state: NewConnectionState(),
+		kind:  "other",
 		dial:  dial,
 		rwc:   rwc,
 	}
@@ -92,8 +94,14 @@ func (c *Connection) WaitForStateChange(ctx context.Context, sourceState State)
 	return c.state.WaitForStateChange(ctx, sourceState)
 }
 
+// Reconnect drops the current connection, Connect dials a new one.
+// Use this when the connection is in an unknown state, for example after receiving a malformed message.
+func (c *Connection) Reconnect() {
+	c.state.update(Disconnected)
+}
+
 func (c *Connection) watchConnection(ctx context.Context) error {
-	reconnect := func() {
+	connect := func() error {
 		c.state.update(Disconnected)
 
 		err := c.dial()
@@ -103,21 +111,35 @@ func (c *Connection) watchConnection(ctx context.Context) error {
 			c.state.update(Connected)
 			c.bo.Reset() // we connected, so reset backoff
 		}
+		return err
+	}
+	reconnect := func() {
+		result := "ok"
+		if err := connect(); err != nil {
+			result = "error"
+		}
+		reconnects.WithLabelValues(c.kind, result).Inc()
 	}
-	reconnect()
+	_ = connect()
 	for {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
 		// wait for backoff
-		b := c.bo.NextBackOff()
-		time.Sleep(b)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.bo.NextBackOff()):
+		}
 
 		ctx, cancelOnStateChange := context.WithCancel(ctx)
 		currentState, onStateChange := c.state.stateChanges(ctx)
 		if currentState == Connected {
 			// wait for an error, then re-dial
 			select {
+			case <-ctx.Done():
+				cancelOnStateChange()
+				continue
 			case <-onStateChange:
 				cancelOnStateChange()
 				reconnect()
@@ -3,6 +3,8 @@ package transport
 import (
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
+
 	"github.com/smart-core-os/sc-bos/pkg/util/jsontypes"
 )
 
@@ -13,6 +15,11 @@ type ConnectionConfig struct {
 	ReadTimeout jsontypes.Duration
 	// WriteTimeout is the timeout used when attempting to write, defaults to 15s
 	WriteTimeout jsontypes.Duration
+	// MinBackoff is the delay before reconnecting after failing to connect, doubling for each failure up to MaxBackoff.
+	// Defaults to 500ms
+	MinBackoff jsontypes.Duration
+	// MaxBackoff is the longest delay between attempts to connect, defaults to 1m
+	MaxBackoff jsontypes.Duration
 }
 
 func (c *ConnectionConfig) defaults() {
@@ -22,4 +29,20 @@ func (c *ConnectionConfig) defaults() {
 	if c.WriteTimeout.Duration == 0 {
 		c.WriteTimeout.Duration = time.Second * 15
 	}
+	if c.MinBackoff.Duration == 0 {
+		c.MinBackoff.Duration = backoff.DefaultInitialInterval
+	}
+	if c.MaxBackoff.Duration == 0 {
+		c.MaxBackoff.Duration = backoff.DefaultMaxInterval
+	}
+}
+
+// newBackoff returns the backoff between attempts to connect, it never gives up.
+func (c *ConnectionConfig) newBackoff() backoff.BackOff {
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = c.MinBackoff.Duration
+	bo.MaxInterval = max(c.MaxBackoff.Duration, c.MinBackoff.Duration)
+	bo.MaxElapsedTime = 0 // never give up
+	bo.Reset()            // apply InitialInterval
+	return bo
 }
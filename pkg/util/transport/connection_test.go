@@ -0,0 +1,60 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestConnection_reconnectMetric(t *testing.T) {
+	var dials int
+	dial := func() error {
+		dials++
+		if dials == 2 {
+			return errors.New("refused")
+		}
+		return nil
+	}
+	conn := NewConnection(ConnectionConfig{}, backoff.NewConstantBackOff(time.Millisecond), dial, nil)
+	conn.kind = "test"
+	ok := reconnects.WithLabelValues("test", "ok")
+	failed := reconnects.WithLabelValues("test", "error")
+	okBefore, failedBefore := counterValue(t, ok), counterValue(t, failed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = conn.Connect(ctx) }()
+	if !WaitConnected(ctx, conn) {
+		t.Fatal("not connected")
+	}
+	if n := counterValue(t, ok) - okBefore + counterValue(t, failed) - failedBefore; n != 0 {
+		t.Fatalf("got %v reconnects before the connection dropped, want 0", n)
+	}
+
+	// the first reconnect fails and the second succeeds
+	conn.Reconnect()
+	deadline := time.Now().Add(time.Second)
+	for counterValue(t, ok)-okBefore < 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %v successful reconnects, want 1", counterValue(t, ok)-okBefore)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := counterValue(t, failed) - failedBefore; n != 1 {
+		t.Fatalf("got %v failed reconnects, want 1", n)
+	}
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
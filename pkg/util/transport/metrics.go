@@ -0,0 +1,13 @@
+package transport
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var reconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "scbos",
+	Subsystem: "transport",
+	Name:      "reconnects_total",
+	Help:      "Number of attempts to connect again after the first, by transport type and result.",
+}, []string{"transport", "result"})
@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/goburrow/serial"
+	"go.uber.org/zap"
+)
+
+// serialPollInterval is how long a read of the port waits for data before checking whether the port has been closed.
+const serialPollInterval = 100 * time.Millisecond
+
+// Serial implements Transport, backed by a serial port. The port is opened again after any error, with an exponential
+// backoff for retries.
+type Serial struct {
+	*Connection
+	conf   SerialConfig
+	logger *zap.Logger
+
+	// control access to port
+	lock sync.RWMutex
+	port serial.Port
+}
+
+// NewSerial creates a new Serial transport with the given config
+func NewSerial(conf SerialConfig, logger *zap.Logger) *Serial {
+	conf.defaults()
+	s := &Serial{
+		conf:   conf,
+		logger: logger,
+	}
+	conn := NewConnection(conf.ConnectionConfig, conf.newBackoff(), s.dial, readWriteCloserFuncs(s.read, s.write, s.close))
+	conn.kind = "serial"
+	s.Connection = conn
+	return s
+}
+
+func (s *Serial) read(p []byte) (n int, err error) {
+	var deadline time.Time
+	if s.conf.ReadTimeout.Duration != 0 {
+		deadline = time.Now().Add(s.conf.ReadTimeout.Duration)
+	}
+	for {
+		// the port times out regularly so close doesn't wait for data that might never come
+		n, err = s.readOnce(p)
+		if !errors.Is(err, serial.ErrTimeout) {
+			return n, err
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			s.state.update(Disconnected)
+			// match the error returned by other transports when the read deadline passes
+			return 0, fmt.Errorf("%w: %w", os.ErrDeadlineExceeded, err)
+		}
+	}
+}
+
+func (s *Serial) readOnce(p []byte) (n int, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.port == nil {
+		return 0, net.ErrClosed
+	}
+	return s.port.Read(p)
+}
+
+func (s *Serial) write(p []byte) (n int, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.port == nil {
+		return 0, net.ErrClosed
+	}
+	return s.port.Write(p)
+}
+
+func (s *Serial) close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.port != nil {
+		err := s.port.Close()
+		s.port = nil
+		return err
+	}
+	return nil
+}
+
+func (s *Serial) dial() error {
+	s.logger.Debug("opening", zap.String("port", s.conf.Port))
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.port != nil {
+		_ = s.port.Close()
+		s.port = nil
+	}
+	port, err := serial.Open(&serial.Config{
+		Address:  s.conf.Port,
+		BaudRate: s.conf.BaudRate,
+		DataBits: s.conf.DataBits,
+		StopBits: s.conf.StopBits,
+		Parity:   s.conf.Parity,
+		Timeout:  serialPollInterval,
+	})
+	if err != nil {
+		return err
+	}
+	s.logger.Debug("opened")
+	s.port = port
+	return nil
+}
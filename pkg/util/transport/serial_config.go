@@ -0,0 +1,32 @@
+package transport
+
+// SerialConfig is used to configure a Serial transport
+type SerialConfig struct {
+	ConnectionConfig
+	// Port is the path of the serial port, like /dev/ttyUSB0
+	Port string
+	// BaudRate defaults to 9600
+	BaudRate int
+	// DataBits defaults to 8
+	DataBits int
+	// StopBits defaults to 1
+	StopBits int
+	// Parity is N, E, or O, defaults to E
+	Parity string
+}
+
+func (c *SerialConfig) defaults() {
+	if c.BaudRate == 0 {
+		c.BaudRate = 9600
+	}
+	if c.DataBits == 0 {
+		c.DataBits = 8
+	}
+	if c.StopBits == 0 {
+		c.StopBits = 1
+	}
+	if c.Parity == "" {
+		c.Parity = "E"
+	}
+	c.ConnectionConfig.defaults()
+}
@@ -6,7 +6,6 @@ import (
 	"net"
 	"sync"
 
-	"github.com/cenkalti/backoff/v4"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -25,12 +24,11 @@ type Ssh struct {
 // NewSsh creates a new Ssh transport with the given config
 func NewSsh(conf SshConfig) *Ssh {
 	conf.defaults()
-	bo := backoff.NewExponentialBackOff()
-	bo.MaxElapsedTime = 0 // never give up
 	s := &Ssh{
 		conf: conf,
 	}
-	conn := NewConnection(conf.ConnectionConfig, bo, s.dial, readWriteCloserFuncs(s.read, s.write, s.close))
+	conn := NewConnection(conf.ConnectionConfig, conf.newBackoff(), s.dial, readWriteCloserFuncs(s.read, s.write, s.close))
+	conn.kind = "ssh"
 	s.Connection = conn
 	return s
 }
@@ -1,12 +1,11 @@
 package transport
 
 import (
-	"fmt"
 	"net"
+	"strconv"
 	"sync"
 	"time"
 
-	"github.com/cenkalti/backoff/v4"
 	"go.uber.org/zap"
 )
 
@@ -25,19 +24,22 @@ type Tcp struct {
 // NewTcp creates a new Tcp transport with the given config
 func NewTcp(conf TcpConfig, logger *zap.Logger) *Tcp {
 	conf.defaults()
-	bo := backoff.NewExponentialBackOff()
-	bo.MaxElapsedTime = 0 // never give up
 	tcp := &Tcp{
 		conf:   conf,
 		logger: logger,
 	}
-	conn := NewConnection(conf.ConnectionConfig, bo, tcp.dial, readWriteCloserFuncs(tcp.read, tcp.write, tcp.close))
+	conn := NewConnection(conf.ConnectionConfig, conf.newBackoff(), tcp.dial, readWriteCloserFuncs(tcp.read, tcp.write, tcp.close))
+	conn.kind = "tcp"
 	tcp.Connection = conn
 	return tcp
 }
 
 func (t *Tcp) read(p []byte) (n int, err error) {
 	t.lock.RLock()
+	if t.conn == nil {
+		t.lock.RUnlock()
+		return 0, net.ErrClosed
+	}
 	c := *t.conn
 	t.lock.RUnlock()
 
@@ -53,6 +55,10 @@ func (t *Tcp) read(p []byte) (n int, err error) {
 
 func (t *Tcp) write(p []byte) (n int, err error) {
 	t.lock.RLock()
+	if t.conn == nil {
+		t.lock.RUnlock()
+		return 0, net.ErrClosed
+	}
 	c := *t.conn
 	t.lock.RUnlock()
 
@@ -77,7 +83,7 @@ func (t *Tcp) close() error {
 }
 
 func (t *Tcp) dial() error {
-	address := fmt.Sprintf("%s:%d", t.conf.Ip, t.conf.Port)
+	address := net.JoinHostPort(t.conf.Ip, strconv.Itoa(t.conf.Port))
 	t.logger.Debug("dialling", zap.String("address", address))
 	t.lock.Lock()
 	defer t.lock.Unlock()
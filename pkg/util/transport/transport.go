@@ -25,6 +25,22 @@ type Transport interface {
 	WaitForStateChange(ctx context.Context, sourceState State) (state State, changed bool)
 }
 
+// WaitConnected blocks until t is Connected, returning false if ctx is done first.
+func WaitConnected(ctx context.Context, t Transport) bool {
+	// WaitForStateChange returns the current state straight away when it isn't the one given
+	state := State(-1)
+	for {
+		s, ok := t.WaitForStateChange(ctx, state)
+		if !ok {
+			return false
+		}
+		if s == Connected {
+			return true
+		}
+		state = s
+	}
+}
+
 // ConnectionState stores a State and provides methods for subscribing to changes to the state
 type ConnectionState struct {
 	// control access to state & bus